@@ -11,7 +11,7 @@ const (
   -instance string
     	Instance name to substitute into socket templates (env SPIRE_AGENT_PUBLIC_SOCKET_TEMPLATE).
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -socketPath string
     	Path to the SPIRE Agent API Unix domain socket (default "/tmp/spire-agent/public/api.sock")
   -spiffeID string
@@ -23,7 +23,7 @@ const (
   -instance string
     	Instance name to substitute into socket templates (env SPIRE_AGENT_PUBLIC_SOCKET_TEMPLATE).
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -silent
     	Suppress stdout
   -socketPath string
@@ -39,7 +39,7 @@ const (
   -instance string
     	Instance name to substitute into socket templates (env SPIRE_AGENT_PUBLIC_SOCKET_TEMPLATE).
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -socketPath string
     	Path to the SPIRE Agent API Unix domain socket (default "/tmp/spire-agent/public/api.sock")
   -svid string
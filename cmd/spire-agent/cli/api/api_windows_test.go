@@ -11,7 +11,7 @@ const (
   -namedPipeName string
     	Pipe name of the SPIRE Agent API named pipe (default "\\spire-agent\\public\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -spiffeID string
     	SPIFFE ID subject (optional)
   -timeout value
@@ -21,7 +21,7 @@ const (
   -namedPipeName string
     	Pipe name of the SPIRE Agent API named pipe (default "\\spire-agent\\public\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -silent
     	Suppress stdout
   -timeout value
@@ -35,7 +35,7 @@ const (
   -namedPipeName string
     	Pipe name of the SPIRE Agent API named pipe (default "\\spire-agent\\public\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -svid string
     	JWT SVID
   -timeout value
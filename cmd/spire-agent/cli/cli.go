@@ -27,6 +27,15 @@ func (cc *CLI) Run(ctx context.Context, args []string) int {
 		"debug getinfo": func() (cli.Command, error) {
 			return debug.NewGetInfoCommand(), nil
 		},
+		"debug cache": func() (cli.Command, error) {
+			return debug.NewCacheCommand(), nil
+		},
+		"debug entries": func() (cli.Command, error) {
+			return debug.NewEntriesCommand(), nil
+		},
+		"debug attest": func() (cli.Command, error) {
+			return debug.NewAttestCommand(), nil
+		},
 		"api fetch": func() (cli.Command, error) {
 			return api.NewFetchX509Command(), nil
 		},
@@ -0,0 +1,78 @@
+package debug
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	"github.com/mitchellh/cli"
+	"github.com/spiffe/spire/cmd/spire-agent/util"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/cliprinter"
+	agentdebug "github.com/spiffe/spire/proto/private/agent/debug"
+)
+
+func NewAttestCommand() cli.Command {
+	return NewAttestCommandWithEnv(commoncli.DefaultEnv)
+}
+
+func NewAttestCommandWithEnv(env *commoncli.Env) cli.Command {
+	return util.AdaptCommand(env, &attestCommand{env: env})
+}
+
+type attestCommand struct {
+	env     *commoncli.Env
+	printer cliprinter.Printer
+
+	pid int
+}
+
+func (*attestCommand) Name() string {
+	return "debug attest"
+}
+
+func (*attestCommand) Synopsis() string {
+	return "Attests a workload and prints its selectors and matching cached entries"
+}
+
+func (c *attestCommand) AppendFlags(fs *flag.FlagSet) {
+	fs.IntVar(&c.pid, "pid", 0, "PID of the workload to attest")
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, prettyPrintAttestWorkload)
+}
+
+func (c *attestCommand) Run(ctx context.Context, _ *commoncli.Env, client util.AgentClient) error {
+	if c.pid == 0 {
+		return errors.New("a pid is required")
+	}
+	pid, err := parsePID(c.pid)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.NewDebugCacheClient().AttestWorkload(ctx, &agentdebug.AttestWorkloadRequest{
+		Pid: pid,
+	})
+	if err != nil {
+		return err
+	}
+	return c.printer.PrintProto(resp)
+}
+
+func prettyPrintAttestWorkload(env *commoncli.Env, results ...any) error {
+	resp, ok := results[0].(*agentdebug.AttestWorkloadResponse)
+	if !ok {
+		return cliprinter.ErrInternalCustomPrettyFunc
+	}
+
+	env.Printf("Selectors:\n")
+	if len(resp.Selectors) == 0 {
+		env.Printf("  (none)\n")
+	}
+	for _, selector := range resp.Selectors {
+		env.Printf("  %s:%s\n", selector.Type, selector.Value)
+	}
+	env.Printf("\n")
+
+	printCachedEntries(env, resp.Entries)
+	return nil
+}
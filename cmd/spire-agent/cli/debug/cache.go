@@ -0,0 +1,148 @@
+package debug
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/spiffe/spire/cmd/spire-agent/util"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/cliprinter"
+	commonutil "github.com/spiffe/spire/pkg/common/util"
+	agentdebug "github.com/spiffe/spire/proto/private/agent/debug"
+	"github.com/spiffe/spire/proto/spire/common"
+)
+
+func NewCacheCommand() cli.Command {
+	return NewCacheCommandWithEnv(commoncli.DefaultEnv)
+}
+
+func NewCacheCommandWithEnv(env *commoncli.Env) cli.Command {
+	return util.AdaptCommand(env, &cacheCommand{env: env, name: "debug cache"})
+}
+
+// NewEntriesCommand returns the "debug entries" command, an alias of
+// "debug cache".
+func NewEntriesCommand() cli.Command {
+	return NewEntriesCommandWithEnv(commoncli.DefaultEnv)
+}
+
+func NewEntriesCommandWithEnv(env *commoncli.Env) cli.Command {
+	return util.AdaptCommand(env, &cacheCommand{env: env, name: "debug entries"})
+}
+
+type cacheCommand struct {
+	env     *commoncli.Env
+	name    string
+	printer cliprinter.Printer
+
+	selectors commoncli.StringsFlag
+	spiffeID  string
+	pid       int
+}
+
+func (c *cacheCommand) Name() string {
+	return c.name
+}
+
+func (*cacheCommand) Synopsis() string {
+	return "Lists the registration entries cached by the agent"
+}
+
+func (c *cacheCommand) AppendFlags(fs *flag.FlagSet) {
+	fs.Var(&c.selectors, "selector", "A colon-delimited type:value selector. Only entries matching a workload with the given selectors are listed. Can be used more than once")
+	fs.StringVar(&c.spiffeID, "spiffeID", "", "Only list the entries with this SPIFFE ID")
+	fs.IntVar(&c.pid, "pid", 0, "Only list the entries matching the workload with this PID. The workload is attested by the agent")
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, prettyPrintCachedEntries)
+}
+
+func (c *cacheCommand) Run(ctx context.Context, _ *commoncli.Env, client util.AgentClient) error {
+	filter := &agentdebug.ListCachedEntriesRequest_Filter{
+		BySpiffeId: c.spiffeID,
+	}
+	if c.pid != 0 {
+		pid, err := parsePID(c.pid)
+		if err != nil {
+			return err
+		}
+		filter.ByPid = pid
+	}
+	for _, s := range c.selectors {
+		selector, err := parseSelector(s)
+		if err != nil {
+			return fmt.Errorf("error parsing selectors: %w", err)
+		}
+		filter.BySelectors = append(filter.BySelectors, selector)
+	}
+
+	resp, err := client.NewDebugCacheClient().ListCachedEntries(ctx, &agentdebug.ListCachedEntriesRequest{
+		Filter: filter,
+	})
+	if err != nil {
+		return err
+	}
+	return c.printer.PrintProto(resp)
+}
+
+func prettyPrintCachedEntries(env *commoncli.Env, results ...any) error {
+	resp, ok := results[0].(*agentdebug.ListCachedEntriesResponse)
+	if !ok {
+		return cliprinter.ErrInternalCustomPrettyFunc
+	}
+
+	printCachedEntries(env, resp.Entries)
+	return nil
+}
+
+func printCachedEntries(env *commoncli.Env, entries []*agentdebug.CachedEntry) {
+	switch len(entries) {
+	case 0:
+		env.Printf("Found no cached entries\n")
+		return
+	case 1:
+		env.Printf("Found 1 cached entry\n")
+	default:
+		env.Printf("Found %d cached entries\n", len(entries))
+	}
+
+	for _, cachedEntry := range entries {
+		entry := cachedEntry.Entry
+		env.Printf("\n")
+		env.Printf("Entry ID         : %s\n", entry.EntryId)
+		env.Printf("SPIFFE ID        : %s\n", entry.SpiffeId)
+		env.Printf("Parent ID        : %s\n", entry.ParentId)
+		for _, selector := range entry.Selectors {
+			env.Printf("Selector         : %s:%s\n", selector.Type, selector.Value)
+		}
+		if entry.Hint != "" {
+			env.Printf("Hint             : %s\n", entry.Hint)
+		}
+		if svid := cachedEntry.X509Svid; svid != nil {
+			env.Printf("X509-SVID Serial : %s\n", svid.SerialNumber)
+			env.Printf("X509-SVID Expiry : %s\n", time.Unix(svid.ExpiresAt, 0).UTC().Format(time.RFC3339))
+		} else {
+			env.Printf("X509-SVID        : (not cached)\n")
+		}
+	}
+}
+
+func parsePID(pid int) (int32, error) {
+	value, err := commonutil.CheckedCast[int32](pid)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid value for pid: %d", pid)
+	}
+	return value, nil
+}
+
+// parseSelector parses a CLI string from type:value into a selector.
+// Everything to the right of the first ":" is considered the selector value.
+func parseSelector(str string) (*common.Selector, error) {
+	selectorType, selectorValue, ok := strings.Cut(str, ":")
+	if !ok || selectorType == "" || selectorValue == "" {
+		return nil, fmt.Errorf("selector %q must be formatted as type:value", str)
+	}
+	return &common.Selector{Type: selectorType, Value: selectorValue}, nil
+}
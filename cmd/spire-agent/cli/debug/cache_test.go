@@ -0,0 +1,237 @@
+package debug_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/spiffe/spire/cmd/spire-agent/cli/debug"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	agentdebug "github.com/spiffe/spire/proto/private/agent/debug"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/spiffe/spire/test/clitest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+var (
+	cachedEntries = []*agentdebug.CachedEntry{
+		{
+			Entry: &common.RegistrationEntry{
+				EntryId:   "ENTRY-1",
+				SpiffeId:  "spiffe://example.org/workload",
+				ParentId:  "spiffe://example.org/spire/agent/foo",
+				Selectors: []*common.Selector{{Type: "unix", Value: "uid:1000"}},
+				Hint:      "internal",
+			},
+			X509Svid: &agentdebug.CachedX509SVID{
+				SerialNumber: "12345",
+				ExpiresAt:    time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC).Unix(),
+			},
+		},
+		{
+			Entry: &common.RegistrationEntry{
+				EntryId:   "ENTRY-2",
+				SpiffeId:  "spiffe://example.org/other",
+				ParentId:  "spiffe://example.org/spire/agent/foo",
+				Selectors: []*common.Selector{{Type: "unix", Value: "uid:2000"}},
+			},
+		},
+	}
+
+	cachedEntriesPretty = `Found 2 cached entries
+
+Entry ID         : ENTRY-1
+SPIFFE ID        : spiffe://example.org/workload
+Parent ID        : spiffe://example.org/spire/agent/foo
+Selector         : unix:uid:1000
+Hint             : internal
+X509-SVID Serial : 12345
+X509-SVID Expiry : 2024-01-15T12:00:00Z
+
+Entry ID         : ENTRY-2
+SPIFFE ID        : spiffe://example.org/other
+Parent ID        : spiffe://example.org/spire/agent/foo
+Selector         : unix:uid:2000
+X509-SVID        : (not cached)
+`
+)
+
+type cacheTest struct {
+	stdout *bytes.Buffer
+	stderr *bytes.Buffer
+	args   []string
+	server *fakeCacheServer
+	env    *commoncli.Env
+}
+
+func setupCacheTest(t *testing.T) *cacheTest {
+	server := &fakeCacheServer{}
+
+	addr := spiretest.StartGRPCServer(t, func(s *grpc.Server) {
+		agentdebug.RegisterCacheServer(s, server)
+	})
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+
+	return &cacheTest{
+		stdout: stdout,
+		stderr: stderr,
+		args:   []string{addrArg, clitest.GetAddr(addr)},
+		server: server,
+		env: &commoncli.Env{
+			Stdin:  new(bytes.Buffer),
+			Stdout: stdout,
+			Stderr: stderr,
+		},
+	}
+}
+
+func TestCache(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		newCommand   func(*commoncli.Env) cli.Command
+		args         []string
+		expectFilter *agentdebug.ListCachedEntriesRequest_Filter
+		expectOut    string
+		expectErr    string
+	}{
+		{
+			name:         "debug cache",
+			newCommand:   debug.NewCacheCommandWithEnv,
+			expectFilter: &agentdebug.ListCachedEntriesRequest_Filter{},
+			expectOut:    cachedEntriesPretty,
+		},
+		{
+			name:         "debug entries",
+			newCommand:   debug.NewEntriesCommandWithEnv,
+			expectFilter: &agentdebug.ListCachedEntriesRequest_Filter{},
+			expectOut:    cachedEntriesPretty,
+		},
+		{
+			name:       "with filters",
+			newCommand: debug.NewCacheCommandWithEnv,
+			args: []string{
+				"-selector", "unix:uid:1000",
+				"-selector", "unix:gid:1000",
+				"-spiffeID", "spiffe://example.org/workload",
+				"-pid", "42",
+			},
+			expectFilter: &agentdebug.ListCachedEntriesRequest_Filter{
+				BySelectors: []*common.Selector{
+					{Type: "unix", Value: "uid:1000"},
+					{Type: "unix", Value: "gid:1000"},
+				},
+				BySpiffeId: "spiffe://example.org/workload",
+				ByPid:      42,
+			},
+			expectOut: cachedEntriesPretty,
+		},
+		{
+			name:       "invalid selector",
+			newCommand: debug.NewCacheCommandWithEnv,
+			args:       []string{"-selector", "unix"},
+			expectErr:  "Error: error parsing selectors: selector \"unix\" must be formatted as type:value\n",
+		},
+		{
+			name:       "invalid pid",
+			newCommand: debug.NewCacheCommandWithEnv,
+			args:       []string{"-pid", "-1"},
+			expectErr:  "Error: invalid value for pid: -1\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			test := setupCacheTest(t)
+			test.server.entries = cachedEntries
+
+			code := tt.newCommand(test.env).Run(append(test.args, tt.args...))
+			if tt.expectErr != "" {
+				require.Equal(t, 1, code)
+				require.Equal(t, tt.expectErr, test.stderr.String())
+				return
+			}
+			require.Equal(t, 0, code, "exit code; stderr: %s", test.stderr.String())
+			require.Empty(t, test.stderr.String(), "stderr")
+			require.Equal(t, tt.expectOut, test.stdout.String())
+			spiretest.RequireProtoEqual(t, tt.expectFilter, test.server.listReq.Filter)
+		})
+	}
+}
+
+func TestCacheNoEntries(t *testing.T) {
+	test := setupCacheTest(t)
+
+	code := debug.NewCacheCommandWithEnv(test.env).Run(test.args)
+	require.Equal(t, 0, code, "exit code; stderr: %s", test.stderr.String())
+	require.Equal(t, "Found no cached entries\n", test.stdout.String())
+}
+
+func TestCacheJSON(t *testing.T) {
+	test := setupCacheTest(t)
+	test.server.entries = cachedEntries
+
+	code := debug.NewCacheCommandWithEnv(test.env).Run(append(test.args, "-output", "json"))
+	require.Equal(t, 0, code, "exit code; stderr: %s", test.stderr.String())
+	require.Contains(t, test.stdout.String(), `"serial_number":"12345"`)
+	require.Contains(t, test.stdout.String(), `"hint":"internal"`)
+}
+
+func TestAttest(t *testing.T) {
+	test := setupCacheTest(t)
+	test.server.entries = cachedEntries[:1]
+	test.server.selectors = []*common.Selector{
+		{Type: "unix", Value: "uid:1000"},
+		{Type: "unix", Value: "gid:1000"},
+	}
+
+	code := debug.NewAttestCommandWithEnv(test.env).Run(append(test.args, "-pid", "42"))
+	require.Equal(t, 0, code, "exit code; stderr: %s", test.stderr.String())
+	require.Equal(t, int32(42), test.server.attestReq.Pid)
+	require.Equal(t, `Selectors:
+  unix:uid:1000
+  unix:gid:1000
+
+Found 1 cached entry
+
+Entry ID         : ENTRY-1
+SPIFFE ID        : spiffe://example.org/workload
+Parent ID        : spiffe://example.org/spire/agent/foo
+Selector         : unix:uid:1000
+Hint             : internal
+X509-SVID Serial : 12345
+X509-SVID Expiry : 2024-01-15T12:00:00Z
+`, test.stdout.String())
+}
+
+func TestAttestRequiresPID(t *testing.T) {
+	test := setupCacheTest(t)
+
+	code := debug.NewAttestCommandWithEnv(test.env).Run(test.args)
+	require.Equal(t, 1, code)
+	require.Equal(t, "Error: a pid is required\n", test.stderr.String())
+	require.Nil(t, test.server.attestReq)
+}
+
+type fakeCacheServer struct {
+	agentdebug.UnimplementedCacheServer
+
+	entries   []*agentdebug.CachedEntry
+	selectors []*common.Selector
+
+	listReq   *agentdebug.ListCachedEntriesRequest
+	attestReq *agentdebug.AttestWorkloadRequest
+}
+
+func (s *fakeCacheServer) ListCachedEntries(_ context.Context, req *agentdebug.ListCachedEntriesRequest) (*agentdebug.ListCachedEntriesResponse, error) {
+	s.listReq = req
+	return &agentdebug.ListCachedEntriesResponse{Entries: s.entries}, nil
+}
+
+func (s *fakeCacheServer) AttestWorkload(_ context.Context, req *agentdebug.AttestWorkloadRequest) (*agentdebug.AttestWorkloadResponse, error) {
+	s.attestReq = req
+	return &agentdebug.AttestWorkloadResponse{Selectors: s.selectors, Entries: s.entries}, nil
+}
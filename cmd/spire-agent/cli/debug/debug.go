@@ -9,8 +9,11 @@ import (
 	"github.com/mitchellh/cli"
 	debugv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/agent/debug/v1"
 	"github.com/spiffe/spire/cmd/spire-agent/util"
+	debugapi "github.com/spiffe/spire/pkg/agent/api/debug/v1"
 	commoncli "github.com/spiffe/spire/pkg/common/cli"
 	"github.com/spiffe/spire/pkg/common/cliprinter"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 func NewGetInfoCommand() cli.Command {
@@ -24,6 +27,10 @@ func NewGetInfoCommandWithEnv(env *commoncli.Env) cli.Command {
 type getInfoCommand struct {
 	env     *commoncli.Env
 	printer cliprinter.Printer
+
+	// activeServer is the SPIRE Server the agent talks to, as reported in
+	// the GetInfo response header
+	activeServer string
 }
 
 func (*getInfoCommand) Name() string {
@@ -35,19 +42,23 @@ func (*getInfoCommand) Synopsis() string {
 }
 
 func (c *getInfoCommand) AppendFlags(fs *flag.FlagSet) {
-	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, prettyPrintGetInfo)
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, c.prettyPrintGetInfo)
 }
 
 func (c *getInfoCommand) Run(ctx context.Context, _ *commoncli.Env, client util.AgentClient) error {
 	debugClient := client.NewDebugClient()
-	resp, err := debugClient.GetInfo(ctx, &debugv1.GetInfoRequest{})
+	var header metadata.MD
+	resp, err := debugClient.GetInfo(ctx, &debugv1.GetInfoRequest{}, grpc.Header(&header))
 	if err != nil {
 		return err
 	}
+	if values := header.Get(debugapi.ActiveServerHeader); len(values) > 0 {
+		c.activeServer = values[0]
+	}
 	return c.printer.PrintProto(resp)
 }
 
-func prettyPrintGetInfo(env *commoncli.Env, results ...any) error {
+func (c *getInfoCommand) prettyPrintGetInfo(env *commoncli.Env, results ...any) error {
 	resp, ok := results[0].(*debugv1.GetInfoResponse)
 	if !ok {
 		return cliprinter.ErrInternalCustomPrettyFunc
@@ -56,6 +67,9 @@ func prettyPrintGetInfo(env *commoncli.Env, results ...any) error {
 	env.Printf("Agent Debug Info:\n")
 	env.Printf("  Uptime:                          %s\n", (time.Duration(resp.Uptime) * time.Second).String())
 	env.Printf("  Last Sync Success:               %s\n", formatLastSync(resp.LastSyncSuccess))
+	if c.activeServer != "" {
+		env.Printf("  Active Server:                   %s\n", c.activeServer)
+	}
 	env.Printf("  Cached X.509 SVIDs:              %d\n", resp.CachedX509SvidsCount)
 	env.Printf("  Cached JWT SVIDs:                %d\n", resp.CachedJwtSvidsCount)
 	env.Printf("  Cached SVID Store X.509 SVIDs:   %d\n", resp.CachedSvidstoreX509SvidsCount)
@@ -5,7 +5,7 @@ package debug_test
 var (
 	usage = `Usage of debug getinfo:
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -socketPath string
     	Path to the SPIRE Agent admin API socket (default "/tmp/spire-agent/private/admin.sock")
 `
@@ -10,11 +10,13 @@ import (
 	debugv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/agent/debug/v1"
 	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
 	"github.com/spiffe/spire/cmd/spire-agent/cli/debug"
+	debugapi "github.com/spiffe/spire/pkg/agent/api/debug/v1"
 	commoncli "github.com/spiffe/spire/pkg/common/cli"
 	"github.com/spiffe/spire/test/clitest"
 	"github.com/spiffe/spire/test/spiretest"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 type debugTest struct {
@@ -114,6 +116,16 @@ func TestGetInfo(t *testing.T) {
 	require.Contains(t, out, "spiffe://example.org/spire/agent/foo")
 }
 
+func TestGetInfoActiveServer(t *testing.T) {
+	test := setupTest(t)
+	test.server.resp = &debugv1.GetInfoResponse{Uptime: 42}
+	test.server.activeServer = "dns:///spire-server-2:8081"
+
+	code := test.client.Run(test.args)
+	require.Equal(t, 0, code, "exit code; stderr: %s", test.stderr.String())
+	require.Contains(t, test.stdout.String(), "Active Server:                   dns:///spire-server-2:8081")
+}
+
 func TestGetInfoNeverSynced(t *testing.T) {
 	test := setupTest(t)
 	test.server.resp = &debugv1.GetInfoResponse{
@@ -149,9 +161,15 @@ func TestGetInfoJSON(t *testing.T) {
 
 type fakeDebugServer struct {
 	debugv1.UnimplementedDebugServer
-	resp *debugv1.GetInfoResponse
+	resp         *debugv1.GetInfoResponse
+	activeServer string
 }
 
-func (s *fakeDebugServer) GetInfo(_ context.Context, _ *debugv1.GetInfoRequest) (*debugv1.GetInfoResponse, error) {
+func (s *fakeDebugServer) GetInfo(ctx context.Context, _ *debugv1.GetInfoRequest) (*debugv1.GetInfoResponse, error) {
+	if s.activeServer != "" {
+		if err := grpc.SetHeader(ctx, metadata.Pairs(debugapi.ActiveServerHeader, s.activeServer)); err != nil {
+			return nil, err
+		}
+	}
 	return s.resp, nil
 }
@@ -7,7 +7,7 @@ var (
   -namedPipeName string
     	Pipe name of the SPIRE Agent admin API named pipe (default "\\spire-agent\\private\\admin")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
 `
 	addrArg               = "-namedPipeName"
 	socketAddrUnavailable = "doesnotexist"
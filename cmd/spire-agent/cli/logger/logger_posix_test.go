@@ -10,7 +10,7 @@ const (
 var (
 	getUsage = `Usage of logger get:
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -socketPath string
     	Path to the SPIRE Agent admin API socket (default "/tmp/spire-agent/private/admin.sock")
 `
@@ -18,13 +18,13 @@ var (
   -level string
     	The new log level, one of (panic, fatal, error, warn, info, debug, trace)
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -socketPath string
     	Path to the SPIRE Agent admin API socket (default "/tmp/spire-agent/private/admin.sock")
 `
 	resetUsage = `Usage of logger reset:
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -socketPath string
     	Path to the SPIRE Agent admin API socket (default "/tmp/spire-agent/private/admin.sock")
 `
@@ -12,7 +12,7 @@ var (
   -namedPipeName string
     	Pipe name of the SPIRE Agent admin API named pipe (default "\\spire-agent\\private\\admin")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
 `
 	setUsage = `Usage of logger set:
   -level string
@@ -20,12 +20,12 @@ var (
   -namedPipeName string
     	Pipe name of the SPIRE Agent admin API named pipe (default "\\spire-agent\\private\\admin")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
 `
 	resetUsage = `Usage of logger reset:
   -namedPipeName string
     	Pipe name of the SPIRE Agent admin API named pipe (default "\\spire-agent\\private\\admin")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
 `
 )
@@ -80,9 +80,13 @@ type agentConfig struct {
 	LogLevel                      string    `hcl:"log_level"`
 	LogSelectors                  []string  `hcl:"log_selectors"`
 	LogSourceLocation             bool      `hcl:"log_source_location"`
+	PersistWorkloadSVIDs          bool      `hcl:"persist_workload_svids"`
 	SDS                           sdsConfig `hcl:"sds"`
 	ServerAddress                 string    `hcl:"server_address"`
+	ServerAddresses               []string  `hcl:"server_addresses"`
 	ServerPort                    int       `hcl:"server_port"`
+	ServerRebalanceInterval       string    `hcl:"server_rebalance_interval"`
+	ServerSRVName                 string    `hcl:"server_srv_name"`
 	SocketPath                    string    `hcl:"socket_path"`
 	DisableWorkloadAPI            bool      `hcl:"disable_workload_api"`
 	DisableSDSAPI                 bool      `hcl:"disable_sds_api"`
@@ -237,7 +241,10 @@ type workloadAPIRateLimitConfig struct {
 	FetchJWTSVID     *int `hcl:"fetch_jwt_svid"`
 	FetchX509Bundles *int `hcl:"fetch_x509_bundles"`
 	FetchJWTBundles  *int `hcl:"fetch_jwt_bundles"`
+	FetchWITSVID     *int `hcl:"fetch_wit_svid"`
+	FetchWITBundles  *int `hcl:"fetch_wit_bundles"`
 	StreamSecrets    *int `hcl:"stream_secrets"`
+	DeltaSecrets     *int `hcl:"delta_secrets"`
 	FetchSecrets     *int `hcl:"fetch_secrets"`
 
 	UnusedKeyPositions map[string][]token.Pos `hcl:",unusedKeyPositions"`
@@ -368,11 +375,20 @@ func (c *agentConfig) validate() error {
 		return errors.New("only one of join_token or join_token_file can be specified, not both")
 	}
 
-	if c.ServerAddress == "" {
+	serverSources := 0
+	for _, configured := range []bool{c.ServerAddress != "", len(c.ServerAddresses) > 0, c.ServerSRVName != ""} {
+		if configured {
+			serverSources++
+		}
+	}
+	switch {
+	case serverSources == 0:
 		return errors.New("server_address must be configured")
+	case serverSources > 1:
+		return errors.New("only one of server_address, server_addresses or server_srv_name can be configured")
 	}
 
-	if c.ServerPort == 0 {
+	if c.ServerAddress != "" && c.ServerPort == 0 {
 		return errors.New("server_port must be configured")
 	}
 
@@ -589,8 +605,25 @@ func NewAgentConfig(c *Config, logOptions []log.Option, allowUnknownConfig bool)
 		}
 	}
 
-	serverHostPort := net.JoinHostPort(c.Agent.ServerAddress, strconv.Itoa(c.Agent.ServerPort))
-	ac.ServerAddress = fmt.Sprintf("dns:///%s", serverHostPort)
+	if c.Agent.ServerAddress != "" {
+		serverHostPort := net.JoinHostPort(c.Agent.ServerAddress, strconv.Itoa(c.Agent.ServerPort))
+		ac.ServerAddress = fmt.Sprintf("dns:///%s", serverHostPort)
+	}
+	for _, serverAddress := range c.Agent.ServerAddresses {
+		host, port, err := net.SplitHostPort(serverAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server address %q in server_addresses; expected host:port: %w", serverAddress, err)
+		}
+		ac.ServerAddresses = append(ac.ServerAddresses, fmt.Sprintf("dns:///%s", net.JoinHostPort(host, port)))
+	}
+	ac.ServerSRVName = c.Agent.ServerSRVName
+
+	if c.Agent.ServerRebalanceInterval != "" {
+		ac.ServerRebalanceInterval, err = time.ParseDuration(c.Agent.ServerRebalanceInterval)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse server rebalance interval: %w", err)
+		}
+	}
 
 	logOptions = append(logOptions,
 		log.WithLevel(c.Agent.LogLevel),
@@ -652,6 +685,7 @@ func NewAgentConfig(c *Config, logOptions []log.Option, allowUnknownConfig bool)
 		return nil, errors.New("jwt_svid_cache_max_size should not be negative")
 	}
 	ac.JWTSVIDCacheMaxSize = c.Agent.JWTSVIDCacheMaxSize
+	ac.PersistWorkloadSVIDs = c.Agent.PersistWorkloadSVIDs
 
 	td, err := common_cli.ParseTrustDomain(c.Agent.TrustDomain, logger)
 	if err != nil {
@@ -735,6 +769,12 @@ func NewAgentConfig(c *Config, logOptions []log.Option, allowUnknownConfig bool)
 		return nil, err
 	}
 
+	if ac.PersistWorkloadSVIDs {
+		if err := checkWorkloadSnapshotKeyManager(ac.PluginConfigs, ac.DataDir); err != nil {
+			return nil, err
+		}
+	}
+
 	ac.Telemetry = c.Telemetry
 	ac.HealthChecks = c.HealthChecks
 
@@ -824,7 +864,10 @@ func NewAgentConfig(c *Config, logOptions []log.Option, allowUnknownConfig bool)
 		FetchJWTSVID:     intVal(c.Agent.Experimental.RateLimit.FetchJWTSVID),
 		FetchX509Bundles: intVal(c.Agent.Experimental.RateLimit.FetchX509Bundles),
 		FetchJWTBundles:  intVal(c.Agent.Experimental.RateLimit.FetchJWTBundles),
+		FetchWITSVID:     intVal(c.Agent.Experimental.RateLimit.FetchWITSVID),
+		FetchWITBundles:  intVal(c.Agent.Experimental.RateLimit.FetchWITBundles),
 		StreamSecrets:    intVal(c.Agent.Experimental.RateLimit.StreamSecrets),
+		DeltaSecrets:     intVal(c.Agent.Experimental.RateLimit.DeltaSecrets),
 		FetchSecrets:     intVal(c.Agent.Experimental.RateLimit.FetchSecrets),
 	}
 	if ac.WorkloadAPIRateLimit.FetchX509SVID < 0 {
@@ -839,9 +882,18 @@ func NewAgentConfig(c *Config, logOptions []log.Option, allowUnknownConfig bool)
 	if ac.WorkloadAPIRateLimit.FetchJWTBundles < 0 {
 		return nil, errors.New("experimental.ratelimit.fetch_jwt_bundles must not be negative")
 	}
+	if ac.WorkloadAPIRateLimit.FetchWITSVID < 0 {
+		return nil, errors.New("experimental.ratelimit.fetch_wit_svid must not be negative")
+	}
+	if ac.WorkloadAPIRateLimit.FetchWITBundles < 0 {
+		return nil, errors.New("experimental.ratelimit.fetch_wit_bundles must not be negative")
+	}
 	if ac.WorkloadAPIRateLimit.StreamSecrets < 0 {
 		return nil, errors.New("experimental.ratelimit.stream_secrets must not be negative")
 	}
+	if ac.WorkloadAPIRateLimit.DeltaSecrets < 0 {
+		return nil, errors.New("experimental.ratelimit.delta_secrets must not be negative")
+	}
 	if ac.WorkloadAPIRateLimit.FetchSecrets < 0 {
 		return nil, errors.New("experimental.ratelimit.fetch_secrets must not be negative")
 	}
@@ -857,6 +909,55 @@ func NewAgentConfig(c *Config, logOptions []log.Option, allowUnknownConfig bool)
 	return ac, nil
 }
 
+// checkWorkloadSnapshotKeyManager checks that the KeyManager can hold the key
+// that seals the workload SVID snapshot. The memory KeyManager loses the key on
+// restart, so the snapshot could never be restored. The disk KeyManager keeps
+// the key in plain text, so it must not keep it in the data directory, beside
+// the snapshot it seals.
+func checkWorkloadSnapshotKeyManager(pluginConfigs catalog.PluginConfigs, dataDir string) error {
+	if c, ok := pluginConfigs.Find("KeyManager", "memory"); ok && c.IsEnabled() {
+		return errors.New("persist_workload_svids cannot be used with the memory KeyManager, since the snapshot key does not survive a restart")
+	}
+
+	c, ok := pluginConfigs.Find("KeyManager", "disk")
+	if !ok || !c.IsEnabled() || c.DataSource == nil {
+		return nil
+	}
+	// Configuration errors are reported when the plugin is loaded
+	data, err := c.DataSource.Load()
+	if err != nil {
+		return nil
+	}
+	var diskConfig struct {
+		Directory string `hcl:"directory"`
+	}
+	if err := hcl.Decode(&diskConfig, data); err != nil || diskConfig.Directory == "" {
+		return nil
+	}
+
+	if isWithinDir(diskConfig.Directory, dataDir) {
+		return errors.New("persist_workload_svids cannot be used with the disk KeyManager keeping its keys within data_dir, since the snapshot key would be stored beside the snapshot")
+	}
+	return nil
+}
+
+// isWithinDir returns true if path is dir or one of its descendants.
+func isWithinDir(path, dir string) bool {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(absDir, absPath)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
 func validateConfig(c *Config) error {
 	if c.Plugins == nil {
 		return errors.New("plugins section must be configured")
@@ -937,6 +1038,14 @@ func checkForUnknownConfig(c *Config, l logrus.FieldLogger) (err error) {
 		detectedUnknown("InMem", p.UnusedKeyPositions)
 	}
 
+	if p := c.Telemetry.OTLP; p != nil && len(p.UnusedKeyPositions) != 0 {
+		detectedUnknown("OTLP", p.UnusedKeyPositions)
+	}
+
+	if p := c.Telemetry.Tracing; p != nil && len(p.UnusedKeyPositions) != 0 {
+		detectedUnknown("Tracing", p.UnusedKeyPositions)
+	}
+
 	if len(c.HealthChecks.UnusedKeyPositions) != 0 {
 		detectedUnknown("health check", c.HealthChecks.UnusedKeyPositions)
 	}
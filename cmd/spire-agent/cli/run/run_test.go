@@ -1,6 +1,7 @@
 package run
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path"
@@ -9,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hashicorp/hcl"
 	"github.com/hashicorp/hcl/hcl/ast"
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
@@ -650,6 +652,75 @@ func TestNewAgentConfig(t *testing.T) {
 				require.Equal(t, "dns:///192.168.1.1:1337", c.ServerAddress)
 			},
 		},
+		{
+			msg: "server_addresses should be correctly parsed",
+			input: func(c *Config) {
+				c.Agent.ServerAddress = ""
+				c.Agent.ServerAddresses = []string{"spire-server-1:8081", "[2001:db8::1]:8081"}
+			},
+			test: func(t *testing.T, c *agent.Config) {
+				require.Empty(t, c.ServerAddress)
+				require.Equal(t, []string{"dns:///spire-server-1:8081", "dns:///[2001:db8::1]:8081"}, c.ServerAddresses)
+			},
+		},
+		{
+			msg:                "server_addresses without a port should return an error",
+			expectError:        true,
+			requireErrorPrefix: `invalid server address "spire-server-1" in server_addresses; expected host:port`,
+			input: func(c *Config) {
+				c.Agent.ServerAddress = ""
+				c.Agent.ServerAddresses = []string{"spire-server-1"}
+			},
+			test: func(t *testing.T, c *agent.Config) {
+				require.Nil(t, c)
+			},
+		},
+		{
+			msg: "server_srv_name should be correctly parsed",
+			input: func(c *Config) {
+				c.Agent.ServerAddress = ""
+				c.Agent.ServerSRVName = "_spire-server._tcp.example.org"
+				c.Agent.ServerRebalanceInterval = "1h"
+			},
+			test: func(t *testing.T, c *agent.Config) {
+				require.Empty(t, c.ServerAddress)
+				require.Equal(t, "_spire-server._tcp.example.org", c.ServerSRVName)
+				require.Equal(t, time.Hour, c.ServerRebalanceInterval)
+			},
+		},
+		{
+			msg:                "invalid server_rebalance_interval should return an error",
+			expectError:        true,
+			requireErrorPrefix: "could not parse server rebalance interval",
+			input: func(c *Config) {
+				c.Agent.ServerRebalanceInterval = "forever"
+			},
+			test: func(t *testing.T, c *agent.Config) {
+				require.Nil(t, c)
+			},
+		},
+		{
+			msg:                "more than one server address source should return an error",
+			expectError:        true,
+			requireErrorPrefix: "only one of server_address, server_addresses or server_srv_name can be configured",
+			input: func(c *Config) {
+				c.Agent.ServerSRVName = "_spire-server._tcp.example.org"
+			},
+			test: func(t *testing.T, c *agent.Config) {
+				require.Nil(t, c)
+			},
+		},
+		{
+			msg:                "missing server address should return an error",
+			expectError:        true,
+			requireErrorPrefix: "server_address must be configured",
+			input: func(c *Config) {
+				c.Agent.ServerAddress = ""
+			},
+			test: func(t *testing.T, c *agent.Config) {
+				require.Nil(t, c)
+			},
+		},
 		{
 			msg: "trust_domain should be correctly parsed",
 			input: func(c *Config) {
@@ -1067,6 +1138,23 @@ func TestNewAgentConfig(t *testing.T) {
 				require.False(t, c.UseSyncAuthorizedEntries)
 			},
 		},
+		{
+			msg: "persist_workload_svids is set",
+			input: func(c *Config) {
+				c.Agent.PersistWorkloadSVIDs = true
+			},
+			test: func(t *testing.T, c *agent.Config) {
+				require.True(t, c.PersistWorkloadSVIDs)
+			},
+		},
+		{
+			msg: "persist_workload_svids is not set",
+			input: func(c *Config) {
+			},
+			test: func(t *testing.T, c *agent.Config) {
+				require.False(t, c.PersistWorkloadSVIDs)
+			},
+		},
 		{
 			msg: "x509_svid_cache_max_size is set",
 			input: func(c *Config) {
@@ -1431,15 +1519,18 @@ func TestNewAgentConfig(t *testing.T) {
 			},
 		},
 		{
-			msg: "ratelimit all six knobs are configurable",
+			msg: "ratelimit all nine knobs are configurable",
 			input: func(c *Config) {
-				a, b, d, e, f, g := 10, 20, 30, 40, 50, 60
+				a, b, d, e, f, g, h, i, j := 10, 20, 30, 40, 50, 60, 70, 80, 90
 				c.Agent.Experimental.RateLimit.FetchX509SVID = &a
 				c.Agent.Experimental.RateLimit.FetchJWTSVID = &b
 				c.Agent.Experimental.RateLimit.FetchX509Bundles = &d
 				c.Agent.Experimental.RateLimit.FetchJWTBundles = &e
 				c.Agent.Experimental.RateLimit.StreamSecrets = &f
 				c.Agent.Experimental.RateLimit.FetchSecrets = &g
+				c.Agent.Experimental.RateLimit.FetchWITSVID = &h
+				c.Agent.Experimental.RateLimit.FetchWITBundles = &i
+				c.Agent.Experimental.RateLimit.DeltaSecrets = &j
 			},
 			test: func(t *testing.T, ac *agent.Config) {
 				require.Equal(t, agent.WorkloadAPIRateLimitConfig{
@@ -1447,7 +1538,10 @@ func TestNewAgentConfig(t *testing.T) {
 					FetchJWTSVID:     20,
 					FetchX509Bundles: 30,
 					FetchJWTBundles:  40,
+					FetchWITSVID:     70,
+					FetchWITBundles:  80,
 					StreamSecrets:    50,
+					DeltaSecrets:     90,
 					FetchSecrets:     60,
 				}, ac.WorkloadAPIRateLimit)
 			},
@@ -1474,6 +1568,28 @@ func TestNewAgentConfig(t *testing.T) {
 				require.Nil(t, ac)
 			},
 		},
+		{
+			msg:         "ratelimit fetch_wit_svid negative value returns an error",
+			expectError: true,
+			input: func(c *Config) {
+				v := -1
+				c.Agent.Experimental.RateLimit.FetchWITSVID = &v
+			},
+			test: func(t *testing.T, ac *agent.Config) {
+				require.Nil(t, ac)
+			},
+		},
+		{
+			msg:         "ratelimit fetch_wit_bundles negative value returns an error",
+			expectError: true,
+			input: func(c *Config) {
+				v := -1
+				c.Agent.Experimental.RateLimit.FetchWITBundles = &v
+			},
+			test: func(t *testing.T, ac *agent.Config) {
+				require.Nil(t, ac)
+			},
+		},
 		{
 			msg:         "ratelimit stream_secrets negative value returns an error",
 			expectError: true,
@@ -1485,6 +1601,17 @@ func TestNewAgentConfig(t *testing.T) {
 				require.Nil(t, ac)
 			},
 		},
+		{
+			msg:         "ratelimit delta_secrets negative value returns an error",
+			expectError: true,
+			input: func(c *Config) {
+				v := -1
+				c.Agent.Experimental.RateLimit.DeltaSecrets = &v
+			},
+			test: func(t *testing.T, ac *agent.Config) {
+				require.Nil(t, ac)
+			},
+		},
 		{
 			msg:         "ratelimit fetch_secrets negative value returns an error",
 			expectError: true,
@@ -1524,6 +1651,58 @@ func TestNewAgentConfig(t *testing.T) {
 	}
 }
 
+func TestPersistWorkloadSVIDsKeyManager(t *testing.T) {
+	dataDir := t.TempDir()
+
+	for _, tt := range []struct {
+		name      string
+		plugins   string
+		expectErr string
+	}{
+		{
+			name:      "memory",
+			plugins:   `KeyManager "memory" { plugin_data {} }`,
+			expectErr: "persist_workload_svids cannot be used with the memory KeyManager, since the snapshot key does not survive a restart",
+		},
+		{
+			name:      "disk in data directory",
+			plugins:   fmt.Sprintf(`KeyManager "disk" { plugin_data { directory = %q } }`, filepath.Join(dataDir, "keys")),
+			expectErr: "persist_workload_svids cannot be used with the disk KeyManager keeping its keys within data_dir, since the snapshot key would be stored beside the snapshot",
+		},
+		{
+			name:    "disk outside data directory",
+			plugins: fmt.Sprintf(`KeyManager "disk" { plugin_data { directory = %q } }`, t.TempDir()),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var parsed struct {
+				Plugins ast.Node `hcl:"plugins"`
+			}
+			require.NoError(t, hcl.Decode(&parsed, fmt.Sprintf("plugins { %s }", tt.plugins)))
+
+			input := defaultValidConfig()
+			input.Agent.DataDir = dataDir
+			input.Agent.PersistWorkloadSVIDs = true
+			input.Plugins = parsed.Plugins
+
+			logOptions := []log.Option{
+				func(logger *log.Logger) error {
+					logger.SetOutput(io.Discard)
+					return nil
+				},
+			}
+
+			ac, err := NewAgentConfig(input, logOptions, false)
+			if tt.expectErr != "" {
+				require.EqualError(t, err, tt.expectErr)
+				require.Nil(t, ac)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestParseBrokerAllowedReferenceTypes(t *testing.T) {
 	file, err := os.CreateTemp("", "spire-agent-broker-*.conf")
 	require.NoError(t, err)
@@ -1688,6 +1867,26 @@ func TestWarnOnUnknownConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			msg:      "in nested OTLP block",
+			confFile: "server_and_agent_bad_nested_OTLP_block.conf",
+			expectedLogEntries: []logEntry{
+				{
+					section: "OTLP",
+					keys:    "unknown_option1,unknown_option2",
+				},
+			},
+		},
+		{
+			msg:      "in nested Tracing block",
+			confFile: "server_and_agent_bad_nested_Tracing_block.conf",
+			expectedLogEntries: []logEntry{
+				{
+					section: "Tracing",
+					keys:    "unknown_option1,unknown_option2",
+				},
+			},
+		},
 		{
 			msg:      "in nested health_checks block",
 			confFile: "server_and_agent_bad_nested_health_checks_block.conf",
@@ -8,6 +8,7 @@ import (
 	debugv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/agent/debug/v1"
 	loggerv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/agent/logger/v1"
 	common_cli "github.com/spiffe/spire/pkg/common/cli"
+	agentdebug "github.com/spiffe/spire/proto/private/agent/debug"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -24,6 +25,7 @@ type AgentClient interface {
 	Release()
 	NewLoggerClient() loggerv1.LoggerClient
 	NewDebugClient() debugv1.DebugClient
+	NewDebugCacheClient() agentdebug.CacheClient
 }
 
 func NewAgentClient(addr string) (AgentClient, error) {
@@ -50,6 +52,10 @@ func (c *agentClient) NewDebugClient() debugv1.DebugClient {
 	return debugv1.NewDebugClient(c.conn)
 }
 
+func (c *agentClient) NewDebugCacheClient() agentdebug.CacheClient {
+	return agentdebug.NewCacheClient(c.conn)
+}
+
 // Command is a common interface for commands in this package. The adapter
 // adapts this interface to the Command interface from github.com/mitchellh/cli.
 type Command interface {
@@ -13,6 +13,8 @@ import (
 	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
 	"github.com/spiffe/spire/cmd/spire-server/cli/agent"
 	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	agentstatusv1 "github.com/spiffe/spire/proto/private/server/agentstatus"
+	"github.com/spiffe/spire/proto/spire/common"
 	"github.com/spiffe/spire/test/clitest"
 	"github.com/spiffe/spire/test/spiretest"
 	"github.com/stretchr/testify/require"
@@ -53,6 +55,8 @@ type agentTest struct {
 	args   []string
 	server *fakeAgentServer
 	client cli.Command
+
+	statusServer *fakeAgentStatusServer
 }
 
 func (s *agentTest) afterTest(t *testing.T) {
@@ -272,9 +276,12 @@ func TestList(t *testing.T) {
 		expectedStdoutJSON   string
 		expectedStderr       string
 		expectReq            *agentv1.ListAgentsRequest
+		expectStatusFilter   *agentstatusv1.ListAgentStatusesRequest_Filter
 		existentAgents       []*types.Agent
+		existentStatuses     []*agentstatusv1.Status
 		expectedFormat       string
 		serverErr            error
+		statusServerErr      error
 	}{
 		{
 			name:                 "1 agent",
@@ -464,11 +471,105 @@ func TestList(t *testing.T) {
 			expectedReturnCode: 1,
 			expectedStderr:     "Error: date is not valid: parsing time \"2001-13-05\": month out of range\n",
 		},
+		{
+			name: "List by last seen",
+			args: []string{
+				"-lastSeenAfter", "2000-01-01 15:00:00 +0000 +00",
+				"-lastSeenBefore", "2000-01-01 16:00:00 +0000 +00",
+			},
+			expectReq: &agentv1.ListAgentsRequest{
+				Filter:   &agentv1.ListAgentsRequest_Filter{},
+				PageSize: 1000,
+			},
+			expectStatusFilter: &agentstatusv1.ListAgentStatusesRequest_Filter{
+				ByLastSeenAfter:  time.Date(2000, 1, 1, 15, 0, 0, 0, time.UTC).Unix(),
+				ByLastSeenBefore: time.Date(2000, 1, 1, 16, 0, 0, 0, time.UTC).Unix(),
+			},
+			existentAgents: []*types.Agent{
+				testAgents[0],
+				{Id: &types.SPIFFEID{TrustDomain: "example.org", Path: "/spire/agent/agent2"}},
+			},
+			existentStatuses: []*agentstatusv1.Status{
+				{SpiffeId: "spiffe://example.org/spire/agent/agent1", LastSeenAt: 946738800},
+			},
+			expectedStdoutPretty: "Found 1 attested agent:\n\nSPIFFE ID         : spiffe://example.org/spire/agent/agent1",
+			expectedStdoutJSON:   `{"agents":[{"id":{"trust_domain":"example.org","path":"/spire/agent/agent1"},"attestation_type":"","x509svid_serial_number":"","x509svid_expires_at":"0","selectors":[],"banned":false,"can_reattest":true,"agent_version":"1.00.0-dev-qwerty"}],"next_page_token":""}`,
+		},
+		{
+			name: "List with agent status",
+			expectReq: &agentv1.ListAgentsRequest{
+				Filter:   &agentv1.ListAgentsRequest_Filter{},
+				PageSize: 1000,
+			},
+			existentAgents: testAgents,
+			existentStatuses: []*agentstatusv1.Status{
+				{
+					SpiffeId:   "spiffe://example.org/spire/agent/agent1",
+					LastSeenAt: 946738800,
+					Report: &common.AgentStatusReport{
+						Uptime:          3600,
+						CachedX509Svids: 3,
+						CachedJwtSvids:  1,
+						LastSyncError:   "oh no",
+						Plugins: []*common.AgentPlugin{
+							{Type: "KeyManager", Name: "disk", Version: "1.00.0-dev-qwerty"},
+							{Type: "NodeAttestor", Name: "custom", Checksum: "abcd"},
+						},
+					},
+				},
+			},
+			expectedStdoutPretty: fmt.Sprintf(`Agent version     : 1.00.0-dev-qwerty
+Last seen         : %s
+Uptime            : 1h0m0s
+Cached X509-SVIDs : 3
+Cached JWT-SVIDs  : 1
+Last sync error   : oh no
+Plugin            : KeyManager:disk 1.00.0-dev-qwerty
+Plugin            : NodeAttestor:custom sha256:abcd
+`, time.Unix(946738800, 0)),
+			expectedStdoutJSON: `{"agents":[{"id":{"trust_domain":"example.org","path":"/spire/agent/agent1"},"attestation_type":"","x509svid_serial_number":"","x509svid_expires_at":"0","selectors":[],"banned":false,"can_reattest":true,"agent_version":"1.00.0-dev-qwerty"}],"next_page_token":""}`,
+		},
+		{
+			name: "server without agent status",
+			expectReq: &agentv1.ListAgentsRequest{
+				Filter:   &agentv1.ListAgentsRequest_Filter{},
+				PageSize: 1000,
+			},
+			existentAgents:       testAgents,
+			statusServerErr:      status.Error(codes.Unimplemented, "unknown service"),
+			expectedStdoutPretty: "Found 1 attested agent:\n\nSPIFFE ID         : spiffe://example.org/spire/agent/agent1",
+			expectedStdoutJSON:   `{"agents":[{"id":{"trust_domain":"example.org","path":"/spire/agent/agent1"},"attestation_type":"","x509svid_serial_number":"","x509svid_expires_at":"0","selectors":[],"banned":false,"can_reattest":true,"agent_version":"1.00.0-dev-qwerty"}],"next_page_token":""}`,
+		},
+		{
+			name: "List by last seen on server without agent status",
+			args: []string{"-lastSeenAfter", "2000-01-01 15:00:00 +0000 +00"},
+			expectStatusFilter: &agentstatusv1.ListAgentStatusesRequest_Filter{
+				ByLastSeenAfter: time.Date(2000, 1, 1, 15, 0, 0, 0, time.UTC).Unix(),
+			},
+			existentAgents:     testAgents,
+			statusServerErr:    status.Error(codes.Unimplemented, "unknown service"),
+			expectedReturnCode: 1,
+			expectedStderr:     "Error: rpc error: code = Unimplemented desc = unknown service\n",
+		},
+		{
+			name:               "List by lastSeenBefore: month out of range",
+			args:               []string{"-lastSeenBefore", "2001-13-05"},
+			expectedReturnCode: 1,
+			expectedStderr:     "Error: last seen before date is not valid: parsing time \"2001-13-05\": month out of range\n",
+		},
+		{
+			name:               "List by lastSeenAfter: month out of range",
+			args:               []string{"-lastSeenAfter", "2001-13-05"},
+			expectedReturnCode: 1,
+			expectedStderr:     "Error: last seen after date is not valid: parsing time \"2001-13-05\": month out of range\n",
+		},
 	} {
 		for _, format := range availableFormats {
 			t.Run(fmt.Sprintf("%s using %s format", tt.name, format), func(t *testing.T) {
 				test := setupTest(t, agent.NewListCommandWithEnv)
 				test.server.agents = tt.existentAgents
+				test.statusServer.statuses = tt.existentStatuses
+				test.statusServer.err = tt.statusServerErr
 				test.server.err = tt.serverErr
 				args := tt.args
 				args = append(args, "-output", format)
@@ -477,6 +578,9 @@ func TestList(t *testing.T) {
 
 				requireOutputBasedOnFormat(t, format, test.stdout.String(), tt.expectedStdoutPretty, tt.expectedStdoutJSON)
 				spiretest.RequireProtoEqual(t, tt.expectReq, test.server.gotListAgentRequest)
+				gotStatusFilter := test.statusServer.gotRequest.GetFilter()
+				require.Equal(t, tt.expectStatusFilter.GetByLastSeenBefore(), gotStatusFilter.GetByLastSeenBefore())
+				require.Equal(t, tt.expectStatusFilter.GetByLastSeenAfter(), gotStatusFilter.GetByLastSeenAfter())
 				require.Equal(t, tt.expectedStderr, test.stderr.String())
 				require.Equal(t, tt.expectedReturnCode, returnCode)
 			})
@@ -791,9 +895,11 @@ func TestShow(t *testing.T) {
 
 func setupTest(t *testing.T, newClient func(*commoncli.Env) cli.Command) *agentTest {
 	server := &fakeAgentServer{}
+	statusServer := &fakeAgentStatusServer{}
 
 	addr := spiretest.StartGRPCServer(t, func(s *grpc.Server) {
 		agentv1.RegisterAgentServer(s, server)
+		agentstatusv1.RegisterAgentStatusServer(s, statusServer)
 	})
 
 	stdin := new(bytes.Buffer)
@@ -813,6 +919,8 @@ func setupTest(t *testing.T, newClient func(*commoncli.Env) cli.Command) *agentT
 		args:   []string{clitest.AddrArg, clitest.GetAddr(addr)},
 		server: server,
 		client: client,
+
+		statusServer: statusServer,
 	}
 
 	t.Cleanup(func() {
@@ -862,6 +970,24 @@ func (s *fakeAgentServer) GetAgent(context.Context, *agentv1.GetAgentRequest) (*
 	return nil, s.err
 }
 
+type fakeAgentStatusServer struct {
+	agentstatusv1.UnimplementedAgentStatusServer
+
+	statuses   []*agentstatusv1.Status
+	gotRequest *agentstatusv1.ListAgentStatusesRequest
+	err        error
+}
+
+func (s *fakeAgentStatusServer) ListAgentStatuses(_ context.Context, req *agentstatusv1.ListAgentStatusesRequest) (*agentstatusv1.ListAgentStatusesResponse, error) {
+	s.gotRequest = req
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &agentstatusv1.ListAgentStatusesResponse{
+		Statuses: s.statuses,
+	}, nil
+}
+
 func requireOutputBasedOnFormat(t *testing.T, format, stdoutString string, expectedStdoutPretty, expectedStdoutJSON string) {
 	switch format {
 	case "pretty":
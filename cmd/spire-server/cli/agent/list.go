@@ -14,6 +14,9 @@ import (
 	commoncli "github.com/spiffe/spire/pkg/common/cli"
 	"github.com/spiffe/spire/pkg/common/cliprinter"
 	"github.com/spiffe/spire/pkg/common/idutil"
+	agentstatusv1 "github.com/spiffe/spire/proto/private/server/agentstatus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -37,6 +40,15 @@ type listCommand struct {
 	// Filters agents that can re-attest.
 	canReattest commoncli.BoolFlag
 
+	// Filters agents by those last seen before this value.
+	lastSeenBefore string
+
+	// Filters agents by those last seen after this value.
+	lastSeenAfter string
+
+	// Status of the listed agents, keyed by SPIFFE ID.
+	statuses map[string]*agentstatusv1.Status
+
 	env *commoncli.Env
 
 	printer cliprinter.Printer
@@ -113,6 +125,32 @@ func (c *listCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient ut
 		filter.ByBanned = wrapperspb.Bool(true)
 	}
 
+	statusFilter := &agentstatusv1.ListAgentStatusesRequest_Filter{}
+	if c.lastSeenBefore != "" {
+		lastSeenBefore, err := time.Parse("2006-01-02 15:04:05 -0700 -07", c.lastSeenBefore)
+		if err != nil {
+			return fmt.Errorf("last seen before date is not valid: %w", err)
+		}
+		statusFilter.ByLastSeenBefore = lastSeenBefore.Unix()
+	}
+	if c.lastSeenAfter != "" {
+		lastSeenAfter, err := time.Parse("2006-01-02 15:04:05 -0700 -07", c.lastSeenAfter)
+		if err != nil {
+			return fmt.Errorf("last seen after date is not valid: %w", err)
+		}
+		statusFilter.ByLastSeenAfter = lastSeenAfter.Unix()
+	}
+	filterByLastSeen := statusFilter.ByLastSeenBefore != 0 || statusFilter.ByLastSeenAfter != 0
+
+	statuses, err := listAgentStatuses(ctx, serverClient.NewAgentStatusClient(), statusFilter)
+	switch {
+	case status.Code(err) == codes.Unimplemented && !filterByLastSeen:
+		// Servers that do not track the agent status are still listed
+	case err != nil:
+		return err
+	}
+	c.statuses = statuses
+
 	agentClient := serverClient.NewAgentClient()
 
 	pageToken := ""
@@ -126,7 +164,13 @@ func (c *listCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient ut
 		if err != nil {
 			return err
 		}
-		response.Agents = append(response.Agents, listResponse.Agents...)
+		for _, agent := range listResponse.Agents {
+			// Only the agents last seen within the filter have a status
+			if filterByLastSeen && !c.hasStatus(agent) {
+				continue
+			}
+			response.Agents = append(response.Agents, agent)
+		}
 		if pageToken = listResponse.NextPageToken; pageToken == "" {
 			break
 		}
@@ -141,11 +185,13 @@ func (c *listCommand) AppendFlags(fs *flag.FlagSet) {
 	fs.Var(&c.canReattest, "canReattest", "Filter based on string received, 'true': agents that can reattest, 'false': agents that can't reattest, other value will return all.")
 	fs.Var(&c.banned, "banned", "Filter based on string received, 'true': banned agents, 'false': not banned agents, other value will return all.")
 	fs.StringVar(&c.expiresBefore, "expiresBefore", "", "Filter by expiration time (format: \"2006-01-02 15:04:05 -0700 -07\")")
+	fs.StringVar(&c.lastSeenBefore, "lastSeenBefore", "", "Filter by agents last seen before this time (format: \"2006-01-02 15:04:05 -0700 -07\")")
+	fs.StringVar(&c.lastSeenAfter, "lastSeenAfter", "", "Filter by agents last seen after this time (format: \"2006-01-02 15:04:05 -0700 -07\")")
 	fs.StringVar(&c.matchSelectorsOn, "matchSelectorsOn", "superset", "The match mode used when filtering by selectors. Options: exact, any, superset and subset")
-	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, prettyPrintAgents)
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, c.prettyPrintAgents)
 }
 
-func prettyPrintAgents(env *commoncli.Env, results ...any) error {
+func (c *listCommand) prettyPrintAgents(env *commoncli.Env, results ...any) error {
 	listResp, ok := results[0].(*agentv1.ListAgentsResponse)
 	if !ok {
 		return errors.New("internal error: cli printer; please report this bug")
@@ -159,10 +205,43 @@ func prettyPrintAgents(env *commoncli.Env, results ...any) error {
 	msg := fmt.Sprintf("Found %d attested ", len(agents))
 	msg = util.Pluralizer(msg, "agent", "agents", len(agents))
 	env.Printf("%s:\n\n", msg)
-	return printAgents(env, agents...)
+	return printAgents(env, c.statuses, agents...)
 }
 
-func printAgents(env *commoncli.Env, agents ...*types.Agent) error {
+func (c *listCommand) hasStatus(agent *types.Agent) bool {
+	id, err := idutil.IDFromProto(agent.Id)
+	if err != nil {
+		return false
+	}
+	_, ok := c.statuses[id.String()]
+	return ok
+}
+
+// listAgentStatuses returns the status of the agents matching the filter,
+// keyed by SPIFFE ID.
+func listAgentStatuses(ctx context.Context, client agentstatusv1.AgentStatusClient, filter *agentstatusv1.ListAgentStatusesRequest_Filter) (map[string]*agentstatusv1.Status, error) {
+	statuses := make(map[string]*agentstatusv1.Status)
+	pageToken := ""
+	for {
+		resp, err := client.ListAgentStatuses(ctx, &agentstatusv1.ListAgentStatusesRequest{
+			Filter:    filter,
+			PageSize:  1000,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, agentStatus := range resp.Statuses {
+			statuses[agentStatus.SpiffeId] = agentStatus
+		}
+		if pageToken = resp.NextPageToken; pageToken == "" {
+			return statuses, nil
+		}
+	}
+}
+
+// printAgents prints the given agents along with their status, if any.
+func printAgents(env *commoncli.Env, statuses map[string]*agentstatusv1.Status, agents ...*types.Agent) error {
 	for _, agent := range agents {
 		id, err := idutil.IDFromProto(agent.Id)
 		if err != nil {
@@ -196,6 +275,9 @@ func printAgents(env *commoncli.Env, agents ...*types.Agent) error {
 				return err
 			}
 		}
+		if err := printAgentStatus(env, statuses[id.String()]); err != nil {
+			return err
+		}
 
 		if err := env.Println(); err != nil {
 			return err
@@ -205,6 +287,48 @@ func printAgents(env *commoncli.Env, agents ...*types.Agent) error {
 	return nil
 }
 
+func printAgentStatus(env *commoncli.Env, agentStatus *agentstatusv1.Status) error {
+	if agentStatus == nil {
+		return nil
+	}
+	if agentStatus.LastSeenAt != 0 {
+		if err := env.Printf("Last seen         : %s\n", time.Unix(agentStatus.LastSeenAt, 0)); err != nil {
+			return err
+		}
+	}
+
+	report := agentStatus.Report
+	if report == nil {
+		return nil
+	}
+	if err := env.Printf("Uptime            : %s\n", time.Duration(report.Uptime)*time.Second); err != nil {
+		return err
+	}
+	if err := env.Printf("Cached X509-SVIDs : %d\n", report.CachedX509Svids); err != nil {
+		return err
+	}
+	if err := env.Printf("Cached JWT-SVIDs  : %d\n", report.CachedJwtSvids); err != nil {
+		return err
+	}
+	if report.LastSyncError != "" {
+		if err := env.Printf("Last sync error   : %s\n", report.LastSyncError); err != nil {
+			return err
+		}
+	}
+	for _, plugin := range report.Plugins {
+		// External plugins are identified by their checksum, built-in
+		// plugins by the agent version
+		revision := plugin.Version
+		if plugin.Checksum != "" {
+			revision = "sha256:" + plugin.Checksum
+		}
+		if err := env.Printf("Plugin            : %s:%s %s\n", plugin.Type, plugin.Name, revision); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func parseToSelectorMatch(match string) (types.SelectorMatch_MatchBehavior, error) {
 	switch match {
 	case "exact":
@@ -73,7 +73,7 @@ func prettyPrintAgent(env *commoncli.Env, results ...any) error {
 	}
 
 	env.Printf("Found an attested agent given its SPIFFE ID\n\n")
-	if err := printAgents(env, agent); err != nil {
+	if err := printAgents(env, nil, agent); err != nil {
 		return err
 	}
 
@@ -0,0 +1,106 @@
+package audit
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/mitchellh/cli"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/server/auditlog"
+)
+
+const verifyCommandName = "audit verify"
+
+// NewVerifyCommand creates a new "audit verify" subcommand.
+func NewVerifyCommand() cli.Command {
+	return newVerifyCommand(commoncli.DefaultEnv)
+}
+
+func newVerifyCommand(env *commoncli.Env) *verifyCommand {
+	return &verifyCommand{
+		env: env,
+	}
+}
+
+type verifyCommand struct {
+	env *commoncli.Env
+
+	publicKeyPath string
+	paths         []string
+}
+
+func (c *verifyCommand) Synopsis() string {
+	return "Verifies the integrity of audit log files"
+}
+
+func (c *verifyCommand) Help() string {
+	// Error is always present because -h is passed
+	return c.parseFlags([]string{"-h"}).Error()
+}
+
+func (c *verifyCommand) Run(args []string) int {
+	if err := c.parseFlags(args); err != nil {
+		return 1
+	}
+
+	if err := c.run(); err != nil {
+		_ = c.env.ErrPrintf("Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func (c *verifyCommand) parseFlags(args []string) error {
+	fs := flag.NewFlagSet(verifyCommandName, flag.ContinueOnError)
+	fs.SetOutput(c.env.Stderr)
+	fs.Usage = func() {
+		_ = c.env.ErrPrintf("Usage of %s:\n", verifyCommandName)
+		_ = c.env.ErrPrintf("  %s -publicKey <path> <audit log file>...\n\n", verifyCommandName)
+		_ = c.env.ErrPrintln("The audit log files, including rotated ones, can be passed in any order.")
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&c.publicKeyPath, "publicKey", "", "Path to the audit log public key (or to the signing key itself)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if c.publicKeyPath == "" {
+		err := errors.New("the -publicKey flag is required")
+		_ = c.env.ErrPrintf("Error: %v\n", err)
+		return err
+	}
+	if fs.NArg() == 0 {
+		err := errors.New("at least one audit log file is required")
+		_ = c.env.ErrPrintf("Error: %v\n", err)
+		return err
+	}
+	c.paths = fs.Args()
+	return nil
+}
+
+func (c *verifyCommand) run() error {
+	publicKey, err := auditlog.LoadPublicKey(c.publicKeyPath)
+	if err != nil {
+		return fmt.Errorf("unable to load public key: %w", err)
+	}
+
+	result, err := auditlog.VerifyFiles(c.paths, publicKey)
+	if err != nil {
+		return err
+	}
+
+	if len(result.Problems) > 0 {
+		for _, problem := range result.Problems {
+			_ = c.env.ErrPrintln(problem)
+		}
+		return fmt.Errorf("audit log verification failed with %d problem(s)", len(result.Problems))
+	}
+
+	if err := c.env.Printf("Verified %d records (sequence %d to %d)\n", result.Records, result.FirstSeq, result.LastSeq); err != nil {
+		return err
+	}
+	if result.FirstSeq > 1 {
+		return c.env.Printf("Records before sequence %d were not provided and could not be verified\n", result.FirstSeq)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/server/auditlog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSynopsis(t *testing.T) {
+	env, _, _ := newEnv()
+	require.Equal(t, "Verifies the integrity of audit log files", newVerifyCommand(env).Synopsis())
+}
+
+func TestHelp(t *testing.T) {
+	env, _, stderr := newEnv()
+	require.Equal(t, "flag: help requested", newVerifyCommand(env).Help())
+	require.Contains(t, stderr.String(), "Usage of audit verify:")
+	require.Contains(t, stderr.String(), "-publicKey")
+}
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "audit.log")
+	keyPath := filepath.Join(dir, "audit_key.pem")
+	publicKeyPath := keyPath + auditlog.PublicKeySuffix
+
+	w, err := auditlog.Open(auditlog.Config{
+		Path:           logPath,
+		SigningKeyPath: keyPath,
+	})
+	require.NoError(t, err)
+	for range 3 {
+		require.NoError(t, w.Append(time.Now(), map[string]any{"method": "A"}))
+	}
+	require.NoError(t, w.Close())
+
+	tamperedPath := filepath.Join(dir, "tampered.log")
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(tamperedPath, bytes.Replace(data, []byte(`"method":"A"`), []byte(`"method":"B"`), 1), 0o600))
+
+	for _, tt := range []struct {
+		name         string
+		args         []string
+		expectCode   int
+		expectStdout string
+		expectStderr string
+	}{
+		{
+			name:         "missing public key",
+			args:         []string{logPath},
+			expectCode:   1,
+			expectStderr: "Error: the -publicKey flag is required\n",
+		},
+		{
+			name:         "missing files",
+			args:         []string{"-publicKey", publicKeyPath},
+			expectCode:   1,
+			expectStderr: "Error: at least one audit log file is required\n",
+		},
+		{
+			name:         "public key does not exist",
+			args:         []string{"-publicKey", filepath.Join(dir, "missing.pub"), logPath},
+			expectCode:   1,
+			expectStderr: "Error: unable to load public key: open " + filepath.Join(dir, "missing.pub") + ": no such file or directory\n",
+		},
+		{
+			name:         "intact",
+			args:         []string{"-publicKey", publicKeyPath, logPath},
+			expectStdout: "Verified 3 records (sequence 1 to 3)\n",
+		},
+		{
+			name:         "intact using signing key",
+			args:         []string{"-publicKey", keyPath, logPath},
+			expectStdout: "Verified 3 records (sequence 1 to 3)\n",
+		},
+		{
+			name:       "tampered",
+			args:       []string{"-publicKey", publicKeyPath, tamperedPath},
+			expectCode: 1,
+			expectStderr: tamperedPath + ":1: record 1: record hash does not match its contents\n" +
+				"Error: audit log verification failed with 1 problem(s)\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			env, stdout, stderr := newEnv()
+			code := newVerifyCommand(env).Run(tt.args)
+			require.Equal(t, tt.expectCode, code)
+			require.Equal(t, tt.expectStdout, stdout.String())
+			require.Equal(t, tt.expectStderr, stderr.String())
+		})
+	}
+}
+
+func newEnv() (*commoncli.Env, *bytes.Buffer, *bytes.Buffer) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	return &commoncli.Env{
+		Stdin:  new(bytes.Buffer),
+		Stdout: stdout,
+		Stderr: stderr,
+	}, stdout, stderr
+}
@@ -12,8 +12,6 @@ import (
 	"github.com/spiffe/spire/test/spiretest"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 var AvailableFormats = []string{"pretty", "json"}
@@ -79,7 +77,12 @@ type fakeLocalAuthorityServer struct {
 	TaintedX509,
 	RevokedX509,
 	TaintedJWT,
-	RevokedJWT *localauthorityv1.AuthorityState
+	RevokedJWT,
+	ActiveWIT,
+	PreparedWIT,
+	OldWIT,
+	TaintedWIT,
+	RevokedWIT *localauthorityv1.AuthorityState
 
 	TaintedUpstreamAuthoritySubjectKeyId,
 	RevokedUpstreamAuthoritySubjectKeyId string
@@ -162,24 +165,36 @@ func (s *fakeLocalAuthorityServer) RevokeX509UpstreamAuthority(context.Context,
 	}, s.Err
 }
 
-func (s *fakeLocalAuthorityServer) GetWITAuthorityState(ctx context.Context, _ *localauthorityv1.GetWITAuthorityStateRequest) (*localauthorityv1.GetWITAuthorityStateResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "RPC is not implemented")
+func (s *fakeLocalAuthorityServer) GetWITAuthorityState(context.Context, *localauthorityv1.GetWITAuthorityStateRequest) (*localauthorityv1.GetWITAuthorityStateResponse, error) {
+	return &localauthorityv1.GetWITAuthorityStateResponse{
+		Active:   s.ActiveWIT,
+		Prepared: s.PreparedWIT,
+		Old:      s.OldWIT,
+	}, s.Err
 }
 
-func (s *fakeLocalAuthorityServer) PrepareWITAuthority(ctx context.Context, _ *localauthorityv1.PrepareWITAuthorityRequest) (*localauthorityv1.PrepareWITAuthorityResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "RPC is not implemented")
+func (s *fakeLocalAuthorityServer) PrepareWITAuthority(context.Context, *localauthorityv1.PrepareWITAuthorityRequest) (*localauthorityv1.PrepareWITAuthorityResponse, error) {
+	return &localauthorityv1.PrepareWITAuthorityResponse{
+		PreparedAuthority: s.PreparedWIT,
+	}, s.Err
 }
 
-func (s *fakeLocalAuthorityServer) ActivateWITAuthority(ctx context.Context, req *localauthorityv1.ActivateWITAuthorityRequest) (*localauthorityv1.ActivateWITAuthorityResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "RPC is not implemented")
+func (s *fakeLocalAuthorityServer) ActivateWITAuthority(context.Context, *localauthorityv1.ActivateWITAuthorityRequest) (*localauthorityv1.ActivateWITAuthorityResponse, error) {
+	return &localauthorityv1.ActivateWITAuthorityResponse{
+		ActivatedAuthority: s.ActiveWIT,
+	}, s.Err
 }
 
-func (s *fakeLocalAuthorityServer) TaintWITAuthority(ctx context.Context, req *localauthorityv1.TaintWITAuthorityRequest) (*localauthorityv1.TaintWITAuthorityResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "RPC is not implemented")
+func (s *fakeLocalAuthorityServer) TaintWITAuthority(context.Context, *localauthorityv1.TaintWITAuthorityRequest) (*localauthorityv1.TaintWITAuthorityResponse, error) {
+	return &localauthorityv1.TaintWITAuthorityResponse{
+		TaintedAuthority: s.TaintedWIT,
+	}, s.Err
 }
 
-func (s *fakeLocalAuthorityServer) RevokeWITAuthority(ctx context.Context, req *localauthorityv1.RevokeWITAuthorityRequest) (*localauthorityv1.RevokeWITAuthorityResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "RPC is not implemented")
+func (s *fakeLocalAuthorityServer) RevokeWITAuthority(context.Context, *localauthorityv1.RevokeWITAuthorityRequest) (*localauthorityv1.RevokeWITAuthorityResponse, error) {
+	return &localauthorityv1.RevokeWITAuthorityResponse{
+		RevokedAuthority: s.RevokedWIT,
+	}, s.Err
 }
 
 func RequireOutputBasedOnFormat(t *testing.T, format, stdoutString string, expectedStdoutPretty, expectedStdoutJSON string) {
@@ -9,13 +9,16 @@ import (
 
 	"github.com/mitchellh/cli"
 	"github.com/spiffe/spire/cmd/spire-server/cli/agent"
+	"github.com/spiffe/spire/cmd/spire-server/cli/audit"
 	"github.com/spiffe/spire/cmd/spire-server/cli/bundle"
+	"github.com/spiffe/spire/cmd/spire-server/cli/datastore"
 	"github.com/spiffe/spire/cmd/spire-server/cli/debug"
 	"github.com/spiffe/spire/cmd/spire-server/cli/entry"
 	"github.com/spiffe/spire/cmd/spire-server/cli/federation"
 	"github.com/spiffe/spire/cmd/spire-server/cli/healthcheck"
 	"github.com/spiffe/spire/cmd/spire-server/cli/jwt"
 	localauthority_jwt "github.com/spiffe/spire/cmd/spire-server/cli/localauthority/jwt"
+	localauthority_wit "github.com/spiffe/spire/cmd/spire-server/cli/localauthority/wit"
 	localauthority_x509 "github.com/spiffe/spire/cmd/spire-server/cli/localauthority/x509"
 	"github.com/spiffe/spire/cmd/spire-server/cli/logger"
 	"github.com/spiffe/spire/cmd/spire-server/cli/run"
@@ -61,6 +64,9 @@ func (cc *CLI) Run(ctx context.Context, args []string) int {
 		"agent purge": func() (cli.Command, error) {
 			return agent.NewPurgeCommand(), nil
 		},
+		"audit verify": func() (cli.Command, error) {
+			return audit.NewVerifyCommand(), nil
+		},
 		"bundle count": func() (cli.Command, error) {
 			return bundle.NewCountCommand(), nil
 		},
@@ -76,6 +82,15 @@ func (cc *CLI) Run(ctx context.Context, args []string) int {
 		"bundle delete": func() (cli.Command, error) {
 			return bundle.NewDeleteCommand(), nil
 		},
+		"datastore export": func() (cli.Command, error) {
+			return datastore.NewExportCommand(), nil
+		},
+		"datastore import": func() (cli.Command, error) {
+			return datastore.NewImportCommand(), nil
+		},
+		"entry apply": func() (cli.Command, error) {
+			return entry.NewApplyCommand(), nil
+		},
 		"entry count": func() (cli.Command, error) {
 			return entry.NewCountCommand(), nil
 		},
@@ -91,6 +106,9 @@ func (cc *CLI) Run(ctx context.Context, args []string) int {
 		"entry show": func() (cli.Command, error) {
 			return entry.NewShowCommand(), nil
 		},
+		"federation approve-bundle": func() (cli.Command, error) {
+			return federation.NewApproveBundleCommand(), nil
+		},
 		"federation create": func() (cli.Command, error) {
 			return federation.NewCreateCommand(), nil
 		},
@@ -100,6 +118,9 @@ func (cc *CLI) Run(ctx context.Context, args []string) int {
 		"federation list": func() (cli.Command, error) {
 			return federation.NewListCommand(), nil
 		},
+		"federation set-options": func() (cli.Command, error) {
+			return federation.NewSetOptionsCommand(), nil
+		},
 		"federation show": func() (cli.Command, error) {
 			return federation.NewShowCommand(), nil
 		},
@@ -121,9 +142,18 @@ func (cc *CLI) Run(ctx context.Context, args []string) int {
 		"run": func() (cli.Command, error) {
 			return run.NewRunCommand(ctx, cc.LogOptions, cc.AllowUnknownConfig), nil
 		},
+		"token create": func() (cli.Command, error) {
+			return token.NewCreateCommand(), nil
+		},
 		"token generate": func() (cli.Command, error) {
 			return token.NewGenerateCommand(), nil
 		},
+		"token list": func() (cli.Command, error) {
+			return token.NewListCommand(), nil
+		},
+		"token revoke": func() (cli.Command, error) {
+			return token.NewRevokeCommand(), nil
+		},
 		"healthcheck": func() (cli.Command, error) {
 			return healthcheck.NewHealthCheckCommand(), nil
 		},
@@ -166,6 +196,21 @@ func (cc *CLI) Run(ctx context.Context, args []string) int {
 		"localauthority jwt revoke": func() (cli.Command, error) {
 			return localauthority_jwt.NewJWTRevokeCommand(), nil
 		},
+		"localauthority wit show": func() (cli.Command, error) {
+			return localauthority_wit.NewWITShowCommand(), nil
+		},
+		"localauthority wit prepare": func() (cli.Command, error) {
+			return localauthority_wit.NewWITPrepareCommand(), nil
+		},
+		"localauthority wit activate": func() (cli.Command, error) {
+			return localauthority_wit.NewWITActivateCommand(), nil
+		},
+		"localauthority wit taint": func() (cli.Command, error) {
+			return localauthority_wit.NewWITTaintCommand(), nil
+		},
+		"localauthority wit revoke": func() (cli.Command, error) {
+			return localauthority_wit.NewWITRevokeCommand(), nil
+		},
 		"upstreamauthority taint": func() (cli.Command, error) {
 			return upstreamauthority.NewTaintCommand(), nil
 		},
@@ -0,0 +1,63 @@
+package datastore
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/spiffe/spire/cmd/spire-server/cli/run"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/log"
+	"github.com/spiffe/spire/pkg/server"
+	"github.com/spiffe/spire/pkg/server/datastore/sqlstore"
+)
+
+const defaultConfigPath = "conf/server/server.conf"
+
+// configFlags holds the flags shared by the datastore commands to locate and
+// parse the server configuration file.
+type configFlags struct {
+	configPath string
+	expandEnv  bool
+}
+
+func (f *configFlags) appendFlags(fs *flag.FlagSet) {
+	fs.StringVar(&f.configPath, "config", defaultConfigPath, "Path to a SPIRE server config file")
+	fs.BoolVar(&f.expandEnv, "expandEnv", false, "Expand environment variables in SPIRE server config file")
+}
+
+// loadDataStore loads the datastore described by the server configuration
+// file. The caller is responsible for closing the returned datastore.
+func (f *configFlags) loadDataStore(ctx context.Context, env *commoncli.Env) (*sqlstore.Plugin, error) {
+	// Load the configuration the same way the run command does so that
+	// defaults and feature flags are applied consistently.
+	args := []string{"-config", f.configPath}
+	if f.expandEnv {
+		args = append(args, "-expandEnv")
+	}
+	config, err := run.LoadConfig("datastore", args, []log.Option{log.WithOutputWriter(io.Discard)}, env.Stderr, false)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := server.New(*config).LoadDataStore(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load datastore: %w", err)
+	}
+	return ds, nil
+}
+
+func parseFlags(env *commoncli.Env, name string, args []string, appendFlags func(fs *flag.FlagSet)) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(env.Stderr)
+	appendFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		err := fmt.Errorf("unexpected arguments: %q", fs.Args())
+		_ = env.ErrPrintln(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package datastore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/cli"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/fflag"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/pkg/server/datastore/sqlstore"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSynopsis(t *testing.T) {
+	env, _, _ := newEnv()
+	require.Equal(t, "Exports the contents of the datastore to a portable file", newExportCommand(env).Synopsis())
+	require.Equal(t, "Imports a portable datastore export into an empty datastore", newImportCommand(env).Synopsis())
+}
+
+func TestHelp(t *testing.T) {
+	env, _, stderr := newEnv()
+	require.Equal(t, "flag: help requested", newExportCommand(env).Help())
+	require.Contains(t, stderr.String(), "Usage of datastore export:")
+	require.Contains(t, stderr.String(), "-output")
+
+	env, _, stderr = newEnv()
+	require.Equal(t, "flag: help requested", newImportCommand(env).Help())
+	require.Contains(t, stderr.String(), "Usage of datastore import:")
+	require.Contains(t, stderr.String(), "-input")
+}
+
+func TestBadFlags(t *testing.T) {
+	env, _, stderr := newEnv()
+	require.Equal(t, 1, runCommand(newExportCommand(env), "-badflag"))
+	require.Contains(t, stderr.String(), "flag provided but not defined: -badflag")
+
+	env, _, stderr = newEnv()
+	require.Equal(t, 1, runCommand(newImportCommand(env), "extra"))
+	require.Contains(t, stderr.String(), `unexpected arguments: ["extra"]`)
+}
+
+func TestMissingConfig(t *testing.T) {
+	env, _, stderr := newEnv()
+	configPath := filepath.Join(t.TempDir(), "missing.conf")
+	require.Equal(t, 1, runCommand(newExportCommand(env), "-config", configPath))
+	require.Contains(t, stderr.String(), "could not find config file")
+}
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	sourceConfig := writeConfig(t, dir, "source")
+	targetConfig := writeConfig(t, dir, "target")
+	exportPath := filepath.Join(dir, "export.json")
+
+	// Seed the source datastore
+	env, _, _ := newEnv()
+	seed := &configFlags{configPath: sourceConfig}
+	ds, err := loadDataStore(ctx, seed, env)
+	require.NoError(t, err)
+	_, err = ds.CreateRegistrationEntry(ctx, &common.RegistrationEntry{
+		EntryId:   "entry",
+		ParentId:  "spiffe://example.org/parent",
+		SpiffeId:  "spiffe://example.org/workload",
+		Selectors: []*common.Selector{{Type: "unix", Value: "uid:1000"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, ds.CreateJoinToken(ctx, &datastore.JoinToken{Token: "token", Expiry: time.Now().Add(time.Hour)}))
+	require.NoError(t, ds.Close())
+
+	// Export to a file
+	env, stdout, stderr := newEnv()
+	require.Equal(t, 0, runCommand(newExportCommand(env), "-config", sourceConfig, "-output", exportPath), stderr.String())
+	require.Equal(t, fmt.Sprintf("Datastore exported to %s\n", exportPath), stdout.String())
+
+	// Export to stdout should produce the same document
+	env, stdout, stderr = newEnv()
+	require.Equal(t, 0, runCommand(newExportCommand(env), "-config", sourceConfig), stderr.String())
+	exported, err := os.ReadFile(exportPath)
+	require.NoError(t, err)
+	require.Equal(t, string(exported), stdout.String())
+
+	// Import into an empty datastore
+	env, stdout, stderr = newEnv()
+	require.Equal(t, 0, runCommand(newImportCommand(env), "-config", targetConfig, "-input", exportPath), stderr.String())
+	require.Equal(t, "Datastore imported successfully.\n", stdout.String())
+
+	target := &configFlags{configPath: targetConfig}
+	ds, err = loadDataStore(ctx, target, env)
+	require.NoError(t, err)
+	defer ds.Close()
+	entry, err := ds.FetchRegistrationEntry(ctx, "entry")
+	require.NoError(t, err)
+	require.Equal(t, "spiffe://example.org/workload", entry.SpiffeId)
+	joinToken, err := ds.FetchJoinToken(ctx, "token")
+	require.NoError(t, err)
+	require.NotNil(t, joinToken)
+	require.NoError(t, ds.Close())
+
+	// Importing the same document again is a no-op, so an import that
+	// failed midway can be completed
+	env, stdout, stderr = newEnv()
+	env.Stdin = bytes.NewReader(exported)
+	require.Equal(t, 0, runCommand(newImportCommand(env), "-config", targetConfig), stderr.String())
+	require.Equal(t, "Datastore imported successfully.\n", stdout.String())
+}
+
+// runCommand runs the command and unloads the feature flags loaded from the
+// server configuration so the next command in the test can load them again.
+func runCommand(cmd cli.Command, args ...string) int {
+	defer func() { _ = fflag.Unload() }()
+	return cmd.Run(args)
+}
+
+func loadDataStore(ctx context.Context, flags *configFlags, env *commoncli.Env) (*sqlstore.Plugin, error) {
+	defer func() { _ = fflag.Unload() }()
+	return flags.loadDataStore(ctx, env)
+}
+
+func newEnv() (*commoncli.Env, *bytes.Buffer, *bytes.Buffer) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	return &commoncli.Env{
+		Stdin:  new(bytes.Buffer),
+		Stdout: stdout,
+		Stderr: stderr,
+	}, stdout, stderr
+}
+
+func writeConfig(t *testing.T, dir, name string) string {
+	dbPath := filepath.ToSlash(filepath.Join(dir, name+".sqlite3"))
+	configPath := filepath.Join(dir, name+".conf")
+	config := fmt.Sprintf(`
+server {
+	bind_address = "127.0.0.1"
+	bind_port = "8081"
+	trust_domain = "example.org"
+	data_dir = %q
+}
+
+plugins {
+	DataStore "sql" {
+		plugin_data {
+			database_type = "sqlite3"
+			connection_string = %q
+		}
+	}
+}
+`, filepath.ToSlash(dir), dbPath)
+	require.NoError(t, os.WriteFile(configPath, []byte(config), 0600))
+	return configPath
+}
@@ -0,0 +1,83 @@
+package datastore
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/mitchellh/cli"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/diskutil"
+	"github.com/spiffe/spire/pkg/server/datastore/portable"
+)
+
+const exportCommandName = "datastore export"
+
+// NewExportCommand creates a new "datastore export" subcommand.
+func NewExportCommand() cli.Command {
+	return newExportCommand(commoncli.DefaultEnv)
+}
+
+func newExportCommand(env *commoncli.Env) *exportCommand {
+	return &exportCommand{
+		env: env,
+	}
+}
+
+type exportCommand struct {
+	env *commoncli.Env
+
+	configFlags
+	outputPath string
+}
+
+func (c *exportCommand) Synopsis() string {
+	return "Exports the contents of the datastore to a portable file"
+}
+
+func (c *exportCommand) Help() string {
+	// Error is always present because -h is passed
+	return parseFlags(c.env, exportCommandName, []string{"-h"}, c.appendFlags).Error()
+}
+
+func (c *exportCommand) appendFlags(fs *flag.FlagSet) {
+	c.configFlags.appendFlags(fs)
+	fs.StringVar(&c.outputPath, "output", "", "Path to write the export to. Defaults to standard output")
+}
+
+func (c *exportCommand) Run(args []string) int {
+	if err := parseFlags(c.env, exportCommandName, args, c.appendFlags); err != nil {
+		return 1
+	}
+
+	if err := c.run(context.Background()); err != nil {
+		_ = c.env.ErrPrintf("Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func (c *exportCommand) run(ctx context.Context) error {
+	ds, err := c.loadDataStore(ctx, c.env)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+
+	if c.outputPath == "" {
+		return portable.Export(ctx, ds, c.env.Stdout)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := portable.Export(ctx, ds, buf); err != nil {
+		return err
+	}
+
+	// The export contains CA journals and join tokens, so restrict access
+	// to the file owner.
+	if err := diskutil.AtomicWritePrivateFile(c.outputPath, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return c.env.Printf("Datastore exported to %s\n", c.outputPath)
+}
@@ -0,0 +1,82 @@
+package datastore
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mitchellh/cli"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/server/datastore/portable"
+)
+
+const importCommandName = "datastore import"
+
+// NewImportCommand creates a new "datastore import" subcommand.
+func NewImportCommand() cli.Command {
+	return newImportCommand(commoncli.DefaultEnv)
+}
+
+func newImportCommand(env *commoncli.Env) *importCommand {
+	return &importCommand{
+		env: env,
+	}
+}
+
+type importCommand struct {
+	env *commoncli.Env
+
+	configFlags
+	inputPath string
+}
+
+func (c *importCommand) Synopsis() string {
+	return "Imports a portable datastore export into an empty datastore"
+}
+
+func (c *importCommand) Help() string {
+	// Error is always present because -h is passed
+	return parseFlags(c.env, importCommandName, []string{"-h"}, c.appendFlags).Error()
+}
+
+func (c *importCommand) appendFlags(fs *flag.FlagSet) {
+	c.configFlags.appendFlags(fs)
+	fs.StringVar(&c.inputPath, "input", "", "Path to read the export from. Defaults to standard input")
+}
+
+func (c *importCommand) Run(args []string) int {
+	if err := parseFlags(c.env, importCommandName, args, c.appendFlags); err != nil {
+		return 1
+	}
+
+	if err := c.run(context.Background()); err != nil {
+		_ = c.env.ErrPrintf("Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func (c *importCommand) run(ctx context.Context) error {
+	var r io.Reader = c.env.Stdin
+	if c.inputPath != "" {
+		f, err := os.Open(c.inputPath)
+		if err != nil {
+			return fmt.Errorf("failed to open export: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	ds, err := c.loadDataStore(ctx, c.env)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+
+	if err := portable.Import(ctx, ds, r); err != nil {
+		return err
+	}
+	return c.env.Println("Datastore imported successfully.")
+}
@@ -0,0 +1,402 @@
+package entry
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/mitchellh/cli"
+	entryv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/entry/v1"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	serverutil "github.com/spiffe/spire/cmd/spire-server/util"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/cliprinter"
+	"github.com/spiffe/spire/pkg/common/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// applyBatchSize is the maximum number of entries sent in a single
+	// batch request.
+	applyBatchSize = 500
+
+	// ownerSeparator separates the owner from the rest of the entry ID of
+	// entries created by the apply command.
+	ownerSeparator = "."
+)
+
+// ownerRegexp restricts the owner to characters that are valid in entry IDs
+// and leaves room for the rest of the entry ID.
+var ownerRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+
+// NewApplyCommand creates a new "apply" subcommand for "entry" command.
+func NewApplyCommand() cli.Command {
+	return newApplyCommand(commoncli.DefaultEnv)
+}
+
+func newApplyCommand(env *commoncli.Env) cli.Command {
+	return serverutil.AdaptCommand(env, &applyCommand{env: env})
+}
+
+type applyCommand struct {
+	// Path to the file holding the desired set of entries.
+	path string
+
+	// Owner marker used to identify the entries managed by this command.
+	owner string
+
+	// Whether owned entries not present in the file must be deleted.
+	prune bool
+
+	// Whether the plan is only printed and not applied.
+	dryRun bool
+
+	printer cliprinter.Printer
+
+	env *commoncli.Env
+}
+
+// applyResult holds the plan computed by the apply command and, unless
+// running in dry-run mode, the entries that failed to be applied.
+type applyResult struct {
+	DryRun    bool           `json:"dry_run"`
+	Create    []*types.Entry `json:"create"`
+	Update    []*types.Entry `json:"update"`
+	Delete    []*types.Entry `json:"delete"`
+	Unchanged int            `json:"unchanged"`
+	Failures  []applyFailure `json:"failures,omitempty"`
+}
+
+type applyFailure struct {
+	Action  string       `json:"action"`
+	Entry   *types.Entry `json:"entry"`
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+}
+
+func (*applyCommand) Name() string {
+	return "entry apply"
+}
+
+func (*applyCommand) Synopsis() string {
+	return "Reconciles registration entries with a desired-state file"
+}
+
+func (c *applyCommand) AppendFlags(f *flag.FlagSet) {
+	f.StringVar(&c.path, "f", "", "Path to a JSON or YAML file containing the desired registration entries. If set to '-', read from stdin.")
+	f.StringVar(&c.owner, "owner", "", "Ownership marker for the entries managed by this file. Created entries get an entry ID prefixed with the marker")
+	f.BoolVar(&c.prune, "prune", false, "If set, delete the entries carrying the ownership marker that are not present in the file. Requires -owner")
+	f.BoolVar(&c.dryRun, "dryRun", false, "If set, print the plan without applying it")
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, f, c.env, prettyPrintApply)
+}
+
+func (c *applyCommand) Run(ctx context.Context, env *commoncli.Env, serverClient serverutil.ServerClient) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+
+	desired, err := c.parseFile(env.Stdin)
+	if err != nil {
+		return err
+	}
+
+	client := serverClient.NewEntryClient()
+	existing, err := listAllEntries(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	result, err := c.plan(desired, existing)
+	if err != nil {
+		return err
+	}
+
+	if !c.dryRun {
+		if err := c.apply(ctx, client, result); err != nil {
+			return err
+		}
+	}
+
+	if err := c.printer.PrintStruct(result); err != nil {
+		return err
+	}
+
+	if len(result.Failures) > 0 {
+		return fmt.Errorf("failed to apply %d of %d changes", len(result.Failures), len(result.Create)+len(result.Update)+len(result.Delete))
+	}
+	return nil
+}
+
+func (c *applyCommand) validate() error {
+	if c.path == "" {
+		return errors.New("a file containing the desired entries is required")
+	}
+
+	if c.prune && c.owner == "" {
+		return errors.New("the -prune flag requires an ownership marker set with -owner")
+	}
+
+	if c.owner != "" && !ownerRegexp.MatchString(c.owner) {
+		return fmt.Errorf("invalid owner %q: must be at most 128 letters, digits, '-' or '_'", c.owner)
+	}
+
+	return nil
+}
+
+// parseFile parses the desired entries. YAML is converted to JSON so both
+// formats share the registration JSON structure used by "entry create".
+func (c *applyCommand) parseFile(stdin io.Reader) ([]*types.Entry, error) {
+	r := stdin
+	if c.path != "-" {
+		f, err := os.Open(c.path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	dat, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := yaml.YAMLToJSON(dat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse entries: %w", err)
+	}
+
+	entries, err := parseEntryJSON(bytes.NewReader(jsonData), "-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse entries: %w", err)
+	}
+	return entries, nil
+}
+
+// plan matches the desired entries with the existing ones using their
+// natural key and computes the entries to create, update and delete.
+func (c *applyCommand) plan(desired, existing []*types.Entry) (*applyResult, error) {
+	result := &applyResult{DryRun: c.dryRun}
+
+	existingByKey := make(map[string]*types.Entry, len(existing))
+	for _, e := range existing {
+		existingByKey[naturalKey(e)] = e
+	}
+
+	seen := make(map[string]bool, len(desired))
+	for _, d := range desired {
+		key := naturalKey(d)
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate entry for SPIFFE ID %q, parent ID %q and selectors %s",
+				protoToIDString(d.SpiffeId), protoToIDString(d.ParentId), selectorsString(d.Selectors))
+		}
+		seen[key] = true
+
+		current, ok := existingByKey[key]
+		if !ok {
+			if d.Id == "" && c.owner != "" {
+				d.Id = c.ownedEntryID(key)
+			}
+			result.Create = append(result.Create, d)
+			continue
+		}
+
+		if update := entryUpdate(current, d); update != nil {
+			result.Update = append(result.Update, update)
+		} else {
+			result.Unchanged++
+		}
+	}
+
+	if c.prune {
+		for _, e := range existing {
+			if !seen[naturalKey(e)] && c.isOwned(e) {
+				result.Delete = append(result.Delete, e)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (c *applyCommand) apply(ctx context.Context, client entryv1.EntryClient, result *applyResult) error {
+	for batch := range slices.Chunk(result.Create, applyBatchSize) {
+		resp, err := client.BatchCreateEntry(ctx, &entryv1.BatchCreateEntryRequest{Entries: batch})
+		if err != nil {
+			return fmt.Errorf("failed to create entries: %w", err)
+		}
+		for i, r := range resp.Results {
+			result.addFailure("create", batch[i], r.Status)
+		}
+	}
+
+	for batch := range slices.Chunk(result.Update, applyBatchSize) {
+		resp, err := client.BatchUpdateEntry(ctx, &entryv1.BatchUpdateEntryRequest{Entries: batch})
+		if err != nil {
+			return fmt.Errorf("failed to update entries: %w", err)
+		}
+		for i, r := range resp.Results {
+			result.addFailure("update", batch[i], r.Status)
+		}
+	}
+
+	for batch := range slices.Chunk(result.Delete, applyBatchSize) {
+		ids := make([]string, 0, len(batch))
+		for _, e := range batch {
+			ids = append(ids, e.Id)
+		}
+		resp, err := client.BatchDeleteEntry(ctx, &entryv1.BatchDeleteEntryRequest{Ids: ids})
+		if err != nil {
+			return fmt.Errorf("failed to delete entries: %w", err)
+		}
+		for i, r := range resp.Results {
+			result.addFailure("delete", batch[i], r.Status)
+		}
+	}
+
+	return nil
+}
+
+func (r *applyResult) addFailure(action string, entry *types.Entry, status *types.Status) {
+	if status.Code == int32(codes.OK) {
+		return
+	}
+	r.Failures = append(r.Failures, applyFailure{
+		Action:  action,
+		Entry:   entry,
+		Code:    util.MustCast[codes.Code](status.Code).String(),
+		Message: status.Message,
+	})
+}
+
+// ownedEntryID returns a deterministic entry ID carrying the ownership
+// marker, so that re-applying a partially applied file does not create
+// duplicate entries.
+func (c *applyCommand) ownedEntryID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return c.owner + ownerSeparator + hex.EncodeToString(sum[:16])
+}
+
+func (c *applyCommand) isOwned(e *types.Entry) bool {
+	return strings.HasPrefix(e.Id, c.owner+ownerSeparator)
+}
+
+// naturalKey identifies an entry by its SPIFFE ID, parent ID and set of
+// selectors.
+func naturalKey(e *types.Entry) string {
+	selectors := make([]string, 0, len(e.Selectors))
+	for _, s := range e.Selectors {
+		selectors = append(selectors, s.Type+":"+s.Value)
+	}
+	slices.Sort(selectors)
+	selectors = slices.Compact(selectors)
+	return strings.Join(append([]string{protoToIDString(e.SpiffeId), protoToIDString(e.ParentId)}, selectors...), "\x00")
+}
+
+// entryUpdate returns the desired entry with the identity of the current one
+// if they differ, or nil if the current entry is already up to date.
+func entryUpdate(current, desired *types.Entry) *types.Entry {
+	update := proto.Clone(desired).(*types.Entry)
+	update.Id = current.Id
+	update.RevisionNumber = current.RevisionNumber
+	update.CreatedAt = current.CreatedAt
+	// The natural keys match, so keep the current selector order.
+	update.Selectors = current.Selectors
+
+	// The order of the federated trust domains is not relevant
+	if sameElements(current.FederatesWith, update.FederatesWith) {
+		update.FederatesWith = current.FederatesWith
+	}
+
+	// Additional attributes with all the values unset are equivalent to
+	// not having additional attributes.
+	if proto.Equal(normalizedAdditionalAttributes(current), normalizedAdditionalAttributes(update)) {
+		update.AdditionalAttributes = current.AdditionalAttributes
+	}
+
+	if proto.Equal(current, update) {
+		return nil
+	}
+	return update
+}
+
+func normalizedAdditionalAttributes(e *types.Entry) *types.Entry_AdditionalAttributes {
+	if e.AdditionalAttributes == nil {
+		return &types.Entry_AdditionalAttributes{}
+	}
+	return e.AdditionalAttributes
+}
+
+func sameElements(a, b []string) bool {
+	a = slices.Sorted(slices.Values(a))
+	b = slices.Sorted(slices.Values(b))
+	return slices.Equal(a, b)
+}
+
+func selectorsString(selectors []*types.Selector) string {
+	s := make([]string, 0, len(selectors))
+	for _, selector := range selectors {
+		s = append(s, selector.Type+":"+selector.Value)
+	}
+	return fmt.Sprintf("%q", s)
+}
+
+func listAllEntries(ctx context.Context, client entryv1.EntryClient) ([]*types.Entry, error) {
+	var entries []*types.Entry
+	pageToken := ""
+	for {
+		resp, err := client.ListEntries(ctx, &entryv1.ListEntriesRequest{
+			PageSize:  listEntriesRequestPageSize,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error fetching entries: %w", err)
+		}
+		entries = append(entries, resp.Entries...)
+		if pageToken = resp.NextPageToken; pageToken == "" {
+			break
+		}
+	}
+	return entries, nil
+}
+
+func prettyPrintApply(env *commoncli.Env, results ...any) error {
+	result, ok := results[0].(*applyResult)
+	if !ok {
+		return cliprinter.ErrInternalCustomPrettyFunc
+	}
+
+	if result.DryRun {
+		env.Println("Dry run: no changes will be applied")
+	}
+	env.Printf("Plan: %d to create, %d to update, %d to delete, %d unchanged\n",
+		len(result.Create), len(result.Update), len(result.Delete), result.Unchanged)
+
+	for _, e := range result.Create {
+		env.Printf("+ create %s (parent %s, selectors %s)\n", protoToIDString(e.SpiffeId), protoToIDString(e.ParentId), selectorsString(e.Selectors))
+	}
+	for _, e := range result.Update {
+		env.Printf("~ update %s %s\n", e.Id, protoToIDString(e.SpiffeId))
+	}
+	for _, e := range result.Delete {
+		env.Printf("- delete %s %s\n", e.Id, protoToIDString(e.SpiffeId))
+	}
+
+	for _, f := range result.Failures {
+		env.ErrPrintf("Failed to %s the following entry (code: %s, msg: %q):\n", f.Action, f.Code, f.Message)
+		printEntry(f.Entry, env.ErrPrintf)
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package entry
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	entryv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/entry/v1"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestApplyHelp(t *testing.T) {
+	test := setupTest(t, newApplyCommand)
+	test.client.Help()
+
+	require.Equal(t, applyUsage, test.stderr.String())
+}
+
+func TestApplySynopsis(t *testing.T) {
+	test := setupTest(t, newApplyCommand)
+	require.Equal(t, "Reconciles registration entries with a desired-state file", test.client.Synopsis())
+}
+
+func TestApply(t *testing.T) {
+	parentID := &types.SPIFFEID{TrustDomain: "example.org", Path: "/parent"}
+
+	// Existing entries
+	outdated := &types.Entry{
+		Id:          "gitops.outdated",
+		SpiffeId:    &types.SPIFFEID{TrustDomain: "example.org", Path: "/workload1"},
+		ParentId:    parentID,
+		Selectors:   []*types.Selector{{Type: "unix", Value: "uid:1000"}, {Type: "unix", Value: "gid:1000"}},
+		X509SvidTtl: 60,
+	}
+	current := &types.Entry{
+		Id:            "gitops.current",
+		SpiffeId:      &types.SPIFFEID{TrustDomain: "example.org", Path: "/workload2"},
+		ParentId:      parentID,
+		Selectors:     []*types.Selector{{Type: "unix", Value: "uid:2000"}},
+		FederatesWith: []string{"domain1.org", "domain2.org"},
+		AdditionalAttributes: &types.Entry_AdditionalAttributes{
+			DisableX509SvidPrefetch: false,
+		},
+		RevisionNumber: 3,
+		CreatedAt:      1700000000,
+	}
+	stale := &types.Entry{
+		Id:        "gitops.stale",
+		SpiffeId:  &types.SPIFFEID{TrustDomain: "example.org", Path: "/stale"},
+		ParentId:  parentID,
+		Selectors: []*types.Selector{{Type: "unix", Value: "uid:3000"}},
+	}
+	unowned := &types.Entry{
+		Id:        "manual",
+		SpiffeId:  &types.SPIFFEID{TrustDomain: "example.org", Path: "/manual"},
+		ParentId:  parentID,
+		Selectors: []*types.Selector{{Type: "unix", Value: "uid:4000"}},
+	}
+
+	// Desired entries. Selectors and federated trust domains are listed
+	// in a different order than the existing entries.
+	desiredYAML := `
+entries:
+  - spiffe_id: spiffe://example.org/workload1
+    parent_id: spiffe://example.org/parent
+    selectors:
+      - type: unix
+        value: gid:1000
+      - type: unix
+        value: uid:1000
+    x509_svid_ttl: 120
+  - spiffe_id: spiffe://example.org/workload2
+    parent_id: spiffe://example.org/parent
+    selectors:
+      - type: unix
+        value: uid:2000
+    federates_with:
+      - spiffe://domain2.org
+      - spiffe://domain1.org
+  - spiffe_id: spiffe://example.org/workload3
+    parent_id: spiffe://example.org/parent
+    selectors:
+      - type: unix
+        value: uid:5000
+`
+	created := &types.Entry{
+		SpiffeId:  &types.SPIFFEID{TrustDomain: "example.org", Path: "/workload3"},
+		ParentId:  parentID,
+		Selectors: []*types.Selector{{Type: "unix", Value: "uid:5000"}},
+	}
+	ownedCreated := proto.Clone(created).(*types.Entry)
+	ownedCreated.Id = (&applyCommand{owner: "gitops"}).ownedEntryID(naturalKey(created))
+
+	updated := proto.Clone(outdated).(*types.Entry)
+	updated.X509SvidTtl = 120
+
+	okStatus := &types.Status{Code: int32(codes.OK), Message: "OK"}
+
+	dir := t.TempDir()
+	desiredPath := filepath.Join(dir, "entries.yaml")
+	require.NoError(t, os.WriteFile(desiredPath, []byte(desiredYAML), 0600))
+
+	for _, tt := range []struct {
+		name string
+		args []string
+
+		stdin string
+
+		expBatchCreateEntryReq *entryv1.BatchCreateEntryRequest
+		expBatchUpdateEntryReq *entryv1.BatchUpdateEntryRequest
+		expBatchDeleteEntryReq *entryv1.BatchDeleteEntryRequest
+		batchCreateEntryResp   *entryv1.BatchCreateEntryResponse
+
+		expReturnCode int
+		expStdout     []string
+		expStderr     string
+	}{
+		{
+			name: "dry run",
+			args: []string{"-f", desiredPath, "-owner", "gitops", "-prune", "-dryRun"},
+			expStdout: []string{
+				"Dry run: no changes will be applied\n",
+				"Plan: 1 to create, 1 to update, 1 to delete, 1 unchanged\n",
+				`+ create spiffe://example.org/workload3 (parent spiffe://example.org/parent, selectors ["unix:uid:5000"])`,
+				"~ update gitops.outdated spiffe://example.org/workload1\n",
+				"- delete gitops.stale spiffe://example.org/stale\n",
+			},
+		},
+		{
+			name:                   "apply with prune",
+			args:                   []string{"-f", desiredPath, "-owner", "gitops", "-prune"},
+			expBatchCreateEntryReq: &entryv1.BatchCreateEntryRequest{Entries: []*types.Entry{ownedCreated}},
+			expBatchUpdateEntryReq: &entryv1.BatchUpdateEntryRequest{Entries: []*types.Entry{updated}},
+			expBatchDeleteEntryReq: &entryv1.BatchDeleteEntryRequest{Ids: []string{"gitops.stale"}},
+			batchCreateEntryResp: &entryv1.BatchCreateEntryResponse{
+				Results: []*entryv1.BatchCreateEntryResponse_Result{{Status: okStatus, Entry: ownedCreated}},
+			},
+			expStdout: []string{
+				"Plan: 1 to create, 1 to update, 1 to delete, 1 unchanged\n",
+			},
+		},
+		{
+			name:                   "apply without owner or prune from stdin",
+			args:                   []string{"-f", "-"},
+			stdin:                  desiredYAML,
+			expBatchCreateEntryReq: &entryv1.BatchCreateEntryRequest{Entries: []*types.Entry{created}},
+			expBatchUpdateEntryReq: &entryv1.BatchUpdateEntryRequest{Entries: []*types.Entry{updated}},
+			batchCreateEntryResp: &entryv1.BatchCreateEntryResponse{
+				Results: []*entryv1.BatchCreateEntryResponse_Result{{Status: okStatus, Entry: created}},
+			},
+			expStdout: []string{
+				"Plan: 1 to create, 1 to update, 0 to delete, 1 unchanged\n",
+			},
+		},
+		{
+			name:                   "apply with failures",
+			args:                   []string{"-f", desiredPath, "-owner", "gitops", "-prune"},
+			expBatchCreateEntryReq: &entryv1.BatchCreateEntryRequest{Entries: []*types.Entry{ownedCreated}},
+			expBatchUpdateEntryReq: &entryv1.BatchUpdateEntryRequest{Entries: []*types.Entry{updated}},
+			expBatchDeleteEntryReq: &entryv1.BatchDeleteEntryRequest{Ids: []string{"gitops.stale"}},
+			batchCreateEntryResp: &entryv1.BatchCreateEntryResponse{
+				Results: []*entryv1.BatchCreateEntryResponse_Result{{Status: &types.Status{Code: int32(codes.AlreadyExists), Message: "similar entry already exists"}}},
+			},
+			expReturnCode: 1,
+			expStderr:     `Failed to create the following entry (code: AlreadyExists, msg: "similar entry already exists"):`,
+		},
+		{
+			name:          "missing file",
+			args:          []string{"-owner", "gitops"},
+			expReturnCode: 1,
+			expStderr:     "Error: a file containing the desired entries is required\n",
+		},
+		{
+			name:          "prune without owner",
+			args:          []string{"-f", desiredPath, "-prune"},
+			expReturnCode: 1,
+			expStderr:     "Error: the -prune flag requires an ownership marker set with -owner\n",
+		},
+		{
+			name:          "invalid owner",
+			args:          []string{"-f", desiredPath, "-owner", "git.ops"},
+			expReturnCode: 1,
+			expStderr:     "Error: invalid owner \"git.ops\": must be at most 128 letters, digits, '-' or '_'\n",
+		},
+		{
+			name: "duplicate entries",
+			args: []string{"-f", "-"},
+			stdin: `{"entries": [
+				{"spiffe_id": "spiffe://example.org/dup", "parent_id": "spiffe://example.org/parent", "selectors": [{"type": "unix", "value": "uid:1"}]},
+				{"spiffe_id": "spiffe://example.org/dup", "parent_id": "spiffe://example.org/parent", "selectors": [{"type": "unix", "value": "uid:1"}]}
+			]}`,
+			expReturnCode: 1,
+			expStderr:     "Error: duplicate entry for SPIFFE ID \"spiffe://example.org/dup\", parent ID \"spiffe://example.org/parent\" and selectors [\"unix:uid:1\"]\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			test := setupTest(t, newApplyCommand)
+			test.stdin.WriteString(tt.stdin)
+			test.server.expListEntriesReq = &entryv1.ListEntriesRequest{PageSize: listEntriesRequestPageSize}
+			test.server.listEntriesResp = &entryv1.ListEntriesResponse{
+				Entries: []*types.Entry{outdated, current, stale, unowned},
+			}
+			test.server.expBatchCreateEntryReq = tt.expBatchCreateEntryReq
+			test.server.expBatchUpdateEntryReq = tt.expBatchUpdateEntryReq
+			test.server.expBatchDeleteEntryReq = tt.expBatchDeleteEntryReq
+			test.server.batchCreateEntryResp = tt.batchCreateEntryResp
+			test.server.batchUpdateEntryResp = &entryv1.BatchUpdateEntryResponse{
+				Results: []*entryv1.BatchUpdateEntryResponse_Result{{Status: okStatus, Entry: updated}},
+			}
+			test.server.batchDeleteEntryResp = &entryv1.BatchDeleteEntryResponse{
+				Results: []*entryv1.BatchDeleteEntryResponse_Result{{Status: okStatus, Id: "gitops.stale"}},
+			}
+
+			rc := test.client.Run(test.args(tt.args...))
+			require.Equal(t, tt.expReturnCode, rc, fmt.Sprintf("stderr: %s", test.stderr.String()))
+			for _, s := range tt.expStdout {
+				require.Contains(t, test.stdout.String(), s)
+			}
+			require.Contains(t, test.stderr.String(), tt.expStderr)
+		})
+	}
+}
+
+func TestApplyJSONOutput(t *testing.T) {
+	test := setupTest(t, newApplyCommand)
+	test.stdin.WriteString(`{"entries": [{"spiffe_id": "spiffe://example.org/workload", "parent_id": "spiffe://example.org/parent", "selectors": [{"type": "unix", "value": "uid:1000"}]}]}`)
+	test.server.expListEntriesReq = &entryv1.ListEntriesRequest{PageSize: listEntriesRequestPageSize}
+	test.server.listEntriesResp = &entryv1.ListEntriesResponse{}
+
+	rc := test.client.Run(test.args("-f", "-", "-dryRun", "-output", "json"))
+	require.Equal(t, 0, rc, test.stderr.String())
+	require.JSONEq(t, `{
+		"dry_run": true,
+		"create": [{
+			"spiffe_id": {"trust_domain": "example.org", "path": "/workload"},
+			"parent_id": {"trust_domain": "example.org", "path": "/parent"},
+			"selectors": [{"type": "unix", "value": "uid:1000"}]
+		}],
+		"update": null,
+		"delete": null,
+		"unchanged": 0
+	}`, test.stdout.String())
+}
+
+func TestEntryUpdate(t *testing.T) {
+	current := &types.Entry{
+		Id:             "id",
+		SpiffeId:       &types.SPIFFEID{TrustDomain: "example.org", Path: "/workload"},
+		ParentId:       &types.SPIFFEID{TrustDomain: "example.org", Path: "/parent"},
+		Selectors:      []*types.Selector{{Type: "a", Value: "1"}, {Type: "b", Value: "2"}},
+		FederatesWith:  []string{"domain1.org", "domain2.org"},
+		DnsNames:       []string{"dns1"},
+		RevisionNumber: 1,
+		CreatedAt:      1,
+	}
+
+	desired := &types.Entry{
+		SpiffeId:             current.SpiffeId,
+		ParentId:             current.ParentId,
+		Selectors:            []*types.Selector{{Type: "b", Value: "2"}, {Type: "a", Value: "1"}},
+		FederatesWith:        []string{"domain2.org", "domain1.org"},
+		DnsNames:             []string{"dns1"},
+		AdditionalAttributes: &types.Entry_AdditionalAttributes{},
+	}
+	require.Nil(t, entryUpdate(current, desired))
+
+	desired.DnsNames = []string{"dns2"}
+	update := entryUpdate(current, desired)
+	require.NotNil(t, update)
+	require.Equal(t, "id", update.Id)
+	require.Equal(t, []string{"dns2"}, update.DnsNames)
+}
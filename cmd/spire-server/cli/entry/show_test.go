@@ -11,6 +11,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/wrapperspb"
+	"sigs.k8s.io/yaml"
 )
 
 func TestShowHelp(t *testing.T) {
@@ -402,6 +403,48 @@ func TestShow(t *testing.T) {
 	}
 }
 
+func TestShowTable(t *testing.T) {
+	test := setupTest(t, newShowCommand)
+	test.server.expListEntriesReq = &entryv1.ListEntriesRequest{
+		PageSize: listEntriesRequestPageSize,
+		Filter: &entryv1.ListEntriesRequest_Filter{
+			ByDownstream: wrapperspb.Bool(false),
+		},
+	}
+	test.server.listEntriesResp = &entryv1.ListEntriesResponse{
+		Entries: getEntries(2),
+	}
+
+	rc := test.client.Run(test.args("-output", "table=id,spiffe_id,selectors,hint"))
+	require.Equal(t, 0, rc, "stderr: %s", test.stderr.String())
+	require.Equal(t, ""+
+		"ID                                     SPIFFE_ID                       SELECTORS         HINT\n"+
+		"00000000-0000-0000-0000-000000000001   spiffe://example.org/daughter   bar:baz,foo:bar   external\n"+
+		"00000000-0000-0000-0000-000000000000   spiffe://example.org/son        foo:bar           internal\n",
+		test.stdout.String())
+}
+
+func TestShowYAML(t *testing.T) {
+	test := setupTest(t, newShowCommand)
+	test.server.expListEntriesReq = &entryv1.ListEntriesRequest{
+		PageSize: listEntriesRequestPageSize,
+		Filter: &entryv1.ListEntriesRequest_Filter{
+			ByDownstream: wrapperspb.Bool(false),
+		},
+	}
+	test.server.listEntriesResp = &entryv1.ListEntriesResponse{
+		Entries: getEntries(1),
+	}
+
+	rc := test.client.Run(test.args("-output", "yaml"))
+	require.Equal(t, 0, rc, "stderr: %s", test.stderr.String())
+
+	// The YAML output has the same structure and field names as the JSON output
+	jsonOut, err := yaml.YAMLToJSON(test.stdout.Bytes())
+	require.NoError(t, err)
+	require.JSONEq(t, fmt.Sprintf(`{"entries": [%s],"next_page_token": ""}`, getJSONPrintedEntry(0)), string(jsonOut))
+}
+
 // registrationEntries returns `count` registration entry records. At most 4.
 func getEntries(count int) []*types.Entry {
 	selectors := []*types.Selector{
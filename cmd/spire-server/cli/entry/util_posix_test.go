@@ -3,6 +3,22 @@
 package entry
 
 const (
+	applyUsage = `Usage of entry apply:
+  -dryRun
+    	If set, print the plan without applying it
+  -f string
+    	Path to a JSON or YAML file containing the desired registration entries. If set to '-', read from stdin.
+  -instance string
+    	Instance name to substitute into socket templates (env SPIRE_SERVER_PRIVATE_SOCKET_TEMPLATE).
+  -output value
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
+  -owner string
+    	Ownership marker for the entries managed by this file. Created entries get an entry ID prefixed with the marker
+  -prune
+    	If set, delete the entries carrying the ownership marker that are not present in the file. Requires -owner
+  -socketPath string
+    	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
+`
 	createUsage = `Usage of entry create:
   -admin
     	If set, the SPIFFE ID in this entry will be granted access to the SPIRE Server's management APIs
@@ -30,7 +46,7 @@ const (
   -node
     	If set, this entry will be applied to matching nodes rather than workloads
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -parentID string
     	The SPIFFE ID of this record's parent
   -selector value
@@ -60,7 +76,7 @@ const (
   -matchSelectorsOn string
     	The match mode used when filtering by selectors. Options: exact, any, superset and subset (default "superset")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -parentID string
     	The Parent ID of the records to show
   -selector value
@@ -95,7 +111,7 @@ const (
 ` + "    \tA boolean value that, when set, includes a unique 'jti' claim in JWT-SVIDs issued for this entry and bypasses the agent JWT-SVID cache\n" + `  -jwtSVIDTTL int
     	The lifetime, in seconds, for JWT-SVIDs issued based on this registration entry.
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -parentID string
     	The SPIFFE ID of this record's parent
   -selector value
@@ -117,7 +133,7 @@ const (
   -instance string
     	Instance name to substitute into socket templates (env SPIRE_SERVER_PRIVATE_SOCKET_TEMPLATE).
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -socketPath string
     	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
 `
@@ -135,7 +151,7 @@ const (
   -matchSelectorsOn string
     	The match mode used when filtering by selectors. Options: exact, any, superset and subset (default "superset")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -parentID string
     	The Parent ID of the records to count
   -selector value
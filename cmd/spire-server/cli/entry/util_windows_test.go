@@ -3,6 +3,20 @@
 package entry
 
 const (
+	applyUsage = `Usage of entry apply:
+  -dryRun
+    	If set, print the plan without applying it
+  -f string
+    	Path to a JSON or YAML file containing the desired registration entries. If set to '-', read from stdin.
+  -namedPipeName string
+    	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
+  -output value
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
+  -owner string
+    	Ownership marker for the entries managed by this file. Created entries get an entry ID prefixed with the marker
+  -prune
+    	If set, delete the entries carrying the ownership marker that are not present in the file. Requires -owner
+`
 	createUsage = `Usage of entry create:
   -admin
     	If set, the SPIFFE ID in this entry will be granted access to the SPIRE Server's management APIs
@@ -30,7 +44,7 @@ const (
   -node
     	If set, this entry will be applied to matching nodes rather than workloads
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -parentID string
     	The SPIFFE ID of this record's parent
   -selector value
@@ -58,7 +72,7 @@ const (
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -parentID string
     	The Parent ID of the records to show
   -selector value
@@ -91,7 +105,7 @@ const (
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -parentID string
     	The SPIFFE ID of this record's parent
   -selector value
@@ -111,7 +125,7 @@ const (
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
 `
 	countUsage = `Usage of entry count:
   -downstream
@@ -127,7 +141,7 @@ const (
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -parentID string
     	The Parent ID of the records to count
   -selector value
@@ -0,0 +1,76 @@
+package federation
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/mitchellh/cli"
+	"github.com/spiffe/spire/cmd/spire-server/util"
+	commonapi "github.com/spiffe/spire/pkg/common/api"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/cliprinter"
+	"github.com/spiffe/spire/proto/private/server/federation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func NewApproveBundleCommand() cli.Command {
+	return newApproveBundleCommand(commoncli.DefaultEnv)
+}
+
+func newApproveBundleCommand(env *commoncli.Env) cli.Command {
+	return util.AdaptCommand(env, &approveBundleCommand{env: env})
+}
+
+type approveBundleCommand struct {
+	trustDomain string
+	digest      string
+	env         *commoncli.Env
+	printer     cliprinter.Printer
+}
+
+func (c *approveBundleCommand) Name() string {
+	return "federation approve-bundle"
+}
+
+func (c *approveBundleCommand) Synopsis() string {
+	return "Approves the bundle quarantined by the bundle safeguards of a federated trust domain"
+}
+
+func (c *approveBundleCommand) AppendFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.trustDomain, "trustDomain", "", "The trust domain name of the federation relationship")
+	fs.StringVar(&c.digest, "digest", "", `Digest of the quarantined bundle, as shown by "federation show"`)
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, prettyPrintApproveBundle)
+}
+
+func (c *approveBundleCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient util.ServerClient) error {
+	if c.trustDomain == "" {
+		return errors.New("a trust domain name is required")
+	}
+	if c.digest == "" {
+		return errors.New("the digest of the quarantined bundle is required")
+	}
+
+	federationClient := serverClient.NewFederationClient()
+	_, err := federationClient.ApproveQuarantinedBundle(ctx, &federation.ApproveQuarantinedBundleRequest{
+		TrustDomain: c.trustDomain,
+		Digest:      c.digest,
+	})
+
+	switch status.Code(err) {
+	case codes.OK:
+		return c.printer.PrintProto(commonapi.OK())
+	case codes.NotFound:
+		return fmt.Errorf("there is no quarantined bundle for trust domain %q", c.trustDomain)
+	case codes.FailedPrecondition:
+		return fmt.Errorf("failed to approve bundle: %s", status.Convert(err).Message())
+	default:
+		return fmt.Errorf("failed to approve bundle: %w", err)
+	}
+}
+
+func prettyPrintApproveBundle(env *commoncli.Env, _ ...any) error {
+	return env.Println("Quarantined bundle approved")
+}
@@ -0,0 +1,103 @@
+package federation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/spiffe/spire/proto/private/server/federation"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestApproveBundleHelp(t *testing.T) {
+	test := setupTest(t, newApproveBundleCommand)
+	test.client.Help()
+
+	require.Equal(t, approveBundleUsage, test.stderr.String())
+}
+
+func TestApproveBundleSynopsis(t *testing.T) {
+	test := setupTest(t, newApproveBundleCommand)
+	require.Equal(t, "Approves the bundle quarantined by the bundle safeguards of a federated trust domain", test.client.Synopsis())
+}
+
+func TestApproveBundle(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		args []string
+
+		expectReq   *federation.ApproveQuarantinedBundleRequest
+		approveResp *federation.ApproveQuarantinedBundleResponse
+		serverErr   error
+
+		expectOutPretty string
+		expectOutJSON   string
+		expectErr       string
+	}{
+		{
+			name: "Success",
+			args: []string{"-trustDomain", "example.org", "-digest", "abcd"},
+			expectReq: &federation.ApproveQuarantinedBundleRequest{
+				TrustDomain: "example.org",
+				Digest:      "abcd",
+			},
+			expectOutPretty: "Quarantined bundle approved\n",
+			expectOutJSON:   `{"code":0,"message":"OK"}`,
+			approveResp:     &federation.ApproveQuarantinedBundleResponse{},
+		},
+		{
+			name:      "Empty trust domain",
+			args:      []string{"-digest", "abcd"},
+			expectErr: "Error: a trust domain name is required\n",
+		},
+		{
+			name:      "Empty digest",
+			args:      []string{"-trustDomain", "example.org"},
+			expectErr: "Error: the digest of the quarantined bundle is required\n",
+		},
+		{
+			name:      "Server client fails",
+			args:      []string{"-trustDomain", "example.org", "-digest", "abcd"},
+			serverErr: status.Error(codes.Internal, "oh! no"),
+			expectErr: `Error: failed to approve bundle: rpc error: code = Internal desc = oh! no
+`,
+		},
+		{
+			name:      "Digest mismatch",
+			args:      []string{"-trustDomain", "example.org", "-digest", "abcd"},
+			serverErr: status.Error(codes.FailedPrecondition, `quarantined bundle digest is "ef01"; it may have changed since it was reviewed`),
+			expectErr: `Error: failed to approve bundle: quarantined bundle digest is "ef01"; it may have changed since it was reviewed
+`,
+		},
+		{
+			name:      "No quarantined bundle",
+			args:      []string{"-trustDomain", "example.org", "-digest", "abcd"},
+			serverErr: status.Error(codes.NotFound, "not found"),
+			expectErr: `Error: there is no quarantined bundle for trust domain "example.org"
+`,
+		},
+	} {
+		for _, format := range availableFormats {
+			t.Run(fmt.Sprintf("%s using %s format", tt.name, format), func(t *testing.T) {
+				test := setupTest(t, newApproveBundleCommand)
+				test.federationServer.err = tt.serverErr
+				test.federationServer.expectApproveReq = tt.expectReq
+				test.federationServer.approveResp = tt.approveResp
+				args := tt.args
+				args = append(args, "-output", format)
+
+				rc := test.client.Run(test.args(args...))
+				if tt.expectErr != "" {
+					require.Equal(t, 1, rc)
+					require.Equal(t, tt.expectErr, test.stderr.String())
+					return
+				}
+
+				require.Equal(t, 0, rc)
+				requireOutputBasedOnFormat(t, format, test.stdout.String(), tt.expectOutPretty, tt.expectOutJSON)
+				require.Empty(t, test.stderr.String())
+			})
+		}
+	}
+}
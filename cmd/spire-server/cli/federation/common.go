@@ -111,6 +111,9 @@ func jsonToProto(fr *federationRelationshipConfig) (*types.FederationRelationshi
 			},
 		}
 
+	case profileFile:
+		return nil, fmt.Errorf("the %q bundle endpoint profile can only be configured in the federates_with section of the server configuration file", profileFile)
+
 	default:
 		return nil, fmt.Errorf("unknown bundle endpoint profile type: %q", fr.BundleEndpointProfile)
 	}
@@ -13,12 +13,14 @@ import (
 	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
 	common_cli "github.com/spiffe/spire/pkg/common/cli"
 	"github.com/spiffe/spire/pkg/common/pemutil"
+	"github.com/spiffe/spire/proto/private/server/federation"
 	"github.com/spiffe/spire/test/clitest"
 	"github.com/spiffe/spire/test/fakes/fakeserverca"
 	"github.com/spiffe/spire/test/spiretest"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -113,6 +115,8 @@ type cmdTest struct {
 	addr   string
 	server *fakeServer
 
+	federationServer *fakeFederationServer
+
 	client cli.Command
 }
 
@@ -144,6 +148,7 @@ type fakeServer struct {
 	deleteResp  *trustdomainv1.BatchDeleteFederationRelationshipResponse
 	listResp    *trustdomainv1.ListFederationRelationshipsResponse
 	showResp    *types.FederationRelationship
+	showHeader  metadata.MD
 	refreshResp *emptypb.Empty
 	updateResp  *trustdomainv1.BatchUpdateFederationRelationshipResponse
 }
@@ -175,13 +180,16 @@ func (f *fakeServer) ListFederationRelationships(_ context.Context, req *trustdo
 	return f.listResp, nil
 }
 
-func (f *fakeServer) GetFederationRelationship(_ context.Context, req *trustdomainv1.GetFederationRelationshipRequest) (*types.FederationRelationship, error) {
+func (f *fakeServer) GetFederationRelationship(ctx context.Context, req *trustdomainv1.GetFederationRelationshipRequest) (*types.FederationRelationship, error) {
 	if f.err != nil {
 		return nil, f.err
 	}
 
 	if f.showResp != nil {
 		require.Equal(f.t, f.showResp.TrustDomain, req.TrustDomain)
+		if f.showHeader != nil {
+			require.NoError(f.t, grpc.SetHeader(ctx, f.showHeader))
+		}
 		return f.showResp, nil
 	}
 	return &types.FederationRelationship{}, status.Error(codes.NotFound, "federation relationship does not exist")
@@ -205,6 +213,37 @@ func (f *fakeServer) BatchUpdateFederationRelationship(_ context.Context, req *t
 	return f.updateResp, nil
 }
 
+type fakeFederationServer struct {
+	federation.UnimplementedFederationServer
+
+	t   *testing.T
+	err error
+
+	expectApproveReq    *federation.ApproveQuarantinedBundleRequest
+	expectSetOptionsReq *federation.SetFederationRelationshipOptionsRequest
+
+	approveResp    *federation.ApproveQuarantinedBundleResponse
+	setOptionsResp *federation.SetFederationRelationshipOptionsResponse
+}
+
+func (f *fakeFederationServer) ApproveQuarantinedBundle(_ context.Context, req *federation.ApproveQuarantinedBundleRequest) (*federation.ApproveQuarantinedBundleResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	spiretest.AssertProtoEqual(f.t, f.expectApproveReq, req)
+	return f.approveResp, nil
+}
+
+func (f *fakeFederationServer) SetFederationRelationshipOptions(_ context.Context, req *federation.SetFederationRelationshipOptionsRequest) (*federation.SetFederationRelationshipOptionsResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	spiretest.AssertProtoEqual(f.t, f.expectSetOptionsReq, req)
+	return f.setOptionsResp, nil
+}
+
 func setupTest(t *testing.T, newClient func(*common_cli.Env) cli.Command) *cmdTest {
 	stdin := new(bytes.Buffer)
 	stdout := new(bytes.Buffer)
@@ -217,8 +256,10 @@ func setupTest(t *testing.T, newClient func(*common_cli.Env) cli.Command) *cmdTe
 	})
 
 	server := &fakeServer{t: t}
+	federationServer := &fakeFederationServer{t: t}
 	addr := spiretest.StartGRPCServer(t, func(s *grpc.Server) {
 		trustdomainv1.RegisterTrustDomainServer(s, server)
+		federation.RegisterFederationServer(s, federationServer)
 	})
 
 	test := &cmdTest{
@@ -228,6 +269,8 @@ func setupTest(t *testing.T, newClient func(*common_cli.Env) cli.Command) *cmdTe
 		stderr: stderr,
 		server: server,
 		client: client,
+
+		federationServer: federationServer,
 	}
 
 	t.Cleanup(func() {
@@ -19,6 +19,10 @@ import (
 const (
 	profileHTTPSWeb    = "https_web"
 	profileHTTPSSPIFFE = "https_spiffe"
+
+	// profileFile reads bundles from the local filesystem. It is only
+	// available for relationships in the server configuration file.
+	profileFile = "file"
 )
 
 // NewCreateCommand creates a new "create" subcommand for "federation" command.
@@ -161,6 +161,12 @@ func TestCreate(t *testing.T) {
 			expErrPretty: "Error: unknown bundle endpoint profile type: \"bad-type\"\n",
 			expErrJSON:   "Error: unknown bundle endpoint profile type: \"bad-type\"\n",
 		},
+		{
+			name:         "File endpoint profile",
+			args:         []string{"-trustDomain", "td.org", "-bundleEndpointURL", "file:///var/lib/spire/bundles", "-bundleEndpointProfile", "file"},
+			expErrPretty: "Error: the \"file\" bundle endpoint profile can only be configured in the federates_with section of the server configuration file\n",
+			expErrJSON:   "Error: the \"file\" bundle endpoint profile can only be configured in the federates_with section of the server configuration file\n",
+		},
 		{
 			name:         "Missing endpoint SPIFFE ID",
 			args:         []string{"-trustDomain", "td.org", "-bundleEndpointURL", "https://td.org/bundle", "-bundleEndpointProfile", profileHTTPSSPIFFE},
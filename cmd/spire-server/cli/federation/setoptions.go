@@ -0,0 +1,142 @@
+package federation
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/mitchellh/cli"
+	serverutil "github.com/spiffe/spire/cmd/spire-server/util"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/cliprinter"
+	"github.com/spiffe/spire/pkg/common/util"
+	"github.com/spiffe/spire/proto/private/server/federation"
+	"github.com/spiffe/spire/proto/spire/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func NewSetOptionsCommand() cli.Command {
+	return newSetOptionsCommand(commoncli.DefaultEnv)
+}
+
+func newSetOptionsCommand(env *commoncli.Env) cli.Command {
+	return serverutil.AdaptCommand(env, &setOptionsCommand{env: env})
+}
+
+type setOptionsCommand struct {
+	trustDomain          string
+	requireOverlap       bool
+	maxKeys              int
+	minRemainingValidity commoncli.DurationFlag
+	pinnedKeys           commoncli.StringsFlag
+	bundleEndpointHub    bool
+	env                  *commoncli.Env
+	printer              cliprinter.Printer
+}
+
+func (c *setOptionsCommand) Name() string {
+	return "federation set-options"
+}
+
+func (c *setOptionsCommand) Synopsis() string {
+	return "Sets the bundle safeguards and the federation hub mode of a federation relationship"
+}
+
+func (c *setOptionsCommand) AppendFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.trustDomain, "trustDomain", "", "The trust domain name of the federation relationship")
+	fs.BoolVar(&c.requireOverlap, "requireOverlap", false, "Quarantine bundles that share no key with the current bundle")
+	fs.IntVar(&c.maxKeys, "maxKeys", 0, "Quarantine bundles with more keys than this. Zero means no limit")
+	fs.Var(&c.minRemainingValidity, "minRemainingValidity", "Quarantine bundles without an X.509 authority valid for at least this long (e.g. 24h). Zero means no requirement")
+	fs.Var(&c.pinnedKeys, "pinnedKey", "SHA-256 fingerprint of the SubjectPublicKeyInfo of a key that must remain in the bundle. Can be used more than once")
+	fs.BoolVar(&c.bundleEndpointHub, "bundleEndpointHub", false, "Whether the bundle endpoint URL of the relationship is the /federated path of a federation hub")
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, prettyPrintSetOptions)
+}
+
+func (c *setOptionsCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient serverutil.ServerClient) error {
+	if c.trustDomain == "" {
+		return errors.New("a trust domain name is required")
+	}
+	if c.maxKeys < 0 {
+		return errors.New("max keys must not be negative")
+	}
+	if c.minRemainingValidity < 0 {
+		return errors.New("min remaining validity must not be negative")
+	}
+
+	maxKeys, err := util.CheckedCast[int32](c.maxKeys)
+	if err != nil {
+		return fmt.Errorf("invalid value for max keys: %w", err)
+	}
+
+	// The options are replaced as a whole, so setting no safeguard clears
+	// the safeguards of the relationship and omitting -bundleEndpointHub
+	// turns the federation hub mode off.
+	options := &common.FederationRelationshipOptions{
+		BundleEndpointHub: c.bundleEndpointHub,
+	}
+	if c.requireOverlap || c.maxKeys > 0 || c.minRemainingValidity > 0 || len(c.pinnedKeys) > 0 {
+		options.BundleSafeguards = &common.BundleSafeguards{
+			RequireOverlap:       c.requireOverlap,
+			MaxKeys:              maxKeys,
+			MinRemainingValidity: int64(time.Duration(c.minRemainingValidity) / time.Second),
+			PinnedKeys:           c.pinnedKeys,
+		}
+	}
+
+	federationClient := serverClient.NewFederationClient()
+	resp, err := federationClient.SetFederationRelationshipOptions(ctx, &federation.SetFederationRelationshipOptionsRequest{
+		TrustDomain: c.trustDomain,
+		Options:     options,
+	})
+
+	switch status.Code(err) {
+	case codes.OK:
+		return c.printer.PrintProto(resp)
+	case codes.NotFound:
+		return fmt.Errorf("there is no federation relationship with trust domain %q", c.trustDomain)
+	case codes.InvalidArgument:
+		return fmt.Errorf("failed to set federation relationship options: %s", status.Convert(err).Message())
+	default:
+		return fmt.Errorf("failed to set federation relationship options: %w", err)
+	}
+}
+
+func prettyPrintSetOptions(env *commoncli.Env, results ...any) error {
+	resp, ok := results[0].(*federation.SetFederationRelationshipOptionsResponse)
+	if !ok {
+		return cliprinter.ErrInternalCustomPrettyFunc
+	}
+
+	if err := prettyPrintSafeguards(env, resp.Options.GetBundleSafeguards()); err != nil {
+		return err
+	}
+	return env.Printf("Bundle endpoint hub    : %t\n", resp.Options.GetBundleEndpointHub())
+}
+
+func prettyPrintSafeguards(env *commoncli.Env, safeguards *common.BundleSafeguards) error {
+	if safeguards == nil {
+		return env.Println("Bundle safeguards cleared")
+	}
+
+	if err := env.Println("Bundle safeguards set"); err != nil {
+		return err
+	}
+	if err := env.Printf("Require overlap        : %t\n", safeguards.RequireOverlap); err != nil {
+		return err
+	}
+	if err := env.Printf("Max keys               : %d\n", safeguards.MaxKeys); err != nil {
+		return err
+	}
+	if err := env.Printf("Min remaining validity : %s\n", time.Duration(safeguards.MinRemainingValidity)*time.Second); err != nil {
+		return err
+	}
+	for _, pinnedKey := range safeguards.PinnedKeys {
+		if err := env.Printf("Pinned key             : %s\n", pinnedKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package federation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/spiffe/spire/proto/private/server/federation"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSetOptionsHelp(t *testing.T) {
+	test := setupTest(t, newSetOptionsCommand)
+	test.client.Help()
+
+	require.Equal(t, setOptionsUsage, test.stderr.String())
+}
+
+func TestSetOptionsSynopsis(t *testing.T) {
+	test := setupTest(t, newSetOptionsCommand)
+	require.Equal(t, "Sets the bundle safeguards and the federation hub mode of a federation relationship", test.client.Synopsis())
+}
+
+func TestSetOptions(t *testing.T) {
+	safeguards := &common.BundleSafeguards{
+		RequireOverlap:       true,
+		MaxKeys:              10,
+		MinRemainingValidity: 86400,
+		PinnedKeys:           []string{"0102", "0304"},
+	}
+
+	for _, tt := range []struct {
+		name string
+		args []string
+
+		expectReq      *federation.SetFederationRelationshipOptionsRequest
+		setOptionsResp *federation.SetFederationRelationshipOptionsResponse
+		serverErr      error
+
+		expectOutPretty string
+		expectOutJSON   string
+		expectErr       string
+	}{
+		{
+			name: "Set safeguards",
+			args: []string{"-trustDomain", "example.org", "-requireOverlap", "-maxKeys", "10", "-minRemainingValidity", "24h", "-pinnedKey", "0102", "-pinnedKey", "0304"},
+			expectReq: &federation.SetFederationRelationshipOptionsRequest{
+				TrustDomain: "example.org",
+				Options: &common.FederationRelationshipOptions{
+					BundleSafeguards: safeguards,
+				},
+			},
+			setOptionsResp: &federation.SetFederationRelationshipOptionsResponse{
+				Options: &common.FederationRelationshipOptions{
+					BundleSafeguards: safeguards,
+				},
+			},
+			expectOutPretty: `Bundle safeguards set
+Require overlap        : true
+Max keys               : 10
+Min remaining validity : 24h0m0s
+Pinned key             : 0102
+Pinned key             : 0304
+Bundle endpoint hub    : false
+`,
+			expectOutJSON: `{"options":{"bundle_endpoint_hub":false,"bundle_safeguards":{"require_overlap":true,"max_keys":10,"min_remaining_validity":"86400","pinned_keys":["0102","0304"]}}}`,
+		},
+		{
+			name: "Clear safeguards",
+			args: []string{"-trustDomain", "example.org"},
+			expectReq: &federation.SetFederationRelationshipOptionsRequest{
+				TrustDomain: "example.org",
+				Options:     &common.FederationRelationshipOptions{},
+			},
+			setOptionsResp: &federation.SetFederationRelationshipOptionsResponse{
+				Options: &common.FederationRelationshipOptions{},
+			},
+			expectOutPretty: "Bundle safeguards cleared\nBundle endpoint hub    : false\n",
+			expectOutJSON:   `{"options":{"bundle_endpoint_hub":false}}`,
+		},
+		{
+			name: "Set federation hub",
+			args: []string{"-trustDomain", "example.org", "-bundleEndpointHub"},
+			expectReq: &federation.SetFederationRelationshipOptionsRequest{
+				TrustDomain: "example.org",
+				Options: &common.FederationRelationshipOptions{
+					BundleEndpointHub: true,
+				},
+			},
+			setOptionsResp: &federation.SetFederationRelationshipOptionsResponse{
+				Options: &common.FederationRelationshipOptions{
+					BundleEndpointHub: true,
+				},
+			},
+			expectOutPretty: "Bundle safeguards cleared\nBundle endpoint hub    : true\n",
+			expectOutJSON:   `{"options":{"bundle_endpoint_hub":true}}`,
+		},
+		{
+			name:      "Empty trust domain",
+			args:      []string{"-maxKeys", "10"},
+			expectErr: "Error: a trust domain name is required\n",
+		},
+		{
+			name:      "Negative max keys",
+			args:      []string{"-trustDomain", "example.org", "-maxKeys", "-1"},
+			expectErr: "Error: max keys must not be negative\n",
+		},
+		{
+			name:      "Negative min remaining validity",
+			args:      []string{"-trustDomain", "example.org", "-minRemainingValidity", "-1h"},
+			expectErr: "Error: min remaining validity must not be negative\n",
+		},
+		{
+			name:      "No relationship",
+			args:      []string{"-trustDomain", "example.org", "-maxKeys", "10"},
+			serverErr: status.Error(codes.NotFound, "federation relationship does not exist"),
+			expectErr: `Error: there is no federation relationship with trust domain "example.org"
+`,
+		},
+		{
+			name:      "Invalid options",
+			args:      []string{"-trustDomain", "example.org", "-pinnedKey", "zz"},
+			serverErr: status.Error(codes.InvalidArgument, "invalid federation relationship options: invalid bundle safeguards pinned key"),
+			expectErr: `Error: failed to set federation relationship options: invalid federation relationship options: invalid bundle safeguards pinned key
+`,
+		},
+		{
+			name:      "Server client fails",
+			args:      []string{"-trustDomain", "example.org", "-maxKeys", "10"},
+			serverErr: status.Error(codes.Internal, "oh! no"),
+			expectErr: `Error: failed to set federation relationship options: rpc error: code = Internal desc = oh! no
+`,
+		},
+	} {
+		for _, format := range availableFormats {
+			t.Run(fmt.Sprintf("%s using %s format", tt.name, format), func(t *testing.T) {
+				test := setupTest(t, newSetOptionsCommand)
+				test.federationServer.err = tt.serverErr
+				test.federationServer.expectSetOptionsReq = tt.expectReq
+				test.federationServer.setOptionsResp = tt.setOptionsResp
+				args := tt.args
+				args = append(args, "-output", format)
+
+				rc := test.client.Run(test.args(args...))
+				if tt.expectErr != "" {
+					require.Equal(t, 1, rc)
+					require.Equal(t, tt.expectErr, test.stderr.String())
+					return
+				}
+
+				require.Equal(t, 0, rc)
+				requireOutputBasedOnFormat(t, format, test.stdout.String(), tt.expectOutPretty, tt.expectOutJSON)
+				require.Empty(t, test.stderr.String())
+			})
+		}
+	}
+}
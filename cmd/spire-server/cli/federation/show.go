@@ -5,13 +5,18 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"time"
 
 	"github.com/mitchellh/cli"
 	trustdomainv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/trustdomain/v1"
 	prototypes "github.com/spiffe/spire-api-sdk/proto/spire/api/types"
 	"github.com/spiffe/spire/cmd/spire-server/util"
+	"github.com/spiffe/spire/pkg/common/bundlequarantine"
 	commoncli "github.com/spiffe/spire/pkg/common/cli"
 	"github.com/spiffe/spire/pkg/common/cliprinter"
+	"github.com/spiffe/spire/proto/spire/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 func NewShowCommand() cli.Command {
@@ -27,6 +32,11 @@ type showCommand struct {
 	trustDomain string
 	env         *commoncli.Env
 	printer     cliprinter.Printer
+
+	// Quarantined bundle of the trust domain, if any, and the bundle it
+	// would replace
+	quarantined   *common.QuarantinedBundle
+	currentBundle *common.Bundle
 }
 
 func (c *showCommand) Name() string {
@@ -49,9 +59,15 @@ func (c *showCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient ut
 
 	trustDomainClient := serverClient.NewTrustDomainClient()
 
+	var header metadata.MD
 	fr, err := trustDomainClient.GetFederationRelationship(ctx, &trustdomainv1.GetFederationRelationshipRequest{
 		TrustDomain: c.trustDomain,
-	})
+	}, grpc.Header(&header))
+	if err != nil {
+		return fmt.Errorf("error showing federation relationship: %w", err)
+	}
+
+	c.quarantined, c.currentBundle, err = bundlequarantine.QuarantinedBundleFromHeader(header)
 	if err != nil {
 		return fmt.Errorf("error showing federation relationship: %w", err)
 	}
@@ -67,5 +83,32 @@ func (c *showCommand) prettyPrintShow(env *commoncli.Env, results ...any) error
 	env.Printf("Found a federation relationship with trust domain %s:\n\n", c.trustDomain)
 	printFederationRelationship(fr, env.Printf)
 
+	if c.quarantined != nil {
+		return printQuarantinedBundle(env, c.trustDomain, c.quarantined, c.currentBundle)
+	}
+	return nil
+}
+
+func printQuarantinedBundle(env *commoncli.Env, trustDomain string, quarantined *common.QuarantinedBundle, current *common.Bundle) error {
+	digest, err := bundlequarantine.Digest(quarantined.Bundle)
+	if err != nil {
+		return err
+	}
+	changes, err := bundlequarantine.Diff(current, quarantined.Bundle)
+	if err != nil {
+		return err
+	}
+
+	env.Printf("\nA bundle update was quarantined by the bundle safeguards:\n\n")
+	env.Printf("Quarantined at            : %s\n", time.Unix(quarantined.QuarantinedAt, 0).UTC())
+	env.Printf("Digest                    : %s\n", digest)
+	for _, violation := range quarantined.Violations {
+		env.Printf("Violation                 : %s\n", violation)
+	}
+	env.Printf("Key changes               :\n")
+	for _, change := range changes {
+		env.Printf("  %s\n", change)
+	}
+	env.Printf("\nReview the changes and run \"federation approve-bundle -trustDomain %s -digest %s\" to apply the bundle.\n", trustDomain, digest)
 	return nil
 }
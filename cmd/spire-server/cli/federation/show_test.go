@@ -1,11 +1,16 @@
 package federation
 
 import (
+	"crypto/x509"
 	"fmt"
 	"testing"
+	"time"
 
 	trustdomainv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/trustdomain/v1"
 	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"github.com/spiffe/spire/pkg/common/bundlequarantine"
+	"github.com/spiffe/spire/pkg/common/pemutil"
+	"github.com/spiffe/spire/proto/spire/common"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -134,3 +139,57 @@ Endpoint SPIFFE ID        : spiffe://endpoint.test/id
 		}
 	}
 }
+
+func TestShowQuarantinedBundle(t *testing.T) {
+	currentCert, err := pemutil.ParseCertificate([]byte(pemCert))
+	require.NoError(t, err)
+	nextBundle, _ := createBundle(t, "example-1.test")
+	nextCert, err := x509.ParseCertificate(nextBundle.X509Authorities[0].Asn1)
+	require.NoError(t, err)
+
+	current := &common.Bundle{
+		TrustDomainId: "spiffe://example-1.test",
+		RootCas:       []*common.Certificate{{DerBytes: currentCert.Raw}},
+	}
+	quarantined := &common.QuarantinedBundle{
+		TrustDomainId: "spiffe://example-1.test",
+		Bundle: &common.Bundle{
+			TrustDomainId: "spiffe://example-1.test",
+			RootCas:       []*common.Certificate{{DerBytes: nextCert.Raw}},
+		},
+		Violations:    []string{"no key overlaps with the current bundle"},
+		QuarantinedAt: 1700000000,
+	}
+	header, err := bundlequarantine.HeaderFromQuarantinedBundle(quarantined, current)
+	require.NoError(t, err)
+	digest, err := bundlequarantine.Digest(quarantined.Bundle)
+	require.NoError(t, err)
+
+	test := setupTest(t, newShowCommand)
+	test.server.showResp = &types.FederationRelationship{
+		TrustDomain:           "example-1.test",
+		BundleEndpointUrl:     "https://bundle-endpoint-1.test/endpoint",
+		BundleEndpointProfile: &types.FederationRelationship_HttpsWeb{},
+	}
+	test.server.showHeader = header
+
+	rc := test.client.Run(test.args("-trustDomain", "example-1.test"))
+	require.Equal(t, 0, rc, test.stderr.String())
+	require.Equal(t, `Found a federation relationship with trust domain example-1.test:
+
+Trust domain              : example-1.test
+Bundle endpoint URL       : https://bundle-endpoint-1.test/endpoint
+Bundle endpoint profile   : https_web
+
+A bundle update was quarantined by the bundle safeguards:
+
+Quarantined at            : 2023-11-14 22:13:20 +0000 UTC
+Digest                    : `+digest+`
+Violation                 : no key overlaps with the current bundle
+Key changes               :
+  - x509 `+bundlequarantine.Fingerprint(currentCert.RawSubjectPublicKeyInfo)+` expires 9999-12-31T23:59:59Z
+  + x509 `+bundlequarantine.Fingerprint(nextCert.RawSubjectPublicKeyInfo)+` (`+nextCert.Subject.String()+`) expires `+nextCert.NotAfter.UTC().Format(time.RFC3339)+`
+
+Review the changes and run "federation approve-bundle -trustDomain example-1.test -digest `+digest+`" to apply the bundle.
+`, test.stdout.String())
+}
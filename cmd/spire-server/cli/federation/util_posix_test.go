@@ -3,6 +3,18 @@
 package federation
 
 const (
+	approveBundleUsage = `Usage of federation approve-bundle:
+  -digest string
+    	Digest of the quarantined bundle, as shown by "federation show"
+  -instance string
+    	Instance name to substitute into socket templates (env SPIRE_SERVER_PRIVATE_SOCKET_TEMPLATE).
+  -output value
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
+  -socketPath string
+    	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
+  -trustDomain string
+    	The trust domain name of the federation relationship
+`
 	createUsage = `Usage of federation create:
   -bundleEndpointProfile string
     	Endpoint profile type (either "https_web" or "https_spiffe")
@@ -15,7 +27,7 @@ const (
   -instance string
     	Instance name to substitute into socket templates (env SPIRE_SERVER_PRIVATE_SOCKET_TEMPLATE).
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -socketPath string
     	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
   -trustDomain string
@@ -31,7 +43,7 @@ const (
   -instance string
     	Instance name to substitute into socket templates (env SPIRE_SERVER_PRIVATE_SOCKET_TEMPLATE).
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -socketPath string
     	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
 `
@@ -39,7 +51,7 @@ const (
   -instance string
     	Instance name to substitute into socket templates (env SPIRE_SERVER_PRIVATE_SOCKET_TEMPLATE).
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -socketPath string
     	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
 `
@@ -49,15 +61,35 @@ const (
   -instance string
     	Instance name to substitute into socket templates (env SPIRE_SERVER_PRIVATE_SOCKET_TEMPLATE).
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -socketPath string
     	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
+`
+	setOptionsUsage = `Usage of federation set-options:
+  -bundleEndpointHub
+    	Whether the bundle endpoint URL of the relationship is the /federated path of a federation hub
+  -instance string
+    	Instance name to substitute into socket templates (env SPIRE_SERVER_PRIVATE_SOCKET_TEMPLATE).
+  -maxKeys int
+    	Quarantine bundles with more keys than this. Zero means no limit
+  -minRemainingValidity value
+    	Quarantine bundles without an X.509 authority valid for at least this long (e.g. 24h). Zero means no requirement
+  -output value
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
+  -pinnedKey value
+    	SHA-256 fingerprint of the SubjectPublicKeyInfo of a key that must remain in the bundle. Can be used more than once
+  -requireOverlap
+    	Quarantine bundles that share no key with the current bundle
+  -socketPath string
+    	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
+  -trustDomain string
+    	The trust domain name of the federation relationship
 `
 	showUsage = `Usage of federation show:
   -instance string
     	Instance name to substitute into socket templates (env SPIRE_SERVER_PRIVATE_SOCKET_TEMPLATE).
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -socketPath string
     	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
   -trustDomain string
@@ -75,7 +107,7 @@ const (
   -instance string
     	Instance name to substitute into socket templates (env SPIRE_SERVER_PRIVATE_SOCKET_TEMPLATE).
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -socketPath string
     	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
   -trustDomain string
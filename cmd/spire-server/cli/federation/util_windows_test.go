@@ -3,6 +3,16 @@
 package federation
 
 const (
+	approveBundleUsage = `Usage of federation approve-bundle:
+  -digest string
+    	Digest of the quarantined bundle, as shown by "federation show"
+  -namedPipeName string
+    	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
+  -output value
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
+  -trustDomain string
+    	The trust domain name of the federation relationship
+`
 	createUsage = `Usage of federation create:
   -bundleEndpointProfile string
     	Endpoint profile type (either "https_web" or "https_spiffe")
@@ -15,7 +25,7 @@ const (
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -trustDomain string
     	Name of the trust domain to federate with (e.g., example.org)
   -trustDomainBundleFormat string
@@ -29,13 +39,13 @@ const (
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
 `
 	listUsage = `Usage of federation list:
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
 `
 	refreshUsage = `Usage of federation refresh:
   -id string
@@ -43,13 +53,31 @@ const (
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
+`
+	setOptionsUsage = `Usage of federation set-options:
+  -bundleEndpointHub
+    	Whether the bundle endpoint URL of the relationship is the /federated path of a federation hub
+  -maxKeys int
+    	Quarantine bundles with more keys than this. Zero means no limit
+  -minRemainingValidity value
+    	Quarantine bundles without an X.509 authority valid for at least this long (e.g. 24h). Zero means no requirement
+  -namedPipeName string
+    	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
+  -output value
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
+  -pinnedKey value
+    	SHA-256 fingerprint of the SubjectPublicKeyInfo of a key that must remain in the bundle. Can be used more than once
+  -requireOverlap
+    	Quarantine bundles that share no key with the current bundle
+  -trustDomain string
+    	The trust domain name of the federation relationship
 `
 	showUsage = `Usage of federation show:
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -trustDomain string
     	The trust domain name of the federation relationship to show
 `
@@ -65,7 +93,7 @@ const (
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -trustDomain string
     	Name of the trust domain to federate with (e.g., example.org)
   -trustDomainBundleFormat string
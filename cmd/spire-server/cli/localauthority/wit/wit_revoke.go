@@ -0,0 +1,86 @@
+package wit
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/mitchellh/cli"
+	localauthorityv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/localauthority/v1"
+	"github.com/spiffe/spire/cmd/spire-server/cli/authoritycommon"
+	"github.com/spiffe/spire/cmd/spire-server/util"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/cliprinter"
+)
+
+// NewWITActivateCommand creates a new "wit revoke" subcommand for "localauthority" command.
+func NewWITRevokeCommand() cli.Command {
+	return NewWITRevokeCommandWithEnv(commoncli.DefaultEnv)
+}
+
+// NewWITActivateCommandWithEnv creates a new "wit revoke" subcommand for "localauthority" command
+// using the environment specified
+func NewWITRevokeCommandWithEnv(env *commoncli.Env) cli.Command {
+	return util.AdaptCommand(env, &witRevokeCommand{env: env})
+}
+
+type witRevokeCommand struct {
+	authorityID string
+	printer     cliprinter.Printer
+	env         *commoncli.Env
+}
+
+func (c *witRevokeCommand) Name() string {
+	return "localauthority wit revoke"
+}
+
+func (*witRevokeCommand) Synopsis() string {
+	return "Revokes the previously active WIT authority by removing it from the bundle and propagating this update throughout the cluster"
+}
+
+func (c *witRevokeCommand) AppendFlags(f *flag.FlagSet) {
+	f.StringVar(&c.authorityID, "authorityID", "", "The authority ID of the WIT authority to revoke")
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, f, c.env, prettyPrintWITRevoke)
+}
+
+// Run executes all logic associated with a single invocation of the
+// `spire-server localauthority wit revoke` CLI command
+func (c *witRevokeCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient util.ServerClient) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+
+	client := serverClient.NewLocalAuthorityClient()
+	resp, err := client.RevokeWITAuthority(ctx, &localauthorityv1.RevokeWITAuthorityRequest{
+		AuthorityId: c.authorityID,
+	})
+	if err != nil {
+		return fmt.Errorf("could not revoke WIT authority: %w", err)
+	}
+
+	return c.printer.PrintProto(resp)
+}
+
+func (c *witRevokeCommand) validate() error {
+	if c.authorityID == "" {
+		return errors.New("an authority ID is required")
+	}
+
+	return nil
+}
+
+func prettyPrintWITRevoke(env *commoncli.Env, results ...any) error {
+	r, ok := results[0].(*localauthorityv1.RevokeWITAuthorityResponse)
+	if !ok {
+		return errors.New("internal error: cli printer; please report this bug")
+	}
+
+	env.Println("Revoked WIT authority:")
+	if r.RevokedAuthority == nil {
+		return errors.New("internal error: expected to have revoked WIT authority information")
+	}
+	authoritycommon.PrettyPrintWITAuthorityState(env, r.RevokedAuthority)
+
+	return nil
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package wit_test
+
+var (
+	witRevokeUsage = `Usage of localauthority wit revoke:
+  -authorityID string
+    	The authority ID of the WIT authority to revoke
+  -namedPipeName string
+    	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
+  -output value
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
+`
+)
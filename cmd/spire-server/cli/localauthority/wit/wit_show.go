@@ -0,0 +1,84 @@
+package wit
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	"github.com/mitchellh/cli"
+	localauthorityv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/localauthority/v1"
+	"github.com/spiffe/spire/cmd/spire-server/cli/authoritycommon"
+	"github.com/spiffe/spire/cmd/spire-server/util"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/cliprinter"
+)
+
+// NewWITShowCommand creates a new "wit show" subcommand for "localauthority" command.
+func NewWITShowCommand() cli.Command {
+	return NewWITShowCommandWithEnv(commoncli.DefaultEnv)
+}
+
+// NewWITShowCommandWithEnv creates a new "wit show" subcommand for "localauthority" command
+// using the environment specified
+func NewWITShowCommandWithEnv(env *commoncli.Env) cli.Command {
+	return util.AdaptCommand(env, &witShowCommand{env: env})
+}
+
+type witShowCommand struct {
+	printer cliprinter.Printer
+
+	env *commoncli.Env
+}
+
+func (c *witShowCommand) Name() string {
+	return "localauthority wit show"
+}
+
+func (*witShowCommand) Synopsis() string {
+	return "Shows the local WIT authorities"
+}
+
+func (c *witShowCommand) AppendFlags(f *flag.FlagSet) {
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, f, c.env, prettyPrintWITShow)
+}
+
+// Run executes all logic associated with a single invocation of the
+// `spire-server localauthority wit show` CLI command
+func (c *witShowCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient util.ServerClient) error {
+	client := serverClient.NewLocalAuthorityClient()
+	resp, err := client.GetWITAuthorityState(ctx, &localauthorityv1.GetWITAuthorityStateRequest{})
+	if err != nil {
+		return err
+	}
+
+	return c.printer.PrintProto(resp)
+}
+
+func prettyPrintWITShow(env *commoncli.Env, results ...any) error {
+	r, ok := results[0].(*localauthorityv1.GetWITAuthorityStateResponse)
+	if !ok {
+		return errors.New("internal error: cli printer; please report this bug")
+	}
+
+	env.Println("Active WIT authority:")
+	if r.Active != nil {
+		authoritycommon.PrettyPrintWITAuthorityState(env, r.Active)
+	} else {
+		env.Println("  No active WIT authority found")
+	}
+	env.Println()
+	env.Println("Prepared WIT authority:")
+	if r.Prepared != nil {
+		authoritycommon.PrettyPrintWITAuthorityState(env, r.Prepared)
+	} else {
+		env.Println("  No prepared WIT authority found")
+	}
+	env.Println()
+	env.Println("Old WIT authority:")
+	if r.Old != nil {
+		authoritycommon.PrettyPrintWITAuthorityState(env, r.Old)
+	} else {
+		env.Println("  No old WIT authority found")
+	}
+	return nil
+}
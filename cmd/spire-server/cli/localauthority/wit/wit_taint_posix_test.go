@@ -0,0 +1,16 @@
+//go:build !windows
+
+package wit_test
+
+var (
+	witTaintUsage = `Usage of localauthority wit taint:
+  -authorityID string
+    	The authority ID of the WIT authority to taint
+  -instance string
+    	Instance name to substitute into socket templates (env SPIRE_SERVER_PRIVATE_SOCKET_TEMPLATE).
+  -output value
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
+  -socketPath string
+    	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
+`
+)
@@ -9,7 +9,7 @@ var (
   -level string
     	The new log level, one of (panic, fatal, error, warn, info, debug, trace)
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table[=<columns>], template=<go-template>); default: pretty.
   -socketPath string
     	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
 `
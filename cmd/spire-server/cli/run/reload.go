@@ -0,0 +1,189 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/hcl/hcl/token"
+	"github.com/sirupsen/logrus"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/common/diskcertmanager"
+	"github.com/spiffe/spire/pkg/common/log"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/pkg/server"
+)
+
+// reloadableFields are the server configuration fields that can be changed
+// without restarting the server. The remaining fields, other than the
+// runtime hooks, require a restart.
+var reloadableFields = map[string]bool{
+	"AdminIDs":        true,
+	"AuditLogEnabled": true,
+	"JWTSVIDTTL":      true,
+	"RateLimit":       true,
+	"X509SVIDTTL":     true,
+
+	// Runtime hooks, which are not configuration
+	"ConfigReloader": true,
+	"Log":            true,
+	"LogReopener":    true,
+}
+
+var restartRequiredCmpOpts = []cmp.Option{
+	cmpopts.EquateComparable(spiffeid.ID{}, spiffeid.TrustDomain{}),
+	cmpopts.IgnoreTypes(map[string][]token.Pos{}),
+	// The trust domains to federate with can be reloaded
+	cmpopts.IgnoreFields(server.FederationConfig{}, "FederatesWith"),
+	cmp.Comparer(func(a, b *diskcertmanager.DiskCertManager) bool {
+		if a == nil || b == nil {
+			return a == b
+		}
+		return a.Config() == b.Config()
+	}),
+}
+
+type configUpdater interface {
+	UpdateConfig(server.ReloadableConfig) error
+}
+
+// configReloader reloads the server configuration when the server receives
+// the reconfigure signal. Changes to the settings that can be reloaded are
+// applied to the running server. If any other setting changed, the whole
+// reload is refused, since those changes require a restart.
+type configReloader struct {
+	log                logrus.FieldLogger
+	args               []string
+	allowUnknownConfig bool
+	server             configUpdater
+
+	// input and config are the configuration the server was started with
+	input  *Config
+	config *server.Config
+
+	// reloadable holds the reloadable settings currently applied
+	reloadable server.ReloadableConfig
+}
+
+func newConfigReloader(args []string, allowUnknownConfig bool, input *Config, config *server.Config) *configReloader {
+	return &configReloader{
+		log:                config.Log.WithField(telemetry.SubsystemName, "config_reloader"),
+		args:               args,
+		allowUnknownConfig: allowUnknownConfig,
+		input:              input,
+		config:             config,
+		reloadable:         config.Reloadable(),
+	}
+}
+
+func (r *configReloader) Reconfigure(context.Context) {
+	if err := r.reload(); err != nil {
+		r.log.WithError(err).Error("Failed to reload server configuration")
+	}
+}
+
+func (r *configReloader) reload() error {
+	input, config, err := r.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if changed := restartRequiredChanges(r.input, input, r.config, config); len(changed) > 0 {
+		return fmt.Errorf("changes to %s require a restart; no changes were applied", strings.Join(changed, ", "))
+	}
+
+	reloadable := config.Reloadable()
+	changed := reloadableChanges(r.reloadable, reloadable)
+	if len(changed) == 0 {
+		r.log.Info("Server configuration not reloaded since it is unchanged")
+		return nil
+	}
+
+	if err := r.server.UpdateConfig(reloadable); err != nil {
+		return err
+	}
+	r.reloadable = reloadable
+	r.log.WithField(telemetry.Settings, changed).Info("Server configuration reloaded")
+	return nil
+}
+
+// loadConfig parses the configuration the same way it is parsed on startup,
+// without opening the log file.
+func (r *configReloader) loadConfig() (*Config, *server.Config, error) {
+	input, err := loadInput(commandName, r.args, io.Discard)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logFile := input.Server.LogFile
+	input.Server.LogFile = ""
+	config, err := NewServerConfig(input, []log.Option{log.WithOutputWriter(io.Discard)}, r.allowUnknownConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	input.Server.LogFile = logFile
+	return input, config, nil
+}
+
+// restartRequiredChanges returns the names of the settings that changed and
+// cannot be applied to the running server.
+func restartRequiredChanges(runningInput, input *Config, running, config *server.Config) []string {
+	var changed []string
+
+	// Logging and feature flags are set up before the server configuration
+	// is built, so they are compared on the parsed input.
+	for _, setting := range []struct {
+		name  string
+		equal bool
+	}{
+		{name: "LogFile", equal: runningInput.Server.LogFile == input.Server.LogFile},
+		{name: "LogFormat", equal: runningInput.Server.LogFormat == input.Server.LogFormat},
+		{name: "LogSourceLocation", equal: runningInput.Server.LogSourceLocation == input.Server.LogSourceLocation},
+		{name: "FeatureFlags", equal: slices.Equal(runningInput.Server.Experimental.Flags, input.Server.Experimental.Flags)},
+	} {
+		if !setting.equal {
+			changed = append(changed, setting.name)
+		}
+	}
+
+	runningValue := reflect.ValueOf(*running)
+	configValue := reflect.ValueOf(*config)
+	for i := range runningValue.NumField() {
+		name := runningValue.Type().Field(i).Name
+		if reloadableFields[name] {
+			continue
+		}
+		if !cmp.Equal(runningValue.Field(i).Interface(), configValue.Field(i).Interface(), restartRequiredCmpOpts...) {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// reloadableChanges returns the names of the reloadable settings that
+// changed.
+func reloadableChanges(current, config server.ReloadableConfig) []string {
+	var changed []string
+	for _, setting := range []struct {
+		name  string
+		equal bool
+	}{
+		{name: "AdminIDs", equal: slices.Equal(current.AdminIDs, config.AdminIDs)},
+		{name: "FederatesWith", equal: cmp.Equal(current.FederatesWith, config.FederatesWith, restartRequiredCmpOpts...)},
+		{name: "RateLimit", equal: current.RateLimit == config.RateLimit},
+		{name: "X509SVIDTTL", equal: current.X509SVIDTTL == config.X509SVIDTTL},
+		{name: "JWTSVIDTTL", equal: current.JWTSVIDTTL == config.JWTSVIDTTL},
+		{name: "LogLevel", equal: current.LogLevel == config.LogLevel},
+		{name: "AuditLogEnabled", equal: current.AuditLogEnabled == config.AuditLogEnabled},
+	} {
+		if !setting.equal {
+			changed = append(changed, setting.name)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,259 @@
+package run
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/pkg/server"
+	bundleClient "github.com/spiffe/spire/pkg/server/bundle/client"
+	"github.com/spiffe/spire/pkg/server/credtemplate"
+	"github.com/spiffe/spire/pkg/server/endpoints"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+)
+
+const reloadTestConfig = `
+server {
+	bind_address = "127.0.0.1"
+	bind_port = "8081"
+	trust_domain = "example.org"
+	data_dir = "DATA_DIR"
+	log_level = "INFO"
+	admin_ids = ["spiffe://example.org/admin"]
+	default_x509_svid_ttl = "1h"
+	ca_subject {
+		country = ["US"]
+		organization = ["SPIFFE"]
+	}
+	federation {
+		bundle_endpoint {
+			address = "0.0.0.0"
+			port = 8443
+		}
+		federates_with "domain1.test" {
+			bundle_endpoint_url = "https://domain1.test/bundle"
+			bundle_endpoint_profile "https_spiffe" {
+				endpoint_spiffe_id = "spiffe://domain1.test/spire/server"
+			}
+		}
+	}
+}
+
+plugins {
+	DataStore "sql" {
+		plugin_data {
+			database_type = "sqlite3"
+			connection_string = "DATA_DIR/datastore.sqlite3"
+		}
+	}
+	KeyManager "memory" {
+		plugin_data = {}
+	}
+}
+
+telemetry {
+	Prometheus {
+		port = 9988
+	}
+}
+
+health_checks {
+	listener_enabled = true
+}
+`
+
+func TestConfigReloader(t *testing.T) {
+	adminID := spiffeid.RequireFromString("spiffe://example.org/admin")
+	otherAdminID := spiffeid.RequireFromString("spiffe://example.org/other-admin")
+	domain1 := spiffeid.RequireTrustDomainFromString("domain1.test")
+	domain1Config := bundleClient.TrustDomainConfig{
+		EndpointURL: "https://domain1.test/bundle",
+		EndpointProfile: bundleClient.HTTPSSPIFFEProfile{
+			EndpointSPIFFEID: spiffeid.RequireFromString("spiffe://domain1.test/spire/server"),
+		},
+	}
+	initial := server.ReloadableConfig{
+		AdminIDs:      []spiffeid.ID{adminID},
+		FederatesWith: map[spiffeid.TrustDomain]bundleClient.TrustDomainConfig{domain1: domain1Config},
+		RateLimit:     endpoints.RateLimitConfig{Attestation: true, Signing: true},
+		X509SVIDTTL:   time.Hour,
+		JWTSVIDTTL:    credtemplate.DefaultJWTSVIDTTL,
+		LogLevel:      logrus.InfoLevel,
+	}
+
+	for _, tt := range []struct {
+		name         string
+		edit         func(string) string
+		updateErr    error
+		expectUpdate *server.ReloadableConfig
+		expectLogs   []spiretest.LogEntry
+	}{
+		{
+			name: "unchanged",
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.InfoLevel,
+					Message: "Server configuration not reloaded since it is unchanged",
+				},
+			},
+		},
+		{
+			name: "reloadable settings changed",
+			edit: func(config string) string {
+				config = strings.Replace(config, `"spiffe://example.org/admin"`, `"spiffe://example.org/other-admin"`, 1)
+				config = strings.Replace(config, `log_level = "INFO"`, `log_level = "DEBUG"`, 1)
+				config = strings.Replace(config, `default_x509_svid_ttl = "1h"`, `default_x509_svid_ttl = "2h"
+	default_jwt_svid_ttl = "10m"
+	audit_log_enabled = true
+	ratelimit {
+		attestation = false
+	}`, 1)
+				return strings.Replace(config, `federates_with "domain1.test"`, `federates_with "domain2.test"`, 1)
+			},
+			expectUpdate: &server.ReloadableConfig{
+				AdminIDs: []spiffeid.ID{otherAdminID},
+				FederatesWith: map[spiffeid.TrustDomain]bundleClient.TrustDomainConfig{
+					spiffeid.RequireTrustDomainFromString("domain2.test"): domain1Config,
+				},
+				RateLimit:       endpoints.RateLimitConfig{Signing: true},
+				X509SVIDTTL:     2 * time.Hour,
+				JWTSVIDTTL:      10 * time.Minute,
+				LogLevel:        logrus.DebugLevel,
+				AuditLogEnabled: true,
+			},
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.InfoLevel,
+					Message: "Server configuration reloaded",
+					Data: logrus.Fields{
+						telemetry.Settings: "[AdminIDs FederatesWith RateLimit X509SVIDTTL JWTSVIDTTL LogLevel AuditLogEnabled]",
+					},
+				},
+			},
+		},
+		{
+			name: "restart required",
+			edit: func(config string) string {
+				config = strings.Replace(config, `"spiffe://example.org/admin"`, `"spiffe://example.org/other-admin"`, 1)
+				config = strings.Replace(config, `bind_port = "8081"`, `bind_port = "8082"
+	log_format = "json"`, 1)
+				config = strings.Replace(config, `port = 8443`, `port = 8444`, 1)
+				config = strings.Replace(config, `database_type = "sqlite3"`, `database_type = "postgres"`, 1)
+				return strings.Replace(config, `country = ["US"]`, `country = ["AR"]`, 1)
+			},
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Failed to reload server configuration",
+					Data: logrus.Fields{
+						logrus.ErrorKey: "changes to LogFormat, PluginConfigs, BindAddress, CASubject, Federation require a restart; no changes were applied",
+					},
+				},
+			},
+		},
+		{
+			name: "invalid configuration",
+			edit: func(config string) string {
+				return strings.Replace(config, `trust_domain = "example.org"`, `trust_domain = "EXAMPLE?"`, 1)
+			},
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Failed to reload server configuration",
+					Data: logrus.Fields{
+						logrus.ErrorKey: `could not parse trust_domain "EXAMPLE?": trust domain characters are limited to lowercase letters, numbers, dots, dashes, and underscores`,
+					},
+				},
+			},
+		},
+		{
+			name: "server not running",
+			edit: func(config string) string {
+				return strings.Replace(config, `log_level = "INFO"`, `log_level = "WARN"`, 1)
+			},
+			updateErr: errors.New("server is not running"),
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Failed to reload server configuration",
+					Data: logrus.Fields{
+						logrus.ErrorKey: "server is not running",
+					},
+				},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			configPath := filepath.Join(dir, "server.conf")
+			baseConfig := strings.ReplaceAll(reloadTestConfig, "DATA_DIR", dir)
+			require.NoError(t, os.WriteFile(configPath, []byte(baseConfig), 0o600))
+
+			log, logHook := test.NewNullLogger()
+			updater := &fakeConfigUpdater{err: tt.updateErr}
+			reloader := newTestConfigReloader(t, []string{"-config", configPath}, updater)
+			reloader.log = log
+			require.Equal(t, initial, reloader.reloadable)
+
+			if tt.edit != nil {
+				require.NoError(t, os.WriteFile(configPath, []byte(tt.edit(baseConfig)), 0o600))
+			}
+			reloader.Reconfigure(t.Context())
+
+			spiretest.AssertLogs(t, logHook.AllEntries(), tt.expectLogs)
+			if tt.expectUpdate == nil {
+				require.Equal(t, initial, reloader.reloadable)
+				if tt.updateErr == nil {
+					require.Empty(t, updater.updates)
+				}
+				return
+			}
+			require.Equal(t, []server.ReloadableConfig{*tt.expectUpdate}, updater.updates)
+			require.Equal(t, *tt.expectUpdate, reloader.reloadable)
+
+			// Reloading again does not update the server
+			logHook.Reset()
+			reloader.Reconfigure(t.Context())
+			require.Len(t, updater.updates, 1)
+			spiretest.AssertLogs(t, logHook.AllEntries(), []spiretest.LogEntry{
+				{
+					Level:   logrus.InfoLevel,
+					Message: "Server configuration not reloaded since it is unchanged",
+				},
+			})
+		})
+	}
+}
+
+func newTestConfigReloader(t *testing.T, args []string, updater configUpdater) *configReloader {
+	// The configuration is loaded the same way on startup and on reload,
+	// except for the feature flags, which can only be loaded once.
+	r := &configReloader{args: args}
+	input, config, err := r.loadConfig()
+	require.NoError(t, err)
+
+	reloader := newConfigReloader(args, false, input, config)
+	reloader.server = updater
+	return reloader
+}
+
+type fakeConfigUpdater struct {
+	updates []server.ReloadableConfig
+	err     error
+}
+
+func (u *fakeConfigUpdater) UpdateConfig(config server.ReloadableConfig) error {
+	if u.err != nil {
+		return u.err
+	}
+	u.updates = append(u.updates, config)
+	return nil
+}
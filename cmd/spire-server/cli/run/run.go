@@ -39,11 +39,13 @@ import (
 	"github.com/spiffe/spire/pkg/common/telemetry"
 	"github.com/spiffe/spire/pkg/common/tlspolicy"
 	"github.com/spiffe/spire/pkg/server"
+	"github.com/spiffe/spire/pkg/server/auditlog"
 	"github.com/spiffe/spire/pkg/server/authpolicy"
 	bundleClient "github.com/spiffe/spire/pkg/server/bundle/client"
 	"github.com/spiffe/spire/pkg/server/ca/manager"
 	"github.com/spiffe/spire/pkg/server/credtemplate"
 	"github.com/spiffe/spire/pkg/server/endpoints/bundle"
+	"github.com/spiffe/spire/pkg/server/node"
 	"github.com/spiffe/spire/pkg/server/plugin/keymanager"
 )
 
@@ -68,6 +70,7 @@ type Config struct {
 type serverConfig struct {
 	AdminIDs                     []string           `hcl:"admin_ids"`
 	AgentTTL                     string             `hcl:"agent_ttl"`
+	AuditLog                     *auditLogConfig    `hcl:"audit_log"`
 	AuditLogEnabled              bool               `hcl:"audit_log_enabled"`
 	BindAddress                  string             `hcl:"bind_address"`
 	BindPort                     int                `hcl:"bind_port"`
@@ -92,6 +95,8 @@ type serverConfig struct {
 	ProxyProtocolTrustedCIDRs    []string           `hcl:"proxy_protocol_trusted_cidrs"`
 	RateLimit                    rateLimitConfig    `hcl:"ratelimit"`
 	SocketPath                   string             `hcl:"socket_path"`
+	StaleAgentsNotSeenFor        string             `hcl:"stale_agents_not_seen_for"`
+	StaleAgentsAction            string             `hcl:"stale_agents_action"`
 	TrustDomain                  string             `hcl:"trust_domain"`
 	MaxAttestedNodeInfoStaleness *string            `hcl:"max_attested_node_info_staleness"`
 
@@ -127,6 +132,14 @@ type experimentalConfig struct {
 	UnusedKeyPositions map[string][]token.Pos `hcl:",unusedKeyPositions"`
 }
 
+type auditLogConfig struct {
+	Path               string                 `hcl:"path"`
+	MaxSizeMB          int64                  `hcl:"max_size_mb"`
+	MaxBackups         *int                   `hcl:"max_backups"`
+	SigningKeyPath     string                 `hcl:"signing_key_path"`
+	UnusedKeyPositions map[string][]token.Pos `hcl:",unusedKeyPositions"`
+}
+
 type caSubjectConfig struct {
 	Country            []string               `hcl:"country"`
 	Organization       []string               `hcl:"organization"`
@@ -147,7 +160,13 @@ type bundleEndpointConfig struct {
 
 	ACME    *bundleEndpointACMEConfig `hcl:"acme"`
 	Profile ast.Node                  `hcl:"profile"`
+	Hub     *bundleEndpointHubConfig  `hcl:"hub"`
+
+	UnusedKeyPositions map[string][]token.Pos `hcl:",unusedKeyPositions"`
+}
 
+type bundleEndpointHubConfig struct {
+	TrustDomains       []string               `hcl:"trust_domains"`
 	UnusedKeyPositions map[string][]token.Pos `hcl:",unusedKeyPositions"`
 }
 
@@ -182,12 +201,14 @@ type bundleEndpointACMEConfig struct {
 type federatesWithConfig struct {
 	BundleEndpointURL     string                 `hcl:"bundle_endpoint_url"`
 	BundleEndpointProfile ast.Node               `hcl:"bundle_endpoint_profile"`
+	BundleEndpointHub     bool                   `hcl:"bundle_endpoint_hub"`
 	UnusedKeyPositions    map[string][]token.Pos `hcl:",unusedKeyPositions"`
 }
 
 type bundleEndpointProfileConfig struct {
 	HTTPSSPIFFE        *httpsSPIFFEProfileConfig `hcl:"https_spiffe"`
 	HTTPSWeb           *httpsWebProfileConfig    `hcl:"https_web"`
+	File               *fileProfileConfig        `hcl:"file"`
 	UnusedKeyPositions map[string][]token.Pos    `hcl:",unusedKeyPositions"`
 }
 
@@ -198,6 +219,11 @@ type httpsSPIFFEProfileConfig struct {
 
 type httpsWebProfileConfig struct{}
 
+type fileProfileConfig struct {
+	TrustedSignerKeysPath string                 `hcl:"trusted_signer_keys_path"`
+	UnusedKeyPositions    map[string][]token.Pos `hcl:",unusedKeyPositions"`
+}
+
 type rateLimitConfig struct {
 	Attestation        *bool                  `hcl:"attestation"`
 	Signing            *bool                  `hcl:"signing"`
@@ -239,6 +265,31 @@ func Help(name string, writer io.Writer) string {
 }
 
 func LoadConfig(name string, args []string, logOptions []log.Option, output io.Writer, allowUnknownConfig bool) (*server.Config, error) {
+	_, c, err := loadConfig(name, args, logOptions, output, allowUnknownConfig)
+	return c, err
+}
+
+func loadConfig(name string, args []string, logOptions []log.Option, output io.Writer, allowUnknownConfig bool) (*Config, *server.Config, error) {
+	input, err := loadInput(name, args, output)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = fflag.Load(input.Server.Experimental.Flags)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading feature flags: %w", err)
+	}
+
+	c, err := NewServerConfig(input, logOptions, allowUnknownConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	return input, c, nil
+}
+
+// loadInput parses the CLI flags and the config file and merges them with
+// the defaults.
+func loadInput(name string, args []string, output io.Writer) (*Config, error) {
 	// First parse the CLI flags so we can get the config
 	// file path, if set
 	cliInput, err := parseFlags(name, args, output)
@@ -253,22 +304,12 @@ func LoadConfig(name string, args []string, logOptions []log.Option, output io.W
 		return nil, err
 	}
 
-	input, err := mergeInput(fileInput, cliInput)
-	if err != nil {
-		return nil, err
-	}
-
-	err = fflag.Load(input.Server.Experimental.Flags)
-	if err != nil {
-		return nil, fmt.Errorf("error loading feature flags: %w", err)
-	}
-
-	return NewServerConfig(input, logOptions, allowUnknownConfig)
+	return mergeInput(fileInput, cliInput)
 }
 
 // Run the SPIFFE Server
 func (cmd *Command) Run(args []string) int {
-	c, err := LoadConfig(commandName, args, cmd.logOptions, cmd.env.Stderr, cmd.allowUnknownConfig)
+	input, c, err := loadConfig(commandName, args, cmd.logOptions, cmd.env.Stderr, cmd.allowUnknownConfig)
 	if err != nil {
 		_, _ = fmt.Fprintln(cmd.env.Stderr, err)
 		return 1
@@ -277,7 +318,10 @@ func (cmd *Command) Run(args []string) int {
 	// Set umask before starting up the server
 	common_cli.SetUmask(c.Log)
 
+	reloader := newConfigReloader(args, cmd.allowUnknownConfig, input, c)
+	c.ConfigReloader = reloader
 	s := server.New(*c)
+	reloader.server = s
 
 	ctx := cmd.ctx
 	if ctx == nil {
@@ -433,6 +477,16 @@ func NewServerConfig(c *Config, logOptions []log.Option, allowUnknownConfig bool
 
 	sc.DataDir = c.Server.DataDir
 	sc.AuditLogEnabled = c.Server.AuditLogEnabled
+	if c.Server.AuditLog != nil {
+		if !c.Server.AuditLogEnabled {
+			return nil, errors.New("audit_log requires audit_log_enabled to be true")
+		}
+		auditLog, err := configToAuditLogConfig(c.Server.AuditLog, c.Server.DataDir)
+		if err != nil {
+			return nil, err
+		}
+		sc.AuditLog = auditLog
+	}
 	sc.ProxyProtocolTrustedCIDRs = c.Server.ProxyProtocolTrustedCIDRs
 
 	td, err := spiffeid.TrustDomainFromString(c.Server.TrustDomain)
@@ -490,6 +544,14 @@ func NewServerConfig(c *Config, logOptions []log.Option, allowUnknownConfig bool
 					return nil, err
 				}
 			}
+
+			if c.Server.Federation.BundleEndpoint.Hub != nil {
+				hub, err := parseBundleEndpointHub(c.Server.Federation.BundleEndpoint.Hub, sc.TrustDomain)
+				if err != nil {
+					return nil, fmt.Errorf("error parsing bundle endpoint hub: %w", err)
+				}
+				sc.Federation.BundleEndpoint.Hub = hub
+			}
 		}
 
 		federatesWith := map[spiffeid.TrustDomain]bundleClient.TrustDomainConfig{}
@@ -715,6 +777,27 @@ func NewServerConfig(c *Config, logOptions []log.Option, allowUnknownConfig bool
 		sc.PruneAttestedNodesBatchSize = c.Server.PruneAttestedNodesBatchSize
 	}
 
+	if c.Server.StaleAgentsNotSeenFor != "" {
+		notSeenFor, err := time.ParseDuration(c.Server.StaleAgentsNotSeenFor)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse stale_agents_not_seen_for: %w", err)
+		}
+		if notSeenFor < node.MinStaleAgentsNotSeenFor {
+			return nil, fmt.Errorf("stale_agents_not_seen_for must be at least %s", node.MinStaleAgentsNotSeenFor)
+		}
+		sc.StaleAgentsNotSeenFor = notSeenFor
+
+		sc.StaleAgentsAction = node.StaleAgentActionLog
+		if c.Server.StaleAgentsAction != "" {
+			sc.StaleAgentsAction, err = node.ParseStaleAgentAction(c.Server.StaleAgentsAction)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse stale_agents_action: %w", err)
+			}
+		}
+	} else if c.Server.StaleAgentsAction != "" {
+		sc.Log.Warn("stale_agents_action configurable is set but stale_agents_not_seen_for is not; it will be ignored")
+	}
+
 	if c.Server.DisableJWTSVIDs {
 		sc.Log.Info("JWT-SVID profile is disabled")
 	}
@@ -857,6 +940,35 @@ func configToACMEConfig(acme *bundleEndpointACMEConfig, dataDir string) *bundle.
 	}
 }
 
+func configToAuditLogConfig(c *auditLogConfig, dataDir string) (*auditlog.Config, error) {
+	if c.Path == "" {
+		return nil, errors.New("audit_log path must be configured")
+	}
+
+	config := &auditlog.Config{
+		Path:           c.Path,
+		MaxSize:        auditlog.DefaultMaxSize,
+		MaxBackups:     auditlog.DefaultMaxBackups,
+		SigningKeyPath: c.SigningKeyPath,
+	}
+	switch {
+	case c.MaxSizeMB < 0:
+		return nil, fmt.Errorf("audit_log max_size_mb must not be negative; got %d", c.MaxSizeMB)
+	case c.MaxSizeMB > 0:
+		config.MaxSize = c.MaxSizeMB * 1024 * 1024
+	}
+	if c.MaxBackups != nil {
+		if *c.MaxBackups < 0 {
+			return nil, fmt.Errorf("audit_log max_backups must not be negative; got %d", *c.MaxBackups)
+		}
+		config.MaxBackups = *c.MaxBackups
+	}
+	if config.SigningKeyPath == "" {
+		config.SigningKeyPath = filepath.Join(dataDir, "audit_log_key.pem")
+	}
+	return config, nil
+}
+
 func configToDiskCertManager(serviceCertFile *bundleEndpointServingCertFile, log logrus.FieldLogger) (*diskcertmanager.DiskCertManager, error) {
 	fileSyncInterval, err := time.ParseDuration(serviceCertFile.RawFileSyncInterval)
 	if err != nil {
@@ -900,16 +1012,58 @@ func parseBundleEndpointProfile(config federatesWithConfig) (trustDomainConfig *
 			return nil, fmt.Errorf("could not get endpoint SPIFFE ID: %w", err)
 		}
 		endpointProfile = bundleClient.HTTPSSPIFFEProfile{EndpointSPIFFEID: spiffeID}
+	case profileConfig.File != nil:
+		if profileConfig.File.TrustedSignerKeysPath == "" {
+			return nil, errors.New("trusted_signer_keys_path must be configured for the file profile")
+		}
+		if _, err := bundleClient.FilePathFromURL(config.BundleEndpointURL); err != nil {
+			return nil, fmt.Errorf("invalid bundle_endpoint_url for the file profile: %w", err)
+		}
+		endpointProfile = bundleClient.FileProfile{TrustedSignerKeysPath: profileConfig.File.TrustedSignerKeysPath}
 	default:
-		return nil, errors.New(`no bundle endpoint profile defined; current supported profiles are "https_spiffe" and 'https_web"`)
+		return nil, errors.New(`no bundle endpoint profile defined; current supported profiles are "https_spiffe", "https_web" and "file"`)
+	}
+
+	_, isFile := endpointProfile.(bundleClient.FileProfile)
+	if !isFile && isFileURL(config.BundleEndpointURL) {
+		return nil, fmt.Errorf("bundle_endpoint_url with the file scheme requires the file profile; profile found: %q", endpointProfile.Name())
+	}
+	if isFile && config.BundleEndpointHub {
+		return nil, errors.New("bundle_endpoint_hub cannot be used with the file profile")
 	}
 
 	return &bundleClient.TrustDomainConfig{
 		EndpointURL:     config.BundleEndpointURL,
 		EndpointProfile: endpointProfile,
+		Hub:             config.BundleEndpointHub,
 	}, nil
 }
 
+func parseBundleEndpointHub(config *bundleEndpointHubConfig, localTrustDomain spiffeid.TrustDomain) (*bundle.HubConfig, error) {
+	if len(config.TrustDomains) == 0 {
+		return nil, errors.New("trust_domains must be configured")
+	}
+
+	hub := new(bundle.HubConfig)
+	for _, trustDomain := range config.TrustDomains {
+		td, err := spiffeid.TrustDomainFromString(trustDomain)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trust domain %q: %w", trustDomain, err)
+		}
+		if td == localTrustDomain {
+			return nil, fmt.Errorf("the local trust domain %q cannot be re-published", trustDomain)
+		}
+		hub.TrustDomains = append(hub.TrustDomains, td)
+	}
+	return hub, nil
+}
+
+// isFileURL returns true if the bundle endpoint URL is a file URL, used by the
+// file profile to read bundles from the local filesystem.
+func isFileURL(bundleEndpointURL string) bool {
+	return strings.HasPrefix(strings.ToLower(bundleEndpointURL), "file://")
+}
+
 func parseBundleEndpointProfileASTNode(node ast.Node) (string, error) {
 	// First check the number of bundle endpoint profiles in the config
 	objectList, ok := node.(*ast.ObjectList)
@@ -966,7 +1120,7 @@ func validateConfig(c *Config) error {
 			switch {
 			case tdConfig.BundleEndpointURL == "":
 				return fmt.Errorf("federation.federates_with[\"%s\"].bundle_endpoint_url must be configured", td)
-			case !strings.HasPrefix(strings.ToLower(tdConfig.BundleEndpointURL), "https://"):
+			case !strings.HasPrefix(strings.ToLower(tdConfig.BundleEndpointURL), "https://") && !isFileURL(tdConfig.BundleEndpointURL):
 				return fmt.Errorf("federation.federates_with[\"%s\"].bundle_endpoint_url must use the HTTPS protocol; URL found: %q", td, tdConfig.BundleEndpointURL)
 			}
 		}
@@ -1013,6 +1167,10 @@ func checkForUnknownConfig(c *Config, l logrus.FieldLogger) (err error) {
 			detectedUnknown("ca_subject", cs.UnusedKeyPositions)
 		}
 
+		if al := c.Server.AuditLog; al != nil && len(al.UnusedKeyPositions) != 0 {
+			detectedUnknown("audit_log", al.UnusedKeyPositions)
+		}
+
 		if rl := c.Server.RateLimit; len(rl.UnusedKeyPositions) != 0 {
 			detectedUnknown("ratelimit", rl.UnusedKeyPositions)
 		}
@@ -1040,6 +1198,10 @@ func checkForUnknownConfig(c *Config, l logrus.FieldLogger) (err error) {
 				if bea := c.Server.Federation.BundleEndpoint.ACME; bea != nil && len(bea.UnusedKeyPositions) != 0 {
 					detectedUnknown("bundle endpoint ACME", bea.UnusedKeyPositions)
 				}
+
+				if beh := c.Server.Federation.BundleEndpoint.Hub; beh != nil && len(beh.UnusedKeyPositions) != 0 {
+					detectedUnknown("bundle endpoint hub", beh.UnusedKeyPositions)
+				}
 			}
 
 			// TODO: Re-enable unused key detection for bundle endpoint profile config. See
@@ -1086,6 +1248,14 @@ func checkForUnknownConfig(c *Config, l logrus.FieldLogger) (err error) {
 		detectedUnknown("InMem", p.UnusedKeyPositions)
 	}
 
+	if p := c.Telemetry.OTLP; p != nil && len(p.UnusedKeyPositions) != 0 {
+		detectedUnknown("OTLP", p.UnusedKeyPositions)
+	}
+
+	if p := c.Telemetry.Tracing; p != nil && len(p.UnusedKeyPositions) != 0 {
+		detectedUnknown("Tracing", p.UnusedKeyPositions)
+	}
+
 	if len(c.HealthChecks.UnusedKeyPositions) != 0 {
 		detectedUnknown("health check", c.HealthChecks.UnusedKeyPositions)
 	}
@@ -18,9 +18,11 @@ import (
 	"github.com/spiffe/spire/pkg/common/log"
 	"github.com/spiffe/spire/pkg/common/telemetry"
 	"github.com/spiffe/spire/pkg/server"
+	"github.com/spiffe/spire/pkg/server/auditlog"
 	bundleClient "github.com/spiffe/spire/pkg/server/bundle/client"
 	"github.com/spiffe/spire/pkg/server/credtemplate"
 	"github.com/spiffe/spire/pkg/server/endpoints/bundle"
+	"github.com/spiffe/spire/pkg/server/node"
 	"github.com/spiffe/spire/pkg/server/plugin/keymanager"
 	"github.com/spiffe/spire/test/spiretest"
 	"github.com/stretchr/testify/assert"
@@ -553,6 +555,95 @@ func TestNewServerConfig(t *testing.T) {
 				require.Equal(t, 0, c.PruneAttestedNodesBatchSize)
 			},
 		},
+		{
+			msg: "stale agent policy should be correctly parsed",
+			input: func(c *Config) {
+				c.Server.StaleAgentsNotSeenFor = "24h"
+				c.Server.StaleAgentsAction = "ban"
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Equal(t, 24*time.Hour, c.StaleAgentsNotSeenFor)
+				require.Equal(t, node.StaleAgentActionBan, c.StaleAgentsAction)
+			},
+		},
+		{
+			msg: "stale agent policy only logs by default",
+			input: func(c *Config) {
+				c.Server.StaleAgentsNotSeenFor = "24h"
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Equal(t, 24*time.Hour, c.StaleAgentsNotSeenFor)
+				require.Equal(t, node.StaleAgentActionLog, c.StaleAgentsAction)
+			},
+		},
+		{
+			msg: "stale agent policy evicts when configured to",
+			input: func(c *Config) {
+				c.Server.StaleAgentsNotSeenFor = "10m"
+				c.Server.StaleAgentsAction = "evict"
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Equal(t, 10*time.Minute, c.StaleAgentsNotSeenFor)
+				require.Equal(t, node.StaleAgentActionEvict, c.StaleAgentsAction)
+			},
+		},
+		{
+			msg:         "invalid stale_agents_not_seen_for should return an error",
+			expectError: true,
+			input: func(c *Config) {
+				c.Server.StaleAgentsNotSeenFor = "forever"
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Nil(t, c)
+			},
+		},
+		{
+			msg:         "non-positive stale_agents_not_seen_for should return an error",
+			expectError: true,
+			input: func(c *Config) {
+				c.Server.StaleAgentsNotSeenFor = "0s"
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Nil(t, c)
+			},
+		},
+		{
+			msg:         "stale_agents_not_seen_for under the minimum should return an error",
+			expectError: true,
+			input: func(c *Config) {
+				c.Server.StaleAgentsNotSeenFor = "9m59s"
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Nil(t, c)
+			},
+		},
+		{
+			msg:         "invalid stale_agents_action should return an error",
+			expectError: true,
+			input: func(c *Config) {
+				c.Server.StaleAgentsNotSeenFor = "24h"
+				c.Server.StaleAgentsAction = "purge"
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Nil(t, c)
+			},
+		},
+		{
+			msg: "stale_agents_action without stale_agents_not_seen_for is ignored",
+			input: func(c *Config) {
+				c.Server.StaleAgentsAction = "ban"
+			},
+			logOptions: assertLogsContainEntries([]spiretest.LogEntry{
+				{
+					Level:   logrus.WarnLevel,
+					Message: "stale_agents_action configurable is set but stale_agents_not_seen_for is not; it will be ignored",
+				},
+			}),
+			test: func(t *testing.T, c *server.Config) {
+				require.Zero(t, c.StaleAgentsNotSeenFor)
+				require.Empty(t, c.StaleAgentsAction)
+			},
+		},
 		{
 			msg: "bind_address and bind_port should be correctly parsed",
 			input: func(c *Config) {
@@ -896,6 +987,120 @@ func TestNewServerConfig(t *testing.T) {
 				require.Equal(t, 5*time.Minute, c.Federation.BundleEndpoint.RefreshHint)
 			},
 		},
+		{
+			msg: "bundle endpoint hub is parsed and configured correctly",
+			input: func(c *Config) {
+				c.Server.Federation = &federationConfig{
+					BundleEndpoint: &bundleEndpointConfig{
+						Address: "192.168.1.1",
+						Port:    1337,
+						Hub: &bundleEndpointHubConfig{
+							TrustDomains: []string{"domain1.test", "domain2.test"},
+						},
+					},
+				}
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Equal(t, &bundle.HubConfig{
+					TrustDomains: []spiffeid.TrustDomain{
+						spiffeid.RequireTrustDomainFromString("domain1.test"),
+						spiffeid.RequireTrustDomainFromString("domain2.test"),
+					},
+				}, c.Federation.BundleEndpoint.Hub)
+			},
+		},
+		{
+			msg:         "bundle endpoint hub requires trust domains",
+			expectError: true,
+			input: func(c *Config) {
+				c.Server.Federation = &federationConfig{
+					BundleEndpoint: &bundleEndpointConfig{
+						Address: "192.168.1.1",
+						Port:    1337,
+						Hub:     &bundleEndpointHubConfig{},
+					},
+				}
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Nil(t, c)
+			},
+		},
+		{
+			msg:         "bundle endpoint hub does not re-publish the local trust domain",
+			expectError: true,
+			input: func(c *Config) {
+				c.Server.TrustDomain = "example.org"
+				c.Server.Federation = &federationConfig{
+					BundleEndpoint: &bundleEndpointConfig{
+						Address: "192.168.1.1",
+						Port:    1337,
+						Hub: &bundleEndpointHubConfig{
+							TrustDomains: []string{"domain1.test", "example.org"},
+						},
+					},
+				}
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Nil(t, c)
+			},
+		},
+		{
+			msg:         "bundle endpoint hub requires valid trust domains",
+			expectError: true,
+			input: func(c *Config) {
+				c.Server.Federation = &federationConfig{
+					BundleEndpoint: &bundleEndpointConfig{
+						Address: "192.168.1.1",
+						Port:    1337,
+						Hub: &bundleEndpointHubConfig{
+							TrustDomains: []string{"Domain1.test"},
+						},
+					},
+				}
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Nil(t, c)
+			},
+		},
+		{
+			msg: "bundle federates with hub is parsed and configured correctly",
+			input: func(c *Config) {
+				c.Server.Federation = &federationConfig{
+					FederatesWith: map[string]federatesWithConfig{
+						"domain1.test": federatesWithConfigTest(t, `bundle_endpoint_url = "https://hub.test/federated"
+						bundle_endpoint_hub = true
+						bundle_endpoint_profile "https_web" {}`),
+					},
+				}
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Equal(t, map[spiffeid.TrustDomain]bundleClient.TrustDomainConfig{
+					spiffeid.RequireTrustDomainFromString("domain1.test"): {
+						EndpointURL:     "https://hub.test/federated",
+						EndpointProfile: bundleClient.HTTPSWebProfile{},
+						Hub:             true,
+					},
+				}, c.Federation.FederatesWith)
+			},
+		},
+		{
+			msg:         "bundle federates with hub cannot use the file profile",
+			expectError: true,
+			input: func(c *Config) {
+				c.Server.Federation = &federationConfig{
+					FederatesWith: map[string]federatesWithConfig{
+						"domain1.test": federatesWithConfigTest(t, `bundle_endpoint_url = "file:///var/lib/spire/bundles"
+						bundle_endpoint_hub = true
+						bundle_endpoint_profile "file" {
+							trusted_signer_keys_path = "/etc/spire/signers.pem"
+						}`),
+					},
+				}
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Nil(t, c)
+			},
+		},
 		{
 			msg: "bundle federates with section is parsed and configured correctly",
 			input: func(c *Config) {
@@ -921,6 +1126,76 @@ func TestNewServerConfig(t *testing.T) {
 				}, c.Federation.FederatesWith)
 			},
 		},
+		{
+			msg: "bundle federates with file profile is parsed and configured correctly",
+			input: func(c *Config) {
+				c.Server.Federation = &federationConfig{
+					FederatesWith: map[string]federatesWithConfig{
+						"domain1.test": federatesWithConfigTest(t, `bundle_endpoint_url = "file:///var/lib/spire/bundles"
+						bundle_endpoint_profile "file" {
+							trusted_signer_keys_path = "/etc/spire/signers.pem"
+						}`),
+					},
+				}
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Equal(t, map[spiffeid.TrustDomain]bundleClient.TrustDomainConfig{
+					spiffeid.RequireTrustDomainFromString("domain1.test"): {
+						EndpointURL: "file:///var/lib/spire/bundles",
+						EndpointProfile: bundleClient.FileProfile{
+							TrustedSignerKeysPath: "/etc/spire/signers.pem",
+						},
+					},
+				}, c.Federation.FederatesWith)
+			},
+		},
+		{
+			msg:         "bundle federates with file profile requires trusted signer keys",
+			expectError: true,
+			input: func(c *Config) {
+				c.Server.Federation = &federationConfig{
+					FederatesWith: map[string]federatesWithConfig{
+						"domain1.test": federatesWithConfigTest(t, `bundle_endpoint_url = "file:///var/lib/spire/bundles"
+						bundle_endpoint_profile "file" {}`),
+					},
+				}
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Nil(t, c)
+			},
+		},
+		{
+			msg:         "bundle federates with file profile requires a file URL",
+			expectError: true,
+			input: func(c *Config) {
+				c.Server.Federation = &federationConfig{
+					FederatesWith: map[string]federatesWithConfig{
+						"domain1.test": federatesWithConfigTest(t, `bundle_endpoint_url = "https://192.168.1.1:1337"
+						bundle_endpoint_profile "file" {
+							trusted_signer_keys_path = "/etc/spire/signers.pem"
+						}`),
+					},
+				}
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Nil(t, c)
+			},
+		},
+		{
+			msg:         "bundle federates with file URL requires the file profile",
+			expectError: true,
+			input: func(c *Config) {
+				c.Server.Federation = &federationConfig{
+					FederatesWith: map[string]federatesWithConfig{
+						"domain1.test": federatesWithConfigTest(t, `bundle_endpoint_url = "file:///var/lib/spire/bundles"
+						bundle_endpoint_profile "https_web" {}`),
+					},
+				}
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Nil(t, c)
+			},
+		},
 		{
 			msg: "default_x509_svid_ttl is correctly parsed",
 			input: func(c *Config) {
@@ -1320,6 +1595,89 @@ func TestNewServerConfig(t *testing.T) {
 				require.False(t, c.AuditLogEnabled)
 			},
 		},
+		{
+			msg: "audit_log is not configured by default",
+			input: func(c *Config) {
+				c.Server.AuditLogEnabled = true
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Nil(t, c.AuditLog)
+			},
+		},
+		{
+			msg: "audit_log uses defaults",
+			input: func(c *Config) {
+				c.Server.AuditLogEnabled = true
+				c.Server.AuditLog = &auditLogConfig{
+					Path: "/var/log/spire/audit.log",
+				}
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Equal(t, &auditlog.Config{
+					Path:           "/var/log/spire/audit.log",
+					MaxSize:        auditlog.DefaultMaxSize,
+					MaxBackups:     auditlog.DefaultMaxBackups,
+					SigningKeyPath: "audit_log_key.pem",
+				}, c.AuditLog)
+			},
+		},
+		{
+			msg: "audit_log is configurable",
+			input: func(c *Config) {
+				c.Server.AuditLogEnabled = true
+				c.Server.AuditLog = &auditLogConfig{
+					Path:           "/var/log/spire/audit.log",
+					MaxSizeMB:      5,
+					MaxBackups:     new(0),
+					SigningKeyPath: "/etc/spire/audit_key.pem",
+				}
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Equal(t, &auditlog.Config{
+					Path:           "/var/log/spire/audit.log",
+					MaxSize:        5 * 1024 * 1024,
+					MaxBackups:     0,
+					SigningKeyPath: "/etc/spire/audit_key.pem",
+				}, c.AuditLog)
+			},
+		},
+		{
+			msg:         "audit_log requires audit_log_enabled",
+			expectError: true,
+			input: func(c *Config) {
+				c.Server.AuditLog = &auditLogConfig{
+					Path: "/var/log/spire/audit.log",
+				}
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Nil(t, c)
+			},
+		},
+		{
+			msg:         "audit_log requires a path",
+			expectError: true,
+			input: func(c *Config) {
+				c.Server.AuditLogEnabled = true
+				c.Server.AuditLog = &auditLogConfig{}
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Nil(t, c)
+			},
+		},
+		{
+			msg:         "audit_log max_backups must not be negative",
+			expectError: true,
+			input: func(c *Config) {
+				c.Server.AuditLogEnabled = true
+				c.Server.AuditLog = &auditLogConfig{
+					Path:       "/var/log/spire/audit.log",
+					MaxBackups: new(-1),
+				}
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Nil(t, c)
+			},
+		},
 		{
 			msg: "proxy_protocol_trusted_cidrs is set",
 			input: func(c *Config) {
@@ -1506,6 +1864,18 @@ func TestValidateConfig(t *testing.T) {
 			},
 			expectedErr: `federation.federates_with["domain.test"].bundle_endpoint_url must use the HTTPS protocol; URL found: "http://example.org/test"`,
 		},
+		{
+			name: "bundle_endpoint_url can use the file scheme",
+			applyConf: func(c *Config) {
+				federatesWith := make(map[string]federatesWithConfig)
+				federatesWith["domain.test"] = federatesWithConfig{
+					BundleEndpointURL: "file:///var/lib/spire/bundles",
+				}
+				c.Server.Federation = &federationConfig{
+					FederatesWith: federatesWith,
+				}
+			},
+		},
 		{
 			name: "can't set both sql_transaction_timeout and event_timeout",
 			applyConf: func(c *Config) {
@@ -1574,6 +1944,16 @@ func TestWarnOnUnknownConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			msg:      "in audit_log block",
+			confFile: "server_bad_audit_log_block.conf",
+			expectedLogEntries: []logEntry{
+				{
+					section: "audit_log",
+					keys:    "unknown_option1,unknown_option2",
+				},
+			},
+		},
 		{
 			msg:      "in ratelimit block",
 			confFile: "server_bad_ratelimit_block.conf",
@@ -1627,6 +2007,16 @@ func TestWarnOnUnknownConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			msg:      "in nested bundle_endpoint.hub block",
+			confFile: "server_bad_nested_bundle_endpoint_hub_block.conf",
+			expectedLogEntries: []logEntry{
+				{
+					section: "bundle endpoint hub",
+					keys:    "unknown_option1,unknown_option2",
+				},
+			},
+		},
 		// TODO: Re-enable unused key detection for experimental config. See
 		// https://github.com/spiffe/spire/issues/1101 for more information
 		//
@@ -1716,6 +2106,26 @@ func TestWarnOnUnknownConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			msg:      "in nested OTLP block",
+			confFile: "server_and_agent_bad_nested_OTLP_block.conf",
+			expectedLogEntries: []logEntry{
+				{
+					section: "OTLP",
+					keys:    "unknown_option1,unknown_option2",
+				},
+			},
+		},
+		{
+			msg:      "in nested Tracing block",
+			confFile: "server_and_agent_bad_nested_Tracing_block.conf",
+			expectedLogEntries: []logEntry{
+				{
+					section: "Tracing",
+					keys:    "unknown_option1,unknown_option2",
+				},
+			},
+		},
 		{
 			msg:      "in nested health_checks block",
 			confFile: "server_and_agent_bad_nested_health_checks_block.conf",
@@ -2219,6 +2629,12 @@ func httpsSPIFFEConfigTest(t *testing.T) federatesWithConfig {
 	return *httpsSPIFFEConfig
 }
 
+func federatesWithConfigTest(t *testing.T, configString string) federatesWithConfig {
+	config := new(federatesWithConfig)
+	require.NoError(t, hcl.Decode(config, configString))
+	return *config
+}
+
 func webPKIConfigTest(t *testing.T) federatesWithConfig {
 	configString := `bundle_endpoint_url = "https://192.168.1.1:1337"
 		bundle_endpoint_profile "https_web" {}`
@@ -0,0 +1,117 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/mitchellh/cli"
+	serverutil "github.com/spiffe/spire/cmd/spire-server/util"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/cliprinter"
+	"github.com/spiffe/spire/pkg/common/util"
+	"github.com/spiffe/spire/proto/private/server/jointoken"
+	"github.com/spiffe/spire/proto/spire/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewCreateCommand creates a new "token create" subcommand.
+func NewCreateCommand() cli.Command {
+	return newCreateCommand(commoncli.DefaultEnv)
+}
+
+func newCreateCommand(env *commoncli.Env) cli.Command {
+	return serverutil.AdaptCommand(env, &createCommand{env: env})
+}
+
+type createCommand struct {
+	token             string
+	ttl               int
+	maxUses           int
+	selectors         commoncli.StringsFlag
+	agentPathTemplate string
+	env               *commoncli.Env
+	printer           cliprinter.Printer
+}
+
+func (c *createCommand) Name() string {
+	return "token create"
+}
+
+func (c *createCommand) Synopsis() string {
+	return "Creates a join token that can be used by multiple agents"
+}
+
+func (c *createCommand) AppendFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.token, "token", "", "Token value. Defaults to a random UUID")
+	fs.IntVar(&c.ttl, "ttl", 600, "Token TTL in seconds")
+	fs.IntVar(&c.maxUses, "maxUses", 1, "Number of agents that can be attested with the token. Zero means the token can be used until it expires")
+	fs.Var(&c.selectors, "selector", "A colon-delimited type:value selector added to the agents attested with the token. Can be used more than once")
+	fs.StringVar(&c.agentPathTemplate, "agentPathTemplate", "", "Template used to build the SPIFFE ID path of the agents attested with the token, under /spire/agent. Defaults to /join_token/{{ .UUID }} for tokens that can be used more than once")
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, prettyPrintCreate)
+}
+
+func (c *createCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient serverutil.ServerClient) error {
+	req, err := c.makeRequest()
+	if err != nil {
+		return err
+	}
+
+	resp, err := serverClient.NewJoinTokenClient().CreateJoinToken(ctx, req)
+	switch status.Code(err) {
+	case codes.OK:
+		return c.printer.PrintProto(resp)
+	case codes.InvalidArgument:
+		return fmt.Errorf("failed to create token: %s", status.Convert(err).Message())
+	default:
+		return fmt.Errorf("failed to create token: %w", err)
+	}
+}
+
+func (c *createCommand) makeRequest() (*jointoken.CreateJoinTokenRequest, error) {
+	if c.ttl < 1 {
+		return nil, errors.New("ttl is required, you must provide one")
+	}
+	ttl, err := util.CheckedCast[int32](c.ttl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for ttl: %w", err)
+	}
+
+	// The API takes a negative value for tokens without a usage limit
+	maxUses, err := util.CheckedCast[int32](c.maxUses)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("invalid value for maxUses: %w", err)
+	case maxUses < 0:
+		return nil, errors.New("maxUses cannot be negative")
+	case maxUses == 0:
+		maxUses = -1
+	}
+
+	var selectors []*common.Selector
+	for _, s := range c.selectors {
+		parsed, err := serverutil.ParseSelector(s)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, &common.Selector{Type: parsed.Type, Value: parsed.Value})
+	}
+
+	return &jointoken.CreateJoinTokenRequest{
+		Token:             c.token,
+		Ttl:               ttl,
+		MaxUses:           maxUses,
+		Selectors:         selectors,
+		AgentPathTemplate: c.agentPathTemplate,
+	}, nil
+}
+
+func prettyPrintCreate(env *commoncli.Env, results ...any) error {
+	token, ok := results[0].(*jointoken.Token)
+	if !ok {
+		return cliprinter.ErrInternalCustomPrettyFunc
+	}
+	return env.Printf("Token: %s\n", token.Value)
+}
@@ -0,0 +1,114 @@
+package token
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mitchellh/cli"
+	serverutil "github.com/spiffe/spire/cmd/spire-server/util"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/cliprinter"
+	"github.com/spiffe/spire/proto/private/server/jointoken"
+)
+
+// NewListCommand creates a new "token list" subcommand.
+func NewListCommand() cli.Command {
+	return newListCommand(commoncli.DefaultEnv)
+}
+
+func newListCommand(env *commoncli.Env) cli.Command {
+	return serverutil.AdaptCommand(env, &listCommand{env: env})
+}
+
+type listCommand struct {
+	env     *commoncli.Env
+	printer cliprinter.Printer
+}
+
+func (c *listCommand) Name() string {
+	return "token list"
+}
+
+func (c *listCommand) Synopsis() string {
+	return "Lists the join tokens that have not been used up"
+}
+
+func (c *listCommand) AppendFlags(fs *flag.FlagSet) {
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, prettyPrintList)
+}
+
+func (c *listCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient serverutil.ServerClient) error {
+	client := serverClient.NewJoinTokenClient()
+
+	resp := &jointoken.ListJoinTokensResponse{}
+	pageToken := ""
+	for {
+		listResp, err := client.ListJoinTokens(ctx, &jointoken.ListJoinTokensRequest{
+			PageSize:  1000,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list tokens: %w", err)
+		}
+		resp.Tokens = append(resp.Tokens, listResp.Tokens...)
+		if pageToken = listResp.NextPageToken; pageToken == "" {
+			break
+		}
+	}
+
+	return c.printer.PrintProto(resp)
+}
+
+func prettyPrintList(env *commoncli.Env, results ...any) error {
+	resp, ok := results[0].(*jointoken.ListJoinTokensResponse)
+	if !ok {
+		return cliprinter.ErrInternalCustomPrettyFunc
+	}
+
+	if len(resp.Tokens) == 0 {
+		return env.Printf("No join tokens found\n")
+	}
+
+	msg := fmt.Sprintf("Found %d ", len(resp.Tokens))
+	msg = serverutil.Pluralizer(msg, "join token", "join tokens", len(resp.Tokens))
+	if err := env.Printf("%s:\n\n", msg); err != nil {
+		return err
+	}
+
+	for _, token := range resp.Tokens {
+		if err := env.Printf("Token               : %s\n", token.Value); err != nil {
+			return err
+		}
+		if err := env.Printf("Expiration time     : %s\n", time.Unix(token.ExpiresAt, 0).UTC().Format(time.RFC3339)); err != nil {
+			return err
+		}
+		if err := env.Printf("Uses                : %s\n", formatUses(token)); err != nil {
+			return err
+		}
+		for _, s := range token.Selectors {
+			if err := env.Printf("Selector            : %s:%s\n", s.Type, s.Value); err != nil {
+				return err
+			}
+		}
+		if token.AgentPathTemplate != "" {
+			if err := env.Printf("Agent path template : %s\n", token.AgentPathTemplate); err != nil {
+				return err
+			}
+		}
+		if err := env.Println(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatUses(token *jointoken.Token) string {
+	maxUses := "unlimited"
+	if token.MaxUses >= 0 {
+		maxUses = strconv.Itoa(int(max(token.MaxUses, 1)))
+	}
+	return fmt.Sprintf("%d/%s", token.UseCount, maxUses)
+}
@@ -0,0 +1,67 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/mitchellh/cli"
+	serverutil "github.com/spiffe/spire/cmd/spire-server/util"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/cliprinter"
+	"github.com/spiffe/spire/proto/private/server/jointoken"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewRevokeCommand creates a new "token revoke" subcommand.
+func NewRevokeCommand() cli.Command {
+	return newRevokeCommand(commoncli.DefaultEnv)
+}
+
+func newRevokeCommand(env *commoncli.Env) cli.Command {
+	return serverutil.AdaptCommand(env, &revokeCommand{env: env})
+}
+
+type revokeCommand struct {
+	token   string
+	env     *commoncli.Env
+	printer cliprinter.Printer
+}
+
+func (c *revokeCommand) Name() string {
+	return "token revoke"
+}
+
+func (c *revokeCommand) Synopsis() string {
+	return "Revokes a join token so it cannot be used to attest more agents"
+}
+
+func (c *revokeCommand) AppendFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.token, "token", "", "The token to revoke")
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, prettyPrintRevoke)
+}
+
+func (c *revokeCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient serverutil.ServerClient) error {
+	if c.token == "" {
+		return errors.New("a token is required")
+	}
+
+	// Agents already attested with the token are not affected
+	resp, err := serverClient.NewJoinTokenClient().RevokeJoinToken(ctx, &jointoken.RevokeJoinTokenRequest{
+		Token: c.token,
+	})
+	switch status.Code(err) {
+	case codes.OK:
+		return c.printer.PrintProto(resp)
+	case codes.NotFound:
+		return errors.New("token does not exist or has already been used")
+	default:
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+}
+
+func prettyPrintRevoke(env *commoncli.Env, _ ...any) error {
+	return env.Println("Token revoked successfully")
+}
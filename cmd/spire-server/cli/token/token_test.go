@@ -0,0 +1,298 @@
+package token
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/cli"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/proto/private/server/jointoken"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/spiffe/spire/test/clitest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestJoinTokenCommandsSynopsis(t *testing.T) {
+	require.Equal(t, "Creates a join token that can be used by multiple agents", NewCreateCommand().Synopsis())
+	require.Equal(t, "Lists the join tokens that have not been used up", NewListCommand().Synopsis())
+	require.Equal(t, "Revokes a join token so it cannot be used to attest more agents", NewRevokeCommand().Synopsis())
+}
+
+func TestJoinTokenCommandsHelp(t *testing.T) {
+	test := setupJoinTokenTest(t, newCreateCommand)
+	test.client.Help()
+	require.Contains(t, test.stderr.String(), "Usage of token create:")
+	require.Contains(t, test.stderr.String(), "-maxUses")
+	require.Contains(t, test.stderr.String(), "-agentPathTemplate")
+
+	test = setupJoinTokenTest(t, newListCommand)
+	test.client.Help()
+	require.Contains(t, test.stderr.String(), "Usage of token list:")
+	require.NotContains(t, test.stderr.String(), "-config")
+
+	test = setupJoinTokenTest(t, newRevokeCommand)
+	test.client.Help()
+	require.Contains(t, test.stderr.String(), "Usage of token revoke:")
+	require.Contains(t, test.stderr.String(), "-token")
+}
+
+func TestCreate(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		args         []string
+		serverErr    error
+		expectReq    *jointoken.CreateJoinTokenRequest
+		expectStdout string
+		expectStderr string
+	}{
+		{
+			name: "success",
+			args: []string{
+				"-token", "ci-runners",
+				"-ttl", "3600",
+				"-maxUses", "10",
+				"-selector", "ci:pool:runners",
+				"-selector", "ci:ephemeral",
+				"-agentPathTemplate", "/join_token/ci/{{ .UseNumber }}",
+			},
+			expectReq: &jointoken.CreateJoinTokenRequest{
+				Token:   "ci-runners",
+				Ttl:     3600,
+				MaxUses: 10,
+				Selectors: []*common.Selector{
+					{Type: "ci", Value: "pool:runners"},
+					{Type: "ci", Value: "ephemeral"},
+				},
+				AgentPathTemplate: "/join_token/ci/{{ .UseNumber }}",
+			},
+			expectStdout: "Token: ci-runners\n",
+		},
+		{
+			name:         "unlimited uses",
+			args:         []string{"-token", "unlimited", "-maxUses", "0"},
+			expectReq:    &jointoken.CreateJoinTokenRequest{Token: "unlimited", Ttl: 600, MaxUses: -1},
+			expectStdout: "Token: unlimited\n",
+		},
+		{
+			name:         "no ttl",
+			args:         []string{"-ttl", "0"},
+			expectStderr: "Error: ttl is required, you must provide one\n",
+		},
+		{
+			name:         "negative max uses",
+			args:         []string{"-maxUses", "-1"},
+			expectStderr: "Error: maxUses cannot be negative\n",
+		},
+		{
+			name:         "malformed selector",
+			args:         []string{"-selector", "ci"},
+			expectStderr: "Error: selector \"ci\" must be formatted as type:value\n",
+		},
+		{
+			name:         "rejected by the server",
+			args:         []string{"-token", "token", "-agentPathTemplate", "/{{ .UseNumber"},
+			expectReq:    &jointoken.CreateJoinTokenRequest{Token: "token", Ttl: 600, MaxUses: 1, AgentPathTemplate: "/{{ .UseNumber"},
+			serverErr:    status.Error(codes.InvalidArgument, "invalid agent path template: template: agent-path:1: unclosed action"),
+			expectStderr: "Error: failed to create token: invalid agent path template: template: agent-path:1: unclosed action\n",
+		},
+		{
+			name:         "server error",
+			args:         []string{"-token", "token"},
+			expectReq:    &jointoken.CreateJoinTokenRequest{Token: "token", Ttl: 600, MaxUses: 1},
+			serverErr:    status.Error(codes.Internal, "oh no"),
+			expectStderr: "Error: failed to create token: rpc error: code = Internal desc = oh no\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			test := setupJoinTokenTest(t, newCreateCommand)
+			test.server.err = tt.serverErr
+
+			rc := test.client.Run(test.args(tt.args...))
+			spiretest.RequireProtoEqual(t, tt.expectReq, test.server.createReq)
+			if tt.expectStderr != "" {
+				require.Equal(t, 1, rc)
+				require.Equal(t, tt.expectStderr, test.stderr.String())
+				return
+			}
+			require.Equal(t, 0, rc, test.stderr.String())
+			require.Equal(t, tt.expectStdout, test.stdout.String())
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	expiresAt := time.Date(2026, 1, 2, 4, 4, 5, 0, time.UTC).Unix()
+
+	test := setupJoinTokenTest(t, newListCommand)
+	require.Equal(t, 0, test.client.Run(test.args()), test.stderr.String())
+	require.Equal(t, "No join tokens found\n", test.stdout.String())
+
+	test = setupJoinTokenTest(t, newListCommand)
+	test.server.tokens = []*jointoken.Token{
+		{
+			Value:     "ci-runners",
+			ExpiresAt: expiresAt,
+			MaxUses:   10,
+			UseCount:  2,
+			Selectors: []*common.Selector{
+				{Type: "ci", Value: "pool:runners"},
+				{Type: "ci", Value: "ephemeral"},
+			},
+			AgentPathTemplate: "/join_token/ci/{{ .UseNumber }}",
+		},
+		{
+			Value:     "unlimited",
+			ExpiresAt: expiresAt,
+			MaxUses:   -1,
+		},
+		{
+			Value:     "once",
+			ExpiresAt: expiresAt,
+		},
+	}
+	require.Equal(t, 0, test.client.Run(test.args()), test.stderr.String())
+	require.Equal(t, `Found 3 join tokens:
+
+Token               : ci-runners
+Expiration time     : 2026-01-02T04:04:05Z
+Uses                : 2/10
+Selector            : ci:pool:runners
+Selector            : ci:ephemeral
+Agent path template : /join_token/ci/{{ .UseNumber }}
+
+Token               : unlimited
+Expiration time     : 2026-01-02T04:04:05Z
+Uses                : 0/unlimited
+
+Token               : once
+Expiration time     : 2026-01-02T04:04:05Z
+Uses                : 0/1
+
+`, test.stdout.String())
+	// The fake server returns a single token per page
+	require.Equal(t, 3, test.server.listCalls)
+
+	test = setupJoinTokenTest(t, newListCommand)
+	test.server.err = status.Error(codes.Internal, "oh no")
+	require.Equal(t, 1, test.client.Run(test.args()))
+	require.Equal(t, "Error: failed to list tokens: rpc error: code = Internal desc = oh no\n", test.stderr.String())
+}
+
+func TestRevoke(t *testing.T) {
+	test := setupJoinTokenTest(t, newRevokeCommand)
+	require.Equal(t, 1, test.client.Run(test.args()))
+	require.Equal(t, "Error: a token is required\n", test.stderr.String())
+
+	test = setupJoinTokenTest(t, newRevokeCommand)
+	test.server.tokens = []*jointoken.Token{{Value: "ci-runners"}}
+	require.Equal(t, 0, test.client.Run(test.args("-token", "ci-runners")), test.stderr.String())
+	require.Equal(t, "Token revoked successfully\n", test.stdout.String())
+	require.Empty(t, test.server.tokens)
+
+	test = setupJoinTokenTest(t, newRevokeCommand)
+	require.Equal(t, 1, test.client.Run(test.args("-token", "ci-runners")))
+	require.Equal(t, "Error: token does not exist or has already been used\n", test.stderr.String())
+
+	test = setupJoinTokenTest(t, newRevokeCommand)
+	test.server.err = status.Error(codes.Internal, "oh no")
+	require.Equal(t, 1, test.client.Run(test.args("-token", "ci-runners")))
+	require.Equal(t, "Error: failed to revoke token: rpc error: code = Internal desc = oh no\n", test.stderr.String())
+}
+
+type joinTokenTest struct {
+	stdout *bytes.Buffer
+	stderr *bytes.Buffer
+
+	addr   string
+	server *fakeJoinTokenServer
+
+	client cli.Command
+}
+
+func (t *joinTokenTest) args(extra ...string) []string {
+	return append([]string{clitest.AddrArg, t.addr}, extra...)
+}
+
+func setupJoinTokenTest(t *testing.T, newClient func(*commoncli.Env) cli.Command) *joinTokenTest {
+	server := &fakeJoinTokenServer{}
+
+	addr := spiretest.StartGRPCServer(t, func(s *grpc.Server) {
+		jointoken.RegisterJoinTokenServer(s, server)
+	})
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	client := newClient(&commoncli.Env{
+		Stdin:  new(bytes.Buffer),
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+
+	return &joinTokenTest{
+		addr:   clitest.GetAddr(addr),
+		stdout: stdout,
+		stderr: stderr,
+		server: server,
+		client: client,
+	}
+}
+
+type fakeJoinTokenServer struct {
+	jointoken.UnimplementedJoinTokenServer
+
+	err       error
+	tokens    []*jointoken.Token
+	createReq *jointoken.CreateJoinTokenRequest
+	listCalls int
+}
+
+func (f *fakeJoinTokenServer) CreateJoinToken(_ context.Context, req *jointoken.CreateJoinTokenRequest) (*jointoken.Token, error) {
+	f.createReq = req
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &jointoken.Token{Value: req.Token}, nil
+}
+
+func (f *fakeJoinTokenServer) ListJoinTokens(_ context.Context, req *jointoken.ListJoinTokensRequest) (*jointoken.ListJoinTokensResponse, error) {
+	f.listCalls++
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	// Return one token per page to exercise the pagination
+	var start int
+	if req.PageToken != "" {
+		if _, err := fmt.Sscan(req.PageToken, &start); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page token")
+		}
+	}
+	resp := &jointoken.ListJoinTokensResponse{}
+	if start < len(f.tokens) {
+		resp.Tokens = f.tokens[start : start+1]
+		if start+1 < len(f.tokens) {
+			resp.NextPageToken = fmt.Sprint(start + 1)
+		}
+	}
+	return resp, nil
+}
+
+func (f *fakeJoinTokenServer) RevokeJoinToken(_ context.Context, req *jointoken.RevokeJoinTokenRequest) (*jointoken.RevokeJoinTokenResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	for i, token := range f.tokens {
+		if token.Value == req.Token {
+			f.tokens = append(f.tokens[:i], f.tokens[i+1:]...)
+			return &jointoken.RevokeJoinTokenResponse{}, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "token does not exist or has already been used")
+}
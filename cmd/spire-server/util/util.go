@@ -21,6 +21,9 @@ import (
 	common_cli "github.com/spiffe/spire/pkg/common/cli"
 	"github.com/spiffe/spire/pkg/common/jwtutil"
 	"github.com/spiffe/spire/pkg/common/pemutil"
+	agentstatusv1 "github.com/spiffe/spire/proto/private/server/agentstatus"
+	federationv1 "github.com/spiffe/spire/proto/private/server/federation"
+	jointokenv1 "github.com/spiffe/spire/proto/private/server/jointoken"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/health/grpc_health_v1"
@@ -52,6 +55,9 @@ type ServerClient interface {
 	NewTrustDomainClient() trustdomainv1.TrustDomainClient
 	NewLocalAuthorityClient() localauthorityv1.LocalAuthorityClient
 	NewHealthClient() grpc_health_v1.HealthClient
+	NewFederationClient() federationv1.FederationClient
+	NewJoinTokenClient() jointokenv1.JoinTokenClient
+	NewAgentStatusClient() agentstatusv1.AgentStatusClient
 }
 
 func NewServerClient(addr string) (ServerClient, error) {
@@ -106,6 +112,18 @@ func (c *serverClient) NewLocalAuthorityClient() localauthorityv1.LocalAuthority
 	return localauthorityv1.NewLocalAuthorityClient(c.conn)
 }
 
+func (c *serverClient) NewFederationClient() federationv1.FederationClient {
+	return federationv1.NewFederationClient(c.conn)
+}
+
+func (c *serverClient) NewJoinTokenClient() jointokenv1.JoinTokenClient {
+	return jointokenv1.NewJoinTokenClient(c.conn)
+}
+
+func (c *serverClient) NewAgentStatusClient() agentstatusv1.AgentStatusClient {
+	return agentstatusv1.NewAgentStatusClient(c.conn)
+}
+
 // Pluralizer concatenates `singular` to `msg` when `val` is one, and
 // `plural` on all other occasions. It is meant to facilitate friendlier
 // CLI output.
@@ -21,6 +21,7 @@ import (
 	workload_attestor "github.com/spiffe/spire/pkg/agent/attestor/workload"
 	"github.com/spiffe/spire/pkg/agent/broker"
 	"github.com/spiffe/spire/pkg/agent/catalog"
+	"github.com/spiffe/spire/pkg/agent/client"
 	"github.com/spiffe/spire/pkg/agent/endpoints"
 	"github.com/spiffe/spire/pkg/agent/manager"
 	"github.com/spiffe/spire/pkg/agent/manager/storecache"
@@ -39,6 +40,7 @@ import (
 	"github.com/spiffe/spire/pkg/common/util"
 	"github.com/spiffe/spire/pkg/common/version"
 	"github.com/spiffe/spire/pkg/common/x509util"
+	"github.com/spiffe/spire/proto/spire/common"
 	_ "golang.org/x/net/trace" // registers handlers on the DefaultServeMux
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -55,6 +57,9 @@ const (
 type Agent struct {
 	c       *Config
 	started bool
+
+	// servers selects the SPIRE Server the agent talks to
+	servers *client.ServerPool
 }
 
 // Run the agent
@@ -154,6 +159,8 @@ func (a *Agent) Run(ctx context.Context) error {
 	telemetry.EmitStarted(metrics, a.c.TrustDomain)
 	uptime.ReportMetrics(ctx, metrics)
 
+	a.servers = a.newServerPool(metrics)
+
 	cat, err := catalog.Load(ctx, catalog.Config{
 		Log:           a.c.Log.WithField(telemetry.SubsystemName, telemetry.Catalog),
 		Metrics:       metrics,
@@ -181,6 +188,14 @@ func (a *Agent) Run(ctx context.Context) error {
 	// signal handler, since MetricsImpl.ListenAndServe drives every sink runner.
 	taskRunner.StartTasks(metrics.ListenAndServe)
 
+	tracing, err := telemetry.NewTracing(ctx, a.c.Telemetry.Tracing,
+		a.c.Log.WithField(telemetry.SubsystemName, telemetry.Telemetry),
+		telemetry.SpireAgent, a.c.TrustDomain.Name())
+	if err != nil {
+		return err
+	}
+	taskRunner.StartTasks(tracing.Run)
+
 	nodeAttestor := nodeattestor.JoinToken(a.c.Log, a.c.JoinToken)
 	if a.c.JoinToken == "" {
 		nodeAttestor = cat.GetNodeAttestor()
@@ -194,6 +209,7 @@ func (a *Agent) Run(ctx context.Context) error {
 	}()
 
 	a.c.TrustBundleSources.SetMetrics(metrics)
+	a.c.TrustBundleSources.SetServerSelector(a.servers)
 	err = a.c.TrustBundleSources.SetStorage(sto)
 	if err != nil {
 		return err
@@ -438,6 +454,7 @@ func (a *Agent) attest(ctx context.Context, sto storage.Storage, cat catalog.Cat
 		Storage:              sto,
 		Log:                  a.c.Log.WithField(telemetry.SubsystemName, telemetry.Attestor),
 		ServerAddress:        a.c.ServerAddress,
+		Servers:              a.servers,
 		NodeAttestor:         na,
 		TLSPolicy:            a.c.TLSPolicy,
 	}
@@ -457,6 +474,7 @@ func (a *Agent) newManager(ctx context.Context, sto storage.Storage, cat catalog
 		Catalog:                  cat,
 		TrustDomain:              a.c.TrustDomain,
 		ServerAddr:               a.c.ServerAddress,
+		Servers:                  a.servers,
 		Log:                      a.c.Log.WithField(telemetry.SubsystemName, telemetry.Manager),
 		Metrics:                  metrics,
 		WorkloadKeyType:          a.c.WorkloadKeyType,
@@ -472,6 +490,8 @@ func (a *Agent) newManager(ctx context.Context, sto storage.Storage, cat catalog
 		NodeAttestor:             na,
 		RotationStrategy:         rotationutil.NewRotationStrategy(a.c.AvailabilityTarget),
 		TLSPolicy:                a.c.TLSPolicy,
+		PersistWorkloadSVIDs:     a.c.PersistWorkloadSVIDs,
+		Plugins:                  statusReportPlugins(a.c.PluginConfigs),
 	}
 
 	mgr := manager.New(config)
@@ -592,11 +612,26 @@ func (a *Agent) newAdminEndpoints(metrics telemetry.Metrics, mgr manager.Manager
 		Uptime:              uptime.Uptime,
 		Attestor:            attestor,
 		AuthorizedDelegates: authorizedDelegates,
+		Servers:             a.servers,
 	}
 
 	return admin_api.New(config)
 }
 
+func (a *Agent) newServerPool(metrics telemetry.Metrics) *client.ServerPool {
+	addresses := a.c.ServerAddresses
+	if len(addresses) == 0 && a.c.ServerAddress != "" {
+		addresses = []string{a.c.ServerAddress}
+	}
+	return client.NewServerPool(client.ServerPoolConfig{
+		Addresses:         addresses,
+		SRVName:           a.c.ServerSRVName,
+		RebalanceInterval: a.c.ServerRebalanceInterval,
+		Log:               a.c.Log.WithField(telemetry.SubsystemName, telemetry.ServerPool),
+		Metrics:           metrics,
+	})
+}
+
 // CheckHealth is used as a top-level health check for the agent.
 func (a *Agent) CheckHealth() health.State {
 	if a.c.BindAddress == nil {
@@ -685,3 +720,26 @@ func (a *Agent) startHealthChecks(readyForHealthChecks chan struct{}, taskRunner
 	}
 	taskRunner.StartTasks(healthChecker.ListenAndServe)
 }
+
+// statusReportPlugins returns the enabled plugins reported to the server with
+// the agent status. Built-in plugins have the version of the agent, and
+// external plugins are identified by their checksum, if configured.
+func statusReportPlugins(configs catalog.PluginConfigs) []*common.AgentPlugin {
+	var plugins []*common.AgentPlugin
+	for _, c := range configs {
+		if !c.IsEnabled() {
+			continue
+		}
+		plugin := &common.AgentPlugin{
+			Type: c.Type,
+			Name: c.Name,
+		}
+		if c.IsExternal() {
+			plugin.Checksum = c.Checksum
+		} else {
+			plugin.Version = version.Version()
+		}
+		plugins = append(plugins, plugin)
+	}
+	return plugins
+}
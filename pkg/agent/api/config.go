@@ -8,6 +8,7 @@ import (
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	loggerv1 "github.com/spiffe/spire/pkg/agent/api/logger/v1"
 	attestor "github.com/spiffe/spire/pkg/agent/attestor/workload"
+	"github.com/spiffe/spire/pkg/agent/client"
 	"github.com/spiffe/spire/pkg/agent/manager"
 	"github.com/spiffe/spire/pkg/common/peertracker"
 	"github.com/spiffe/spire/pkg/common/telemetry"
@@ -33,6 +34,9 @@ type Config struct {
 	Attestor attestor.Attestor
 
 	AuthorizedDelegates []string
+
+	// Servers is the pool of SPIRE servers the agent talks to
+	Servers *client.ServerPool
 }
 
 func New(c *Config) *Endpoints {
@@ -0,0 +1,124 @@
+package debug
+
+import (
+	"context"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/agent/manager/cache"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	agentdebug "github.com/spiffe/spire/proto/private/agent/debug"
+	"github.com/spiffe/spire/proto/spire/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListCachedEntries lists the registration entries cached by the agent
+func (s *Service) ListCachedEntries(ctx context.Context, req *agentdebug.ListCachedEntriesRequest) (*agentdebug.ListCachedEntriesResponse, error) {
+	var filters []func(*common.RegistrationEntry) bool
+	if filter := req.Filter; filter != nil {
+		if len(filter.BySelectors) > 0 {
+			selectors := filter.BySelectors
+			filters = append(filters, func(entry *common.RegistrationEntry) bool {
+				return matchesSelectors(entry, selectors)
+			})
+		}
+		if filter.BySpiffeId != "" {
+			id, err := spiffeid.FromString(filter.BySpiffeId)
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid SPIFFE ID filter: %v", err)
+			}
+			filters = append(filters, func(entry *common.RegistrationEntry) bool {
+				return entry.SpiffeId == id.String()
+			})
+		}
+		if filter.ByPid != 0 {
+			selectors, err := s.attest(ctx, filter.ByPid)
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, func(entry *common.RegistrationEntry) bool {
+				return matchesSelectors(entry, selectors)
+			})
+		}
+	}
+
+	resp := &agentdebug.ListCachedEntriesResponse{}
+	for _, identity := range s.m.CachedX509Identities() {
+		if matchesAll(identity.Entry, filters) {
+			resp.Entries = append(resp.Entries, cachedEntryFromIdentity(identity))
+		}
+	}
+	return resp, nil
+}
+
+// AttestWorkload attests a workload and returns its selectors along with the
+// cached entries that match them
+func (s *Service) AttestWorkload(ctx context.Context, req *agentdebug.AttestWorkloadRequest) (*agentdebug.AttestWorkloadResponse, error) {
+	selectors, err := s.attest(ctx, req.Pid)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &agentdebug.AttestWorkloadResponse{
+		Selectors: selectors,
+	}
+	for _, identity := range s.m.CachedX509Identities() {
+		if matchesSelectors(identity.Entry, selectors) {
+			resp.Entries = append(resp.Entries, cachedEntryFromIdentity(identity))
+		}
+	}
+	return resp, nil
+}
+
+func (s *Service) attest(ctx context.Context, pid int32) ([]*common.Selector, error) {
+	if pid <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid PID %d", pid)
+	}
+	if s.attestor == nil {
+		return nil, status.Error(codes.Unimplemented, "workload attestation is not available")
+	}
+
+	selectors, err := s.attestor.Attest(ctx, int(pid))
+	if err != nil {
+		s.log.WithError(err).WithField(telemetry.PID, pid).Error("Failed to attest workload")
+		return nil, status.Errorf(codes.Internal, "failed to attest workload: %v", err)
+	}
+	return selectors, nil
+}
+
+// matchesSelectors returns true if the entry would be assigned to a workload
+// with the given selectors, i.e. if the entry selectors are a subset of them.
+func matchesSelectors(entry *common.RegistrationEntry, selectors []*common.Selector) bool {
+	set := make(map[string]struct{}, len(selectors))
+	for _, selector := range selectors {
+		set[selector.Type+":"+selector.Value] = struct{}{}
+	}
+	for _, selector := range entry.Selectors {
+		if _, ok := set[selector.Type+":"+selector.Value]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAll(entry *common.RegistrationEntry, filters []func(*common.RegistrationEntry) bool) bool {
+	for _, filter := range filters {
+		if !filter(entry) {
+			return false
+		}
+	}
+	return true
+}
+
+func cachedEntryFromIdentity(identity cache.X509Identity) *agentdebug.CachedEntry {
+	cachedEntry := &agentdebug.CachedEntry{
+		Entry: identity.Entry,
+	}
+	if len(identity.SVID) > 0 {
+		cachedEntry.X509Svid = &agentdebug.CachedX509SVID{
+			SerialNumber: identity.SVID[0].SerialNumber.String(),
+			ExpiresAt:    identity.SVID[0].NotAfter.Unix(),
+		}
+	}
+	return cachedEntry
+}
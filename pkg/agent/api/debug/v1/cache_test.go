@@ -0,0 +1,194 @@
+package debug_test
+
+import (
+	"crypto/x509"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spiffe/spire/pkg/agent/manager/cache"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	agentdebug "github.com/spiffe/spire/proto/private/agent/debug"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	fooEntry = &common.RegistrationEntry{
+		EntryId:   "FOO",
+		SpiffeId:  "spiffe://example.org/foo",
+		ParentId:  "spiffe://example.org/spire/agent/foo",
+		Selectors: []*common.Selector{{Type: "unix", Value: "uid:1000"}},
+		Hint:      "external",
+	}
+	barEntry = &common.RegistrationEntry{
+		EntryId:  "BAR",
+		SpiffeId: "spiffe://example.org/bar",
+		ParentId: "spiffe://example.org/spire/agent/foo",
+		Selectors: []*common.Selector{
+			{Type: "unix", Value: "uid:1000"},
+			{Type: "unix", Value: "gid:1000"},
+		},
+	}
+	bazEntry = &common.RegistrationEntry{
+		EntryId:   "BAZ",
+		SpiffeId:  "spiffe://example.org/baz",
+		ParentId:  "spiffe://example.org/spire/agent/foo",
+		Selectors: []*common.Selector{{Type: "unix", Value: "uid:2000"}},
+	}
+	svidExpiresAt = time.Unix(1700000000, 0)
+	cachedFoo     = &agentdebug.CachedEntry{
+		Entry: fooEntry,
+		X509Svid: &agentdebug.CachedX509SVID{
+			SerialNumber: "12345",
+			ExpiresAt:    svidExpiresAt.Unix(),
+		},
+	}
+	cachedBar = &agentdebug.CachedEntry{Entry: barEntry}
+	cachedBaz = &agentdebug.CachedEntry{Entry: bazEntry}
+)
+
+func TestListCachedEntries(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		filter       *agentdebug.ListCachedEntriesRequest_Filter
+		expectCode   codes.Code
+		expectMsg    string
+		expectResp   *agentdebug.ListCachedEntriesResponse
+		expectedLogs []spiretest.LogEntry
+	}{
+		{
+			name: "no filter",
+			expectResp: &agentdebug.ListCachedEntriesResponse{
+				Entries: []*agentdebug.CachedEntry{cachedBar, cachedBaz, cachedFoo},
+			},
+		},
+		{
+			name: "by selectors",
+			filter: &agentdebug.ListCachedEntriesRequest_Filter{
+				BySelectors: []*common.Selector{{Type: "unix", Value: "uid:1000"}},
+			},
+			expectResp: &agentdebug.ListCachedEntriesResponse{
+				Entries: []*agentdebug.CachedEntry{cachedFoo},
+			},
+		},
+		{
+			name: "by SPIFFE ID",
+			filter: &agentdebug.ListCachedEntriesRequest_Filter{
+				BySpiffeId: "spiffe://example.org/baz",
+			},
+			expectResp: &agentdebug.ListCachedEntriesResponse{
+				Entries: []*agentdebug.CachedEntry{cachedBaz},
+			},
+		},
+		{
+			name: "by PID",
+			filter: &agentdebug.ListCachedEntriesRequest_Filter{
+				ByPid: 1,
+			},
+			expectResp: &agentdebug.ListCachedEntriesResponse{
+				Entries: []*agentdebug.CachedEntry{cachedBar, cachedFoo},
+			},
+		},
+		{
+			name: "by PID and SPIFFE ID",
+			filter: &agentdebug.ListCachedEntriesRequest_Filter{
+				ByPid:      1,
+				BySpiffeId: "spiffe://example.org/bar",
+			},
+			expectResp: &agentdebug.ListCachedEntriesResponse{
+				Entries: []*agentdebug.CachedEntry{cachedBar},
+			},
+		},
+		{
+			name: "no matches",
+			filter: &agentdebug.ListCachedEntriesRequest_Filter{
+				BySpiffeId: "spiffe://example.org/unknown",
+			},
+			expectResp: &agentdebug.ListCachedEntriesResponse{},
+		},
+		{
+			name: "invalid SPIFFE ID",
+			filter: &agentdebug.ListCachedEntriesRequest_Filter{
+				BySpiffeId: "not-an-id",
+			},
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "invalid SPIFFE ID filter: scheme is missing or invalid",
+		},
+		{
+			name: "attestation fails",
+			filter: &agentdebug.ListCachedEntriesRequest_Filter{
+				ByPid: 2,
+			},
+			expectCode: codes.Internal,
+			expectMsg:  "failed to attest workload: no such process",
+			expectedLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Failed to attest workload",
+					Data: logrus.Fields{
+						logrus.ErrorKey: "no such process",
+						telemetry.PID:   "2",
+					},
+				},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			test := setupCacheServiceTest(t)
+			defer test.Cleanup()
+
+			resp, err := test.cacheClient.ListCachedEntries(ctx, &agentdebug.ListCachedEntriesRequest{
+				Filter: tt.filter,
+			})
+			spiretest.AssertLogs(t, test.logHook.AllEntries(), tt.expectedLogs)
+			if tt.expectMsg != "" {
+				spiretest.RequireGRPCStatus(t, err, tt.expectCode, tt.expectMsg)
+				require.Nil(t, resp)
+				return
+			}
+			require.NoError(t, err)
+			spiretest.RequireProtoEqual(t, tt.expectResp, resp)
+		})
+	}
+}
+
+func TestAttestWorkload(t *testing.T) {
+	test := setupCacheServiceTest(t)
+	defer test.Cleanup()
+
+	resp, err := test.cacheClient.AttestWorkload(ctx, &agentdebug.AttestWorkloadRequest{Pid: 1})
+	require.NoError(t, err)
+	spiretest.RequireProtoEqual(t, &agentdebug.AttestWorkloadResponse{
+		Selectors: barEntry.Selectors,
+		Entries:   []*agentdebug.CachedEntry{cachedBar, cachedFoo},
+	}, resp)
+
+	_, err = test.cacheClient.AttestWorkload(ctx, &agentdebug.AttestWorkloadRequest{})
+	spiretest.RequireGRPCStatus(t, err, codes.InvalidArgument, "invalid PID 0")
+
+	_, err = test.cacheClient.AttestWorkload(ctx, &agentdebug.AttestWorkloadRequest{Pid: 2})
+	spiretest.RequireGRPCStatus(t, err, codes.Internal, "failed to attest workload: no such process")
+}
+
+func setupCacheServiceTest(t *testing.T) *serviceTest {
+	test := setupServiceTest(t)
+	test.m.identities = []cache.X509Identity{
+		{Entry: barEntry},
+		{Entry: bazEntry},
+		{
+			Entry: fooEntry,
+			SVID: []*x509.Certificate{{
+				SerialNumber: big.NewInt(12345),
+				NotAfter:     svidExpiresAt,
+			}},
+		},
+	}
+	test.attestor.selectors = map[int][]*common.Selector{
+		1: barEntry.Selectors,
+	}
+	return test
+}
@@ -13,21 +13,30 @@ import (
 	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
 	debugv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/agent/debug/v1"
 	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	workloadattestor "github.com/spiffe/spire/pkg/agent/attestor/workload"
+	"github.com/spiffe/spire/pkg/agent/client"
 	"github.com/spiffe/spire/pkg/agent/manager"
 	"github.com/spiffe/spire/pkg/common/util"
+	agentdebug "github.com/spiffe/spire/proto/private/agent/debug"
 	"github.com/spiffe/spire/test/clock"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
 const (
 	cacheExpiry = 5 * time.Second
+
+	// ActiveServerHeader is the GetInfo response header that holds the
+	// address of the SPIRE Server the agent currently talks to.
+	ActiveServerHeader = "spire-active-server"
 )
 
 // RegisterService registers debug service on provided server
 func RegisterService(s grpc.ServiceRegistrar, service *Service) {
 	debugv1.RegisterDebugServer(s, service)
+	agentdebug.RegisterCacheServer(s, service)
 }
 
 // Config configurations for debug service
@@ -37,28 +46,38 @@ type Config struct {
 	Manager     manager.Manager
 	TrustDomain spiffeid.TrustDomain
 	Uptime      func() time.Duration
+	// Servers is the optional pool of SPIRE Servers the agent talks to
+	Servers *client.ServerPool
+	// Attestor is the optional workload attestor used to inspect which
+	// cached entries match a workload
+	Attestor workloadattestor.Attestor
 }
 
 // New creates a new debug service
 func New(config Config) *Service {
 	return &Service{
-		clock:  config.Clock,
-		log:    config.Log,
-		m:      config.Manager,
-		td:     config.TrustDomain,
-		uptime: config.Uptime,
+		clock:    config.Clock,
+		log:      config.Log,
+		m:        config.Manager,
+		td:       config.TrustDomain,
+		uptime:   config.Uptime,
+		servers:  config.Servers,
+		attestor: config.Attestor,
 	}
 }
 
 // Service implements debug server
 type Service struct {
 	debugv1.UnsafeDebugServer
+	agentdebug.UnsafeCacheServer
 
-	clock  clock.Clock
-	log    logrus.FieldLogger
-	m      manager.Manager
-	td     spiffeid.TrustDomain
-	uptime func() time.Duration
+	clock    clock.Clock
+	log      logrus.FieldLogger
+	m        manager.Manager
+	td       spiffeid.TrustDomain
+	uptime   func() time.Duration
+	servers  *client.ServerPool
+	attestor workloadattestor.Attestor
 
 	getInfoResp getInfoResp
 }
@@ -70,7 +89,17 @@ type getInfoResp struct {
 }
 
 // GetInfo gets SPIRE Agent debug information
-func (s *Service) GetInfo(context.Context, *debugv1.GetInfoRequest) (*debugv1.GetInfoResponse, error) {
+func (s *Service) GetInfo(ctx context.Context, _ *debugv1.GetInfoRequest) (*debugv1.GetInfoResponse, error) {
+	// The active server changes independently of the cached response, so it
+	// is reported on every call
+	if s.servers != nil {
+		if activeServer := s.servers.Active(); activeServer != "" {
+			if err := grpc.SetHeader(ctx, metadata.Pairs(ActiveServerHeader, activeServer)); err != nil {
+				s.log.WithError(err).Debug("Failed to set active server header")
+			}
+		}
+	}
+
 	s.getInfoResp.mtx.Lock()
 	defer s.getInfoResp.mtx.Unlock()
 
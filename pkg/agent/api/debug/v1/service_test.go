@@ -5,6 +5,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"errors"
 	"testing"
 	"time"
 
@@ -15,9 +16,13 @@ import (
 	debugv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/agent/debug/v1"
 	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
 	debug "github.com/spiffe/spire/pkg/agent/api/debug/v1"
+	workloadattestor "github.com/spiffe/spire/pkg/agent/attestor/workload"
+	"github.com/spiffe/spire/pkg/agent/client"
 	"github.com/spiffe/spire/pkg/agent/manager"
 	"github.com/spiffe/spire/pkg/agent/manager/cache"
 	"github.com/spiffe/spire/pkg/agent/svid"
+	agentdebug "github.com/spiffe/spire/proto/private/agent/debug"
+	"github.com/spiffe/spire/proto/spire/common"
 	"github.com/spiffe/spire/test/clock"
 	"github.com/spiffe/spire/test/grpctest"
 	"github.com/spiffe/spire/test/spiretest"
@@ -25,6 +30,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 )
 
 var (
@@ -231,14 +237,43 @@ func TestGetInfo(t *testing.T) {
 	}
 }
 
-type serviceTest struct {
-	client debugv1.DebugClient
-	done   func()
+func TestGetInfoActiveServer(t *testing.T) {
+	ca := testca.New(t, td)
+	x509SVID := ca.CreateX509SVID(spiffeid.RequireFromPath(td, "/spire/agent/foo"))
+
+	test := setupServiceTest(t)
+	defer test.Cleanup()
+	test.m.bundle = spiffebundle.FromX509Authorities(td, ca.Bundle().X509Authorities())
+	test.m.svidState = svid.State{
+		SVID: x509SVID.Certificates,
+		Key:  x509SVID.PrivateKey.(*ecdsa.PrivateKey),
+	}
+
+	// No server has been selected yet
+	var header metadata.MD
+	_, err := test.client.GetInfo(ctx, &debugv1.GetInfoRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+	require.Empty(t, header.Get(debug.ActiveServerHeader))
+
+	_, err = test.servers.Select()
+	require.NoError(t, err)
+
+	_, err = test.client.GetInfo(ctx, &debugv1.GetInfoRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+	require.Equal(t, []string{"dns:///spire-server:8081"}, header.Get(debug.ActiveServerHeader))
+}
 
-	clk     *clock.Mock
-	logHook *test.Hook
-	m       *fakeManager
-	uptime  *fakeUptime
+type serviceTest struct {
+	client      debugv1.DebugClient
+	cacheClient agentdebug.CacheClient
+	done        func()
+
+	clk      *clock.Mock
+	logHook  *test.Hook
+	m        *fakeManager
+	uptime   *fakeUptime
+	servers  *client.ServerPool
+	attestor *fakeAttestor
 }
 
 func (s *serviceTest) Cleanup() {
@@ -248,6 +283,7 @@ func (s *serviceTest) Cleanup() {
 func setupServiceTest(t *testing.T) *serviceTest {
 	clk := clock.NewMock(t)
 	manager := &fakeManager{}
+	attestor := &fakeAttestor{}
 	log, logHook := test.NewNullLogger()
 	log.Level = logrus.DebugLevel
 
@@ -256,19 +292,28 @@ func setupServiceTest(t *testing.T) *serviceTest {
 		clk:   clk,
 	}
 
+	servers := client.NewServerPool(client.ServerPoolConfig{
+		Addresses: []string{"dns:///spire-server:8081"},
+		Clock:     clk,
+	})
+
 	service := debug.New(debug.Config{
 		Clock:       clk,
 		Log:         log,
 		Manager:     manager,
 		TrustDomain: td,
 		Uptime:      fakeUptime.uptime,
+		Servers:     servers,
+		Attestor:    attestor,
 	})
 
 	test := &serviceTest{
-		clk:     clk,
-		logHook: logHook,
-		m:       manager,
-		uptime:  fakeUptime,
+		clk:      clk,
+		logHook:  logHook,
+		m:        manager,
+		uptime:   fakeUptime,
+		servers:  servers,
+		attestor: attestor,
 	}
 
 	registerFn := func(s grpc.ServiceRegistrar) {
@@ -276,7 +321,9 @@ func setupServiceTest(t *testing.T) *serviceTest {
 	}
 	server := grpctest.StartServer(t, registerFn)
 	test.done = server.Stop
-	test.client = debugv1.NewDebugClient(server.NewGRPCClient(t))
+	conn := server.NewGRPCClient(t)
+	test.client = debugv1.NewDebugClient(conn)
+	test.cacheClient = agentdebug.NewCacheClient(conn)
 
 	return test
 }
@@ -291,6 +338,7 @@ type fakeManager struct {
 	jwtSvidCount           int
 	svidstoreX509SvidCount int
 	lastSync               time.Time
+	identities             []cache.X509Identity
 }
 
 func (m *fakeManager) GetCurrentCredentials() svid.State {
@@ -321,6 +369,24 @@ func (m *fakeManager) GetBundle() *cache.Bundle {
 	return m.bundle
 }
 
+func (m *fakeManager) CachedX509Identities() []cache.X509Identity {
+	return m.identities
+}
+
+type fakeAttestor struct {
+	workloadattestor.Attestor
+
+	selectors map[int][]*common.Selector
+}
+
+func (a *fakeAttestor) Attest(_ context.Context, pid int) ([]*common.Selector, error) {
+	selectors, ok := a.selectors[pid]
+	if !ok {
+		return nil, errors.New("no such process")
+	}
+	return selectors, nil
+}
+
 type fakeUptime struct {
 	start time.Time
 	clk   *clock.Mock
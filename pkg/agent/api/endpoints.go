@@ -74,6 +74,8 @@ func (e *Endpoints) registerDebugAPI(server *grpc.Server) {
 		Manager:     e.c.Manager,
 		Uptime:      e.c.Uptime,
 		TrustDomain: e.c.TrustDomain,
+		Servers:     e.c.Servers,
+		Attestor:    e.c.Attestor,
 	})
 
 	debugv1.RegisterService(server, service)
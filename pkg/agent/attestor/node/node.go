@@ -50,8 +50,11 @@ type Config struct {
 	Storage              storage.Storage
 	Log                  logrus.FieldLogger
 	ServerAddress        string
-	NodeAttestor         nodeattestor.NodeAttestor
-	TLSPolicy            tlspolicy.Policy
+	// Servers optionally selects the server among several. When unset,
+	// ServerAddress is used.
+	Servers      *client.ServerPool
+	NodeAttestor nodeattestor.NodeAttestor
+	TLSPolicy    tlspolicy.Policy
 }
 
 type attestor struct {
@@ -59,6 +62,13 @@ type attestor struct {
 }
 
 func New(config *Config) Attestor {
+	if config.Servers == nil {
+		config.Servers = client.NewServerPool(client.ServerPoolConfig{
+			Addresses: []string{config.ServerAddress},
+			Log:       config.Log,
+			Metrics:   config.Metrics,
+		})
+	}
 	return &attestor{c: config}
 }
 
@@ -247,7 +257,7 @@ func (a *attestor) newSVID(ctx context.Context, key keymanager.Key, bundle *spif
 func (a *attestor) serverConn(bundle *spiffebundle.Bundle) (*grpc.ClientConn, error) {
 	if bundle != nil {
 		return client.NewServerGRPCClient(client.ServerClientConfig{
-			Address:     a.c.ServerAddress,
+			Servers:     a.c.Servers,
 			TrustDomain: a.c.TrustDomain,
 			GetBundle:   bundle.X509Authorities,
 			TLSPolicy:   a.c.TLSPolicy,
@@ -290,11 +300,18 @@ func (a *attestor) serverConn(bundle *spiffebundle.Bundle) (*grpc.ClientConn, er
 		},
 	}
 
+	address, err := a.c.Servers.Select()
+	if err != nil {
+		return nil, err
+	}
+
 	return grpc.NewClient(
-		a.c.ServerAddress,
-		grpc.WithDefaultServiceConfig(roundRobinServiceConfig),
-		grpc.WithDisableServiceConfig(),
-		grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
+		address,
+		append([]grpc.DialOption{
+			grpc.WithDefaultServiceConfig(roundRobinServiceConfig),
+			grpc.WithDisableServiceConfig(),
+			grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
+		}, a.c.Servers.DialOptions(address)...)...,
 	)
 }
 
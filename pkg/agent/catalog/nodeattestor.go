@@ -8,6 +8,7 @@ import (
 	"github.com/spiffe/spire/pkg/agent/plugin/nodeattestor/gcpiit"
 	"github.com/spiffe/spire/pkg/agent/plugin/nodeattestor/httpchallenge"
 	"github.com/spiffe/spire/pkg/agent/plugin/nodeattestor/jointoken"
+	"github.com/spiffe/spire/pkg/agent/plugin/nodeattestor/jwtoidc"
 	"github.com/spiffe/spire/pkg/agent/plugin/nodeattestor/k8spsat"
 	"github.com/spiffe/spire/pkg/agent/plugin/nodeattestor/sshpop"
 	"github.com/spiffe/spire/pkg/agent/plugin/nodeattestor/tpmdevid"
@@ -41,6 +42,7 @@ func (repo *nodeAttestorRepository) BuiltIns() []catalog.BuiltIn {
 		gcpiit.BuiltIn(),
 		httpchallenge.BuiltIn(),
 		jointoken.BuiltIn(),
+		jwtoidc.BuiltIn(),
 		k8spsat.BuiltIn(),
 		sshpop.BuiltIn(),
 		tpmdevid.BuiltIn(),
@@ -3,7 +3,9 @@ package catalog
 import (
 	"github.com/spiffe/spire/pkg/agent/plugin/svidstore"
 	"github.com/spiffe/spire/pkg/agent/plugin/svidstore/awssecretsmanager"
+	"github.com/spiffe/spire/pkg/agent/plugin/svidstore/disk"
 	"github.com/spiffe/spire/pkg/agent/plugin/svidstore/gcpsecretmanager"
+	"github.com/spiffe/spire/pkg/agent/plugin/svidstore/k8ssecret"
 	"github.com/spiffe/spire/pkg/common/catalog"
 )
 
@@ -26,7 +28,9 @@ func (repo *svidStoreRepository) Versions() []catalog.Version {
 func (repo *svidStoreRepository) BuiltIns() []catalog.BuiltIn {
 	return []catalog.BuiltIn{
 		awssecretsmanager.BuiltIn(),
+		disk.BuiltIn(),
 		gcpsecretmanager.BuiltIn(),
+		k8ssecret.BuiltIn(),
 	}
 }
 
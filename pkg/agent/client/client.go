@@ -24,6 +24,7 @@ import (
 	"github.com/spiffe/spire/pkg/common/idutil"
 	"github.com/spiffe/spire/pkg/common/telemetry"
 	"github.com/spiffe/spire/pkg/common/tlspolicy"
+	agentstatusv1 "github.com/spiffe/spire/proto/private/server/agentstatus"
 	"github.com/spiffe/spire/proto/spire/common"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -108,6 +109,7 @@ type Client interface {
 	NewJWTSVID(ctx context.Context, entryID string, audience []string, hasCacheHit bool) (*JWTSVID, spiffeid.ID, error)
 	NewWITSVIDs(ctx context.Context, publicKeys map[string]crypto.PublicKey, signatureAlgorithm string) (map[string]*WITSVID, error)
 	PostStatus(ctx context.Context, agentVersion string) error
+	PostStatusReport(ctx context.Context, statusReport *common.AgentStatusReport) error
 
 	// Release releases any resources that were held by this Client, if any.
 	Release()
@@ -115,7 +117,10 @@ type Client interface {
 
 // Config holds a client configuration
 type Config struct {
-	Addr        string
+	Addr string
+	// Servers optionally selects the server to connect to among several,
+	// failing over when a server is unavailable. When unset, Addr is used.
+	Servers     *ServerPool
 	Log         logrus.FieldLogger
 	TrustDomain spiffeid.TrustDomain
 	// KeysAndBundle is a callback that must return the keys and bundle used by the client
@@ -131,8 +136,11 @@ type Config struct {
 
 type client struct {
 	c           *Config
+	servers     *ServerPool
 	connections *nodeConn
-	m           sync.Mutex
+	// connAddr is the address of the server connections is connected to
+	connAddr string
+	m        sync.Mutex
 
 	// clk is used for backoff timing when retrying transient failures.
 	clk clock.Clock
@@ -153,13 +161,24 @@ func New(c *Config) Client {
 }
 
 func newClient(c *Config) *client {
+	servers := c.Servers
+	if servers == nil {
+		servers = NewServerPool(ServerPoolConfig{
+			Addresses: []string{c.Addr},
+			Log:       c.Log,
+		})
+	}
 	return &client{
-		c:   c,
-		clk: clock.New(),
+		c:       c,
+		servers: servers,
+		clk:     clock.New(),
 	}
 }
 
-func (c *client) FetchUpdates(ctx context.Context) (*Update, error) {
+func (c *client) FetchUpdates(ctx context.Context) (_ *Update, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "agent.client.FetchUpdates")
+	defer telemetry.EndSpan(span, &err)
+
 	c.c.RotMtx.RLock()
 	defer c.c.RotMtx.RUnlock()
 
@@ -218,7 +237,10 @@ func (c *client) FetchUpdates(ctx context.Context) (*Update, error) {
 	}, nil
 }
 
-func (c *client) SyncUpdates(ctx context.Context, cachedEntries map[string]*common.RegistrationEntry, cachedBundles map[string]*common.Bundle) (SyncStats, error) {
+func (c *client) SyncUpdates(ctx context.Context, cachedEntries map[string]*common.RegistrationEntry, cachedBundles map[string]*common.Bundle) (_ SyncStats, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "agent.client.SyncUpdates")
+	defer telemetry.EndSpan(span, &err)
+
 	switch {
 	case cachedEntries == nil:
 		return SyncStats{}, errors.New("non-nil cached entries map is required")
@@ -270,7 +292,10 @@ func (c *client) SyncUpdates(ctx context.Context, cachedEntries map[string]*comm
 	}, nil
 }
 
-func (c *client) RenewSVID(ctx context.Context, csr []byte) (*X509SVID, error) {
+func (c *client) RenewSVID(ctx context.Context, csr []byte) (_ *X509SVID, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "agent.client.RenewSVID")
+	defer telemetry.EndSpan(span, &err)
+
 	ctx, cancel := context.WithTimeout(ctx, rpcTimeout)
 	defer cancel()
 
@@ -301,7 +326,10 @@ func (c *client) RenewSVID(ctx context.Context, csr []byte) (*X509SVID, error) {
 	}, nil
 }
 
-func (c *client) PostStatus(ctx context.Context, agentVersion string) error {
+func (c *client) PostStatus(ctx context.Context, agentVersion string) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "agent.client.PostStatus")
+	defer telemetry.EndSpan(span, &err)
+
 	c.c.RotMtx.RLock()
 	defer c.c.RotMtx.RUnlock()
 
@@ -326,7 +354,40 @@ func (c *client) PostStatus(ctx context.Context, agentVersion string) error {
 	return nil
 }
 
-func (c *client) NewX509SVIDs(ctx context.Context, csrs map[string][]byte) (map[string]*X509SVID, error) {
+// PostStatusReport posts the status report of the agent, which also
+// refreshes the last time the server has seen it.
+func (c *client) PostStatusReport(ctx context.Context, statusReport *common.AgentStatusReport) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "agent.client.PostStatusReport")
+	defer telemetry.EndSpan(span, &err)
+
+	c.c.RotMtx.RLock()
+	defer c.c.RotMtx.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	agentStatusClient, connection, err := c.newAgentStatusClient()
+	if err != nil {
+		return err
+	}
+	defer connection.Release()
+
+	_, err = agentStatusClient.PostStatus(ctx, &agentstatusv1.PostStatusRequest{
+		Report: statusReport,
+	})
+	if err != nil {
+		c.release(connection)
+		c.c.Log.WithError(err).Warn("Failed to post agent status report")
+		return fmt.Errorf("failed to post agent status report: %w", err)
+	}
+
+	return nil
+}
+
+func (c *client) NewX509SVIDs(ctx context.Context, csrs map[string][]byte) (_ map[string]*X509SVID, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "agent.client.NewX509SVIDs")
+	defer telemetry.EndSpan(span, &err)
+
 	c.c.RotMtx.RLock()
 	defer c.c.RotMtx.RUnlock()
 
@@ -367,7 +428,10 @@ func (c *client) NewX509SVIDs(ctx context.Context, csrs map[string][]byte) (map[
 	return svids, nil
 }
 
-func (c *client) NewJWTSVID(ctx context.Context, entryID string, audience []string, hasCacheHit bool) (*JWTSVID, spiffeid.ID, error) {
+func (c *client) NewJWTSVID(ctx context.Context, entryID string, audience []string, hasCacheHit bool) (_ *JWTSVID, _ spiffeid.ID, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "agent.client.NewJWTSVID")
+	defer telemetry.EndSpan(span, &err)
+
 	timeout := rpcTimeout
 	if hasCacheHit {
 		timeout = RPCTimeoutWithCacheHit
@@ -451,7 +515,10 @@ func (c *client) fetchJWTSVID(ctx context.Context, entryID string, audience []st
 	return resp.Svid, nil
 }
 
-func (c *client) NewWITSVIDs(ctx context.Context, publicKeys map[string]crypto.PublicKey, signatureAlgorithm string) (map[string]*WITSVID, error) {
+func (c *client) NewWITSVIDs(ctx context.Context, publicKeys map[string]crypto.PublicKey, signatureAlgorithm string) (_ map[string]*WITSVID, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "agent.client.NewWITSVIDs")
+	defer telemetry.EndSpan(span, &err)
+
 	c.c.RotMtx.RLock()
 	defer c.c.RotMtx.RUnlock()
 
@@ -510,7 +577,7 @@ func (c *client) release(conn *nodeConn) {
 
 func (c *client) newServerGRPCClient() (*grpc.ClientConn, error) {
 	return NewServerGRPCClient(ServerClientConfig{
-		Address:     c.c.Addr,
+		Servers:     c.servers,
 		TrustDomain: c.c.TrustDomain,
 		GetBundle: func() []*x509.Certificate {
 			_, _, bundle := c.c.KeysAndBundle()
@@ -948,16 +1015,38 @@ func (c *client) newAgentClient() (agentv1.AgentClient, *nodeConn, error) {
 	return agentv1.NewAgentClient(conn.Conn()), conn, nil
 }
 
+func (c *client) newAgentStatusClient() (agentstatusv1.AgentStatusClient, *nodeConn, error) {
+	conn, err := c.getOrOpenConn()
+	if err != nil {
+		return nil, nil, err
+	}
+	return agentstatusv1.NewAgentStatusClient(conn.Conn()), conn, nil
+}
+
 func (c *client) getOrOpenConn() (*nodeConn, error) {
 	c.m.Lock()
 	defer c.m.Unlock()
 
+	if c.connections != nil {
+		// Drop the connection when another server has been selected, e.g.
+		// after failing over or rebalancing
+		address, err := c.servers.Select()
+		if err != nil {
+			return nil, err
+		}
+		if address != c.connAddr {
+			c.connections.Release()
+			c.connections = nil
+		}
+	}
+
 	if c.connections == nil {
 		conn, err := c.newServerGRPCClient()
 		if err != nil {
 			return nil, err
 		}
 		c.connections = newNodeConn(conn)
+		c.connAddr = conn.Target()
 	}
 	c.connections.AddRef()
 	return c.connections, nil
@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/spiffe/go-spiffe/v2/bundle/x509bundle"
@@ -12,6 +13,7 @@ import (
 	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
 	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
 	"github.com/spiffe/spire/pkg/common/idutil"
+	"github.com/spiffe/spire/pkg/common/telemetry"
 	"github.com/spiffe/spire/pkg/common/tlspolicy"
 	"github.com/spiffe/spire/pkg/common/x509util"
 	"google.golang.org/grpc"
@@ -27,6 +29,11 @@ type ServerClientConfig struct {
 	// Address is the SPIRE server address
 	Address string
 
+	// Servers is an optional server pool. When set, the server address is
+	// selected from the pool instead of using Address, and the results of the
+	// RPCs made over the connection are reported back to the pool.
+	Servers *ServerPool
+
 	TrustDomain spiffeid.TrustDomain
 
 	// GetBundle is a required callback that returns the current trust bundle
@@ -64,7 +71,15 @@ func NewServerGRPCClient(config ServerClientConfig) (*grpc.ClientConn, error) {
 		return nil, err
 	}
 
-	dialOpts := config.dialOpts
+	address := config.Address
+	if config.Servers != nil {
+		address, err = config.Servers.Select()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dialOpts := slices.Clone(config.dialOpts)
 	if dialOpts == nil {
 		dialOpts = []grpc.DialOption{
 			grpc.WithDefaultServiceConfig(roundRobinServiceConfig),
@@ -72,8 +87,15 @@ func NewServerGRPCClient(config ServerClientConfig) (*grpc.ClientConn, error) {
 			grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
 		}
 	}
+	dialOpts = append(dialOpts,
+		grpc.WithChainUnaryInterceptor(telemetry.TracingUnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(telemetry.TracingStreamClientInterceptor()),
+	)
+	if config.Servers != nil {
+		dialOpts = append(dialOpts, config.Servers.DialOptions(address)...)
+	}
 
-	client, err := grpc.NewClient(config.Address, dialOpts...)
+	client, err := grpc.NewClient(address, dialOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
 	}
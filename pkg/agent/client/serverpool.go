@@ -0,0 +1,383 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andres-erbsen/clock"
+	"github.com/sirupsen/logrus"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultServerRebalanceInterval is how long the agent sticks to a
+	// healthy server before selecting a server again, which spreads agents
+	// back across servers that have recovered from an outage.
+	DefaultServerRebalanceInterval = 30 * time.Minute
+
+	// serverRetryInterval is how long a server is avoided after it is first
+	// found unavailable. The interval doubles on consecutive failures, up to
+	// serverMaxRetryInterval.
+	serverRetryInterval    = 5 * time.Second
+	serverMaxRetryInterval = 5 * time.Minute
+
+	// srvRefreshInterval is how often the SRV record is resolved again.
+	srvRefreshInterval = time.Minute
+	srvLookupTimeout   = 5 * time.Second
+)
+
+// ServerPoolConfig is the configuration for a ServerPool.
+type ServerPoolConfig struct {
+	// Addresses are the gRPC target addresses of the SPIRE Servers
+	// (e.g. dns:///spire-server-1:8081).
+	Addresses []string
+
+	// SRVName is the name of a DNS SRV record used to discover the SPIRE
+	// Servers (e.g. _spire-server._tcp.example.org). It is resolved
+	// periodically. Servers with the lowest priority value are preferred.
+	SRVName string
+
+	// RebalanceInterval is how long a healthy server is kept before the
+	// server is selected again. Up to a fifth of the interval is added as
+	// jitter so agents do not rebalance in lockstep. Defaults to
+	// DefaultServerRebalanceInterval.
+	RebalanceInterval time.Duration
+
+	Log     logrus.FieldLogger
+	Metrics telemetry.Metrics
+	Clock   clock.Clock
+
+	// lookupSRV and randIntN are overridden by tests
+	lookupSRV func(ctx context.Context, name string) ([]*net.SRV, error)
+	randIntN  func(n int) int
+}
+
+// ServerStatus describes a server in the pool.
+type ServerStatus struct {
+	Address string
+
+	// Active is true if the server is the one the agent currently talks to
+	Active bool
+
+	// Healthy is false while the server is avoided because it was found
+	// unavailable
+	Healthy bool
+
+	// ConsecutiveFailures is the number of times in a row the server was
+	// found unavailable
+	ConsecutiveFailures int
+
+	// LastError is the error that last marked the server unavailable
+	LastError string
+}
+
+// ServerPool selects the SPIRE Server the agent talks to. The selected
+// server is sticky: it is kept until it is found unavailable or until the
+// rebalance interval elapses. Servers that are unavailable are avoided, with
+// an exponential backoff, until they are retried. When every server is
+// unavailable, the server that is due to be retried first is selected.
+type ServerPool struct {
+	c ServerPoolConfig
+
+	mu      sync.Mutex
+	servers []*serverState
+	// active is the server in use. It is cleared when the server is found
+	// unavailable, while selected keeps the server last selected.
+	active       *serverState
+	selected     *serverState
+	rebalanceAt  time.Time
+	srvRefreshAt time.Time
+}
+
+type serverState struct {
+	address    string
+	priority   uint16
+	failures   int
+	lastErr    error
+	retryAfter time.Time
+}
+
+// NewServerPool returns a new server pool.
+func NewServerPool(config ServerPoolConfig) *ServerPool {
+	if config.RebalanceInterval <= 0 {
+		config.RebalanceInterval = DefaultServerRebalanceInterval
+	}
+	if config.Log == nil {
+		log := logrus.New()
+		log.Out = io.Discard
+		config.Log = log
+	}
+	if config.Metrics == nil {
+		config.Metrics = telemetry.Blackhole{}
+	}
+	if config.Clock == nil {
+		config.Clock = clock.New()
+	}
+	if config.lookupSRV == nil {
+		config.lookupSRV = lookupSRV
+	}
+	if config.randIntN == nil {
+		config.randIntN = rand.IntN
+	}
+
+	p := &ServerPool{c: config}
+	for _, address := range config.Addresses {
+		p.servers = append(p.servers, &serverState{address: address})
+	}
+	return p
+}
+
+// Select returns the address of the server to talk to.
+func (p *ServerPool) Select() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.c.Clock.Now()
+	p.refreshSRV(now)
+
+	if p.active != nil && !p.active.retryAfter.After(now) && now.Before(p.rebalanceAt) {
+		return p.active.address, nil
+	}
+
+	selected := p.pick(now)
+	if selected == nil {
+		return "", errors.New("no SPIRE Server addresses available")
+	}
+	p.setActive(selected, now)
+	return selected.address, nil
+}
+
+// ReportResult reports the result of an RPC made to the server with the
+// given address. Unavailable errors mark the server as unhealthy, which makes
+// the pool fail over to another server. Other errors indicate the server is
+// reachable and do not affect its health.
+func (p *ServerPool) ReportResult(address string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	server := p.find(address)
+	if server == nil {
+		return
+	}
+
+	switch status.Code(err) {
+	case codes.OK:
+		server.failures = 0
+		server.lastErr = nil
+		server.retryAfter = time.Time{}
+	case codes.Unavailable:
+		server.failures++
+		server.lastErr = err
+		server.retryAfter = p.c.Clock.Now().Add(retryInterval(server.failures))
+		if server == p.active {
+			p.active = nil
+			p.c.Log.WithError(err).WithFields(logrus.Fields{
+				telemetry.Address: address,
+				telemetry.Attempt: server.failures,
+			}).Warn("SPIRE Server is unavailable; failing over")
+			p.c.Metrics.IncrCounterWithLabels([]string{telemetry.ServerPool, telemetry.Failover}, 1, []telemetry.Label{
+				{Name: telemetry.Address, Value: address},
+			})
+		}
+	}
+}
+
+// Active returns the address of the server currently selected, if any.
+func (p *ServerPool) Active() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.active == nil {
+		return ""
+	}
+	return p.active.address
+}
+
+// Status returns the status of each server in the pool.
+func (p *ServerPool) Status() []ServerStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.c.Clock.Now()
+	statuses := make([]ServerStatus, 0, len(p.servers))
+	for _, server := range p.servers {
+		s := ServerStatus{
+			Address:             server.address,
+			Active:              server == p.active,
+			Healthy:             !server.retryAfter.After(now),
+			ConsecutiveFailures: server.failures,
+		}
+		if server.lastErr != nil {
+			s.LastError = server.lastErr.Error()
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses
+}
+
+// DialOptions returns the dial options that report the results of the RPCs
+// made over a connection to the server with the given address.
+func (p *ServerPool) DialOptions(address string) []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			p.ReportResult(address, err)
+			return err
+		}),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			stream, err := streamer(ctx, desc, cc, method, opts...)
+			if err != nil {
+				p.ReportResult(address, err)
+				return nil, err
+			}
+			return &reportingStream{ClientStream: stream, pool: p, address: address}, nil
+		}),
+	}
+}
+
+func (p *ServerPool) pick(now time.Time) *serverState {
+	var candidates []*serverState
+	for _, server := range p.servers {
+		if !server.retryAfter.After(now) {
+			candidates = append(candidates, server)
+		}
+	}
+
+	if len(candidates) == 0 {
+		// Every server is unavailable. Keep trying the one that is due to be
+		// retried first rather than failing outright.
+		var next *serverState
+		for _, server := range p.servers {
+			if next == nil || server.retryAfter.Before(next.retryAfter) {
+				next = server
+			}
+		}
+		return next
+	}
+
+	// Prefer the servers with the lowest priority value and spread the
+	// agents randomly among them.
+	lowest := slices.MinFunc(candidates, func(a, b *serverState) int {
+		return int(a.priority) - int(b.priority)
+	}).priority
+	candidates = slices.DeleteFunc(candidates, func(server *serverState) bool {
+		return server.priority != lowest
+	})
+	return candidates[p.c.randIntN(len(candidates))]
+}
+
+func (p *ServerPool) setActive(server *serverState, now time.Time) {
+	previous := p.selected
+	p.active = server
+	p.selected = server
+	jitter := time.Duration(p.c.randIntN(int(p.c.RebalanceInterval/5) + 1))
+	p.rebalanceAt = now.Add(p.c.RebalanceInterval + jitter)
+
+	if previous == server {
+		return
+	}
+
+	log := p.c.Log.WithField(telemetry.ActiveServer, server.address)
+	if previous == nil {
+		log.Debug("Selected SPIRE Server")
+	} else {
+		log.Info("Selected SPIRE Server")
+		p.c.Metrics.SetGaugeWithLabels([]string{telemetry.ServerPool, telemetry.ActiveServer}, 0, []telemetry.Label{
+			{Name: telemetry.Address, Value: previous.address},
+		})
+	}
+	p.c.Metrics.SetGaugeWithLabels([]string{telemetry.ServerPool, telemetry.ActiveServer}, 1, []telemetry.Label{
+		{Name: telemetry.Address, Value: server.address},
+	})
+}
+
+// refreshSRV resolves the SRV record when due. The servers from the last
+// successful lookup are kept when the lookup fails.
+func (p *ServerPool) refreshSRV(now time.Time) {
+	if p.c.SRVName == "" || now.Before(p.srvRefreshAt) {
+		return
+	}
+	p.srvRefreshAt = now.Add(srvRefreshInterval)
+
+	ctx, cancel := context.WithTimeout(context.Background(), srvLookupTimeout)
+	defer cancel()
+	records, err := p.c.lookupSRV(ctx, p.c.SRVName)
+	if err != nil {
+		p.c.Log.WithError(err).WithField(telemetry.Address, p.c.SRVName).Warn("Failed to resolve SPIRE Server SRV record")
+		return
+	}
+
+	servers := make([]*serverState, 0, len(records))
+	for _, record := range records {
+		address := srvAddress(record)
+		server := p.find(address)
+		if server == nil {
+			server = &serverState{address: address}
+		}
+		server.priority = record.Priority
+		servers = append(servers, server)
+	}
+	p.servers = servers
+
+	if p.active != nil && !slices.Contains(p.servers, p.active) {
+		p.active = nil
+	}
+}
+
+func (p *ServerPool) find(address string) *serverState {
+	for _, server := range p.servers {
+		if server.address == address {
+			return server
+		}
+	}
+	return nil
+}
+
+func retryInterval(failures int) time.Duration {
+	interval := serverRetryInterval
+	for i := 1; i < failures && interval < serverMaxRetryInterval; i++ {
+		interval *= 2
+	}
+	return min(interval, serverMaxRetryInterval)
+}
+
+func srvAddress(record *net.SRV) string {
+	host := strings.TrimSuffix(record.Target, ".")
+	return fmt.Sprintf("dns:///%s", net.JoinHostPort(host, strconv.Itoa(int(record.Port))))
+}
+
+func lookupSRV(ctx context.Context, name string) ([]*net.SRV, error) {
+	_, records, err := net.DefaultResolver.LookupSRV(ctx, "", "", name)
+	return records, err
+}
+
+// reportingStream reports the result of a stream to the server pool once the
+// stream ends.
+type reportingStream struct {
+	grpc.ClientStream
+	pool    *ServerPool
+	address string
+}
+
+func (s *reportingStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == nil:
+	case errors.Is(err, io.EOF):
+		s.pool.ReportResult(s.address, nil)
+	default:
+		s.pool.ReportResult(s.address, err)
+	}
+	return err
+}
@@ -0,0 +1,278 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	agentv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/agent/v1"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/test/clock"
+	"github.com/spiffe/spire/test/fakes/fakemetrics"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	serverA = "dns:///server-a:8081"
+	serverB = "dns:///server-b:8081"
+	serverC = "dns:///server-c:8081"
+)
+
+var errUnavailable = status.Error(codes.Unavailable, "connection refused")
+
+func TestServerPoolIsSticky(t *testing.T) {
+	p, _ := newTestServerPool(t, ServerPoolConfig{Addresses: []string{serverA, serverB}})
+
+	requireSelect(t, p, serverA)
+	p.c.randIntN = func(n int) int { return n - 1 }
+	requireSelect(t, p, serverA)
+
+	// Errors other than Unavailable mean the server is reachable
+	p.ReportResult(serverA, status.Error(codes.PermissionDenied, "denied"))
+	requireSelect(t, p, serverA)
+	require.Equal(t, serverA, p.Active())
+}
+
+func TestServerPoolFailsOver(t *testing.T) {
+	metrics := fakemetrics.New()
+	log, hook := test.NewNullLogger()
+	p, clk := newTestServerPool(t, ServerPoolConfig{
+		Addresses: []string{serverA, serverB},
+		Log:       log,
+		Metrics:   metrics,
+	})
+
+	requireSelect(t, p, serverA)
+	p.ReportResult(serverA, errUnavailable)
+	require.Empty(t, p.Active())
+	requireSelect(t, p, serverB)
+
+	spiretest.AssertLastLogs(t, hook.AllEntries(), []spiretest.LogEntry{
+		{
+			Level:   logrus.WarnLevel,
+			Message: "SPIRE Server is unavailable; failing over",
+			Data: logrus.Fields{
+				telemetry.Address: serverA,
+				telemetry.Attempt: "1",
+				logrus.ErrorKey:   errUnavailable.Error(),
+			},
+		},
+		{
+			Level:   logrus.InfoLevel,
+			Message: "Selected SPIRE Server",
+			Data: logrus.Fields{
+				telemetry.ActiveServer: serverB,
+			},
+		},
+	})
+
+	require.Equal(t, []fakemetrics.MetricItem{
+		{
+			Type:   fakemetrics.SetGaugeWithLabelsType,
+			Key:    []string{telemetry.ServerPool, telemetry.ActiveServer},
+			Val:    1,
+			Labels: []telemetry.Label{{Name: telemetry.Address, Value: "dns_server_a_8081"}},
+		},
+		{
+			Type:   fakemetrics.IncrCounterWithLabelsType,
+			Key:    []string{telemetry.ServerPool, telemetry.Failover},
+			Val:    1,
+			Labels: []telemetry.Label{{Name: telemetry.Address, Value: "dns_server_a_8081"}},
+		},
+		{
+			Type:   fakemetrics.SetGaugeWithLabelsType,
+			Key:    []string{telemetry.ServerPool, telemetry.ActiveServer},
+			Val:    0,
+			Labels: []telemetry.Label{{Name: telemetry.Address, Value: "dns_server_a_8081"}},
+		},
+		{
+			Type:   fakemetrics.SetGaugeWithLabelsType,
+			Key:    []string{telemetry.ServerPool, telemetry.ActiveServer},
+			Val:    1,
+			Labels: []telemetry.Label{{Name: telemetry.Address, Value: "dns_server_b_8081"}},
+		},
+	}, metrics.AllMetrics())
+
+	require.Equal(t, []ServerStatus{
+		{Address: serverA, ConsecutiveFailures: 1, LastError: errUnavailable.Error()},
+		{Address: serverB, Active: true, Healthy: true},
+	}, p.Status())
+
+	// The recovered server is healthy again but the active server is kept
+	clk.Add(serverRetryInterval)
+	requireSelect(t, p, serverB)
+	require.True(t, p.Status()[0].Healthy)
+}
+
+func TestServerPoolAllServersUnavailable(t *testing.T) {
+	p, clk := newTestServerPool(t, ServerPoolConfig{Addresses: []string{serverA, serverB}})
+
+	requireSelect(t, p, serverA)
+	p.ReportResult(serverA, errUnavailable)
+	p.ReportResult(serverA, errUnavailable)
+	clk.Add(time.Second)
+	p.ReportResult(serverB, errUnavailable)
+
+	// server-a was unavailable twice so it is retried after server-b
+	requireSelect(t, p, serverB)
+
+	// A success makes the server healthy again
+	p.ReportResult(serverA, nil)
+	require.Equal(t, []ServerStatus{
+		{Address: serverA, Healthy: true},
+		{Address: serverB, Active: true, ConsecutiveFailures: 1, LastError: errUnavailable.Error()},
+	}, p.Status())
+	requireSelect(t, p, serverA)
+}
+
+func TestServerPoolRebalances(t *testing.T) {
+	p, clk := newTestServerPool(t, ServerPoolConfig{
+		Addresses:         []string{serverA, serverB},
+		RebalanceInterval: time.Minute,
+	})
+
+	p.c.randIntN = func(n int) int { return n - 1 }
+	requireSelect(t, p, serverB)
+	p.c.randIntN = func(int) int { return 0 }
+
+	// The jitter is picked when the server is selected, so the rebalance
+	// happens at the interval plus a fifth of the interval at most.
+	clk.Add(time.Minute)
+	requireSelect(t, p, serverB)
+	clk.Add(12 * time.Second)
+	requireSelect(t, p, serverA)
+}
+
+func TestServerPoolSRV(t *testing.T) {
+	var records []*net.SRV
+	var lookupErr error
+	var lookups []string
+	p, clk := newTestServerPool(t, ServerPoolConfig{
+		SRVName: "_spire-server._tcp.example.org",
+		lookupSRV: func(_ context.Context, name string) ([]*net.SRV, error) {
+			lookups = append(lookups, name)
+			return records, lookupErr
+		},
+	})
+
+	// No servers discovered
+	_, err := p.Select()
+	require.EqualError(t, err, "no SPIRE Server addresses available")
+
+	// The lookup is not repeated until the refresh interval elapses
+	records = []*net.SRV{
+		{Target: "server-c.", Port: 8081, Priority: 20},
+		{Target: "server-b.", Port: 8081, Priority: 10},
+	}
+	_, err = p.Select()
+	require.Error(t, err)
+	require.Len(t, lookups, 1)
+
+	// Servers with the lowest priority value are preferred
+	clk.Add(srvRefreshInterval)
+	requireSelect(t, p, serverB)
+	require.Equal(t, []string{"_spire-server._tcp.example.org", "_spire-server._tcp.example.org"}, lookups)
+
+	p.ReportResult(serverB, errUnavailable)
+	requireSelect(t, p, serverC)
+
+	// Lookup failures keep the servers already discovered
+	lookupErr = errors.New("oh no")
+	clk.Add(srvRefreshInterval)
+	requireSelect(t, p, serverC)
+
+	// The state of the servers is kept across lookups and servers that are
+	// no longer in the record are dropped
+	lookupErr = nil
+	records = []*net.SRV{
+		{Target: "server-a.", Port: 8081, Priority: 20},
+		{Target: "server-b.", Port: 8081, Priority: 30},
+	}
+	clk.Add(srvRefreshInterval)
+	requireSelect(t, p, serverA)
+	require.Equal(t, []ServerStatus{
+		{Address: serverA, Active: true, Healthy: true},
+		{Address: serverB, Healthy: true, ConsecutiveFailures: 1, LastError: errUnavailable.Error()},
+	}, p.Status())
+}
+
+func TestRetryInterval(t *testing.T) {
+	require.Equal(t, 5*time.Second, retryInterval(1))
+	require.Equal(t, 10*time.Second, retryInterval(2))
+	require.Equal(t, 160*time.Second, retryInterval(6))
+	require.Equal(t, 5*time.Minute, retryInterval(7))
+	require.Equal(t, 5*time.Minute, retryInterval(100))
+}
+
+func TestClientFailsOverBetweenServers(t *testing.T) {
+	svid := &types.X509SVID{
+		Id:        &types.SPIFFEID{TrustDomain: "example.org", Path: "/agent1"},
+		CertChain: [][]byte{{1, 2, 3}},
+		ExpiresAt: 12345,
+	}
+	agentServers := map[string]*fakeAgentServer{
+		"server-a": {err: status.Error(codes.Unavailable, "shutting down")},
+		"server-b": {svid: svid},
+	}
+	listeners := make(map[string]*bufconn.Listener)
+	for name, agentServer := range agentServers {
+		server := grpc.NewServer()
+		agentv1.RegisterAgentServer(server, agentServer)
+		listeners[name] = bufconn.Listen(1024)
+		spiretest.ServeGRPCServerOnListener(t, server, listeners[name])
+	}
+
+	servers, _ := newTestServerPool(t, ServerPoolConfig{
+		Addresses: []string{"passthrough:///server-a", "passthrough:///server-b"},
+	})
+	client := newClient(&Config{
+		Servers:       servers,
+		Log:           log,
+		KeysAndBundle: keysAndBundle,
+		RotMtx:        new(sync.RWMutex),
+		TrustDomain:   trustDomain,
+	})
+	client.dialOpts = []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return listeners[addr].DialContext(ctx)
+		}),
+	}
+	t.Cleanup(client.Release)
+
+	_, err := client.RenewSVID(context.Background(), []byte{1})
+	spiretest.RequireGRPCStatusContains(t, err, codes.Unavailable, "shutting down")
+	require.Empty(t, servers.Active())
+
+	resp, err := client.RenewSVID(context.Background(), []byte{1})
+	require.NoError(t, err)
+	require.Equal(t, &X509SVID{CertChain: []byte{1, 2, 3}, ExpiresAt: 12345}, resp)
+	require.Equal(t, "passthrough:///server-b", servers.Active())
+	require.Equal(t, "passthrough:///server-b", client.connAddr)
+}
+
+func newTestServerPool(t *testing.T, config ServerPoolConfig) (*ServerPool, *clock.Mock) {
+	clk := clock.NewMock(t)
+	config.Clock = clk
+	config.randIntN = func(int) int { return 0 }
+	return NewServerPool(config), clk
+}
+
+func requireSelect(t *testing.T, p *ServerPool, expected string) {
+	t.Helper()
+	address, err := p.Select()
+	require.NoError(t, err)
+	require.Equal(t, expected, address)
+}
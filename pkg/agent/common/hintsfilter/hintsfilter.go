@@ -44,6 +44,25 @@ func FilterIdentities(identities []cache.X509Identity, log logrus.FieldLogger) [
 	return filteredIdentities
 }
 
+// FilterWITIdentities returns WIT identities whose underlying registration
+// entries survive hint deduplication. Same tie-breaking rules as
+// FilterRegistrations.
+func FilterWITIdentities(identities []cache.WITIdentity, log logrus.FieldLogger) []cache.WITIdentity {
+	entries := make([]*common.RegistrationEntry, 0, len(identities))
+	for _, identity := range identities {
+		entries = append(entries, identity.Entry)
+	}
+	entriesToRemove := getEntriesToRemove(entries, log)
+
+	var filteredIdentities []cache.WITIdentity
+	for _, identity := range identities {
+		if _, ok := entriesToRemove[identity.Entry.EntryId]; !ok {
+			filteredIdentities = append(filteredIdentities, identity)
+		}
+	}
+	return filteredIdentities
+}
+
 func getEntriesToRemove(entries []*common.RegistrationEntry, log logrus.FieldLogger) map[string]struct{} {
 	entriesToRemove := make(map[string]struct{})
 	hintsMap := make(map[string]*common.RegistrationEntry)
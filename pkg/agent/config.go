@@ -77,6 +77,18 @@ type Config struct {
 	// Address of SPIRE server
 	ServerAddress string
 
+	// ServerAddresses are the addresses of several SPIRE servers the agent
+	// fails over between. Takes precedence over ServerAddress.
+	ServerAddresses []string
+
+	// ServerSRVName is the name of a DNS SRV record used to discover the
+	// SPIRE servers
+	ServerSRVName string
+
+	// ServerRebalanceInterval is how long the agent sticks to a healthy
+	// server before selecting a server again
+	ServerRebalanceInterval time.Duration
+
 	// SVID key type
 	WorkloadKeyType workloadkey.KeyType
 
@@ -93,6 +105,11 @@ type Config struct {
 	// JWTSVIDCacheMaxSize is a soft limit of max number of JWT-SVIDs that would be stored in cache
 	JWTSVIDCacheMaxSize int
 
+	// PersistWorkloadSVIDs enables the encrypted on-disk snapshot of the
+	// workload X509-SVIDs, which is served at startup until the first
+	// successful sync when the servers are unreachable
+	PersistWorkloadSVIDs bool
+
 	// Trust domain and associated CA bundle
 	TrustDomain spiffeid.TrustDomain
 
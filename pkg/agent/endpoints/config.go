@@ -23,7 +23,10 @@ type WorkloadAPIRateLimitConfig struct {
 	FetchJWTSVID     int
 	FetchX509Bundles int
 	FetchJWTBundles  int
+	FetchWITSVID     int
+	FetchWITBundles  int
 	StreamSecrets    int
+	DeltaSecrets     int
 	FetchSecrets     int
 }
 
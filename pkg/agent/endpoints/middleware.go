@@ -22,6 +22,7 @@ const (
 func Middleware(log logrus.FieldLogger, metrics telemetry.Metrics) middleware.Middleware {
 	return middleware.Chain(
 		middleware.WithLogger(log),
+		middleware.WithTracing(),
 		middleware.WithMetrics(metrics),
 		withPerServiceConnectionMetrics(metrics),
 		middleware.Preprocess(addWatcherPID),
@@ -247,7 +247,7 @@ func TestNewWorkloadRateLimiterBothMethods(t *testing.T) {
 	assert.Contains(t, rl.limiters, workload.MethodFetchJWTSVID)
 }
 
-func TestNewWorkloadRateLimiterAllSixMethods(t *testing.T) {
+func TestNewWorkloadRateLimiterAllMethods(t *testing.T) {
 	log, _ := test.NewNullLogger()
 	metrics := telemetry.Blackhole{}
 	cfg := WorkloadAPIRateLimitConfig{
@@ -257,15 +257,21 @@ func TestNewWorkloadRateLimiterAllSixMethods(t *testing.T) {
 		FetchJWTBundles:  4,
 		StreamSecrets:    5,
 		FetchSecrets:     6,
+		FetchWITSVID:     7,
+		FetchWITBundles:  8,
+		DeltaSecrets:     9,
 	}
 	rl := NewWorkloadRateLimiter(cfg, log, metrics)
 	require.NotNil(t, rl)
-	assert.Len(t, rl.limiters, 6)
+	assert.Len(t, rl.limiters, 9)
 	assert.Contains(t, rl.limiters, workload.MethodFetchX509SVID)
 	assert.Contains(t, rl.limiters, workload.MethodFetchJWTSVID)
 	assert.Contains(t, rl.limiters, workload.MethodFetchX509Bundles)
 	assert.Contains(t, rl.limiters, workload.MethodFetchJWTBundles)
+	assert.Contains(t, rl.limiters, workload.MethodFetchWITSVID)
+	assert.Contains(t, rl.limiters, workload.MethodFetchWITBundles)
 	assert.Contains(t, rl.limiters, sdsv3.MethodStreamSecrets)
+	assert.Contains(t, rl.limiters, sdsv3.MethodDeltaSecrets)
 	assert.Contains(t, rl.limiters, sdsv3.MethodFetchSecrets)
 }
 
@@ -3,12 +3,14 @@ package sdsv3
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"maps"
 	"os"
+	"slices"
 	"sort"
 	"strconv"
 
@@ -34,6 +36,7 @@ const (
 	disableSPIFFECertValidationKey = "disable_spiffe_cert_validation"
 
 	MethodStreamSecrets = "/envoy.service.secret.v3.SecretDiscoveryService/StreamSecrets"
+	MethodDeltaSecrets  = "/envoy.service.secret.v3.SecretDiscoveryService/DeltaSecrets"
 	MethodFetchSecrets  = "/envoy.service.secret.v3.SecretDiscoveryService/FetchSecrets"
 )
 
@@ -253,8 +256,360 @@ func subListChanged(oldSubs []string, newSubs []string) (b bool) {
 	return false
 }
 
-func (h *Handler) DeltaSecrets(secret_v3.SecretDiscoveryService_DeltaSecretsServer) error {
-	return status.Error(codes.Unimplemented, "Method is not implemented")
+// wildcardResourceName is the resource name used by incremental xDS clients to
+// subscribe to (or unsubscribe from) every resource the server has.
+const wildcardResourceName = "*"
+
+func (h *Handler) DeltaSecrets(stream secret_v3.SecretDiscoveryService_DeltaSecretsServer) error {
+	log := rpccontext.Logger(stream.Context())
+
+	selectors, err := h.c.Attestor.Attest(stream.Context())
+	if err != nil {
+		log.WithError(err).Error("Failed to attest the workload")
+		return workloadAttestationFailedError(stream.Context())
+	}
+
+	if err := h.rateLimit(stream.Context(), MethodDeltaSecrets, selectors); err != nil {
+		return err
+	}
+
+	sub, err := h.c.Manager.SubscribeToCacheChanges(stream.Context(), selectors)
+	if err != nil {
+		log.WithError(err).Error("Subscribe to cache changes failed")
+		return err
+	}
+	defer sub.Finish()
+
+	updch := sub.Updates()
+	reqch := make(chan *discovery_v3.DeltaDiscoveryRequest, 1)
+	errch := make(chan error, 1)
+
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				if status.Code(err) == codes.Canceled || errors.Is(err, io.EOF) {
+					err = nil
+				}
+				errch <- err
+				return
+			}
+			reqch <- req
+		}
+	}()
+
+	var versionCounter int64
+	var lastNonce string
+	var upd *cache.X509WorkloadUpdate
+	var state *deltaState
+	// newlySubscribed holds the names subscribed to since the last response
+	// was built.
+	newlySubscribed := make(map[string]struct{})
+	for {
+		select {
+		case newReq := <-reqch:
+			log.WithFields(logrus.Fields{
+				telemetry.SubscribedResourceNames:   newReq.ResourceNamesSubscribe,
+				telemetry.UnsubscribedResourceNames: newReq.ResourceNamesUnsubscribe,
+				telemetry.Nonce:                     newReq.ResponseNonce,
+			}).Debug("Received DeltaSecrets request")
+			h.triggerReceivedHook()
+
+			subscribe := newReq.ResourceNamesSubscribe
+			if state == nil {
+				// The first request on the stream carries the node
+				// information, the type URL and, when reconnecting, the
+				// versions of the resources the client already has.
+				state = newDeltaState(newReq)
+
+				// Following the xDS protocol, a first request that does not
+				// subscribe to anything is a legacy wildcard subscription.
+				if len(subscribe) == 0 {
+					subscribe = []string{wildcardResourceName}
+				}
+			}
+
+			// A request carrying the last sent nonce is an ACK or a NACK of
+			// that response. Requests carrying any other nonce are stale, but
+			// their subscription changes still have to be honored.
+			if newReq.ResponseNonce != "" {
+				switch {
+				case newReq.ResponseNonce != lastNonce:
+					log.WithFields(logrus.Fields{
+						telemetry.Nonce:  newReq.ResponseNonce,
+						telemetry.Expect: lastNonce,
+					}).Warn("Received unexpected nonce; ignoring ACK")
+				case newReq.ErrorDetail != nil:
+					log.WithFields(logrus.Fields{
+						telemetry.Nonce: newReq.ResponseNonce,
+						telemetry.Error: newReq.ErrorDetail.Message,
+					}).Error("Envoy reported errors applying secrets")
+					// The client kept the resources it had before the
+					// rejected response, so forget what was sent in it and
+					// let the next workload update resend them.
+					state.rollback()
+				default:
+					state.ack()
+				}
+			}
+
+			subscribed := state.updateSubscriptions(subscribe, newReq.ResourceNamesUnsubscribe)
+			for _, name := range newReq.ResourceNamesUnsubscribe {
+				delete(newlySubscribed, name)
+			}
+			if len(subscribed) == 0 {
+				continue
+			}
+			maps.Copy(newlySubscribed, subscribed)
+
+			if upd == nil {
+				// Workload update has not been received yet, defer sending updates until then
+				continue
+			}
+
+		case upd = <-updch:
+			versionCounter++
+			if state == nil {
+				// Nothing has been requested yet.
+				continue
+			}
+		case err := <-errch:
+			if err != nil {
+				log.WithError(err).Error("Received error from delta secrets server")
+			}
+			return err
+		}
+
+		resp, err := h.buildDeltaResponse(strconv.FormatInt(versionCounter, 10), state, newlySubscribed, upd)
+		if err != nil {
+			log.WithError(err).Error("Error building delta secrets response")
+			return err
+		}
+		clear(newlySubscribed)
+		if resp == nil {
+			// The client is already up to date.
+			continue
+		}
+
+		log.WithFields(logrus.Fields{
+			telemetry.VersionInfo:          resp.SystemVersionInfo,
+			telemetry.Nonce:                resp.Nonce,
+			telemetry.Count:                len(resp.Resources),
+			telemetry.RemovedResourceNames: resp.RemovedResources,
+		}).Debug("Sending DeltaSecrets response")
+		if err := stream.Send(resp); err != nil {
+			log.WithError(err).Error("Error sending secrets over stream")
+			return err
+		}
+
+		// remember the last nonce
+		lastNonce = resp.Nonce
+	}
+}
+
+// deltaState tracks the subscriptions of an incremental SDS client and the
+// versions of the resources it holds.
+type deltaState struct {
+	node    *core_v3.Node
+	typeURL string
+
+	wildcard   bool
+	subscribed map[string]struct{}
+
+	// known holds the version of each resource the client is believed to
+	// have, keyed by resource name. It is updated as soon as a response is
+	// sent. acked holds the state as of the last ACKed response so it can be
+	// restored when a response is rejected.
+	known map[string]string
+	acked map[string]string
+}
+
+func newDeltaState(req *discovery_v3.DeltaDiscoveryRequest) *deltaState {
+	known := make(map[string]string, len(req.InitialResourceVersions))
+	maps.Copy(known, req.InitialResourceVersions)
+	return &deltaState{
+		node:       req.Node,
+		typeURL:    req.TypeUrl,
+		subscribed: make(map[string]struct{}),
+		known:      known,
+		acked:      maps.Clone(known),
+	}
+}
+
+func (s *deltaState) ack() {
+	s.acked = maps.Clone(s.known)
+}
+
+func (s *deltaState) rollback() {
+	s.known = maps.Clone(s.acked)
+}
+
+// updateSubscriptions applies subscription changes and returns the set of
+// names that were not subscribed to before. A newly enabled wildcard
+// subscription is returned as the wildcard resource name.
+func (s *deltaState) updateSubscriptions(subscribe, unsubscribe []string) map[string]struct{} {
+	newlySubscribed := make(map[string]struct{})
+	for _, name := range subscribe {
+		if name == wildcardResourceName {
+			if !s.wildcard {
+				newlySubscribed[name] = struct{}{}
+			}
+			s.wildcard = true
+			continue
+		}
+		if _, ok := s.subscribed[name]; !ok {
+			newlySubscribed[name] = struct{}{}
+		}
+		s.subscribed[name] = struct{}{}
+	}
+	for _, name := range unsubscribe {
+		if name == wildcardResourceName {
+			s.wildcard = false
+			continue
+		}
+		delete(s.subscribed, name)
+		delete(newlySubscribed, name)
+		// The client drops unsubscribed resources on its own, so there is
+		// no need to notify it of their removal.
+		delete(s.known, name)
+		delete(s.acked, name)
+	}
+	return newlySubscribed
+}
+
+func (s *deltaState) isSubscribed(name string) bool {
+	_, ok := s.subscribed[name]
+	return ok
+}
+
+// buildDeltaResponse builds a response holding the resources that changed
+// since they were last sent to the client, along with the names of the
+// resources the client holds that no longer exist. Newly subscribed names
+// that cannot be served are also reported as removed so the client stops
+// waiting on them. It returns nil if there is nothing to send.
+func (h *Handler) buildDeltaResponse(versionInfo string, state *deltaState, newlySubscribed map[string]struct{}, upd *cache.X509WorkloadUpdate) (*discovery_v3.DeltaDiscoveryResponse, error) {
+	secrets, err := h.buildDeltaSecrets(state, upd)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &discovery_v3.DeltaDiscoveryResponse{
+		TypeUrl:           state.typeURL,
+		SystemVersionInfo: versionInfo,
+	}
+
+	for _, name := range sortedKeys(secrets) {
+		secret := secrets[name]
+		version := resourceVersion(secret)
+		if state.known[name] == version {
+			continue
+		}
+		state.known[name] = version
+		resp.Resources = append(resp.Resources, &discovery_v3.Resource{
+			Name:     name,
+			Version:  version,
+			Resource: secret,
+		})
+	}
+
+	for _, name := range sortedKeys(state.known) {
+		if _, ok := secrets[name]; !ok {
+			delete(state.known, name)
+			resp.RemovedResources = append(resp.RemovedResources, name)
+		}
+	}
+
+	for _, name := range sortedKeys(newlySubscribed) {
+		if _, ok := secrets[name]; !ok && name != wildcardResourceName && !slices.Contains(resp.RemovedResources, name) {
+			resp.RemovedResources = append(resp.RemovedResources, name)
+		}
+	}
+
+	if len(resp.Resources) == 0 && len(resp.RemovedResources) == 0 {
+		return nil, nil
+	}
+
+	if resp.Nonce, err = nextNonce(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// buildDeltaSecrets builds every secret the client is subscribed to, keyed by
+// resource name. Resource names are resolved in the same way as for
+// StreamSecrets.
+func (h *Handler) buildDeltaSecrets(state *deltaState, upd *cache.X509WorkloadUpdate) (map[string]*anypb.Any, error) {
+	builder, err := h.getValidationContextBuilder(state.node, upd)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make(map[string]*anypb.Any)
+	addSecret := func(name string, build func() (*anypb.Any, error)) error {
+		if _, ok := secrets[name]; ok {
+			return nil
+		}
+		secret, err := build()
+		if err != nil {
+			return err
+		}
+		secrets[name] = secret
+		return nil
+	}
+
+	if upd.Bundle != nil {
+		tdID := upd.Bundle.TrustDomain().IDString()
+		if state.wildcard || state.isSubscribed(tdID) {
+			if err := addSecret(tdID, func() (*anypb.Any, error) { return builder.buildOne(tdID, tdID) }); err != nil {
+				return nil, err
+			}
+		}
+		if state.isSubscribed(h.c.DefaultBundleName) {
+			if err := addSecret(h.c.DefaultBundleName, func() (*anypb.Any, error) { return builder.buildOne(h.c.DefaultBundleName, tdID) }); err != nil {
+				return nil, err
+			}
+		}
+		if state.isSubscribed(h.c.DefaultAllBundlesName) {
+			if err := addSecret(h.c.DefaultAllBundlesName, func() (*anypb.Any, error) { return builder.buildAll(h.c.DefaultAllBundlesName) }); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for td := range upd.FederatedBundles {
+		tdID := td.IDString()
+		if state.wildcard || state.isSubscribed(tdID) {
+			if err := addSecret(tdID, func() (*anypb.Any, error) { return builder.buildOne(tdID, tdID) }); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for i, identity := range upd.Identities {
+		if state.wildcard || state.isSubscribed(identity.Entry.SpiffeId) {
+			if err := addSecret(identity.Entry.SpiffeId, func() (*anypb.Any, error) { return buildTLSCertificate(identity, "") }); err != nil {
+				return nil, err
+			}
+		}
+		if i == 0 && state.isSubscribed(h.c.DefaultSVIDName) {
+			if err := addSecret(h.c.DefaultSVIDName, func() (*anypb.Any, error) { return buildTLSCertificate(identity, h.c.DefaultSVIDName) }); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return secrets, nil
+}
+
+// resourceVersion returns a version for the secret derived from its content,
+// so that a secret only changes version when its content changes.
+func resourceVersion(secret *anypb.Any) string {
+	sum := sha256.Sum256(secret.Value)
+	return hex.EncodeToString(sum[:8])
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
 }
 
 func workloadAttestationFailedError(ctx context.Context) error {
@@ -314,7 +669,7 @@ func (h *Handler) buildResponse(versionInfo string, req *discovery_v3.DiscoveryR
 	}
 	returnAllEntries := len(names) == 0
 
-	builder, err := h.getValidationContextBuilder(req, upd)
+	builder, err := h.getValidationContextBuilder(req.Node, upd)
 	if err != nil {
 		return nil, err
 	}
@@ -399,10 +754,10 @@ type validationContextBuilder interface {
 	buildAll(resourceName string) (*anypb.Any, error)
 }
 
-func (h *Handler) getValidationContextBuilder(req *discovery_v3.DiscoveryRequest, upd *cache.X509WorkloadUpdate) (validationContextBuilder, error) {
+func (h *Handler) getValidationContextBuilder(node *core_v3.Node, upd *cache.X509WorkloadUpdate) (validationContextBuilder, error) {
 	federatedBundles := make(map[spiffeid.TrustDomain]*spiffebundle.Bundle)
 	maps.Copy(federatedBundles, upd.FederatedBundles)
-	if !h.isSPIFFECertValidationDisabled(req) && supportsSPIFFEAuthExtension(req) {
+	if !h.isSPIFFECertValidationDisabled(node) && supportsSPIFFEAuthExtension(node) {
 		return newSpiffeBuilder(upd.Bundle, federatedBundles)
 	}
 
@@ -555,8 +910,8 @@ func (b *spiffeBuilder) buildAll(resourceName string) (*anypb.Any, error) {
 	})
 }
 
-func supportsSPIFFEAuthExtension(req *discovery_v3.DiscoveryRequest) bool {
-	if buildVersion := req.Node.GetUserAgentBuildVersion(); buildVersion != nil {
+func supportsSPIFFEAuthExtension(node *core_v3.Node) bool {
+	if buildVersion := node.GetUserAgentBuildVersion(); buildVersion != nil {
 		version := buildVersion.Version
 		return (version.MajorNumber == 1 && version.MinorNumber > 17) || version.MajorNumber > 1
 	}
@@ -564,9 +919,9 @@ func supportsSPIFFEAuthExtension(req *discovery_v3.DiscoveryRequest) bool {
 	return true
 }
 
-func (h *Handler) isSPIFFECertValidationDisabled(req *discovery_v3.DiscoveryRequest) bool {
+func (h *Handler) isSPIFFECertValidationDisabled(node *core_v3.Node) bool {
 	disabled := h.c.DisableSPIFFECertValidation
-	if v, ok := req.Node.GetMetadata().GetFields()[disableSPIFFECertValidationKey]; ok {
+	if v, ok := node.GetMetadata().GetFields()[disableSPIFFECertValidationKey]; ok {
 		// error means that field have some unexpected value
 		// so it would be safer to assume that key doesn't exist in envoy node metadata
 		if override, err := parseBool(v); err == nil {
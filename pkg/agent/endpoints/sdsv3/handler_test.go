@@ -868,6 +868,242 @@ func TestStreamSecretsErrInSubscribeToCacheChanges(t *testing.T) {
 	require.Nil(t, resp)
 }
 
+func TestDeltaSecrets(t *testing.T) {
+	test := setupTest(t)
+	defer test.server.Stop()
+
+	stream, err := test.handler.DeltaSecrets(context.Background())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, stream.CloseSend())
+	}()
+
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResourceNamesSubscribe: []string{"spiffe://domain.test", "spiffe://domain.test/workload"},
+		Node: &core_v3.Node{
+			UserAgentVersionType: userAgentVersionTypeV17,
+		},
+	})
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.SystemVersionInfo)
+	require.NotEmpty(t, resp.Nonce)
+	require.Empty(t, resp.RemovedResources)
+	requireDeltaSecrets(t, resp, tdValidationContext, workloadTLSCertificate1)
+	bundleVersion := resp.Resources[0].Version
+
+	// ACK the response
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResponseNonce: resp.Nonce,
+	})
+
+	// Only the rotated SVID is sent
+	test.setWorkloadUpdate(workloadCert2)
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	requireDeltaSecrets(t, resp, workloadTLSCertificate2)
+	require.NotEqual(t, bundleVersion, resp.Resources[0].Version)
+}
+
+func TestDeltaSecretsWildcard(t *testing.T) {
+	test := setupTest(t)
+	defer test.server.Stop()
+
+	stream, err := test.handler.DeltaSecrets(context.Background())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, stream.CloseSend())
+	}()
+
+	// A first request without subscriptions is a wildcard subscription
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		Node: &core_v3.Node{
+			UserAgentVersionType: userAgentVersionTypeV17,
+		},
+	})
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	requireDeltaSecrets(t, resp, tdValidationContext, workloadTLSCertificate1, fedValidationContext)
+}
+
+func TestDeltaSecretsSubscriptionChanges(t *testing.T) {
+	test := setupTest(t)
+	defer test.server.Stop()
+
+	stream, err := test.handler.DeltaSecrets(context.Background())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, stream.CloseSend())
+	}()
+
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResourceNamesSubscribe: []string{"spiffe://domain.test/workload"},
+	})
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	requireDeltaSecrets(t, resp, workloadTLSCertificate1)
+
+	// Subscribing to another resource only sends the new resource
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResponseNonce:          resp.Nonce,
+		ResourceNamesSubscribe: []string{"spiffe://otherdomain.test"},
+	})
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	requireDeltaSecrets(t, resp, fedValidationContextSpiffeValidator)
+
+	// Unsubscribed resources are no longer sent
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResponseNonce:            resp.Nonce,
+		ResourceNamesUnsubscribe: []string{"spiffe://domain.test/workload"},
+	})
+	test.setWorkloadUpdate(workloadCert2)
+
+	// Subscribing to a resource that does not exist reports it as removed
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResourceNamesSubscribe: []string{"spiffe://domain.test/other"},
+	})
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	require.Empty(t, resp.Resources)
+	require.Equal(t, []string{"spiffe://domain.test/other"}, resp.RemovedResources)
+}
+
+func TestDeltaSecretsRemovedResources(t *testing.T) {
+	test := setupTest(t)
+	defer test.server.Stop()
+
+	stream, err := test.handler.DeltaSecrets(context.Background())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, stream.CloseSend())
+	}()
+
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResourceNamesSubscribe: []string{"spiffe://domain.test", "spiffe://otherdomain.test"},
+	})
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	requireDeltaSecrets(t, resp, tdValidationContextSpiffeValidator, fedValidationContextSpiffeValidator)
+
+	// Stop federating with otherdomain.test
+	test.manager.SetWorkloadUpdate(&cache.X509WorkloadUpdate{
+		Identities: []cache.X509Identity{
+			{
+				Entry: &common.RegistrationEntry{
+					SpiffeId: "spiffe://domain.test/workload",
+				},
+				SVID:       []*x509.Certificate{workloadCert1},
+				PrivateKey: workloadKey,
+			},
+		},
+		Bundle: tdBundle,
+	})
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	require.Empty(t, resp.Resources)
+	require.Equal(t, []string{"spiffe://otherdomain.test"}, resp.RemovedResources)
+}
+
+func TestDeltaSecretsInitialResourceVersions(t *testing.T) {
+	test := setupTest(t)
+	defer test.server.Stop()
+
+	stream, err := test.handler.DeltaSecrets(context.Background())
+	require.NoError(t, err)
+
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResourceNamesSubscribe: []string{"spiffe://domain.test"},
+	})
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	requireDeltaSecrets(t, resp, tdValidationContextSpiffeValidator)
+	require.NoError(t, stream.CloseSend())
+
+	// Reconnect with the version the client already has. Only the resource
+	// the client does not have yet is sent.
+	stream, err = test.handler.DeltaSecrets(context.Background())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, stream.CloseSend())
+	}()
+
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResourceNamesSubscribe: []string{"spiffe://domain.test", "spiffe://domain.test/workload"},
+		InitialResourceVersions: map[string]string{
+			"spiffe://domain.test": resp.Resources[0].Version,
+		},
+	})
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	requireDeltaSecrets(t, resp, workloadTLSCertificate1)
+}
+
+func TestDeltaSecretsNACK(t *testing.T) {
+	test := setupTest(t)
+	defer test.server.Stop()
+
+	stream, err := test.handler.DeltaSecrets(context.Background())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, stream.CloseSend())
+	}()
+
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResourceNamesSubscribe: []string{"spiffe://domain.test", "spiffe://domain.test/workload"},
+		Node: &core_v3.Node{
+			UserAgentVersionType: userAgentVersionTypeV17,
+		},
+	})
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	requireDeltaSecrets(t, resp, tdValidationContext, workloadTLSCertificate1)
+
+	// ACK the response
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResponseNonce: resp.Nonce,
+	})
+
+	test.setWorkloadUpdate(workloadCert2)
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	requireDeltaSecrets(t, resp, workloadTLSCertificate2)
+
+	// Reject the update. A request with an unexpected nonce is ignored.
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResponseNonce: "FOO",
+		ErrorDetail:   &status.Status{Message: "OHNO!"},
+	})
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResponseNonce: resp.Nonce,
+		ErrorDetail:   &status.Status{Message: "OHNO!"},
+	})
+
+	// The rejected SVID is sent again with the next workload update, even
+	// though it did not change. The bundle was ACKed and is not resent.
+	test.setWorkloadUpdate(workloadCert2)
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	requireDeltaSecrets(t, resp, workloadTLSCertificate2)
+}
+
+func TestDeltaSecretsErrInSubscribeToCacheChanges(t *testing.T) {
+	test := setupErrTest(t)
+	defer test.server.Stop()
+
+	stream, err := test.handler.DeltaSecrets(context.Background())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, stream.CloseSend())
+	}()
+
+	resp, err := stream.Recv()
+	require.Error(t, err)
+	require.Nil(t, resp)
+}
+
 func TestFetchSecrets(t *testing.T) {
 	for _, tt := range []struct {
 		name          string
@@ -1254,6 +1490,30 @@ func TestStreamSecretsRateLimit(t *testing.T) {
 	spiretest.RequireGRPCStatusContains(t, err, codes.Unavailable, "rate limit exceeded")
 }
 
+func TestDeltaSecretsRateLimit(t *testing.T) {
+	rl := newFakeRateLimiter(1)
+	// Use a caller PID that is not the agent's own so the agent exemption does
+	// not apply and rate limiting is actually enforced.
+	test := setupTestWithConfigAsPID(t, Config{RateLimiter: rl}, os.Getpid()+1)
+	defer test.cleanup()
+
+	// First stream open is within the limit.
+	stream, err := test.handler.DeltaSecrets(context.Background())
+	require.NoError(t, err)
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{ResourceNamesSubscribe: []string{"default"}})
+	require.NoError(t, stream.CloseSend())
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	// Second stream open exhausts the limit.
+	stream2, err := test.handler.DeltaSecrets(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream2.Send(&discovery_v3.DeltaDiscoveryRequest{}))
+	_, err = stream2.Recv()
+	require.Error(t, err)
+	spiretest.RequireGRPCStatusContains(t, err, codes.Unavailable, "rate limit exceeded")
+}
+
 func TestFetchSecretsRateLimit(t *testing.T) {
 	rl := newFakeRateLimiter(1)
 	// Use a caller PID that is not the agent's own so the agent exemption does
@@ -1423,6 +1683,17 @@ func (h *handlerTest) sendAndWait(stream secret_v3.SecretDiscoveryService_Stream
 	}
 }
 
+func (h *handlerTest) sendDeltaAndWait(stream secret_v3.SecretDiscoveryService_DeltaSecretsClient, req *discovery_v3.DeltaDiscoveryRequest) {
+	require.NoError(h.t, stream.Send(req))
+	timer := time.NewTimer(time.Second)
+	defer timer.Stop()
+	select {
+	case <-h.received:
+	case <-timer.C:
+		assert.Fail(h.t, "timed out waiting for request to be received")
+	}
+}
+
 type FakeAttestor []*common.Selector
 
 func (a FakeAttestor) Attest(context.Context) ([]*common.Selector, error) {
@@ -1463,6 +1734,8 @@ func (m *FakeManager) SubscribeToCacheChanges(_ context.Context, selectors cache
 	m.next++
 	m.subs[key] = updch
 	return NewFakeSubscriber(updch, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
 		delete(m.subs, key)
 		close(updch)
 	}), nil
@@ -1553,3 +1826,16 @@ func requireSecrets(t *testing.T, resp *discovery_v3.DiscoveryResponse, expected
 
 	spiretest.RequireProtoListEqual(t, expectedSecrets, actualSecrets)
 }
+
+func requireDeltaSecrets(t *testing.T, resp *discovery_v3.DeltaDiscoveryResponse, expectedSecrets ...*tls_v3.Secret) {
+	var actualSecrets []*tls_v3.Secret
+	for _, resource := range resp.Resources {
+		secret := new(tls_v3.Secret)
+		require.NoError(t, resource.Resource.UnmarshalTo(secret))
+		require.Equal(t, secret.Name, resource.Name)
+		require.NotEmpty(t, resource.Version)
+		actualSecrets = append(actualSecrets, secret)
+	}
+
+	spiretest.RequireProtoListEqual(t, expectedSecrets, actualSecrets)
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-jose/go-jose/v4"
 	"github.com/sirupsen/logrus"
 	"github.com/spiffe/go-spiffe/v2/bundle/spiffebundle"
 	"github.com/spiffe/go-spiffe/v2/proto/spiffe/workload"
@@ -34,6 +35,7 @@ import (
 
 type Manager interface {
 	SubscribeToCacheChanges(ctx context.Context, key cache.Selectors) (cache.Subscriber[cache.X509WorkloadUpdate], error)
+	SubscribeToWITChanges(ctx context.Context, key cache.Selectors) (cache.Subscriber[cache.WITWorkloadUpdate], error)
 	MatchingRegistrationEntries(selectors []*common.Selector) []*common.RegistrationEntry
 	FetchJWTSVID(ctx context.Context, entry *common.RegistrationEntry, audience []string) (*client.JWTSVID, error)
 	FetchWorkloadUpdate([]*common.Selector) *cache.X509WorkloadUpdate
@@ -53,6 +55,8 @@ const (
 	MethodFetchJWTSVID     = "/SpiffeWorkloadAPI/FetchJWTSVID"
 	MethodFetchX509Bundles = "/SpiffeWorkloadAPI/FetchX509Bundles"
 	MethodFetchJWTBundles  = "/SpiffeWorkloadAPI/FetchJWTBundles"
+	MethodFetchWITSVID     = "/SpiffeWorkloadAPI/FetchWITSVID"
+	MethodFetchWITBundles  = "/SpiffeWorkloadAPI/FetchWITBundles"
 )
 
 type Config struct {
@@ -321,12 +325,84 @@ func (h *Handler) FetchX509Bundles(_ *workload.X509BundlesRequest, stream worklo
 	}
 }
 
-func (h *Handler) FetchWITSVID(_ *workload.WITSVIDRequest, stream workload.SpiffeWorkloadAPI_FetchWITSVIDServer) error {
-	return status.Error(codes.Unimplemented, "fetching WIT-SVIDs is not implemented")
+// FetchWITSVID processes request for WIT-SVIDs. In case of multiple fetched SVIDs with same hint, the SVID that has the oldest
+// associated entry will be returned.
+func (h *Handler) FetchWITSVID(req *workload.WITSVIDRequest, stream workload.SpiffeWorkloadAPI_FetchWITSVIDServer) error {
+	ctx := stream.Context()
+	start := time.Now()
+	log := rpccontext.Logger(ctx)
+
+	if req.SpiffeId != "" {
+		if _, err := spiffeid.FromString(req.SpiffeId); err != nil {
+			log.WithField(telemetry.SPIFFEID, req.SpiffeId).WithError(err).Error("Invalid requested SPIFFE ID")
+			return status.Errorf(codes.InvalidArgument, "invalid requested SPIFFE ID: %v", err)
+		}
+	}
+
+	selectors, err := h.c.Attestor.Attest(ctx)
+	if err != nil {
+		loggerWithContextInfo(ctx, log, start, err).Error("Workload attestation failed")
+		return workloadAttestationFailedError(ctx)
+	}
+
+	if err := h.rateLimit(ctx, MethodFetchWITSVID, selectors); err != nil {
+		return err
+	}
+
+	subscriber, err := h.c.Manager.SubscribeToWITChanges(ctx, selectors)
+	if err != nil {
+		loggerWithContextInfo(ctx, log, start, err).Error("Subscribe to cache changes failed")
+		return err
+	}
+	defer subscriber.Finish()
+
+	for {
+		select {
+		case update := <-subscriber.Updates():
+			update.Identities = hintsfilter.FilterWITIdentities(update.Identities, log)
+			if err := h.sendWITSVIDResponse(update, stream, req.SpiffeId, selectors, log, start); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
 }
 
+// FetchWITBundles processes request for WIT bundles
 func (h *Handler) FetchWITBundles(_ *workload.WITBundlesRequest, stream workload.SpiffeWorkloadAPI_FetchWITBundlesServer) error {
-	return status.Error(codes.Unimplemented, "fetching WIT bundles is not implemented")
+	ctx := stream.Context()
+	start := time.Now()
+	log := rpccontext.Logger(ctx)
+
+	selectors, err := h.c.Attestor.Attest(ctx)
+	if err != nil {
+		loggerWithContextInfo(ctx, log, start, err).Error("Workload attestation failed")
+		return workloadAttestationFailedError(ctx)
+	}
+
+	if err := h.rateLimit(ctx, MethodFetchWITBundles, selectors); err != nil {
+		return err
+	}
+
+	subscriber, err := h.c.Manager.SubscribeToCacheChanges(ctx, selectors)
+	if err != nil {
+		loggerWithContextInfo(ctx, log, start, err).Error("Subscribe to cache changes failed")
+		return err
+	}
+	defer subscriber.Finish()
+
+	var previousResp *workload.WITBundlesResponse
+	for {
+		select {
+		case update := <-subscriber.Updates():
+			if previousResp, err = h.sendWITBundlesResponse(update, stream, selectors, log, previousResp, start); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
 }
 
 func workloadAttestationFailedError(ctx context.Context) error {
@@ -518,7 +594,7 @@ func composeJWTBundlesResponse(update *cache.X509WorkloadUpdate) (*workload.JWTB
 	}
 
 	bundles := make(map[string][]byte)
-	jwksBytes, err := bundleutil.Marshal(update.Bundle, bundleutil.NoX509SVIDKeys(), bundleutil.StandardJWKS())
+	jwksBytes, err := bundleutil.MarshalJWTSVIDBundle(update.Bundle, bundleutil.StandardJWKS())
 	if err != nil {
 		return nil, err
 	}
@@ -526,7 +602,7 @@ func composeJWTBundlesResponse(update *cache.X509WorkloadUpdate) (*workload.JWTB
 
 	if update.HasIdentity() {
 		for _, federatedBundle := range update.FederatedBundles {
-			jwksBytes, err := bundleutil.Marshal(federatedBundle, bundleutil.NoX509SVIDKeys(), bundleutil.StandardJWKS())
+			jwksBytes, err := bundleutil.MarshalJWTSVIDBundle(federatedBundle, bundleutil.StandardJWKS())
 			if err != nil {
 				return nil, err
 			}
@@ -539,6 +615,134 @@ func composeJWTBundlesResponse(update *cache.X509WorkloadUpdate) (*workload.JWTB
 	}, nil
 }
 
+func (h *Handler) sendWITSVIDResponse(update *cache.WITWorkloadUpdate, stream workload.SpiffeWorkloadAPI_FetchWITSVIDServer, spiffeID string, selectors []*common.Selector, log logrus.FieldLogger, start time.Time) error {
+	ctx := stream.Context()
+	// The agent health check currently exercises the Workload API.
+	// Only log if it is not the agent itself.
+	quietLogging := isAgent(ctx)
+
+	identities := update.Identities
+	if spiffeID != "" {
+		identities = slices.DeleteFunc(slices.Clone(identities), func(identity cache.WITIdentity) bool {
+			return identity.Entry.SpiffeId != spiffeID
+		})
+	}
+
+	if len(identities) == 0 {
+		if !quietLogging {
+			h.logNoIdentityIssued(ctx, log, selectors, start)
+		}
+		return status.Error(codes.PermissionDenied, "no identity issued")
+	}
+
+	log = log.WithField(telemetry.Registered, true)
+
+	resp, err := composeWITSVIDResponse(identities)
+	if err != nil {
+		log.WithError(err).Error("Could not serialize WIT-SVID response")
+		return status.Errorf(codes.Unavailable, "could not serialize response: %v", err)
+	}
+
+	if err := stream.Send(resp); err != nil {
+		loggerWithContextInfo(ctx, log, start, err).Error("Failed to send WIT-SVID response")
+		return err
+	}
+
+	log = log.WithField(telemetry.Count, len(resp.Svids))
+
+	// log and emit telemetry on each SVID
+	// a response has already been sent so nothing is
+	// blocked on this logic
+	if !quietLogging {
+		for i, svid := range resp.Svids {
+			ttl := time.Until(identities[i].ExpiresAt)
+			log.WithFields(logrus.Fields{
+				telemetry.SPIFFEID: svid.SpiffeId,
+				telemetry.TTL:      ttl.Seconds(),
+			}).Debug("Fetched WIT-SVID")
+		}
+	}
+
+	return nil
+}
+
+func composeWITSVIDResponse(identities []cache.WITIdentity) (*workload.WITSVIDResponse, error) {
+	resp := new(workload.WITSVIDResponse)
+	resp.Svids = []*workload.WITSVID{}
+
+	for _, identity := range identities {
+		id := identity.Entry.SpiffeId
+
+		keyData, err := json.Marshal(jose.JSONWebKey{Key: identity.PrivateKey})
+		if err != nil {
+			return nil, fmt.Errorf("marshal key for %v: %w", id, err)
+		}
+
+		resp.Svids = append(resp.Svids, &workload.WITSVID{
+			SpiffeId:   id,
+			WitSvid:    identity.Token,
+			WitSvidKey: string(keyData),
+			Hint:       identity.Entry.Hint,
+		})
+	}
+
+	return resp, nil
+}
+
+func (h *Handler) sendWITBundlesResponse(update *cache.X509WorkloadUpdate, stream workload.SpiffeWorkloadAPI_FetchWITBundlesServer, selectors []*common.Selector, log logrus.FieldLogger, previousResponse *workload.WITBundlesResponse, start time.Time) (*workload.WITBundlesResponse, error) {
+	ctx := stream.Context()
+	if !h.c.AllowUnauthenticatedVerifiers && !update.HasIdentity() {
+		h.logNoIdentityIssued(ctx, log, selectors, start)
+		return nil, status.Error(codes.PermissionDenied, "no identity issued")
+	}
+
+	resp, err := composeWITBundlesResponse(update)
+	if err != nil {
+		log.WithError(err).Error("Could not serialize WIT bundle response")
+		return nil, status.Errorf(codes.Unavailable, "could not serialize response: %v", err)
+	}
+
+	if proto.Equal(resp, previousResponse) {
+		return previousResponse, nil
+	}
+
+	if err := stream.Send(resp); err != nil {
+		loggerWithContextInfo(ctx, log, start, err).Error("Failed to send WIT bundle response")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func composeWITBundlesResponse(update *cache.X509WorkloadUpdate) (*workload.WITBundlesResponse, error) {
+	if update.Bundle == nil {
+		// This should be purely defensive since the cache should always supply
+		// a bundle.
+		return nil, errors.New("bundle not available")
+	}
+
+	bundles := make(map[string]string)
+	jwks, err := bundleutil.MarshalWITSVIDBundle(update.Bundle, bundleutil.StandardJWKS())
+	if err != nil {
+		return nil, err
+	}
+	bundles[update.Bundle.TrustDomain().IDString()] = jwks
+
+	if update.HasIdentity() {
+		for _, federatedBundle := range update.FederatedBundles {
+			jwks, err := bundleutil.MarshalWITSVIDBundle(federatedBundle, bundleutil.StandardJWKS())
+			if err != nil {
+				return nil, err
+			}
+			bundles[federatedBundle.TrustDomain().IDString()] = jwks
+		}
+	}
+
+	return &workload.WITBundlesResponse{
+		Bundles: bundles,
+	}, nil
+}
+
 // isAgent returns true if the caller PID from the provided context is the
 // agent's process ID.
 func isAgent(ctx context.Context) bool {
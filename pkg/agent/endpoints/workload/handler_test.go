@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-jose/go-jose/v4"
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/spiffe/go-spiffe/v2/bundle/spiffebundle"
@@ -30,6 +31,7 @@ import (
 	"github.com/spiffe/spire/test/grpctest"
 	"github.com/spiffe/spire/test/spiretest"
 	"github.com/spiffe/spire/test/testca"
+	"github.com/spiffe/spire/test/testkey"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
@@ -1001,6 +1003,12 @@ func TestRateLimitAgentExemption(t *testing.T) {
 			Bundle:     ca.Bundle(),
 		},
 	}
+	witUpdates := []*cache.WITWorkloadUpdate{
+		{
+			Identities: []cache.WITIdentity{witIdentity(t, workloadID, "id0", "")},
+			Bundle:     ca.Bundle(),
+		},
+	}
 
 	for _, tt := range []struct {
 		name   string
@@ -1046,6 +1054,26 @@ func TestRateLimitAgentExemption(t *testing.T) {
 				require.NotNil(t, resp)
 			},
 		},
+		{
+			name: "FetchWITSVID",
+			invoke: func(t *testing.T, ctx context.Context, c workloadPB.SpiffeWorkloadAPIClient) {
+				stream, err := c.FetchWITSVID(ctx, &workloadPB.WITSVIDRequest{})
+				require.NoError(t, err)
+				resp, err := stream.Recv()
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+			},
+		},
+		{
+			name: "FetchWITBundles",
+			invoke: func(t *testing.T, ctx context.Context, c workloadPB.SpiffeWorkloadAPIClient) {
+				stream, err := c.FetchWITBundles(ctx, &workloadPB.WITBundlesRequest{})
+				require.NoError(t, err)
+				resp, err := stream.Recv()
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+			},
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			rl := &rejectAllRateLimiter{}
@@ -1053,6 +1081,7 @@ func TestRateLimitAgentExemption(t *testing.T) {
 				CA:          ca,
 				Identities:  identities,
 				Updates:     updates,
+				WITUpdates:  witUpdates,
 				AsPID:       os.Getpid(),
 				RateLimiter: rl,
 			}
@@ -1118,6 +1147,24 @@ func TestRateLimitEnforced(t *testing.T) {
 				return err
 			},
 		},
+		{
+			name: "FetchWITSVID",
+			invoke: func(t *testing.T, ctx context.Context, c workloadPB.SpiffeWorkloadAPIClient) error {
+				stream, err := c.FetchWITSVID(ctx, &workloadPB.WITSVIDRequest{})
+				require.NoError(t, err)
+				_, err = stream.Recv()
+				return err
+			},
+		},
+		{
+			name: "FetchWITBundles",
+			invoke: func(t *testing.T, ctx context.Context, c workloadPB.SpiffeWorkloadAPIClient) error {
+				stream, err := c.FetchWITBundles(ctx, &workloadPB.WITBundlesRequest{})
+				require.NoError(t, err)
+				_, err = stream.Recv()
+				return err
+			},
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			rl := &rejectAllRateLimiter{}
@@ -1509,6 +1556,377 @@ func TestFetchJWTBundles_SpuriousUpdates(t *testing.T) {
 		})
 }
 
+func TestFetchWITSVID(t *testing.T) {
+	ca := testca.New(t, td)
+
+	identity1 := witIdentity(t, spiffeid.RequireFromPath(td, "/one"), "id1", "internal")
+	identity2 := witIdentity(t, spiffeid.RequireFromPath(td, "/two"), "id2", "")
+	identity3 := witIdentity(t, spiffeid.RequireFromPath(td, "/three"), "id3", "internal")
+	identity3.Entry.CreatedAt = time.Now().Unix() + 3600
+
+	for _, tt := range []struct {
+		name         string
+		updates      []*cache.WITWorkloadUpdate
+		spiffeID     string
+		attestErr    error
+		managerErr   error
+		asPID        int
+		expectCode   codes.Code
+		expectMsg    string
+		expectedResp []cache.WITIdentity
+		expectLogs   []spiretest.LogEntry
+	}{
+		{
+			name:       "spiffe_id set, but not a valid SPIFFE ID",
+			spiffeID:   "foo",
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "invalid requested SPIFFE ID: scheme is missing or invalid",
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Invalid requested SPIFFE ID",
+					Data: logrus.Fields{
+						"service":       "WorkloadAPI",
+						"method":        "FetchWITSVID",
+						"spiffe_id":     "foo",
+						logrus.ErrorKey: "scheme is missing or invalid",
+					},
+				},
+			},
+		},
+		{
+			name:       "no identity issued",
+			updates:    []*cache.WITWorkloadUpdate{{}},
+			expectCode: codes.PermissionDenied,
+			expectMsg:  "no identity issued",
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "No identity issued",
+					Data: logrus.Fields{
+						"registered": "false",
+						"service":    "WorkloadAPI",
+						"method":     "FetchWITSVID",
+					},
+				},
+			},
+		},
+		{
+			name:       "no identity issued (healthcheck)",
+			updates:    []*cache.WITWorkloadUpdate{{}},
+			asPID:      os.Getpid(),
+			expectCode: codes.PermissionDenied,
+			expectMsg:  "no identity issued",
+		},
+		{
+			name: "identity found but unexpected SPIFFE ID",
+			updates: []*cache.WITWorkloadUpdate{
+				{Identities: []cache.WITIdentity{identity1, identity2}},
+			},
+			spiffeID:   spiffeid.RequireFromPath(td, "/unexpected").String(),
+			expectCode: codes.PermissionDenied,
+			expectMsg:  "no identity issued",
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "No identity issued",
+					Data: logrus.Fields{
+						"registered": "false",
+						"service":    "WorkloadAPI",
+						"method":     "FetchWITSVID",
+					},
+				},
+			},
+		},
+		{
+			name:       "attest error",
+			attestErr:  errors.New("ohno"),
+			expectCode: codes.Unavailable,
+			expectMsg:  "workload attestation failed",
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Workload attestation failed",
+					Data: logrus.Fields{
+						"service":       "WorkloadAPI",
+						"method":        "FetchWITSVID",
+						logrus.ErrorKey: "ohno",
+					},
+				},
+			},
+		},
+		{
+			name:       "subscribe to cache changes error",
+			managerErr: errors.New("err"),
+			expectCode: codes.Unknown,
+			expectMsg:  "err",
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Subscribe to cache changes failed",
+					Data: logrus.Fields{
+						"service":       "WorkloadAPI",
+						"method":        "FetchWITSVID",
+						logrus.ErrorKey: "err",
+					},
+				},
+			},
+		},
+		{
+			name: "success all",
+			updates: []*cache.WITWorkloadUpdate{
+				{Identities: []cache.WITIdentity{identity1, identity2}},
+			},
+			expectCode:   codes.OK,
+			expectedResp: []cache.WITIdentity{identity1, identity2},
+		},
+		{
+			name: "success specific",
+			updates: []*cache.WITWorkloadUpdate{
+				{Identities: []cache.WITIdentity{identity1, identity2}},
+			},
+			spiffeID:     identity2.Entry.SpiffeId,
+			expectCode:   codes.OK,
+			expectedResp: []cache.WITIdentity{identity2},
+		},
+		{
+			name: "identities with duplicated hints",
+			updates: []*cache.WITWorkloadUpdate{
+				{Identities: []cache.WITIdentity{identity1, identity2, identity3}},
+			},
+			expectCode:   codes.OK,
+			expectedResp: []cache.WITIdentity{identity1, identity2},
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.WarnLevel,
+					Message: "Ignoring entry with duplicate hint",
+					Data: logrus.Fields{
+						telemetry.RegistrationID: "id3",
+						telemetry.Hint:           "internal",
+						telemetry.Method:         "FetchWITSVID",
+						telemetry.Service:        "WorkloadAPI",
+					},
+				},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			params := testParams{
+				CA:         ca,
+				WITUpdates: tt.updates,
+				AttestErr:  tt.attestErr,
+				ManagerErr: tt.managerErr,
+				ExpectLogs: tt.expectLogs,
+				AsPID:      tt.asPID,
+			}
+			runTest(t, params,
+				func(ctx context.Context, client workloadPB.SpiffeWorkloadAPIClient) {
+					stream, err := client.FetchWITSVID(ctx, &workloadPB.WITSVIDRequest{
+						SpiffeId: tt.spiffeID,
+					})
+					require.NoError(t, err)
+
+					resp, err := stream.Recv()
+					spiretest.RequireGRPCStatus(t, err, tt.expectCode, tt.expectMsg)
+					if tt.expectCode != codes.OK {
+						assert.Nil(t, resp)
+						return
+					}
+
+					require.Len(t, resp.Svids, len(tt.expectedResp))
+					for i, svid := range resp.Svids {
+						expected := tt.expectedResp[i]
+						assert.Equal(t, expected.Entry.SpiffeId, svid.SpiffeId)
+						assert.Equal(t, expected.Entry.Hint, svid.Hint)
+						assert.Equal(t, expected.Token, svid.WitSvid)
+
+						var jwk jose.JSONWebKey
+						require.NoError(t, jwk.UnmarshalJSON([]byte(svid.WitSvidKey)))
+						assert.False(t, jwk.IsPublic())
+						assert.Equal(t, expected.PrivateKey.Public(), jwk.Public().Key)
+					}
+				})
+		})
+	}
+}
+
+func TestFetchWITBundles(t *testing.T) {
+	ca := testca.New(t, td)
+
+	indent := func(in string) string {
+		buf := new(bytes.Buffer)
+		require.NoError(t, json.Indent(buf, []byte(in), "", "    "))
+		return buf.String()
+	}
+
+	witKey := testkey.NewEC256(t)
+	bundle := spiffebundle.New(td)
+	require.NoError(t, bundle.AddWITAuthority("wit-key", witKey.Public()))
+	// X.509 and JWT authorities must never leak into WIT bundles
+	for _, authority := range ca.X509Authorities() {
+		bundle.AddX509Authority(authority)
+	}
+	for keyID, key := range ca.JWTAuthorities() {
+		require.NoError(t, bundle.AddJWTAuthority(keyID, key))
+	}
+	bundleJWKS, err := json.Marshal(jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{{Key: witKey.Public(), KeyID: "wit-key"}},
+	})
+	require.NoError(t, err)
+
+	federatedWITKey := testkey.NewEC256(t)
+	federatedBundle := spiffebundle.New(td2)
+	require.NoError(t, federatedBundle.AddWITAuthority("federated-wit-key", federatedWITKey.Public()))
+	federatedBundleJWKS, err := json.Marshal(jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{{Key: federatedWITKey.Public(), KeyID: "federated-wit-key"}},
+	})
+	require.NoError(t, err)
+
+	identity := witIdentity(t, workloadID, "id1", "")
+	x509Identity := identityFromX509SVID(ca.CreateX509SVID(workloadID), "id1")
+
+	for _, tt := range []struct {
+		name                          string
+		updates                       []*cache.X509WorkloadUpdate
+		attestErr                     error
+		managerErr                    error
+		expectCode                    codes.Code
+		expectMsg                     string
+		expectResp                    *workloadPB.WITBundlesResponse
+		expectLogs                    []spiretest.LogEntry
+		allowUnauthenticatedVerifiers bool
+	}{
+		{
+			name:       "no identity issued",
+			updates:    []*cache.X509WorkloadUpdate{{}},
+			expectCode: codes.PermissionDenied,
+			expectMsg:  "no identity issued",
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "No identity issued",
+					Data: logrus.Fields{
+						"registered": "false",
+						"service":    "WorkloadAPI",
+						"method":     "FetchWITBundles",
+					},
+				},
+			},
+		},
+		{
+			name:       "attest error",
+			attestErr:  errors.New("ohno"),
+			expectCode: codes.Unavailable,
+			expectMsg:  "workload attestation failed",
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Workload attestation failed",
+					Data: logrus.Fields{
+						"service":       "WorkloadAPI",
+						"method":        "FetchWITBundles",
+						logrus.ErrorKey: "ohno",
+					},
+				},
+			},
+		},
+		{
+			name:       "subscribe to cache changes error",
+			managerErr: errors.New("err"),
+			expectCode: codes.Unknown,
+			expectMsg:  "err",
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Subscribe to cache changes failed",
+					Data: logrus.Fields{
+						"service":       "WorkloadAPI",
+						"method":        "FetchWITBundles",
+						logrus.ErrorKey: "err",
+					},
+				},
+			},
+		},
+		{
+			name: "cache update unexpectedly missing bundle",
+			updates: []*cache.X509WorkloadUpdate{
+				{Identities: []cache.X509Identity{x509Identity}},
+			},
+			expectCode: codes.Unavailable,
+			expectMsg:  "could not serialize response: bundle not available",
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Could not serialize WIT bundle response",
+					Data: logrus.Fields{
+						"service":       "WorkloadAPI",
+						"method":        "FetchWITBundles",
+						logrus.ErrorKey: "bundle not available",
+					},
+				},
+			},
+		},
+		{
+			name: "success",
+			updates: []*cache.X509WorkloadUpdate{
+				{
+					Identities: []cache.X509Identity{x509Identity},
+					Bundle:     bundle,
+					FederatedBundles: map[spiffeid.TrustDomain]*spiffebundle.Bundle{
+						federatedBundle.TrustDomain(): federatedBundle,
+					},
+				},
+			},
+			expectCode: codes.OK,
+			expectResp: &workloadPB.WITBundlesResponse{
+				Bundles: map[string]string{
+					bundle.TrustDomain().IDString():          indent(string(bundleJWKS)),
+					federatedBundle.TrustDomain().IDString(): indent(string(federatedBundleJWKS)),
+				},
+			},
+		},
+		{
+			name:                          "when allowed to fetch without identity",
+			allowUnauthenticatedVerifiers: true,
+			updates: []*cache.X509WorkloadUpdate{
+				{
+					Bundle: bundle,
+					FederatedBundles: map[spiffeid.TrustDomain]*spiffebundle.Bundle{
+						federatedBundle.TrustDomain(): federatedBundle,
+					},
+				},
+			},
+			expectCode: codes.OK,
+			expectResp: &workloadPB.WITBundlesResponse{
+				Bundles: map[string]string{
+					bundle.TrustDomain().IDString(): indent(string(bundleJWKS)),
+				},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			params := testParams{
+				CA:                            ca,
+				Updates:                       tt.updates,
+				WITUpdates:                    []*cache.WITWorkloadUpdate{{Identities: []cache.WITIdentity{identity}}},
+				AttestErr:                     tt.attestErr,
+				ManagerErr:                    tt.managerErr,
+				ExpectLogs:                    tt.expectLogs,
+				AllowUnauthenticatedVerifiers: tt.allowUnauthenticatedVerifiers,
+			}
+			runTest(t, params,
+				func(ctx context.Context, client workloadPB.SpiffeWorkloadAPIClient) {
+					stream, err := client.FetchWITBundles(ctx, &workloadPB.WITBundlesRequest{})
+					require.NoError(t, err)
+
+					resp, err := stream.Recv()
+					spiretest.RequireGRPCStatus(t, err, tt.expectCode, tt.expectMsg)
+					spiretest.RequireProtoEqual(t, tt.expectResp, resp)
+				})
+		})
+	}
+}
+
 func TestValidateJWTSVID(t *testing.T) {
 	ca := testca.New(t, td)
 	ca2 := testca.New(t, td2)
@@ -1793,6 +2211,7 @@ type testParams struct {
 	CA                            *testca.CA
 	Identities                    []cache.X509Identity
 	Updates                       []*cache.X509WorkloadUpdate
+	WITUpdates                    []*cache.WITWorkloadUpdate
 	Selectors                     []*common.Selector
 	LogSelectors                  []string
 	AttestErr                     error
@@ -1811,6 +2230,7 @@ func runTest(t *testing.T, params testParams, fn func(ctx context.Context, clien
 		ca:         params.CA,
 		identities: params.Identities,
 		updates:    params.Updates,
+		witUpdates: params.WITUpdates,
 		err:        params.ManagerErr,
 	}
 
@@ -1856,6 +2276,7 @@ type FakeManager struct {
 	ca          *testca.CA
 	identities  []cache.X509Identity
 	updates     []*cache.X509WorkloadUpdate
+	witUpdates  []*cache.WITWorkloadUpdate
 	subscribers atomic.Int32
 	err         error
 }
@@ -1891,6 +2312,14 @@ func (m *FakeManager) SubscribeToCacheChanges(context.Context, cache.Selectors)
 	return newFakeSubscriber(m, m.updates), nil
 }
 
+func (m *FakeManager) SubscribeToWITChanges(context.Context, cache.Selectors) (cache.Subscriber[cache.WITWorkloadUpdate], error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.subscribers.Add(1)
+	return newFakeSubscriber(m, m.witUpdates), nil
+}
+
 func (m *FakeManager) FetchWorkloadUpdate([]*common.Selector) *cache.X509WorkloadUpdate {
 	if len(m.updates) == 0 {
 		return &cache.X509WorkloadUpdate{}
@@ -1906,14 +2335,14 @@ func (m *FakeManager) subscriberDone() {
 	m.subscribers.Add(-1)
 }
 
-type fakeSubscriber struct {
+type fakeSubscriber[U any] struct {
 	m      *FakeManager
-	ch     chan *cache.X509WorkloadUpdate
+	ch     chan *U
 	cancel context.CancelFunc
 }
 
-func newFakeSubscriber(m *FakeManager, updates []*cache.X509WorkloadUpdate) *fakeSubscriber {
-	ch := make(chan *cache.X509WorkloadUpdate)
+func newFakeSubscriber[U any](m *FakeManager, updates []*U) *fakeSubscriber[U] {
+	ch := make(chan *U)
 	ctx, cancel := context.WithCancel(context.Background())
 	go func() {
 		for _, update := range updates {
@@ -1925,18 +2354,18 @@ func newFakeSubscriber(m *FakeManager, updates []*cache.X509WorkloadUpdate) *fak
 		}
 		<-ctx.Done()
 	}()
-	return &fakeSubscriber{
+	return &fakeSubscriber[U]{
 		m:      m,
 		ch:     ch,
 		cancel: cancel,
 	}
 }
 
-func (s *fakeSubscriber) Updates() <-chan *cache.X509WorkloadUpdate {
+func (s *fakeSubscriber[U]) Updates() <-chan *U {
 	return s.ch
 }
 
-func (s *fakeSubscriber) Finish() {
+func (s *fakeSubscriber[U]) Finish() {
 	s.cancel()
 	s.m.subscriberDone()
 }
@@ -1950,6 +2379,15 @@ func (a *FakeAttestor) Attest(context.Context) ([]*common.Selector, error) {
 	return a.selectors, a.err
 }
 
+func witIdentity(t *testing.T, id spiffeid.ID, entryID, hint string) cache.WITIdentity {
+	return cache.WITIdentity{
+		Entry:      &common.RegistrationEntry{SpiffeId: id.String(), Hint: hint, EntryId: entryID},
+		Token:      "wit-svid-" + entryID,
+		PrivateKey: testkey.NewEC256(t),
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+}
+
 func identityFromX509SVID(svid *x509svid.SVID, entryID string) cache.X509Identity {
 	return cache.X509Identity{
 		Entry:      &common.RegistrationEntry{SpiffeId: svid.ID.String(), Hint: svid.Hint, EntryId: entryID},
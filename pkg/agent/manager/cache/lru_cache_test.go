@@ -110,6 +110,26 @@ func TestLRUCacheCountSVIDs(t *testing.T) {
 	require.Equal(t, 1, cache.CountSVIDs())
 }
 
+func TestLRUCacheCachedIdentities(t *testing.T) {
+	cache := newTestLRUCache(t)
+
+	foo := makeRegistrationEntry("FOO", "A")
+	bar := makeRegistrationEntry("BAR", "B")
+	updateEntries := &UpdateEntries{
+		Bundles:             makeBundles(bundleV1),
+		RegistrationEntries: makeRegistrationEntries(foo, bar),
+	}
+	cache.UpdateEntries(updateEntries, nil)
+	svid := &X509SVID{Chain: []*x509.Certificate{{}}}
+	cache.UpdateSVIDs(map[string]*X509SVID{foo.EntryId: svid})
+
+	// Entries without an SVID are returned as well
+	require.Equal(t, []X509Identity{
+		{Entry: bar},
+		{Entry: foo, SVID: svid.Chain},
+	}, cache.CachedIdentities())
+}
+
 func TestLRUCacheCountRecords(t *testing.T) {
 	cache := newTestLRUCache(t)
 	// populate the cache with FOO and BAR without SVIDS
@@ -19,3 +19,9 @@ func sortIdentities(identities []X509Identity) {
 		return identities[a].Entry.EntryId < identities[b].Entry.EntryId
 	})
 }
+
+func sortWITIdentities(identities []WITIdentity) {
+	sort.Slice(identities, func(a, b int) bool {
+		return identities[a].Entry.EntryId < identities[b].Entry.EntryId
+	})
+}
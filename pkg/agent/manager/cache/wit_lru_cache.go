@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"context"
+	"crypto"
+	"time"
+
+	"github.com/andres-erbsen/clock"
+	"github.com/sirupsen/logrus"
+	"github.com/spiffe/go-spiffe/v2/bundle/spiffebundle"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	agentmetrics "github.com/spiffe/spire/pkg/common/telemetry/agent"
+	"github.com/spiffe/spire/proto/spire/common"
+)
+
+// WITSVID holds onto the WIT-SVID token and the private key it is bound to.
+type WITSVID struct {
+	Token      string
+	PrivateKey crypto.Signer
+	IssuedAt   time.Time
+	NotAfter   time.Time
+}
+
+func (s WITSVID) ExpiresAt() time.Time {
+	return s.NotAfter
+}
+
+// WITIdentity holds the data for a single WIT workload identity.
+type WITIdentity struct {
+	Entry      *common.RegistrationEntry
+	Token      string
+	PrivateKey crypto.Signer
+	ExpiresAt  time.Time
+}
+
+// WITWorkloadUpdate is used to convey WIT workload information to cache subscribers.
+type WITWorkloadUpdate struct {
+	Identities       []WITIdentity
+	Bundle           *spiffebundle.Bundle
+	FederatedBundles map[spiffeid.TrustDomain]*spiffebundle.Bundle
+}
+
+func (u *WITWorkloadUpdate) HasIdentity() bool {
+	return len(u.Identities) > 0
+}
+
+// WITSVIDLRUCache wraps LRUCache with WIT-specific operations.
+type WITSVIDLRUCache struct {
+	*LRUCache[WITSVID, WITWorkloadUpdate]
+}
+
+type WITLRUCacheConfig struct {
+	Log              logrus.FieldLogger
+	TrustDomain      spiffeid.TrustDomain
+	Bundle           *Bundle
+	Metrics          telemetry.Metrics
+	SvidCacheMaxSize int
+	Clk              clock.Clock
+}
+
+// NewWITLRUCache creates a cache for WIT-SVIDs. Unlike X509-SVIDs, WIT-SVIDs
+// are not prefetched; they are only minted for entries that have an active
+// subscriber, since most workloads never ask for one.
+func NewWITLRUCache(config WITLRUCacheConfig) *WITSVIDLRUCache {
+	return &WITSVIDLRUCache{
+		LRUCache: NewLRUCache(LRUCacheConfig[WITSVID, WITWorkloadUpdate]{
+			Log:              config.Log,
+			TrustDomain:      config.TrustDomain,
+			Bundle:           config.Bundle,
+			Metrics:          config.Metrics,
+			SvidCacheMaxSize: config.SvidCacheMaxSize,
+			Clk:              config.Clk,
+			SVIDType:         "WIT",
+			BuildUpdate:      buildWITWorkloadUpdate,
+			ShouldPrefetch: func(*common.RegistrationEntry) bool {
+				return false
+			},
+		}),
+	}
+}
+
+func (c *WITSVIDLRUCache) UpdateWITSVIDs(svids map[string]*WITSVID) {
+	c.LRUCache.UpdateSVIDs(svids)
+}
+
+// Identities is only used by manager tests.
+func (c *WITSVIDLRUCache) Identities() []WITIdentity {
+	c.LRUCache.mu.RLock()
+	defer c.LRUCache.mu.RUnlock()
+
+	out := make([]WITIdentity, 0, len(c.LRUCache.records))
+	for _, record := range c.LRUCache.records {
+		svid, ok := c.LRUCache.svids[record.entry.EntryId]
+		if !ok {
+			continue
+		}
+		out = append(out, makeNewWITIdentity(record, svid))
+	}
+	sortWITIdentities(out)
+	return out
+}
+
+// TaintWITSVIDs removes all cached WIT-SVIDs that were signed by any of the
+// provided authorities. Entries with active subscribers are picked up again
+// on the next SVID sync and get a fresh WIT-SVID.
+func (c *WITSVIDLRUCache) TaintWITSVIDs(ctx context.Context, taintedWITAuthorities map[string]struct{}) {
+	defer func() { agentmetrics.SetSVIDMapSize(c.LRUCache.metrics, c.LRUCache.svidType, c.CountSVIDs()) }()
+
+	counter := telemetry.StartCall(c.LRUCache.metrics, telemetry.CacheManager, agentmetrics.CacheTypeWorkload, telemetry.ProcessTaintedWITSVIDs)
+	defer counter.Done(nil)
+
+	c.LRUCache.mu.Lock()
+	defer c.LRUCache.mu.Unlock()
+
+	taintedSVIDs := 0
+	for entryID, svid := range c.LRUCache.svids {
+		keyID, err := getKeyIDFromSVIDToken(svid.Token)
+		if err != nil {
+			c.LRUCache.log.WithError(err).
+				WithField(telemetry.RegistrationID, entryID).
+				Error("Could not get key ID from cached WIT-SVID")
+			continue
+		}
+
+		if _, tainted := taintedWITAuthorities[keyID]; tainted {
+			delete(c.LRUCache.svids, entryID)
+			taintedSVIDs++
+		}
+
+		select {
+		case <-ctx.Done():
+			c.LRUCache.log.WithError(ctx.Err()).Warn("Context cancelled, exiting process of tainting WIT-SVIDs in cache")
+			return
+		default:
+		}
+	}
+
+	agentmetrics.AddCacheManagerTaintedWITSVIDsSample(c.LRUCache.metrics, agentmetrics.CacheTypeWorkload, float32(taintedSVIDs))
+	c.LRUCache.log.WithField(telemetry.TaintedWITSVIDs, taintedSVIDs).Info("Tainted WIT-SVIDs")
+}
+
+func buildWITWorkloadUpdate(cache *LRUCache[WITSVID, WITWorkloadUpdate], set selectorSet) *WITWorkloadUpdate {
+	records, recordsDone := cache.getRecordsForSelectors(set)
+	defer recordsDone()
+
+	var identities []WITIdentity
+	if len(records) > 0 {
+		identities = make([]WITIdentity, 0, len(records))
+		for record := range records {
+			if svid, ok := cache.svids[record.entry.EntryId]; ok {
+				identities = append(identities, makeNewWITIdentity(record, svid))
+			}
+		}
+		sortWITIdentities(identities)
+		if len(identities) == 0 {
+			identities = nil
+		}
+	}
+
+	entries := make([]*common.RegistrationEntry, 0, len(identities))
+	for _, identity := range identities {
+		entries = append(entries, identity.Entry)
+	}
+
+	return &WITWorkloadUpdate{
+		Bundle:           cache.bundles[cache.trustDomain],
+		FederatedBundles: cache.GatherFederatedBundles(entries),
+		Identities:       identities,
+	}
+}
+
+func makeNewWITIdentity(record *lruCacheRecord, svid *WITSVID) WITIdentity {
+	return WITIdentity{
+		Entry:      record.entry,
+		Token:      svid.Token,
+		PrivateKey: svid.PrivateKey,
+		ExpiresAt:  svid.NotAfter,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto"
 	"crypto/x509"
+	"maps"
 	"sort"
 	"time"
 
@@ -108,6 +109,33 @@ func (c *X509SVIDLRUCache) UpdateX509SVIDs(svids map[string]*X509SVID) {
 	c.LRUCache.UpdateSVIDs(svids)
 }
 
+// X509SVIDs returns the cached X509-SVIDs keyed by registration entry ID.
+func (c *X509SVIDLRUCache) X509SVIDs() map[string]*X509SVID {
+	c.LRUCache.mu.RLock()
+	defer c.LRUCache.mu.RUnlock()
+
+	return maps.Clone(c.LRUCache.svids)
+}
+
+// CachedIdentities returns an identity for every cached registration entry,
+// sorted by entry ID. The SVID and private key are unset for entries that
+// do not have an X509-SVID cached yet.
+func (c *X509SVIDLRUCache) CachedIdentities() []X509Identity {
+	c.LRUCache.mu.RLock()
+	defer c.LRUCache.mu.RUnlock()
+
+	out := make([]X509Identity, 0, len(c.LRUCache.records))
+	for _, record := range c.LRUCache.records {
+		identity := X509Identity{Entry: record.entry}
+		if svid, ok := c.LRUCache.svids[record.entry.EntryId]; ok {
+			identity = makeNewX509Identity(record, svid)
+		}
+		out = append(out, identity)
+	}
+	sortIdentities(out)
+	return out
+}
+
 // Identities is only used by manager tests.
 func (c *X509SVIDLRUCache) Identities() []X509Identity {
 	c.LRUCache.mu.RLock()
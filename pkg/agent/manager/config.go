@@ -9,6 +9,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	"github.com/spiffe/spire/pkg/agent/catalog"
+	"github.com/spiffe/spire/pkg/agent/client"
 	managerCache "github.com/spiffe/spire/pkg/agent/manager/cache"
 	"github.com/spiffe/spire/pkg/agent/manager/storecache"
 	"github.com/spiffe/spire/pkg/agent/plugin/keymanager"
@@ -17,9 +18,11 @@ import (
 	"github.com/spiffe/spire/pkg/agent/svid"
 	"github.com/spiffe/spire/pkg/agent/trustbundlesources"
 	"github.com/spiffe/spire/pkg/agent/workloadkey"
+	"github.com/spiffe/spire/pkg/common/agentstatus"
 	"github.com/spiffe/spire/pkg/common/rotationutil"
 	"github.com/spiffe/spire/pkg/common/telemetry"
 	"github.com/spiffe/spire/pkg/common/tlspolicy"
+	"github.com/spiffe/spire/proto/spire/common"
 )
 
 // Config holds a cache manager configuration
@@ -34,6 +37,7 @@ type Config struct {
 	Log                      logrus.FieldLogger
 	Metrics                  telemetry.Metrics
 	ServerAddr               string
+	Servers                  *client.ServerPool
 	Storage                  storage.Storage
 	TrustBundleSources       trustbundlesources.Bundle
 	RebootstrapMode          string
@@ -50,6 +54,19 @@ type Config struct {
 	RotationStrategy         *rotationutil.RotationStrategy
 	TLSPolicy                tlspolicy.Policy
 
+	// Plugins are the plugins loaded by the agent, reported to the server
+	// with the agent status.
+	Plugins []*common.AgentPlugin
+
+	// StatusReportInterval is the interval at which the agent status is
+	// reported to the server.
+	StatusReportInterval time.Duration
+
+	// PersistWorkloadSVIDs enables the encrypted workload SVID snapshot,
+	// which is restored at startup so workloads can be served while the
+	// servers are unreachable.
+	PersistWorkloadSVIDs bool
+
 	// Clk is the clock the manager will use to get time
 	Clk clock.Clock
 }
@@ -68,6 +85,10 @@ func newManager(c *Config) *manager {
 		c.RotationInterval = svid.DefaultRotatorInterval
 	}
 
+	if c.StatusReportInterval == 0 {
+		c.StatusReportInterval = agentstatus.DefaultReportInterval
+	}
+
 	if c.Clk == nil {
 		c.Clk = clock.New()
 	}
@@ -82,6 +103,15 @@ func newManager(c *Config) *manager {
 		Clk:              c.Clk,
 	})
 
+	witCache := managerCache.NewWITLRUCache(managerCache.WITLRUCacheConfig{
+		Log:              logger,
+		TrustDomain:      c.TrustDomain,
+		Bundle:           c.Bundle,
+		Metrics:          c.Metrics,
+		SvidCacheMaxSize: c.X509SVIDCacheMaxSize,
+		Clk:              c.Clk,
+	})
+
 	jwtCache := managerCache.NewJWTSVIDCache(logger, c.Metrics, c.JWTSVIDCacheMaxSize)
 
 	bundleCache := managerCache.NewBundleCache(c.TrustDomain, c.Bundle)
@@ -94,6 +124,7 @@ func newManager(c *Config) *manager {
 		SVIDKey:          c.SVIDKey,
 		BundleStream:     bundleCache.SubscribeToBundleChanges(),
 		ServerAddr:       c.ServerAddr,
+		Servers:          c.Servers,
 		TrustDomain:      c.TrustDomain,
 		Interval:         c.RotationInterval,
 		Clk:              c.Clk,
@@ -108,6 +139,7 @@ func newManager(c *Config) *manager {
 		bundleCache:    bundleCache,
 		x509Cache:      x509Cache,
 		jwtCache:       jwtCache,
+		witCache:       witCache,
 		c:              c,
 		mtx:            new(sync.RWMutex),
 		svid:           svidRotator,
@@ -118,6 +150,7 @@ func newManager(c *Config) *manager {
 
 		processedTaintedX509Authorities: make(map[string]struct{}),
 		processedTaintedJWTAuthorities:  make(map[string]struct{}),
+		processedTaintedWITAuthorities:  make(map[string]struct{}),
 	}
 
 	return m
@@ -2,6 +2,7 @@ package manager
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/x509"
 	"errors"
 	"fmt"
@@ -52,6 +53,10 @@ type Manager interface {
 	// for a particular set of selectors.
 	SubscribeToCacheChanges(ctx context.Context, key cache.Selectors) (cache.Subscriber[cache.X509WorkloadUpdate], error)
 
+	// SubscribeToWITChanges returns a Subscriber on which WIT-SVID updates are sent
+	// for a particular set of selectors.
+	SubscribeToWITChanges(ctx context.Context, key cache.Selectors) (cache.Subscriber[cache.WITWorkloadUpdate], error)
+
 	// SubscribeToSVIDChanges returns a new observer.Stream on which svid.State instances are received
 	// each time an SVID rotation finishes.
 	SubscribeToSVIDChanges() observer.Stream
@@ -74,6 +79,10 @@ type Manager interface {
 	// selectors are a subset of the passed selectors.
 	MatchingRegistrationEntries(selectors []*common.Selector) []*common.RegistrationEntry
 
+	// CachedX509Identities returns every cached registration entry along with
+	// its X509-SVID, if one has been minted.
+	CachedX509Identities() []cache.X509Identity
+
 	// FetchWorkloadUpdates gets the latest workload update for the selectors
 	FetchWorkloadUpdate(selectors []*common.Selector) *cache.X509WorkloadUpdate
 
@@ -87,6 +96,9 @@ type Manager interface {
 	// CountJWTSVIDs returns the amount of JWT SVIDs on memory
 	CountJWTSVIDs() int
 
+	// CountWITSVIDs returns the amount of WIT SVIDs on memory
+	CountWITSVIDs() int
+
 	// CountSVIDStoreX509SVIDs returns the amount of x509 SVIDs on SVIDStore in-memory cache
 	CountSVIDStoreX509SVIDs() int
 
@@ -114,6 +126,7 @@ type manager struct {
 	bundleCache *cache.BundleCache
 	x509Cache   *cache.X509SVIDLRUCache
 	jwtCache    *cache.JWTSVIDCache
+	witCache    *cache.WITSVIDLRUCache
 	svid        svid.Rotator
 
 	storage storage.Storage
@@ -132,6 +145,24 @@ type manager struct {
 	// Saves last success sync
 	lastSync time.Time
 
+	// Saves the error of the last sync, reported to the server with the
+	// agent status. Protected by mtx.
+	lastSyncError string
+
+	// servingWorkloadSnapshot is true while workloads are served from the
+	// workload SVID snapshot restored at startup, until the first successful
+	// sync. Protected by mtx.
+	servingWorkloadSnapshot bool
+
+	// workloadSnapshotRestoredKeyVersion is the key version of the snapshot
+	// found at startup. workloadSnapshotKey is the key that seals the
+	// snapshots written since, of the next version. They and
+	// workloadSnapshotDigest are only used at startup and by the synchronizer,
+	// which runs one sync at a time.
+	workloadSnapshotRestoredKeyVersion uint64
+	workloadSnapshotKey                *workloadSnapshotKey
+	workloadSnapshotDigest             [sha256.Size]byte
+
 	// Cache for 'storable' SVIDs
 	svidStoreCache *storecache.Cache
 
@@ -149,6 +180,10 @@ type manager struct {
 	// processedTaintedJWTAuthorities holds all the already processed tainted JWT Authorities
 	// to prevent processing them again.
 	processedTaintedJWTAuthorities map[string]struct{}
+
+	// processedTaintedWITAuthorities holds all the already processed tainted WIT Authorities
+	// to prevent processing them again.
+	processedTaintedWITAuthorities map[string]struct{}
 }
 
 func (m *manager) Initialize(ctx context.Context) error {
@@ -164,11 +199,17 @@ func (m *manager) Initialize(ctx context.Context) error {
 	m.syncedEntries = make(map[string]*common.RegistrationEntry)
 	m.syncedBundles = make(map[string]*common.Bundle)
 
-	// Post agent status with version information to the server
+	restoredSnapshot := m.c.PersistWorkloadSVIDs && m.restoreWorkloadSnapshot(ctx)
+
+	// Post agent status with version information to the server. The version
+	// cannot change while the agent runs, so it is only posted here.
 	if err := m.client.PostStatus(ctx, version.Version()); err != nil {
 		// Log the error but don't fail initialization - the server may not support this yet
 		m.c.Log.WithField(telemetry.AgentVersion, version.Version()).WithError(err).Error("Failed to post agent status")
 	}
+	// The client logs failures. The status is reported again on the next
+	// interval.
+	_ = m.client.PostStatusReport(ctx, m.statusReport())
 
 	err := m.synchronize(ctx)
 	if nodeutil.ShouldAgentReattest(err) {
@@ -179,6 +220,12 @@ func (m *manager) Initialize(ctx context.Context) error {
 		m.c.Log.WithError(err).Error("Agent is banned: removing SVID and shutting down")
 		m.deleteSVID()
 	}
+	if err != nil && restoredSnapshot && !nodeutil.ShouldAgentReattest(err) && !nodeutil.ShouldAgentShutdown(err) && !x509util.IsUnknownAuthorityError(err) {
+		// Keep serving the restored SVIDs; the synchronizer retries in the
+		// background.
+		m.c.Log.WithError(err).Warn("Initial synchronization failed; serving workloads from the workload SVID snapshot")
+		return nil
+	}
 	return err
 }
 
@@ -191,6 +238,7 @@ func (m *manager) Run(ctx context.Context) error {
 			m.runSyncSVIDs,
 			m.runSVIDObserver,
 			m.runBundleObserver,
+			m.runStatusReporter,
 			m.svid.Run)
 
 		switch {
@@ -220,6 +268,10 @@ func (m *manager) SubscribeToCacheChanges(ctx context.Context, selectors cache.S
 	return m.x509Cache.SubscribeToWorkloadUpdates(ctx, selectors)
 }
 
+func (m *manager) SubscribeToWITChanges(ctx context.Context, selectors cache.Selectors) (cache.Subscriber[cache.WITWorkloadUpdate], error) {
+	return m.witCache.SubscribeToWorkloadUpdates(ctx, selectors)
+}
+
 func (m *manager) SubscribeToSVIDChanges() observer.Stream {
 	return m.svid.Subscribe()
 }
@@ -244,6 +296,10 @@ func (m *manager) MatchingRegistrationEntries(selectors []*common.Selector) []*c
 	return m.x509Cache.MatchingRegistrationEntries(selectors)
 }
 
+func (m *manager) CachedX509Identities() []cache.X509Identity {
+	return m.x509Cache.CachedIdentities()
+}
+
 func (m *manager) CountX509SVIDs() int {
 	return m.x509Cache.CountSVIDs()
 }
@@ -252,6 +308,10 @@ func (m *manager) CountJWTSVIDs() int {
 	return m.jwtCache.CountJWTSVIDs()
 }
 
+func (m *manager) CountWITSVIDs() int {
+	return m.witCache.CountSVIDs()
+}
+
 func (m *manager) CountSVIDStoreX509SVIDs() int {
 	return m.svidStoreCache.CountX509SVIDs()
 }
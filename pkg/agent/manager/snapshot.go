@@ -0,0 +1,406 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spiffe/go-spiffe/v2/bundle/spiffebundle"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/agent/manager/cache"
+	"github.com/spiffe/spire/pkg/agent/plugin/keymanager"
+	"github.com/spiffe/spire/pkg/agent/storage"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	telemetry_agent "github.com/spiffe/spire/pkg/common/telemetry/agent"
+	"github.com/spiffe/spire/proto/spire/common"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// workloadSnapshotKeyIDPrefix prefixes the ID of the KeyManager keys the
+	// workload snapshot encryption keys are derived from.
+	workloadSnapshotKeyIDPrefix = "agent-workload-snapshot-"
+
+	// workloadSnapshotContext is signed, along with the key version, with the
+	// KeyManager key to derive the encryption key. It is also used as the
+	// additional data when sealing.
+	workloadSnapshotContext = "spire-agent workload snapshot v1"
+
+	// workloadSnapshotSaltSize is the size of the random salt the encryption
+	// key of each snapshot is derived with.
+	workloadSnapshotSaltSize = 32
+)
+
+// sealedWorkloadSnapshot is the form the workload SVID snapshot is stored in.
+// The key version identifies the KeyManager key the encryption key is derived
+// from, and the salt, random for each snapshot, is used in the derivation.
+type sealedWorkloadSnapshot struct {
+	KeyVersion uint64 `json:"key_version"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// workloadSnapshotKey is the secret the encryption keys of the snapshots are
+// derived from, for a given key version.
+type workloadSnapshotKey struct {
+	version uint64
+	secret  []byte
+}
+
+// workloadSnapshot is the content of the workload SVID snapshot. It holds the
+// cached X509-SVIDs that have not expired, the registration entries they were
+// issued for and the federated bundles.
+type workloadSnapshot struct {
+	Entries [][]byte                        `json:"entries"`
+	SVIDs   map[string]workloadSnapshotSVID `json:"svids"`
+	Bundles map[string][]byte               `json:"bundles"`
+}
+
+type workloadSnapshotSVID struct {
+	CertChain  [][]byte `json:"cert_chain"`
+	PrivateKey []byte   `json:"private_key"`
+}
+
+// restoreWorkloadSnapshot restores the workload SVID snapshot into the cache
+// so workloads can be served before the first synchronization with the
+// server succeeds. It returns true if any SVID was restored.
+func (m *manager) restoreWorkloadSnapshot(ctx context.Context) bool {
+	sealed, err := m.storage.LoadWorkloadSnapshot()
+	switch {
+	case errors.Is(err, storage.ErrNotCached):
+		return false
+	case err != nil:
+		m.c.Log.WithError(err).Warn("Could not load workload SVID snapshot")
+		return false
+	}
+
+	var envelope sealedWorkloadSnapshot
+	if err := json.Unmarshal(sealed, &envelope); err != nil {
+		m.c.Log.WithError(err).Warn("Discarding workload SVID snapshot that could not be restored")
+		m.deleteWorkloadSnapshot()
+		return false
+	}
+	m.workloadSnapshotRestoredKeyVersion = envelope.KeyVersion
+
+	key, err := m.getWorkloadSnapshotKey(ctx, envelope.KeyVersion)
+	if err != nil {
+		m.c.Log.WithError(err).Warn("Could not open workload SVID snapshot")
+		return false
+	}
+
+	update, svids, err := m.openWorkloadSnapshot(key, &envelope)
+	if err != nil {
+		m.c.Log.WithError(err).Warn("Discarding workload SVID snapshot that could not be restored")
+		m.deleteWorkloadSnapshot()
+		return false
+	}
+	if len(svids) == 0 {
+		m.c.Log.Info("Discarding workload SVID snapshot since all of its SVIDs have expired")
+		m.deleteWorkloadSnapshot()
+		return false
+	}
+
+	m.bundleCache.Update(update.Bundles)
+	m.x509Cache.UpdateEntries(update, nil)
+	m.x509Cache.UpdateX509SVIDs(svids)
+
+	m.setServingWorkloadSnapshot(true)
+	telemetry_agent.IncrementWorkloadSnapshotRestoredSVIDs(m.c.Metrics, len(svids))
+	m.c.Log.WithField(telemetry.Count, len(svids)).Info("Restored workload SVID snapshot; serving workloads from it until the first synchronization succeeds")
+	return true
+}
+
+// storeWorkloadSnapshot persists the workload SVID snapshot. The snapshot is
+// only written when its content changed since it was last written.
+func (m *manager) storeWorkloadSnapshot(ctx context.Context) {
+	plaintext, err := m.marshalWorkloadSnapshot()
+	if err != nil {
+		m.c.Log.WithError(err).Warn("Could not marshal workload SVID snapshot")
+		return
+	}
+
+	digest := sha256.Sum256(plaintext)
+	if digest == m.workloadSnapshotDigest {
+		return
+	}
+
+	sealed, err := m.sealWorkloadSnapshot(ctx, plaintext)
+	if err != nil {
+		m.c.Log.WithError(err).Warn("Could not seal workload SVID snapshot")
+		return
+	}
+
+	if err := m.storage.StoreWorkloadSnapshot(sealed); err != nil {
+		m.c.Log.WithError(err).Warn("Could not store workload SVID snapshot")
+		return
+	}
+	m.workloadSnapshotDigest = digest
+}
+
+func (m *manager) deleteWorkloadSnapshot() {
+	if err := m.storage.DeleteWorkloadSnapshot(); err != nil {
+		m.c.Log.WithError(err).Warn("Could not remove workload SVID snapshot")
+	}
+}
+
+func (m *manager) setServingWorkloadSnapshot(serving bool) {
+	m.mtx.Lock()
+	changed := m.servingWorkloadSnapshot != serving
+	m.servingWorkloadSnapshot = serving
+	m.mtx.Unlock()
+
+	if changed {
+		telemetry_agent.SetServingWorkloadSnapshot(m.c.Metrics, serving)
+		if !serving {
+			m.c.Log.Info("Synchronized with SPIRE Server; no longer serving workloads from the workload SVID snapshot")
+		}
+	}
+}
+
+func (m *manager) marshalWorkloadSnapshot() ([]byte, error) {
+	now := m.clk.Now()
+
+	var snapshot workloadSnapshot
+	svids := m.x509Cache.X509SVIDs()
+	for _, entry := range m.x509Cache.Entries() {
+		svid, ok := svids[entry.EntryId]
+		if !ok || len(svid.Chain) == 0 || !now.Before(svid.Chain[0].NotAfter) {
+			continue
+		}
+
+		entryBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal entry %q: %w", entry.EntryId, err)
+		}
+		privateKey, err := x509.MarshalPKCS8PrivateKey(svid.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal private key for entry %q: %w", entry.EntryId, err)
+		}
+		var certChain [][]byte
+		for _, cert := range svid.Chain {
+			certChain = append(certChain, cert.Raw)
+		}
+
+		snapshot.Entries = append(snapshot.Entries, entryBytes)
+		if snapshot.SVIDs == nil {
+			snapshot.SVIDs = make(map[string]workloadSnapshotSVID)
+		}
+		snapshot.SVIDs[entry.EntryId] = workloadSnapshotSVID{
+			CertChain:  certChain,
+			PrivateKey: privateKey,
+		}
+	}
+
+	// The bundle for the agent trust domain is not part of the snapshot since
+	// it is kept up to date in the agent storage.
+	for td, bundle := range m.bundleCache.Bundles() {
+		if td == m.c.TrustDomain {
+			continue
+		}
+		bundleBytes, err := bundle.Marshal()
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal bundle for %q: %w", td, err)
+		}
+		if snapshot.Bundles == nil {
+			snapshot.Bundles = make(map[string][]byte)
+		}
+		snapshot.Bundles[td.Name()] = bundleBytes
+	}
+
+	return json.Marshal(snapshot)
+}
+
+// sealWorkloadSnapshot encrypts the snapshot with a key derived from the
+// current snapshot key and a random salt. The snapshot key is rotated once per
+// agent run: the first snapshot sealed by the agent uses a new key version.
+func (m *manager) sealWorkloadSnapshot(ctx context.Context, plaintext []byte) ([]byte, error) {
+	if m.workloadSnapshotKey == nil {
+		key, err := m.generateWorkloadSnapshotKey(ctx, m.workloadSnapshotRestoredKeyVersion+1)
+		if err != nil {
+			return nil, err
+		}
+		m.workloadSnapshotKey = key
+	}
+
+	salt := make([]byte, workloadSnapshotSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := newWorkloadSnapshotAEAD(m.workloadSnapshotKey, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(sealedWorkloadSnapshot{
+		KeyVersion: m.workloadSnapshotKey.version,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plaintext, []byte(workloadSnapshotContext)),
+	})
+}
+
+func (m *manager) openWorkloadSnapshot(key *workloadSnapshotKey, envelope *sealedWorkloadSnapshot) (*cache.UpdateEntries, map[string]*cache.X509SVID, error) {
+	aead, err := newWorkloadSnapshotAEAD(key, envelope.Salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(envelope.Nonce) != aead.NonceSize() {
+		return nil, nil, errors.New("snapshot nonce has an invalid size")
+	}
+	plaintext, err := aead.Open(nil, envelope.Nonce, envelope.Ciphertext, []byte(workloadSnapshotContext))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt snapshot: %w", err)
+	}
+
+	var snapshot workloadSnapshot
+	if err := json.Unmarshal(plaintext, &snapshot); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal snapshot: %w", err)
+	}
+
+	update := &cache.UpdateEntries{
+		Bundles: map[spiffeid.TrustDomain]*spiffebundle.Bundle{
+			m.c.TrustDomain: m.bundleCache.Bundle(),
+		},
+		RegistrationEntries: make(map[string]*common.RegistrationEntry),
+	}
+	for name, bundleBytes := range snapshot.Bundles {
+		td, err := spiffeid.TrustDomainFromString(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid trust domain %q: %w", name, err)
+		}
+		if td == m.c.TrustDomain {
+			continue
+		}
+		bundle, err := spiffebundle.Parse(td, bundleBytes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse bundle for %q: %w", td, err)
+		}
+		update.Bundles[td] = bundle
+	}
+
+	now := m.clk.Now()
+	svids := make(map[string]*cache.X509SVID)
+	for _, entryBytes := range snapshot.Entries {
+		entry := new(common.RegistrationEntry)
+		if err := proto.Unmarshal(entryBytes, entry); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal entry: %w", err)
+		}
+		snapshotSVID, ok := snapshot.SVIDs[entry.EntryId]
+		if !ok {
+			continue
+		}
+		svid, err := parseWorkloadSnapshotSVID(snapshotSVID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse SVID for entry %q: %w", entry.EntryId, err)
+		}
+		if !now.Before(svid.ExpiresAt()) {
+			continue
+		}
+		update.RegistrationEntries[entry.EntryId] = entry
+		svids[entry.EntryId] = svid
+	}
+
+	return update, svids, nil
+}
+
+// getWorkloadSnapshotKey returns the snapshot key of the given version, held
+// by the KeyManager.
+func (m *manager) getWorkloadSnapshotKey(ctx context.Context, version uint64) (*workloadSnapshotKey, error) {
+	key, err := m.c.Catalog.GetKeyManager().GetKey(ctx, workloadSnapshotKeyID(version))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workload snapshot key: %w", err)
+	}
+	return deriveWorkloadSnapshotKey(key, version)
+}
+
+// generateWorkloadSnapshotKey generates the snapshot key of the given version
+// in the KeyManager.
+func (m *manager) generateWorkloadSnapshotKey(ctx context.Context, version uint64) (*workloadSnapshotKey, error) {
+	key, err := m.c.Catalog.GetKeyManager().GenerateKey(ctx, workloadSnapshotKeyID(version), keymanager.RSA2048)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate workload snapshot key: %w", err)
+	}
+	return deriveWorkloadSnapshotKey(key, version)
+}
+
+// workloadSnapshotKeyID returns the ID of the KeyManager key for the given
+// key version. KeyManagers cannot delete keys, so the versions alternate
+// between two IDs: generating the key of a new version replaces the key of
+// the version before the previous one, which no stored snapshot uses since
+// the snapshot restored at startup is sealed with the previous version.
+func workloadSnapshotKeyID(version uint64) string {
+	return workloadSnapshotKeyIDPrefix + strconv.FormatUint(version%2, 10)
+}
+
+// deriveWorkloadSnapshotKey derives the snapshot key secret from an RSA key
+// held by the KeyManager: RSASSA-PKCS1-v1_5 signatures are deterministic, so
+// signing a fixed message yields the same secret for as long as the KeyManager
+// keeps the key, without the secret ever being written to disk. The key
+// version is part of the signed message, so each version has its own secret.
+func deriveWorkloadSnapshotKey(key keymanager.Key, version uint64) (*workloadSnapshotKey, error) {
+	if _, ok := key.Public().(*rsa.PublicKey); !ok {
+		return nil, fmt.Errorf("unexpected workload snapshot key type %T", key.Public())
+	}
+
+	digest := sha256.Sum256(fmt.Appendf(nil, "%s key version %d", workloadSnapshotContext, version))
+	signature, err := key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign with workload snapshot key: %w", err)
+	}
+	return &workloadSnapshotKey{
+		version: version,
+		secret:  signature,
+	}, nil
+}
+
+// newWorkloadSnapshotAEAD returns the AEAD that seals the snapshot, keyed
+// with a key derived from the snapshot key secret and the snapshot salt.
+func newWorkloadSnapshotAEAD(key *workloadSnapshotKey, salt []byte) (cipher.AEAD, error) {
+	if len(salt) != workloadSnapshotSaltSize {
+		return nil, errors.New("snapshot salt has an invalid size")
+	}
+	secret, err := hkdf.Key(sha256.New, key.secret, salt, workloadSnapshotContext, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive workload snapshot encryption key: %w", err)
+	}
+	block, err := aes.NewCipher(secret)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func parseWorkloadSnapshotSVID(snapshotSVID workloadSnapshotSVID) (*cache.X509SVID, error) {
+	chain, err := x509.ParseCertificates(bytes.Join(snapshotSVID.CertChain, nil))
+	if err != nil {
+		return nil, err
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("empty certificate chain")
+	}
+	privateKey, err := x509.ParsePKCS8PrivateKey(snapshotSVID.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unexpected private key type %T", privateKey)
+	}
+	return &cache.X509SVID{Chain: chain, PrivateKey: signer}, nil
+}
@@ -0,0 +1,223 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	entryv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/entry/v1"
+	"github.com/spiffe/spire/pkg/agent/manager/cache"
+	"github.com/spiffe/spire/pkg/agent/manager/storecache"
+	"github.com/spiffe/spire/pkg/agent/storage"
+	"github.com/spiffe/spire/pkg/agent/workloadkey"
+	"github.com/spiffe/spire/pkg/common/rotationutil"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/spiffe/spire/test/clock"
+	"github.com/spiffe/spire/test/fakes/fakeagentcatalog"
+	"github.com/spiffe/spire/test/fakes/fakeagentkeymanager"
+	"github.com/spiffe/spire/test/fakes/fakemetrics"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkloadSnapshot(t *testing.T) {
+	dir := spiretest.TempDir(t)
+	km := fakeagentkeymanager.New(t, dir)
+	sto := openStorage(t, dir)
+
+	clk := clock.NewMock(t)
+	api := newMockAPI(t, &mockAPIConfig{
+		km: km,
+		getAuthorizedEntries: func(*mockAPI, int32, *entryv1.GetAuthorizedEntriesRequest) (*entryv1.GetAuthorizedEntriesResponse, error) {
+			return makeGetAuthorizedEntriesResponse(t, "resp1", "resp2"), nil
+		},
+		batchNewX509SVIDEntries: func(*mockAPI, int32) []*common.RegistrationEntry {
+			return makeBatchNewX509SVIDEntries("resp1", "resp2")
+		},
+		svidTTL: 200,
+		clk:     clk,
+	})
+
+	baseSVID, baseSVIDKey := api.newSVID(joinTokenID, 1*time.Hour)
+	cat := fakeagentcatalog.New()
+	cat.SetKeyManager(km)
+
+	newConfig := func(serverAddr string, metrics telemetry.Metrics) *Config {
+		return &Config{
+			ServerAddr:           serverAddr,
+			SVID:                 baseSVID,
+			SVIDKey:              baseSVIDKey,
+			Log:                  testLogger,
+			TrustDomain:          trustDomain,
+			Storage:              sto,
+			WorkloadKeyType:      workloadkey.ECP256,
+			Bundle:               api.bundle,
+			Metrics:              metrics,
+			Clk:                  clk,
+			Catalog:              cat,
+			SVIDStoreCache:       storecache.New(&storecache.Config{TrustDomain: trustDomain, Log: testLogger}),
+			RotationStrategy:     rotationutil.NewRotationStrategy(0),
+			PersistWorkloadSVIDs: true,
+		}
+	}
+
+	// The snapshot is stored after a successful synchronization
+	synced := initializeNewManager(t, newConfig(api.addr, &telemetry.Blackhole{}))
+	syncedSVIDs := synced.x509Cache.X509SVIDs()
+	require.Len(t, syncedSVIDs, 3)
+	sealed, err := sto.LoadWorkloadSnapshot()
+	require.NoError(t, err)
+	require.NotContains(t, string(sealed), "spiffe://example.org", "snapshot is not encrypted")
+	envelope := loadSealedWorkloadSnapshot(t, sto)
+	require.Equal(t, uint64(1), envelope.KeyVersion)
+
+	unreachableAddr := closedListenerAddr(t)
+
+	t.Run("restored while the server is unreachable", func(t *testing.T) {
+		metrics := fakemetrics.New()
+		m := initializeNewManager(t, newConfig(unreachableAddr, metrics))
+
+		require.Equal(t, syncedSVIDs, m.x509Cache.X509SVIDs())
+		update := m.FetchWorkloadUpdate(cache.Selectors{{Type: "unix", Value: "uid:1111"}})
+		require.Len(t, update.Identities, 2)
+		require.True(t, m.GetLastSync().IsZero())
+
+		require.Contains(t, metrics.AllMetrics(), fakemetrics.MetricItem{
+			Type: fakemetrics.IncrCounterType,
+			Key:  []string{telemetry.CacheManager, telemetry.WorkloadSnapshot, telemetry.Restore},
+			Val:  3,
+		})
+		require.Contains(t, metrics.AllMetrics(), fakemetrics.MetricItem{
+			Type: fakemetrics.SetGaugeType,
+			Key:  []string{telemetry.CacheManager, telemetry.WorkloadSnapshot, telemetry.Serving},
+			Val:  1,
+		})
+	})
+
+	t.Run("no longer served once synchronized", func(t *testing.T) {
+		metrics := fakemetrics.New()
+		initializeNewManager(t, newConfig(api.addr, metrics))
+
+		require.Equal(t, []fakemetrics.MetricItem{
+			{
+				Type: fakemetrics.SetGaugeType,
+				Key:  []string{telemetry.CacheManager, telemetry.WorkloadSnapshot, telemetry.Serving},
+				Val:  1,
+			},
+			{
+				Type: fakemetrics.SetGaugeType,
+				Key:  []string{telemetry.CacheManager, telemetry.WorkloadSnapshot, telemetry.Serving},
+				Val:  0,
+			},
+		}, filterSnapshotServingMetrics(metrics.AllMetrics()))
+
+		// The snapshot written once synchronized is sealed with a new key
+		// version and salt
+		rotated := loadSealedWorkloadSnapshot(t, sto)
+		require.Equal(t, uint64(2), rotated.KeyVersion)
+		require.NotEqual(t, envelope.Salt, rotated.Salt)
+		require.NotEqual(t, workloadSnapshotKeyID(envelope.KeyVersion), workloadSnapshotKeyID(rotated.KeyVersion))
+	})
+
+	t.Run("discarded when tampered", func(t *testing.T) {
+		tampered := envelope
+		tampered.Ciphertext = append([]byte(nil), envelope.Ciphertext...)
+		tampered.Ciphertext[len(tampered.Ciphertext)-1] ^= 0xff
+		tamperedBytes, err := json.Marshal(tampered)
+		require.NoError(t, err)
+		require.NoError(t, sto.StoreWorkloadSnapshot(tamperedBytes))
+
+		m := newManager(newConfig(unreachableAddr, &telemetry.Blackhole{}))
+		require.Error(t, m.Initialize(context.Background()))
+		require.Zero(t, m.CountX509SVIDs())
+		_, err = sto.LoadWorkloadSnapshot()
+		require.True(t, errors.Is(err, storage.ErrNotCached))
+	})
+
+	t.Run("discarded when the SVIDs have expired", func(t *testing.T) {
+		require.NoError(t, sto.StoreWorkloadSnapshot(sealed))
+		clk.Add(200 * time.Second)
+
+		m := newManager(newConfig(unreachableAddr, &telemetry.Blackhole{}))
+		require.Error(t, m.Initialize(context.Background()))
+		require.Zero(t, m.CountX509SVIDs())
+		_, err := sto.LoadWorkloadSnapshot()
+		require.True(t, errors.Is(err, storage.ErrNotCached))
+	})
+}
+
+func TestWorkloadSnapshotDisabled(t *testing.T) {
+	dir := spiretest.TempDir(t)
+	km := fakeagentkeymanager.New(t, dir)
+	sto := openStorage(t, dir)
+
+	clk := clock.NewMock(t)
+	api := newMockAPI(t, &mockAPIConfig{
+		km: km,
+		getAuthorizedEntries: func(*mockAPI, int32, *entryv1.GetAuthorizedEntriesRequest) (*entryv1.GetAuthorizedEntriesResponse, error) {
+			return makeGetAuthorizedEntriesResponse(t, "resp1", "resp2"), nil
+		},
+		batchNewX509SVIDEntries: func(*mockAPI, int32) []*common.RegistrationEntry {
+			return makeBatchNewX509SVIDEntries("resp1", "resp2")
+		},
+		svidTTL: 200,
+		clk:     clk,
+	})
+
+	baseSVID, baseSVIDKey := api.newSVID(joinTokenID, 1*time.Hour)
+	cat := fakeagentcatalog.New()
+	cat.SetKeyManager(km)
+
+	initializeNewManager(t, &Config{
+		ServerAddr:       api.addr,
+		SVID:             baseSVID,
+		SVIDKey:          baseSVIDKey,
+		Log:              testLogger,
+		TrustDomain:      trustDomain,
+		Storage:          sto,
+		WorkloadKeyType:  workloadkey.ECP256,
+		Bundle:           api.bundle,
+		Metrics:          &telemetry.Blackhole{},
+		Clk:              clk,
+		Catalog:          cat,
+		SVIDStoreCache:   storecache.New(&storecache.Config{TrustDomain: trustDomain, Log: testLogger}),
+		RotationStrategy: rotationutil.NewRotationStrategy(0),
+	})
+
+	_, err := sto.LoadWorkloadSnapshot()
+	require.True(t, errors.Is(err, storage.ErrNotCached))
+	for version := range uint64(2) {
+		_, err = km.GetKey(context.Background(), workloadSnapshotKeyID(version))
+		require.Error(t, err)
+	}
+}
+
+func loadSealedWorkloadSnapshot(t *testing.T, sto storage.Storage) sealedWorkloadSnapshot {
+	sealed, err := sto.LoadWorkloadSnapshot()
+	require.NoError(t, err)
+	var envelope sealedWorkloadSnapshot
+	require.NoError(t, json.Unmarshal(sealed, &envelope))
+	return envelope
+}
+
+func filterSnapshotServingMetrics(items []fakemetrics.MetricItem) []fakemetrics.MetricItem {
+	var out []fakemetrics.MetricItem
+	for _, item := range items {
+		if len(item.Key) == 3 && item.Key[1] == telemetry.WorkloadSnapshot && item.Key[2] == telemetry.Serving {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func closedListenerAddr(t *testing.T) string {
+	listener, err := net.Listen("tcp", "localhost:")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return addr
+}
@@ -0,0 +1,55 @@
+package manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/spiffe/spire/pkg/common/uptime"
+	"github.com/spiffe/spire/pkg/common/util"
+	"github.com/spiffe/spire/proto/spire/common"
+)
+
+// maxLastSyncErrorLength bounds the size of the last sync error reported to
+// the server.
+const maxLastSyncErrorLength = 1024
+
+func (m *manager) runStatusReporter(ctx context.Context) error {
+	for {
+		select {
+		case <-m.clk.After(m.c.StatusReportInterval):
+		case <-ctx.Done():
+			return nil
+		}
+
+		// The client logs failures. The status is reported again on the
+		// next interval.
+		_ = m.client.PostStatusReport(ctx, m.statusReport())
+	}
+}
+
+func (m *manager) statusReport() *common.AgentStatusReport {
+	m.mtx.RLock()
+	lastSyncError := m.lastSyncError
+	m.mtx.RUnlock()
+
+	return &common.AgentStatusReport{
+		Uptime:          int64(uptime.Uptime() / time.Second),
+		Plugins:         m.c.Plugins,
+		CachedX509Svids: util.MustCast[int32](m.CountX509SVIDs()),
+		CachedJwtSvids:  util.MustCast[int32](m.CountJWTSVIDs()),
+		LastSyncError:   lastSyncError,
+	}
+}
+
+func (m *manager) setLastSyncError(err error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.lastSyncError = ""
+	if err != nil {
+		m.lastSyncError = err.Error()
+		if len(m.lastSyncError) > maxLastSyncErrorLength {
+			m.lastSyncError = m.lastSyncError[:maxLastSyncErrorLength]
+		}
+	}
+}
@@ -34,6 +34,7 @@ type csrRequest struct {
 type taintedAuthorities struct {
 	X509 []string
 	JWT  map[string]struct{}
+	WIT  map[string]struct{}
 }
 
 // Implemented by both the LRU cache and the store cache as the common
@@ -50,8 +51,17 @@ type x509SVIDCache interface {
 }
 
 func (m *manager) syncSVIDs(ctx context.Context) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "agent.manager.syncSVIDs")
+	defer telemetry.EndSpan(span, &err)
+	log := telemetry.WithTraceFields(ctx, m.c.Log)
+
 	m.x509Cache.SyncSVIDsWithSubscribers()
-	return m.updateX509SVIDs(ctx, m.c.Log.WithField(telemetry.CacheType, "workload"), m.x509Cache)
+	if err := m.updateX509SVIDs(ctx, log.WithField(telemetry.CacheType, "workload"), m.x509Cache); err != nil {
+		return err
+	}
+
+	m.witCache.SyncSVIDsWithSubscribers()
+	return m.updateWITSVIDs(ctx, log.WithField(telemetry.CacheType, "workload"))
 }
 
 // processTaintedAuthorities verifies if a new authority is tainted and forces rotation in all caches if required.
@@ -95,12 +105,30 @@ func (m *manager) processTaintedAuthorities(ctx context.Context, bundle *spiffeb
 		}
 	}
 
+	newTaintedWITAuthorities := getNewItemsFromMap(m.processedTaintedWITAuthorities, tainted.WIT)
+	if len(newTaintedWITAuthorities) > 0 {
+		m.c.Log.WithField(telemetry.WITAuthorityKeyIDs, strings.Join(newTaintedWITAuthorities, ",")).
+			Debug("New tainted WIT authorities found")
+
+		// Taint WIT-SVIDs in the cache
+		m.witCache.TaintWITSVIDs(ctx, tainted.WIT)
+
+		for _, keyID := range newTaintedWITAuthorities {
+			m.processedTaintedWITAuthorities[keyID] = struct{}{}
+		}
+	}
+
 	return nil
 }
 
 // synchronize fetches the authorized entries from the server, updates the
 // cache, and fetches missing/expiring SVIDs.
 func (m *manager) synchronize(ctx context.Context) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "agent.manager.synchronize")
+	defer telemetry.EndSpan(span, &err)
+	defer func() { m.setLastSyncError(err) }()
+	log := telemetry.WithTraceFields(ctx, m.c.Log)
+
 	cacheUpdate, storeUpdate, tainted, err := m.fetchEntries(ctx)
 	if err != nil {
 		return err
@@ -118,16 +146,25 @@ func (m *manager) synchronize(ctx context.Context) (err error) {
 		return err
 	}
 
-	if err := m.updateX509SVIDCache(ctx, cacheUpdate, m.c.Log.WithField(telemetry.CacheType, telemetry_agent.CacheTypeWorkload), "", m.x509Cache); err != nil {
+	if err := m.updateX509SVIDCache(ctx, cacheUpdate, log.WithField(telemetry.CacheType, telemetry_agent.CacheTypeWorkload), "", m.x509Cache); err != nil {
+		return err
+	}
+
+	if err := m.updateX509SVIDCache(ctx, storeUpdate, log.WithField(telemetry.CacheType, telemetry_agent.CacheTypeSVIDStore), telemetry_agent.CacheTypeSVIDStore, m.svidStoreCache); err != nil {
 		return err
 	}
 
-	if err := m.updateX509SVIDCache(ctx, storeUpdate, m.c.Log.WithField(telemetry.CacheType, telemetry_agent.CacheTypeSVIDStore), telemetry_agent.CacheTypeSVIDStore, m.svidStoreCache); err != nil {
+	if err := m.updateWITSVIDCache(ctx, cacheUpdate, log.WithField(telemetry.CacheType, telemetry_agent.CacheTypeWorkload)); err != nil {
 		return err
 	}
 
 	// Set last success sync
 	m.setLastSync()
+
+	if m.c.PersistWorkloadSVIDs {
+		m.setServingWorkloadSnapshot(false)
+		m.storeWorkloadSnapshot(ctx)
+	}
 	return nil
 }
 
@@ -292,12 +329,142 @@ func (m *manager) fetchX509SVIDs(ctx context.Context, csrs []csrRequest) (_ map[
 	return byEntryID, nil
 }
 
+func (m *manager) updateWITSVIDCache(ctx context.Context, update *cache.UpdateEntries, log logrus.FieldLogger) error {
+	// the values in `update` now belong to the cache. DO NOT MODIFY.
+	m.witCache.UpdateEntries(update, func(existingEntry, newEntry *common.RegistrationEntry, svid *cache.WITSVID) bool {
+		switch {
+		case svid == nil:
+			// no SVID
+		case m.c.RotationStrategy.ShouldRotateWIT(m.c.Clk.Now(), svid.IssuedAt, svid.NotAfter):
+			// SVID is expiring
+		case existingEntry != nil && existingEntry.RevisionNumber != newEntry.RevisionNumber:
+			// Registration entry has been updated
+		default:
+			// SVID is good
+			return false
+		}
+
+		return true
+	})
+
+	return m.updateWITSVIDs(ctx, log)
+}
+
+func (m *manager) updateWITSVIDs(ctx context.Context, log logrus.FieldLogger) error {
+	m.updateSVIDMu.Lock()
+	defer m.updateSVIDMu.Unlock()
+
+	staleEntries := m.witCache.GetStaleEntries()
+	if len(staleEntries) == 0 {
+		return nil
+	}
+
+	sizeLimit := m.csrSizeLimitedBackoff.NextBackOff()
+	log.WithFields(logrus.Fields{
+		telemetry.Count: len(staleEntries),
+		telemetry.Limit: sizeLimit,
+	}).Debug("Renewing stale WIT-SVID entries")
+
+	var requests []csrRequest
+	for _, entry := range staleEntries {
+		// we've exceeded the request limit, don't make any more requests
+		if len(requests) >= sizeLimit {
+			break
+		}
+
+		requests = append(requests, csrRequest{
+			EntryID:              entry.Entry.EntryId,
+			SpiffeID:             entry.Entry.SpiffeId,
+			CurrentSVIDExpiresAt: entry.SVIDExpiresAt,
+		})
+	}
+
+	svids, err := m.fetchWITSVIDs(ctx, requests)
+	if err != nil {
+		return err
+	}
+	// the values in `update` now belong to the cache. DO NOT MODIFY.
+	m.witCache.UpdateWITSVIDs(svids)
+	return nil
+}
+
+func (m *manager) fetchWITSVIDs(ctx context.Context, requests []csrRequest) (_ map[string]*cache.WITSVID, err error) {
+	counter := telemetry_agent.StartManagerFetchWITSVIDsUpdatesCall(m.c.Metrics)
+	defer counter.Done(&err)
+	defer func() {
+		if err == nil {
+			m.csrSizeLimitedBackoff.Success()
+		}
+	}()
+
+	signingAlgorithm, err := m.c.WorkloadKeyType.WITSigningAlgorithm()
+	if err != nil {
+		return nil, err
+	}
+
+	publicKeys := make(map[string]crypto.PublicKey, len(requests))
+	privateKeys := make(map[string]crypto.Signer, len(requests))
+	for _, req := range requests {
+		log := m.c.Log.WithFields(logrus.Fields{
+			"spiffe_id": req.SpiffeID,
+			"entry_id":  req.EntryID,
+		})
+		if !req.CurrentSVIDExpiresAt.IsZero() {
+			log = log.WithField("expires_at", req.CurrentSVIDExpiresAt.Format(time.RFC3339))
+		}
+
+		// Since entryIDs are unique, this shouldn't happen. Log just in case
+		if _, ok := privateKeys[req.EntryID]; ok {
+			log.Warnf("Ignoring duplicate WIT-SVID renewal for entry ID: %q", req.EntryID)
+			continue
+		}
+
+		if req.CurrentSVIDExpiresAt.IsZero() {
+			log.Info("Creating WIT-SVID")
+		} else {
+			log.Info("Renewing WIT-SVID")
+		}
+
+		privateKey, err := m.c.WorkloadKeyType.GenerateSigner()
+		if err != nil {
+			return nil, err
+		}
+		privateKeys[req.EntryID] = privateKey
+		publicKeys[req.EntryID] = privateKey.Public()
+	}
+
+	svidsOut, err := m.client.NewWITSVIDs(ctx, publicKeys, signingAlgorithm)
+	if err != nil {
+		// Reduce request size for next invocation
+		m.csrSizeLimitedBackoff.Failure()
+		return nil, err
+	}
+
+	byEntryID := make(map[string]*cache.WITSVID, len(svidsOut))
+	for entryID, svid := range svidsOut {
+		privateKey, ok := privateKeys[entryID]
+		if !ok {
+			continue
+		}
+		byEntryID[entryID] = &cache.WITSVID{
+			Token:      svid.Token,
+			PrivateKey: privateKey,
+			IssuedAt:   svid.IssuedAt,
+			NotAfter:   svid.ExpiresAt,
+		}
+	}
+
+	return byEntryID, nil
+}
+
 // fetchEntries fetches entries that the agent is entitled to, divided in lists, one for regular entries and
 // another one for storable entries
 func (m *manager) fetchEntries(ctx context.Context) (_ *cache.UpdateEntries, _ *cache.UpdateEntries, _ *taintedAuthorities, err error) {
 	// Put all the CSRs in an array to make just one call with all the CSRs.
 	counter := telemetry_agent.StartManagerFetchEntriesUpdatesCall(m.c.Metrics)
 	defer counter.Done(&err)
+	ctx, span := telemetry.StartSpan(ctx, "agent.manager.fetchEntries")
+	defer telemetry.EndSpan(span, &err)
 
 	var update *client.Update
 	if m.c.UseSyncAuthorizedEntries {
@@ -325,6 +492,7 @@ func (m *manager) fetchEntries(ctx context.Context) (_ *cache.UpdateEntries, _ *
 	// Get all Subject Key IDs and KeyIDs of tainted authorities
 	var taintedX509Authorities []string
 	taintedJWTAuthorities := make(map[string]struct{})
+	taintedWITAuthorities := make(map[string]struct{})
 	if b, ok := update.Bundles[m.c.TrustDomain.IDString()]; ok {
 		for _, rootCA := range b.RootCas {
 			if rootCA.TaintedKey {
@@ -341,6 +509,11 @@ func (m *manager) fetchEntries(ctx context.Context) (_ *cache.UpdateEntries, _ *
 				taintedJWTAuthorities[jwtKey.Kid] = struct{}{}
 			}
 		}
+		for _, witKey := range b.WitSigningKeys {
+			if witKey.TaintedKey {
+				taintedWITAuthorities[witKey.Kid] = struct{}{}
+			}
+		}
 	}
 
 	cacheEntries := make(map[string]*common.RegistrationEntry)
@@ -356,15 +529,16 @@ func (m *manager) fetchEntries(ctx context.Context) (_ *cache.UpdateEntries, _ *
 	}
 
 	return &cache.UpdateEntries{
-			Bundles:             bundles,
-			RegistrationEntries: cacheEntries,
-		}, &cache.UpdateEntries{
-			Bundles:             bundles,
-			RegistrationEntries: storeEntries,
-		}, &taintedAuthorities{
-			X509: taintedX509Authorities,
-			JWT:  taintedJWTAuthorities,
-		}, nil
+		Bundles:             bundles,
+		RegistrationEntries: cacheEntries,
+	}, &cache.UpdateEntries{
+		Bundles:             bundles,
+		RegistrationEntries: storeEntries,
+	}, &taintedAuthorities{
+		X509: taintedX509Authorities,
+		JWT:  taintedJWTAuthorities,
+		WIT:  taintedWITAuthorities,
+	}, nil
 }
 
 func newCSR(spiffeID spiffeid.ID, keyType workloadkey.KeyType) (crypto.Signer, []byte, error) {
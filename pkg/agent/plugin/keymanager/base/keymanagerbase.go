@@ -255,6 +255,12 @@ func makeKeyEntry(keyID string, keyType keymanagerv1.KeyType, privateKey crypto.
 	}, nil
 }
 
+// MakeKeyEntryFromSigner makes a key entry for a private key that is only
+// reachable through a signer, e.g. because it lives in hardware.
+func MakeKeyEntryFromSigner(id string, keyType keymanagerv1.KeyType, signer crypto.Signer) (*KeyEntry, error) {
+	return makeKeyEntry(id, keyType, signer)
+}
+
 func MakeKeyEntryFromKey(id string, privateKey crypto.PrivateKey) (*KeyEntry, error) {
 	switch privateKey := privateKey.(type) {
 	case *ecdsa.PrivateKey:
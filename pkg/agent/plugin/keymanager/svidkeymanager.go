@@ -2,6 +2,12 @@ package keymanager
 
 import (
 	"context"
+	"slices"
+)
+
+const (
+	svidKeyA = "agent-svid-A"
+	svidKeyB = "agent-svid-B"
 )
 
 // SVIDKeyManager is a wrapper around the key manager specifically used for
@@ -12,7 +18,8 @@ type SVIDKeyManager interface {
 	// which key ID to use for the new key).
 	GenerateKey(ctx context.Context, currentKey Key) (Key, error)
 
-	// GetKeys returns all keys managed by the KeyManager.
+	// GetKeys returns the SVID keys managed by the KeyManager. Keys the
+	// KeyManager holds for other purposes are not returned.
 	GetKeys(ctx context.Context) ([]Key, error)
 }
 
@@ -26,13 +33,19 @@ type svidKeyManager struct {
 }
 
 func (s svidKeyManager) GenerateKey(ctx context.Context, currentKey Key) (Key, error) {
-	keyID := "agent-svid-A"
+	keyID := svidKeyA
 	if currentKey != nil && currentKey.ID() == keyID {
-		keyID = "agent-svid-B"
+		keyID = svidKeyB
 	}
 	return s.km.GenerateKey(ctx, keyID, ECP256)
 }
 
 func (s svidKeyManager) GetKeys(ctx context.Context) ([]Key, error) {
-	return s.km.GetKeys(ctx)
+	keys, err := s.km.GetKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(keys, func(key Key) bool {
+		return key.ID() != svidKeyA && key.ID() != svidKeyB
+	}), nil
 }
@@ -40,6 +40,13 @@ func TestSVIDKeyManager(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, []keymanager.Key{keyA, keyB}, keys)
 
+	// Assert that keys held for other purposes are not listed
+	_, err = km.GenerateKey(context.Background(), "other", keymanager.ECP256)
+	require.NoError(t, err)
+	keys, err = svidKM.GetKeys(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []keymanager.Key{keyA, keyB}, keys)
+
 	// Regenerate the A key (passing the B key)
 	keyA, err = svidKM.GenerateKey(context.Background(), keyB)
 	require.NoError(t, err)
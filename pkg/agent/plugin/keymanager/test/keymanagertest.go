@@ -11,6 +11,7 @@ import (
 	"crypto/x509"
 	"math/big"
 	"os"
+	"slices"
 	"strconv"
 	"testing"
 
@@ -68,6 +69,11 @@ type Config struct {
 	// unsupported for the given key type.
 	UnsupportedSignatureAlgorithms map[keymanager.KeyType][]x509.SignatureAlgorithm
 
+	// UnsupportedKeyTypes is a list of key types that are unsupported by
+	// the key manager (e.g. because the backing hardware lacks support).
+	UnsupportedKeyTypes []keymanager.KeyType
+
+	keyTypes            map[keymanager.KeyType]keyAlgorithm
 	signatureAlgorithms map[keymanager.KeyType][]x509.SignatureAlgorithm
 }
 
@@ -133,6 +139,13 @@ func Test(t *testing.T, config Config) {
 		}
 	}
 
+	config.keyTypes = make(map[keymanager.KeyType]keyAlgorithm)
+	for keyType, keyAlgorithm := range keyTypes {
+		if !slices.Contains(config.UnsupportedKeyTypes, keyType) {
+			config.keyTypes[keyType] = keyAlgorithm
+		}
+	}
+
 	t.Run("GenerateKey", func(t *testing.T) {
 		testGenerateKey(t, config)
 	})
@@ -149,7 +162,7 @@ func Test(t *testing.T, config Config) {
 func testGenerateKey(t *testing.T, config Config) {
 	km := config.Create(t)
 
-	for keyType := range keyTypes {
+	for keyType := range config.keyTypes {
 		t.Run(keyType.String(), func(t *testing.T) {
 			key := requireGenerateKey(t, km, keyType)
 			config.testKey(t, key, keyType)
@@ -183,7 +196,7 @@ func testGenerateKey(t *testing.T, config Config) {
 func testGetKey(t *testing.T, config Config) {
 	km := config.Create(t)
 
-	for keyType := range keyTypes {
+	for keyType := range config.keyTypes {
 		t.Run(keyType.String(), func(t *testing.T) {
 			requireGenerateKey(t, km, keyType)
 			key := requireGetKey(t, km, keyType.String())
@@ -209,7 +222,7 @@ func testGetKeys(t *testing.T, config Config) {
 		require.Empty(t, requireGetKeys(t, km))
 	})
 
-	for keyType := range keyTypes {
+	for keyType := range config.keyTypes {
 		requireGenerateKey(t, km, keyType)
 	}
 
@@ -218,8 +231,8 @@ func testGetKeys(t *testing.T, config Config) {
 		for _, key := range requireGetKeys(t, km) {
 			keys[key.ID()] = key
 		}
-		require.Len(t, keys, len(keyTypes))
-		for keyType := range keyTypes {
+		require.Len(t, keys, len(config.keyTypes))
+		for keyType := range config.keyTypes {
 			config.testKey(t, keys[keyType.String()], keyType)
 		}
 	})
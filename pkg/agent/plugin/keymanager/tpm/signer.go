@@ -0,0 +1,267 @@
+package tpm
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	tpmhandle "github.com/google/go-tpm/tpmutil"
+	"github.com/spiffe/spire/pkg/agent/plugin/nodeattestor/tpmdevid/tpmutil"
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// keyAttributes makes the keys non-exportable (fixedTPM and fixedParent),
+// generated by the TPM (sensitiveDataOrigin) and usable for signing without
+// a policy. Dictionary attack protections are disabled since the keys have
+// an empty authorization value.
+const keyAttributes = tpm2.FlagSign |
+	tpm2.FlagFixedTPM |
+	tpm2.FlagFixedParent |
+	tpm2.FlagSensitiveDataOrigin |
+	tpm2.FlagUserWithAuth |
+	tpm2.FlagNoDA
+
+// The key templates leave the signing scheme unset so the scheme and hash
+// algorithm can be chosen on each signing operation.
+func rsaKeyTemplate(bits uint16) tpm2.Public {
+	return tpm2.Public{
+		Type:       tpm2.AlgRSA,
+		NameAlg:    tpm2.AlgSHA256,
+		Attributes: keyAttributes,
+		RSAParameters: &tpm2.RSAParams{
+			Sign:    &tpm2.SigScheme{Alg: tpm2.AlgNull},
+			KeyBits: bits,
+		},
+	}
+}
+
+func eccKeyTemplate(curve tpm2.EllipticCurve) tpm2.Public {
+	return tpm2.Public{
+		Type:       tpm2.AlgECC,
+		NameAlg:    tpm2.AlgSHA256,
+		Attributes: keyAttributes,
+		ECCParameters: &tpm2.ECCParams{
+			Sign:    &tpm2.SigScheme{Alg: tpm2.AlgNull},
+			CurveID: curve,
+			KDF:     &tpm2.KDFScheme{Alg: tpm2.AlgNull},
+		},
+	}
+}
+
+// tpmDevice serializes the access to the TPM. The TPM is opened and the
+// storage root key is created once, when the key manager is configured, and
+// both are held until the key manager is closed, since creating the storage
+// root key is too slow to do on every signing operation. Keys are only loaded
+// while they are used, so the key manager does not hold more than one TPM
+// object slot between operations.
+type tpmDevice struct {
+	open func(...string) (io.ReadWriteCloser, error)
+
+	mu                     sync.Mutex
+	rwc                    io.ReadWriteCloser
+	srk                    tpmhandle.Handle
+	devicePath             string
+	ownerHierarchyPassword string
+}
+
+// configure opens the TPM and creates the storage root key the keys are
+// wrapped with. The storage root key is derived from the owner hierarchy
+// seed, so the same key is produced every time on the same TPM. The TPM is
+// only reopened if the device path or the owner hierarchy password changed.
+func (d *tpmDevice) configure(devicePath, ownerHierarchyPassword string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.rwc != nil && d.devicePath == devicePath && d.ownerHierarchyPassword == ownerHierarchyPassword {
+		return nil
+	}
+
+	// Some TPM devices can only be opened once, so the previous one is
+	// closed before opening the new one.
+	if err := d.closeLocked(); err != nil {
+		return fmt.Errorf("cannot close TPM at %q: %w", d.devicePath, err)
+	}
+
+	rwc, err := d.open(devicePath)
+	if err != nil {
+		return fmt.Errorf("cannot open TPM at %q: %w", devicePath, err)
+	}
+
+	srk, _, err := tpm2.CreatePrimary(rwc, tpm2.HandleOwner, tpm2.PCRSelection{}, ownerHierarchyPassword, "", tpmutil.SRKTemplateHighECC())
+	if err != nil {
+		_ = rwc.Close()
+		return fmt.Errorf("tpm2.CreatePrimary failed: %w", err)
+	}
+
+	d.rwc = rwc
+	d.srk = srk
+	d.devicePath = devicePath
+	d.ownerHierarchyPassword = ownerHierarchyPassword
+	return nil
+}
+
+// close flushes the storage root key and closes the TPM.
+func (d *tpmDevice) close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.closeLocked()
+}
+
+func (d *tpmDevice) closeLocked() error {
+	if d.rwc == nil {
+		return nil
+	}
+	_ = tpm2.FlushContext(d.rwc, d.srk)
+	err := d.rwc.Close()
+	d.rwc = nil
+	return err
+}
+
+func (d *tpmDevice) createKey(template tpm2.Public) (*tpmKey, error) {
+	var key *tpmKey
+	err := d.withStorageRootKey(func(rw io.ReadWriter, srk tpmhandle.Handle) error {
+		privateBlob, publicBlob, _, _, _, err := tpm2.CreateKey(rw, srk, tpm2.PCRSelection{}, "", "", template)
+		if err != nil {
+			return fmt.Errorf("tpm2.CreateKey failed: %w", err)
+		}
+		key, err = newKey(d, publicBlob, privateBlob)
+		return err
+	})
+	return key, err
+}
+
+func (d *tpmDevice) loadKey(key *tpmKey) error {
+	return d.withKey(key, func(io.ReadWriter, tpmhandle.Handle) error {
+		return nil
+	})
+}
+
+func (d *tpmDevice) sign(key *tpmKey, digest []byte, scheme *tpm2.SigScheme) (*tpm2.Signature, error) {
+	var signature *tpm2.Signature
+	err := d.withKey(key, func(rw io.ReadWriter, handle tpmhandle.Handle) (err error) {
+		signature, err = tpm2.Sign(rw, handle, "", digest, nil, scheme)
+		if err != nil {
+			return fmt.Errorf("tpm2.Sign failed: %w", err)
+		}
+		return nil
+	})
+	return signature, err
+}
+
+func (d *tpmDevice) withKey(key *tpmKey, fn func(io.ReadWriter, tpmhandle.Handle) error) error {
+	return d.withStorageRootKey(func(rw io.ReadWriter, srk tpmhandle.Handle) error {
+		handle, _, err := tpm2.Load(rw, srk, "", key.publicBlob, key.privateBlob)
+		if err != nil {
+			return fmt.Errorf("tpm2.Load failed: %w", err)
+		}
+		defer func() { _ = tpm2.FlushContext(rw, handle) }()
+		return fn(rw, handle)
+	})
+}
+
+// withStorageRootKey runs the function with the open TPM and the handle of
+// the storage root key, holding the TPM for the duration of the function.
+func (d *tpmDevice) withStorageRootKey(fn func(io.ReadWriter, tpmhandle.Handle) error) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.rwc == nil {
+		return status.Error(codes.FailedPrecondition, "not configured")
+	}
+
+	return fn(d.rwc, d.srk)
+}
+
+// tpmKey is a crypto.Signer for a key that lives in the TPM.
+type tpmKey struct {
+	tpm         *tpmDevice
+	public      tpm2.Public
+	publicKey   crypto.PublicKey
+	publicBlob  []byte
+	privateBlob []byte
+}
+
+func newKey(tpm *tpmDevice, publicBlob, privateBlob []byte) (*tpmKey, error) {
+	public, err := tpm2.DecodePublic(publicBlob)
+	if err != nil {
+		return nil, fmt.Errorf("tpm2.DecodePublic failed: %w", err)
+	}
+	if public.Attributes&tpm2.FlagSign == 0 {
+		return nil, errors.New("not a signing key")
+	}
+	publicKey, err := public.Key()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get public key: %w", err)
+	}
+	return &tpmKey{
+		tpm:         tpm,
+		public:      public,
+		publicKey:   publicKey,
+		publicBlob:  publicBlob,
+		privateBlob: privateBlob,
+	}, nil
+}
+
+func (k *tpmKey) Public() crypto.PublicKey {
+	return k.publicKey
+}
+
+// Sign signs the digest in the TPM. PSS signatures are produced by the TPM
+// with a salt as long as the hash, so only the rsa.PSSSaltLengthAuto and
+// rsa.PSSSaltLengthEqualsHash salt lengths are supported.
+func (k *tpmKey) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	hashAlg, err := tpm2.HashToAlgorithm(opts.HashFunc())
+	if err != nil {
+		return nil, err
+	}
+	if len(digest) != opts.HashFunc().Size() {
+		return nil, fmt.Errorf("digest length: got %d, want %d", len(digest), opts.HashFunc().Size())
+	}
+
+	scheme := &tpm2.SigScheme{Hash: hashAlg}
+	switch k.public.Type {
+	case tpm2.AlgECC:
+		scheme.Alg = tpm2.AlgECDSA
+	case tpm2.AlgRSA:
+		scheme.Alg = tpm2.AlgRSASSA
+		if pssOpts, ok := opts.(*rsa.PSSOptions); ok {
+			switch pssOpts.SaltLength {
+			case rsa.PSSSaltLengthAuto, rsa.PSSSaltLengthEqualsHash, opts.HashFunc().Size():
+			default:
+				return nil, fmt.Errorf("unsupported PSS salt length %d", pssOpts.SaltLength)
+			}
+			scheme.Alg = tpm2.AlgRSAPSS
+		}
+	default:
+		return nil, fmt.Errorf("unsupported key algorithm %v", k.public.Type)
+	}
+
+	signature, err := k.tpm.sign(k, digest, scheme)
+	if err != nil {
+		return nil, err
+	}
+	return encodeSignature(signature)
+}
+
+func encodeSignature(signature *tpm2.Signature) ([]byte, error) {
+	switch {
+	case signature.RSA != nil:
+		return signature.RSA.Signature, nil
+	case signature.ECC != nil:
+		var b cryptobyte.Builder
+		b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1BigInt(signature.ECC.R)
+			b.AddASN1BigInt(signature.ECC.S)
+		})
+		return b.Bytes()
+	default:
+		return nil, errors.New("unrecognized tpm2.Signature")
+	}
+}
@@ -0,0 +1,303 @@
+package tpm
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl"
+	keymanagerv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/agent/keymanager/v1"
+	configv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/service/common/config/v1"
+	keymanagerbase "github.com/spiffe/spire/pkg/agent/plugin/keymanager/base"
+	"github.com/spiffe/spire/pkg/agent/plugin/nodeattestor/tpmdevid/tpmutil"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/common/diskutil"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	pluginName = "tpm"
+
+	// baseTPMDir is the directory scanned for a TPM device when no device
+	// path is configured.
+	baseTPMDir = "/dev"
+)
+
+func BuiltIn() catalog.BuiltIn {
+	return asBuiltIn(newKeyManager())
+}
+
+func asBuiltIn(p *KeyManager) catalog.BuiltIn {
+	return catalog.MakeBuiltIn(pluginName,
+		keymanagerv1.KeyManagerPluginServer(p),
+		configv1.ConfigServiceServer(p))
+}
+
+type configuration struct {
+	// Directory where the TPM wrapped key blobs are persisted
+	Directory string `hcl:"directory"`
+	// DevicePath is the path to the TPM device. Autodetected when empty.
+	DevicePath string `hcl:"tpm_device_path"`
+	// OwnerHierarchyPassword is the authorization value of the owner
+	// hierarchy, used to derive the storage root key the keys are created
+	// under.
+	OwnerHierarchyPassword string `hcl:"owner_hierarchy_password"`
+}
+
+// KeyManager is a key manager that creates the keys inside of a TPM. The
+// private keys never leave the TPM in plaintext. Only the key blobs wrapped
+// by the TPM storage root key are persisted to disk, which can only be loaded
+// back into the same TPM.
+type KeyManager struct {
+	*keymanagerbase.Base
+	configv1.UnimplementedConfigServer
+
+	log hclog.Logger
+	tpm *tpmDevice
+
+	mu     sync.Mutex
+	config *configuration
+
+	hooks struct {
+		openTPM           func(...string) (io.ReadWriteCloser, error)
+		autoDetectTPMPath func(string) (string, error)
+	}
+}
+
+func newKeyManager() *KeyManager {
+	m := &KeyManager{}
+	m.hooks.openTPM = tpmutil.OpenTPM
+	m.hooks.autoDetectTPMPath = tpmutil.AutoDetectTPMPath
+	m.tpm = &tpmDevice{
+		open: func(paths ...string) (io.ReadWriteCloser, error) {
+			return m.hooks.openTPM(paths...)
+		},
+	}
+	m.Base = keymanagerbase.New(keymanagerbase.Config{
+		Generator:    &generator{tpm: m.tpm},
+		WriteEntries: m.writeEntries,
+	})
+	return m
+}
+
+func (m *KeyManager) SetLogger(log hclog.Logger) {
+	m.log = log
+}
+
+func (m *KeyManager) Configure(_ context.Context, req *configv1.ConfigureRequest) (*configv1.ConfigureResponse, error) {
+	config := new(configuration)
+	if err := hcl.Decode(config, req.HclConfiguration); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to decode configuration: %v", err)
+	}
+
+	if config.Directory == "" {
+		return nil, status.Error(codes.InvalidArgument, "directory must be configured")
+	}
+
+	if config.DevicePath != "" && runtime.GOOS == "windows" {
+		return nil, status.Error(codes.InvalidArgument, "device path is not allowed on windows")
+	}
+
+	if config.DevicePath == "" && runtime.GOOS != "windows" {
+		devicePath, err := m.hooks.autoDetectTPMPath(baseTPMDir)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "tpm autodetection failed: %v", err)
+		}
+		config.DevicePath = devicePath
+	}
+
+	if err := os.MkdirAll(config.Directory, 0700); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "directory validation failed: %v", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.tpm.configure(config.DevicePath, config.OwnerHierarchyPassword); err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to configure TPM: %v", err)
+	}
+
+	// Only load entry information on first configure
+	if m.config == nil {
+		if err := m.loadEntries(config.Directory); err != nil {
+			return nil, err
+		}
+	}
+
+	m.config = config
+	return &configv1.ConfigureResponse{}, nil
+}
+
+// Close releases the storage root key and closes the TPM.
+func (m *KeyManager) Close() error {
+	return m.tpm.close()
+}
+
+func (m *KeyManager) loadEntries(dir string) error {
+	blobs, err := loadKeyBlobs(keysPath(dir))
+	if err != nil {
+		return err
+	}
+
+	// Sort the key IDs so keys are loaded and logged in a consistent order
+	ids := make([]string, 0, len(blobs))
+	for id := range blobs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var entries []*keymanagerbase.KeyEntry
+	for _, id := range ids {
+		key, err := newKey(m.tpm, blobs[id].Public, blobs[id].Private)
+		if err != nil {
+			return status.Errorf(codes.Internal, "unable to parse key %q: %v", id, err)
+		}
+
+		// Keys that can no longer be loaded into the TPM (e.g. because the
+		// TPM was cleared) are dropped so they are regenerated, instead of
+		// failing every signing operation.
+		if err := m.tpm.loadKey(key); err != nil {
+			m.log.Warn("Dropping key that cannot be loaded into the TPM", telemetry.KeyID, id, telemetry.Error, err)
+			continue
+		}
+
+		entry, err := makeKeyEntry(id, key)
+		if err != nil {
+			return status.Errorf(codes.Internal, "unable to make entry %q: %v", id, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	m.Base.SetEntries(entries)
+	return nil
+}
+
+func (m *KeyManager) writeEntries(_ context.Context, allEntries []*keymanagerbase.KeyEntry, _ *keymanagerbase.KeyEntry) error {
+	m.mu.Lock()
+	config := m.config
+	m.mu.Unlock()
+
+	if config == nil {
+		return status.Error(codes.FailedPrecondition, "not configured")
+	}
+
+	return writeKeyBlobs(keysPath(config.Directory), allEntries)
+}
+
+// keyBlobs holds the public area and the private area wrapped by the storage
+// root key, as returned by the TPM when the key is created.
+type keyBlobs struct {
+	Public  []byte `json:"public"`
+	Private []byte `json:"private"`
+}
+
+type entriesData struct {
+	Keys map[string]keyBlobs `json:"keys"`
+}
+
+func loadKeyBlobs(path string) (map[string]keyBlobs, error) {
+	jsonBytes, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, status.Errorf(codes.Internal, "unable to read keys: %v", err)
+	}
+
+	data := new(entriesData)
+	if err := json.Unmarshal(jsonBytes, data); err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to decode keys JSON: %v", err)
+	}
+	return data.Keys, nil
+}
+
+func writeKeyBlobs(path string, entries []*keymanagerbase.KeyEntry) error {
+	data := &entriesData{
+		Keys: make(map[string]keyBlobs),
+	}
+	for _, entry := range entries {
+		key, ok := entry.PrivateKey.(*tpmKey)
+		if !ok {
+			return status.Errorf(codes.Internal, "unexpected private key type %T for key %q", entry.PrivateKey, entry.Id)
+		}
+		data.Keys[entry.Id] = keyBlobs{
+			Public:  key.publicBlob,
+			Private: key.privateBlob,
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(data, "", "\t")
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to marshal entries: %v", err)
+	}
+
+	if err := diskutil.AtomicWritePrivateFile(path, jsonBytes); err != nil {
+		return status.Errorf(codes.Internal, "unable to write entries: %v", err)
+	}
+
+	return nil
+}
+
+func makeKeyEntry(id string, key *tpmKey) (*keymanagerbase.KeyEntry, error) {
+	var keyType keymanagerv1.KeyType
+	switch {
+	case key.public.Type == tpm2.AlgECC && key.public.ECCParameters.CurveID == tpm2.CurveNISTP256:
+		keyType = keymanagerv1.KeyType_EC_P256
+	case key.public.Type == tpm2.AlgECC && key.public.ECCParameters.CurveID == tpm2.CurveNISTP384:
+		keyType = keymanagerv1.KeyType_EC_P384
+	case key.public.Type == tpm2.AlgRSA && key.public.RSAParameters.KeyBits == 2048:
+		keyType = keymanagerv1.KeyType_RSA_2048
+	case key.public.Type == tpm2.AlgRSA && key.public.RSAParameters.KeyBits == 4096:
+		keyType = keymanagerv1.KeyType_RSA_4096
+	default:
+		return nil, errors.New("unsupported key type")
+	}
+	return keymanagerbase.MakeKeyEntryFromSigner(id, keyType, key)
+}
+
+func keysPath(dir string) string {
+	return filepath.Join(dir, "keys.json")
+}
+
+// generator creates the keys inside of the TPM.
+type generator struct {
+	tpm *tpmDevice
+}
+
+func (g *generator) GenerateRSA2048Key() (crypto.Signer, error) {
+	return g.generateKey(rsaKeyTemplate(2048))
+}
+
+func (g *generator) GenerateRSA4096Key() (crypto.Signer, error) {
+	return g.generateKey(rsaKeyTemplate(4096))
+}
+
+func (g *generator) GenerateEC256Key() (crypto.Signer, error) {
+	return g.generateKey(eccKeyTemplate(tpm2.CurveNISTP256))
+}
+
+func (g *generator) GenerateEC384Key() (crypto.Signer, error) {
+	return g.generateKey(eccKeyTemplate(tpm2.CurveNISTP384))
+}
+
+func (g *generator) generateKey(template tpm2.Public) (crypto.Signer, error) {
+	key, err := g.tpm.createKey(template)
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "unable to create key in TPM: %v", err)
+	}
+	return key, nil
+}
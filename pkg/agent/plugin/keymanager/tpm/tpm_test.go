@@ -0,0 +1,237 @@
+//go:build !darwin
+
+package tpm
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/spire/pkg/agent/plugin/keymanager"
+	keymanagertest "github.com/spiffe/spire/pkg/agent/plugin/keymanager/test"
+	"github.com/spiffe/spire/test/plugintest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/spiffe/spire/test/tpmsimulator"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	tpmDevicePath          = "/dev/tpmrm0"
+	ownerHierarchyPassword = "owner-password"
+)
+
+func TestKeyManagerContract(t *testing.T) {
+	sim := newSimulator(t)
+
+	keymanagertest.Test(t, keymanagertest.Config{
+		Create: func(t *testing.T) keymanager.KeyManager {
+			dir := spiretest.TempDir(t)
+			km, err := loadPlugin(t, sim, directoryConfig(dir))
+			require.NoError(t, err)
+			return km
+		},
+		// The simulator, like most TPMs, does not support 4096-bit RSA keys
+		UnsupportedKeyTypes: []keymanager.KeyType{keymanager.RSA4096},
+	})
+}
+
+func TestConfigure(t *testing.T) {
+	sim := newSimulator(t)
+
+	t.Run("malformed configuration", func(t *testing.T) {
+		_, err := loadPlugin(t, sim, "blah")
+		spiretest.RequireGRPCStatusContains(t, err, codes.InvalidArgument, "unable to decode configuration")
+	})
+	t.Run("missing directory", func(t *testing.T) {
+		_, err := loadPlugin(t, sim, "")
+		spiretest.RequireGRPCStatus(t, err, codes.InvalidArgument, "directory must be configured")
+	})
+	t.Run("directory created if missing", func(t *testing.T) {
+		dir := filepath.Join(spiretest.TempDir(t), "no-such-dir")
+		_, err := loadPlugin(t, sim, directoryConfig(dir))
+		require.NoError(t, err)
+		require.DirExists(t, dir)
+	})
+	t.Run("autodetection fails", func(t *testing.T) {
+		p := newTestKeyManager(sim)
+		p.hooks.autoDetectTPMPath = func(string) (string, error) {
+			return "", errors.New("no TPM found")
+		}
+		_, err := loadKeyManager(t, p, directoryConfig(spiretest.TempDir(t)))
+		spiretest.RequireGRPCStatus(t, err, codes.Internal, "tpm autodetection failed: no TPM found")
+	})
+	t.Run("keys file is malformed", func(t *testing.T) {
+		dir := spiretest.TempDir(t)
+		require.NoError(t, os.WriteFile(keysPath(dir), []byte("{"), 0600))
+		_, err := loadPlugin(t, sim, directoryConfig(dir))
+		spiretest.RequireGRPCStatusContains(t, err, codes.Internal, "unable to decode keys JSON")
+	})
+}
+
+func TestGenerateKeyBeforeConfigure(t *testing.T) {
+	sim := newSimulator(t)
+
+	km := new(keymanager.V1)
+	plugintest.Load(t, asBuiltIn(newTestKeyManager(sim)), km)
+
+	_, err := km.GenerateKey(context.Background(), "id", keymanager.ECP256)
+	spiretest.RequireGRPCStatus(t, err, codes.FailedPrecondition, "keymanager(tpm): failed to generate key: not configured")
+}
+
+func TestGenerateKeyPersistence(t *testing.T) {
+	sim := newSimulator(t)
+	dir := spiretest.TempDir(t)
+
+	km, err := loadPlugin(t, sim, directoryConfig(dir))
+	require.NoError(t, err)
+	keyIn, err := km.GenerateKey(context.Background(), "id", keymanager.ECP256)
+	require.NoError(t, err)
+
+	// Only the TPM wrapped blobs are persisted
+	data, err := os.ReadFile(keysPath(dir))
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "PRIVATE KEY")
+
+	// reload the plugin. original key should have persisted and still be
+	// usable for signing.
+	km, err = loadPlugin(t, sim, directoryConfig(dir))
+	require.NoError(t, err)
+	keyOut, err := km.GetKey(context.Background(), "id")
+	require.NoError(t, err)
+	require.Equal(t, publicKeyBytes(t, keyIn), publicKeyBytes(t, keyOut))
+	requireSignatureVerifies(t, keyOut)
+}
+
+func TestKeysDroppedWhenTPMChanges(t *testing.T) {
+	dir := spiretest.TempDir(t)
+
+	sim := newSimulator(t)
+	km, err := loadPlugin(t, sim, directoryConfig(dir))
+	require.NoError(t, err)
+	_, err = km.GenerateKey(context.Background(), "id", keymanager.ECP256)
+	require.NoError(t, err)
+	sim.Close()
+
+	// A new simulator is seeded differently, so the storage root key, and
+	// therefore the keys wrapped by it, are not the same.
+	sim = newSimulator(t)
+	log, hook := test.NewNullLogger()
+	km, err = loadPlugin(t, sim, directoryConfig(dir), plugintest.Log(log))
+	require.NoError(t, err)
+
+	keys, err := km.GetKeys(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	require.Equal(t, logrus.WarnLevel, entry.Level)
+	require.Equal(t, "Dropping key that cannot be loaded into the TPM", entry.Message)
+	require.Equal(t, "id", entry.Data["key_id"])
+
+	// A new key can be generated in place of the dropped one
+	key, err := km.GenerateKey(context.Background(), "id", keymanager.ECP256)
+	require.NoError(t, err)
+	requireSignatureVerifies(t, key)
+}
+
+func TestTPMHeldOpenUntilClosed(t *testing.T) {
+	sim := newSimulator(t)
+	p := newTestKeyManager(sim)
+	var opened, closed int
+	p.hooks.openTPM = func(paths ...string) (io.ReadWriteCloser, error) {
+		rwc, err := sim.OpenTPM(paths...)
+		if err != nil {
+			return nil, err
+		}
+		opened++
+		return countingCloser{ReadWriteCloser: rwc, closed: &closed}, nil
+	}
+
+	km := new(keymanager.V1)
+	plugintest.Load(t, asBuiltIn(p), km, plugintest.Configure(directoryConfig(spiretest.TempDir(t))))
+
+	key, err := km.GenerateKey(context.Background(), "id", keymanager.ECP256)
+	require.NoError(t, err)
+	for range 3 {
+		requireSignatureVerifies(t, key)
+	}
+
+	// The TPM is opened once, when configured, and not for each operation
+	require.Equal(t, 1, opened)
+	require.Equal(t, 0, closed)
+
+	require.NoError(t, p.Close())
+	require.Equal(t, 1, closed)
+}
+
+type countingCloser struct {
+	io.ReadWriteCloser
+	closed *int
+}
+
+func (c countingCloser) Close() error {
+	*c.closed++
+	return c.ReadWriteCloser.Close()
+}
+
+func newSimulator(t *testing.T) *tpmsimulator.TPMSimulator {
+	sim, err := tpmsimulator.New("endorsement-password", ownerHierarchyPassword)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sim.Close() })
+	return sim
+}
+
+func newTestKeyManager(sim *tpmsimulator.TPMSimulator) *KeyManager {
+	p := newKeyManager()
+	p.hooks.openTPM = func(paths ...string) (io.ReadWriteCloser, error) {
+		return sim.OpenTPM(paths...)
+	}
+	p.hooks.autoDetectTPMPath = func(string) (string, error) {
+		return tpmDevicePath, nil
+	}
+	return p
+}
+
+func directoryConfig(dir string) string {
+	return fmt.Sprintf("directory = %q\nowner_hierarchy_password = %q", dir, ownerHierarchyPassword)
+}
+
+func loadPlugin(t *testing.T, sim *tpmsimulator.TPMSimulator, config string, options ...plugintest.Option) (keymanager.KeyManager, error) {
+	return loadKeyManager(t, newTestKeyManager(sim), config, options...)
+}
+
+func loadKeyManager(t *testing.T, p *KeyManager, config string, options ...plugintest.Option) (keymanager.KeyManager, error) {
+	km := new(keymanager.V1)
+	var configErr error
+	plugintest.Load(t, asBuiltIn(p), km, append(options,
+		plugintest.Configure(config),
+		plugintest.CaptureConfigureError(&configErr),
+	)...)
+	return km, configErr
+}
+
+func requireSignatureVerifies(t *testing.T, key keymanager.Key) {
+	template := &x509.Certificate{SerialNumber: big.NewInt(1)}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(certDER)
+	require.NoError(t, err)
+	require.NoError(t, cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature))
+}
+
+func publicKeyBytes(t *testing.T, key keymanager.Key) []byte {
+	b, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+	return b
+}
@@ -0,0 +1,162 @@
+package jwtoidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/hcl"
+	nodeattestorv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/agent/nodeattestor/v1"
+	configv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/service/common/config/v1"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/common/plugin/jwtoidc"
+	"github.com/spiffe/spire/pkg/common/pluginconf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	pluginName = jwtoidc.PluginName
+)
+
+func BuiltIn() catalog.BuiltIn {
+	return builtin(New())
+}
+
+func builtin(p *AttestorPlugin) catalog.BuiltIn {
+	return catalog.MakeBuiltIn(pluginName,
+		nodeattestorv1.NodeAttestorPluginServer(p),
+		configv1.ConfigServiceServer(p),
+	)
+}
+
+// New creates a new JWT/OIDC attestor plugin
+func New() *AttestorPlugin {
+	return &AttestorPlugin{}
+}
+
+// AttestorPlugin is a JWT/OIDC attestor plugin. It sends an ID token issued
+// to the agent host, e.g. by a CI system, to the server.
+type AttestorPlugin struct {
+	nodeattestorv1.UnsafeNodeAttestorServer
+	configv1.UnsafeConfigServer
+
+	mu     sync.RWMutex
+	config *attestorConfig
+}
+
+// AttestorConfig holds configuration for AttestorPlugin
+type AttestorConfig struct {
+	// File path of the token
+	TokenPath string `hcl:"token_path"`
+	// Name of the environment variable holding the token
+	TokenEnv string `hcl:"token_env"`
+}
+
+type attestorConfig struct {
+	tokenPath string
+	tokenEnv  string
+}
+
+func buildConfig(_ catalog.CoreConfig, hclText string, status *pluginconf.Status) *attestorConfig {
+	hclConfig := new(AttestorConfig)
+	if err := hcl.Decode(hclConfig, hclText); err != nil {
+		status.ReportErrorf("unable to decode configuration: %v", err)
+		return nil
+	}
+
+	switch {
+	case hclConfig.TokenPath == "" && hclConfig.TokenEnv == "":
+		status.ReportError("one of token_path or token_env is required")
+	case hclConfig.TokenPath != "" && hclConfig.TokenEnv != "":
+		status.ReportError("token_path and token_env are mutually exclusive")
+	}
+
+	return &attestorConfig{
+		tokenPath: hclConfig.TokenPath,
+		tokenEnv:  hclConfig.TokenEnv,
+	}
+}
+
+// AidAttestation loads the token from the configured file or environment
+// variable. The token is read on every attestation since tokens issued to
+// CI jobs are short lived and get replaced.
+func (p *AttestorPlugin) AidAttestation(stream nodeattestorv1.NodeAttestor_AidAttestationServer) error {
+	config, err := p.getConfig()
+	if err != nil {
+		return err
+	}
+
+	token, err := loadToken(config)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "unable to load token: %v", err)
+	}
+
+	payload, err := json.Marshal(jwtoidc.AttestationData{
+		Token: token,
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to marshal attestation data: %v", err)
+	}
+
+	return stream.Send(&nodeattestorv1.PayloadOrChallengeResponse{
+		Data: &nodeattestorv1.PayloadOrChallengeResponse_Payload{
+			Payload: payload,
+		},
+	})
+}
+
+// Configure decodes JSON config from request and populates AttestorPlugin with it
+func (p *AttestorPlugin) Configure(_ context.Context, req *configv1.ConfigureRequest) (*configv1.ConfigureResponse, error) {
+	newConfig, _, err := pluginconf.Build(req, buildConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.config = newConfig
+
+	return &configv1.ConfigureResponse{}, nil
+}
+
+func (p *AttestorPlugin) Validate(_ context.Context, req *configv1.ValidateRequest) (*configv1.ValidateResponse, error) {
+	_, notes, err := pluginconf.Build(req, buildConfig)
+
+	return &configv1.ValidateResponse{
+		Valid: err == nil,
+		Notes: notes,
+	}, nil
+}
+
+func (p *AttestorPlugin) getConfig() (*attestorConfig, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.config == nil {
+		return nil, status.Error(codes.FailedPrecondition, "not configured")
+	}
+	return p.config, nil
+}
+
+func loadToken(config *attestorConfig) (string, error) {
+	if config.tokenEnv != "" {
+		token := strings.TrimSpace(os.Getenv(config.tokenEnv))
+		if token == "" {
+			return "", fmt.Errorf("environment variable %q is empty", config.tokenEnv)
+		}
+		return token, nil
+	}
+
+	data, err := os.ReadFile(config.tokenPath)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("%q is empty", config.tokenPath)
+	}
+	return token, nil
+}
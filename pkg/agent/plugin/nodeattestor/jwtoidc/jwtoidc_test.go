@@ -0,0 +1,114 @@
+package jwtoidc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/agent/plugin/nodeattestor"
+	nodeattestortest "github.com/spiffe/spire/pkg/agent/plugin/nodeattestor/test"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/test/plugintest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	streamBuilder = nodeattestortest.ServerStream(pluginName)
+)
+
+func TestConfigure(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		config    string
+		expectErr string
+	}{
+		{
+			name:      "malformed",
+			config:    "malformed",
+			expectErr: "unable to decode configuration",
+		},
+		{
+			name:      "no token source",
+			config:    "",
+			expectErr: "one of token_path or token_env is required",
+		},
+		{
+			name: "both token sources",
+			config: `
+				token_path = "/token"
+				token_env = "TOKEN"
+			`,
+			expectErr: "token_path and token_env are mutually exclusive",
+		},
+		{
+			name:   "success",
+			config: `token_path = "/token"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			loadPlugin(t, plugintest.CaptureConfigureError(&err), plugintest.Configure(tt.config))
+			if tt.expectErr != "" {
+				spiretest.RequireGRPCStatusContains(t, err, codes.InvalidArgument, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAttestNotConfigured(t *testing.T) {
+	na := new(nodeattestor.V1)
+	plugintest.Load(t, BuiltIn(), na)
+	err := na.Attest(context.Background(), streamBuilder.Build())
+	spiretest.RequireGRPCStatusContains(t, err, codes.FailedPrecondition, "nodeattestor(jwt_oidc): not configured")
+}
+
+func TestAttestWithTokenPath(t *testing.T) {
+	dir := t.TempDir()
+	tokenPath := filepath.Join(dir, "token")
+	na := loadPlugin(t, plugintest.Configuref(`token_path = %q`, tokenPath))
+
+	// missing file
+	err := na.Attest(context.Background(), streamBuilder.Build())
+	spiretest.RequireGRPCStatusContains(t, err, codes.InvalidArgument, "nodeattestor(jwt_oidc): unable to load token")
+
+	// empty file
+	require.NoError(t, os.WriteFile(tokenPath, []byte("\n"), 0600))
+	err = na.Attest(context.Background(), streamBuilder.Build())
+	spiretest.RequireGRPCStatusContains(t, err, codes.InvalidArgument, fmt.Sprintf("nodeattestor(jwt_oidc): unable to load token: %q is empty", tokenPath))
+
+	// success, trailing whitespace is trimmed
+	require.NoError(t, os.WriteFile(tokenPath, []byte("TOKEN\n"), 0600))
+	err = na.Attest(context.Background(), streamBuilder.ExpectAndBuild([]byte(`{"token":"TOKEN"}`)))
+	require.NoError(t, err)
+}
+
+func TestAttestWithTokenEnv(t *testing.T) {
+	na := loadPlugin(t, plugintest.Configure(`token_env = "SPIRE_TEST_JWT_OIDC_TOKEN"`))
+
+	// unset variable
+	t.Setenv("SPIRE_TEST_JWT_OIDC_TOKEN", "")
+	err := na.Attest(context.Background(), streamBuilder.Build())
+	spiretest.RequireGRPCStatus(t, err, codes.InvalidArgument, `nodeattestor(jwt_oidc): unable to load token: environment variable "SPIRE_TEST_JWT_OIDC_TOKEN" is empty`)
+
+	// success
+	t.Setenv("SPIRE_TEST_JWT_OIDC_TOKEN", "TOKEN")
+	err = na.Attest(context.Background(), streamBuilder.ExpectAndBuild([]byte(`{"token":"TOKEN"}`)))
+	require.NoError(t, err)
+}
+
+func loadPlugin(t *testing.T, options ...plugintest.Option) nodeattestor.NodeAttestor {
+	na := new(nodeattestor.V1)
+	plugintest.Load(t, BuiltIn(), na, append([]plugintest.Option{
+		plugintest.CoreConfig(catalog.CoreConfig{
+			TrustDomain: spiffeid.RequireTrustDomainFromString("example.org"),
+		}),
+	}, options...)...)
+	return na
+}
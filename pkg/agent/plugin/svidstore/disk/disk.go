@@ -0,0 +1,488 @@
+package disk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl"
+	svidstorev1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/agent/svidstore/v1"
+	configv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/service/common/config/v1"
+	"github.com/spiffe/spire/pkg/agent/plugin/svidstore"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/common/pluginconf"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	pluginName = "disk"
+
+	defaultCertFileName   = "svid.pem"
+	defaultKeyFileName    = "svid_key.pem"
+	defaultBundleFileName = "bundle.pem"
+	defaultCertMode       = 0o644
+	defaultKeyMode        = 0o600
+	defaultReloadTimeout  = 10 * time.Second
+
+	dirMode = 0o755
+
+	// dataLinkName is the name of the symlink, next to the configured file
+	// names, that points to the version directory holding the current files.
+	dataLinkName = ".data"
+
+	// versionDirPrefix prefixes the name of the directories holding each
+	// version of the files.
+	versionDirPrefix = ".version-"
+)
+
+func BuiltIn() catalog.BuiltIn {
+	return builtin(New())
+}
+
+func builtin(p *DiskPlugin) catalog.BuiltIn {
+	return catalog.MakeBuiltIn(pluginName,
+		svidstorev1.SVIDStorePluginServer(p),
+		configv1.ConfigServiceServer(p),
+	)
+}
+
+func New() *DiskPlugin {
+	p := &DiskPlugin{}
+	p.hooks.lookupOwner = lookupOwner
+	p.hooks.runCommand = runCommand
+
+	return p
+}
+
+type Configuration struct {
+	Directory               string   `hcl:"directory" json:"directory"`
+	CertFileName            string   `hcl:"cert_file_name" json:"cert_file_name"`
+	KeyFileName             string   `hcl:"key_file_name" json:"key_file_name"`
+	BundleFileName          string   `hcl:"bundle_file_name" json:"bundle_file_name"`
+	IncludeFederatedBundles bool     `hcl:"include_federated_bundles" json:"include_federated_bundles"`
+	Owner                   string   `hcl:"owner" json:"owner"`
+	Group                   string   `hcl:"group" json:"group"`
+	CertFileMode            string   `hcl:"cert_file_mode" json:"cert_file_mode"`
+	KeyFileMode             string   `hcl:"key_file_mode" json:"key_file_mode"`
+	ReloadCommand           []string `hcl:"reload_command" json:"reload_command"`
+	ReloadTimeout           string   `hcl:"reload_timeout" json:"reload_timeout"`
+}
+
+type diskConfig struct {
+	directory               string
+	certFileName            string
+	keyFileName             string
+	bundleFileName          string
+	includeFederatedBundles bool
+	owner                   string
+	group                   string
+	certMode                fs.FileMode
+	keyMode                 fs.FileMode
+	reloadCommand           []string
+	reloadTimeout           time.Duration
+}
+
+func (p *DiskPlugin) buildConfig(_ catalog.CoreConfig, hclText string, status *pluginconf.Status) *diskConfig {
+	newConfig := &Configuration{}
+	if err := hcl.Decode(newConfig, hclText); err != nil {
+		status.ReportErrorf("unable to decode configuration: %v", err)
+		return nil
+	}
+
+	config := &diskConfig{
+		directory:               newConfig.Directory,
+		certFileName:            stringOrDefault(newConfig.CertFileName, defaultCertFileName),
+		keyFileName:             stringOrDefault(newConfig.KeyFileName, defaultKeyFileName),
+		bundleFileName:          stringOrDefault(newConfig.BundleFileName, defaultBundleFileName),
+		includeFederatedBundles: newConfig.IncludeFederatedBundles,
+		owner:                   newConfig.Owner,
+		group:                   newConfig.Group,
+		certMode:                defaultCertMode,
+		keyMode:                 defaultKeyMode,
+		reloadCommand:           newConfig.ReloadCommand,
+		reloadTimeout:           defaultReloadTimeout,
+	}
+
+	switch {
+	case config.directory == "":
+		status.ReportError("directory is required")
+	case !filepath.IsAbs(config.directory):
+		status.ReportError("directory must be an absolute path")
+	}
+
+	for _, file := range []struct{ key, name string }{
+		{key: "cert_file_name", name: config.certFileName},
+		{key: "key_file_name", name: config.keyFileName},
+		{key: "bundle_file_name", name: config.bundleFileName},
+	} {
+		if !isPlainFileName(file.name) {
+			status.ReportErrorf("%s %q must be a plain file name", file.key, file.name)
+		}
+	}
+	if config.certFileName == config.keyFileName || config.certFileName == config.bundleFileName || config.keyFileName == config.bundleFileName {
+		status.ReportError("cert_file_name, key_file_name and bundle_file_name must be distinct")
+	}
+
+	if newConfig.CertFileMode != "" {
+		mode, err := parseFileMode(newConfig.CertFileMode)
+		if err != nil {
+			status.ReportErrorf("invalid cert_file_mode: %v", err)
+		}
+		config.certMode = mode
+	}
+	if newConfig.KeyFileMode != "" {
+		mode, err := parseFileMode(newConfig.KeyFileMode)
+		if err != nil {
+			status.ReportErrorf("invalid key_file_mode: %v", err)
+		}
+		config.keyMode = mode
+	}
+
+	if _, _, err := p.hooks.lookupOwner(config.owner, config.group); err != nil {
+		status.ReportErrorf("invalid ownership: %v", err)
+	}
+
+	if newConfig.ReloadTimeout != "" {
+		if len(config.reloadCommand) == 0 {
+			status.ReportError("reload_timeout requires reload_command to be set")
+		}
+		timeout, err := time.ParseDuration(newConfig.ReloadTimeout)
+		switch {
+		case err != nil:
+			status.ReportErrorf("invalid reload_timeout: %v", err)
+		case timeout <= 0:
+			status.ReportError("reload_timeout must be positive")
+		default:
+			config.reloadTimeout = timeout
+		}
+	}
+	if len(config.reloadCommand) > 0 && config.reloadCommand[0] == "" {
+		status.ReportError("reload_command must start with the program to run")
+	}
+
+	return config
+}
+
+// DiskPlugin is an SVIDStore plugin that writes the X509-SVIDs of the
+// entries the agent is entitled to as PEM files on the local filesystem.
+type DiskPlugin struct {
+	svidstorev1.UnsafeSVIDStoreServer
+	configv1.UnsafeConfigServer
+
+	log    hclog.Logger
+	config *diskConfig
+	mtx    sync.RWMutex
+
+	hooks struct {
+		lookupOwner func(owner, group string) (int, int, error)
+		runCommand  func(ctx context.Context, dir string, args []string) ([]byte, error)
+	}
+}
+
+func (p *DiskPlugin) SetLogger(log hclog.Logger) {
+	p.log = log
+}
+
+// Configure configures the DiskPlugin.
+func (p *DiskPlugin) Configure(_ context.Context, req *configv1.ConfigureRequest) (*configv1.ConfigureResponse, error) {
+	newConfig, _, err := pluginconf.Build(req, p.buildConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(newConfig.directory, dirMode); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create directory %q: %v", newConfig.directory, err)
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.config = newConfig
+
+	return &configv1.ConfigureResponse{}, nil
+}
+
+func (p *DiskPlugin) Validate(_ context.Context, req *configv1.ValidateRequest) (*configv1.ValidateResponse, error) {
+	_, notes, err := pluginconf.Build(req, p.buildConfig)
+
+	return &configv1.ValidateResponse{
+		Valid: err == nil,
+		Notes: notes,
+	}, nil
+}
+
+// PutX509SVID writes the specified X509-SVID, its key and bundle to the
+// directory selected by the entry metadata.
+func (p *DiskPlugin) PutX509SVID(ctx context.Context, req *svidstorev1.PutX509SVIDRequest) (*svidstorev1.PutX509SVIDResponse, error) {
+	config, err := p.getConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	opt, err := optionsFromMetadata(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := stringOrDefault(opt.owner, config.owner)
+	group := stringOrDefault(opt.group, config.group)
+	uid, gid, err := p.hooks.lookupOwner(owner, group)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid ownership: %v", err)
+	}
+
+	secret, err := svidstore.SecretFromProto(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse request: %v", err)
+	}
+
+	bundle := secret.Bundle
+	if config.includeFederatedBundles {
+		bundle += concatFederatedBundles(secret.FederatedBundles)
+	}
+
+	root, err := os.OpenRoot(config.directory)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to open directory: %v", err)
+	}
+	defer root.Close()
+
+	if err := root.MkdirAll(opt.path, dirMode); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create directory %q: %v", opt.path, err)
+	}
+
+	files := []svidFile{
+		{name: config.bundleFileName, data: []byte(bundle), mode: config.certMode},
+		{name: config.keyFileName, data: []byte(secret.X509SVIDKey), mode: config.keyMode},
+		{name: config.certFileName, data: []byte(secret.X509SVID), mode: config.certMode},
+	}
+	if err := writeSVIDFiles(root, opt.path, files, uid, gid); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to write files to %q: %v", opt.path, err)
+	}
+
+	dir := filepath.Join(config.directory, opt.path)
+	p.log.With("spiffe_id", secret.SPIFFEID).With("path", dir).Debug("SVID written to disk")
+
+	if len(config.reloadCommand) > 0 {
+		p.reload(ctx, config, dir)
+	}
+
+	return &svidstorev1.PutX509SVIDResponse{}, nil
+}
+
+// DeleteX509SVID removes the files written for the entry selected by the
+// metadata.
+func (p *DiskPlugin) DeleteX509SVID(_ context.Context, req *svidstorev1.DeleteX509SVIDRequest) (*svidstorev1.DeleteX509SVIDResponse, error) {
+	config, err := p.getConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	opt, err := optionsFromMetadata(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := os.OpenRoot(config.directory)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to open directory: %v", err)
+	}
+	defer root.Close()
+
+	removed := 0
+	for _, fileName := range []string{config.certFileName, config.keyFileName, config.bundleFileName} {
+		name := filepath.Join(opt.path, fileName)
+		switch err := root.Remove(name); {
+		case err == nil:
+			removed++
+		case errors.Is(err, fs.ErrNotExist):
+		default:
+			return nil, status.Errorf(codes.Internal, "failed to remove %q: %v", name, err)
+		}
+	}
+
+	// Remove the data symlink and the version directories written on
+	// platforms that support them
+	if err := root.Remove(filepath.Join(opt.path, dataLinkName)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, status.Errorf(codes.Internal, "failed to remove %q: %v", filepath.Join(opt.path, dataLinkName), err)
+	}
+	if err := removeVersionDirs(root, opt.path, ""); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, status.Errorf(codes.Internal, "failed to remove version directories: %v", err)
+	}
+
+	dir := filepath.Join(config.directory, opt.path)
+	if removed == 0 {
+		p.log.With("path", dir).Warn("SVID files not found")
+		return &svidstorev1.DeleteX509SVIDResponse{}, nil
+	}
+
+	p.log.With("path", dir).Debug("SVID files deleted")
+	return &svidstorev1.DeleteX509SVIDResponse{}, nil
+}
+
+func (p *DiskPlugin) getConfig() (*diskConfig, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	if p.config == nil {
+		return nil, status.Error(codes.FailedPrecondition, "not configured")
+	}
+	return p.config, nil
+}
+
+// reload runs the configured reload command. Failures are logged but do not
+// fail the write, since the files are already in place and will be picked up
+// by the next successful reload.
+func (p *DiskPlugin) reload(ctx context.Context, config *diskConfig, dir string) {
+	ctx, cancel := context.WithTimeout(ctx, config.reloadTimeout)
+	defer cancel()
+
+	log := p.log.With("path", dir).With("command", config.reloadCommand[0])
+	output, err := p.hooks.runCommand(ctx, dir, config.reloadCommand)
+	if err != nil {
+		log.With("output", strings.TrimSpace(string(output))).Error("Reload command failed", telemetry.Error, err)
+		return
+	}
+	log.Debug("Reload command succeeded")
+}
+
+type secretOptions struct {
+	path  string
+	owner string
+	group string
+}
+
+func optionsFromMetadata(metadata []string) (*secretOptions, error) {
+	data, err := svidstore.ParseMetadata(metadata)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse Metadata: %v", err)
+	}
+
+	opt := &secretOptions{
+		path:  data["path"],
+		owner: data["owner"],
+		group: data["group"],
+	}
+
+	if opt.path == "" {
+		return nil, status.Error(codes.InvalidArgument, "path is required")
+	}
+	if !filepath.IsLocal(opt.path) {
+		return nil, status.Errorf(codes.InvalidArgument, "path %q must be relative to the configured directory", opt.path)
+	}
+	opt.path = filepath.Clean(opt.path)
+
+	return opt, nil
+}
+
+type svidFile struct {
+	name string
+	data []byte
+	mode fs.FileMode
+}
+
+// createFile writes data to a new file.
+func createFile(root *os.Root, name string, data []byte, mode fs.FileMode, uid, gid int) error {
+	f, err := root.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(f, data, mode, uid, gid); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// removeVersionDirs removes the version directories in dir, except keep.
+func removeVersionDirs(root *os.Root, dir, keep string) error {
+	d, err := root.Open(dir)
+	if err != nil {
+		return err
+	}
+	entries, err := d.ReadDir(-1)
+	d.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), versionDirPrefix) && entry.Name() != keep {
+			if err := root.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeFile(f *os.File, data []byte, mode fs.FileMode, uid, gid int) error {
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	// Apply the mode explicitly since the one provided on creation is
+	// subject to the process umask.
+	if err := f.Chmod(mode); err != nil {
+		return err
+	}
+	if uid != -1 || gid != -1 {
+		if err := f.Chown(uid, gid); err != nil {
+			return err
+		}
+	}
+	return f.Sync()
+}
+
+func runCommand(ctx context.Context, dir string, args []string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...) // #nosec G204 command is provided by the agent operator
+	cmd.Dir = dir
+	return cmd.CombinedOutput()
+}
+
+func concatFederatedBundles(federatedBundles map[string]string) string {
+	trustDomains := make([]string, 0, len(federatedBundles))
+	for td := range federatedBundles {
+		trustDomains = append(trustDomains, td)
+	}
+	sort.Strings(trustDomains)
+
+	var sb strings.Builder
+	for _, td := range trustDomains {
+		sb.WriteString(federatedBundles[td])
+	}
+	return sb.String()
+}
+
+func parseFileMode(s string) (fs.FileMode, error) {
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not an octal file mode", s)
+	}
+	if mode&^0o666 != 0 {
+		return 0, fmt.Errorf("%q may only contain read and write permissions", s)
+	}
+	return fs.FileMode(mode), nil
+}
+
+func isPlainFileName(name string) bool {
+	return name != "" && name != "." && name != ".." && filepath.Base(name) == name && filepath.IsLocal(name)
+}
+
+func stringOrDefault(value, defaultValue string) string {
+	if value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,131 @@
+//go:build !windows
+
+package disk
+
+import (
+	"crypto/rand"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+)
+
+// lookupOwner resolves the owner and group, given either as names or numeric
+// IDs, into the uid and gid to set on written files. Empty values resolve to
+// -1, which leaves the corresponding ID unchanged.
+func lookupOwner(owner, group string) (int, int, error) {
+	uid, err := lookupID(owner, func(name string) (string, error) {
+		u, err := user.Lookup(name)
+		if err != nil {
+			return "", err
+		}
+		return u.Uid, nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	gid, err := lookupID(group, func(name string) (string, error) {
+		g, err := user.LookupGroup(name)
+		if err != nil {
+			return "", err
+		}
+		return g.Gid, nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return uid, gid, nil
+}
+
+func lookupID(name string, lookup func(string) (string, error)) (int, error) {
+	if name == "" {
+		return -1, nil
+	}
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+	id, err := lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(id)
+}
+
+func syncDir(root *os.Root, name string) error {
+	dir, err := root.Open(name)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	return dir.Sync()
+}
+
+// writeSVIDFiles writes the files to a new version directory and then swaps
+// the data symlink to it, so the files change all at once. Each configured
+// file name is a symlink through the data symlink:
+//
+//	svid.pem -> .data/svid.pem
+//	.data -> .version-<random>
+//
+// Readers that open the files by their configured names never see a
+// certificate, key and bundle from different writes, as long as they open
+// them after a single change is observed. The previous version directory is
+// removed once the swap is done.
+func writeSVIDFiles(root *os.Root, dir string, files []svidFile, uid, gid int) error {
+	version := versionDirPrefix + rand.Text()
+	versionDir := filepath.Join(dir, version)
+	if err := root.Mkdir(versionDir, dirMode); err != nil {
+		return err
+	}
+
+	if err := writeVersionDir(root, versionDir, files, uid, gid); err != nil {
+		_ = root.RemoveAll(versionDir)
+		return err
+	}
+	if err := replaceSymlink(root, version, filepath.Join(dir, dataLinkName)); err != nil {
+		_ = root.RemoveAll(versionDir)
+		return err
+	}
+
+	// The file symlinks always point through the data symlink, so they only
+	// need to be created by the first write.
+	for _, file := range files {
+		if err := replaceSymlink(root, filepath.Join(dataLinkName, file.name), filepath.Join(dir, file.name)); err != nil {
+			return err
+		}
+	}
+
+	if err := removeVersionDirs(root, dir, version); err != nil {
+		return err
+	}
+	return syncDir(root, dir)
+}
+
+func writeVersionDir(root *os.Root, versionDir string, files []svidFile, uid, gid int) error {
+	for _, file := range files {
+		if err := createFile(root, filepath.Join(versionDir, file.name), file.data, file.mode, uid, gid); err != nil {
+			return err
+		}
+	}
+	return syncDir(root, versionDir)
+}
+
+// replaceSymlink atomically points name to target, unless it already does.
+func replaceSymlink(root *os.Root, target, name string) error {
+	if current, err := root.Readlink(name); err == nil && current == target {
+		return nil
+	}
+
+	tmpName := filepath.Join(filepath.Dir(name), "."+filepath.Base(name)+".tmp-"+rand.Text())
+	if err := root.Symlink(target, tmpName); err != nil {
+		return err
+	}
+	if err := root.Rename(tmpName, name); err != nil {
+		_ = root.Remove(tmpName)
+		return err
+	}
+	return nil
+}
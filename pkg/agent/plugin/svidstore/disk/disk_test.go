@@ -0,0 +1,597 @@
+package disk
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"github.com/spiffe/spire/pkg/agent/plugin/svidstore"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/common/pemutil"
+	"github.com/spiffe/spire/test/plugintest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/spiffe/spire/test/testca"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	td          = spiffeid.RequireTrustDomainFromString("example.org")
+	federatedTD = spiffeid.RequireTrustDomainFromString("federated.test")
+)
+
+func TestConfigure(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, tt := range []struct {
+		name            string
+		config          string
+		expectCode      codes.Code
+		expectMsgPrefix string
+		expectConfig    *diskConfig
+	}{
+		{
+			name:   "defaults",
+			config: `directory = "` + filepath.ToSlash(dir) + `"`,
+			expectConfig: &diskConfig{
+				directory:      filepath.ToSlash(dir),
+				certFileName:   "svid.pem",
+				keyFileName:    "svid_key.pem",
+				bundleFileName: "bundle.pem",
+				certMode:       0o644,
+				keyMode:        0o600,
+				reloadTimeout:  10 * time.Second,
+			},
+		},
+		{
+			name: "custom values",
+			config: `
+				directory = "` + filepath.ToSlash(dir) + `"
+				cert_file_name = "tls.crt"
+				key_file_name = "tls.key"
+				bundle_file_name = "ca.crt"
+				include_federated_bundles = true
+				cert_file_mode = "0640"
+				key_file_mode = "0400"
+				reload_command = ["systemctl", "reload", "nginx"]
+				reload_timeout = "30s"
+			`,
+			expectConfig: &diskConfig{
+				directory:               filepath.ToSlash(dir),
+				certFileName:            "tls.crt",
+				keyFileName:             "tls.key",
+				bundleFileName:          "ca.crt",
+				includeFederatedBundles: true,
+				certMode:                0o640,
+				keyMode:                 0o400,
+				reloadCommand:           []string{"systemctl", "reload", "nginx"},
+				reloadTimeout:           30 * time.Second,
+			},
+		},
+		{
+			name:            "malformed configuration",
+			config:          "{ no a hcl }",
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "unable to decode configuration",
+		},
+		{
+			name:            "no directory",
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "directory is required",
+		},
+		{
+			name:            "relative directory",
+			config:          `directory = "svids"`,
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "directory must be an absolute path",
+		},
+		{
+			name: "file name with separator",
+			config: `
+				directory = "` + filepath.ToSlash(dir) + `"
+				key_file_name = "../svid_key.pem"
+			`,
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: `key_file_name "../svid_key.pem" must be a plain file name`,
+		},
+		{
+			name: "duplicated file names",
+			config: `
+				directory = "` + filepath.ToSlash(dir) + `"
+				bundle_file_name = "svid.pem"
+			`,
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "cert_file_name, key_file_name and bundle_file_name must be distinct",
+		},
+		{
+			name: "invalid mode",
+			config: `
+				directory = "` + filepath.ToSlash(dir) + `"
+				key_file_mode = "rw"
+			`,
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: `invalid key_file_mode: "rw" is not an octal file mode`,
+		},
+		{
+			name: "executable mode",
+			config: `
+				directory = "` + filepath.ToSlash(dir) + `"
+				cert_file_mode = "0755"
+			`,
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: `invalid cert_file_mode: "0755" may only contain read and write permissions`,
+		},
+		{
+			name: "unknown owner",
+			config: `
+				directory = "` + filepath.ToSlash(dir) + `"
+				owner = "unknown"
+			`,
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "invalid ownership: user unknown not found",
+		},
+		{
+			name: "reload timeout without command",
+			config: `
+				directory = "` + filepath.ToSlash(dir) + `"
+				reload_timeout = "1s"
+			`,
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "reload_timeout requires reload_command to be set",
+		},
+		{
+			name: "invalid reload timeout",
+			config: `
+				directory = "` + filepath.ToSlash(dir) + `"
+				reload_command = ["true"]
+				reload_timeout = "-1s"
+			`,
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "reload_timeout must be positive",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New()
+			p.hooks.lookupOwner = fakeLookupOwner
+
+			var err error
+			plugintest.Load(t, builtin(p), nil,
+				plugintest.CaptureConfigureError(&err),
+				plugintest.CoreConfig(catalog.CoreConfig{
+					TrustDomain: td,
+				}),
+				plugintest.Configure(tt.config),
+			)
+			spiretest.RequireGRPCStatusHasPrefix(t, err, tt.expectCode, tt.expectMsgPrefix)
+			if tt.expectCode != codes.OK {
+				require.Nil(t, p.config)
+				return
+			}
+			require.Equal(t, tt.expectConfig, p.config)
+		})
+	}
+}
+
+func TestPutX509SVID(t *testing.T) {
+	ca := testca.New(t, td)
+	federatedCA := testca.New(t, federatedTD)
+	svid := ca.CreateX509SVID(spiffeid.RequireFromPath(td, "/workload"))
+
+	certPEM := string(pemutil.EncodeCertificates(svid.Certificates))
+	bundlePEM := string(pemutil.EncodeCertificates(ca.X509Authorities()))
+	federatedBundlePEM := string(pemutil.EncodeCertificates(federatedCA.X509Authorities()))
+	keyPEM, err := pemutil.EncodePKCS8PrivateKey(svid.PrivateKey)
+	require.NoError(t, err)
+
+	newReq := func(metadata ...string) *svidstore.X509SVID {
+		return &svidstore.X509SVID{
+			SVID: &svidstore.SVID{
+				SPIFFEID:   svid.ID,
+				CertChain:  svid.Certificates,
+				PrivateKey: svid.PrivateKey,
+				Bundle:     ca.X509Authorities(),
+				ExpiresAt:  svid.Certificates[0].NotAfter,
+			},
+			Metadata: metadata,
+			FederatedBundles: map[string][]*x509.Certificate{
+				federatedTD.IDString(): federatedCA.X509Authorities(),
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name         string
+		config       string
+		req          *svidstore.X509SVID
+		reloadErr    error
+		expectCode   codes.Code
+		expectMsg    string
+		expectDir    string
+		expectBundle string
+		expectReload bool
+	}{
+		{
+			name:         "success",
+			req:          newReq("path:web"),
+			expectDir:    "web",
+			expectBundle: bundlePEM,
+		},
+		{
+			name:         "nested path",
+			req:          newReq("path:apps/web"),
+			expectDir:    filepath.Join("apps", "web"),
+			expectBundle: bundlePEM,
+		},
+		{
+			name:         "federated bundles included",
+			config:       "include_federated_bundles = true",
+			req:          newReq("path:web"),
+			expectDir:    "web",
+			expectBundle: bundlePEM + federatedBundlePEM,
+		},
+		{
+			name:         "reload command",
+			config:       `reload_command = ["reload"]`,
+			req:          newReq("path:web"),
+			expectDir:    "web",
+			expectBundle: bundlePEM,
+			expectReload: true,
+		},
+		{
+			name:         "reload command fails",
+			config:       `reload_command = ["reload"]`,
+			req:          newReq("path:web"),
+			reloadErr:    errors.New("oh no"),
+			expectDir:    "web",
+			expectBundle: bundlePEM,
+			expectReload: true,
+		},
+		{
+			name:       "no path",
+			req:        newReq("owner:1000"),
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "svidstore(disk): path is required",
+		},
+		{
+			name:       "path outside directory",
+			req:        newReq("path:../web"),
+			expectCode: codes.InvalidArgument,
+			expectMsg:  `svidstore(disk): path "../web" must be relative to the configured directory`,
+		},
+		{
+			name:       "invalid metadata",
+			req:        newReq("path"),
+			expectCode: codes.InvalidArgument,
+			expectMsg:  `svidstore(disk): failed to parse Metadata: metadata does not contain a colon: "path"`,
+		},
+		{
+			name:       "unknown owner",
+			req:        newReq("path:web", "owner:unknown"),
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "svidstore(disk): invalid ownership: user unknown not found",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			var reloadDir string
+			p := New()
+			p.hooks.lookupOwner = fakeLookupOwner
+			p.hooks.runCommand = func(ctx context.Context, dir string, args []string) ([]byte, error) {
+				assert.Equal(t, []string{"reload"}, args)
+				reloadDir = dir
+				return nil, tt.reloadErr
+			}
+
+			ss := new(svidstore.V1)
+			plugintest.Load(t, builtin(p), ss,
+				plugintest.CoreConfig(catalog.CoreConfig{
+					TrustDomain: td,
+				}),
+				plugintest.Configure(`directory = "`+filepath.ToSlash(dir)+`"`+"\n"+tt.config),
+			)
+
+			err := ss.PutX509SVID(context.Background(), tt.req)
+			spiretest.RequireGRPCStatus(t, err, tt.expectCode, tt.expectMsg)
+			if tt.expectCode != codes.OK {
+				return
+			}
+
+			svidDir := filepath.Join(dir, tt.expectDir)
+			requireFile(t, filepath.Join(svidDir, "svid.pem"), certPEM, 0o644)
+			requireFile(t, filepath.Join(svidDir, "svid_key.pem"), string(keyPEM), 0o600)
+			requireFile(t, filepath.Join(svidDir, "bundle.pem"), tt.expectBundle, 0o644)
+
+			requireNoLeftovers(t, svidDir)
+
+			if tt.expectReload {
+				require.Equal(t, svidDir, reloadDir)
+			} else {
+				require.Empty(t, reloadDir)
+			}
+		})
+	}
+}
+
+func TestPutX509SVIDReplacesFiles(t *testing.T) {
+	ca := testca.New(t, td)
+	dir := t.TempDir()
+
+	p := New()
+	ss := new(svidstore.V1)
+	plugintest.Load(t, builtin(p), ss,
+		plugintest.CoreConfig(catalog.CoreConfig{
+			TrustDomain: td,
+		}),
+		plugintest.Configure(`
+			directory = "`+filepath.ToSlash(dir)+`"
+			cert_file_mode = "0640"
+			key_file_mode = "0400"
+		`),
+	)
+
+	for range 2 {
+		svid := ca.CreateX509SVID(spiffeid.RequireFromPath(td, "/workload"))
+		err := ss.PutX509SVID(context.Background(), &svidstore.X509SVID{
+			SVID: &svidstore.SVID{
+				SPIFFEID:   svid.ID,
+				CertChain:  svid.Certificates,
+				PrivateKey: svid.PrivateKey,
+				Bundle:     ca.X509Authorities(),
+				ExpiresAt:  svid.Certificates[0].NotAfter,
+			},
+			Metadata: []string{"path:web"},
+		})
+		require.NoError(t, err)
+
+		requireFile(t, filepath.Join(dir, "web", "svid.pem"), string(pemutil.EncodeCertificates(svid.Certificates)), 0o640)
+	}
+	requireMode(t, filepath.Join(dir, "web", "svid_key.pem"), 0o400)
+}
+
+func TestPutX509SVIDSwapsVersions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("files are replaced one at a time on windows")
+	}
+
+	ca := testca.New(t, td)
+	dir := t.TempDir()
+	svidDir := filepath.Join(dir, "web")
+	ss := loadPlugin(t, dir)
+
+	svid := ca.CreateX509SVID(spiffeid.RequireFromPath(td, "/workload"))
+	require.NoError(t, ss.PutX509SVID(context.Background(), newX509SVID(ca, svid)))
+	firstVersion := requireVersionLayout(t, svidDir)
+	firstCert := string(pemutil.EncodeCertificates(svid.Certificates))
+
+	// A write interrupted before the swap leaves a version directory behind
+	// without affecting the files readers see.
+	interrupted := filepath.Join(svidDir, versionDirPrefix+"interrupted")
+	require.NoError(t, os.Mkdir(interrupted, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(interrupted, "svid.pem"), []byte("partial"), 0o644))
+	requireFile(t, filepath.Join(svidDir, "svid.pem"), firstCert, 0o644)
+
+	svid = ca.CreateX509SVID(spiffeid.RequireFromPath(td, "/workload"))
+	require.NoError(t, ss.PutX509SVID(context.Background(), newX509SVID(ca, svid)))
+	secondVersion := requireVersionLayout(t, svidDir)
+	require.NotEqual(t, firstVersion, secondVersion)
+	requireFile(t, filepath.Join(svidDir, "svid.pem"), string(pemutil.EncodeCertificates(svid.Certificates)), 0o644)
+
+	// The previous and the interrupted version directories are removed
+	requireNoLeftovers(t, svidDir)
+	require.NoDirExists(t, filepath.Join(svidDir, firstVersion))
+	require.NoDirExists(t, interrupted)
+}
+
+func TestPutX509SVIDOwnership(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("ownership is not supported on windows")
+	}
+
+	ca := testca.New(t, td)
+	svid := ca.CreateX509SVID(spiffeid.RequireFromPath(td, "/workload"))
+	dir := t.TempDir()
+
+	// Changing the ownership to the current user and group is always allowed.
+	uid := strconv.Itoa(os.Getuid())
+	gid := strconv.Itoa(os.Getgid())
+
+	p := New()
+	ss := new(svidstore.V1)
+	plugintest.Load(t, builtin(p), ss,
+		plugintest.CoreConfig(catalog.CoreConfig{
+			TrustDomain: td,
+		}),
+		plugintest.Configure(`
+			directory = "`+dir+`"
+			owner = "`+uid+`"
+		`),
+	)
+
+	err := ss.PutX509SVID(context.Background(), &svidstore.X509SVID{
+		SVID: &svidstore.SVID{
+			SPIFFEID:   svid.ID,
+			CertChain:  svid.Certificates,
+			PrivateKey: svid.PrivateKey,
+			Bundle:     ca.X509Authorities(),
+			ExpiresAt:  svid.Certificates[0].NotAfter,
+		},
+		Metadata: []string{"path:web", "group:" + gid},
+	})
+	require.NoError(t, err)
+}
+
+func TestDeleteX509SVIDWrittenFiles(t *testing.T) {
+	ca := testca.New(t, td)
+	dir := t.TempDir()
+	ss := loadPlugin(t, dir)
+
+	svid := ca.CreateX509SVID(spiffeid.RequireFromPath(td, "/workload"))
+	require.NoError(t, ss.PutX509SVID(context.Background(), newX509SVID(ca, svid)))
+	require.NoError(t, ss.DeleteX509SVID(context.Background(), []string{"path:web"}))
+
+	entries, err := os.ReadDir(filepath.Join(dir, "web"))
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestDeleteX509SVID(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		metadata   []string
+		files      []string
+		expectCode codes.Code
+		expectMsg  string
+		expectLeft []string
+	}{
+		{
+			name:       "success",
+			metadata:   []string{"path:web"},
+			files:      []string{"svid.pem", "svid_key.pem", "bundle.pem", "other.pem"},
+			expectLeft: []string{"other.pem"},
+		},
+		{
+			name:       "some files missing",
+			metadata:   []string{"path:web"},
+			files:      []string{"svid.pem"},
+			expectLeft: []string{},
+		},
+		{
+			name:     "no files",
+			metadata: []string{"path:web"},
+		},
+		{
+			name:       "no path",
+			metadata:   []string{"owner:1000"},
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "svidstore(disk): path is required",
+		},
+		{
+			name:       "path outside directory",
+			metadata:   []string{"path:/etc"},
+			expectCode: codes.InvalidArgument,
+			expectMsg:  `svidstore(disk): path "/etc" must be relative to the configured directory`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if len(tt.files) > 0 {
+				require.NoError(t, os.Mkdir(filepath.Join(dir, "web"), 0o755))
+			}
+			for _, file := range tt.files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "web", file), []byte("data"), 0o600))
+			}
+
+			ss := new(svidstore.V1)
+			plugintest.Load(t, builtin(New()), ss,
+				plugintest.CoreConfig(catalog.CoreConfig{
+					TrustDomain: td,
+				}),
+				plugintest.Configure(`directory = "`+filepath.ToSlash(dir)+`"`),
+			)
+
+			err := ss.DeleteX509SVID(context.Background(), tt.metadata)
+			spiretest.RequireGRPCStatus(t, err, tt.expectCode, tt.expectMsg)
+			if tt.expectLeft == nil {
+				return
+			}
+
+			entries, err := os.ReadDir(filepath.Join(dir, "web"))
+			require.NoError(t, err)
+			left := []string{}
+			for _, entry := range entries {
+				left = append(left, entry.Name())
+			}
+			require.Equal(t, tt.expectLeft, left)
+		})
+	}
+}
+
+func fakeLookupOwner(owner, group string) (int, int, error) {
+	if owner == "unknown" {
+		return 0, 0, errors.New("user unknown not found")
+	}
+	return -1, -1, nil
+}
+
+func requireFile(t *testing.T, path, expectData string, expectMode os.FileMode) {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, expectData, string(data))
+	requireMode(t, path, expectMode)
+}
+
+func requireMode(t *testing.T, path string, expectMode os.FileMode) {
+	if runtime.GOOS == "windows" {
+		return
+	}
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, expectMode, info.Mode().Perm())
+}
+
+func loadPlugin(t *testing.T, dir string) *svidstore.V1 {
+	ss := new(svidstore.V1)
+	plugintest.Load(t, builtin(New()), ss,
+		plugintest.CoreConfig(catalog.CoreConfig{
+			TrustDomain: td,
+		}),
+		plugintest.Configure(`directory = "`+filepath.ToSlash(dir)+`"`),
+	)
+	return ss
+}
+
+func newX509SVID(ca *testca.CA, svid *x509svid.SVID) *svidstore.X509SVID {
+	return &svidstore.X509SVID{
+		SVID: &svidstore.SVID{
+			SPIFFEID:   svid.ID,
+			CertChain:  svid.Certificates,
+			PrivateKey: svid.PrivateKey,
+			Bundle:     ca.X509Authorities(),
+			ExpiresAt:  svid.Certificates[0].NotAfter,
+		},
+		Metadata: []string{"path:web"},
+	}
+}
+
+// requireVersionLayout requires the files to be symlinks through the data
+// symlink and returns the current version directory.
+func requireVersionLayout(t *testing.T, dir string) string {
+	version, err := os.Readlink(filepath.Join(dir, dataLinkName))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(version, versionDirPrefix), "unexpected data symlink target %q", version)
+	require.DirExists(t, filepath.Join(dir, version))
+
+	for _, name := range []string{"svid.pem", "svid_key.pem", "bundle.pem"} {
+		target, err := os.Readlink(filepath.Join(dir, name))
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(dataLinkName, name), target)
+	}
+	return version
+}
+
+// requireNoLeftovers requires that no temporary file and no version directory
+// other than the current one are left behind.
+func requireNoLeftovers(t *testing.T, dir string) {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	versions := 0
+	for _, entry := range entries {
+		require.NotContains(t, entry.Name(), ".tmp-", "temporary files were left behind")
+		if strings.HasPrefix(entry.Name(), versionDirPrefix) {
+			versions++
+		}
+	}
+	require.LessOrEqual(t, versions, 1, "previous versions were left behind")
+}
@@ -0,0 +1,50 @@
+//go:build windows
+
+package disk
+
+import (
+	"crypto/rand"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+func lookupOwner(owner, group string) (int, int, error) {
+	if owner != "" || group != "" {
+		return 0, 0, errors.New("owner and group are not supported on this platform")
+	}
+	return -1, -1, nil
+}
+
+func syncDir(*os.Root, string) error {
+	return nil
+}
+
+// writeSVIDFiles atomically replaces each file in turn. Symlinks cannot be
+// relied upon on Windows, so the files are not replaced all at once. They are
+// written in the given order, with the certificate last, so readers that
+// reload once the certificate changes see the matching key and bundle.
+func writeSVIDFiles(root *os.Root, dir string, files []svidFile, uid, gid int) error {
+	for _, file := range files {
+		if err := atomicWrite(root, filepath.Join(dir, file.name), file.data, file.mode, uid, gid); err != nil {
+			return err
+		}
+	}
+	return syncDir(root, dir)
+}
+
+// atomicWrite writes data to a temporary file next to name and renames it
+// into place, so readers never observe a partially written file.
+func atomicWrite(root *os.Root, name string, data []byte, mode fs.FileMode, uid, gid int) error {
+	tmpName := filepath.Join(filepath.Dir(name), "."+filepath.Base(name)+".tmp-"+rand.Text())
+	if err := createFile(root, tmpName, data, mode, uid, gid); err != nil {
+		_ = root.Remove(tmpName)
+		return err
+	}
+	if err := root.Rename(tmpName, name); err != nil {
+		_ = root.Remove(tmpName)
+		return err
+	}
+	return nil
+}
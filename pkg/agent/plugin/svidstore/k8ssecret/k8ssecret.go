@@ -0,0 +1,382 @@
+package k8ssecret
+
+import (
+	"context"
+	"crypto/sha1" //nolint: gosec // We use sha1 to hash trust domain names in 128 bytes to avoid label value restrictions
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	svidstorev1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/agent/svidstore/v1"
+	configv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/service/common/config/v1"
+	"github.com/spiffe/spire/pkg/agent/plugin/svidstore"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/common/pluginconf"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	pluginName = "k8s_secret"
+
+	// spireSVIDLabel is set on every Secret managed by the plugin, with the
+	// hashed trust domain as value. Secrets without it are never modified.
+	spireSVIDLabel = "spire-svid"
+
+	// spiffeIDAnnotation records the SPIFFE ID of the stored SVID.
+	spiffeIDAnnotation = "spiffe.io/spiffe-id"
+
+	// federatedBundleKeyPrefix prefixes the Secret keys that hold the
+	// bundles of federated trust domains.
+	federatedBundleKeyPrefix = "federated."
+	federatedBundleKeySuffix = ".crt"
+)
+
+func BuiltIn() catalog.BuiltIn {
+	return builtin(New())
+}
+
+func builtin(p *SecretPlugin) catalog.BuiltIn {
+	return catalog.MakeBuiltIn(pluginName,
+		svidstorev1.SVIDStorePluginServer(p),
+		configv1.ConfigServiceServer(p),
+	)
+}
+
+func New() *SecretPlugin {
+	p := &SecretPlugin{}
+	p.hooks.newClient = newClient
+
+	return p
+}
+
+type Configuration struct {
+	KubeConfigFilePath      string `hcl:"kube_config_file_path" json:"kube_config_file_path"`
+	Namespace               string `hcl:"namespace" json:"namespace"`
+	IncludeFederatedBundles bool   `hcl:"include_federated_bundles" json:"include_federated_bundles"`
+}
+
+func buildConfig(_ catalog.CoreConfig, hclText string, status *pluginconf.Status) *Configuration {
+	newConfig := &Configuration{}
+	if err := hcl.Decode(newConfig, hclText); err != nil {
+		status.ReportErrorf("unable to decode configuration: %v", err)
+		return nil
+	}
+
+	if newConfig.Namespace != "" {
+		if errs := validation.IsDNS1123Label(newConfig.Namespace); len(errs) > 0 {
+			status.ReportErrorf("invalid namespace %q: %s", newConfig.Namespace, strings.Join(errs, "; "))
+		}
+	}
+
+	return newConfig
+}
+
+// SecretPlugin is an SVIDStore plugin that stores the X509-SVIDs of the
+// entries the agent is entitled to in Kubernetes Secrets of type
+// kubernetes.io/tls.
+type SecretPlugin struct {
+	svidstorev1.UnsafeSVIDStoreServer
+	configv1.UnsafeConfigServer
+
+	log    hclog.Logger
+	config *Configuration
+	client kubernetes.Interface
+	tdHash string
+	mtx    sync.RWMutex
+
+	hooks struct {
+		newClient func(kubeConfigFilePath string) (kubernetes.Interface, error)
+	}
+}
+
+func (p *SecretPlugin) SetLogger(log hclog.Logger) {
+	p.log = log
+}
+
+// Configure configures the SecretPlugin.
+func (p *SecretPlugin) Configure(_ context.Context, req *configv1.ConfigureRequest) (*configv1.ConfigureResponse, error) {
+	newConfig, _, err := pluginconf.Build(req, buildConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := p.hooks.newClient(newConfig.KubeConfigFilePath)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create kubernetes client: %v", err)
+	}
+
+	// Label values are limited to 63 characters, hash td as label
+	tdHash := sha1.Sum([]byte(req.CoreConfiguration.TrustDomain)) //nolint: gosec // We use sha1 to hash trust domain names in 128 bytes to avoid label value restrictions
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.config = newConfig
+	p.client = client
+	p.tdHash = hex.EncodeToString(tdHash[:])
+
+	return &configv1.ConfigureResponse{}, nil
+}
+
+func (p *SecretPlugin) Validate(_ context.Context, req *configv1.ValidateRequest) (*configv1.ValidateResponse, error) {
+	_, notes, err := pluginconf.Build(req, buildConfig)
+
+	return &configv1.ValidateResponse{
+		Valid: err == nil,
+		Notes: notes,
+	}, nil
+}
+
+// PutX509SVID creates or updates the kubernetes.io/tls Secret selected by
+// the entry metadata with the specified X509-SVID.
+func (p *SecretPlugin) PutX509SVID(ctx context.Context, req *svidstorev1.PutX509SVIDRequest) (*svidstorev1.PutX509SVIDResponse, error) {
+	config, client, tdHash, err := p.getState()
+	if err != nil {
+		return nil, err
+	}
+
+	opt, err := optionsFromSecretData(req.Metadata, config.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	secretData, err := svidstore.SecretFromProto(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse request: %v", err)
+	}
+
+	data, err := dataFromSecret(secretData, config.IncludeFederatedBundles)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := client.CoreV1().Secrets(opt.namespace)
+	log := p.log.With("namespace", opt.namespace).With("name", opt.name)
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := secrets.Get(ctx, opt.name, metav1.GetOptions{})
+		switch {
+		case k8serrors.IsNotFound(err):
+			_, err := secrets.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      opt.name,
+					Namespace: opt.namespace,
+					Labels: map[string]string{
+						spireSVIDLabel: tdHash,
+					},
+					Annotations: map[string]string{
+						spiffeIDAnnotation: secretData.SPIFFEID,
+					},
+				},
+				Type: corev1.SecretTypeTLS,
+				Data: data,
+			}, metav1.CreateOptions{})
+			if err != nil {
+				// Handled by the retry loop as a conflict, so the secret
+				// created concurrently is fetched and updated.
+				if k8serrors.IsAlreadyExists(err) {
+					return k8serrors.NewConflict(corev1.Resource("secrets"), opt.name, err)
+				}
+				return status.Errorf(codes.Internal, "failed to create secret: %v", err)
+			}
+			log.Debug("Secret created")
+			return nil
+		case err != nil:
+			return status.Errorf(codes.Internal, "failed to get secret: %v", err)
+		}
+
+		if err := validateSecret(secret, tdHash); err != nil {
+			return err
+		}
+
+		secret.Data = data
+		if secret.Annotations == nil {
+			secret.Annotations = make(map[string]string)
+		}
+		secret.Annotations[spiffeIDAnnotation] = secretData.SPIFFEID
+
+		if _, err := secrets.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			if k8serrors.IsConflict(err) {
+				return err
+			}
+			return status.Errorf(codes.Internal, "failed to update secret: %v", err)
+		}
+		log.Debug("Secret updated")
+		return nil
+	})
+	if err != nil {
+		if k8serrors.IsConflict(err) {
+			return nil, status.Errorf(codes.Aborted, "failed to update secret: %v", err)
+		}
+		return nil, err
+	}
+
+	return &svidstorev1.PutX509SVIDResponse{}, nil
+}
+
+// DeleteX509SVID deletes the Secret selected by the entry metadata.
+func (p *SecretPlugin) DeleteX509SVID(ctx context.Context, req *svidstorev1.DeleteX509SVIDRequest) (*svidstorev1.DeleteX509SVIDResponse, error) {
+	config, client, tdHash, err := p.getState()
+	if err != nil {
+		return nil, err
+	}
+
+	opt, err := optionsFromSecretData(req.Metadata, config.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := client.CoreV1().Secrets(opt.namespace)
+	log := p.log.With("namespace", opt.namespace).With("name", opt.name)
+
+	secret, err := secrets.Get(ctx, opt.name, metav1.GetOptions{})
+	switch {
+	case k8serrors.IsNotFound(err):
+		log.Warn("Secret not found")
+		return &svidstorev1.DeleteX509SVIDResponse{}, nil
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to get secret: %v", err)
+	}
+
+	if err := validateSecret(secret, tdHash); err != nil {
+		return nil, err
+	}
+
+	// Make sure the secret that was validated is the one being deleted.
+	err = secrets.Delete(ctx, opt.name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{
+			UID:             &secret.UID,
+			ResourceVersion: &secret.ResourceVersion,
+		},
+	})
+	switch {
+	case k8serrors.IsNotFound(err):
+		log.Warn("Secret not found")
+		return &svidstorev1.DeleteX509SVIDResponse{}, nil
+	case k8serrors.IsConflict(err):
+		return nil, status.Errorf(codes.Aborted, "failed to delete secret: %v", err)
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to delete secret: %v", err)
+	}
+
+	log.Debug("Secret deleted")
+	return &svidstorev1.DeleteX509SVIDResponse{}, nil
+}
+
+func (p *SecretPlugin) getState() (*Configuration, kubernetes.Interface, string, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	if p.config == nil {
+		return nil, nil, "", status.Error(codes.FailedPrecondition, "not configured")
+	}
+	return p.config, p.client, p.tdHash, nil
+}
+
+type secretOptions struct {
+	namespace string
+	name      string
+}
+
+func optionsFromSecretData(metadata []string, defaultNamespace string) (*secretOptions, error) {
+	data, err := svidstore.ParseMetadata(metadata)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse Metadata: %v", err)
+	}
+
+	opt := &secretOptions{
+		namespace: data["namespace"],
+		name:      data["name"],
+	}
+	if opt.namespace == "" {
+		opt.namespace = defaultNamespace
+	}
+
+	switch {
+	case opt.name == "":
+		return nil, status.Error(codes.InvalidArgument, "secret name is required")
+	case opt.namespace == "":
+		return nil, status.Error(codes.InvalidArgument, "secret namespace is required")
+	}
+	if errs := validation.IsDNS1123Subdomain(opt.name); len(errs) > 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid secret name %q: %s", opt.name, strings.Join(errs, "; "))
+	}
+	if errs := validation.IsDNS1123Label(opt.namespace); len(errs) > 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid secret namespace %q: %s", opt.namespace, strings.Join(errs, "; "))
+	}
+
+	return opt, nil
+}
+
+// dataFromSecret builds the Secret data. Besides the standard
+// kubernetes.io/tls keys, the bundle of each federated trust domain is
+// stored under its own key.
+func dataFromSecret(secretData *svidstore.Data, includeFederatedBundles bool) (map[string][]byte, error) {
+	data := map[string][]byte{
+		corev1.TLSCertKey:       []byte(secretData.X509SVID),
+		corev1.TLSPrivateKeyKey: []byte(secretData.X509SVIDKey),
+	}
+
+	federatedIDs := make([]string, 0, len(secretData.FederatedBundles))
+	for id := range secretData.FederatedBundles {
+		federatedIDs = append(federatedIDs, id)
+	}
+	sort.Strings(federatedIDs)
+
+	caBundle := secretData.Bundle
+	for _, id := range federatedIDs {
+		td, err := spiffeid.TrustDomainFromString(id)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid federated trust domain %q: %v", id, err)
+		}
+		bundle := secretData.FederatedBundles[id]
+		data[federatedBundleKeyPrefix+td.Name()+federatedBundleKeySuffix] = []byte(bundle)
+		if includeFederatedBundles {
+			caBundle += bundle
+		}
+	}
+	data["ca.crt"] = []byte(caBundle)
+
+	return data, nil
+}
+
+// validateSecret expects that the secret was created by this plugin for the
+// same trust domain.
+func validateSecret(secret *corev1.Secret, tdHash string) error {
+	if secret.Labels[spireSVIDLabel] != tdHash {
+		return status.Errorf(codes.InvalidArgument, "secret does not contain the '%s' label for the trust domain", spireSVIDLabel)
+	}
+	if secret.Type != corev1.SecretTypeTLS {
+		return status.Errorf(codes.InvalidArgument, "secret has type %q instead of %q", secret.Type, corev1.SecretTypeTLS)
+	}
+	return nil
+}
+
+func newClient(kubeConfigFilePath string) (kubernetes.Interface, error) {
+	config, err := getKubeConfig(kubeConfigFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return kubernetes.NewForConfig(config)
+}
+
+func getKubeConfig(configPath string) (*rest.Config, error) {
+	if configPath != "" {
+		return clientcmd.BuildConfigFromFlags("", configPath)
+	}
+	return rest.InClusterConfig()
+}
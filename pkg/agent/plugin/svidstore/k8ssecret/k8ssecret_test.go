@@ -0,0 +1,489 @@
+package k8ssecret
+
+import (
+	"context"
+	"crypto/sha1" //nolint: gosec // Used to build the expected trust domain label
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/agent/plugin/svidstore"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/common/pemutil"
+	"github.com/spiffe/spire/test/plugintest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/spiffe/spire/test/testca"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var (
+	td          = spiffeid.RequireTrustDomainFromString("example.org")
+	federatedTD = spiffeid.RequireTrustDomainFromString("federated.test")
+	tdHash      = func() string {
+		sum := sha1.Sum([]byte(td.Name())) //nolint: gosec // Used to build the expected trust domain label
+		return hex.EncodeToString(sum[:])
+	}()
+)
+
+func TestConfigure(t *testing.T) {
+	for _, tt := range []struct {
+		name            string
+		config          string
+		clientErr       error
+		expectCode      codes.Code
+		expectMsgPrefix string
+		expectConfig    *Configuration
+		expectPath      string
+	}{
+		{
+			name:         "in cluster",
+			expectConfig: &Configuration{},
+		},
+		{
+			name: "all values",
+			config: `
+				kube_config_file_path = "/etc/kubeconfig"
+				namespace = "apps"
+				include_federated_bundles = true
+			`,
+			expectConfig: &Configuration{
+				KubeConfigFilePath:      "/etc/kubeconfig",
+				Namespace:               "apps",
+				IncludeFederatedBundles: true,
+			},
+			expectPath: "/etc/kubeconfig",
+		},
+		{
+			name:            "malformed configuration",
+			config:          "{ no a hcl }",
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "unable to decode configuration",
+		},
+		{
+			name:            "invalid namespace",
+			config:          `namespace = "Not_Valid"`,
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: `invalid namespace "Not_Valid"`,
+		},
+		{
+			name:            "new client fails",
+			clientErr:       errors.New("oh no"),
+			expectCode:      codes.Internal,
+			expectMsgPrefix: "failed to create kubernetes client: oh no",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var path string
+			p := New()
+			p.hooks.newClient = func(kubeConfigFilePath string) (kubernetes.Interface, error) {
+				path = kubeConfigFilePath
+				if tt.clientErr != nil {
+					return nil, tt.clientErr
+				}
+				return fake.NewClientset(), nil
+			}
+
+			var err error
+			plugintest.Load(t, builtin(p), nil,
+				plugintest.CaptureConfigureError(&err),
+				plugintest.CoreConfig(catalog.CoreConfig{
+					TrustDomain: td,
+				}),
+				plugintest.Configure(tt.config),
+			)
+			spiretest.RequireGRPCStatusHasPrefix(t, err, tt.expectCode, tt.expectMsgPrefix)
+			if tt.expectCode != codes.OK {
+				require.Nil(t, p.client)
+				return
+			}
+
+			require.Equal(t, tt.expectConfig, p.config)
+			require.Equal(t, tt.expectPath, path)
+			require.Equal(t, tdHash, p.tdHash)
+		})
+	}
+}
+
+func TestPutX509SVID(t *testing.T) {
+	ca := testca.New(t, td)
+	federatedCA := testca.New(t, federatedTD)
+	svid := ca.CreateX509SVID(spiffeid.RequireFromPath(td, "/ingress"))
+
+	certPEM := pemutil.EncodeCertificates(svid.Certificates)
+	bundlePEM := pemutil.EncodeCertificates(ca.X509Authorities())
+	federatedBundlePEM := pemutil.EncodeCertificates(federatedCA.X509Authorities())
+	keyPEM, err := pemutil.EncodePKCS8PrivateKey(svid.PrivateKey)
+	require.NoError(t, err)
+
+	newReq := func(metadata ...string) *svidstore.X509SVID {
+		return &svidstore.X509SVID{
+			SVID: &svidstore.SVID{
+				SPIFFEID:   svid.ID,
+				CertChain:  svid.Certificates,
+				PrivateKey: svid.PrivateKey,
+				Bundle:     ca.X509Authorities(),
+				ExpiresAt:  svid.Certificates[0].NotAfter,
+			},
+			Metadata: metadata,
+			FederatedBundles: map[string][]*x509.Certificate{
+				federatedTD.IDString(): federatedCA.X509Authorities(),
+			},
+		}
+	}
+
+	expectSecret := func(caBundle []byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ingress-tls",
+				Namespace: "apps",
+				Labels: map[string]string{
+					"spire-svid": tdHash,
+				},
+				Annotations: map[string]string{
+					"spiffe.io/spiffe-id": "spiffe://example.org/ingress",
+				},
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: map[string][]byte{
+				"tls.crt":                      certPEM,
+				"tls.key":                      keyPEM,
+				"ca.crt":                       caBundle,
+				"federated.federated.test.crt": federatedBundlePEM,
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name         string
+		config       string
+		objects      []runtime.Object
+		reactor      k8stesting.ReactionFunc
+		req          *svidstore.X509SVID
+		expectCode   codes.Code
+		expectMsg    string
+		expectSecret *corev1.Secret
+	}{
+		{
+			name:         "secret created",
+			req:          newReq("namespace:apps", "name:ingress-tls"),
+			expectSecret: expectSecret(bundlePEM),
+		},
+		{
+			name:         "namespace from configuration",
+			config:       `namespace = "apps"`,
+			req:          newReq("name:ingress-tls"),
+			expectSecret: expectSecret(bundlePEM),
+		},
+		{
+			name:         "federated bundles included in ca.crt",
+			config:       "include_federated_bundles = true",
+			req:          newReq("namespace:apps", "name:ingress-tls"),
+			expectSecret: expectSecret(append(append([]byte{}, bundlePEM...), federatedBundlePEM...)),
+		},
+		{
+			name: "secret updated",
+			objects: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "ingress-tls",
+						Namespace: "apps",
+						Labels: map[string]string{
+							"spire-svid": tdHash,
+						},
+					},
+					Type: corev1.SecretTypeTLS,
+					Data: map[string][]byte{
+						"tls.crt":            []byte("old"),
+						"federated.gone.crt": []byte("old"),
+					},
+				},
+			},
+			req:          newReq("namespace:apps", "name:ingress-tls"),
+			expectSecret: expectSecret(bundlePEM),
+		},
+		{
+			name: "secret not managed by spire",
+			objects: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "ingress-tls",
+						Namespace: "apps",
+					},
+					Type: corev1.SecretTypeTLS,
+				},
+			},
+			req:        newReq("namespace:apps", "name:ingress-tls"),
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "svidstore(k8s_secret): secret does not contain the 'spire-svid' label for the trust domain",
+		},
+		{
+			name: "secret managed for another trust domain",
+			objects: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "ingress-tls",
+						Namespace: "apps",
+						Labels: map[string]string{
+							"spire-svid": "other",
+						},
+					},
+					Type: corev1.SecretTypeTLS,
+				},
+			},
+			req:        newReq("namespace:apps", "name:ingress-tls"),
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "svidstore(k8s_secret): secret does not contain the 'spire-svid' label for the trust domain",
+		},
+		{
+			name: "secret with another type",
+			objects: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "ingress-tls",
+						Namespace: "apps",
+						Labels: map[string]string{
+							"spire-svid": tdHash,
+						},
+					},
+					Type: corev1.SecretTypeOpaque,
+				},
+			},
+			req:        newReq("namespace:apps", "name:ingress-tls"),
+			expectCode: codes.InvalidArgument,
+			expectMsg:  `svidstore(k8s_secret): secret has type "Opaque" instead of "kubernetes.io/tls"`,
+		},
+		{
+			name:       "no name",
+			req:        newReq("namespace:apps"),
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "svidstore(k8s_secret): secret name is required",
+		},
+		{
+			name:       "no namespace",
+			req:        newReq("name:ingress-tls"),
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "svidstore(k8s_secret): secret namespace is required",
+		},
+		{
+			name:       "invalid name",
+			req:        newReq("namespace:apps", "name:Ingress_TLS"),
+			expectCode: codes.InvalidArgument,
+			expectMsg:  `svidstore(k8s_secret): invalid secret name "Ingress_TLS": a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`,
+		},
+		{
+			name:       "invalid metadata",
+			req:        newReq("name"),
+			expectCode: codes.InvalidArgument,
+			expectMsg:  `svidstore(k8s_secret): failed to parse Metadata: metadata does not contain a colon: "name"`,
+		},
+		{
+			name: "create fails",
+			reactor: func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if action.GetVerb() == "create" {
+					return true, nil, errors.New("oh no")
+				}
+				return false, nil, nil
+			},
+			req:        newReq("namespace:apps", "name:ingress-tls"),
+			expectCode: codes.Internal,
+			expectMsg:  "svidstore(k8s_secret): failed to create secret: oh no",
+		},
+		{
+			name: "get fails",
+			reactor: func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if action.GetVerb() == "get" {
+					return true, nil, errors.New("oh no")
+				}
+				return false, nil, nil
+			},
+			req:        newReq("namespace:apps", "name:ingress-tls"),
+			expectCode: codes.Internal,
+			expectMsg:  "svidstore(k8s_secret): failed to get secret: oh no",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewClientset(tt.objects...)
+			if tt.reactor != nil {
+				client.PrependReactor("*", "secrets", tt.reactor)
+			}
+
+			ss := loadPlugin(t, client, tt.config)
+
+			err := ss.PutX509SVID(context.Background(), tt.req)
+			spiretest.RequireGRPCStatus(t, err, tt.expectCode, tt.expectMsg)
+			if tt.expectCode != codes.OK {
+				return
+			}
+
+			secret, err := client.CoreV1().Secrets("apps").Get(context.Background(), "ingress-tls", metav1.GetOptions{})
+			require.NoError(t, err)
+			secret.ResourceVersion = ""
+			secret.ManagedFields = nil
+			secret.TypeMeta = metav1.TypeMeta{}
+			require.Equal(t, tt.expectSecret, secret)
+		})
+	}
+}
+
+func TestPutX509SVIDRetriesOnConflict(t *testing.T) {
+	ca := testca.New(t, td)
+	svid := ca.CreateX509SVID(spiffeid.RequireFromPath(td, "/ingress"))
+
+	client := fake.NewClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ingress-tls",
+			Namespace: "apps",
+			Labels: map[string]string{
+				"spire-svid": tdHash,
+			},
+		},
+		Type: corev1.SecretTypeTLS,
+	})
+	conflicts := 0
+	client.PrependReactor("update", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if conflicts == 0 {
+			conflicts++
+			return true, nil, k8serrors.NewConflict(corev1.Resource("secrets"), "ingress-tls", errors.New("oh no"))
+		}
+		return false, nil, nil
+	})
+
+	ss := loadPlugin(t, client, "")
+
+	err := ss.PutX509SVID(context.Background(), &svidstore.X509SVID{
+		SVID: &svidstore.SVID{
+			SPIFFEID:   svid.ID,
+			CertChain:  svid.Certificates,
+			PrivateKey: svid.PrivateKey,
+			Bundle:     ca.X509Authorities(),
+			ExpiresAt:  svid.Certificates[0].NotAfter,
+		},
+		Metadata: []string{"namespace:apps", "name:ingress-tls"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, conflicts)
+
+	secret, err := client.CoreV1().Secrets("apps").Get(context.Background(), "ingress-tls", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, pemutil.EncodeCertificates(svid.Certificates), secret.Data["tls.crt"])
+}
+
+func TestDeleteX509SVID(t *testing.T) {
+	managedSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ingress-tls",
+			Namespace: "apps",
+			Labels: map[string]string{
+				"spire-svid": tdHash,
+			},
+		},
+		Type: corev1.SecretTypeTLS,
+	}
+	unmanagedSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ingress-tls",
+			Namespace: "apps",
+		},
+		Type: corev1.SecretTypeTLS,
+	}
+
+	for _, tt := range []struct {
+		name         string
+		config       string
+		objects      []runtime.Object
+		reactor      k8stesting.ReactionFunc
+		metadata     []string
+		expectCode   codes.Code
+		expectMsg    string
+		expectExists bool
+	}{
+		{
+			name:     "secret deleted",
+			objects:  []runtime.Object{managedSecret},
+			metadata: []string{"namespace:apps", "name:ingress-tls"},
+		},
+		{
+			name:     "namespace from configuration",
+			config:   `namespace = "apps"`,
+			objects:  []runtime.Object{managedSecret},
+			metadata: []string{"name:ingress-tls"},
+		},
+		{
+			name:     "secret not found",
+			metadata: []string{"namespace:apps", "name:ingress-tls"},
+		},
+		{
+			name:         "secret not managed by spire",
+			objects:      []runtime.Object{unmanagedSecret},
+			metadata:     []string{"namespace:apps", "name:ingress-tls"},
+			expectCode:   codes.InvalidArgument,
+			expectMsg:    "svidstore(k8s_secret): secret does not contain the 'spire-svid' label for the trust domain",
+			expectExists: true,
+		},
+		{
+			name:       "no name",
+			metadata:   []string{"namespace:apps"},
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "svidstore(k8s_secret): secret name is required",
+		},
+		{
+			name:    "delete fails",
+			objects: []runtime.Object{managedSecret},
+			reactor: func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if action.GetVerb() == "delete" {
+					return true, nil, errors.New("oh no")
+				}
+				return false, nil, nil
+			},
+			metadata:     []string{"namespace:apps", "name:ingress-tls"},
+			expectCode:   codes.Internal,
+			expectMsg:    "svidstore(k8s_secret): failed to delete secret: oh no",
+			expectExists: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewClientset(tt.objects...)
+			if tt.reactor != nil {
+				client.PrependReactor("*", "secrets", tt.reactor)
+			}
+
+			ss := loadPlugin(t, client, tt.config)
+
+			err := ss.DeleteX509SVID(context.Background(), tt.metadata)
+			spiretest.RequireGRPCStatus(t, err, tt.expectCode, tt.expectMsg)
+
+			_, err = client.CoreV1().Secrets("apps").Get(context.Background(), "ingress-tls", metav1.GetOptions{})
+			if tt.expectExists {
+				require.NoError(t, err)
+			} else {
+				require.True(t, k8serrors.IsNotFound(err), "unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func loadPlugin(t *testing.T, client kubernetes.Interface, config string) *svidstore.V1 {
+	p := New()
+	p.hooks.newClient = func(string) (kubernetes.Interface, error) {
+		return client, nil
+	}
+
+	ss := new(svidstore.V1)
+	plugintest.Load(t, builtin(p), ss,
+		plugintest.CoreConfig(catalog.CoreConfig{
+			TrustDomain: td,
+		}),
+		plugintest.Configure(config),
+	)
+	return ss
+}
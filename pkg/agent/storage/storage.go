@@ -45,6 +45,16 @@ type Storage interface {
 
 	// DeleteBootstrapState removes the bootstrap state
 	DeleteBootstrapState() error
+
+	// LoadWorkloadSnapshot loads the sealed workload SVID snapshot from
+	// storage. Returns ErrNotCached if the snapshot does not exist.
+	LoadWorkloadSnapshot() ([]byte, error)
+
+	// StoreWorkloadSnapshot stores the sealed workload SVID snapshot.
+	StoreWorkloadSnapshot(sealed []byte) error
+
+	// DeleteWorkloadSnapshot deletes the workload SVID snapshot.
+	DeleteWorkloadSnapshot() error
 }
 
 func Open(dir string) (Storage, error) {
@@ -170,6 +180,40 @@ func (s *storage) DeleteBootstrapState() error {
 	return nil
 }
 
+func (s *storage) LoadWorkloadSnapshot() ([]byte, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	sealed, err := os.ReadFile(workloadSnapshotPath(s.dir))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, ErrNotCached
+	case err != nil:
+		return nil, fmt.Errorf("failed to read workload snapshot: %w", err)
+	}
+	return sealed, nil
+}
+
+func (s *storage) StoreWorkloadSnapshot(sealed []byte) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if err := diskutil.AtomicWritePrivateFile(workloadSnapshotPath(s.dir), sealed); err != nil {
+		return fmt.Errorf("failed to write workload snapshot: %w", err)
+	}
+	return nil
+}
+
+func (s *storage) DeleteWorkloadSnapshot() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if err := os.Remove(workloadSnapshotPath(s.dir)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove workload snapshot: %w", err)
+	}
+	return nil
+}
+
 type storageJSON struct {
 	SVID               [][]byte  `json:"svid"`
 	Bundle             [][]byte  `json:"bundle"`
@@ -287,3 +331,7 @@ func encodeCertificates(certs []*x509.Certificate) ([][]byte, error) {
 func dataPath(dir string) string {
 	return filepath.Join(dir, "agent-data.json")
 }
+
+func workloadSnapshotPath(dir string) string {
+	return filepath.Join(dir, "workload-snapshot.bin")
+}
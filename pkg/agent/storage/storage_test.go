@@ -134,6 +134,50 @@ func TestSVID(t *testing.T) {
 	})
 }
 
+func TestWorkloadSnapshot(t *testing.T) {
+	sealed := []byte("sealed")
+
+	t.Run("load from empty storage", func(t *testing.T) {
+		dir := spiretest.TempDir(t)
+
+		sto := openStorage(t, dir)
+		actual, err := sto.LoadWorkloadSnapshot()
+		require.True(t, errors.Is(err, ErrNotCached))
+		require.Nil(t, actual)
+	})
+
+	t.Run("load from new storage instance", func(t *testing.T) {
+		dir := spiretest.TempDir(t)
+
+		sto := openStorage(t, dir)
+		require.NoError(t, sto.StoreWorkloadSnapshot(sealed))
+
+		sto = openStorage(t, dir)
+		actual, err := sto.LoadWorkloadSnapshot()
+		require.NoError(t, err)
+		require.Equal(t, sealed, actual)
+	})
+
+	t.Run("delete from empty storage", func(t *testing.T) {
+		dir := spiretest.TempDir(t)
+
+		sto := openStorage(t, dir)
+		require.NoError(t, sto.DeleteWorkloadSnapshot())
+	})
+
+	t.Run("delete from populated storage", func(t *testing.T) {
+		dir := spiretest.TempDir(t)
+
+		sto := openStorage(t, dir)
+		require.NoError(t, sto.StoreWorkloadSnapshot(sealed))
+		require.NoError(t, sto.DeleteWorkloadSnapshot())
+
+		actual, err := sto.LoadWorkloadSnapshot()
+		require.True(t, errors.Is(err, ErrNotCached))
+		require.Nil(t, actual)
+	})
+}
+
 func openStorage(t *testing.T, dir string) Storage {
 	sto, err := Open(dir)
 	require.NoError(t, err)
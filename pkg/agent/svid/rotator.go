@@ -364,7 +364,7 @@ func (r *rotator) generateKey(ctx context.Context) (keymanager.Key, error) {
 
 func (r *rotator) serverConn(bundle *spiffebundle.Bundle) (*grpc.ClientConn, error) {
 	return client.NewServerGRPCClient(client.ServerClientConfig{
-		Address:     r.c.ServerAddr,
+		Servers:     r.c.Servers,
 		TrustDomain: r.c.TrustDomain,
 		GetBundle:   bundle.X509Authorities,
 		TLSPolicy:   r.c.TLSPolicy,
@@ -28,8 +28,11 @@ type RotatorConfig struct {
 	Metrics        telemetry.Metrics
 	TrustDomain    spiffeid.TrustDomain
 	ServerAddr     string
-	NodeAttestor   nodeattestor.NodeAttestor
-	Reattestable   bool
+	// Servers optionally selects the server among several. When unset,
+	// ServerAddr is used.
+	Servers      *client.ServerPool
+	NodeAttestor nodeattestor.NodeAttestor
+	Reattestable bool
 
 	// Initial SVID and key
 	SVID    []*x509.Certificate
@@ -62,6 +65,14 @@ func newRotator(c *RotatorConfig) (*rotator, client.Client) {
 		c.Clk = clock.New()
 	}
 
+	if c.Servers == nil {
+		c.Servers = client.NewServerPool(client.ServerPoolConfig{
+			Addresses: []string{c.ServerAddr},
+			Log:       c.Log,
+			Metrics:   c.Metrics,
+		})
+	}
+
 	state := observer.NewProperty(State{
 		SVID:         c.SVID,
 		Key:          c.SVIDKey,
@@ -75,6 +86,7 @@ func newRotator(c *RotatorConfig) (*rotator, client.Client) {
 		TrustDomain: c.TrustDomain,
 		Log:         c.Log,
 		Addr:        c.ServerAddr,
+		Servers:     c.Servers,
 		RotMtx:      rotMtx,
 		KeysAndBundle: func() ([]*x509.Certificate, crypto.Signer, []*x509.Certificate) {
 			s := state.Value().(State)
@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -26,8 +27,15 @@ import (
 // SPIFFE Workload API. The agent retries with backoff on failure.
 const spiffeWorkloadAPIFetchTimeout = time.Minute
 
+// ServerSelector selects the SPIRE Server the agent talks to, as a gRPC
+// target address (e.g. dns:///spire-server:8081).
+type ServerSelector interface {
+	Select() (string, error)
+}
+
 type Bundle struct {
 	config             *Config
+	servers            ServerSelector
 	use                int
 	connectionAttempts int
 	startTime          time.Time
@@ -48,6 +56,14 @@ func (b *Bundle) SetMetrics(metrics telemetry.Metrics) {
 	b.metrics = metrics
 }
 
+// SetServerSelector sets the selector of the SPIRE Server the agent talks to.
+// The selected server is passed to the trust bundle URL instead of the
+// configured server address, which is unset when the agent is configured with
+// several server addresses or discovers them through DNS SRV records.
+func (b *Bundle) SetServerSelector(servers ServerSelector) {
+	b.servers = servers
+}
+
 func (b *Bundle) SetStorage(sto storage.Storage) error {
 	b.storage = sto
 	use, startTime, connectionAttempts, err := b.storage.LoadBootstrapState()
@@ -177,6 +193,10 @@ func (b *Bundle) GetBundle() ([]*x509.Certificate, bool, error) {
 			return nil, false, fmt.Errorf("unable to parse trust bundle URL: %w", err)
 		}
 		if b.config.TrustBundleUnixSocket != "" {
+			serverAddress, serverPort, err := b.serverAddress()
+			if err != nil {
+				return nil, false, err
+			}
 			params := u.Query()
 			if b.use == UseRebootstrap {
 				params.Set("spire-attest-mode", "rebootstrap")
@@ -185,8 +205,8 @@ func (b *Bundle) GetBundle() ([]*x509.Certificate, bool, error) {
 			}
 			params.Set("spire-connection-attempts", strconv.Itoa(b.connectionAttempts))
 			params.Set("spire-attest-start-time", b.startTime.Format(time.RFC3339))
-			params.Set("spire-server-address", b.config.ServerAddress)
-			params.Set("spire-server-port", strconv.Itoa(b.config.ServerPort))
+			params.Set("spire-server-address", serverAddress)
+			params.Set("spire-server-port", strconv.Itoa(serverPort))
 			params.Set("spiffe-trust-domain", b.config.TrustDomain)
 			u.RawQuery = params.Encode()
 		}
@@ -228,6 +248,28 @@ func (b *Bundle) GetBundle() ([]*x509.Certificate, bool, error) {
 	return bundle, false, nil
 }
 
+// serverAddress returns the host and port of the SPIRE Server the agent
+// talks to.
+func (b *Bundle) serverAddress() (string, int, error) {
+	if b.servers == nil {
+		return b.config.ServerAddress, b.config.ServerPort, nil
+	}
+
+	target, err := b.servers.Select()
+	if err != nil {
+		return "", 0, fmt.Errorf("unable to select SPIRE Server: %w", err)
+	}
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(target, "dns:///"))
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid SPIRE Server address %q: %w", target, err)
+	}
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid SPIRE Server port in address %q: %w", target, err)
+	}
+	return host, portNumber, nil
+}
+
 func (b *Bundle) GetInsecureBootstrap() bool {
 	return b.config.InsecureBootstrap
 }
@@ -1,10 +1,12 @@
 package trustbundlesources
 
 import (
+	"errors"
 	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
@@ -14,6 +16,7 @@ import (
 
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/spiffe/go-spiffe/v2/proto/spiffe/workload"
+	"github.com/spiffe/spire/pkg/agent/client"
 	"github.com/spiffe/spire/pkg/agent/storage"
 	"github.com/spiffe/spire/pkg/common/pemutil"
 	"github.com/spiffe/spire/pkg/common/telemetry"
@@ -21,6 +24,8 @@ import (
 	"github.com/spiffe/spire/test/spiretest"
 	"github.com/spiffe/spire/test/util"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestGetBundle(t *testing.T) {
@@ -173,6 +178,97 @@ func TestGetBundle(t *testing.T) {
 	}
 }
 
+func TestGetBundleServerAddress(t *testing.T) {
+	testTB, err := os.ReadFile(path.Join(util.ProjectRoot(), "conf/agent/dummy_root_ca.crt"))
+	require.NoError(t, err)
+
+	// The first server is unavailable, so the pool fails over to the second
+	multiServerPool := client.NewServerPool(client.ServerPoolConfig{
+		Addresses: []string{"dns:///spire-server-1:8081", "dns:///spire-server-2:8082"},
+	})
+	multiServerPool.ReportResult("dns:///spire-server-1:8081", status.Error(codes.Unavailable, "oh no"))
+
+	for _, tt := range []struct {
+		name          string
+		serverAddress string
+		serverPort    int
+		servers       ServerSelector
+		expectAddress string
+		expectPort    string
+		expectErr     string
+	}{
+		{
+			name:          "server address",
+			serverAddress: "spire-server",
+			serverPort:    8081,
+			expectAddress: "spire-server",
+			expectPort:    "8081",
+		},
+		{
+			name:          "server addresses",
+			servers:       multiServerPool,
+			expectAddress: "spire-server-2",
+			expectPort:    "8082",
+		},
+		{
+			name:          "server discovered through SRV records",
+			servers:       fakeServerSelector{address: "dns:///spire-server-3.example.org:8443"},
+			expectAddress: "spire-server-3.example.org",
+			expectPort:    "8443",
+		},
+		{
+			name:      "no server available",
+			servers:   fakeServerSelector{err: errors.New("no SPIRE Server addresses available")},
+			expectErr: "unable to select SPIRE Server: no SPIRE Server addresses available",
+		},
+		{
+			name:      "invalid server address",
+			servers:   fakeServerSelector{address: "dns:///spire-server"},
+			expectErr: `invalid SPIRE Server address "dns:///spire-server": address spire-server: missing port in address`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var query url.Values
+			tempDir := spiretest.TempDir(t)
+			unixSocket := filepath.Join(tempDir, "socket")
+			testServer := httptest.NewUnstartedServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					query = r.URL.Query()
+					_, _ = w.Write(testTB)
+				}))
+			listener, err := net.Listen("unix", unixSocket)
+			require.NoError(t, err)
+			testServer.Listener = listener
+			testServer.Start()
+			defer testServer.Close()
+
+			log, _ := test.NewNullLogger()
+			tbs := New(&Config{
+				TrustBundleFormat:     BundleFormatPEM,
+				TrustBundleURL:        "http://localhost/trustbundle",
+				TrustBundleUnixSocket: unixSocket,
+				TrustDomain:           "example.org",
+				ServerAddress:         tt.serverAddress,
+				ServerPort:            tt.serverPort,
+			}, log)
+			tbs.SetMetrics(&telemetry.Blackhole{})
+			require.NoError(t, tbs.SetStorage(openStorage(t, spiretest.TempDir(t))))
+			if tt.servers != nil {
+				tbs.SetServerSelector(tt.servers)
+			}
+
+			_, _, err = tbs.GetBundle()
+			if tt.expectErr != "" {
+				require.EqualError(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectAddress, query.Get("spire-server-address"))
+			require.Equal(t, tt.expectPort, query.Get("spire-server-port"))
+		})
+	}
+}
+
 func TestDownloadTrustBundle(t *testing.T) {
 	testTB, _ := os.ReadFile(path.Join(util.ProjectRoot(), "conf/agent/dummy_root_ca.crt"))
 	testTBSPIFFE := `{
@@ -324,6 +420,15 @@ func getWorkloadAPITestAddress(path string) string {
 	return "unix://" + path
 }
 
+type fakeServerSelector struct {
+	address string
+	err     error
+}
+
+func (s fakeServerSelector) Select() (string, error) {
+	return s.address, s.err
+}
+
 func openStorage(t *testing.T, dir string) storage.Storage {
 	sto, err := storage.Open(dir)
 	require.NoError(t, err)
@@ -62,3 +62,18 @@ func (keyType KeyType) String() string {
 		return fmt.Sprintf("UNKNOWN(%d)", int(keyType))
 	}
 }
+
+// WITSigningAlgorithm returns the JWS algorithm used by a workload to sign
+// with a WIT-SVID key of the given key type.
+func (keyType KeyType) WITSigningAlgorithm() (string, error) {
+	switch keyType {
+	case ECP256:
+		return "ES256", nil
+	case ECP384:
+		return "ES384", nil
+	case RSA2048:
+		return "RS256", nil
+	default:
+		return "", fmt.Errorf("unknown key type %q", keyType)
+	}
+}
@@ -50,3 +50,26 @@ func TestKeyTypeFromString(t *testing.T) {
 		})
 	}
 }
+
+func TestWITSigningAlgorithm(t *testing.T) {
+	for _, tt := range []struct {
+		keyType     workloadkey.KeyType
+		expectAlg   string
+		expectedErr string
+	}{
+		{keyType: workloadkey.ECP256, expectAlg: "ES256"},
+		{keyType: workloadkey.ECP384, expectAlg: "ES384"},
+		{keyType: workloadkey.RSA2048, expectAlg: "RS256"},
+		{keyType: workloadkey.KeyTypeUnset, expectedErr: "unknown key type \"UNSET\""},
+	} {
+		t.Run(tt.keyType.String(), func(t *testing.T) {
+			alg, err := tt.keyType.WITSigningAlgorithm()
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectAlg, alg)
+		})
+	}
+}
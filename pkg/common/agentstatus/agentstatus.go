@@ -0,0 +1,13 @@
+// Package agentstatus holds the settings of the agent status reports shared
+// by agents and servers.
+//
+// Agents post their status report periodically through the private agent
+// status API of the server, which also refreshes the last time the server has
+// seen them.
+package agentstatus
+
+import "time"
+
+// DefaultReportInterval is the default interval at which agents report their
+// status, which also refreshes the last time the server has seen them.
+const DefaultReportInterval = time.Minute
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/spiffe/spire/pkg/common/api/rpccontext"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithTracing starts a server span for each RPC, continuing any trace
+// propagated by the caller in the gRPC metadata. The trace and span IDs are
+// added to the per-rpc logger, so it should be chained after WithLogger. If
+// tracing is not configured, spans are not recorded and the logger is left
+// untouched.
+func WithTracing() Middleware {
+	return tracingMiddleware{}
+}
+
+type tracingMiddleware struct{}
+
+func (tracingMiddleware) Preprocess(ctx context.Context, fullMethod string, _ any) (context.Context, error) {
+	ctx, names := withNames(ctx, fullMethod)
+	ctx = telemetry.ExtractTraceContext(ctx)
+	ctx, span := telemetry.StartSpan(ctx, strings.TrimPrefix(fullMethod, "/"),
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.service", names.RawService),
+		attribute.String("rpc.method", names.Method),
+	)
+	if span.IsRecording() {
+		ctx = rpccontext.WithLogger(ctx, telemetry.WithTraceFields(ctx, rpccontext.Logger(ctx)))
+	}
+	return ctx, nil
+}
+
+func (tracingMiddleware) Postprocess(ctx context.Context, _ string, _ bool, rpcErr error) {
+	telemetry.EndSpan(trace.SpanFromContext(ctx), &rpcErr)
+}
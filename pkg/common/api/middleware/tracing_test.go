@@ -0,0 +1,91 @@
+package middleware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/spire/pkg/common/api/middleware"
+	"github.com/spiffe/spire/pkg/common/api/rpccontext"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestWithTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	// Simulate a caller propagating its span context
+	callerCtx, callerSpan := telemetry.StartSpan(context.Background(), "caller")
+	callerSpan.End()
+	md, _ := metadata.FromOutgoingContext(telemetry.InjectTraceContext(callerCtx))
+
+	log, hook := test.NewNullLogger()
+	m := middleware.Chain(middleware.WithLogger(log), middleware.WithTracing())
+
+	ctx, err := m.Preprocess(metadata.NewIncomingContext(context.Background(), md), fakeFullMethod, nil)
+	require.NoError(t, err)
+	rpccontext.Logger(ctx).Info("HELLO")
+	m.Postprocess(ctx, fakeFullMethod, true, errFake)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	span := spans[1]
+	assert.Equal(t, "spire.api.server.foo.v1.Foo/SomeMethod", span.Name())
+	assert.Equal(t, trace.SpanKindInternal, span.SpanKind())
+	assert.Equal(t, callerSpan.SpanContext().TraceID(), span.SpanContext().TraceID())
+	assert.Equal(t, callerSpan.SpanContext().SpanID(), span.Parent().SpanID())
+	assert.Equal(t, otelcodes.Error, span.Status().Code)
+	assert.Equal(t, "ohno", span.Status().Description)
+	assert.Subset(t, span.Attributes(), []attribute.KeyValue{
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.service", "spire.api.server.foo.v1.Foo"),
+		attribute.String("rpc.method", "SomeMethod"),
+	})
+
+	// The per-rpc logger carries the trace and span IDs
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, logrus.Fields{
+		telemetry.Service: "foo.v1.Foo",
+		telemetry.Method:  "SomeMethod",
+		telemetry.TraceID: span.SpanContext().TraceID().String(),
+		telemetry.SpanID:  span.SpanContext().SpanID().String(),
+	}, entry.Data)
+}
+
+func TestWithTracingDisabled(t *testing.T) {
+	otel.SetTracerProvider(noop.NewTracerProvider())
+
+	log, hook := test.NewNullLogger()
+	m := middleware.Chain(middleware.WithLogger(log), middleware.WithTracing())
+
+	ctx, err := m.Preprocess(context.Background(), fakeFullMethod, nil)
+	require.NoError(t, err)
+	rpccontext.Logger(ctx).Info("HELLO")
+	assert.NotPanics(t, func() {
+		m.Postprocess(ctx, fakeFullMethod, true, nil)
+	})
+
+	// No trace fields are added when tracing is not configured
+	assert.Equal(t, logrus.Fields{
+		telemetry.Service: "foo.v1.Foo",
+		telemetry.Method:  "SomeMethod",
+	}, hook.LastEntry().Data)
+}
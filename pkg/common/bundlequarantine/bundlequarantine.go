@@ -0,0 +1,215 @@
+// Package bundlequarantine describes federated bundle updates that were held
+// back by the bundle safeguards of a federation relationship, and carries
+// them over gRPC metadata of the trust domain API calls, since the SPIRE API
+// types have no fields for them.
+//
+// The quarantined bundle of a trust domain, along with its current bundle, is
+// returned in the GetFederationRelationship response header. A quarantined
+// bundle is approved with the ApproveQuarantinedBundle RPC of the private
+// Federation API, given the digest of the bundle.
+package bundlequarantine
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spiffe/spire/proto/spire/common"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// QuarantinedBundleKey is the binary gRPC metadata key of the
+	// GetFederationRelationship response header that holds the quarantined
+	// bundle of the trust domain, a protobuf encoded QuarantinedBundle.
+	QuarantinedBundleKey = "spire-quarantined-bundle-bin"
+
+	// CurrentBundleKey is the binary gRPC metadata key of the
+	// GetFederationRelationship response header that holds the bundle
+	// currently in use for the trust domain, a protobuf encoded Bundle.
+	CurrentBundleKey = "spire-current-bundle-bin"
+)
+
+const (
+	KeyTypeX509 = "x509"
+	KeyTypeJWT  = "jwt"
+)
+
+// Key is an authority of a bundle.
+type Key struct {
+	// Type is the type of the authority, either KeyTypeX509 or KeyTypeJWT.
+	Type string
+
+	// Fingerprint is the hex encoded SHA-256 hash of the DER encoded
+	// SubjectPublicKeyInfo of the authority.
+	Fingerprint string
+
+	// ID identifies the authority: the subject of X.509 authorities and the
+	// key ID of JWT authorities.
+	ID string
+
+	// NotAfter is when the authority expires, if known.
+	NotAfter time.Time
+}
+
+// KeyChange is a key added to or removed from a bundle.
+type KeyChange struct {
+	Key
+	Added bool
+}
+
+func (c KeyChange) String() string {
+	op := "-"
+	if c.Added {
+		op = "+"
+	}
+	s := fmt.Sprintf("%s %s %s", op, c.Type, c.Fingerprint)
+	if c.ID != "" {
+		s += fmt.Sprintf(" (%s)", c.ID)
+	}
+	if !c.NotAfter.IsZero() {
+		s += fmt.Sprintf(" expires %s", c.NotAfter.UTC().Format(time.RFC3339))
+	}
+	return s
+}
+
+// Fingerprint returns the hex encoded SHA-256 hash of a DER encoded
+// SubjectPublicKeyInfo.
+func Fingerprint(spki []byte) string {
+	sum := sha256.Sum256(spki)
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizeFingerprint normalizes a fingerprint provided by the user,
+// accepting an optional "sha256:" prefix and colon separators.
+func NormalizeFingerprint(s string) (string, error) {
+	fingerprint := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "sha256:")
+	fingerprint = strings.ReplaceAll(fingerprint, ":", "")
+	b, err := hex.DecodeString(fingerprint)
+	if err != nil || len(b) != sha256.Size {
+		return "", fmt.Errorf("invalid SHA-256 fingerprint %q", s)
+	}
+	return fingerprint, nil
+}
+
+// Keys returns the X.509 and JWT authorities of a bundle.
+func Keys(bundle *common.Bundle) ([]Key, error) {
+	var keys []Key
+	for _, rootCA := range bundle.GetRootCas() {
+		cert, err := x509.ParseCertificate(rootCA.DerBytes)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse X.509 authority: %w", err)
+		}
+		keys = append(keys, Key{
+			Type:        KeyTypeX509,
+			Fingerprint: Fingerprint(cert.RawSubjectPublicKeyInfo),
+			ID:          cert.Subject.String(),
+			NotAfter:    cert.NotAfter,
+		})
+	}
+	for _, jwtKey := range bundle.GetJwtSigningKeys() {
+		key := Key{
+			Type:        KeyTypeJWT,
+			Fingerprint: Fingerprint(jwtKey.PkixBytes),
+			ID:          jwtKey.Kid,
+		}
+		if jwtKey.NotAfter != 0 {
+			key.NotAfter = time.Unix(jwtKey.NotAfter, 0)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Diff returns the keys removed from the current bundle followed by the keys
+// added by the next bundle. The current bundle can be nil.
+func Diff(current, next *common.Bundle) ([]KeyChange, error) {
+	currentKeys, err := Keys(current)
+	if err != nil {
+		return nil, fmt.Errorf("current bundle: %w", err)
+	}
+	nextKeys, err := Keys(next)
+	if err != nil {
+		return nil, fmt.Errorf("next bundle: %w", err)
+	}
+
+	contains := func(keys []Key, key Key) bool {
+		return slices.ContainsFunc(keys, func(k Key) bool {
+			return k.Type == key.Type && k.Fingerprint == key.Fingerprint
+		})
+	}
+
+	var changes []KeyChange
+	for _, key := range currentKeys {
+		if !contains(nextKeys, key) {
+			changes = append(changes, KeyChange{Key: key})
+		}
+	}
+	for _, key := range nextKeys {
+		if !contains(currentKeys, key) {
+			changes = append(changes, KeyChange{Key: key, Added: true})
+		}
+	}
+	return changes, nil
+}
+
+// Digest returns the hex encoded SHA-256 hash of the deterministic protobuf
+// encoding of a bundle. It is used to make sure the bundle approved by an
+// operator is the bundle that was quarantined.
+func Digest(bundle *common.Bundle) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(bundle)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal bundle: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// HeaderFromQuarantinedBundle returns the GetFederationRelationship response
+// header with the quarantined bundle and the current bundle, if any.
+func HeaderFromQuarantinedBundle(quarantined *common.QuarantinedBundle, current *common.Bundle) (metadata.MD, error) {
+	md := metadata.MD{}
+	value, err := proto.Marshal(quarantined)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal quarantined bundle: %w", err)
+	}
+	md.Set(QuarantinedBundleKey, string(value))
+
+	if current != nil {
+		value, err := proto.Marshal(current)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal current bundle: %w", err)
+		}
+		md.Set(CurrentBundleKey, string(value))
+	}
+	return md, nil
+}
+
+// QuarantinedBundleFromHeader returns the quarantined bundle and the current
+// bundle in a GetFederationRelationship response header. The quarantined
+// bundle is nil if the trust domain has no quarantined bundle.
+func QuarantinedBundleFromHeader(md metadata.MD) (*common.QuarantinedBundle, *common.Bundle, error) {
+	values := md.Get(QuarantinedBundleKey)
+	if len(values) == 0 {
+		return nil, nil, nil
+	}
+	quarantined := new(common.QuarantinedBundle)
+	if err := proto.Unmarshal([]byte(values[0]), quarantined); err != nil {
+		return nil, nil, fmt.Errorf("malformed quarantined bundle: %w", err)
+	}
+
+	values = md.Get(CurrentBundleKey)
+	if len(values) == 0 {
+		return quarantined, nil, nil
+	}
+	current := new(common.Bundle)
+	if err := proto.Unmarshal([]byte(values[0]), current); err != nil {
+		return nil, nil, fmt.Errorf("malformed current bundle: %w", err)
+	}
+	return quarantined, current, nil
+}
@@ -0,0 +1,61 @@
+package bundleutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/bundle/spiffebundle"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+)
+
+// hubDoc is the document served by a federation hub. It holds the bundles of
+// many trust domains, keyed by trust domain name, each one in the SPIFFE
+// bundle format with its own refresh hint.
+type hubDoc struct {
+	Bundles map[string]json.RawMessage `json:"bundles"`
+}
+
+// MarshalHub marshals the bundles into a federation hub document. The refresh
+// hint of each bundle is kept, falling back to defaultRefreshHint for bundles
+// without one.
+func MarshalHub(bundles []*spiffebundle.Bundle, defaultRefreshHint time.Duration) ([]byte, error) {
+	doc := hubDoc{
+		Bundles: make(map[string]json.RawMessage, len(bundles)),
+	}
+	for _, bundle := range bundles {
+		var opts []MarshalOption
+		if refreshHint, ok := bundle.RefreshHint(); !ok || refreshHint == 0 {
+			opts = append(opts, OverrideRefreshHint(defaultRefreshHint))
+		}
+		bundleBytes, err := Marshal(bundle, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal bundle for %q: %w", bundle.TrustDomain(), err)
+		}
+		doc.Bundles[bundle.TrustDomain().Name()] = bundleBytes
+	}
+	return json.MarshalIndent(doc, "", "    ")
+}
+
+// DecodeHub decodes a federation hub document into the bundles it holds.
+func DecodeHub(r io.Reader) (map[spiffeid.TrustDomain]*spiffebundle.Bundle, error) {
+	doc := new(hubDoc)
+	if err := json.NewDecoder(r).Decode(doc); err != nil {
+		return nil, fmt.Errorf("failed to decode hub bundles: %w", err)
+	}
+
+	bundles := make(map[spiffeid.TrustDomain]*spiffebundle.Bundle, len(doc.Bundles))
+	for name, bundleBytes := range doc.Bundles {
+		td, err := spiffeid.TrustDomainFromString(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trust domain %q in hub bundles: %w", name, err)
+		}
+		bundle, err := Unmarshal(td, bundleBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode hub bundle for %q: %w", name, err)
+		}
+		bundles[td] = bundle
+	}
+	return bundles, nil
+}
@@ -0,0 +1,79 @@
+package bundleutil
+
+import (
+	"bytes"
+	"crypto/x509"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/bundle/spiffebundle"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHubRoundTrip(t *testing.T) {
+	rootCA := createCACertificate(t)
+	td1 := spiffeid.RequireTrustDomainFromString("domain1.test")
+	td2 := spiffeid.RequireTrustDomainFromString("domain2.test")
+
+	bundle1 := spiffebundle.FromX509Authorities(td1, []*x509.Certificate{rootCA})
+	bundle1.SetRefreshHint(time.Minute)
+	bundle2 := spiffebundle.FromX509Authorities(td2, []*x509.Certificate{rootCA})
+
+	hubBytes, err := MarshalHub([]*spiffebundle.Bundle{bundle1, bundle2}, 5*time.Minute)
+	require.NoError(t, err)
+
+	bundles, err := DecodeHub(bytes.NewReader(hubBytes))
+	require.NoError(t, err)
+	require.Len(t, bundles, 2)
+
+	// Bundles keep their own refresh hint, or get the default one
+	refreshHint, ok := bundles[td1].RefreshHint()
+	require.True(t, ok)
+	require.Equal(t, time.Minute, refreshHint)
+	refreshHint, ok = bundles[td2].RefreshHint()
+	require.True(t, ok)
+	require.Equal(t, 5*time.Minute, refreshHint)
+
+	require.Equal(t, []*x509.Certificate{rootCA}, bundles[td1].X509Authorities())
+	require.Equal(t, []*x509.Certificate{rootCA}, bundles[td2].X509Authorities())
+}
+
+func TestDecodeHub(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		doc  string
+		err  string
+	}{
+		{
+			name: "empty",
+			doc:  `{}`,
+		},
+		{
+			name: "malformed",
+			doc:  `{`,
+			err:  "failed to decode hub bundles: unexpected EOF",
+		},
+		{
+			name: "invalid trust domain",
+			doc:  `{"bundles": {"Domain.Test": {}}}`,
+			err:  `invalid trust domain "Domain.Test" in hub bundles: trust domain characters are limited to lowercase letters, numbers, dots, dashes, and underscores`,
+		},
+		{
+			name: "invalid bundle",
+			doc:  `{"bundles": {"domain.test": {"keys": [{"kty": "EC"}]}}}`,
+			err:  `failed to decode hub bundle for "domain.test"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			bundles, err := DecodeHub(strings.NewReader(tt.doc))
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Empty(t, bundles)
+		})
+	}
+}
@@ -14,6 +14,9 @@ func TestUnmarshal(t *testing.T) {
 	trustDomain := spiffeid.RequireTrustDomainFromString("domain.test")
 	emptyBundle := spiffebundle.New(trustDomain)
 	emptyBundle.SetRefreshHint(0)
+	sequencedBundle := spiffebundle.New(trustDomain)
+	sequencedBundle.SetRefreshHint(0)
+	sequencedBundle.SetSequenceNumber(42)
 	testCases := []struct {
 		name   string
 		doc    string
@@ -25,6 +28,11 @@ func TestUnmarshal(t *testing.T) {
 			doc:    "{}",
 			bundle: emptyBundle,
 		},
+		{
+			name:   "sequence number",
+			doc:    `{"spiffe_sequence": 42}`,
+			bundle: sequencedBundle,
+		},
 		{
 			name: "entry missing use",
 			doc: `{
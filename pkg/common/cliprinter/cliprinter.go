@@ -3,14 +3,20 @@ package cliprinter
 import (
 	"errors"
 	"io"
+	texttemplate "text/template"
 
 	commoncli "github.com/spiffe/spire/pkg/common/cli"
 	"github.com/spiffe/spire/pkg/common/cliprinter/internal/errorjson"
 	"github.com/spiffe/spire/pkg/common/cliprinter/internal/errorpretty"
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/erroryaml"
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/gotemplate"
 	"github.com/spiffe/spire/pkg/common/cliprinter/internal/protojson"
 	"github.com/spiffe/spire/pkg/common/cliprinter/internal/protopretty"
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/protoyaml"
 	"github.com/spiffe/spire/pkg/common/cliprinter/internal/structjson"
 	"github.com/spiffe/spire/pkg/common/cliprinter/internal/structpretty"
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/structyaml"
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/tabular"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -36,6 +42,11 @@ type printer struct {
 	format formatType
 	env    *commoncli.Env
 	cp     CustomPrettyFunc
+
+	// columns are the columns printed by the table format
+	columns []string
+	// tmpl is the template executed by the template format
+	tmpl *texttemplate.Template
 }
 
 func newPrinter(f formatType, env *commoncli.Env) *printer {
@@ -67,6 +78,8 @@ func (p *printer) printError(err error) error {
 	switch p.format {
 	case json:
 		return errorjson.Print(err, p.env.Stdout, p.env.Stderr)
+	case yaml:
+		return erroryaml.Print(err, p.env.Stdout, p.env.Stderr)
 	default:
 		return p.printPrettyError(err, p.env.Stdout, p.env.Stderr)
 	}
@@ -76,6 +89,12 @@ func (p *printer) printProto(msg ...proto.Message) error {
 	switch p.format {
 	case json:
 		return protojson.Print(msg, p.env.Stdout, p.env.Stderr)
+	case yaml:
+		return protoyaml.Print(msg, p.env.Stdout, p.env.Stderr)
+	case table:
+		return tabular.PrintProto(msg, p.columns, p.env.Stdout, p.env.Stderr)
+	case template:
+		return gotemplate.PrintProto(p.tmpl, msg, p.env.Stdout, p.env.Stderr)
 	default:
 		return p.printPrettyProto(msg, p.env.Stdout, p.env.Stderr)
 	}
@@ -85,6 +104,12 @@ func (p *printer) printStruct(msg ...any) error {
 	switch p.format {
 	case json:
 		return structjson.Print(msg, p.env.Stdout, p.env.Stderr)
+	case yaml:
+		return structyaml.Print(msg, p.env.Stdout, p.env.Stderr)
+	case table:
+		return tabular.PrintStruct(msg, p.columns, p.env.Stdout, p.env.Stderr)
+	case template:
+		return gotemplate.PrintStruct(p.tmpl, msg, p.env.Stdout, p.env.Stderr)
 	default:
 		return p.printPrettyStruct(msg, p.env.Stdout, p.env.Stderr)
 	}
@@ -98,6 +123,19 @@ func (p *printer) setCustomPrettyPrinter(cp CustomPrettyFunc) {
 	p.cp = cp
 }
 
+// setFormatArg configures the printer with the argument of its format, i.e.
+// the columns of the table format or the template of the template format.
+func (p *printer) setFormatArg(arg string) error {
+	var err error
+	switch p.format {
+	case table:
+		p.columns, err = tabular.ParseColumns(arg)
+	case template:
+		p.tmpl, err = gotemplate.Parse(arg)
+	}
+	return err
+}
+
 func (p *printer) printPrettyError(err error, stdout, stderr io.Writer) error {
 	if p.cp != nil {
 		return p.cp(p.env, err)
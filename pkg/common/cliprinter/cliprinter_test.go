@@ -89,6 +89,39 @@ func TestPrintStruct(t *testing.T) {
 	}
 }
 
+func TestPrintFormats(t *testing.T) {
+	cases := []struct {
+		format string
+		stdout string
+	}{
+		{format: "json", stdout: "{\"count\":42}\n"},
+		{format: "yaml", stdout: "count: 42\n"},
+		{format: "table", stdout: "COUNT\n42\n"},
+		{format: "template=count is {{.count}}", stdout: "count is 42"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.format, func(t *testing.T) {
+			format, arg, err := parseFormat(c.format)
+			if err != nil {
+				t.Fatalf("failed to parse format: %v", err)
+			}
+			stdout := new(bytes.Buffer)
+			p := newPrinter(format, &commoncli.Env{Stdout: stdout, Stderr: new(bytes.Buffer)})
+			if err := p.setFormatArg(arg); err != nil {
+				t.Fatalf("failed to set format argument: %v", err)
+			}
+
+			if err := p.PrintProto(&agentapi.CountAgentsResponse{Count: 42}); err != nil {
+				t.Fatalf("failed to print proto: %v", err)
+			}
+			if stdout.String() != c.stdout {
+				t.Errorf("output expected to be %q but got %q", c.stdout, stdout.String())
+			}
+		})
+	}
+}
+
 func newTestPrinter() (p *printer, stdout, stderr *bytes.Buffer) {
 	stdout = new(bytes.Buffer)
 	stderr = new(bytes.Buffer)
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"strings"
 
 	commoncli "github.com/spiffe/spire/pkg/common/cli"
 )
@@ -11,9 +12,12 @@ import (
 const defaultFlagName = "output"
 
 var flagDescription = fmt.Sprintf(
-	"Desired output format (%s, %s); default: %s.",
+	"Desired output format (%s, %s, %s, %s[=<columns>], %s=<go-template>); default: %s.",
 	formatTypeToStr(pretty),
 	formatTypeToStr(json),
+	formatTypeToStr(yaml),
+	formatTypeToStr(table),
+	formatTypeToStr(template),
 	formatTypeToStr(defaultFormatType),
 )
 
@@ -54,6 +58,8 @@ type FormatterFlag struct {
 	f     formatType
 	env   *commoncli.Env
 	isSet bool
+	// raw is the format as set on the command line, including its argument
+	raw string
 }
 
 func (f *FormatterFlag) String() string {
@@ -65,23 +71,27 @@ func (f *FormatterFlag) String() string {
 }
 
 func (f *FormatterFlag) Set(formatStr string) error {
-	if f.isSet && formatTypeToStr(f.f) != formatStr {
-		return fmt.Errorf("the output format has already been set to %q", formatTypeToStr(f.f))
+	if f.isSet && !strings.EqualFold(f.raw, formatStr) {
+		return fmt.Errorf("the output format has already been set to %q", f.raw)
 	}
 	if f.p == nil {
 		return errors.New("internal error: formatter flag not correctly invoked; please report this bug")
 	}
 
-	format, err := strToFormatType(formatStr)
+	format, arg, err := parseFormat(formatStr)
 	if err != nil {
 		return fmt.Errorf("bad formatter flag: %w", err)
 	}
 
 	np := newPrinter(format, f.env)
 	np.setCustomPrettyPrinter(f.customPretty)
+	if err := np.setFormatArg(arg); err != nil {
+		return fmt.Errorf("bad formatter flag: %w", err)
+	}
 
 	*f.p = np
 	f.f = format
+	f.raw = formatStr
 	f.isSet = true
 	return nil
 }
@@ -3,6 +3,7 @@ package cliprinter
 import (
 	"bytes"
 	"flag"
+	"slices"
 	"testing"
 
 	agentapi "github.com/spiffe/spire-api-sdk/proto/spire/api/server/agent/v1"
@@ -15,6 +16,7 @@ func TestAppendFlag(t *testing.T) {
 		input          []string
 		extraFlags     []string
 		expectedFormat formatType
+		expectedCols   []string
 		expectError    bool
 	}{
 		{
@@ -60,6 +62,53 @@ func TestAppendFlag(t *testing.T) {
 			input:          []string{"-output", "jSoN"},
 			expectedFormat: json,
 		},
+		{
+			name:           "works when specifying yaml",
+			input:          []string{"-output", "yaml"},
+			expectedFormat: yaml,
+		},
+		{
+			name:           "works when specifying table",
+			input:          []string{"-output", "table"},
+			expectedFormat: table,
+		},
+		{
+			name:           "works when specifying table columns",
+			input:          []string{"-output", "table=id,spiffe_id.path"},
+			expectedFormat: table,
+			expectedCols:   []string{"id", "spiffe_id.path"},
+		},
+		{
+			name:        "requires valid table columns",
+			input:       []string{"-output", "table=id,"},
+			expectError: true,
+		},
+		{
+			name:           "works when specifying a template",
+			input:          []string{"-output", "template={{.count}}"},
+			expectedFormat: template,
+		},
+		{
+			name:        "requires a template",
+			input:       []string{"-output", "template"},
+			expectError: true,
+		},
+		{
+			name:        "requires a valid template",
+			input:       []string{"-output", "template={{.count"},
+			expectError: true,
+		},
+		{
+			name:        "does not take an argument for other formats",
+			input:       []string{"-output", "json=id"},
+			expectError: true,
+		},
+		{
+			name:        "error when setting a different table columns more than once",
+			input:       []string{"-output", "table=id", "-format", "table=hint"},
+			extraFlags:  []string{"format"},
+			expectError: true,
+		},
 	}
 
 	for _, c := range flagCases {
@@ -95,6 +144,9 @@ func TestAppendFlag(t *testing.T) {
 			if pp.getFormat() != c.expectedFormat {
 				t.Errorf("expected format type %q but got %q", formatTypeToStr(c.expectedFormat), formatTypeToStr(pp.getFormat()))
 			}
+			if !slices.Equal(pp.columns, c.expectedCols) {
+				t.Errorf("expected table columns %q but got %q", c.expectedCols, pp.columns)
+			}
 		})
 	}
 }
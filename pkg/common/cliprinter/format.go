@@ -9,6 +9,9 @@ const (
 	_ formatType = iota
 	json
 	pretty
+	yaml
+	table
+	template
 
 	defaultFormatType = pretty
 )
@@ -21,6 +24,12 @@ func strToFormatType(f string) (formatType, error) {
 		return json, nil
 	case "pretty", "prettyprint":
 		return pretty, nil
+	case "yaml":
+		return yaml, nil
+	case "table":
+		return table, nil
+	case "template":
+		return template, nil
 	default:
 		return 0, fmt.Errorf("unknown format option: %q", f)
 	}
@@ -32,7 +41,32 @@ func formatTypeToStr(f formatType) string {
 		return "json"
 	case pretty:
 		return "pretty"
+	case yaml:
+		return "yaml"
+	case table:
+		return "table"
+	case template:
+		return "template"
 	default:
 		return "unknown"
 	}
 }
+
+// parseFormat parses an output format of the form <format>[=<argument>].
+// The table format optionally takes the comma separated columns to print
+// and the template format requires the Go template to execute.
+func parseFormat(s string) (formatType, string, error) {
+	name, arg, hasArg := strings.Cut(s, "=")
+	f, err := strToFormatType(name)
+	if err != nil {
+		return 0, "", err
+	}
+
+	switch {
+	case f == template && arg == "":
+		return 0, "", fmt.Errorf("the %q format requires a Go template, e.g. template='{{.id}}'", formatTypeToStr(f))
+	case hasArg && f != table && f != template:
+		return 0, "", fmt.Errorf("the %q format does not take an argument", formatTypeToStr(f))
+	}
+	return f, arg, nil
+}
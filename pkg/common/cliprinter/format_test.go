@@ -23,6 +23,18 @@ func TestStrFormatType(t *testing.T) {
 			name:  "json should work",
 			input: "json",
 		},
+		{
+			name:  "yaml should work",
+			input: "yaml",
+		},
+		{
+			name:  "table should work",
+			input: "table",
+		},
+		{
+			name:  "template should work",
+			input: "template",
+		},
 	}
 
 	for _, c := range cases {
@@ -47,3 +59,39 @@ func TestStrFormatType(t *testing.T) {
 		})
 	}
 }
+
+func TestParseFormat(t *testing.T) {
+	cases := []struct {
+		input       string
+		format      formatType
+		arg         string
+		expectError string
+	}{
+		{input: "json", format: json},
+		{input: "table", format: table},
+		{input: "table=id,hint", format: table, arg: "id,hint"},
+		{input: "template={{.id}}={{.hint}}", format: template, arg: "{{.id}}={{.hint}}"},
+		{input: "template", expectError: `the "template" format requires a Go template, e.g. template='{{.id}}'`},
+		{input: "template=", expectError: `the "template" format requires a Go template, e.g. template='{{.id}}'`},
+		{input: "yaml=id", expectError: `the "yaml" format does not take an argument`},
+		{input: "xml", expectError: `unknown format option: "xml"`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			format, arg, err := parseFormat(c.input)
+			if c.expectError != "" {
+				if err == nil || err.Error() != c.expectError {
+					t.Fatalf("expected error %q but got %v", c.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+			if format != c.format || arg != c.arg {
+				t.Errorf("expected format %q with argument %q but got %q with %q", formatTypeToStr(c.format), c.arg, formatTypeToStr(format), arg)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Package gotemplate prints messages by executing a Go template. The template
+// is executed against the JSON representation of the messages, so fields are
+// referenced by their JSON (protobuf) names, e.g. {{.spiffe_id.path}}.
+package gotemplate
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"text/template"
+
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Parse parses the given Go template.
+func Parse(text string) (*template.Template, error) {
+	return template.New("output").Parse(text)
+}
+
+// PrintProto executes the template for each of the protobuf messages.
+func PrintProto(tmpl *template.Template, msgs []proto.Message, stdout, _ io.Writer) error {
+	jms, err := protojson.Marshal(msgs)
+	if err != nil {
+		return err
+	}
+
+	for _, jm := range jms {
+		if err := execute(tmpl, jm, stdout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintStruct executes the template for each of the structs.
+func PrintStruct(tmpl *template.Template, msgs []any, stdout, _ io.Writer) error {
+	for _, msg := range msgs {
+		jb, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		if err := execute(tmpl, jb, stdout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func execute(tmpl *template.Template, jb []byte, stdout io.Writer) error {
+	// Numbers are decoded as json.Number so that they are printed as they
+	// appear in the JSON output instead of as floats
+	decoder := json.NewDecoder(bytes.NewReader(jb))
+	decoder.UseNumber()
+
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return err
+	}
+
+	// Execute into a buffer so nothing is printed if the execution fails
+	out := new(bytes.Buffer)
+	if err := tmpl.Execute(out, data); err != nil {
+		return err
+	}
+	_, err := out.WriteTo(stdout)
+	return err
+}
@@ -0,0 +1,55 @@
+package gotemplate
+
+import (
+	"bytes"
+	"testing"
+
+	entryapi "github.com/spiffe/spire-api-sdk/proto/spire/api/server/entry/v1"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPrintProto(t *testing.T) {
+	tmpl, err := Parse(`{{range .entries}}{{.id}} {{.spiffe_id.path}} {{.x509_svid_ttl}}{{"\n"}}{{end}}`)
+	require.NoError(t, err)
+
+	stdout := &bytes.Buffer{}
+	err = PrintProto(tmpl, []proto.Message{&entryapi.ListEntriesResponse{
+		Entries: []*types.Entry{
+			{Id: "ENTRY1", SpiffeId: &types.SPIFFEID{TrustDomain: "example.org", Path: "/one"}, X509SvidTtl: 3600},
+			{Id: "ENTRY2", SpiffeId: &types.SPIFFEID{TrustDomain: "example.org", Path: "/two"}},
+		},
+	}}, stdout, &bytes.Buffer{})
+	require.NoError(t, err)
+	assert.Equal(t, "ENTRY1 /one 3600\nENTRY2 /two 0\n", stdout.String())
+}
+
+func TestPrintStruct(t *testing.T) {
+	tmpl, err := Parse(`{{.name}} {{.count}}`)
+	require.NoError(t, err)
+
+	stdout := &bytes.Buffer{}
+	err = PrintStruct(tmpl, []any{struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}{Name: "boaty", Count: 12345678901}}, stdout, &bytes.Buffer{})
+	require.NoError(t, err)
+	assert.Equal(t, "boaty 12345678901", stdout.String())
+}
+
+func TestPrintFailure(t *testing.T) {
+	tmpl, err := Parse(`before {{.name.first}}`)
+	require.NoError(t, err)
+
+	stdout := &bytes.Buffer{}
+	err = PrintStruct(tmpl, []any{map[string]string{"name": "boaty"}}, stdout, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Empty(t, stdout.String(), "nothing is printed on failure")
+}
+
+func TestParse(t *testing.T) {
+	_, err := Parse(`{{.name`)
+	require.Error(t, err)
+}
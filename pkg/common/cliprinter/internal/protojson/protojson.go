@@ -15,6 +15,27 @@ func Print(msgs []proto.Message, stdout, stderr io.Writer) error {
 		return nil
 	}
 
+	// We build up the marshaled messages before printing them to reduce our
+	// chances of printing an unterminated result
+	parsedJms, err := Marshal(msgs)
+	if err != nil {
+		_ = errorjson.Print(err, stdout, stderr)
+		return err
+	}
+
+	if len(parsedJms) == 1 {
+		err = json.NewEncoder(stdout).Encode(parsedJms[0])
+	} else {
+		err = json.NewEncoder(stdout).Encode(parsedJms)
+	}
+
+	return err
+}
+
+// Marshal marshals each of the protobuf messages to JSON, using the protobuf
+// field names and omitting null values. It is shared by the formats that are
+// derived from the JSON representation of the messages.
+func Marshal(msgs []proto.Message) ([]json.RawMessage, error) {
 	jms := []json.RawMessage{}
 	m := &protojson.MarshalOptions{
 		UseProtoNames:   true,
@@ -22,33 +43,17 @@ func Print(msgs []proto.Message, stdout, stderr io.Writer) error {
 	}
 
 	// Unfortunately, we can only marshal one message at a time, so
-	// we need to build up an array of marshaled messages. We do this
-	// before printing them to reduce our chances of printing an
-	// unterminated result
+	// we need to build up an array of marshaled messages.
 	for _, msg := range msgs {
 		jb, err := m.Marshal(msg)
 		if err != nil {
-			_ = errorjson.Print(err, stdout, stderr)
-			return err
+			return nil, err
 		}
 
 		jms = append(jms, jb)
 	}
-	var err error
 
-	parsedJms, err := parseJSONMessages(jms)
-	if err != nil {
-		_ = errorjson.Print(err, stdout, stderr)
-		return err
-	}
-
-	if len(parsedJms) == 1 {
-		err = json.NewEncoder(stdout).Encode(parsedJms[0])
-	} else {
-		err = json.NewEncoder(stdout).Encode(parsedJms)
-	}
-
-	return err
+	return parseJSONMessages(jms)
 }
 
 func parseJSONMessages(jms []json.RawMessage) ([]json.RawMessage, error) {
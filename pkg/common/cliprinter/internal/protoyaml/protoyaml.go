@@ -0,0 +1,45 @@
+package protoyaml
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/erroryaml"
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/protojson"
+	"google.golang.org/protobuf/proto"
+	"sigs.k8s.io/yaml"
+)
+
+// Print prints one or more protobuf messages formatted as YAML. The messages
+// have the same shape and field names as when formatted as JSON.
+func Print(msgs []proto.Message, stdout, stderr io.Writer) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	jms, err := protojson.Marshal(msgs)
+	if err != nil {
+		_ = erroryaml.Print(err, stdout, stderr)
+		return err
+	}
+
+	var jb []byte
+	if len(jms) == 1 {
+		jb = jms[0]
+	} else {
+		jb, err = json.Marshal(jms)
+		if err != nil {
+			_ = erroryaml.Print(err, stdout, stderr)
+			return err
+		}
+	}
+
+	yb, err := yaml.JSONToYAML(jb)
+	if err != nil {
+		_ = erroryaml.Print(err, stdout, stderr)
+		return err
+	}
+
+	_, err = stdout.Write(yb)
+	return err
+}
@@ -0,0 +1,66 @@
+package protoyaml
+
+import (
+	"bytes"
+	"testing"
+
+	agentapi "github.com/spiffe/spire-api-sdk/proto/spire/api/server/agent/v1"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/server/trustdomain/v1"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPrint(t *testing.T) {
+	cases := []struct {
+		name   string
+		msgs   []proto.Message
+		stdout string
+	}{
+		{
+			name:   "normal_protobuf_message",
+			msgs:   []proto.Message{&agentapi.CountAgentsResponse{Count: 42}},
+			stdout: "count: 42\n",
+		},
+		{
+			name: "double_protobuf_message",
+			msgs: []proto.Message{
+				&agentapi.CountAgentsResponse{Count: 42},
+				&agentapi.CountAgentsResponse{Count: 43},
+			},
+			stdout: "- count: 42\n- count: 43\n",
+		},
+		{
+			name:   "no_message",
+			stdout: "",
+		},
+		{
+			name: "message_with_null_pointers",
+			msgs: []proto.Message{&trustdomain.ListFederationRelationshipsResponse{
+				FederationRelationships: []*types.FederationRelationship{
+					{
+						TrustDomain:       "example.org",
+						BundleEndpointUrl: "https://example.org/bundle",
+					},
+				},
+			}},
+			stdout: "" +
+				"federation_relationships:\n" +
+				"- bundle_endpoint_url: https://example.org/bundle\n" +
+				"  trust_domain: example.org\n" +
+				"next_page_token: \"\"\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			err := Print(c.msgs, stdout, stderr)
+			require.NoError(t, err)
+			assert.Equal(t, c.stdout, stdout.String())
+			assert.Empty(t, stderr.String())
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package structyaml
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Print prints one or more structs formatted as YAML. The structs are
+// marshaled to JSON first, so the JSON field names are honored.
+func Print(msgs []any, stdout, _ io.Writer) error {
+	var jb []byte
+	var err error
+
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	if len(msgs) == 1 {
+		jb, err = json.Marshal(msgs[0])
+	} else {
+		jb, err = json.Marshal(msgs)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(stdout, "error: %q\n", err.Error())
+		return err
+	}
+
+	yb, err := yaml.JSONToYAML(jb)
+	if err != nil {
+		_, _ = fmt.Fprintf(stdout, "error: %q\n", err.Error())
+		return err
+	}
+
+	_, err = stdout.Write(yb)
+	return err
+}
@@ -0,0 +1,55 @@
+package structyaml
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrint(t *testing.T) {
+	cases := []struct {
+		name   string
+		s      []any
+		stdout string
+	}{
+		{
+			name:   "friendly_struct",
+			s:      []any{&friendlyStruct{Friendly: true}},
+			stdout: "friendly: true\n",
+		},
+		{
+			name: "double_friendly_struct",
+			s: []any{
+				&friendlyStruct{Friendly: true},
+				&friendlyStruct{Friendly: false},
+			},
+			stdout: "- friendly: true\n- friendly: false\n",
+		},
+		{
+			name:   "nil_slice",
+			s:      nil,
+			stdout: "",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			err := Print(c.s, stdout, &bytes.Buffer{})
+			assert.NoError(t, err)
+			assert.Equal(t, c.stdout, stdout.String())
+		})
+	}
+}
+
+func TestPrintUnmarshalable(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	err := Print([]any{make(chan int)}, stdout, &bytes.Buffer{})
+	assert.Error(t, err)
+	assert.Equal(t, "error: \"json: unsupported type: chan int\"\n", stdout.String())
+}
+
+type friendlyStruct struct {
+	Friendly bool `json:"friendly"`
+}
@@ -0,0 +1,288 @@
+// Package tabular prints messages as column-aligned tables.
+//
+// Cell values are taken from the JSON representation of the messages, so
+// columns are named after the JSON (protobuf) field names. Nested fields are
+// selected with dotted paths, e.g. "spiffe_id.path".
+package tabular
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ParseColumns parses a comma separated list of columns.
+func ParseColumns(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var columns []string
+	for _, column := range strings.Split(s, ",") {
+		column = strings.TrimSpace(column)
+		if column == "" {
+			return nil, fmt.Errorf("invalid table columns %q", s)
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// PrintProto prints each of the protobuf messages as a table. List responses,
+// i.e. response messages with a single repeated message field, are printed
+// with one row per element of that field. Any other message is printed as a
+// single row. When no columns are given, a column is printed for each field
+// of the row message, in declaration order.
+func PrintProto(msgs []proto.Message, columns []string, stdout, _ io.Writer) error {
+	jms, err := protojson.Marshal(msgs)
+	if err != nil {
+		return err
+	}
+
+	out := new(bytes.Buffer)
+	for i, msg := range msgs {
+		value, err := decode(jms[i])
+		if err != nil {
+			return err
+		}
+
+		rowDesc := msg.ProtoReflect().Descriptor()
+		rows := []any{value}
+		if field := listField(rowDesc); field != nil {
+			rowDesc = field.Message()
+			rows = listValue(value, string(field.Name()))
+		}
+
+		msgColumns := columns
+		if len(msgColumns) == 0 {
+			msgColumns = fieldNames(rowDesc)
+		}
+
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		if err := write(out, msgColumns, rows); err != nil {
+			return err
+		}
+	}
+
+	_, err = out.WriteTo(stdout)
+	return err
+}
+
+// PrintStruct prints each of the structs as a table. Slices, and structs
+// with a single field holding a slice, are printed with one row per element.
+// Any other struct is printed as a single row. When no columns are given, a
+// column is printed for each field of the rows, in alphabetical order.
+func PrintStruct(msgs []any, columns []string, stdout, _ io.Writer) error {
+	out := new(bytes.Buffer)
+	for i, msg := range msgs {
+		jb, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		value, err := decode(jb)
+		if err != nil {
+			return err
+		}
+
+		rows := []any{value}
+		switch v := value.(type) {
+		case []any:
+			rows = v
+		case map[string]any:
+			if len(v) == 1 {
+				for _, field := range v {
+					if list, ok := field.([]any); ok {
+						rows = list
+					}
+				}
+			}
+		}
+
+		msgColumns := columns
+		if len(msgColumns) == 0 {
+			msgColumns = keys(rows)
+		}
+
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		if err := write(out, msgColumns, rows); err != nil {
+			return err
+		}
+	}
+
+	_, err := out.WriteTo(stdout)
+	return err
+}
+
+func write(out io.Writer, columns []string, rows []any) error {
+	if len(columns) == 0 {
+		return nil
+	}
+
+	buf := new(bytes.Buffer)
+	tw := tabwriter.NewWriter(buf, 0, 8, 3, ' ', 0)
+	header := make([]string, 0, len(columns))
+	for _, column := range columns {
+		header = append(header, strings.ToUpper(column))
+	}
+	if _, err := fmt.Fprintln(tw, strings.Join(header, "\t")); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		cells := make([]string, 0, len(columns))
+		for _, column := range columns {
+			cells = append(cells, cell(lookup(row, column)))
+		}
+		if _, err := fmt.Fprintln(tw, strings.Join(cells, "\t")); err != nil {
+			return err
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	// Empty trailing cells are padded by the tabwriter
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		if _, err := io.WriteString(out, strings.TrimRight(line, " ")+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listField returns the field holding the list of a list response, if any.
+func listField(desc protoreflect.MessageDescriptor) protoreflect.FieldDescriptor {
+	if !strings.HasSuffix(string(desc.Name()), "Response") {
+		return nil
+	}
+
+	var list protoreflect.FieldDescriptor
+	fields := desc.Fields()
+	for i := range fields.Len() {
+		field := fields.Get(i)
+		if !field.IsList() || field.Message() == nil {
+			continue
+		}
+		if list != nil {
+			return nil
+		}
+		list = field
+	}
+	return list
+}
+
+func listValue(value any, name string) []any {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	list, _ := object[name].([]any)
+	return list
+}
+
+func fieldNames(desc protoreflect.MessageDescriptor) []string {
+	fields := desc.Fields()
+	names := make([]string, 0, fields.Len())
+	for i := range fields.Len() {
+		names = append(names, string(fields.Get(i).Name()))
+	}
+	return names
+}
+
+func keys(rows []any) []string {
+	set := make(map[string]struct{})
+	for _, row := range rows {
+		if object, ok := row.(map[string]any); ok {
+			for key := range object {
+				set[key] = struct{}{}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(set))
+	for key := range set {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func lookup(value any, column string) any {
+	for _, name := range strings.Split(column, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+	return value
+}
+
+func cell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, elem := range v {
+			values = append(values, cell(elem))
+		}
+		return strings.Join(values, ",")
+	case map[string]any:
+		return objectCell(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// objectCell renders an object. SPIFFE IDs and selectors are rendered in
+// their string form. Any other object is rendered as compact JSON.
+func objectCell(object map[string]any) string {
+	if len(object) == 2 {
+		if td, ok := object["trust_domain"].(string); ok {
+			if path, ok := object["path"].(string); ok {
+				return "spiffe://" + td + path
+			}
+		}
+		if t, ok := object["type"].(string); ok {
+			if value, ok := object["value"].(string); ok {
+				return t + ":" + value
+			}
+		}
+	}
+
+	jb, err := json.Marshal(object)
+	if err != nil {
+		return fmt.Sprint(object)
+	}
+	return string(jb)
+}
+
+func decode(jb []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(jb))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
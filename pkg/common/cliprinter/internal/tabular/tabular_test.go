@@ -0,0 +1,167 @@
+package tabular
+
+import (
+	"bytes"
+	"testing"
+
+	agentapi "github.com/spiffe/spire-api-sdk/proto/spire/api/server/agent/v1"
+	entryapi "github.com/spiffe/spire-api-sdk/proto/spire/api/server/entry/v1"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	entry1 = &types.Entry{
+		Id:       "ENTRY1",
+		SpiffeId: &types.SPIFFEID{TrustDomain: "example.org", Path: "/workload"},
+		ParentId: &types.SPIFFEID{TrustDomain: "example.org", Path: "/agent"},
+		Selectors: []*types.Selector{
+			{Type: "unix", Value: "uid:1000"},
+			{Type: "unix", Value: "gid:1000"},
+		},
+		X509SvidTtl: 3600,
+	}
+	entry2 = &types.Entry{
+		Id:       "ENTRY2",
+		SpiffeId: &types.SPIFFEID{TrustDomain: "example.org", Path: "/other-workload"},
+		ParentId: &types.SPIFFEID{TrustDomain: "example.org", Path: "/agent"},
+		Hint:     "external",
+	}
+)
+
+func TestPrintProto(t *testing.T) {
+	cases := []struct {
+		name    string
+		msgs    []proto.Message
+		columns []string
+		stdout  string
+	}{
+		{
+			name:    "list_response_prints_a_row_per_element",
+			msgs:    []proto.Message{&entryapi.ListEntriesResponse{Entries: []*types.Entry{entry1, entry2}}},
+			columns: []string{"id", "spiffe_id", "selectors", "hint"},
+			stdout: "" +
+				"ID       SPIFFE_ID                             SELECTORS                     HINT\n" +
+				"ENTRY1   spiffe://example.org/workload         unix:uid:1000,unix:gid:1000\n" +
+				"ENTRY2   spiffe://example.org/other-workload                                 external\n",
+		},
+		{
+			name:    "nested_columns",
+			msgs:    []proto.Message{&entryapi.ListEntriesResponse{Entries: []*types.Entry{entry1}}},
+			columns: []string{"spiffe_id.path", "x509_svid_ttl", "missing"},
+			stdout: "" +
+				"SPIFFE_ID.PATH   X509_SVID_TTL   MISSING\n" +
+				"/workload        3600\n",
+		},
+		{
+			name: "message_is_a_single_row_with_default_columns",
+			msgs: []proto.Message{&agentapi.CountAgentsResponse{Count: 42}},
+			stdout: "" +
+				"COUNT\n" +
+				"42\n",
+		},
+		{
+			name: "non_response_message_is_a_single_row",
+			msgs: []proto.Message{&types.Agent{
+				Id:        &types.SPIFFEID{TrustDomain: "example.org", Path: "/agent"},
+				Selectors: []*types.Selector{{Type: "k8s_psat", Value: "cluster:demo"}},
+			}},
+			columns: []string{"id", "selectors"},
+			stdout: "" +
+				"ID                           SELECTORS\n" +
+				"spiffe://example.org/agent   k8s_psat:cluster:demo\n",
+		},
+		{
+			name: "each_message_is_a_table",
+			msgs: []proto.Message{
+				&agentapi.CountAgentsResponse{Count: 1},
+				&agentapi.CountAgentsResponse{Count: 2},
+			},
+			stdout: "" +
+				"COUNT\n" +
+				"1\n" +
+				"\n" +
+				"COUNT\n" +
+				"2\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			err := PrintProto(c.msgs, c.columns, stdout, &bytes.Buffer{})
+			require.NoError(t, err)
+			assert.Equal(t, c.stdout, stdout.String())
+		})
+	}
+}
+
+func TestPrintProtoDefaultColumns(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	err := PrintProto([]proto.Message{&entryapi.ListEntriesResponse{Entries: []*types.Entry{entry1}}}, nil, stdout, &bytes.Buffer{})
+	require.NoError(t, err)
+
+	lines := bytes.SplitN(stdout.Bytes(), []byte("\n"), 2)
+	assert.Regexp(t, "^ID +SPIFFE_ID +PARENT_ID +SELECTORS +X509_SVID_TTL ", string(lines[0]))
+}
+
+func TestPrintStruct(t *testing.T) {
+	type agent struct {
+		AgentID string `json:"agent_id"`
+		Deleted bool   `json:"deleted"`
+	}
+	type agents struct {
+		Agents []agent `json:"agents"`
+	}
+
+	cases := []struct {
+		name    string
+		msgs    []any
+		columns []string
+		stdout  string
+	}{
+		{
+			name: "single_field_with_a_list_prints_a_row_per_element",
+			msgs: []any{&agents{Agents: []agent{
+				{AgentID: "spiffe://example.org/agent1", Deleted: true},
+				{AgentID: "spiffe://example.org/agent2"},
+			}}},
+			stdout: "" +
+				"AGENT_ID                      DELETED\n" +
+				"spiffe://example.org/agent1   true\n" +
+				"spiffe://example.org/agent2   false\n",
+		},
+		{
+			name:    "struct_is_a_single_row",
+			msgs:    []any{agent{AgentID: "spiffe://example.org/agent1"}},
+			columns: []string{"deleted"},
+			stdout: "" +
+				"DELETED\n" +
+				"false\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			err := PrintStruct(c.msgs, c.columns, stdout, &bytes.Buffer{})
+			require.NoError(t, err)
+			assert.Equal(t, c.stdout, stdout.String())
+		})
+	}
+}
+
+func TestParseColumns(t *testing.T) {
+	columns, err := ParseColumns("id, spiffe_id.path")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "spiffe_id.path"}, columns)
+
+	columns, err = ParseColumns("")
+	require.NoError(t, err)
+	assert.Nil(t, columns)
+
+	_, err = ParseColumns("id,,hint")
+	assert.EqualError(t, err, `invalid table columns "id,,hint"`)
+}
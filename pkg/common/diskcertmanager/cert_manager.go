@@ -58,6 +58,15 @@ func New(config *Config, clk clock.Clock, log logrus.FieldLogger) (*DiskCertMana
 	return dm, nil
 }
 
+// Config returns the configuration the manager was created with.
+func (m *DiskCertManager) Config() Config {
+	return Config{
+		CertFilePath:     m.certFilePath,
+		KeyFilePath:      m.keyFilePath,
+		FileSyncInterval: m.fileSyncInterval,
+	}
+}
+
 // TLSConfig returns a TLS configuration that uses the provided certificate stored on disk.
 func (m *DiskCertManager) GetTLSConfig() *tls.Config {
 	return &tls.Config{
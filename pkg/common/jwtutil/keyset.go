@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
 	"sync"
 	"time"
@@ -46,12 +47,42 @@ func (c OIDCIssuer) GetKeySet(ctx context.Context) (*jose.JSONWebKeySet, error)
 	return FetchKeySet(ctx, uri)
 }
 
+// KeySetURL is a KeySetProvider that fetches the key set from a JWKS URL.
+type KeySetURL string
+
+func (c KeySetURL) GetKeySet(ctx context.Context) (*jose.JSONWebKeySet, error) {
+	return FetchKeySet(ctx, string(c))
+}
+
+// KeySetFile is a KeySetProvider that reads the key set from a JWKS file on
+// disk.
+type KeySetFile string
+
+func (c KeySetFile) GetKeySet(context.Context) (*jose.JSONWebKeySet, error) {
+	data, err := os.ReadFile(string(c))
+	if err != nil {
+		return nil, err
+	}
+
+	jwks := new(jose.JSONWebKeySet)
+	if err := json.Unmarshal(data, jwks); err != nil {
+		return nil, fmt.Errorf("failed to decode key set: %w", err)
+	}
+
+	return jwks, nil
+}
+
+// minForcedRefreshInterval bounds how often RefreshKeySet goes to the wrapped
+// provider, so tokens with unknown key IDs cannot be used to hammer it.
+const minForcedRefreshInterval = 30 * time.Second
+
 type CachingKeySetProvider struct {
 	provider        KeySetProvider
 	refreshInterval time.Duration
 
 	mu      sync.Mutex
 	updated time.Time
+	forced  time.Time
 	jwks    *jose.JSONWebKeySet
 
 	hooks struct {
@@ -78,6 +109,28 @@ func (c *CachingKeySetProvider) GetKeySet(ctx context.Context) (*jose.JSONWebKey
 		return c.jwks, nil
 	}
 
+	return c.refresh(ctx, now)
+}
+
+// RefreshKeySet refreshes the key set ahead of the refresh interval, e.g. when
+// a token is signed with a key that is not in the cached set. Forced refreshes
+// happen at most once every minForcedRefreshInterval; in between, the cached
+// key set is returned.
+func (c *CachingKeySetProvider) RefreshKeySet(ctx context.Context) (*jose.JSONWebKeySet, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.hooks.now()
+
+	if c.jwks != nil && !c.forced.IsZero() && now.Sub(c.forced) < minForcedRefreshInterval {
+		return c.jwks, nil
+	}
+	c.forced = now
+
+	return c.refresh(ctx, now)
+}
+
+func (c *CachingKeySetProvider) refresh(ctx context.Context, now time.Time) (*jose.JSONWebKeySet, error) {
 	// refresh key set. if there is a failure, log and return the old set if
 	// available.
 	jwks, err := c.provider.GetKeySet(ctx)
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -72,6 +74,38 @@ func TestOIDCIssuer(t *testing.T) {
 	require.NotNil(t, keySet)
 }
 
+func TestKeySetURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(jwksHandler))
+	defer server.Close()
+
+	keySet, err := KeySetURL(server.URL + "/keys").GetKeySet(context.Background())
+	require.NoError(t, err)
+	require.Len(t, keySet.Key("TioGywwlhvdFbXZ813WpPay9AlU"), 1)
+}
+
+func TestKeySetFile(t *testing.T) {
+	dir := t.TempDir()
+
+	// missing file
+	keySet, err := KeySetFile(filepath.Join(dir, "missing.json")).GetKeySet(context.Background())
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.Nil(t, keySet)
+
+	// malformed file
+	malformedPath := filepath.Join(dir, "malformed.json")
+	require.NoError(t, os.WriteFile(malformedPath, []byte("{"), 0600))
+	keySet, err = KeySetFile(malformedPath).GetKeySet(context.Background())
+	require.EqualError(t, err, "failed to decode key set: unexpected end of JSON input")
+	require.Nil(t, keySet)
+
+	// success
+	keysPath := filepath.Join(dir, "keys.json")
+	require.NoError(t, os.WriteFile(keysPath, []byte(`{"keys":[{"kty":"EC","kid":"A","crv":"P-256","x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU","y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"}]}`), 0600))
+	keySet, err = KeySetFile(keysPath).GetKeySet(context.Background())
+	require.NoError(t, err)
+	require.Len(t, keySet.Key("A"), 1)
+}
+
 func TestCachingKeySetProvider(t *testing.T) {
 	a := &jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{KeyID: "A"}}}
 	b := &jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{KeyID: "B"}}}
@@ -139,6 +173,68 @@ func TestCachingKeySetProvider(t *testing.T) {
 	require.Equal(t, b, jwks)
 }
 
+func TestCachingKeySetProviderRefreshKeySet(t *testing.T) {
+	a := &jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{KeyID: "A"}}}
+	b := &jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{KeyID: "B"}}}
+	c := &jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{KeyID: "C"}}}
+
+	providerJWKS := a
+	var providerErr error
+	provider := func(ctx context.Context) (*jose.JSONWebKeySet, error) {
+		return providerJWKS, providerErr
+	}
+	now := time.Now()
+
+	// set up a new caching provider that refreshes every hour
+	caching := NewCachingKeySetProvider(KeySetProviderFunc(provider), time.Hour)
+	caching.hooks.now = func() time.Time {
+		return now
+	}
+
+	jwks, err := caching.GetKeySet(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, a, jwks)
+
+	// assert that a forced refresh returns keyset "b" even though the
+	// refresh interval has not elapsed
+	providerJWKS = b
+	jwks, err = caching.RefreshKeySet(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, b, jwks)
+
+	// assert that the refreshed keyset is cached
+	providerJWKS = c
+	jwks, err = caching.GetKeySet(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, b, jwks)
+
+	// assert that another forced refresh within the minimum interval
+	// returns the cached keyset "b"
+	now = now.Add(minForcedRefreshInterval - time.Second)
+	jwks, err = caching.RefreshKeySet(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, b, jwks)
+
+	// assert that a failed forced refresh returns the cached keyset "b"
+	now = now.Add(time.Second)
+	providerErr = errors.New("FAILED")
+	jwks, err = caching.RefreshKeySet(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, b, jwks)
+
+	// assert that the failed attempt counts against the minimum interval
+	providerErr = nil
+	jwks, err = caching.RefreshKeySet(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, b, jwks)
+
+	// assert that the next forced refresh returns keyset "c"
+	now = now.Add(minForcedRefreshInterval)
+	jwks, err = caching.RefreshKeySet(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, c, jwks)
+}
+
 func jwksHandler(w http.ResponseWriter, req *http.Request) {
 	if req.Method != "GET" {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
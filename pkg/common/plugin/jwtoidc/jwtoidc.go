@@ -0,0 +1,97 @@
+package jwtoidc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/common/agentpathtemplate"
+	"github.com/spiffe/spire/pkg/common/idutil"
+)
+
+const (
+	PluginName = "jwt_oidc"
+)
+
+// DefaultAgentPathTemplate is the default text/template. The subject is
+// hashed since subjects issued by CI systems usually contain characters
+// that are not allowed in a SPIFFE ID path.
+var DefaultAgentPathTemplate = agentpathtemplate.MustParse("/{{ .PluginName }}/{{ .SubjectHash }}")
+
+// AttestationData is the payload sent by the agent to the server.
+type AttestationData struct {
+	Token string `json:"token"`
+}
+
+type agentPathTemplateData struct {
+	PluginName  string
+	Issuer      string
+	Subject     string
+	SubjectHash string
+	Claims      map[string]string
+}
+
+// MakeAgentID makes an agent SPIFFE ID. The ID always has a host value equal
+// to the given trust domain, the path is created using the given
+// agentPathTemplate which is given access to the issuer, the subject, the
+// hex encoded SHA-256 hash of the subject and the scalar claims of the token.
+func MakeAgentID(td spiffeid.TrustDomain, agentPathTemplate *agentpathtemplate.Template, claims map[string]any) (spiffeid.ID, error) {
+	scalarClaims := make(map[string]string, len(claims))
+	for name, value := range claims {
+		if s, ok := scalarClaimValue(value); ok {
+			scalarClaims[name] = s
+		}
+	}
+
+	subject := scalarClaims["sub"]
+	subjectHash := sha256.Sum256([]byte(subject))
+
+	agentPath, err := agentPathTemplate.Execute(agentPathTemplateData{
+		PluginName:  PluginName,
+		Issuer:      scalarClaims["iss"],
+		Subject:     subject,
+		SubjectHash: hex.EncodeToString(subjectHash[:]),
+		Claims:      scalarClaims,
+	})
+	if err != nil {
+		return spiffeid.ID{}, err
+	}
+
+	return idutil.AgentID(td, agentPath)
+}
+
+// ClaimValues returns the string representation of the named claim. Strings,
+// numbers and booleans produce a single value and arrays of those produce
+// one value per element. Missing claims and claims holding objects produce
+// no values.
+func ClaimValues(claims map[string]any, name string) []string {
+	switch value := claims[name].(type) {
+	case []any:
+		var values []string
+		for _, element := range value {
+			if s, ok := scalarClaimValue(element); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		if s, ok := scalarClaimValue(value); ok {
+			return []string{s}
+		}
+		return nil
+	}
+}
+
+func scalarClaimValue(value any) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(value), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,54 @@
+package jwtoidc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/common/agentpathtemplate"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeAgentID(t *testing.T) {
+	td := spiffeid.RequireTrustDomainFromString("example.org")
+	claims := parseClaims(t, `{
+		"iss": "https://token.example.org",
+		"sub": "repo:acme/app:ref:refs/heads/main",
+		"run_id": 1234567890,
+		"aud": ["spire"]
+	}`)
+
+	id, err := MakeAgentID(td, DefaultAgentPathTemplate, claims)
+	require.NoError(t, err)
+	require.Equal(t, "spiffe://example.org/spire/agent/jwt_oidc/fc58e6ceb596cd5e8b6a7de78fd8e8fedbe9e31545e920c8e6589a16c9a98cf1", id.String())
+
+	id, err = MakeAgentID(td, agentpathtemplate.MustParse("/ci/{{ .Claims.run_id }}"), claims)
+	require.NoError(t, err)
+	require.Equal(t, "spiffe://example.org/spire/agent/ci/1234567890", id.String())
+
+	_, err = MakeAgentID(td, agentpathtemplate.MustParse("/ci/{{ .Subject }}"), claims)
+	require.EqualError(t, err, `invalid agent path suffix "/ci/repo:acme/app:ref:refs/heads/main": path segment characters are limited to letters, numbers, dots, dashes, and underscores`)
+}
+
+func TestClaimValues(t *testing.T) {
+	claims := parseClaims(t, `{
+		"string": "value",
+		"number": 42.5,
+		"bool": true,
+		"array": ["a", 1, {"nested": "ignored"}],
+		"object": {"nested": "ignored"}
+	}`)
+
+	require.Equal(t, []string{"value"}, ClaimValues(claims, "string"))
+	require.Equal(t, []string{"42.5"}, ClaimValues(claims, "number"))
+	require.Equal(t, []string{"true"}, ClaimValues(claims, "bool"))
+	require.Equal(t, []string{"a", "1"}, ClaimValues(claims, "array"))
+	require.Empty(t, ClaimValues(claims, "object"))
+	require.Empty(t, ClaimValues(claims, "missing"))
+}
+
+func parseClaims(t *testing.T, s string) map[string]any {
+	claims := make(map[string]any)
+	require.NoError(t, json.Unmarshal([]byte(s), &claims))
+	return claims
+}
@@ -63,6 +63,8 @@ func TestAllTrueMasks(t *testing.T) {
 		NewCertNotAfter:     true,
 		CanReattest:         true,
 		AgentVersion:        true,
+		LastSeenAt:          true,
+		StatusReport:        true,
 	}, protoutil.AllTrueCommonAgentMask)
 
 	spiretest.AssertProtoEqual(t, &types.FederationRelationshipMask{
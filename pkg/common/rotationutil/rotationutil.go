@@ -60,6 +60,13 @@ func JWTSVIDExpired(svid *client.JWTSVID, now time.Time) bool {
 	return !now.Before(svid.ExpiresAt)
 }
 
+// ShouldRotateWIT determines if a WIT-SVID issued at the given time and
+// expiring at the given time should be rotated. WIT-SVIDs are rotated at
+// (jittered) half-life, the same as JWT-SVIDs.
+func (rs *RotationStrategy) ShouldRotateWIT(now, issuedAt, expiresAt time.Time) bool {
+	return shouldRotateJWT(now, issuedAt, expiresAt)
+}
+
 func shouldRotateX509(now, beginTime, expiryTime time.Time, availabilityTarget time.Duration) bool {
 	ttl := expiryTime.Sub(now)
 	// return true quickly if the expiry is already met.
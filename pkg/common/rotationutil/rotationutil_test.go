@@ -164,3 +164,39 @@ func TestJWTSVIDExpiresSoon(t *testing.T) {
 		})
 	}
 }
+
+func TestShouldRotateWIT(t *testing.T) {
+	mockClk := clock.NewMock(t)
+	now := mockClk.Now()
+
+	for _, tc := range []struct {
+		desc         string
+		issuedAt     time.Time
+		expiresAt    time.Time
+		shouldRotate bool
+	}{
+		{
+			desc:         "brand new token",
+			issuedAt:     now,
+			expiresAt:    now.Add(time.Hour),
+			shouldRotate: false,
+		},
+		{
+			desc:         "token that's almost expired",
+			issuedAt:     now.Add(-1 * time.Hour),
+			expiresAt:    now.Add(1 * time.Minute),
+			shouldRotate: true,
+		},
+		{
+			desc:         "token that's already expired",
+			issuedAt:     now.Add(-1 * time.Hour),
+			expiresAt:    now.Add(-30 * time.Minute),
+			shouldRotate: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			rs := NewRotationStrategy(0)
+			assert.Equal(t, tc.shouldRotate, rs.ShouldRotateWIT(now, tc.issuedAt, tc.expiresAt))
+		})
+	}
+}
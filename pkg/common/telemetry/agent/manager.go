@@ -27,6 +27,13 @@ func StartManagerFetchSVIDsUpdatesCall(m telemetry.Metrics) *telemetry.CallCount
 	return telemetry.StartCall(m, telemetry.Manager, telemetry.Sync, telemetry.FetchSVIDsUpdates)
 }
 
+// StartManagerFetchWITSVIDsUpdatesCall returns metric for when agent's
+// synchronization manager fetching latest WIT-SVIDs information
+// from server
+func StartManagerFetchWITSVIDsUpdatesCall(m telemetry.Metrics) *telemetry.CallCounter {
+	return telemetry.StartCall(m, telemetry.Manager, telemetry.Sync, telemetry.FetchSVIDsUpdates, telemetry.WIT)
+}
+
 // End Call Counters
 
 // Add Samples (metric on count of some object, entries, event...)
@@ -71,8 +78,34 @@ func AddCacheManagerTaintedJWTSVIDsSample(m telemetry.Metrics, cacheType string,
 	m.AddSample(key, count)
 }
 
+// AddCacheManagerTaintedWITSVIDsSample count of tainted WIT-SVIDs according to
+// agent cache manager
+func AddCacheManagerTaintedWITSVIDsSample(m telemetry.Metrics, cacheType string, count float32) {
+	key := []string{telemetry.CacheManager, telemetry.TaintedWITSVIDs}
+	if cacheType != "" {
+		key = append(key, cacheType)
+	}
+	m.AddSample(key, count)
+}
+
 // End Add Samples
 
+// SetServingWorkloadSnapshot sets whether the agent cache manager is serving
+// workloads from the workload SVID snapshot restored at startup
+func SetServingWorkloadSnapshot(m telemetry.Metrics, serving bool) {
+	var val float32
+	if serving {
+		val = 1
+	}
+	m.SetGauge([]string{telemetry.CacheManager, telemetry.WorkloadSnapshot, telemetry.Serving}, val)
+}
+
+// IncrementWorkloadSnapshotRestoredSVIDs counts the X509-SVIDs restored from
+// the workload SVID snapshot
+func IncrementWorkloadSnapshotRestoredSVIDs(m telemetry.Metrics, count int) {
+	m.IncrCounter([]string{telemetry.CacheManager, telemetry.WorkloadSnapshot, telemetry.Restore}, float32(count))
+}
+
 func SetSyncStats(m telemetry.Metrics, stats client.SyncStats) {
 	m.SetGauge([]string{telemetry.SyncBundlesTotal}, float32(stats.Bundles.Total))
 	m.SetGauge([]string{telemetry.SyncEntriesTotal}, float32(stats.Entries.Total))
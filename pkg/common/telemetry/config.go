@@ -27,6 +27,9 @@ type FileConfig struct {
 	Statsd     []StatsdConfig    `hcl:"Statsd"`
 	M3         []M3Config        `hcl:"M3"`
 	InMem      *InMem            `hcl:"InMem"`
+	OTLP       *OTLPConfig       `hcl:"OTLP"`
+
+	Tracing *TracingConfig `hcl:"Tracing"`
 
 	MetricPrefix           string   `hcl:"MetricPrefix"`
 	EnableTrustDomainLabel *bool    `hcl:"EnableTrustDomainLabel"`
@@ -70,6 +73,34 @@ type M3Config struct {
 	UnusedKeyPositions map[string][]token.Pos `hcl:",unusedKeyPositions"`
 }
 
+type OTLPConfig struct {
+	Protocol           string                 `hcl:"protocol"`
+	Endpoint           string                 `hcl:"endpoint"`
+	Insecure           bool                   `hcl:"insecure"`
+	Headers            map[string]string      `hcl:"headers"`
+	TLS                *OTLPTLSConfig         `hcl:"tls"`
+	ResourceAttributes map[string]string      `hcl:"resource_attributes"`
+	PushInterval       string                 `hcl:"push_interval"`
+	UnusedKeyPositions map[string][]token.Pos `hcl:",unusedKeyPositions"`
+}
+
+type OTLPTLSConfig struct {
+	CAFile              string   `hcl:"ca_file"`   // optional
+	CertFile            string   `hcl:"cert_file"` // optional
+	KeyFile             string   `hcl:"key_file"`  // optional
+	UseSPIRESVID        bool     `hcl:"use_spire_svid"`
+	AuthorizedSPIFFEIDs []string `hcl:"authorized_spiffe_ids"`
+}
+
 type InMem struct {
 	UnusedKeyPositions map[string][]token.Pos `hcl:",unusedKeyPositions"`
 }
+
+type TracingConfig struct {
+	Exporter           string                 `hcl:"exporter"`
+	Endpoint           string                 `hcl:"endpoint"`
+	Insecure           bool                   `hcl:"insecure"`
+	Headers            map[string]string      `hcl:"headers"`
+	SampleRatio        *float64               `hcl:"sample_ratio"`
+	UnusedKeyPositions map[string][]token.Pos `hcl:",unusedKeyPositions"`
+}
@@ -22,6 +22,10 @@ const (
 	// to add clarity
 	Attest = "attest"
 
+	// Consume functionality related to consuming some entity; should be used with other tags
+	// to add clarity
+	Consume = "consume"
+
 	// Create functionality related to creating some entity; should be used with other tags
 	// to add clarity
 	Create = "create"
@@ -34,6 +38,10 @@ const (
 	// to add clarity
 	Delete = "delete"
 
+	// Failover functionality related to failing over from an unavailable
+	// server to another; should be used with other tags to add clarity
+	Failover = "failover"
+
 	// Fetch functionality related to fetching some entity; should be used with other tags
 	// to add clarity
 	Fetch = "fetch"
@@ -89,9 +97,17 @@ const (
 	// to add clarity
 	Push = "push"
 
+	// Release functionality related to releasing some entity from
+	// quarantine; should be used with other tags to add clarity
+	Release = "release"
+
 	// Reload functionality related to reloading of a cache
 	Reload = "reload"
 
+	// Restore functionality related to restoring some entity from a snapshot;
+	// should be used with other tags to add clarity
+	Restore = "restore"
+
 	// Rotate functionality related to rotation of SVID; should be used with other tags
 	// to add clarity
 	Rotate = "rotate"
@@ -136,6 +152,9 @@ const (
 // Attribute metric tags or labels that are typically an attribute of a
 // larger entity or logic path
 const (
+	// ActiveServer tags the SPIRE Server an agent is currently connected to
+	ActiveServer = "active_server"
+
 	// Address tags some network address
 	Address = "address"
 
@@ -210,6 +229,10 @@ const (
 	// to add clarity
 	CallerPath = "caller_path"
 
+	// CallerPID tags an API caller process ID; should be used with other tags
+	// to add clarity
+	CallerPID = "caller_pid"
+
 	// CertFilePath tags a certificate file path used for TLS connections.
 	CertFilePath = "cert_file_path"
 
@@ -359,6 +382,10 @@ const (
 	// FederationRelationship tags a federation relationship
 	FederationRelationship = "federation_relationship"
 
+	// FederationRelationshipOptions tags the options of a federation
+	// relationship
+	FederationRelationshipOptions = "federation_relationship_options"
+
 	// Generation represents an objection generation (i.e. version)
 	Generation = "generation"
 
@@ -408,6 +435,9 @@ const (
 	// LoggerAPI functionality related to logger endpoints
 	LoggerAPI = "logger_api"
 
+	// MaxUses tags the number of agents that can be attested with a join token
+	MaxUses = "max_uses"
+
 	// Mode tags a bundle deletion mode
 	Mode = "mode"
 
@@ -465,6 +495,13 @@ const (
 	// Pruned flagging something has been pruned
 	Pruned = "pruned"
 
+	// QuarantinedBundle is a federated bundle held back by the bundle
+	// safeguards of a federation relationship
+	QuarantinedBundle = "quarantined_bundle"
+
+	// QuarantinedBundleDigest tags the digest of a quarantined bundle
+	QuarantinedBundleDigest = "quarantined_bundle_digest"
+
 	// RateLimitExceeded tags a rate limit exceeded event
 	RateLimitExceeded = "rate_limit_exceeded"
 
@@ -515,6 +552,9 @@ const (
 	// RegistrationEntryEvent is a notice a registration entry has been created, modified, or deleted
 	RegistrationEntryEvent = "registration_entry_event"
 
+	// RemovedResourceNames tags some group of resources removed from a client by name
+	RemovedResourceNames = "removed_resource_names"
+
 	// RequestID tags a request identifier
 	RequestID = "request_id"
 
@@ -558,6 +598,12 @@ const (
 	// SerialNumber tags a certificate serial number
 	SerialNumber = "serial_num"
 
+	// Serving tags whether some entity is currently being served to workloads
+	Serving = "serving"
+
+	// Settings tags the names of some group of configuration settings
+	Settings = "settings"
+
 	// Slot X509 CA Slot ID
 	Slot = "slot"
 
@@ -580,12 +626,18 @@ const (
 	// with other tags to add clarity
 	Subject = "subject"
 
+	// SpanID tags the ID of the span the operation belongs to
+	SpanID = "span_id"
+
 	// SubjectKeyID tags a certificate subject key ID
 	SubjectKeyID = "subject_key_id"
 
 	// SubjectKeyIDs tags a list of subject key ID
 	SubjectKeyIDs = "subject_key_ids"
 
+	// SubscribedResourceNames tags some group of resources subscribed to by name
+	SubscribedResourceNames = "subscribed_resource_names"
+
 	// SVIDMapSize is the gauge key for the size of the LRU cache SVID map
 	SVIDMapSize = "lru_cache_svid_map_size"
 
@@ -631,6 +683,9 @@ const (
 	// Type tags a type
 	Type = "type"
 
+	// TraceID tags the ID of the trace the operation belongs to
+	TraceID = "trace_id"
+
 	// TrustDomain tags the name of some trust domain
 	TrustDomain = "trust_domain"
 
@@ -640,6 +695,9 @@ const (
 	// Unknown tags some unknown caller, entity, or status
 	Unknown = "unknown"
 
+	// UnsubscribedResourceNames tags some group of resources unsubscribed from by name
+	UnsubscribedResourceNames = "unsubscribed_resource_names"
+
 	// Updated tags some entity as updated; should be used
 	// with other tags to add clarity
 	Updated = "updated"
@@ -656,6 +714,18 @@ const (
 	// VersionInfo tags some version information
 	VersionInfo = "version_info"
 
+	// WITAuthorityExpiresAt tags a WIT Authority expiration
+	WITAuthorityExpiresAt = "wit_authority_expires_at"
+
+	// WITAuthorityKeyID tags a WIT authority key ID
+	WITAuthorityKeyID = "wit_authority_key_id"
+
+	// WITAuthorityKeyIDs tags a list of WIT authority key IDs
+	WITAuthorityKeyIDs = "wit_authority_key_ids"
+
+	// WITAuthorityPublicKeySHA256 tags a WIT Authority public key
+	WITAuthorityPublicKeySHA256 = "wit_authority_public_key_sha256"
+
 	// WITKeys tags some count or list of WIT Keys. Should NEVER provide the actual keys, use
 	// Key IDs instead.
 	WITKeys = "wit_keys"
@@ -666,6 +736,10 @@ const (
 	// WorkloadAttestor tags call of a workload attestor
 	WorkloadAttestor = "workload_attestor"
 
+	// WorkloadSnapshot tags the snapshot of the workload SVIDs persisted by
+	// the agent
+	WorkloadSnapshot = "workload_snapshot"
+
 	// X509 declared X509 SVID type, clarifying metrics
 	X509 = "x509"
 
@@ -686,6 +760,9 @@ const (
 	// Attestor tags an attestor plugin/type (eg. gcp, aws...)
 	Attestor = "attestor"
 
+	// AuthPolicy functionality related to the authorization policy engine
+	AuthPolicy = "auth_policy"
+
 	// Bundle functionality related to a bundle; should be used with other tags
 	// to add clarity
 	Bundle = "bundle"
@@ -798,6 +875,10 @@ const (
 	// to add clarity
 	ServerCA = "server_ca"
 
+	// ServerPool functionality related to selecting the SPIRE Server an
+	// agent connects to
+	ServerPool = "server_pool"
+
 	// Service is the name of the service invoked
 	Service = "service"
 
@@ -823,6 +904,9 @@ const (
 	// TaintedJWTSVIDs tags tainted JWT SVID count/list
 	TaintedJWTSVIDs = "tainted_jwt_svids"
 
+	// TaintedWITSVIDs tags tainted WIT SVID count/list
+	TaintedWITSVIDs = "tainted_wit_svids"
+
 	// TaintedX509SVIDs tags tainted X.509 SVID count/list
 	TaintedX509SVIDs = "tainted_x509_svids"
 
@@ -980,6 +1064,9 @@ const (
 	// ProcessTaintedJWTSVIDs functionality related to processing tainted JWT SVIDs.
 	ProcessTaintedJWTSVIDs = "process_tainted_jwt_svids"
 
+	// ProcessTaintedWITSVIDs functionality related to processing tainted WIT SVIDs.
+	ProcessTaintedWITSVIDs = "process_tainted_wit_svids"
+
 	// SDSAPI functionality related to SDS; should be used with other tags
 	// to add clarity
 	SDSAPI = "sds_api"
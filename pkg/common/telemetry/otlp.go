@@ -0,0 +1,393 @@
+package telemetry
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"github.com/spiffe/spire/pkg/common/tlspolicy"
+	"github.com/spiffe/spire/pkg/common/util"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	// OTLPProtocolGRPC exports metrics using OTLP over gRPC
+	OTLPProtocolGRPC = "grpc"
+
+	// OTLPProtocolHTTP exports metrics using OTLP over HTTP
+	OTLPProtocolHTTP = "http"
+
+	defaultOTLPPushInterval = 10 * time.Second
+	otlpShutdownGrace       = 5 * time.Second
+)
+
+type otlpRunner struct {
+	c                        *OTLPConfig
+	log                      logrus.FieldLogger
+	provider                 *sdkmetric.MeterProvider
+	sink                     *otlpSink
+	tlsPolicy                tlspolicy.Policy
+	getX509SVID              func() (*x509svid.SVID, error)
+	getX509BundleAuthorities func(spiffeid.TrustDomain) ([]*x509.Certificate, error)
+}
+
+func newOTLPRunner(c *MetricsConfig) (sinkRunner, error) {
+	runner := &otlpRunner{
+		c:                        c.FileConfig.OTLP,
+		log:                      c.Logger,
+		tlsPolicy:                c.TLSPolicy,
+		getX509SVID:              c.GetX509SVID,
+		getX509BundleAuthorities: c.GetX509BundleAuthorities,
+	}
+
+	if runner.c == nil {
+		return runner, nil
+	}
+
+	pushInterval := defaultOTLPPushInterval
+	if runner.c.PushInterval != "" {
+		var err error
+		pushInterval, err = time.ParseDuration(runner.c.PushInterval)
+		if err != nil {
+			return runner, fmt.Errorf("invalid OTLP push_interval: %w", err)
+		}
+		if pushInterval <= 0 {
+			return runner, fmt.Errorf("OTLP push_interval must be positive, got %v", pushInterval)
+		}
+	}
+
+	protocol := runner.c.Protocol
+	if protocol == "" {
+		protocol = OTLPProtocolGRPC
+	}
+
+	exporter, err := runner.newExporter(protocol)
+	if err != nil {
+		return runner, err
+	}
+
+	attrs := []attribute.KeyValue{semconv.ServiceName(c.ServiceName)}
+	for k, v := range runner.c.ResourceAttributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+
+	runner.provider = sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(pushInterval))),
+		sdkmetric.WithResource(resource.NewSchemaless(attrs...)),
+	)
+	runner.sink = newOTLPSink(runner.provider.Meter(instrumentationName), runner.log)
+
+	runner.log.WithFields(logrus.Fields{
+		"protocol":      protocol,
+		"endpoint":      runner.c.Endpoint,
+		"push_interval": pushInterval,
+	}).Info("Starting OTLP metrics exporter")
+
+	return runner, nil
+}
+
+func (r *otlpRunner) newExporter(protocol string) (sdkmetric.Exporter, error) {
+	var tlsCfg *tls.Config
+	if r.c.TLS != nil {
+		if r.c.Insecure {
+			return nil, errors.New("OTLP tls cannot be configured when insecure is enabled")
+		}
+		var err error
+		tlsCfg, err = r.newTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create TLS config for OTLP: %w", err)
+		}
+		if err := tlspolicy.ApplyPolicy(tlsCfg, r.tlsPolicy); err != nil {
+			return nil, fmt.Errorf("failed to apply TLS policy for OTLP: %w", err)
+		}
+	}
+
+	// The exporters do not connect until the first export, so creating them
+	// does not block on the collector being available.
+	ctx := context.Background()
+	switch protocol {
+	case OTLPProtocolGRPC:
+		var opts []otlpmetricgrpc.Option
+		if r.c.Endpoint != "" {
+			opts = append(opts, otlpmetricgrpc.WithEndpoint(r.c.Endpoint))
+		}
+		if r.c.Insecure {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+		if tlsCfg != nil {
+			opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsCfg)))
+		}
+		if len(r.c.Headers) > 0 {
+			opts = append(opts, otlpmetricgrpc.WithHeaders(r.c.Headers))
+		}
+		return otlpmetricgrpc.New(ctx, opts...)
+	case OTLPProtocolHTTP:
+		var opts []otlpmetrichttp.Option
+		if r.c.Endpoint != "" {
+			opts = append(opts, otlpmetrichttp.WithEndpoint(r.c.Endpoint))
+		}
+		if r.c.Insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		if tlsCfg != nil {
+			opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tlsCfg))
+		}
+		if len(r.c.Headers) > 0 {
+			opts = append(opts, otlpmetrichttp.WithHeaders(r.c.Headers))
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q", protocol)
+	}
+}
+
+func (r *otlpRunner) newTLSConfig() (*tls.Config, error) {
+	if err := r.validateTLSConfig(); err != nil {
+		return nil, err
+	}
+
+	authorizedSPIFFEIDs := make([]spiffeid.ID, 0, len(r.c.TLS.AuthorizedSPIFFEIDs))
+	for _, idString := range r.c.TLS.AuthorizedSPIFFEIDs {
+		id, err := spiffeid.FromString(idString)
+		if err != nil {
+			return nil, fmt.Errorf("invalid authorized SPIFFE ID %q: %w", idString, err)
+		}
+		authorizedSPIFFEIDs = append(authorizedSPIFFEIDs, id)
+	}
+
+	tlsCfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	// Authenticate the collector either with its SPIFFE ID or with the
+	// configured (or system) roots.
+	if len(authorizedSPIFFEIDs) > 0 {
+		bundleSource := &telemetryBundleSource{getter: r.getX509BundleAuthorities}
+		tlsconfig.HookTLSClientConfig(tlsCfg, bundleSource, tlsconfig.AuthorizeOneOf(authorizedSPIFFEIDs...))
+	} else if r.c.TLS.CAFile != "" {
+		rootCAs, err := util.LoadCertPool(r.c.TLS.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.RootCAs = rootCAs
+	}
+
+	switch {
+	case r.c.TLS.UseSPIRESVID:
+		tlsCfg.GetClientCertificate = tlsconfig.GetClientCertificate(&telemetryX509SVIDSource{getter: r.getX509SVID})
+	case r.c.TLS.CertFile != "":
+		certificate, err := tls.LoadX509KeyPair(r.c.TLS.CertFile, r.c.TLS.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsCfg, nil
+}
+
+func (r *otlpRunner) validateTLSConfig() error {
+	switch {
+	case r.c.TLS.UseSPIRESVID && (r.c.TLS.CertFile != "" || r.c.TLS.KeyFile != ""):
+		return errors.New("cert_file and key_file cannot be configured when use_spire_svid is enabled")
+	case (r.c.TLS.CertFile == "") != (r.c.TLS.KeyFile == ""):
+		return errors.New("cert_file and key_file must be configured together")
+	case len(r.c.TLS.AuthorizedSPIFFEIDs) > 0 && r.c.TLS.CAFile != "":
+		return errors.New("ca_file cannot be configured with authorized_spiffe_ids")
+	case r.c.TLS.UseSPIRESVID && r.getX509SVID == nil:
+		return errors.New("use_spire_svid requires access to the current SPIRE SVID")
+	case len(r.c.TLS.AuthorizedSPIFFEIDs) > 0 && r.getX509BundleAuthorities == nil:
+		return errors.New("authorized_spiffe_ids requires access to SPIRE trust bundles")
+	default:
+		return nil
+	}
+}
+
+func (r *otlpRunner) isConfigured() bool {
+	return r.c != nil
+}
+
+func (r *otlpRunner) sinks() []Sink {
+	if !r.isConfigured() {
+		return []Sink{}
+	}
+
+	return []Sink{r.sink}
+}
+
+func (r *otlpRunner) run(ctx context.Context) error {
+	if !r.isConfigured() {
+		return nil
+	}
+
+	<-ctx.Done()
+
+	// Flush any pending metrics before shutting down
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), otlpShutdownGrace)
+	defer cancel()
+	if err := r.provider.Shutdown(shutdownCtx); err != nil {
+		r.log.WithError(err).Warn("Failed to shut down OTLP metrics exporter")
+	}
+	return ctx.Err()
+}
+
+func (r *otlpRunner) requiresTypePrefix() bool {
+	return false
+}
+
+// otlpSink records go-metrics measurements using OpenTelemetry instruments.
+// Instruments are created lazily and cached by name.
+type otlpSink struct {
+	meter metric.Meter
+	log   logrus.FieldLogger
+
+	mu         sync.Mutex
+	gauges     map[string]metric.Float64Gauge
+	counters   map[string]metric.Float64Counter
+	histograms map[string]metric.Float64Histogram
+}
+
+func newOTLPSink(meter metric.Meter, log logrus.FieldLogger) *otlpSink {
+	return &otlpSink{
+		meter:      meter,
+		log:        log,
+		gauges:     make(map[string]metric.Float64Gauge),
+		counters:   make(map[string]metric.Float64Counter),
+		histograms: make(map[string]metric.Float64Histogram),
+	}
+}
+
+func (s *otlpSink) SetGauge(key []string, val float32) {
+	s.SetPrecisionGaugeWithLabels(key, float64(val), nil)
+}
+
+func (s *otlpSink) SetGaugeWithLabels(key []string, val float32, labels []Label) {
+	s.SetPrecisionGaugeWithLabels(key, float64(val), labels)
+}
+
+func (s *otlpSink) SetPrecisionGauge(key []string, val float64) {
+	s.SetPrecisionGaugeWithLabels(key, val, nil)
+}
+
+func (s *otlpSink) SetPrecisionGaugeWithLabels(key []string, val float64, labels []Label) {
+	gauge, ok := s.getGauge(otlpMetricName(key))
+	if !ok {
+		return
+	}
+	gauge.Record(context.Background(), val, metric.WithAttributes(labelsToAttributes(labels)...))
+}
+
+// Not implemented for OTLP
+func (s *otlpSink) EmitKey([]string, float32) {}
+
+func (s *otlpSink) IncrCounter(key []string, val float32) {
+	s.IncrCounterWithLabels(key, val, nil)
+}
+
+func (s *otlpSink) IncrCounterWithLabels(key []string, val float32, labels []Label) {
+	counter, ok := s.getCounter(otlpMetricName(key))
+	if !ok {
+		return
+	}
+	counter.Add(context.Background(), float64(val), metric.WithAttributes(labelsToAttributes(labels)...))
+}
+
+func (s *otlpSink) AddSample(key []string, val float32) {
+	s.AddSampleWithLabels(key, val, nil)
+}
+
+func (s *otlpSink) AddSampleWithLabels(key []string, val float32, labels []Label) {
+	histogram, ok := s.getHistogram(otlpMetricName(key))
+	if !ok {
+		return
+	}
+	histogram.Record(context.Background(), float64(val), metric.WithAttributes(labelsToAttributes(labels)...))
+}
+
+func (s *otlpSink) getGauge(name string) (metric.Float64Gauge, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if gauge, ok := s.gauges[name]; ok {
+		return gauge, true
+	}
+	gauge, err := s.meter.Float64Gauge(name)
+	if err != nil {
+		s.log.WithError(err).WithField("name", name).Warn("Failed to create OTLP gauge")
+		return nil, false
+	}
+	s.gauges[name] = gauge
+	return gauge, true
+}
+
+func (s *otlpSink) getCounter(name string) (metric.Float64Counter, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if counter, ok := s.counters[name]; ok {
+		return counter, true
+	}
+	counter, err := s.meter.Float64Counter(name)
+	if err != nil {
+		s.log.WithError(err).WithField("name", name).Warn("Failed to create OTLP counter")
+		return nil, false
+	}
+	s.counters[name] = counter
+	return counter, true
+}
+
+func (s *otlpSink) getHistogram(name string) (metric.Float64Histogram, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if histogram, ok := s.histograms[name]; ok {
+		return histogram, true
+	}
+	histogram, err := s.meter.Float64Histogram(name)
+	if err != nil {
+		s.log.WithError(err).WithField("name", name).Warn("Failed to create OTLP histogram")
+		return nil, false
+	}
+	s.histograms[name] = histogram
+	return histogram, true
+}
+
+// otlpMetricName flattens the key the same way the Prometheus sink does so
+// that metric names are consistent across sinks. Characters that are not
+// allowed in OpenTelemetry instrument names are replaced with underscores.
+func otlpMetricName(key []string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, strings.Join(key, "_"))
+}
+
+func labelsToAttributes(labels []Label) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(labels))
+	for _, l := range labels {
+		attrs = append(attrs, attribute.String(l.Name, l.Value))
+	}
+	return attrs
+}
+
+var _ Sink = (*otlpSink)(nil)
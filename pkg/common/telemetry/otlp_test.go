@@ -0,0 +1,419 @@
+package telemetry
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"github.com/spiffe/spire/test/testca"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	collectormetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestOTLPRunnerNotConfigured(t *testing.T) {
+	config := testOTLPConfig()
+	config.FileConfig.OTLP = nil
+
+	runner, err := newOTLPRunner(config)
+	require.NoError(t, err)
+	assert.False(t, runner.isConfigured())
+	assert.Empty(t, runner.sinks())
+	assert.NoError(t, runner.run(context.Background()))
+}
+
+func TestOTLPRunnerConfigValidation(t *testing.T) {
+	certFile, keyFile := createTestCertAndKey(t)
+
+	tests := []struct {
+		name             string
+		setupConfig      func(*MetricsConfig)
+		errorMsgContains string
+	}{
+		{
+			name: "unsupported protocol",
+			setupConfig: func(config *MetricsConfig) {
+				config.FileConfig.OTLP.Protocol = "udp"
+			},
+			errorMsgContains: `unsupported OTLP protocol "udp"`,
+		},
+		{
+			name: "invalid push interval",
+			setupConfig: func(config *MetricsConfig) {
+				config.FileConfig.OTLP.PushInterval = "soon"
+			},
+			errorMsgContains: "invalid OTLP push_interval",
+		},
+		{
+			name: "non-positive push interval",
+			setupConfig: func(config *MetricsConfig) {
+				config.FileConfig.OTLP.PushInterval = "0s"
+			},
+			errorMsgContains: "OTLP push_interval must be positive",
+		},
+		{
+			name: "tls with insecure",
+			setupConfig: func(config *MetricsConfig) {
+				config.FileConfig.OTLP.TLS = &OTLPTLSConfig{}
+			},
+			errorMsgContains: "OTLP tls cannot be configured when insecure is enabled",
+		},
+		{
+			name: "use SPIRE SVID with cert file",
+			setupConfig: func(config *MetricsConfig) {
+				config.FileConfig.OTLP.Insecure = false
+				config.FileConfig.OTLP.TLS = &OTLPTLSConfig{
+					UseSPIRESVID: true,
+					CertFile:     certFile,
+					KeyFile:      keyFile,
+				}
+			},
+			errorMsgContains: "cert_file and key_file cannot be configured when use_spire_svid is enabled",
+		},
+		{
+			name: "cert file without key file",
+			setupConfig: func(config *MetricsConfig) {
+				config.FileConfig.OTLP.Insecure = false
+				config.FileConfig.OTLP.TLS = &OTLPTLSConfig{
+					CertFile: certFile,
+				}
+			},
+			errorMsgContains: "cert_file and key_file must be configured together",
+		},
+		{
+			name: "ca file with authorized SPIFFE IDs",
+			setupConfig: func(config *MetricsConfig) {
+				config.FileConfig.OTLP.Insecure = false
+				config.FileConfig.OTLP.TLS = &OTLPTLSConfig{
+					CAFile:              certFile,
+					AuthorizedSPIFFEIDs: []string{"spiffe://example.org/collector"},
+				}
+			},
+			errorMsgContains: "ca_file cannot be configured with authorized_spiffe_ids",
+		},
+		{
+			name: "use SPIRE SVID without SVID access",
+			setupConfig: func(config *MetricsConfig) {
+				config.FileConfig.OTLP.Insecure = false
+				config.FileConfig.OTLP.TLS = &OTLPTLSConfig{
+					UseSPIRESVID: true,
+				}
+			},
+			errorMsgContains: "use_spire_svid requires access to the current SPIRE SVID",
+		},
+		{
+			name: "authorized SPIFFE IDs without bundle access",
+			setupConfig: func(config *MetricsConfig) {
+				config.FileConfig.OTLP.Insecure = false
+				config.FileConfig.OTLP.TLS = &OTLPTLSConfig{
+					AuthorizedSPIFFEIDs: []string{"spiffe://example.org/collector"},
+				}
+			},
+			errorMsgContains: "authorized_spiffe_ids requires access to SPIRE trust bundles",
+		},
+		{
+			name: "invalid authorized SPIFFE ID",
+			setupConfig: func(config *MetricsConfig) {
+				config.FileConfig.OTLP.Insecure = false
+				config.FileConfig.OTLP.TLS = &OTLPTLSConfig{
+					AuthorizedSPIFFEIDs: []string{"not-a-spiffe-id"},
+				}
+				config.GetX509BundleAuthorities = func(spiffeid.TrustDomain) ([]*x509.Certificate, error) {
+					return nil, nil
+				}
+			},
+			errorMsgContains: `invalid authorized SPIFFE ID "not-a-spiffe-id"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testOTLPConfig()
+			tt.setupConfig(config)
+
+			_, err := newOTLPRunner(config)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMsgContains)
+		})
+	}
+}
+
+func TestOTLPExport(t *testing.T) {
+	for _, protocol := range []string{OTLPProtocolGRPC, OTLPProtocolHTTP} {
+		t.Run(protocol, func(t *testing.T) {
+			receiver := newFakeOTLPReceiver()
+
+			config := testOTLPConfig()
+			config.FileConfig.OTLP.Protocol = protocol
+			config.FileConfig.OTLP.Headers = map[string]string{"x-api-key": "secret"}
+			config.FileConfig.OTLP.ResourceAttributes = map[string]string{"deployment.environment": "test"}
+			config.FileConfig.EnableTrustDomainLabel = new(true)
+			config.FileConfig.BlockedPrefixes = []string{"foo.blocked"}
+
+			switch protocol {
+			case OTLPProtocolGRPC:
+				config.FileConfig.OTLP.Endpoint = receiver.serveGRPC(t, nil)
+			case OTLPProtocolHTTP:
+				config.FileConfig.OTLP.Endpoint = receiver.serveHTTP(t)
+			}
+
+			exportMetrics(t, config)
+
+			resourceAttrs := receiver.resourceAttributes()
+			assert.Equal(t, "foo", resourceAttrs["service.name"])
+			assert.Equal(t, "test", resourceAttrs["deployment.environment"])
+			assert.Equal(t, "secret", receiver.header("x-api-key"))
+
+			metrics := receiver.metrics()
+			assert.NotContains(t, metrics, "foo_blocked")
+
+			counter := metrics["foo_counter"]
+			require.NotNil(t, counter, "counter was not exported")
+			require.Len(t, counter.GetSum().GetDataPoints(), 1)
+			point := counter.GetSum().GetDataPoints()[0]
+			assert.Equal(t, 3.0, point.GetAsDouble())
+			attrs := dataPointAttributes(point.GetAttributes())
+			assert.Equal(t, "test_org", attrs[TrustDomain])
+			assert.Equal(t, "value", attrs["label"])
+			assert.Contains(t, attrs, "host")
+
+			gauge := metrics["foo_gauge"]
+			require.NotNil(t, gauge, "gauge was not exported")
+			require.Len(t, gauge.GetGauge().GetDataPoints(), 1)
+			assert.Equal(t, 42.5, gauge.GetGauge().GetDataPoints()[0].GetAsDouble())
+
+			histogram := metrics["foo_sample"]
+			require.NotNil(t, histogram, "histogram was not exported")
+			require.Len(t, histogram.GetHistogram().GetDataPoints(), 1)
+			assert.Equal(t, uint64(2), histogram.GetHistogram().GetDataPoints()[0].GetCount())
+			assert.Equal(t, 30.0, histogram.GetHistogram().GetDataPoints()[0].GetSum())
+		})
+	}
+}
+
+func TestOTLPExportWithSPIRESVID(t *testing.T) {
+	td := spiffeid.RequireTrustDomainFromString("example.org")
+	ca := testca.New(t, td)
+	collectorSVID := ca.CreateX509SVID(spiffeid.RequireFromPath(td, "/collector"))
+	serverSVID := ca.CreateX509SVID(spiffeid.RequireFromPath(td, "/spire/server"))
+
+	// The collector only accepts clients presenting the SPIRE server SVID
+	receiver := newFakeOTLPReceiver()
+	serverTLS := tlsconfig.MTLSServerConfig(collectorSVID, ca.X509Bundle(), tlsconfig.AuthorizeID(serverSVID.ID))
+
+	config := testOTLPConfig()
+	config.FileConfig.OTLP.Insecure = false
+	config.FileConfig.OTLP.Endpoint = receiver.serveGRPC(t, serverTLS)
+	config.FileConfig.OTLP.TLS = &OTLPTLSConfig{
+		UseSPIRESVID:        true,
+		AuthorizedSPIFFEIDs: []string{collectorSVID.ID.String()},
+	}
+	config.GetX509SVID = func() (*x509svid.SVID, error) {
+		return serverSVID, nil
+	}
+	config.GetX509BundleAuthorities = func(spiffeid.TrustDomain) ([]*x509.Certificate, error) {
+		return ca.X509Authorities(), nil
+	}
+
+	exportMetrics(t, config)
+
+	assert.Contains(t, receiver.metrics(), "foo_counter")
+}
+
+func TestOTLPMetricName(t *testing.T) {
+	assert.Equal(t, "spire_server_rpc_svid_v1_SVID_MintX509SVID", otlpMetricName([]string{"spire_server", "rpc", "svid", "v1", "SVID", "MintX509SVID"}))
+	assert.Equal(t, "spire_agent_manager_sync_fetch_entries_updates", otlpMetricName([]string{"spire-agent", "manager.sync", "fetch entries/updates"}))
+}
+
+// exportMetrics emits a set of metrics through the configured OTLP sink and
+// shuts it down, which flushes the metrics to the receiver.
+func exportMetrics(t *testing.T, config *MetricsConfig) {
+	metrics, err := NewMetrics(config)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- metrics.ListenAndServe(ctx)
+	}()
+
+	labels := []Label{{Name: "label", Value: "value"}}
+	metrics.IncrCounterWithLabels([]string{"counter"}, 1, labels)
+	metrics.IncrCounterWithLabels([]string{"counter"}, 2, labels)
+	metrics.IncrCounter([]string{"blocked"}, 1)
+	metrics.SetPrecisionGauge([]string{"gauge"}, 42.5)
+	metrics.AddSample([]string{"sample"}, 10)
+	metrics.AddSample([]string{"sample"}, 20)
+
+	cancel()
+	select {
+	case err := <-errCh:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Minute):
+		t.Fatal("timed out waiting for the OTLP exporter to shut down")
+	}
+}
+
+func testOTLPConfig() *MetricsConfig {
+	l, _ := test.NewNullLogger()
+
+	return &MetricsConfig{
+		Logger:      l,
+		ServiceName: "foo",
+		TrustDomain: "test.org",
+		FileConfig: FileConfig{
+			OTLP: &OTLPConfig{
+				Insecure:     true,
+				PushInterval: "1h",
+			},
+		},
+	}
+}
+
+type fakeOTLPReceiver struct {
+	collectormetricspb.UnimplementedMetricsServiceServer
+
+	mu       sync.Mutex
+	requests []*collectormetricspb.ExportMetricsServiceRequest
+	headers  map[string]string
+}
+
+func newFakeOTLPReceiver() *fakeOTLPReceiver {
+	return &fakeOTLPReceiver{
+		headers: make(map[string]string),
+	}
+}
+
+func (r *fakeOTLPReceiver) serveGRPC(t *testing.T, tlsConfig *tls.Config) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	var opts []grpc.ServerOption
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	server := grpc.NewServer(opts...)
+	collectormetricspb.RegisterMetricsServiceServer(server, r)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func (r *fakeOTLPReceiver) serveHTTP(t *testing.T) string {
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+func (r *fakeOTLPReceiver) Export(ctx context.Context, req *collectormetricspb.ExportMetricsServiceRequest) (*collectormetricspb.ExportMetricsServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	headers := make(map[string]string)
+	for key, values := range md {
+		headers[key] = strings.Join(values, ",")
+	}
+	r.record(req, headers)
+	return &collectormetricspb.ExportMetricsServiceResponse{}, nil
+}
+
+func (r *fakeOTLPReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	exportReq := new(collectormetricspb.ExportMetricsServiceRequest)
+	if err := proto.Unmarshal(body, exportReq); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	headers := make(map[string]string)
+	for key := range req.Header {
+		headers[strings.ToLower(key)] = req.Header.Get(key)
+	}
+	r.record(exportReq, headers)
+
+	resp, err := proto.Marshal(&collectormetricspb.ExportMetricsServiceResponse{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(resp)
+}
+
+func (r *fakeOTLPReceiver) record(req *collectormetricspb.ExportMetricsServiceRequest, headers map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	for key, value := range headers {
+		r.headers[key] = value
+	}
+}
+
+func (r *fakeOTLPReceiver) header(key string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.headers[key]
+}
+
+// metrics returns the most recently exported metric for each metric name.
+func (r *fakeOTLPReceiver) metrics() map[string]*metricspb.Metric {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	metrics := make(map[string]*metricspb.Metric)
+	for _, req := range r.requests {
+		for _, rm := range req.GetResourceMetrics() {
+			for _, sm := range rm.GetScopeMetrics() {
+				for _, m := range sm.GetMetrics() {
+					metrics[m.GetName()] = m
+				}
+			}
+		}
+	}
+	return metrics
+}
+
+func (r *fakeOTLPReceiver) resourceAttributes() map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attrs := make(map[string]string)
+	for _, req := range r.requests {
+		for _, rm := range req.GetResourceMetrics() {
+			for _, kv := range rm.GetResource().GetAttributes() {
+				attrs[kv.GetKey()] = kv.GetValue().GetStringValue()
+			}
+		}
+	}
+	return attrs
+}
+
+func dataPointAttributes(kvs []*commonpb.KeyValue) map[string]string {
+	attrs := make(map[string]string, len(kvs))
+	for _, kv := range kvs {
+		attrs[kv.GetKey()] = kv.GetValue().GetStringValue()
+	}
+	return attrs
+}
@@ -85,4 +85,40 @@ func StartRevokeJWTKeyCall(m telemetry.Metrics) *telemetry.CallCounter {
 	return telemetry.StartCall(m, telemetry.Datastore, telemetry.Bundle, telemetry.JWT, telemetry.Revoke)
 }
 
+// StartTaintWITKeyCall return metric
+// for server's datastore, on tainting a WIT public key.
+func StartTaintWITKeyCall(m telemetry.Metrics) *telemetry.CallCounter {
+	return telemetry.StartCall(m, telemetry.Datastore, telemetry.Bundle, telemetry.WIT, telemetry.Taint)
+}
+
+// StartRevokeWITKeyCall return metric
+// for server's datastore, on revoking a WIT Signing Key from bundle.
+func StartRevokeWITKeyCall(m telemetry.Metrics) *telemetry.CallCounter {
+	return telemetry.StartCall(m, telemetry.Datastore, telemetry.Bundle, telemetry.WIT, telemetry.Revoke)
+}
+
 // End Call Counters
+
+// StartSetQuarantinedBundleCall return metric
+// for server's datastore, on setting a quarantined bundle.
+func StartSetQuarantinedBundleCall(m telemetry.Metrics) *telemetry.CallCounter {
+	return telemetry.StartCall(m, telemetry.Datastore, telemetry.QuarantinedBundle, telemetry.Set)
+}
+
+// StartFetchQuarantinedBundleCall return metric
+// for server's datastore, on fetching a quarantined bundle.
+func StartFetchQuarantinedBundleCall(m telemetry.Metrics) *telemetry.CallCounter {
+	return telemetry.StartCall(m, telemetry.Datastore, telemetry.QuarantinedBundle, telemetry.Fetch)
+}
+
+// StartDeleteQuarantinedBundleCall return metric
+// for server's datastore, on deleting a quarantined bundle.
+func StartDeleteQuarantinedBundleCall(m telemetry.Metrics) *telemetry.CallCounter {
+	return telemetry.StartCall(m, telemetry.Datastore, telemetry.QuarantinedBundle, telemetry.Delete)
+}
+
+// StartReleaseQuarantinedBundleCall return metric
+// for server's datastore, on setting a bundle and releasing its quarantine.
+func StartReleaseQuarantinedBundleCall(m telemetry.Metrics) *telemetry.CallCounter {
+	return telemetry.StartCall(m, telemetry.Datastore, telemetry.QuarantinedBundle, telemetry.Release)
+}
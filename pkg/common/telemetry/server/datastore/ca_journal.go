@@ -16,6 +16,12 @@ func StartFetchCAJournal(m telemetry.Metrics) *telemetry.CallCounter {
 	return telemetry.StartCall(m, telemetry.Datastore, telemetry.CAJournal, telemetry.Fetch)
 }
 
+// StartListCAJournalsCall return metric for server's datastore, on listing CA
+// journals.
+func StartListCAJournalsCall(m telemetry.Metrics) *telemetry.CallCounter {
+	return telemetry.StartCall(m, telemetry.Datastore, telemetry.CAJournal, telemetry.List)
+}
+
 // StartPruneCAJournalsCall return metric for server's datastore, on pruning CA
 // journals.
 func StartPruneCAJournalsCall(m telemetry.Metrics) *telemetry.CallCounter {
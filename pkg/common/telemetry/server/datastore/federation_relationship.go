@@ -34,3 +34,9 @@ func StartListFederationRelationshipsCall(m telemetry.Metrics) *telemetry.CallCo
 func StartUpdateFederationRelationshipCall(m telemetry.Metrics) *telemetry.CallCounter {
 	return telemetry.StartCall(m, telemetry.Datastore, telemetry.FederationRelationship, telemetry.Update)
 }
+
+// StartSetFederationRelationshipOptionsCall return metric
+// for server's datastore, on setting the options of a federation relationship.
+func StartSetFederationRelationshipOptionsCall(m telemetry.Metrics) *telemetry.CallCounter {
+	return telemetry.StartCall(m, telemetry.Datastore, telemetry.FederationRelationshipOptions, telemetry.Set)
+}
@@ -16,6 +16,11 @@ import (
 	"github.com/spiffe/spire/test/fakes/fakemetrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
 	"google.golang.org/grpc/codes"
 )
 
@@ -53,6 +58,10 @@ func TestWithMetrics(t *testing.T) {
 			key:        "datastore.registration_entry.count",
 			methodName: "CountRegistrationEntries",
 		},
+		{
+			key:        "datastore.join_token.consume",
+			methodName: "ConsumeJoinToken",
+		},
 		{
 			key:        "datastore.node.create",
 			methodName: "CreateAttestedNode",
@@ -149,6 +158,10 @@ func TestWithMetrics(t *testing.T) {
 			key:        "datastore.bundle.list",
 			methodName: "ListBundles",
 		},
+		{
+			key:        "datastore.join_token.list",
+			methodName: "ListJoinTokens",
+		},
 		{
 			key:        "datastore.node.selectors.list",
 			methodName: "ListNodeSelectors",
@@ -209,6 +222,14 @@ func TestWithMetrics(t *testing.T) {
 			key:        "datastore.bundle.jwt.taint",
 			methodName: "TaintJWTKey",
 		},
+		{
+			key:        "datastore.bundle.wit.revoke",
+			methodName: "RevokeWITKey",
+		},
+		{
+			key:        "datastore.bundle.wit.taint",
+			methodName: "TaintWITKey",
+		},
 		{
 			key:        "datastore.node.selectors.set",
 			methodName: "SetNodeSelectors",
@@ -225,10 +246,30 @@ func TestWithMetrics(t *testing.T) {
 			key:        "datastore.federation_relationship.update",
 			methodName: "UpdateFederationRelationship",
 		},
+		{
+			key:        "datastore.federation_relationship_options.set",
+			methodName: "SetFederationRelationshipOptions",
+		},
 		{
 			key:        "datastore.registration_entry.update",
 			methodName: "UpdateRegistrationEntry",
 		},
+		{
+			key:        "datastore.quarantined_bundle.set",
+			methodName: "SetQuarantinedBundle",
+		},
+		{
+			key:        "datastore.quarantined_bundle.fetch",
+			methodName: "FetchQuarantinedBundle",
+		},
+		{
+			key:        "datastore.quarantined_bundle.delete",
+			methodName: "DeleteQuarantinedBundle",
+		},
+		{
+			key:        "datastore.quarantined_bundle.release",
+			methodName: "ReleaseQuarantinedBundle",
+		},
 		{
 			key:        "datastore.ca_journal.set",
 			methodName: "SetCAJournal",
@@ -237,6 +278,10 @@ func TestWithMetrics(t *testing.T) {
 			key:        "datastore.ca_journal.fetch",
 			methodName: "FetchCAJournal",
 		},
+		{
+			key:        "datastore.ca_journal.list",
+			methodName: "ListCAJournals",
+		},
 		{
 			key:        "datastore.ca_journal.prune",
 			methodName: "PruneCAJournals",
@@ -312,6 +357,38 @@ func TestWithMetrics(t *testing.T) {
 	}
 }
 
+func TestWithMetricsTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+	})
+
+	ds := &fakeDataStore{}
+	w := WithMetrics(ds, fakemetrics.New())
+
+	// Calls outside of a sampled trace are not traced
+	_, err := w.CountBundles(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, recorder.Ended())
+
+	ctx, parent := telemetry.StartSpan(context.Background(), "parent")
+	_, err = w.CountBundles(ctx)
+	require.NoError(t, err)
+	ds.SetError(errors.New("ohno"))
+	_, err = w.FetchBundle(ctx, "spiffe://example.org")
+	require.Error(t, err)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "datastore.CountBundles", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, otelcodes.Unset, spans[0].Status().Code)
+	assert.Equal(t, "datastore.FetchBundle", spans[1].Name())
+	assert.Equal(t, otelcodes.Error, spans[1].Status().Code)
+}
+
 type fakeDataStore struct {
 	err error
 }
@@ -352,6 +429,10 @@ func (ds *fakeDataStore) ListFederationRelationships(context.Context, *datastore
 	return &datastore.ListFederationRelationshipsResponse{}, ds.err
 }
 
+func (ds *fakeDataStore) ConsumeJoinToken(context.Context, string, time.Time, func(*datastore.JoinToken) error) (*datastore.JoinToken, error) {
+	return &datastore.JoinToken{}, ds.err
+}
+
 func (ds *fakeDataStore) CreateJoinToken(context.Context, *datastore.JoinToken) error {
 	return ds.err
 }
@@ -460,6 +541,10 @@ func (ds *fakeDataStore) PruneBundle(context.Context, string, time.Time) (bool,
 	return false, ds.err
 }
 
+func (ds *fakeDataStore) ListJoinTokens(context.Context, *datastore.ListJoinTokensRequest) (*datastore.ListJoinTokensResponse, error) {
+	return &datastore.ListJoinTokensResponse{}, ds.err
+}
+
 func (ds *fakeDataStore) PruneJoinTokens(context.Context, time.Time) error {
 	return ds.err
 }
@@ -492,6 +577,14 @@ func (ds *fakeDataStore) RevokeJWTKey(context.Context, string, string) (*common.
 	return &common.PublicKey{}, ds.err
 }
 
+func (ds *fakeDataStore) TaintWITKey(context.Context, string, string) (*common.PublicKey, error) {
+	return &common.PublicKey{}, ds.err
+}
+
+func (ds *fakeDataStore) RevokeWITKey(context.Context, string, string) (*common.PublicKey, error) {
+	return &common.PublicKey{}, ds.err
+}
+
 func (ds *fakeDataStore) SetNodeSelectors(context.Context, string, []*common.Selector) error {
 	return ds.err
 }
@@ -512,10 +605,34 @@ func (ds *fakeDataStore) UpdateFederationRelationship(context.Context, *datastor
 	return &datastore.FederationRelationship{}, ds.err
 }
 
+func (ds *fakeDataStore) SetFederationRelationshipOptions(context.Context, spiffeid.TrustDomain, *common.FederationRelationshipOptions) (*datastore.FederationRelationship, error) {
+	return &datastore.FederationRelationship{}, ds.err
+}
+
+func (ds *fakeDataStore) SetQuarantinedBundle(context.Context, *common.QuarantinedBundle) (*common.QuarantinedBundle, error) {
+	return &common.QuarantinedBundle{}, ds.err
+}
+
+func (ds *fakeDataStore) FetchQuarantinedBundle(context.Context, string) (*common.QuarantinedBundle, error) {
+	return &common.QuarantinedBundle{}, ds.err
+}
+
+func (ds *fakeDataStore) DeleteQuarantinedBundle(context.Context, string) error {
+	return ds.err
+}
+
+func (ds *fakeDataStore) ReleaseQuarantinedBundle(context.Context, *common.Bundle) (*common.Bundle, error) {
+	return &common.Bundle{}, ds.err
+}
+
 func (ds *fakeDataStore) SetCAJournal(context.Context, *datastore.CAJournal) (*datastore.CAJournal, error) {
 	return &datastore.CAJournal{}, ds.err
 }
 
+func (ds *fakeDataStore) ListCAJournals(context.Context, *datastore.ListCAJournalsRequest) (*datastore.ListCAJournalsResponse, error) {
+	return &datastore.ListCAJournalsResponse{}, ds.err
+}
+
 func (ds *fakeDataStore) FetchCAJournal(context.Context, string) (*datastore.CAJournal, error) {
 	return &datastore.CAJournal{}, ds.err
 }
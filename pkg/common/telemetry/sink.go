@@ -10,6 +10,7 @@ var sinkRunnerFactories = []sinkRunnerFactory{
 	newPrometheusRunner,
 	newStatsdRunner,
 	newM3Runner,
+	newOTLPRunner,
 }
 
 type sinkRunnerFactory func(*MetricsConfig) (sinkRunner, error)
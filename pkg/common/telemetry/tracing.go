@@ -0,0 +1,273 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// TracingExporterOTLPGRPC exports spans using OTLP over gRPC
+	TracingExporterOTLPGRPC = "otlp_grpc"
+
+	// TracingExporterOTLPHTTP exports spans using OTLP over HTTP
+	TracingExporterOTLPHTTP = "otlp_http"
+
+	instrumentationName  = "github.com/spiffe/spire"
+	tracingShutdownGrace = 5 * time.Second
+)
+
+// Tracing owns the OpenTelemetry tracer provider configured for the process.
+// Spans are created through the global OpenTelemetry API (see StartSpan), so
+// when tracing is not configured every span is a cheap no-op.
+type Tracing struct {
+	provider *sdktrace.TracerProvider
+}
+
+// NewTracing configures OpenTelemetry tracing from the telemetry
+// configuration. If tracing is not configured (tc is nil), the returned
+// Tracing does nothing. Otherwise the configured tracer provider and W3C
+// trace context propagator are installed globally.
+func NewTracing(ctx context.Context, tc *TracingConfig, log logrus.FieldLogger, serviceName, trustDomain string) (*Tracing, error) {
+	if tc == nil {
+		return &Tracing{}, nil
+	}
+
+	ratio := 1.0
+	if tc.SampleRatio != nil {
+		ratio = *tc.SampleRatio
+	}
+	if ratio < 0 || ratio > 1 {
+		return nil, fmt.Errorf("tracing sample_ratio must be between 0 and 1, got %v", ratio)
+	}
+
+	exporterName := tc.Exporter
+	if exporterName == "" {
+		exporterName = TracingExporterOTLPGRPC
+	}
+	exporter, err := newSpanExporter(ctx, exporterName, tc)
+	if err != nil {
+		return nil, err
+	}
+
+	res := resource.NewSchemaless(
+		semconv.ServiceName(serviceName),
+		attribute.String("spiffe.trust_domain", trustDomain),
+	)
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(newSampler(ratio)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	log.WithFields(logrus.Fields{
+		"exporter":     exporterName,
+		"endpoint":     tc.Endpoint,
+		"sample_ratio": ratio,
+	}).Info("Tracing enabled")
+
+	return &Tracing{provider: provider}, nil
+}
+
+// Run blocks until the context is done and then flushes and shuts down the
+// tracer provider.
+func (t *Tracing) Run(ctx context.Context) error {
+	if t.provider == nil {
+		return nil
+	}
+
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), tracingShutdownGrace)
+	defer cancel()
+	return t.provider.Shutdown(shutdownCtx)
+}
+
+// newSampler returns a sampler that samples root spans by the given ratio and
+// follows the decision of local parents. Spans continuing a trace propagated
+// by a remote caller are sampled by the ratio alone: the caller might not be
+// authenticated yet, so neither its sampled flag nor its trace ID, which the
+// trace ID ratio sampler decides on, can be trusted to bypass the ratio.
+func newSampler(ratio float64) sdktrace.Sampler {
+	remote := remoteParentSampler{ratio: ratio}
+	return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio),
+		sdktrace.WithRemoteParentSampled(remote),
+		sdktrace.WithRemoteParentNotSampled(remote),
+	)
+}
+
+// remoteParentSampler samples spans with a remote parent by a random draw
+// against the ratio, regardless of the parent trace ID and flags.
+type remoteParentSampler struct {
+	ratio float64
+}
+
+func (s remoteParentSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	decision := sdktrace.Drop
+	if s.ratio >= 1 || rand.Float64() < s.ratio { //nolint:gosec // sampling does not need a secure source
+		decision = sdktrace.RecordAndSample
+	}
+	return sdktrace.SamplingResult{
+		Decision:   decision,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+func (s remoteParentSampler) Description() string {
+	return fmt.Sprintf("RemoteParentSampler{%g}", s.ratio)
+}
+
+func newSpanExporter(ctx context.Context, exporterName string, tc *TracingConfig) (sdktrace.SpanExporter, error) {
+	switch exporterName {
+	case TracingExporterOTLPGRPC:
+		var opts []otlptracegrpc.Option
+		if tc.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(tc.Endpoint))
+		}
+		if tc.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		if len(tc.Headers) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(tc.Headers))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case TracingExporterOTLPHTTP:
+		var opts []otlptracehttp.Option
+		if tc.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(tc.Endpoint))
+		}
+		if tc.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(tc.Headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(tc.Headers))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported tracing exporter %q", exporterName)
+	}
+}
+
+// StartSpan starts a span using the globally configured tracer provider. It is
+// intended to be paired with a deferred EndSpan and a named error value:
+//
+//	func Foo(ctx context.Context) (err error) {
+//	    ctx, span := StartSpan(ctx, "foo")
+//	    defer EndSpan(span, &err)
+//	}
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan ends the span, recording the error, if any, along with its gRPC
+// status code.
+func EndSpan(span trace.Span, errp *error) {
+	if errp != nil && *errp != nil {
+		err := *errp
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		if st, ok := status.FromError(err); ok {
+			span.SetAttributes(attribute.String(StatusCode, st.Code().String()))
+		}
+	}
+	span.End()
+}
+
+// IsTracing returns true if the context carries a span that is being recorded.
+func IsTracing(ctx context.Context) bool {
+	return trace.SpanFromContext(ctx).IsRecording()
+}
+
+// WithTraceFields returns a logger with the trace and span IDs of the span
+// carried by the context, if any.
+func WithTraceFields(ctx context.Context, log logrus.FieldLogger) logrus.FieldLogger {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return log
+	}
+	return log.WithFields(logrus.Fields{
+		TraceID: spanContext.TraceID().String(),
+		SpanID:  spanContext.SpanID().String(),
+	})
+}
+
+// ExtractTraceContext returns a context carrying the remote span context
+// propagated in the incoming gRPC metadata, if any.
+func ExtractTraceContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+}
+
+// InjectTraceContext returns a context whose outgoing gRPC metadata carries
+// the span context of the current span, if any.
+func InjectTraceContext(ctx context.Context) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// TracingUnaryClientInterceptor propagates the trace context on outgoing
+// unary RPCs.
+func TracingUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(InjectTraceContext(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// TracingStreamClientInterceptor propagates the trace context on outgoing
+// streaming RPCs.
+func TracingStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(InjectTraceContext(ctx), desc, cc, method, opts...)
+	}
+}
+
+// metadataCarrier adapts gRPC metadata to the OpenTelemetry TextMapCarrier
+// interface.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+var _ propagation.TextMapCarrier = metadataCarrier(nil)
@@ -0,0 +1,197 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewTracingNotConfigured(t *testing.T) {
+	log, _ := test.NewNullLogger()
+	tracing, err := NewTracing(context.Background(), nil, log, SpireServer, "example.org")
+	require.NoError(t, err)
+
+	// Run returns immediately when tracing is not configured
+	require.NoError(t, tracing.Run(context.Background()))
+}
+
+func TestNewTracingValidation(t *testing.T) {
+	log, _ := test.NewNullLogger()
+	ratio := 1.5
+	_, err := NewTracing(context.Background(), &TracingConfig{SampleRatio: &ratio}, log, SpireServer, "example.org")
+	require.EqualError(t, err, "tracing sample_ratio must be between 0 and 1, got 1.5")
+
+	_, err = NewTracing(context.Background(), &TracingConfig{Exporter: "zipkin"}, log, SpireServer, "example.org")
+	require.EqualError(t, err, `unsupported tracing exporter "zipkin"`)
+}
+
+func TestNewTracing(t *testing.T) {
+	restoreGlobals(t)
+
+	for _, exporter := range []string{"", TracingExporterOTLPGRPC, TracingExporterOTLPHTTP} {
+		t.Run(exporter, func(t *testing.T) {
+			log, hook := test.NewNullLogger()
+			ratio := 0.5
+			tracing, err := NewTracing(context.Background(), &TracingConfig{
+				Exporter:    exporter,
+				Endpoint:    "localhost:4317",
+				Insecure:    true,
+				Headers:     map[string]string{"key": "value"},
+				SampleRatio: &ratio,
+			}, log, SpireAgent, "example.org")
+			require.NoError(t, err)
+
+			expectedExporter := exporter
+			if expectedExporter == "" {
+				expectedExporter = TracingExporterOTLPGRPC
+			}
+			entry := hook.LastEntry()
+			require.NotNil(t, entry)
+			assert.Equal(t, "Tracing enabled", entry.Message)
+			assert.Equal(t, expectedExporter, entry.Data["exporter"])
+			assert.Equal(t, 0.5, entry.Data["sample_ratio"])
+
+			// Run flushes and shuts down the provider once the context is done
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			require.NoError(t, tracing.Run(ctx))
+		})
+	}
+}
+
+func TestSpans(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	ctx, span := StartSpan(context.Background(), "ok")
+	require.True(t, IsTracing(ctx))
+	var err error
+	EndSpan(span, &err)
+
+	_, span = StartSpan(context.Background(), "failed")
+	err = status.Error(codes.NotFound, "oh no")
+	EndSpan(span, &err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "ok", spans[0].Name())
+	assert.Equal(t, otelcodes.Unset, spans[0].Status().Code)
+	assert.Equal(t, "failed", spans[1].Name())
+	assert.Equal(t, otelcodes.Error, spans[1].Status().Code)
+	assert.Equal(t, "rpc error: code = NotFound desc = oh no", spans[1].Status().Description)
+	assert.Contains(t, spans[1].Attributes(), attribute.String(StatusCode, "NotFound"))
+
+	assert.False(t, IsTracing(context.Background()))
+}
+
+func TestWithTraceFields(t *testing.T) {
+	setupRecorder(t)
+	log, hook := test.NewNullLogger()
+
+	// No span in context
+	WithTraceFields(context.Background(), log).Info("no span")
+	assert.Empty(t, hook.LastEntry().Data)
+
+	ctx, span := StartSpan(context.Background(), "span")
+	defer span.End()
+	WithTraceFields(ctx, log).Info("span")
+	assert.Equal(t, logrus.Fields{
+		TraceID: span.SpanContext().TraceID().String(),
+		SpanID:  span.SpanContext().SpanID().String(),
+	}, hook.LastEntry().Data)
+}
+
+func TestTraceContextPropagation(t *testing.T) {
+	setupRecorder(t)
+
+	ctx, span := StartSpan(context.Background(), "client")
+	defer span.End()
+	ctx = metadata.AppendToOutgoingContext(ctx, "existing", "value")
+	ctx = InjectTraceContext(ctx)
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, []string{"value"}, md.Get("existing"))
+	assert.Len(t, md.Get("traceparent"), 1)
+
+	serverCtx := ExtractTraceContext(metadata.NewIncomingContext(context.Background(), md))
+	remote := trace.SpanContextFromContext(serverCtx)
+	assert.True(t, remote.IsRemote())
+	assert.Equal(t, span.SpanContext().TraceID(), remote.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), remote.SpanID())
+}
+
+func TestSampler(t *testing.T) {
+	remoteParent := func(sampled bool) context.Context {
+		var flags trace.TraceFlags
+		if sampled {
+			flags = trace.FlagsSampled
+		}
+		return trace.ContextWithRemoteSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    trace.TraceID{1},
+			SpanID:     trace.SpanID{1},
+			TraceFlags: flags,
+			Remote:     true,
+		}))
+	}
+	shouldSample := func(sampler sdktrace.Sampler, ctx context.Context) bool {
+		result := sampler.ShouldSample(sdktrace.SamplingParameters{
+			ParentContext: ctx,
+			TraceID:       trace.SpanContextFromContext(ctx).TraceID(),
+			Name:          "span",
+		})
+		return result.Decision == sdktrace.RecordAndSample
+	}
+
+	never := newSampler(0)
+	always := newSampler(1)
+
+	// Root spans are sampled by the ratio
+	assert.False(t, shouldSample(never, context.Background()))
+	assert.True(t, shouldSample(always, context.Background()))
+
+	// Remote parents cannot force sampling past the ratio...
+	assert.False(t, shouldSample(never, remoteParent(true)))
+	// ...nor suppress it
+	assert.True(t, shouldSample(always, remoteParent(false)))
+
+	// Local parents are followed
+	localParent := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+	}))
+	assert.True(t, shouldSample(never, localParent))
+}
+
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	restoreGlobals(t)
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return recorder
+}
+
+// restoreGlobals resets the global tracer provider and propagator once the
+// test finishes. The original globals cannot be restored since they delegate
+// to the first provider that is set.
+func restoreGlobals(t *testing.T) {
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	agentv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/agent/v1"
 	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"github.com/spiffe/spire/pkg/common/agentpathtemplate"
 	commonapi "github.com/spiffe/spire/pkg/common/api"
 	"github.com/spiffe/spire/pkg/common/errorutil"
 	"github.com/spiffe/spire/pkg/common/idutil"
@@ -22,15 +23,18 @@ import (
 	"github.com/spiffe/spire/pkg/common/x509util"
 	"github.com/spiffe/spire/pkg/server/api"
 	"github.com/spiffe/spire/pkg/server/api/rpccontext"
+	"github.com/spiffe/spire/pkg/server/authpolicy"
 	"github.com/spiffe/spire/pkg/server/ca"
 	"github.com/spiffe/spire/pkg/server/catalog"
 	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/pkg/server/plugin/credentialcomposer"
 	"github.com/spiffe/spire/pkg/server/plugin/nodeattestor"
 	"github.com/spiffe/spire/proto/spire/common"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -111,6 +115,14 @@ func (s *Service) CountAgents(ctx context.Context, req *agentv1.CountAgentsReque
 		}
 	}
 
+	// Agents outside of the caller scopes are not counted
+	prefixes, inScopes := agentIDPrefixes(rpccontext.CallerScopes(ctx))
+	if !inScopes {
+		rpccontext.AuditRPC(ctx)
+		return &agentv1.CountAgentsResponse{}, nil
+	}
+	countReq.BySpiffeIDPrefixes = prefixes
+
 	count, err := s.ds.CountAttestedNodes(ctx, countReq)
 	if err != nil {
 		log := rpccontext.Logger(ctx)
@@ -170,9 +182,17 @@ func (s *Service) ListAgents(ctx context.Context, req *agentv1.ListAgentsRequest
 		}
 	}
 
-	dsResp, err := s.ds.ListAttestedNodes(ctx, listReq)
-	if err != nil {
-		return nil, commonapi.MakeErr(log, codes.Internal, "failed to list agents", err)
+	// Agents outside of the caller scopes are filtered out by the query
+	prefixes, inScopes := agentIDPrefixes(rpccontext.CallerScopes(ctx))
+	listReq.BySpiffeIDPrefixes = prefixes
+
+	dsResp := &datastore.ListAttestedNodesResponse{}
+	if inScopes {
+		var err error
+		dsResp, err = s.ds.ListAttestedNodes(ctx, listReq)
+		if err != nil {
+			return nil, commonapi.MakeErr(log, codes.Internal, "failed to list agents", err)
+		}
 	}
 
 	resp := &agentv1.ListAgentsResponse{}
@@ -208,6 +228,10 @@ func (s *Service) GetAgent(ctx context.Context, req *agentv1.GetAgentRequest) (*
 	rpccontext.AddRPCAuditFields(ctx, logrus.Fields{telemetry.SPIFFEID: agentID.String()})
 
 	log = log.WithField(telemetry.SPIFFEID, agentID.String())
+	if !rpccontext.CallerScopes(ctx).AllowsID(agentID) {
+		return nil, commonapi.MakeErr(log, codes.PermissionDenied, "agent is outside of the caller scopes", nil)
+	}
+
 	attestedNode, err := s.ds.FetchAttestedNode(ctx, agentID.String())
 	if err != nil {
 		return nil, commonapi.MakeErr(log, codes.Internal, "failed to fetch agent", err)
@@ -243,6 +267,9 @@ func (s *Service) DeleteAgent(ctx context.Context, req *agentv1.DeleteAgentReque
 	rpccontext.AddRPCAuditFields(ctx, logrus.Fields{telemetry.SPIFFEID: id.String()})
 
 	log = log.WithField(telemetry.SPIFFEID, id.String())
+	if !rpccontext.CallerScopes(ctx).AllowsID(id) {
+		return nil, commonapi.MakeErr(log, codes.PermissionDenied, "agent is outside of the caller scopes", nil)
+	}
 
 	_, err = s.ds.DeleteAttestedNode(ctx, id.String())
 	switch status.Code(err) {
@@ -268,6 +295,9 @@ func (s *Service) BanAgent(ctx context.Context, req *agentv1.BanAgentRequest) (*
 	rpccontext.AddRPCAuditFields(ctx, logrus.Fields{telemetry.SPIFFEID: id.String()})
 
 	log = log.WithField(telemetry.SPIFFEID, id.String())
+	if !rpccontext.CallerScopes(ctx).AllowsID(id) {
+		return nil, commonapi.MakeErr(log, codes.PermissionDenied, "agent is outside of the caller scopes", nil)
+	}
 
 	// The agent "Banned" state is pointed out by setting its
 	// serial numbers (current and new) to empty strings.
@@ -367,14 +397,17 @@ func (s *Service) AttestAgent(stream agentv1.Agent_AttestAgentServer) error {
 		return commonapi.MakeErr(log, codes.PermissionDenied, "failed to attest: agent is banned", nil)
 	}
 
+	// dedupe node selectors
+	attestResult.Selectors = selector.Dedupe(attestResult.Selectors)
+
 	// parse and sign CSR
-	svid, err := s.signSvid(ctx, agentID, params.Params.Csr, log)
+	svid, err := s.signSvid(ctx, agentID, params.Params.Csr, api.ProtoFromSelectors(attestResult.Selectors), log)
 	if err != nil {
 		return err
 	}
 
-	// dedupe and store node selectors
-	err = s.ds.SetNodeSelectors(ctx, agentID.String(), selector.Dedupe(attestResult.Selectors))
+	// store node selectors
+	err = s.ds.SetNodeSelectors(ctx, agentID.String(), attestResult.Selectors)
 	if err != nil {
 		return commonapi.MakeErr(log, codes.Internal, "failed to update selectors", err)
 	}
@@ -387,6 +420,7 @@ func (s *Service) AttestAgent(stream agentv1.Agent_AttestAgentServer) error {
 			CertNotAfter:        svid[0].NotAfter.Unix(),
 			CertSerialNumber:    svid[0].SerialNumber.String(),
 			CanReattest:         attestResult.CanReattest,
+			LastSeenAt:          s.clk.Now().Unix(),
 		}
 		if _, err := s.ds.CreateAttestedNode(ctx, node); err != nil {
 			return commonapi.MakeErr(log, codes.Internal, "failed to create attested agent", err)
@@ -397,8 +431,11 @@ func (s *Service) AttestAgent(stream agentv1.Agent_AttestAgentServer) error {
 			CertNotAfter:     svid[0].NotAfter.Unix(),
 			CertSerialNumber: svid[0].SerialNumber.String(),
 			CanReattest:      attestResult.CanReattest,
+			LastSeenAt:       s.clk.Now().Unix(),
 		}
-		if _, err := s.ds.UpdateAttestedNode(ctx, node, api.UpdateAttestedNodeCertificateMask); err != nil {
+		mask := proto.Clone(api.UpdateAttestedNodeCertificateMask).(*common.AttestedNodeMask)
+		mask.LastSeenAt = true
+		if _, err := s.ds.UpdateAttestedNode(ctx, node, mask); err != nil {
 			return commonapi.MakeErr(log, codes.Internal, "failed to update attested agent", err)
 		}
 	}
@@ -458,7 +495,12 @@ func (s *Service) RenewAgent(ctx context.Context, req *agentv1.RenewAgentRequest
 		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "missing CSR", nil)
 	}
 
-	agentSVID, err := s.signSvid(ctx, callerID, req.Params.Csr, log)
+	selectors, err := s.getSelectorsFromAgentID(ctx, callerID.String())
+	if err != nil {
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to get agent selectors", err)
+	}
+
+	agentSVID, err := s.signSvid(ctx, callerID, req.Params.Csr, selectors, log)
 	if err != nil {
 		return nil, err
 	}
@@ -467,10 +509,12 @@ func (s *Service) RenewAgent(ctx context.Context, req *agentv1.RenewAgentRequest
 		SpiffeId:            callerID.String(),
 		NewCertNotAfter:     agentSVID[0].NotAfter.Unix(),
 		NewCertSerialNumber: agentSVID[0].SerialNumber.String(),
+		LastSeenAt:          s.clk.Now().Unix(),
 	}
 	mask := &common.AttestedNodeMask{
 		NewCertNotAfter:     true,
 		NewCertSerialNumber: true,
+		LastSeenAt:          true,
 	}
 	if err := s.updateAttestedNode(ctx, update, mask, log); err != nil {
 		return nil, err
@@ -507,6 +551,15 @@ func (s *Service) PostStatus(ctx context.Context, req *agentv1.PostStatusRequest
 		telemetry.SPIFFEID: callerID.String(),
 	})
 
+	// Every status post refreshes the last time the agent was seen
+	update := &common.AttestedNode{
+		SpiffeId:   callerID.String(),
+		LastSeenAt: s.clk.Now().Unix(),
+	}
+	mask := &common.AttestedNodeMask{
+		LastSeenAt: true,
+	}
+
 	if agentVersion != "" {
 		if len(agentVersion) > 255 {
 			return nil, commonapi.MakeErr(log, codes.InvalidArgument, "agent version is too long (max 255 characters)", nil)
@@ -515,20 +568,17 @@ func (s *Service) PostStatus(ctx context.Context, req *agentv1.PostStatusRequest
 		rpccontext.AddRPCAuditFields(ctx, logrus.Fields{
 			telemetry.AgentVersion: agentVersion,
 		})
+		log = log.WithField(telemetry.AgentVersion, agentVersion)
 
-		update := &common.AttestedNode{
-			SpiffeId:     callerID.String(),
-			AgentVersion: agentVersion,
-		}
-		mask := &common.AttestedNodeMask{
-			AgentVersion: true,
-		}
-		if err := s.updateAttestedNode(ctx, update, mask, log); err != nil {
-			return nil, err
-		}
-		log.WithField(telemetry.AgentVersion, agentVersion).Debug("Agent status updated")
+		update.AgentVersion = agentVersion
+		mask.AgentVersion = true
 	}
 
+	if err := s.updateAttestedNode(ctx, update, mask, log); err != nil {
+		return nil, err
+	}
+	log.Debug("Agent status updated")
+
 	rpccontext.AuditRPC(ctx)
 	return &agentv1.PostStatusResponse{}, nil
 }
@@ -562,6 +612,17 @@ func (s *Service) CreateJoinToken(ctx context.Context, req *agentv1.CreateJoinTo
 		log.WithField(telemetry.SPIFFEID, agentID.String())
 	}
 
+	// Tokens without an agent ID attest agents with an ID derived from the
+	// token, which cannot be within the caller scopes
+	if scopes := rpccontext.CallerScopes(ctx); len(scopes) > 0 {
+		if req.AgentId == nil {
+			return nil, commonapi.MakeErr(log, codes.PermissionDenied, "scoped callers must provide an agent ID", nil)
+		}
+		if !scopes.AllowsID(agentID) {
+			return nil, commonapi.MakeErr(log, codes.PermissionDenied, "agent ID is outside of the caller scopes", nil)
+		}
+	}
+
 	// Generate a token if one wasn't specified
 	if req.Token == "" {
 		u, err := uuid.NewV4()
@@ -624,7 +685,7 @@ func (s *Service) updateAttestedNode(ctx context.Context, node *common.AttestedN
 	}
 }
 
-func (s *Service) signSvid(ctx context.Context, agentID spiffeid.ID, csr []byte, log logrus.FieldLogger) ([]*x509.Certificate, error) {
+func (s *Service) signSvid(ctx context.Context, agentID spiffeid.ID, csr []byte, selectors []*types.Selector, log logrus.FieldLogger) ([]*x509.Certificate, error) {
 	parsedCsr, err := x509.ParseCertificateRequest(csr)
 	if err != nil {
 		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "failed to parse CSR", err)
@@ -634,6 +695,10 @@ func (s *Service) signSvid(ctx context.Context, agentID spiffeid.ID, csr []byte,
 	x509Svid, err := s.ca.SignAgentX509SVID(ctx, ca.AgentX509SVIDParams{
 		SPIFFEID:  agentID,
 		PublicKey: parsedCsr.PublicKey,
+		SVIDContext: credentialcomposer.SVIDContext{
+			AgentID:        agentID,
+			AgentSelectors: selectors,
+		},
 	})
 	if err != nil {
 		return nil, commonapi.MakeErr(log, codes.Internal, "failed to sign X509 SVID", err)
@@ -654,30 +719,55 @@ func (s *Service) getSelectorsFromAgentID(ctx context.Context, agentID string) (
 func (s *Service) attestJoinToken(ctx context.Context, token string) (*nodeattestor.AttestResult, error) {
 	log := rpccontext.Logger(ctx).WithField(telemetry.NodeAttestorType, "join_token")
 
-	joinToken, err := s.ds.FetchJoinToken(ctx, token)
+	// The agent ID is checked while the token is being consumed, so a
+	// rejected attestation does not use the token up.
+	var agentID spiffeid.ID
+	var agentIDErr error
+	joinToken, err := s.ds.ConsumeJoinToken(ctx, token, s.clk.Now(), func(joinToken *datastore.JoinToken) error {
+		agentID, agentIDErr = s.makeJoinTokenAgentID(ctx, log, token, joinToken)
+		return agentIDErr
+	})
 	switch {
+	case agentIDErr != nil:
+		return nil, agentIDErr
 	case err != nil:
-		return nil, commonapi.MakeErr(log, codes.Internal, "failed to fetch join token", err)
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to consume join token", err)
 	case joinToken == nil:
-		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "failed to attest: join token does not exist or has already been used", nil)
+		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "failed to attest: join token does not exist, has expired or has already been used", nil)
 	}
 
-	err = s.ds.DeleteJoinToken(ctx, token)
-	switch {
-	case err != nil:
-		return nil, commonapi.MakeErr(log, codes.Internal, "failed to delete join token", err)
-	case joinToken.Expiry.Before(s.clk.Now()):
-		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "join token expired", nil)
+	return &nodeattestor.AttestResult{
+		AgentID:   agentID.String(),
+		Selectors: joinToken.Selectors,
+	}, nil
+}
+
+// makeJoinTokenAgentID returns the agent ID for a use of the given join token.
+func (s *Service) makeJoinTokenAgentID(ctx context.Context, log logrus.FieldLogger, token string, joinToken *datastore.JoinToken) (spiffeid.ID, error) {
+	if !joinToken.IsMultiUse() && joinToken.AgentPathTemplate == "" {
+		agentID, err := joinTokenID(s.td, token)
+		if err != nil {
+			return spiffeid.ID{}, commonapi.MakeErr(log, codes.Internal, "failed to create join token ID", err)
+		}
+		return agentID, nil
 	}
 
-	agentID, err := joinTokenID(s.td, token)
+	agentID, err := makeJoinTokenAgentID(s.td, joinToken)
 	if err != nil {
-		return nil, commonapi.MakeErr(log, codes.Internal, "failed to create join token ID", err)
+		return spiffeid.ID{}, commonapi.MakeErr(log, codes.Internal, "failed to create join token agent ID", err)
 	}
 
-	return &nodeattestor.AttestResult{
-		AgentID: agentID.String(),
-	}, nil
+	// Agents attested with a token that can be used more than once must not
+	// take over the identity of an agent already attested with it
+	attestedNode, err := s.ds.FetchAttestedNode(ctx, agentID.String())
+	switch {
+	case err != nil:
+		return spiffeid.ID{}, commonapi.MakeErr(log, codes.Internal, "failed to fetch agent", err)
+	case attestedNode != nil:
+		return spiffeid.ID{}, commonapi.MakeErr(log.WithField(telemetry.AgentID, agentID), codes.PermissionDenied, "failed to attest: the agent ID produced by the join token is already in use", nil)
+	}
+
+	return agentID, nil
 }
 
 func (s *Service) attestChallengeResponse(ctx context.Context, agentStream agentv1.Agent_AttestAgentServer, params *agentv1.AttestAgentRequest_Params) (*nodeattestor.AttestResult, error) {
@@ -713,6 +803,20 @@ func (s *Service) attestChallengeResponse(ctx context.Context, agentStream agent
 	return result, nil
 }
 
+// agentIDPrefixes returns the SPIFFE ID prefixes matching the agents within
+// the given caller scopes, or nil if the scopes do not restrict the caller.
+// It returns false if no agent can be within the scopes.
+func agentIDPrefixes(scopes authpolicy.Scopes) ([]string, bool) {
+	if len(scopes) == 0 {
+		return nil, true
+	}
+	var prefixes []string
+	for _, prefix := range scopes.IDPrefixes() {
+		prefixes = append(prefixes, prefix.String())
+	}
+	return prefixes, len(prefixes) > 0
+}
+
 func applyMask(a *types.Agent, mask *types.AgentMask) {
 	if mask == nil {
 		return
@@ -831,3 +935,46 @@ func fieldsFromCountAgentsRequest(filter *agentv1.CountAgentsRequest_Filter) log
 func joinTokenID(td spiffeid.TrustDomain, token string) (spiffeid.ID, error) {
 	return spiffeid.FromSegments(td, "spire", "agent", "join_token", token)
 }
+
+// defaultJoinTokenAgentPathTemplate is used to build the agent ID of the
+// agents attested with join tokens that can be used more than once. Unlike
+// single-use tokens, the token itself is left out of the agent ID since it
+// can still be used to attest other agents.
+var defaultJoinTokenAgentPathTemplate = agentpathtemplate.MustParse("/{{ .PluginName }}/{{ .UUID }}")
+
+type joinTokenAgentPathTemplateData struct {
+	PluginName  string
+	TrustDomain string
+	UseNumber   int32
+	UUID        string
+}
+
+// makeJoinTokenAgentID creates the agent ID of an agent attested with a join
+// token that has an agent path template or can be used more than once.
+func makeJoinTokenAgentID(td spiffeid.TrustDomain, joinToken *datastore.JoinToken) (spiffeid.ID, error) {
+	agentPathTemplate := defaultJoinTokenAgentPathTemplate
+	if joinToken.AgentPathTemplate != "" {
+		tmpl, err := agentpathtemplate.Parse(joinToken.AgentPathTemplate)
+		if err != nil {
+			return spiffeid.ID{}, fmt.Errorf("failed to parse agent path template: %w", err)
+		}
+		agentPathTemplate = tmpl
+	}
+
+	u, err := uuid.NewV4()
+	if err != nil {
+		return spiffeid.ID{}, fmt.Errorf("failed to generate agent UUID: %w", err)
+	}
+
+	agentPath, err := agentPathTemplate.Execute(joinTokenAgentPathTemplateData{
+		PluginName:  "join_token",
+		TrustDomain: td.Name(),
+		UseNumber:   joinToken.UseCount,
+		UUID:        u.String(),
+	})
+	if err != nil {
+		return spiffeid.ID{}, err
+	}
+
+	return idutil.AgentID(td, agentPath)
+}
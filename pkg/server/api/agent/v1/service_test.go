@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 	agent "github.com/spiffe/spire/pkg/server/api/agent/v1"
 	"github.com/spiffe/spire/pkg/server/api/middleware"
 	"github.com/spiffe/spire/pkg/server/api/rpccontext"
+	"github.com/spiffe/spire/pkg/server/authpolicy"
 	"github.com/spiffe/spire/pkg/server/datastore"
 	"github.com/spiffe/spire/proto/spire/common"
 	"github.com/spiffe/spire/test/clock"
@@ -2050,6 +2052,7 @@ func TestRenewAgent(t *testing.T) {
 			expectedNode := tt.createNode
 			expectedNode.NewCertNotAfter = x509Svid.NotAfter.Unix()
 			expectedNode.NewCertSerialNumber = x509Svid.SerialNumber.String()
+			expectedNode.LastSeenAt = test.clk.Now().Unix()
 			spiretest.AssertProtoEqual(t, expectedNode, updatedNode)
 
 			// No logs expected
@@ -2174,6 +2177,7 @@ func TestPostStatus(t *testing.T) {
 					require.NoError(t, err)
 					require.NotNil(t, node)
 					require.Equal(t, tt.expectVersion, node.AgentVersion)
+					require.Equal(t, test.clk.Now().Unix(), node.LastSeenAt)
 				}
 			})
 		}
@@ -2298,6 +2302,74 @@ func TestCreateJoinToken(t *testing.T) {
 	}
 }
 
+func TestScopedAgents(t *testing.T) {
+	test := setupServiceTest(t, 0, false)
+	defer test.Cleanup()
+	test.callerScopes = authpolicy.Scopes{
+		{SPIFFEIDPrefix: spiffeid.RequireFromString("spiffe://example.org/spire/agent/agent-1")},
+		{SPIFFEIDPrefix: spiffeid.RequireFromString("spiffe://example.org/nodes/team-a")},
+	}
+
+	for _, id := range []string{agent2, agent1} {
+		_, err := test.ds.CreateAttestedNode(ctx, &common.AttestedNode{
+			SpiffeId:            id,
+			AttestationDataType: "t",
+			CertSerialNumber:    "1",
+		})
+		require.NoError(t, err)
+	}
+
+	resp, err := test.client.ListAgents(ctx, &agentv1.ListAgentsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Agents, 1)
+	require.Equal(t, "/spire/agent/agent-1", resp.Agents[0].Id.Path)
+
+	// Agents outside of the scopes do not take room in the pages
+	resp, err = test.client.ListAgents(ctx, &agentv1.ListAgentsRequest{PageSize: 1})
+	require.NoError(t, err)
+	require.Len(t, resp.Agents, 1)
+	require.Equal(t, "/spire/agent/agent-1", resp.Agents[0].Id.Path)
+
+	countResp, err := test.client.CountAgents(ctx, &agentv1.CountAgentsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), countResp.Count)
+
+	agent2ID := &types.SPIFFEID{TrustDomain: td.Name(), Path: "/spire/agent/agent-2"}
+	_, err = test.client.GetAgent(ctx, &agentv1.GetAgentRequest{Id: agent2ID})
+	spiretest.RequireGRPCStatus(t, err, codes.PermissionDenied, "agent is outside of the caller scopes")
+	_, err = test.client.BanAgent(ctx, &agentv1.BanAgentRequest{Id: agent2ID})
+	spiretest.RequireGRPCStatus(t, err, codes.PermissionDenied, "agent is outside of the caller scopes")
+	_, err = test.client.DeleteAgent(ctx, &agentv1.DeleteAgentRequest{Id: agent2ID})
+	spiretest.RequireGRPCStatus(t, err, codes.PermissionDenied, "agent is outside of the caller scopes")
+
+	_, err = test.client.GetAgent(ctx, &agentv1.GetAgentRequest{Id: &types.SPIFFEID{TrustDomain: td.Name(), Path: "/spire/agent/agent-1"}})
+	require.NoError(t, err)
+
+	_, err = test.client.CreateJoinToken(ctx, &agentv1.CreateJoinTokenRequest{Ttl: 1000})
+	spiretest.RequireGRPCStatus(t, err, codes.PermissionDenied, "scoped callers must provide an agent ID")
+	_, err = test.client.CreateJoinToken(ctx, &agentv1.CreateJoinTokenRequest{
+		Ttl:     1000,
+		AgentId: &types.SPIFFEID{TrustDomain: td.Name(), Path: "/nodes/team-b/node"},
+	})
+	spiretest.RequireGRPCStatus(t, err, codes.PermissionDenied, "agent ID is outside of the caller scopes")
+	_, err = test.client.CreateJoinToken(ctx, &agentv1.CreateJoinTokenRequest{
+		Ttl:     1000,
+		AgentId: &types.SPIFFEID{TrustDomain: td.Name(), Path: "/nodes/team-a/node"},
+	})
+	require.NoError(t, err)
+
+	// Scopes restricting the parent ID do not match any agent
+	test.callerScopes = authpolicy.Scopes{
+		{ParentID: spiffeid.RequireFromString("spiffe://example.org/spire/agent/agent-1")},
+	}
+	resp, err = test.client.ListAgents(ctx, &agentv1.ListAgentsRequest{})
+	require.NoError(t, err)
+	require.Empty(t, resp.Agents)
+	countResp, err = test.client.CountAgents(ctx, &agentv1.CountAgentsRequest{})
+	require.NoError(t, err)
+	require.Zero(t, countResp.Count)
+}
+
 func TestCreateJoinTokenWithAgentId(t *testing.T) {
 	test := setupServiceTest(t, 0, false)
 
@@ -2628,11 +2700,11 @@ func TestAttestAgent(t *testing.T) {
 			name:       "join token does not exist",
 			request:    getAttestAgentRequest("join_token", []byte("bad_token"), testCsr),
 			expectCode: codes.InvalidArgument,
-			expectMsg:  "failed to attest: join token does not exist or has already been used",
+			expectMsg:  "failed to attest: join token does not exist, has expired or has already been used",
 			expectLogs: []spiretest.LogEntry{
 				{
 					Level:   logrus.ErrorLevel,
-					Message: "Invalid argument: failed to attest: join token does not exist or has already been used",
+					Message: "Invalid argument: failed to attest: join token does not exist, has expired or has already been used",
 					Data: logrus.Fields{
 						telemetry.NodeAttestorType: "join_token",
 					},
@@ -2644,7 +2716,7 @@ func TestAttestAgent(t *testing.T) {
 						telemetry.Status:           "error",
 						telemetry.Type:             "audit",
 						telemetry.StatusCode:       "InvalidArgument",
-						telemetry.StatusMessage:    "failed to attest: join token does not exist or has already been used",
+						telemetry.StatusMessage:    "failed to attest: join token does not exist, has expired or has already been used",
 						telemetry.NodeAttestorType: "join_token",
 					},
 				},
@@ -2711,11 +2783,11 @@ func TestAttestAgent(t *testing.T) {
 			name:       "attest with join token is expired",
 			request:    getAttestAgentRequest("join_token", []byte("expired_token"), testCsr),
 			expectCode: codes.InvalidArgument,
-			expectMsg:  "join token expired",
+			expectMsg:  "failed to attest: join token does not exist, has expired or has already been used",
 			expectLogs: []spiretest.LogEntry{
 				{
 					Level:   logrus.ErrorLevel,
-					Message: "Invalid argument: join token expired",
+					Message: "Invalid argument: failed to attest: join token does not exist, has expired or has already been used",
 					Data: logrus.Fields{
 						telemetry.NodeAttestorType: "join_token",
 					},
@@ -2727,7 +2799,7 @@ func TestAttestAgent(t *testing.T) {
 						telemetry.Status:           "error",
 						telemetry.Type:             "audit",
 						telemetry.StatusCode:       "InvalidArgument",
-						telemetry.StatusMessage:    "join token expired",
+						telemetry.StatusMessage:    "failed to attest: join token does not exist, has expired or has already been used",
 						telemetry.NodeAttestorType: "join_token",
 					},
 				},
@@ -2739,7 +2811,7 @@ func TestAttestAgent(t *testing.T) {
 			retry:      true,
 			request:    getAttestAgentRequest("join_token", []byte("test_token"), testCsr),
 			expectCode: codes.InvalidArgument,
-			expectMsg:  "failed to attest: join token does not exist or has already been used",
+			expectMsg:  "failed to attest: join token does not exist, has expired or has already been used",
 			expectLogs: []spiretest.LogEntry{
 				{
 					Level:   logrus.InfoLevel,
@@ -2762,7 +2834,7 @@ func TestAttestAgent(t *testing.T) {
 				},
 				{
 					Level:   logrus.ErrorLevel,
-					Message: "Invalid argument: failed to attest: join token does not exist or has already been used",
+					Message: "Invalid argument: failed to attest: join token does not exist, has expired or has already been used",
 					Data: logrus.Fields{
 						telemetry.NodeAttestorType: "join_token",
 					},
@@ -2774,7 +2846,7 @@ func TestAttestAgent(t *testing.T) {
 						telemetry.Status:           "error",
 						telemetry.Type:             "audit",
 						telemetry.StatusCode:       "InvalidArgument",
-						telemetry.StatusMessage:    "failed to attest: join token does not exist or has already been used",
+						telemetry.StatusMessage:    "failed to attest: join token does not exist, has expired or has already been used",
 						telemetry.NodeAttestorType: "join_token",
 					},
 				},
@@ -3010,49 +3082,17 @@ func TestAttestAgent(t *testing.T) {
 		},
 
 		{
-			name:       "ds: fails to fetch join token",
-			request:    getAttestAgentRequest("join_token", []byte("test_token"), testCsr),
-			expectCode: codes.Internal,
-			expectMsg:  "failed to fetch join token",
-			dsError: []error{
-				errors.New("some error"),
-			},
-			expectLogs: []spiretest.LogEntry{
-				{
-					Level:   logrus.ErrorLevel,
-					Message: "Failed to fetch join token",
-					Data: logrus.Fields{
-						telemetry.NodeAttestorType: "join_token",
-						logrus.ErrorKey:            "some error",
-					},
-				},
-				{
-					Level:   logrus.InfoLevel,
-					Message: "API accessed",
-					Data: logrus.Fields{
-						telemetry.Status:           "error",
-						telemetry.Type:             "audit",
-						telemetry.StatusCode:       "Internal",
-						telemetry.StatusMessage:    "failed to fetch join token: some error",
-						telemetry.NodeAttestorType: "join_token",
-					},
-				},
-			},
-		},
-
-		{
-			name:       "ds: fails to delete join token",
+			name:       "ds: fails to consume join token",
 			request:    getAttestAgentRequest("join_token", []byte("test_token"), testCsr),
 			expectCode: codes.Internal,
-			expectMsg:  "failed to delete join token",
+			expectMsg:  "failed to consume join token",
 			dsError: []error{
-				nil,
 				errors.New("some error"),
 			},
 			expectLogs: []spiretest.LogEntry{
 				{
 					Level:   logrus.ErrorLevel,
-					Message: "Failed to delete join token",
+					Message: "Failed to consume join token",
 					Data: logrus.Fields{
 						telemetry.NodeAttestorType: "join_token",
 						logrus.ErrorKey:            "some error",
@@ -3065,7 +3105,7 @@ func TestAttestAgent(t *testing.T) {
 						telemetry.Status:           "error",
 						telemetry.Type:             "audit",
 						telemetry.StatusCode:       "Internal",
-						telemetry.StatusMessage:    "failed to delete join token: some error",
+						telemetry.StatusMessage:    "failed to consume join token: some error",
 						telemetry.NodeAttestorType: "join_token",
 					},
 				},
@@ -3078,7 +3118,6 @@ func TestAttestAgent(t *testing.T) {
 			expectCode: codes.Internal,
 			expectMsg:  "failed to fetch agent",
 			dsError: []error{
-				nil,
 				nil,
 				errors.New("some error"),
 			},
@@ -3113,7 +3152,6 @@ func TestAttestAgent(t *testing.T) {
 			expectCode: codes.Internal,
 			expectMsg:  "failed to update selectors",
 			dsError: []error{
-				nil,
 				nil,
 				nil,
 				errors.New("some error"),
@@ -3153,7 +3191,6 @@ func TestAttestAgent(t *testing.T) {
 				nil,
 				nil,
 				nil,
-				nil,
 				errors.New("some error"),
 			},
 			expectLogs: []spiretest.LogEntry{
@@ -3379,6 +3416,129 @@ func TestAttestAgent(t *testing.T) {
 	}
 }
 
+func TestAttestAgentWithMultiUseJoinToken(t *testing.T) {
+	testCsr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, testKey)
+	require.NoError(t, err)
+
+	tokenSelectors := []*common.Selector{{Type: "ci", Value: "runner"}}
+
+	for _, tt := range []struct {
+		name              string
+		agentPathTemplate string
+		expectIDs         []string
+		expectIDPrefix    string
+		expectCode        codes.Code
+		expectMsg         string
+	}{
+		{
+			name:           "default agent path",
+			expectIDPrefix: "spiffe://example.org/spire/agent/join_token/",
+		},
+		{
+			name:              "agent path template",
+			agentPathTemplate: "/join_token/ci/{{ .UseNumber }}",
+			expectIDs: []string{
+				"spiffe://example.org/spire/agent/join_token/ci/1",
+				"spiffe://example.org/spire/agent/join_token/ci/2",
+			},
+		},
+		{
+			name:              "agent path template produces an agent ID in use",
+			agentPathTemplate: "/join_token/ci",
+			expectIDs:         []string{"spiffe://example.org/spire/agent/join_token/ci"},
+			expectCode:        codes.PermissionDenied,
+			expectMsg:         "failed to attest: the agent ID produced by the join token is already in use",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			test := setupServiceTest(t, 0, false)
+			defer test.Cleanup()
+			ctx := t.Context()
+
+			require.NoError(t, test.ds.CreateJoinToken(ctx, &datastore.JoinToken{
+				Token:             "multi_use_token",
+				Expiry:            test.clk.Now().Add(time.Minute),
+				MaxUses:           2,
+				Selectors:         tokenSelectors,
+				AgentPathTemplate: tt.agentPathTemplate,
+			}))
+			test.rateLimiter.count = 1
+
+			var agentIDs []string
+			for i := range 3 {
+				stream, err := test.client.AttestAgent(ctx)
+				require.NoError(t, err)
+				result, err := attest(t, stream, getAttestAgentRequest("join_token", []byte("multi_use_token"), testCsr))
+				require.NoError(t, stream.CloseSend())
+
+				switch {
+				case i > 0 && tt.expectCode != codes.OK:
+					// Rejected attestations do not use the token up
+					spiretest.RequireGRPCStatus(t, err, tt.expectCode, tt.expectMsg)
+					continue
+				case i == 2:
+					// The token has no uses left
+					spiretest.RequireGRPCStatus(t, err, codes.InvalidArgument, "failed to attest: join token does not exist, has expired or has already been used")
+					continue
+				}
+				require.NoError(t, err)
+
+				agentID, err := x509.ParseCertificate(result.Svid.CertChain[0])
+				require.NoError(t, err)
+				require.Len(t, agentID.URIs, 1)
+				agentIDs = append(agentIDs, agentID.URIs[0].String())
+				test.assertAgentWasStored(t, agentID.URIs[0].String(), tokenSelectors, "", false)
+			}
+
+			if tt.expectCode != codes.OK {
+				joinToken, err := test.ds.FetchJoinToken(ctx, "multi_use_token")
+				require.NoError(t, err)
+				require.NotNil(t, joinToken)
+				require.Equal(t, int32(1), joinToken.UseCount)
+			}
+
+			if tt.expectIDPrefix != "" {
+				require.Len(t, agentIDs, 2)
+				require.NotEqual(t, agentIDs[0], agentIDs[1])
+				for _, agentID := range agentIDs {
+					require.True(t, strings.HasPrefix(agentID, tt.expectIDPrefix), agentID)
+					require.NotContains(t, agentID, "multi_use_token")
+				}
+				return
+			}
+			require.Equal(t, tt.expectIDs, agentIDs)
+		})
+	}
+}
+
+func TestAttestAgentWithExpiredMultiUseJoinToken(t *testing.T) {
+	testCsr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, testKey)
+	require.NoError(t, err)
+
+	test := setupServiceTest(t, 0, false)
+	defer test.Cleanup()
+	ctx := t.Context()
+
+	require.NoError(t, test.ds.CreateJoinToken(ctx, &datastore.JoinToken{
+		Token:   "multi_use_token",
+		Expiry:  test.clk.Now(),
+		MaxUses: 2,
+	}))
+	test.rateLimiter.count = 1
+
+	stream, err := test.client.AttestAgent(ctx)
+	require.NoError(t, err)
+	_, err = attest(t, stream, getAttestAgentRequest("join_token", []byte("multi_use_token"), testCsr))
+	require.NoError(t, stream.CloseSend())
+	spiretest.RequireGRPCStatus(t, err, codes.InvalidArgument, "failed to attest: join token does not exist, has expired or has already been used")
+
+	// The rejected attestation does not use the token up
+	joinToken, err := test.ds.FetchJoinToken(ctx, "multi_use_token")
+	require.NoError(t, err)
+	require.NotNil(t, joinToken)
+	require.Equal(t, int32(0), joinToken.UseCount)
+}
+
 type serviceTest struct {
 	client       agentv1.AgentClient
 	done         func()
@@ -3389,6 +3549,7 @@ type serviceTest struct {
 	logHook      *test.Hook
 	rateLimiter  *fakeRateLimiter
 	withCallerID bool
+	callerScopes authpolicy.Scopes
 	pluginCloser func()
 }
 
@@ -3436,6 +3597,9 @@ func setupServiceTest(t *testing.T, agentSVIDTTL time.Duration, agentSpiffeIdAsS
 		if test.withCallerID {
 			ctx = rpccontext.WithCallerID(ctx, agentID)
 		}
+		if len(test.callerScopes) > 0 {
+			ctx = rpccontext.WithCallerScopes(ctx, test.callerScopes)
+		}
 		return ctx
 	}
 
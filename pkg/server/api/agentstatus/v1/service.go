@@ -0,0 +1,144 @@
+package agentstatus
+
+import (
+	"context"
+	"time"
+
+	"github.com/andres-erbsen/clock"
+	"github.com/sirupsen/logrus"
+	commonapi "github.com/spiffe/spire/pkg/common/api"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/pkg/server/api/rpccontext"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	agentstatusv1 "github.com/spiffe/spire/proto/private/server/agentstatus"
+	"github.com/spiffe/spire/proto/spire/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RegisterService registers the agent status service on the gRPC server.
+func RegisterService(s grpc.ServiceRegistrar, service *Service) {
+	agentstatusv1.RegisterAgentStatusServer(s, service)
+}
+
+// Config is the service configuration.
+type Config struct {
+	DataStore datastore.DataStore
+	Clock     clock.Clock
+}
+
+// New creates a new agent status service.
+func New(config Config) *Service {
+	return &Service{
+		ds:  config.DataStore,
+		clk: config.Clock,
+	}
+}
+
+// Service implements the agent status service.
+type Service struct {
+	agentstatusv1.UnsafeAgentStatusServer
+
+	ds  datastore.DataStore
+	clk clock.Clock
+}
+
+// PostStatus stores the status report of the calling agent and refreshes the
+// last time it was seen. Neither is used by the node caches, so the update
+// does not produce an attested node event.
+func (s *Service) PostStatus(ctx context.Context, req *agentstatusv1.PostStatusRequest) (*agentstatusv1.PostStatusResponse, error) {
+	log := rpccontext.Logger(ctx)
+
+	if err := rpccontext.RateLimit(ctx, 1); err != nil {
+		return nil, commonapi.MakeErr(log, status.Code(err), "rejecting request due to post status rate limiting", err)
+	}
+
+	callerID, ok := rpccontext.CallerID(ctx)
+	if !ok {
+		return nil, commonapi.MakeErr(log, codes.Internal, "caller ID missing from request context", nil)
+	}
+	log = log.WithField(telemetry.SPIFFEID, callerID.String())
+	rpccontext.AddRPCAuditFields(ctx, logrus.Fields{
+		telemetry.SPIFFEID: callerID.String(),
+	})
+
+	update := &common.AttestedNode{
+		SpiffeId:   callerID.String(),
+		LastSeenAt: s.clk.Now().Unix(),
+	}
+	mask := &common.AttestedNodeMask{
+		LastSeenAt: true,
+	}
+	if req.Report != nil {
+		update.StatusReport = req.Report
+		mask.StatusReport = true
+	}
+
+	_, err := s.ds.UpdateAttestedNode(ctx, update, mask)
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.NotFound:
+		return nil, commonapi.MakeErr(log, codes.NotFound, "agent not found", err)
+	default:
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to update agent", err)
+	}
+	log.Debug("Agent status updated")
+
+	rpccontext.AuditRPC(ctx)
+	return &agentstatusv1.PostStatusResponse{}, nil
+}
+
+// ListAgentStatuses lists the status of agents. Scoped callers only see the
+// agents within their scopes.
+func (s *Service) ListAgentStatuses(ctx context.Context, req *agentstatusv1.ListAgentStatusesRequest) (*agentstatusv1.ListAgentStatusesResponse, error) {
+	log := rpccontext.Logger(ctx)
+
+	listReq := &datastore.ListAttestedNodesRequest{}
+	if filter := req.Filter; filter != nil {
+		if filter.ByLastSeenBefore != 0 {
+			listReq.ByLastSeenBefore = time.Unix(filter.ByLastSeenBefore, 0)
+		}
+		if filter.ByLastSeenAfter != 0 {
+			listReq.ByLastSeenAfter = time.Unix(filter.ByLastSeenAfter, 0)
+		}
+	}
+	if req.PageSize > 0 {
+		listReq.Pagination = &datastore.Pagination{
+			PageSize: req.PageSize,
+			Token:    req.PageToken,
+		}
+	}
+
+	resp := &agentstatusv1.ListAgentStatusesResponse{}
+
+	// Agents outside of the caller scopes are filtered out by the query
+	if scopes := rpccontext.CallerScopes(ctx); len(scopes) > 0 {
+		for _, prefix := range scopes.IDPrefixes() {
+			listReq.BySpiffeIDPrefixes = append(listReq.BySpiffeIDPrefixes, prefix.String())
+		}
+		if len(listReq.BySpiffeIDPrefixes) == 0 {
+			rpccontext.AuditRPC(ctx)
+			return resp, nil
+		}
+	}
+
+	dsResp, err := s.ds.ListAttestedNodes(ctx, listReq)
+	if err != nil {
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to list agents", err)
+	}
+
+	if dsResp.Pagination != nil {
+		resp.NextPageToken = dsResp.Pagination.Token
+	}
+	for _, node := range dsResp.Nodes {
+		resp.Statuses = append(resp.Statuses, &agentstatusv1.Status{
+			SpiffeId:   node.SpiffeId,
+			LastSeenAt: node.LastSeenAt,
+			Report:     node.StatusReport,
+		})
+	}
+
+	rpccontext.AuditRPC(ctx)
+	return resp, nil
+}
@@ -0,0 +1,271 @@
+package agentstatus_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/server/api/agentstatus/v1"
+	"github.com/spiffe/spire/pkg/server/api/middleware"
+	"github.com/spiffe/spire/pkg/server/api/rpccontext"
+	"github.com/spiffe/spire/pkg/server/authpolicy"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	agentstatusv1 "github.com/spiffe/spire/proto/private/server/agentstatus"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/spiffe/spire/test/clock"
+	"github.com/spiffe/spire/test/fakes/fakedatastore"
+	"github.com/spiffe/spire/test/grpctest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ctx = context.Background()
+
+	agent1 = "spiffe://example.org/spire/agent/agent-1"
+	agent2 = "spiffe://example.org/spire/agent/agent-2"
+)
+
+func TestPostStatus(t *testing.T) {
+	report := &common.AgentStatusReport{
+		Uptime:          120,
+		Plugins:         []*common.AgentPlugin{{Type: "KeyManager", Name: "memory", Version: "1.0.0"}},
+		CachedX509Svids: 3,
+		CachedJwtSvids:  1,
+		LastSyncError:   "oh no",
+	}
+
+	for _, tt := range []struct {
+		name           string
+		req            *agentstatusv1.PostStatusRequest
+		callerID       string
+		createAgent    bool
+		rateLimiterErr error
+		expectCode     codes.Code
+		expectMsg      string
+		expectReport   *common.AgentStatusReport
+	}{
+		{
+			name:         "success",
+			req:          &agentstatusv1.PostStatusRequest{Report: report},
+			callerID:     agent1,
+			createAgent:  true,
+			expectReport: report,
+		},
+		{
+			name:        "success without report",
+			req:         &agentstatusv1.PostStatusRequest{},
+			callerID:    agent1,
+			createAgent: true,
+		},
+		{
+			name:        "missing caller ID",
+			req:         &agentstatusv1.PostStatusRequest{Report: report},
+			createAgent: true,
+			expectCode:  codes.Internal,
+			expectMsg:   "caller ID missing from request context",
+		},
+		{
+			name:       "agent not found",
+			req:        &agentstatusv1.PostStatusRequest{Report: report},
+			callerID:   agent1,
+			expectCode: codes.NotFound,
+			expectMsg:  "agent not found",
+		},
+		{
+			name:           "rate limit fails",
+			req:            &agentstatusv1.PostStatusRequest{Report: report},
+			callerID:       agent1,
+			createAgent:    true,
+			rateLimiterErr: status.Error(codes.Unknown, "rate limit fails"),
+			expectCode:     codes.Unknown,
+			expectMsg:      "rejecting request due to post status rate limiting: rate limit fails",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			test := setupServiceTest(t, tt.callerID, nil)
+			test.rateLimiter.err = tt.rateLimiterErr
+			if tt.createAgent {
+				createAgent(t, test.ds, &common.AttestedNode{SpiffeId: agent1})
+			}
+
+			_, err := test.client.PostStatus(ctx, tt.req)
+			if tt.expectCode != codes.OK {
+				spiretest.RequireGRPCStatusHasPrefix(t, err, tt.expectCode, tt.expectMsg)
+				return
+			}
+			require.NoError(t, err)
+
+			node, err := test.ds.FetchAttestedNode(ctx, agent1)
+			require.NoError(t, err)
+			require.Equal(t, test.clk.Now().Unix(), node.LastSeenAt)
+			spiretest.AssertProtoEqual(t, tt.expectReport, node.StatusReport)
+		})
+	}
+}
+
+func TestPostStatusDoesNotCreateEvents(t *testing.T) {
+	test := setupServiceTest(t, agent1, nil)
+	createAgent(t, test.ds, &common.AttestedNode{SpiffeId: agent1})
+
+	events, err := test.ds.ListAttestedNodeEvents(ctx, &datastore.ListAttestedNodeEventsRequest{})
+	require.NoError(t, err)
+
+	for i := range 3 {
+		_, err := test.client.PostStatus(ctx, &agentstatusv1.PostStatusRequest{
+			Report: &common.AgentStatusReport{Uptime: int64(i)},
+		})
+		require.NoError(t, err)
+	}
+
+	after, err := test.ds.ListAttestedNodeEvents(ctx, &datastore.ListAttestedNodeEventsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, events.Events, after.Events)
+}
+
+func TestListAgentStatuses(t *testing.T) {
+	test := setupServiceTest(t, "", nil)
+
+	now := test.clk.Now()
+	report := &common.AgentStatusReport{
+		Uptime:          3600,
+		CachedX509Svids: 2,
+	}
+	createAgent(t, test.ds, &common.AttestedNode{SpiffeId: agent1, LastSeenAt: now.Add(-time.Hour).Unix(), StatusReport: report})
+	createAgent(t, test.ds, &common.AttestedNode{SpiffeId: agent2, LastSeenAt: now.Add(-time.Minute).Unix()})
+	createAgent(t, test.ds, &common.AttestedNode{SpiffeId: "spiffe://example.org/spire/agent/never-seen"})
+
+	status1 := &agentstatusv1.Status{SpiffeId: agent1, LastSeenAt: now.Add(-time.Hour).Unix(), Report: report}
+	status2 := &agentstatusv1.Status{SpiffeId: agent2, LastSeenAt: now.Add(-time.Minute).Unix()}
+
+	for _, tt := range []struct {
+		name         string
+		filter       *agentstatusv1.ListAgentStatusesRequest_Filter
+		callerScopes authpolicy.Scopes
+		expect       []*agentstatusv1.Status
+	}{
+		{
+			name: "all agents",
+			expect: []*agentstatusv1.Status{
+				status1,
+				status2,
+				{SpiffeId: "spiffe://example.org/spire/agent/never-seen"},
+			},
+		},
+		{
+			name:   "last seen before",
+			filter: &agentstatusv1.ListAgentStatusesRequest_Filter{ByLastSeenBefore: now.Add(-30 * time.Minute).Unix()},
+			expect: []*agentstatusv1.Status{status1},
+		},
+		{
+			name:   "last seen after",
+			filter: &agentstatusv1.ListAgentStatusesRequest_Filter{ByLastSeenAfter: now.Add(-30 * time.Minute).Unix()},
+			expect: []*agentstatusv1.Status{status2},
+		},
+		{
+			name:         "within caller scopes",
+			callerScopes: authpolicy.Scopes{{SPIFFEIDPrefix: spiffeid.RequireFromString(agent2)}},
+			expect:       []*agentstatusv1.Status{status2},
+		},
+		{
+			name:         "parent scopes match no agent",
+			callerScopes: authpolicy.Scopes{{ParentID: spiffeid.RequireFromString(agent1)}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			test.callerScopes = tt.callerScopes
+
+			var statuses []*agentstatusv1.Status
+			pageToken := ""
+			for {
+				resp, err := test.client.ListAgentStatuses(ctx, &agentstatusv1.ListAgentStatusesRequest{
+					Filter:    tt.filter,
+					PageSize:  1,
+					PageToken: pageToken,
+				})
+				require.NoError(t, err)
+				require.LessOrEqual(t, len(resp.Statuses), 1)
+				statuses = append(statuses, resp.Statuses...)
+				if pageToken = resp.NextPageToken; pageToken == "" {
+					break
+				}
+			}
+			spiretest.AssertProtoListEqual(t, tt.expect, statuses)
+		})
+	}
+}
+
+type serviceTest struct {
+	client       agentstatusv1.AgentStatusClient
+	ds           *fakedatastore.DataStore
+	clk          *clock.Mock
+	rateLimiter  *fakeRateLimiter
+	callerScopes authpolicy.Scopes
+}
+
+func setupServiceTest(t *testing.T, callerID string, callerScopes authpolicy.Scopes) *serviceTest {
+	ds := fakedatastore.New(t)
+	clk := clock.NewMock(t)
+	service := agentstatus.New(agentstatus.Config{
+		DataStore: ds,
+		Clock:     clk,
+	})
+
+	log, _ := test.NewNullLogger()
+	log.Level = logrus.DebugLevel
+
+	test := &serviceTest{
+		ds:           ds,
+		clk:          clk,
+		rateLimiter:  &fakeRateLimiter{},
+		callerScopes: callerScopes,
+	}
+
+	overrideContext := func(ctx context.Context) context.Context {
+		ctx = rpccontext.WithLogger(ctx, log)
+		ctx = rpccontext.WithRateLimiter(ctx, test.rateLimiter)
+		if callerID != "" {
+			ctx = rpccontext.WithCallerID(ctx, spiffeid.RequireFromString(callerID))
+		}
+		if test.callerScopes != nil {
+			ctx = rpccontext.WithCallerScopes(ctx, test.callerScopes)
+		}
+		return ctx
+	}
+
+	server := grpctest.StartServer(t, func(s grpc.ServiceRegistrar) {
+		agentstatus.RegisterService(s, service)
+	},
+		grpctest.OverrideContext(overrideContext),
+		grpctest.Middleware(middleware.WithAuditLog(false)),
+	)
+
+	test.client = agentstatusv1.NewAgentStatusClient(server.NewGRPCClient(t))
+	return test
+}
+
+func createAgent(t *testing.T, ds datastore.DataStore, node *common.AttestedNode) {
+	node.AttestationDataType = "t"
+	node.CertSerialNumber = "1"
+	node.CertNotAfter = time.Now().Add(time.Hour).Unix()
+	_, err := ds.CreateAttestedNode(ctx, node)
+	require.NoError(t, err)
+}
+
+type fakeRateLimiter struct {
+	err error
+}
+
+func (f *fakeRateLimiter) RateLimit(_ context.Context, count int) error {
+	if count != 1 {
+		return fmt.Errorf("rate limiter got %d but expected 1", count)
+	}
+	return f.err
+}
@@ -22,6 +22,13 @@ type AuthorizedEntryFetcher interface {
 	FetchAuthorizedEntries(ctx context.Context, id spiffeid.ID) ([]ReadOnlyEntry, error)
 }
 
+// AgentSelectorsCache looks up the selectors of agents in memory
+type AgentSelectorsCache interface {
+	// LookupAgentSelectors returns the cached selectors of the agent, and
+	// whether the agent is in the cache.
+	LookupAgentSelectors(agentID spiffeid.ID) ([]*types.Selector, bool)
+}
+
 type AttestedNodeCache interface {
 	// LookupAttestedNode returns the cached attested node with the time when
 	// the data was last refreshed by the cache.
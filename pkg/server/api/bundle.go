@@ -152,6 +152,27 @@ func ParseJWTAuthorities(keys []*types.JWTKey) ([]*common.PublicKey, error) {
 	return jwtKeys, nil
 }
 
+func ParseWITAuthorities(keys []*types.WITKey) ([]*common.PublicKey, error) {
+	var witKeys []*common.PublicKey
+	for _, key := range keys {
+		if _, err := x509.ParsePKIXPublicKey(key.PublicKey); err != nil {
+			return nil, err
+		}
+
+		if key.KeyId == "" {
+			return nil, errors.New("missing key ID")
+		}
+
+		witKeys = append(witKeys, &common.PublicKey{
+			PkixBytes: key.PublicKey,
+			Kid:       key.KeyId,
+			NotAfter:  key.ExpiresAt,
+		})
+	}
+
+	return witKeys, nil
+}
+
 func HashByte(b []byte) string {
 	if len(b) == 0 {
 		return ""
@@ -34,11 +34,25 @@ func (fn UpstreamPublisherFunc) PublishJWTKey(ctx context.Context, jwtKey *commo
 	return fn(ctx, jwtKey)
 }
 
+// WITKeyPublisher defines the WIT key publisher interface.
+type WITKeyPublisher interface {
+	PublishWITKey(ctx context.Context, witKey *common.PublicKey) ([]*common.PublicKey, error)
+}
+
+// WITKeyPublisherFunc defines the function.
+type WITKeyPublisherFunc func(ctx context.Context, witKey *common.PublicKey) ([]*common.PublicKey, error)
+
+// PublishWITKey publishes the WIT key with the given function.
+func (fn WITKeyPublisherFunc) PublishWITKey(ctx context.Context, witKey *common.PublicKey) ([]*common.PublicKey, error) {
+	return fn(ctx, witKey)
+}
+
 // Config defines the bundle service configuration.
 type Config struct {
 	DataStore         datastore.DataStore
 	TrustDomain       spiffeid.TrustDomain
 	UpstreamPublisher UpstreamPublisher
+	WITKeyPublisher   WITKeyPublisher
 }
 
 // Service defines the v1 bundle service properties.
@@ -48,6 +62,7 @@ type Service struct {
 	ds datastore.DataStore
 	td spiffeid.TrustDomain
 	up UpstreamPublisher
+	wp WITKeyPublisher
 }
 
 // New creates a new bundle service.
@@ -56,6 +71,7 @@ func New(config Config) *Service {
 		ds: config.DataStore,
 		td: config.TrustDomain,
 		up: config.UpstreamPublisher,
+		wp: config.WITKeyPublisher,
 	}
 }
 
@@ -189,8 +205,40 @@ func (s *Service) PublishJWTAuthority(ctx context.Context, req *bundlev1.Publish
 
 // PublishWITAuthority published the WIT key on the server.
 func (s *Service) PublishWITAuthority(ctx context.Context, req *bundlev1.PublishWITAuthorityRequest) (*bundlev1.PublishWITAuthorityResponse, error) {
+	parseRequest := func() logrus.Fields {
+		fields := logrus.Fields{}
+		if req.WitAuthority != nil {
+			fields[telemetry.WITAuthorityExpiresAt] = req.WitAuthority.ExpiresAt
+			fields[telemetry.WITAuthorityKeyID] = req.WitAuthority.KeyId
+			fields[telemetry.WITAuthorityPublicKeySHA256] = api.HashByte(req.WitAuthority.PublicKey)
+		}
+		return fields
+	}
+	rpccontext.AddRPCAuditFields(ctx, parseRequest())
 	log := rpccontext.Logger(ctx)
-	return nil, commonapi.MakeErr(log, codes.Unimplemented, "WIT-SVID functionality is not yet implemented", nil)
+
+	if err := rpccontext.RateLimit(ctx, 1); err != nil {
+		return nil, commonapi.MakeErr(log, status.Code(err), "rejecting request due to key publishing rate limiting", err)
+	}
+
+	if req.WitAuthority == nil {
+		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "missing WIT authority", nil)
+	}
+
+	keys, err := api.ParseWITAuthorities([]*types.WITKey{req.WitAuthority})
+	if err != nil {
+		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "invalid WIT authority", err)
+	}
+
+	resp, err := s.wp.PublishWITKey(ctx, keys[0])
+	if err != nil {
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to publish WIT key", err)
+	}
+	rpccontext.AuditRPC(ctx)
+
+	return &bundlev1.PublishWITAuthorityResponse{
+		WitAuthorities: api.PublicKeysToWITKeys(resp),
+	}, nil
 }
 
 // ListFederatedBundles returns an optionally paginated list of federated bundles.
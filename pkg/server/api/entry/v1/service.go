@@ -16,6 +16,7 @@ import (
 	"github.com/spiffe/spire/pkg/common/telemetry"
 	"github.com/spiffe/spire/pkg/server/api"
 	"github.com/spiffe/spire/pkg/server/api/rpccontext"
+	"github.com/spiffe/spire/pkg/server/authpolicy"
 	"github.com/spiffe/spire/pkg/server/datastore"
 	"github.com/spiffe/spire/proto/spire/common"
 	"google.golang.org/grpc"
@@ -23,7 +24,11 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-const defaultEntryPageSize = 500
+const (
+	defaultEntryPageSize = 500
+
+	privilegedEntryMsg = "scoped callers cannot manage admin or downstream entries"
+)
 
 // Config defines the service configuration.
 type Config struct {
@@ -125,6 +130,9 @@ func (s *Service) CountEntries(ctx context.Context, req *entryv1.CountEntriesReq
 		}
 	}
 
+	// Entries outside of the caller scopes are not counted
+	countReq.ByScopes = entryScopes(rpccontext.CallerScopes(ctx))
+
 	count, err := s.ds.CountRegistrationEntries(ctx, countReq)
 	if err != nil {
 		log := rpccontext.Logger(ctx)
@@ -208,6 +216,11 @@ func (s *Service) ListEntries(ctx context.Context, req *entryv1.ListEntriesReque
 		}
 	}
 
+	// Entries outside of the caller scopes are filtered out by the query
+	// instead of failing the call, so scoped callers can list what they
+	// manage.
+	listReq.ByScopes = entryScopes(rpccontext.CallerScopes(ctx))
+
 	dsResp, err := s.ds.ListRegistrationEntries(ctx, listReq)
 	if err != nil {
 		return nil, commonapi.MakeErr(log, codes.Internal, "failed to list entries", err)
@@ -250,6 +263,10 @@ func (s *Service) GetEntry(ctx context.Context, req *entryv1.GetEntryRequest) (*
 		return nil, commonapi.MakeErr(log, codes.NotFound, "entry not found", nil)
 	}
 
+	if !entryInScopes(rpccontext.CallerScopes(ctx), registrationEntry) {
+		return nil, commonapi.MakeErr(log, codes.PermissionDenied, "entry is outside of the caller scopes", nil)
+	}
+
 	entry, err := api.RegistrationEntryToProto(registrationEntry)
 	if err != nil {
 		return nil, commonapi.MakeErr(log, codes.Internal, "failed to convert entry", err)
@@ -288,6 +305,19 @@ func (s *Service) createEntry(ctx context.Context, e *types.Entry, outputMask *t
 
 	log = log.WithField(telemetry.SPIFFEID, cEntry.SpiffeId)
 
+	if scopes := rpccontext.CallerScopes(ctx); len(scopes) > 0 {
+		switch {
+		case isPrivilegedEntry(cEntry):
+			return &entryv1.BatchCreateEntryResponse_Result{
+				Status: commonapi.MakeStatus(log, codes.PermissionDenied, privilegedEntryMsg, nil),
+			}
+		case !entryInScopes(scopes, cEntry):
+			return &entryv1.BatchCreateEntryResponse_Result{
+				Status: commonapi.MakeStatus(log, codes.PermissionDenied, "entry is outside of the caller scopes", nil),
+			}
+		}
+	}
+
 	resultStatus := commonapi.OK()
 	regEntry, existing, err := s.ds.CreateOrReturnRegistrationEntry(ctx, cEntry)
 	switch {
@@ -363,6 +393,27 @@ func (s *Service) deleteEntry(ctx context.Context, id string) *entryv1.BatchDele
 
 	log = log.WithField(telemetry.RegistrationID, id)
 
+	if scopes := rpccontext.CallerScopes(ctx); len(scopes) > 0 {
+		entry, err := s.ds.FetchRegistrationEntry(ctx, id)
+		switch {
+		case err != nil:
+			return &entryv1.BatchDeleteEntryResponse_Result{
+				Id:     id,
+				Status: commonapi.MakeStatus(log, codes.Internal, "failed to fetch entry", err),
+			}
+		case entry == nil:
+			return &entryv1.BatchDeleteEntryResponse_Result{
+				Id:     id,
+				Status: commonapi.MakeStatus(log, codes.NotFound, "entry not found", nil),
+			}
+		case !entryInScopes(scopes, entry):
+			return &entryv1.BatchDeleteEntryResponse_Result{
+				Id:     id,
+				Status: commonapi.MakeStatus(log, codes.PermissionDenied, "entry is outside of the caller scopes", nil),
+			}
+		}
+	}
+
 	_, err := s.ds.DeleteRegistrationEntry(ctx, id)
 	switch status.Code(err) {
 	case codes.OK:
@@ -649,6 +700,14 @@ func (s *Service) updateEntry(ctx context.Context, e *types.Entry, inputMask *ty
 		}
 	}
 
+	if scopes := rpccontext.CallerScopes(ctx); len(scopes) > 0 {
+		if st := s.checkUpdateInScopes(ctx, log, scopes, convEntry, inputMask); st != nil {
+			return &entryv1.BatchUpdateEntryResponse_Result{
+				Status: st,
+			}
+		}
+	}
+
 	var mask *common.RegistrationEntryMask
 	if inputMask != nil {
 		mask = &common.RegistrationEntryMask{
@@ -693,6 +752,92 @@ func (s *Service) updateEntry(ctx context.Context, e *types.Entry, inputMask *ty
 	}
 }
 
+// checkUpdateInScopes verifies that both the entry being updated and the
+// result of the update are within the caller scopes, so scoped callers can
+// neither modify entries they do not manage nor move entries out of their
+// scopes. Scoped callers cannot change the admin and downstream flags either.
+func (s *Service) checkUpdateInScopes(ctx context.Context, log logrus.FieldLogger, scopes authpolicy.Scopes, entry *common.RegistrationEntry, inputMask *types.EntryMask) *types.Status {
+	if inputMask != nil && (inputMask.Admin || inputMask.Downstream) {
+		return commonapi.MakeStatus(log, codes.PermissionDenied, privilegedEntryMsg, nil)
+	}
+
+	existing, err := s.ds.FetchRegistrationEntry(ctx, entry.EntryId)
+	switch {
+	case err != nil:
+		return commonapi.MakeStatus(log, codes.Internal, "failed to fetch entry", err)
+	case existing == nil:
+		return commonapi.MakeStatus(log, codes.NotFound, "entry not found", nil)
+	case !entryInScopes(scopes, existing):
+		return commonapi.MakeStatus(log, codes.PermissionDenied, "entry is outside of the caller scopes", nil)
+	}
+
+	updated := &common.RegistrationEntry{
+		SpiffeId: existing.SpiffeId,
+		ParentId: existing.ParentId,
+	}
+	if inputMask == nil || inputMask.SpiffeId {
+		updated.SpiffeId = entry.SpiffeId
+	}
+	if inputMask == nil || inputMask.ParentId {
+		updated.ParentId = entry.ParentId
+	}
+	if inputMask == nil {
+		updated.Admin = entry.Admin
+		updated.Downstream = entry.Downstream
+	}
+	switch {
+	case isPrivilegedEntry(updated):
+		return commonapi.MakeStatus(log, codes.PermissionDenied, privilegedEntryMsg, nil)
+	case !entryInScopes(scopes, updated):
+		return commonapi.MakeStatus(log, codes.PermissionDenied, "updated entry would be outside of the caller scopes", nil)
+	}
+	return nil
+}
+
+// entryInScopes returns true if the entry is within the given caller scopes.
+// Admin and downstream entries are never within scopes, since their SVIDs
+// grant rights beyond the scopes.
+func entryInScopes(scopes authpolicy.Scopes, entry *common.RegistrationEntry) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+	if isPrivilegedEntry(entry) {
+		return false
+	}
+	spiffeID, err := spiffeid.FromString(entry.SpiffeId)
+	if err != nil {
+		return false
+	}
+	parentID, err := spiffeid.FromString(entry.ParentId)
+	if err != nil {
+		return false
+	}
+	return scopes.AllowsEntry(spiffeID, parentID)
+}
+
+// isPrivilegedEntry returns true if the SVIDs of the entry are granted admin
+// rights or can sign downstream CAs.
+func isPrivilegedEntry(entry *common.RegistrationEntry) bool {
+	return entry.Admin || entry.Downstream
+}
+
+// entryScopes returns the datastore filter matching the entries within the
+// given caller scopes, or nil if the scopes do not restrict the caller.
+func entryScopes(scopes authpolicy.Scopes) []datastore.EntryScope {
+	var entryScopes []datastore.EntryScope
+	for _, scope := range scopes {
+		var entryScope datastore.EntryScope
+		if !scope.SPIFFEIDPrefix.IsZero() {
+			entryScope.SPIFFEIDPrefix = scope.SPIFFEIDPrefix.String()
+		}
+		if !scope.ParentID.IsZero() {
+			entryScope.ParentID = scope.ParentID.String()
+		}
+		entryScopes = append(entryScopes, entryScope)
+	}
+	return entryScopes
+}
+
 func fieldsFromEntryProto(ctx context.Context, proto *types.Entry, inputMask *types.EntryMask) logrus.Fields {
 	fields := logrus.Fields{}
 
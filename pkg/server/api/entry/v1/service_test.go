@@ -23,6 +23,7 @@ import (
 	"github.com/spiffe/spire/pkg/server/api/entry/v1"
 	"github.com/spiffe/spire/pkg/server/api/middleware"
 	"github.com/spiffe/spire/pkg/server/api/rpccontext"
+	"github.com/spiffe/spire/pkg/server/authpolicy"
 	"github.com/spiffe/spire/pkg/server/datastore"
 	"github.com/spiffe/spire/proto/spire/common"
 	"github.com/spiffe/spire/test/fakes/fakedatastore"
@@ -2561,29 +2562,29 @@ func TestBatchDeleteEntry(t *testing.T) {
 			expectDs: dsEntries,
 			expectResult: func(m map[string]*common.RegistrationEntry) ([]*entryv1.BatchDeleteEntryResponse_Result, []spiretest.LogEntry) {
 				return []*entryv1.BatchDeleteEntryResponse_Result{
-						{
-							Status: &types.Status{
-								Code:    int32(codes.InvalidArgument),
-								Message: "missing entry ID",
-							},
-						},
-					}, []spiretest.LogEntry{
-						{
-							Level:   logrus.ErrorLevel,
-							Message: "Invalid argument: missing entry ID",
+					{
+						Status: &types.Status{
+							Code:    int32(codes.InvalidArgument),
+							Message: "missing entry ID",
 						},
-						{
-							Level:   logrus.InfoLevel,
-							Message: "API accessed",
-							Data: logrus.Fields{
-								telemetry.Status:         "error",
-								telemetry.Type:           "audit",
-								telemetry.RegistrationID: "",
-								telemetry.StatusCode:     "InvalidArgument",
-								telemetry.StatusMessage:  "missing entry ID",
-							},
+					},
+				}, []spiretest.LogEntry{
+					{
+						Level:   logrus.ErrorLevel,
+						Message: "Invalid argument: missing entry ID",
+					},
+					{
+						Level:   logrus.InfoLevel,
+						Message: "API accessed",
+						Data: logrus.Fields{
+							telemetry.Status:         "error",
+							telemetry.Type:           "audit",
+							telemetry.RegistrationID: "",
+							telemetry.StatusCode:     "InvalidArgument",
+							telemetry.StatusMessage:  "missing entry ID",
 						},
-					}
+					},
+				}
 			},
 			ids: func(m map[string]*common.RegistrationEntry) []string {
 				return []string{""}
@@ -2595,34 +2596,34 @@ func TestBatchDeleteEntry(t *testing.T) {
 			expectDs: dsEntries,
 			expectResult: func(m map[string]*common.RegistrationEntry) ([]*entryv1.BatchDeleteEntryResponse_Result, []spiretest.LogEntry) {
 				return []*entryv1.BatchDeleteEntryResponse_Result{
-						{
-							Status: &types.Status{
-								Code:    int32(codes.Internal),
-								Message: "failed to delete entry: some error",
-							},
-							Id: m[fooSpiffeID].EntryId,
+					{
+						Status: &types.Status{
+							Code:    int32(codes.Internal),
+							Message: "failed to delete entry: some error",
 						},
-					}, []spiretest.LogEntry{
-						{
-							Level:   logrus.ErrorLevel,
-							Message: "Failed to delete entry",
-							Data: logrus.Fields{
-								telemetry.RegistrationID: m[fooSpiffeID].EntryId,
-								logrus.ErrorKey:          "some error",
-							},
+						Id: m[fooSpiffeID].EntryId,
+					},
+				}, []spiretest.LogEntry{
+					{
+						Level:   logrus.ErrorLevel,
+						Message: "Failed to delete entry",
+						Data: logrus.Fields{
+							telemetry.RegistrationID: m[fooSpiffeID].EntryId,
+							logrus.ErrorKey:          "some error",
 						},
-						{
-							Level:   logrus.InfoLevel,
-							Message: "API accessed",
-							Data: logrus.Fields{
-								telemetry.Status:         "error",
-								telemetry.Type:           "audit",
-								telemetry.RegistrationID: m[fooSpiffeID].EntryId,
-								telemetry.StatusCode:     "Internal",
-								telemetry.StatusMessage:  "failed to delete entry: some error",
-							},
+					},
+					{
+						Level:   logrus.InfoLevel,
+						Message: "API accessed",
+						Data: logrus.Fields{
+							telemetry.Status:         "error",
+							telemetry.Type:           "audit",
+							telemetry.RegistrationID: m[fooSpiffeID].EntryId,
+							telemetry.StatusCode:     "Internal",
+							telemetry.StatusMessage:  "failed to delete entry: some error",
 						},
-					}
+					},
+				}
 			},
 			ids: func(m map[string]*common.RegistrationEntry) []string {
 				return []string{m[fooSpiffeID].EntryId}
@@ -2633,33 +2634,33 @@ func TestBatchDeleteEntry(t *testing.T) {
 			expectDs: dsEntries,
 			expectResult: func(m map[string]*common.RegistrationEntry) ([]*entryv1.BatchDeleteEntryResponse_Result, []spiretest.LogEntry) {
 				return []*entryv1.BatchDeleteEntryResponse_Result{
-						{
-							Status: &types.Status{
-								Code:    int32(codes.NotFound),
-								Message: "entry not found",
-							},
-							Id: "invalid id",
+					{
+						Status: &types.Status{
+							Code:    int32(codes.NotFound),
+							Message: "entry not found",
 						},
-					}, []spiretest.LogEntry{
-						{
-							Level:   logrus.ErrorLevel,
-							Message: "Entry not found",
-							Data: logrus.Fields{
-								telemetry.RegistrationID: "invalid id",
-							},
+						Id: "invalid id",
+					},
+				}, []spiretest.LogEntry{
+					{
+						Level:   logrus.ErrorLevel,
+						Message: "Entry not found",
+						Data: logrus.Fields{
+							telemetry.RegistrationID: "invalid id",
 						},
-						{
-							Level:   logrus.InfoLevel,
-							Message: "API accessed",
-							Data: logrus.Fields{
-								telemetry.Status:         "error",
-								telemetry.Type:           "audit",
-								telemetry.RegistrationID: "invalid id",
-								telemetry.StatusCode:     "NotFound",
-								telemetry.StatusMessage:  "entry not found",
-							},
+					},
+					{
+						Level:   logrus.InfoLevel,
+						Message: "API accessed",
+						Data: logrus.Fields{
+							telemetry.Status:         "error",
+							telemetry.Type:           "audit",
+							telemetry.RegistrationID: "invalid id",
+							telemetry.StatusCode:     "NotFound",
+							telemetry.StatusMessage:  "entry not found",
 						},
-					}
+					},
+				}
 			},
 			ids: func(m map[string]*common.RegistrationEntry) []string {
 				return []string{"invalid id"}
@@ -4811,6 +4812,230 @@ func createFederatedBundles(t *testing.T, ds datastore.DataStore) {
 	require.NoError(t, err)
 }
 
+func TestScopedEntries(t *testing.T) {
+	ds := fakedatastore.New(t)
+	test := setupServiceTest(t, ds, withCallerScopes(authpolicy.Scopes{
+		{SPIFFEIDPrefix: spiffeid.RequireFromSegments(td, "team-a")},
+	}))
+	defer test.Cleanup()
+
+	selectors := []*common.Selector{{Type: "unix", Value: "uid:1000"}}
+	entries := createTestEntries(t, ds,
+		&common.RegistrationEntry{
+			ParentId:  agentID.String(),
+			SpiffeId:  "spiffe://example.org/team-b/db",
+			Selectors: selectors,
+		},
+		&common.RegistrationEntry{
+			ParentId:  agentID.String(),
+			SpiffeId:  "spiffe://example.org/team-a/admin",
+			Selectors: selectors,
+			Admin:     true,
+		},
+		&common.RegistrationEntry{
+			ParentId:  agentID.String(),
+			SpiffeId:  "spiffe://example.org/team-a/db",
+			Selectors: selectors,
+		},
+	)
+	inScope := entries["spiffe://example.org/team-a/db"]
+	outOfScope := entries["spiffe://example.org/team-b/db"]
+	admin := entries["spiffe://example.org/team-a/admin"]
+
+	t.Run("list filters entries", func(t *testing.T) {
+		resp, err := test.client.ListEntries(ctx, &entryv1.ListEntriesRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Entries, 1)
+		require.Equal(t, inScope.EntryId, resp.Entries[0].Id)
+	})
+
+	t.Run("list returns full pages", func(t *testing.T) {
+		resp, err := test.client.ListEntries(ctx, &entryv1.ListEntriesRequest{PageSize: 1})
+		require.NoError(t, err)
+		require.Len(t, resp.Entries, 1)
+		require.Equal(t, inScope.EntryId, resp.Entries[0].Id)
+
+		resp, err = test.client.ListEntries(ctx, &entryv1.ListEntriesRequest{PageSize: 1, PageToken: resp.NextPageToken})
+		require.NoError(t, err)
+		require.Empty(t, resp.Entries)
+	})
+
+	t.Run("count filters entries", func(t *testing.T) {
+		resp, err := test.client.CountEntries(ctx, &entryv1.CountEntriesRequest{})
+		require.NoError(t, err)
+		require.Equal(t, int32(1), resp.Count)
+
+		resp, err = test.client.CountEntries(ctx, &entryv1.CountEntriesRequest{
+			Filter: &entryv1.CountEntriesRequest_Filter{
+				BySpiffeId: &types.SPIFFEID{TrustDomain: td.Name(), Path: "/team-b/db"},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(0), resp.Count)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		_, err := test.client.GetEntry(ctx, &entryv1.GetEntryRequest{Id: inScope.EntryId})
+		require.NoError(t, err)
+
+		_, err = test.client.GetEntry(ctx, &entryv1.GetEntryRequest{Id: outOfScope.EntryId})
+		spiretest.RequireGRPCStatus(t, err, codes.PermissionDenied, "entry is outside of the caller scopes")
+
+		_, err = test.client.GetEntry(ctx, &entryv1.GetEntryRequest{Id: admin.EntryId})
+		spiretest.RequireGRPCStatus(t, err, codes.PermissionDenied, "entry is outside of the caller scopes")
+	})
+
+	t.Run("create admin or downstream entry", func(t *testing.T) {
+		resp, err := test.client.BatchCreateEntry(ctx, &entryv1.BatchCreateEntryRequest{
+			Entries: []*types.Entry{
+				{
+					ParentId:  api.ProtoFromID(agentID),
+					SpiffeId:  &types.SPIFFEID{TrustDomain: td.Name(), Path: "/team-a/escalate"},
+					Selectors: []*types.Selector{{Type: "unix", Value: "uid:1000"}},
+					Admin:     true,
+				},
+				{
+					ParentId:   api.ProtoFromID(agentID),
+					SpiffeId:   &types.SPIFFEID{TrustDomain: td.Name(), Path: "/team-a/escalate"},
+					Selectors:  []*types.Selector{{Type: "unix", Value: "uid:1001"}},
+					Downstream: true,
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Results, 2)
+		for _, result := range resp.Results {
+			require.Equal(t, int32(codes.PermissionDenied), result.Status.Code)
+			require.Equal(t, "scoped callers cannot manage admin or downstream entries", result.Status.Message)
+		}
+
+		listResp, err := ds.ListRegistrationEntries(ctx, &datastore.ListRegistrationEntriesRequest{
+			BySpiffeID: "spiffe://example.org/team-a/escalate",
+		})
+		require.NoError(t, err)
+		require.Empty(t, listResp.Entries)
+	})
+
+	t.Run("update admin or downstream flags", func(t *testing.T) {
+		resp, err := test.client.BatchUpdateEntry(ctx, &entryv1.BatchUpdateEntryRequest{
+			Entries: []*types.Entry{
+				{Id: inScope.EntryId, Admin: true},
+				{Id: inScope.EntryId, Downstream: true},
+				{Id: inScope.EntryId, Admin: false},
+			},
+			InputMask: &types.EntryMask{Admin: true, Downstream: true},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Results, 3)
+		for _, result := range resp.Results {
+			require.Equal(t, int32(codes.PermissionDenied), result.Status.Code)
+			require.Equal(t, "scoped callers cannot manage admin or downstream entries", result.Status.Message)
+		}
+
+		// Without an input mask, every field is updated
+		resp, err = test.client.BatchUpdateEntry(ctx, &entryv1.BatchUpdateEntryRequest{
+			Entries: []*types.Entry{
+				{
+					Id:        inScope.EntryId,
+					ParentId:  api.ProtoFromID(agentID),
+					SpiffeId:  &types.SPIFFEID{TrustDomain: td.Name(), Path: "/team-a/db"},
+					Selectors: []*types.Selector{{Type: "unix", Value: "uid:1000"}},
+					Admin:     true,
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Results, 1)
+		require.Equal(t, int32(codes.PermissionDenied), resp.Results[0].Status.Code)
+		require.Equal(t, "scoped callers cannot manage admin or downstream entries", resp.Results[0].Status.Message)
+
+		// Existing admin entries cannot be updated, e.g. to obtain their
+		// SVIDs
+		resp, err = test.client.BatchUpdateEntry(ctx, &entryv1.BatchUpdateEntryRequest{
+			Entries: []*types.Entry{
+				{Id: admin.EntryId, Selectors: []*types.Selector{{Type: "unix", Value: "uid:0"}}},
+			},
+			InputMask: &types.EntryMask{Selectors: true},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Results, 1)
+		require.Equal(t, int32(codes.PermissionDenied), resp.Results[0].Status.Code)
+		require.Equal(t, "entry is outside of the caller scopes", resp.Results[0].Status.Message)
+
+		for _, id := range []string{inScope.EntryId, admin.EntryId} {
+			entry, err := ds.FetchRegistrationEntry(ctx, id)
+			require.NoError(t, err)
+			require.Equal(t, id == admin.EntryId, entry.Admin)
+			require.False(t, entry.Downstream)
+			require.Equal(t, selectors, entry.Selectors)
+		}
+	})
+
+	t.Run("create", func(t *testing.T) {
+		resp, err := test.client.BatchCreateEntry(ctx, &entryv1.BatchCreateEntryRequest{
+			Entries: []*types.Entry{
+				{
+					ParentId:  api.ProtoFromID(agentID),
+					SpiffeId:  &types.SPIFFEID{TrustDomain: td.Name(), Path: "/team-a/web"},
+					Selectors: []*types.Selector{{Type: "unix", Value: "uid:1000"}},
+				},
+				{
+					ParentId:  api.ProtoFromID(agentID),
+					SpiffeId:  &types.SPIFFEID{TrustDomain: td.Name(), Path: "/team-ab/web"},
+					Selectors: []*types.Selector{{Type: "unix", Value: "uid:1000"}},
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Results, 2)
+		require.Equal(t, int32(codes.OK), resp.Results[0].Status.Code)
+		require.Equal(t, int32(codes.PermissionDenied), resp.Results[1].Status.Code)
+		require.Equal(t, "entry is outside of the caller scopes", resp.Results[1].Status.Message)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		resp, err := test.client.BatchUpdateEntry(ctx, &entryv1.BatchUpdateEntryRequest{
+			Entries: []*types.Entry{
+				{
+					Id:       inScope.EntryId,
+					SpiffeId: &types.SPIFFEID{TrustDomain: td.Name(), Path: "/team-a/db2"},
+				},
+				{
+					Id:       inScope.EntryId,
+					SpiffeId: &types.SPIFFEID{TrustDomain: td.Name(), Path: "/team-b/db2"},
+				},
+				{
+					Id:       outOfScope.EntryId,
+					SpiffeId: &types.SPIFFEID{TrustDomain: td.Name(), Path: "/team-a/db3"},
+				},
+			},
+			InputMask: &types.EntryMask{SpiffeId: true},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Results, 3)
+		require.Equal(t, int32(codes.OK), resp.Results[0].Status.Code)
+		require.Equal(t, int32(codes.PermissionDenied), resp.Results[1].Status.Code)
+		require.Equal(t, "updated entry would be outside of the caller scopes", resp.Results[1].Status.Message)
+		require.Equal(t, int32(codes.PermissionDenied), resp.Results[2].Status.Code)
+		require.Equal(t, "entry is outside of the caller scopes", resp.Results[2].Status.Message)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		resp, err := test.client.BatchDeleteEntry(ctx, &entryv1.BatchDeleteEntryRequest{
+			Ids: []string{outOfScope.EntryId, "missing", inScope.EntryId},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Results, 3)
+		require.Equal(t, int32(codes.PermissionDenied), resp.Results[0].Status.Code)
+		require.Equal(t, int32(codes.NotFound), resp.Results[1].Status.Code)
+		require.Equal(t, int32(codes.OK), resp.Results[2].Status.Code)
+
+		remaining, err := ds.FetchRegistrationEntry(ctx, outOfScope.EntryId)
+		require.NoError(t, err)
+		require.NotNil(t, remaining)
+	})
+}
+
 func createTestEntries(t *testing.T, ds datastore.DataStore, entry ...*common.RegistrationEntry) map[string]*common.RegistrationEntry {
 	entriesMap := make(map[string]*common.RegistrationEntry)
 
@@ -4832,8 +5057,15 @@ func withEntryPageSize(v int) func(*serviceTestConfig) {
 	}
 }
 
+func withCallerScopes(scopes authpolicy.Scopes) func(*serviceTestConfig) {
+	return func(config *serviceTestConfig) {
+		config.callerScopes = scopes
+	}
+}
+
 type serviceTestConfig struct {
 	entryPageSize int
+	callerScopes  authpolicy.Scopes
 }
 
 type serviceTest struct {
@@ -4878,6 +5110,9 @@ func setupServiceTest(t *testing.T, ds datastore.DataStore, options ...serviceTe
 		if !test.omitCallerID {
 			ctx = rpccontext.WithCallerID(ctx, agentID)
 		}
+		if len(config.callerScopes) > 0 {
+			ctx = rpccontext.WithCallerScopes(ctx, config.callerScopes)
+		}
 		return ctx
 	}
 
@@ -0,0 +1,206 @@
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	commonapi "github.com/spiffe/spire/pkg/common/api"
+	"github.com/spiffe/spire/pkg/common/bundlequarantine"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/pkg/server/api/rpccontext"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	federationv1 "github.com/spiffe/spire/proto/private/server/federation"
+	"github.com/spiffe/spire/proto/spire/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+// RegisterService registers the federation service on the gRPC server.
+func RegisterService(s grpc.ServiceRegistrar, service *Service) {
+	federationv1.RegisterFederationServer(s, service)
+}
+
+// BundleRefresher is used by the service to reload the configuration of the
+// bundle refresher when the options of a relationship change.
+type BundleRefresher interface {
+	// TriggerConfigReload triggers the refresher to reload it's configuration
+	TriggerConfigReload()
+}
+
+// Config is the service configuration.
+type Config struct {
+	DataStore       datastore.DataStore
+	BundleRefresher BundleRefresher
+}
+
+// New creates a new federation service.
+func New(config Config) *Service {
+	return &Service{
+		ds: config.DataStore,
+		br: config.BundleRefresher,
+	}
+}
+
+// Service implements the federation service.
+type Service struct {
+	federationv1.UnsafeFederationServer
+
+	ds datastore.DataStore
+	br BundleRefresher
+}
+
+// ApproveQuarantinedBundle replaces the bundle of the trust domain with its
+// quarantined bundle, as long as the quarantined bundle is still the one the
+// caller approved and is not older than the current bundle.
+func (s *Service) ApproveQuarantinedBundle(ctx context.Context, req *federationv1.ApproveQuarantinedBundleRequest) (*federationv1.ApproveQuarantinedBundleResponse, error) {
+	log := rpccontext.Logger(ctx)
+	rpccontext.AddRPCAuditFields(ctx, logrus.Fields{
+		telemetry.TrustDomainID:           req.TrustDomain,
+		telemetry.QuarantinedBundleDigest: req.Digest,
+	})
+
+	trustDomain, err := spiffeid.TrustDomainFromString(req.TrustDomain)
+	if err != nil {
+		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "failed to parse trust domain", err)
+	}
+	if req.Digest == "" {
+		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "missing quarantined bundle digest", nil)
+	}
+
+	log = log.WithFields(logrus.Fields{
+		telemetry.TrustDomainID:           trustDomain.Name(),
+		telemetry.QuarantinedBundleDigest: req.Digest,
+	})
+
+	quarantined, err := s.ds.FetchQuarantinedBundle(ctx, trustDomain.IDString())
+	if err != nil {
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to fetch quarantined bundle", err)
+	}
+	if quarantined == nil {
+		return nil, commonapi.MakeErr(log, codes.NotFound, fmt.Sprintf("no quarantined bundle for trust domain %q", trustDomain), nil)
+	}
+
+	quarantinedDigest, err := bundlequarantine.Digest(quarantined.Bundle)
+	if err != nil {
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to compute quarantined bundle digest", err)
+	}
+	if req.Digest != quarantinedDigest {
+		return nil, commonapi.MakeErr(log, codes.FailedPrecondition, fmt.Sprintf("quarantined bundle digest is %q; it may have changed since it was reviewed", quarantinedDigest), nil)
+	}
+
+	// The bundle may have been updated since the bundle was quarantined.
+	// Approving a quarantined bundle must never roll the bundle back.
+	current, err := s.ds.FetchBundle(ctx, trustDomain.IDString())
+	if err != nil {
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to fetch bundle", err)
+	}
+	if current != nil && quarantined.Bundle.SequenceNumber < current.SequenceNumber {
+		return nil, commonapi.MakeErr(log, codes.FailedPrecondition, fmt.Sprintf("quarantined bundle sequence number %d is older than the current bundle sequence number %d", quarantined.Bundle.SequenceNumber, current.SequenceNumber), nil)
+	}
+
+	bundle, err := s.ds.ReleaseQuarantinedBundle(ctx, quarantined.Bundle)
+	if err != nil {
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to release quarantined bundle", err)
+	}
+
+	log.Info("Quarantined bundle approved")
+	rpccontext.AuditRPC(ctx)
+	return &federationv1.ApproveQuarantinedBundleResponse{
+		Bundle: bundle,
+	}, nil
+}
+
+// GetFederationRelationshipOptions returns the options of the federation
+// relationship with the trust domain.
+func (s *Service) GetFederationRelationshipOptions(ctx context.Context, req *federationv1.GetFederationRelationshipOptionsRequest) (*federationv1.GetFederationRelationshipOptionsResponse, error) {
+	log := rpccontext.Logger(ctx)
+	rpccontext.AddRPCAuditFields(ctx, logrus.Fields{telemetry.TrustDomainID: req.TrustDomain})
+
+	trustDomain, err := spiffeid.TrustDomainFromString(req.TrustDomain)
+	if err != nil {
+		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "failed to parse trust domain", err)
+	}
+
+	fr, err := s.ds.FetchFederationRelationship(ctx, trustDomain)
+	if err != nil {
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to fetch federation relationship", err)
+	}
+	if fr == nil {
+		return nil, commonapi.MakeErr(log, codes.NotFound, "federation relationship does not exist", nil)
+	}
+
+	rpccontext.AuditRPC(ctx)
+	return &federationv1.GetFederationRelationshipOptionsResponse{
+		Options: fr.Options,
+	}, nil
+}
+
+// SetFederationRelationshipOptions replaces the options of the federation
+// relationship with the trust domain.
+func (s *Service) SetFederationRelationshipOptions(ctx context.Context, req *federationv1.SetFederationRelationshipOptionsRequest) (*federationv1.SetFederationRelationshipOptionsResponse, error) {
+	log := rpccontext.Logger(ctx)
+	rpccontext.AddRPCAuditFields(ctx, logrus.Fields{telemetry.TrustDomainID: req.TrustDomain})
+
+	trustDomain, err := spiffeid.TrustDomainFromString(req.TrustDomain)
+	if err != nil {
+		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "failed to parse trust domain", err)
+	}
+	log = log.WithField(telemetry.TrustDomainID, trustDomain.Name())
+
+	options, err := validateOptions(req.Options)
+	if err != nil {
+		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "invalid federation relationship options", err)
+	}
+
+	fr, err := s.ds.FetchFederationRelationship(ctx, trustDomain)
+	if err != nil {
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to fetch federation relationship", err)
+	}
+	if fr == nil {
+		return nil, commonapi.MakeErr(log, codes.NotFound, "federation relationship does not exist", nil)
+	}
+
+	fr, err = s.ds.SetFederationRelationshipOptions(ctx, trustDomain, options)
+	if err != nil {
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to set federation relationship options", err)
+	}
+
+	s.br.TriggerConfigReload()
+
+	log.Debug("Federation relationship options set")
+	rpccontext.AuditRPC(ctx)
+	return &federationv1.SetFederationRelationshipOptionsResponse{
+		Options: fr.Options,
+	}, nil
+}
+
+// validateOptions validates the options and returns them with the pinned key
+// fingerprints normalized.
+func validateOptions(options *common.FederationRelationshipOptions) (*common.FederationRelationshipOptions, error) {
+	if options == nil {
+		return nil, nil
+	}
+	options = proto.Clone(options).(*common.FederationRelationshipOptions)
+
+	if safeguards := options.BundleSafeguards; safeguards != nil {
+		if safeguards.MaxKeys < 0 {
+			return nil, errors.New("bundle safeguards max keys must not be negative")
+		}
+		if safeguards.MinRemainingValidity < 0 {
+			return nil, errors.New("bundle safeguards min remaining validity must not be negative")
+		}
+		for i, pinnedKey := range safeguards.PinnedKeys {
+			fingerprint, err := bundlequarantine.NormalizeFingerprint(pinnedKey)
+			if err != nil {
+				return nil, fmt.Errorf("invalid bundle safeguards pinned key: %w", err)
+			}
+			safeguards.PinnedKeys[i] = fingerprint
+		}
+	}
+
+	return options, nil
+}
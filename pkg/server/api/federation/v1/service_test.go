@@ -0,0 +1,376 @@
+package federation_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/common/bundlequarantine"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/pkg/server/api/federation/v1"
+	"github.com/spiffe/spire/pkg/server/api/middleware"
+	"github.com/spiffe/spire/pkg/server/api/rpccontext"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	federationv1 "github.com/spiffe/spire/proto/private/server/federation"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/spiffe/spire/test/fakes/fakedatastore"
+	"github.com/spiffe/spire/test/grpctest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+var ctx = context.Background()
+
+func TestApproveQuarantinedBundle(t *testing.T) {
+	currentBundle := &common.Bundle{
+		TrustDomainId:  "spiffe://good.test",
+		RootCas:        []*common.Certificate{{DerBytes: []byte("current")}},
+		SequenceNumber: 2,
+	}
+	quarantinedBundle := &common.Bundle{
+		TrustDomainId:  "spiffe://good.test",
+		RootCas:        []*common.Certificate{{DerBytes: []byte("quarantined")}},
+		SequenceNumber: 3,
+	}
+	staleBundle := &common.Bundle{
+		TrustDomainId:  "spiffe://good.test",
+		RootCas:        []*common.Certificate{{DerBytes: []byte("stale")}},
+		SequenceNumber: 1,
+	}
+	digest, err := bundlequarantine.Digest(quarantinedBundle)
+	require.NoError(t, err)
+	staleDigest, err := bundlequarantine.Digest(staleBundle)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name          string
+		trustDomain   string
+		digest        string
+		quarantined   *common.Bundle
+		expectCode    codes.Code
+		expectMsg     string
+		expectApplied bool
+	}{
+		{
+			name:          "success",
+			trustDomain:   "good.test",
+			digest:        digest,
+			quarantined:   quarantinedBundle,
+			expectCode:    codes.OK,
+			expectApplied: true,
+		},
+		{
+			name:        "malformed trust domain",
+			trustDomain: "no a trustdomain",
+			digest:      digest,
+			quarantined: quarantinedBundle,
+			expectCode:  codes.InvalidArgument,
+			expectMsg:   "failed to parse trust domain: trust domain characters are limited to lowercase letters, numbers, dots, dashes, and underscores",
+		},
+		{
+			name:        "missing digest",
+			trustDomain: "good.test",
+			quarantined: quarantinedBundle,
+			expectCode:  codes.InvalidArgument,
+			expectMsg:   "missing quarantined bundle digest",
+		},
+		{
+			name:        "digest mismatch",
+			trustDomain: "good.test",
+			digest:      "deadbeef",
+			quarantined: quarantinedBundle,
+			expectCode:  codes.FailedPrecondition,
+			expectMsg:   `quarantined bundle digest is "` + digest + `"; it may have changed since it was reviewed`,
+		},
+		{
+			name:        "no quarantined bundle",
+			trustDomain: "good.test",
+			digest:      digest,
+			expectCode:  codes.NotFound,
+			expectMsg:   `no quarantined bundle for trust domain "good.test"`,
+		},
+		{
+			name:        "quarantined bundle older than the current bundle",
+			trustDomain: "good.test",
+			digest:      staleDigest,
+			quarantined: staleBundle,
+			expectCode:  codes.FailedPrecondition,
+			expectMsg:   "quarantined bundle sequence number 1 is older than the current bundle sequence number 2",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := fakedatastore.New(t)
+			test := setupServiceTest(t, ds)
+
+			_, err := ds.CreateBundle(ctx, currentBundle)
+			require.NoError(t, err)
+			if tt.quarantined != nil {
+				_, err = ds.SetQuarantinedBundle(ctx, &common.QuarantinedBundle{
+					TrustDomainId: "spiffe://good.test",
+					Bundle:        tt.quarantined,
+					Violations:    []string{"no key overlaps with the current bundle"},
+				})
+				require.NoError(t, err)
+			}
+
+			resp, err := test.client.ApproveQuarantinedBundle(ctx, &federationv1.ApproveQuarantinedBundleRequest{
+				TrustDomain: tt.trustDomain,
+				Digest:      tt.digest,
+			})
+			spiretest.RequireGRPCStatus(t, err, tt.expectCode, tt.expectMsg)
+
+			bundle, err := ds.FetchBundle(ctx, "spiffe://good.test")
+			require.NoError(t, err)
+			quarantined, err := ds.FetchQuarantinedBundle(ctx, "spiffe://good.test")
+			require.NoError(t, err)
+			if !tt.expectApplied {
+				require.Nil(t, resp)
+				spiretest.RequireProtoEqual(t, currentBundle, bundle)
+				if tt.quarantined != nil {
+					spiretest.RequireProtoEqual(t, tt.quarantined, quarantined.Bundle)
+				}
+				return
+			}
+
+			spiretest.RequireProtoEqual(t, quarantinedBundle, resp.Bundle)
+			spiretest.RequireProtoEqual(t, quarantinedBundle, bundle)
+			require.Nil(t, quarantined)
+			spiretest.AssertLastLogs(t, test.logHook.AllEntries(), []spiretest.LogEntry{
+				{
+					Level:   logrus.InfoLevel,
+					Message: "Quarantined bundle approved",
+					Data: logrus.Fields{
+						telemetry.QuarantinedBundleDigest: digest,
+						telemetry.TrustDomainID:           "good.test",
+					},
+				},
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:                  "success",
+						telemetry.QuarantinedBundleDigest: digest,
+						telemetry.TrustDomainID:           "good.test",
+						telemetry.Type:                    "audit",
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestGetFederationRelationshipOptions(t *testing.T) {
+	options := &common.FederationRelationshipOptions{
+		BundleSafeguards: &common.BundleSafeguards{
+			RequireOverlap: true,
+		},
+	}
+
+	for _, tt := range []struct {
+		name          string
+		trustDomain   string
+		expectCode    codes.Code
+		expectMsg     string
+		expectOptions *common.FederationRelationshipOptions
+	}{
+		{
+			name:          "success",
+			trustDomain:   "good.test",
+			expectOptions: options,
+		},
+		{
+			name:        "malformed trust domain",
+			trustDomain: "no a trustdomain",
+			expectCode:  codes.InvalidArgument,
+			expectMsg:   "failed to parse trust domain: trust domain characters are limited to lowercase letters, numbers, dots, dashes, and underscores",
+		},
+		{
+			name:        "no relationship",
+			trustDomain: "other.test",
+			expectCode:  codes.NotFound,
+			expectMsg:   "federation relationship does not exist",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := fakedatastore.New(t)
+			test := setupServiceTest(t, ds)
+			createRelationship(t, ds, options)
+
+			resp, err := test.client.GetFederationRelationshipOptions(ctx, &federationv1.GetFederationRelationshipOptionsRequest{
+				TrustDomain: tt.trustDomain,
+			})
+			spiretest.RequireGRPCStatus(t, err, tt.expectCode, tt.expectMsg)
+			if tt.expectCode != codes.OK {
+				require.Nil(t, resp)
+				return
+			}
+			spiretest.RequireProtoEqual(t, tt.expectOptions, resp.Options)
+		})
+	}
+}
+
+func TestSetFederationRelationshipOptions(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		trustDomain   string
+		options       *common.FederationRelationshipOptions
+		expectCode    codes.Code
+		expectMsg     string
+		expectOptions *common.FederationRelationshipOptions
+	}{
+		{
+			name:        "success",
+			trustDomain: "good.test",
+			options: &common.FederationRelationshipOptions{
+				BundleSafeguards: &common.BundleSafeguards{
+					MaxKeys:              5,
+					MinRemainingValidity: 3600,
+					PinnedKeys:           []string{"SHA256:" + strings.Repeat("AB:", 31) + "AB"},
+				},
+			},
+			expectOptions: &common.FederationRelationshipOptions{
+				BundleSafeguards: &common.BundleSafeguards{
+					MaxKeys:              5,
+					MinRemainingValidity: 3600,
+					PinnedKeys:           []string{strings.Repeat("ab", 32)},
+				},
+			},
+		},
+		{
+			name:        "federation hub",
+			trustDomain: "good.test",
+			options: &common.FederationRelationshipOptions{
+				BundleEndpointHub: true,
+			},
+			expectOptions: &common.FederationRelationshipOptions{
+				BundleEndpointHub: true,
+			},
+		},
+		{
+			name:        "clear options",
+			trustDomain: "good.test",
+		},
+		{
+			name:        "malformed trust domain",
+			trustDomain: "no a trustdomain",
+			expectCode:  codes.InvalidArgument,
+			expectMsg:   "failed to parse trust domain: trust domain characters are limited to lowercase letters, numbers, dots, dashes, and underscores",
+		},
+		{
+			name:        "negative max keys",
+			trustDomain: "good.test",
+			options: &common.FederationRelationshipOptions{
+				BundleSafeguards: &common.BundleSafeguards{MaxKeys: -1},
+			},
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "invalid federation relationship options: bundle safeguards max keys must not be negative",
+		},
+		{
+			name:        "negative min remaining validity",
+			trustDomain: "good.test",
+			options: &common.FederationRelationshipOptions{
+				BundleSafeguards: &common.BundleSafeguards{MinRemainingValidity: -1},
+			},
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "invalid federation relationship options: bundle safeguards min remaining validity must not be negative",
+		},
+		{
+			name:        "invalid pinned key",
+			trustDomain: "good.test",
+			options: &common.FederationRelationshipOptions{
+				BundleSafeguards: &common.BundleSafeguards{PinnedKeys: []string{"nope"}},
+			},
+			expectCode: codes.InvalidArgument,
+			expectMsg:  `invalid federation relationship options: invalid bundle safeguards pinned key: invalid SHA-256 fingerprint "nope"`,
+		},
+		{
+			name:        "no relationship",
+			trustDomain: "other.test",
+			expectCode:  codes.NotFound,
+			expectMsg:   "federation relationship does not exist",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := fakedatastore.New(t)
+			test := setupServiceTest(t, ds)
+			createRelationship(t, ds, &common.FederationRelationshipOptions{
+				BundleSafeguards: &common.BundleSafeguards{RequireOverlap: true},
+			})
+
+			resp, err := test.client.SetFederationRelationshipOptions(ctx, &federationv1.SetFederationRelationshipOptionsRequest{
+				TrustDomain: tt.trustDomain,
+				Options:     tt.options,
+			})
+			spiretest.RequireGRPCStatus(t, err, tt.expectCode, tt.expectMsg)
+			if tt.expectCode != codes.OK {
+				require.Nil(t, resp)
+				require.False(t, test.br.reloaded)
+				return
+			}
+			spiretest.RequireProtoEqual(t, tt.expectOptions, resp.Options)
+			require.True(t, test.br.reloaded)
+
+			fr, err := ds.FetchFederationRelationship(ctx, spiffeid.RequireTrustDomainFromString(tt.trustDomain))
+			require.NoError(t, err)
+			spiretest.RequireProtoEqual(t, tt.expectOptions, fr.Options)
+		})
+	}
+}
+
+func createRelationship(t *testing.T, ds *fakedatastore.DataStore, options *common.FederationRelationshipOptions) {
+	_, err := ds.CreateFederationRelationship(ctx, &datastore.FederationRelationship{
+		TrustDomain:           spiffeid.RequireTrustDomainFromString("good.test"),
+		BundleEndpointURL:     &url.URL{Scheme: "https", Host: "good.test", Path: "/bundle"},
+		BundleEndpointProfile: datastore.BundleEndpointWeb,
+		Options:               options,
+	})
+	require.NoError(t, err)
+}
+
+type serviceTest struct {
+	client  federationv1.FederationClient
+	br      *fakeBundleRefresher
+	logHook *test.Hook
+}
+
+func setupServiceTest(t *testing.T, ds *fakedatastore.DataStore) *serviceTest {
+	br := &fakeBundleRefresher{}
+	service := federation.New(federation.Config{
+		DataStore:       ds,
+		BundleRefresher: br,
+	})
+
+	log, logHook := test.NewNullLogger()
+	log.Level = logrus.DebugLevel
+
+	overrideContext := func(ctx context.Context) context.Context {
+		return rpccontext.WithLogger(ctx, log)
+	}
+
+	server := grpctest.StartServer(t, func(s grpc.ServiceRegistrar) {
+		federation.RegisterService(s, service)
+	},
+		grpctest.OverrideContext(overrideContext),
+		grpctest.Middleware(middleware.WithAuditLog(false)),
+	)
+
+	return &serviceTest{
+		client:  federationv1.NewFederationClient(server.NewGRPCClient(t)),
+		br:      br,
+		logHook: logHook,
+	}
+}
+
+type fakeBundleRefresher struct {
+	reloaded bool
+}
+
+func (r *fakeBundleRefresher) TriggerConfigReload() {
+	r.reloaded = true
+}
@@ -0,0 +1,195 @@
+package jointoken
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/andres-erbsen/clock"
+	"github.com/gofrs/uuid/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/spiffe/spire/pkg/common/agentpathtemplate"
+	commonapi "github.com/spiffe/spire/pkg/common/api"
+	"github.com/spiffe/spire/pkg/common/selector"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/pkg/server/api/rpccontext"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	jointokenv1 "github.com/spiffe/spire/proto/private/server/jointoken"
+	"github.com/spiffe/spire/proto/spire/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// RegisterService registers the join token service on the gRPC server.
+func RegisterService(s grpc.ServiceRegistrar, service *Service) {
+	jointokenv1.RegisterJoinTokenServer(s, service)
+}
+
+// Config is the service configuration.
+type Config struct {
+	DataStore datastore.DataStore
+	Clock     clock.Clock
+}
+
+// New creates a new join token service.
+func New(config Config) *Service {
+	return &Service{
+		ds:  config.DataStore,
+		clk: config.Clock,
+	}
+}
+
+// Service implements the join token service.
+type Service struct {
+	jointokenv1.UnsafeJoinTokenServer
+
+	ds  datastore.DataStore
+	clk clock.Clock
+}
+
+// CreateJoinToken creates a join token.
+func (s *Service) CreateJoinToken(ctx context.Context, req *jointokenv1.CreateJoinTokenRequest) (*jointokenv1.Token, error) {
+	log := rpccontext.Logger(ctx)
+	rpccontext.AddRPCAuditFields(ctx, logrus.Fields{
+		telemetry.TTL:     req.Ttl,
+		telemetry.MaxUses: req.MaxUses,
+	})
+
+	if isScoped(ctx) {
+		return nil, commonapi.MakeErr(log, codes.PermissionDenied, "scoped callers cannot manage join tokens", nil)
+	}
+
+	if req.Ttl < 1 {
+		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "ttl is required, you must provide one", nil)
+	}
+	for _, s := range req.Selectors {
+		if err := validateSelector(s); err != nil {
+			return nil, commonapi.MakeErr(log, codes.InvalidArgument, "invalid selector", err)
+		}
+	}
+	if req.AgentPathTemplate != "" {
+		if _, err := agentpathtemplate.Parse(req.AgentPathTemplate); err != nil {
+			return nil, commonapi.MakeErr(log, codes.InvalidArgument, "invalid agent path template", err)
+		}
+	}
+
+	// Generate a token if one wasn't specified
+	token := req.Token
+	if token == "" {
+		u, err := uuid.NewV4()
+		if err != nil {
+			return nil, commonapi.MakeErr(log, codes.Internal, "failed to generate token UUID", err)
+		}
+		token = u.String()
+	}
+
+	maxUses := req.MaxUses
+	if maxUses < 0 {
+		maxUses = datastore.UnlimitedJoinTokenUses
+	}
+
+	joinToken := &datastore.JoinToken{
+		Token:             token,
+		Expiry:            s.clk.Now().Add(time.Second * time.Duration(req.Ttl)),
+		MaxUses:           maxUses,
+		Selectors:         req.Selectors,
+		AgentPathTemplate: req.AgentPathTemplate,
+	}
+	if err := s.ds.CreateJoinToken(ctx, joinToken); err != nil {
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to create token", err)
+	}
+
+	rpccontext.AuditRPC(ctx)
+	return tokenToProto(joinToken), nil
+}
+
+// ListJoinTokens lists the join tokens that have not been used up.
+func (s *Service) ListJoinTokens(ctx context.Context, req *jointokenv1.ListJoinTokensRequest) (*jointokenv1.ListJoinTokensResponse, error) {
+	log := rpccontext.Logger(ctx)
+
+	if isScoped(ctx) {
+		return nil, commonapi.MakeErr(log, codes.PermissionDenied, "scoped callers cannot manage join tokens", nil)
+	}
+
+	listReq := &datastore.ListJoinTokensRequest{}
+	if req.PageSize > 0 {
+		listReq.Pagination = &datastore.Pagination{
+			PageSize: req.PageSize,
+			Token:    req.PageToken,
+		}
+	}
+
+	dsResp, err := s.ds.ListJoinTokens(ctx, listReq)
+	if err != nil {
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to list tokens", err)
+	}
+
+	resp := &jointokenv1.ListJoinTokensResponse{}
+	if dsResp.Pagination != nil {
+		resp.NextPageToken = dsResp.Pagination.Token
+	}
+	for _, joinToken := range dsResp.JoinTokens {
+		resp.Tokens = append(resp.Tokens, tokenToProto(joinToken))
+	}
+
+	rpccontext.AuditRPC(ctx)
+	return resp, nil
+}
+
+// RevokeJoinToken deletes a join token so it cannot be used to attest more
+// agents. Agents already attested with the token are not affected.
+func (s *Service) RevokeJoinToken(ctx context.Context, req *jointokenv1.RevokeJoinTokenRequest) (*jointokenv1.RevokeJoinTokenResponse, error) {
+	log := rpccontext.Logger(ctx)
+
+	if isScoped(ctx) {
+		return nil, commonapi.MakeErr(log, codes.PermissionDenied, "scoped callers cannot manage join tokens", nil)
+	}
+
+	if req.Token == "" {
+		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "missing token", nil)
+	}
+
+	joinToken, err := s.ds.FetchJoinToken(ctx, req.Token)
+	switch {
+	case err != nil:
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to fetch token", err)
+	case joinToken == nil:
+		return nil, commonapi.MakeErr(log, codes.NotFound, "token does not exist or has already been used", nil)
+	}
+
+	if err := s.ds.DeleteJoinToken(ctx, req.Token); err != nil {
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to revoke token", err)
+	}
+
+	log.Info("Join token revoked")
+	rpccontext.AuditRPC(ctx)
+	return &jointokenv1.RevokeJoinTokenResponse{}, nil
+}
+
+func validateSelector(s *common.Selector) error {
+	switch {
+	case s.Type == "":
+		return errors.New("missing selector type")
+	case s.Value == "":
+		return errors.New("missing selector value")
+	}
+	return selector.Validate(s)
+}
+
+// isScoped returns true if the caller is limited to some SPIFFE IDs. Such
+// callers cannot manage join tokens, since the IDs of the agents attested
+// with a join token are not known in advance.
+func isScoped(ctx context.Context) bool {
+	return len(rpccontext.CallerScopes(ctx)) > 0
+}
+
+func tokenToProto(joinToken *datastore.JoinToken) *jointokenv1.Token {
+	return &jointokenv1.Token{
+		Value:             joinToken.Token,
+		ExpiresAt:         joinToken.Expiry.Unix(),
+		MaxUses:           joinToken.MaxUses,
+		UseCount:          joinToken.UseCount,
+		Selectors:         joinToken.Selectors,
+		AgentPathTemplate: joinToken.AgentPathTemplate,
+	}
+}
@@ -0,0 +1,255 @@
+package jointoken_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/server/api/jointoken/v1"
+	"github.com/spiffe/spire/pkg/server/api/middleware"
+	"github.com/spiffe/spire/pkg/server/api/rpccontext"
+	"github.com/spiffe/spire/pkg/server/authpolicy"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	jointokenv1 "github.com/spiffe/spire/proto/private/server/jointoken"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/spiffe/spire/test/clock"
+	"github.com/spiffe/spire/test/fakes/fakedatastore"
+	"github.com/spiffe/spire/test/grpctest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+var ctx = context.Background()
+
+func TestCreateJoinToken(t *testing.T) {
+	selectors := []*common.Selector{{Type: "type", Value: "value"}}
+
+	for _, tt := range []struct {
+		name         string
+		req          *jointokenv1.CreateJoinTokenRequest
+		callerScopes authpolicy.Scopes
+		expectCode   codes.Code
+		expectMsg    string
+		expectToken  *datastore.JoinToken
+	}{
+		{
+			name: "success",
+			req: &jointokenv1.CreateJoinTokenRequest{
+				Token:             "token",
+				Ttl:               60,
+				MaxUses:           3,
+				Selectors:         selectors,
+				AgentPathTemplate: "/{{ .UUID }}",
+			},
+			expectToken: &datastore.JoinToken{
+				Token:             "token",
+				MaxUses:           3,
+				Selectors:         selectors,
+				AgentPathTemplate: "/{{ .UUID }}",
+			},
+		},
+		{
+			name: "unlimited uses",
+			req: &jointokenv1.CreateJoinTokenRequest{
+				Token:   "token",
+				Ttl:     60,
+				MaxUses: -5,
+			},
+			expectToken: &datastore.JoinToken{
+				Token:   "token",
+				MaxUses: datastore.UnlimitedJoinTokenUses,
+			},
+		},
+		{
+			name:       "missing ttl",
+			req:        &jointokenv1.CreateJoinTokenRequest{Token: "token"},
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "ttl is required, you must provide one",
+		},
+		{
+			name: "invalid selector",
+			req: &jointokenv1.CreateJoinTokenRequest{
+				Token:     "token",
+				Ttl:       60,
+				Selectors: []*common.Selector{{Type: "type"}},
+			},
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "invalid selector: missing selector value",
+		},
+		{
+			name: "selector type with a colon",
+			req: &jointokenv1.CreateJoinTokenRequest{
+				Token:     "token",
+				Ttl:       60,
+				Selectors: []*common.Selector{{Type: "unix:uid", Value: "1000"}},
+			},
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "invalid selector: selector type must not contain a colon",
+		},
+		{
+			name: "invalid agent path template",
+			req: &jointokenv1.CreateJoinTokenRequest{
+				Token:             "token",
+				Ttl:               60,
+				AgentPathTemplate: "{{ .UUID",
+			},
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "invalid agent path template",
+		},
+		{
+			name:         "scoped caller",
+			req:          &jointokenv1.CreateJoinTokenRequest{Token: "token", Ttl: 60},
+			callerScopes: authpolicy.Scopes{{SPIFFEIDPrefix: spiffeid.RequireFromString("spiffe://example.org/team-a")}},
+			expectCode:   codes.PermissionDenied,
+			expectMsg:    "scoped callers cannot manage join tokens",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			test := setupServiceTest(t, tt.callerScopes)
+
+			resp, err := test.client.CreateJoinToken(ctx, tt.req)
+			if tt.expectCode != codes.OK {
+				require.Error(t, err)
+				spiretest.RequireGRPCStatusHasPrefix(t, err, tt.expectCode, tt.expectMsg)
+				require.Nil(t, resp)
+				return
+			}
+			require.NoError(t, err)
+
+			expiry := test.clk.Now().Add(time.Minute)
+			spiretest.RequireProtoEqual(t, &jointokenv1.Token{
+				Value:             tt.expectToken.Token,
+				ExpiresAt:         expiry.Unix(),
+				MaxUses:           tt.expectToken.MaxUses,
+				Selectors:         tt.expectToken.Selectors,
+				AgentPathTemplate: tt.expectToken.AgentPathTemplate,
+			}, resp)
+
+			joinToken, err := test.ds.FetchJoinToken(ctx, tt.expectToken.Token)
+			require.NoError(t, err)
+			require.Equal(t, expiry.Unix(), joinToken.Expiry.Unix())
+			require.Equal(t, tt.expectToken.MaxUses, joinToken.MaxUses)
+			require.Equal(t, tt.expectToken.AgentPathTemplate, joinToken.AgentPathTemplate)
+			spiretest.RequireProtoListEqual(t, tt.expectToken.Selectors, joinToken.Selectors)
+		})
+	}
+}
+
+func TestCreateJoinTokenGeneratesToken(t *testing.T) {
+	test := setupServiceTest(t, nil)
+
+	resp, err := test.client.CreateJoinToken(ctx, &jointokenv1.CreateJoinTokenRequest{Ttl: 60})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Value)
+
+	joinToken, err := test.ds.FetchJoinToken(ctx, resp.Value)
+	require.NoError(t, err)
+	require.NotNil(t, joinToken)
+}
+
+func TestListJoinTokens(t *testing.T) {
+	test := setupServiceTest(t, nil)
+	expiry := test.clk.Now().Add(time.Hour)
+	for _, joinToken := range []*datastore.JoinToken{
+		{Token: "token1", Expiry: expiry},
+		{Token: "token2", Expiry: expiry, MaxUses: 5, UseCount: 2, AgentPathTemplate: "/{{ .UUID }}"},
+	} {
+		require.NoError(t, test.ds.CreateJoinToken(ctx, joinToken))
+	}
+
+	resp, err := test.client.ListJoinTokens(ctx, &jointokenv1.ListJoinTokensRequest{})
+	require.NoError(t, err)
+	spiretest.RequireProtoEqual(t, &jointokenv1.ListJoinTokensResponse{
+		Tokens: []*jointokenv1.Token{
+			{Value: "token1", ExpiresAt: expiry.Unix()},
+			{Value: "token2", ExpiresAt: expiry.Unix(), MaxUses: 5, UseCount: 2, AgentPathTemplate: "/{{ .UUID }}"},
+		},
+	}, resp)
+
+	// Paginated
+	resp, err = test.client.ListJoinTokens(ctx, &jointokenv1.ListJoinTokensRequest{PageSize: 1})
+	require.NoError(t, err)
+	require.Len(t, resp.Tokens, 1)
+	require.NotEmpty(t, resp.NextPageToken)
+}
+
+func TestRevokeJoinToken(t *testing.T) {
+	test := setupServiceTest(t, nil)
+	require.NoError(t, test.ds.CreateJoinToken(ctx, &datastore.JoinToken{
+		Token:  "token",
+		Expiry: test.clk.Now().Add(time.Hour),
+	}))
+
+	_, err := test.client.RevokeJoinToken(ctx, &jointokenv1.RevokeJoinTokenRequest{})
+	spiretest.RequireGRPCStatus(t, err, codes.InvalidArgument, "missing token")
+
+	_, err = test.client.RevokeJoinToken(ctx, &jointokenv1.RevokeJoinTokenRequest{Token: "unknown"})
+	spiretest.RequireGRPCStatus(t, err, codes.NotFound, "token does not exist or has already been used")
+
+	_, err = test.client.RevokeJoinToken(ctx, &jointokenv1.RevokeJoinTokenRequest{Token: "token"})
+	require.NoError(t, err)
+
+	joinToken, err := test.ds.FetchJoinToken(ctx, "token")
+	require.NoError(t, err)
+	require.Nil(t, joinToken)
+	spiretest.AssertLastLogs(t, test.logHook.AllEntries(), []spiretest.LogEntry{
+		{
+			Level:   logrus.InfoLevel,
+			Message: "Join token revoked",
+		},
+		{
+			Level:   logrus.InfoLevel,
+			Message: "API accessed",
+			Data: logrus.Fields{
+				"status": "success",
+				"type":   "audit",
+			},
+		},
+	})
+}
+
+type serviceTest struct {
+	client  jointokenv1.JoinTokenClient
+	ds      *fakedatastore.DataStore
+	clk     *clock.Mock
+	logHook *test.Hook
+}
+
+func setupServiceTest(t *testing.T, callerScopes authpolicy.Scopes) *serviceTest {
+	ds := fakedatastore.New(t)
+	clk := clock.NewMock(t)
+	service := jointoken.New(jointoken.Config{
+		DataStore: ds,
+		Clock:     clk,
+	})
+
+	log, logHook := test.NewNullLogger()
+	log.Level = logrus.DebugLevel
+
+	overrideContext := func(ctx context.Context) context.Context {
+		ctx = rpccontext.WithLogger(ctx, log)
+		if callerScopes != nil {
+			ctx = rpccontext.WithCallerScopes(ctx, callerScopes)
+		}
+		return ctx
+	}
+
+	server := grpctest.StartServer(t, func(s grpc.ServiceRegistrar) {
+		jointoken.RegisterService(s, service)
+	},
+		grpctest.OverrideContext(overrideContext),
+		grpctest.Middleware(middleware.WithAuditLog(false)),
+	)
+
+	return &serviceTest{
+		client:  jointokenv1.NewJoinTokenClient(server.NewGRPCClient(t)),
+		ds:      ds,
+		clk:     clk,
+		logHook: logHook,
+	}
+}
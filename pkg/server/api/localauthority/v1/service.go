@@ -27,6 +27,13 @@ type CAManager interface {
 	RotateJWTKey(ctx context.Context)
 	IsJWTSVIDsDisabled() bool
 
+	// WIT
+	GetCurrentWITKeySlot() manager.Slot
+	GetNextWITKeySlot() manager.Slot
+	PrepareWITKey(ctx context.Context) error
+	RotateWITKey(ctx context.Context)
+	IsWITSVIDsDisabled() bool
+
 	// X509
 	GetCurrentX509CASlot() manager.Slot
 	GetNextX509CASlot() manager.Slot
@@ -505,33 +512,199 @@ func (s *Service) RevokeX509UpstreamAuthority(ctx context.Context, req *localaut
 
 func (s *Service) GetWITAuthorityState(ctx context.Context, _ *localauthorityv1.GetWITAuthorityStateRequest) (*localauthorityv1.GetWITAuthorityStateResponse, error) {
 	log := rpccontext.Logger(ctx)
-	return nil, commonapi.MakeErr(log, codes.Unimplemented, "WIT-SVID functionality is not yet implemented", nil)
+	if s.isWITSVIDsDisabled() {
+		return nil, commonapi.MakeErr(log, codes.Unimplemented, "WIT functionality is disabled", nil)
+	}
+
+	current := s.ca.GetCurrentWITKeySlot()
+	switch {
+	case current.Status() != journal.Status_ACTIVE:
+		return nil, commonapi.MakeErr(log, codes.Unavailable, "server is initializing", nil)
+	case current.AuthorityID() == "":
+		return nil, commonapi.MakeErr(log, codes.Internal, "current slot does not contain authority ID", nil)
+	}
+
+	resp := &localauthorityv1.GetWITAuthorityStateResponse{
+		Active: stateFromSlot(current),
+	}
+
+	next := s.ca.GetNextWITKeySlot()
+
+	// when next has a key indicates that it was initialized
+	if next.AuthorityID() != "" {
+		switch next.Status() {
+		case journal.Status_OLD:
+			resp.Old = stateFromSlot(next)
+		case journal.Status_PREPARED:
+			resp.Prepared = stateFromSlot(next)
+		case journal.Status_UNKNOWN:
+			log.WithField(telemetry.LocalAuthorityID, next.AuthorityID()).Error("Slot has an unknown status")
+		}
+	}
+
+	rpccontext.AuditRPC(ctx)
+
+	return resp, nil
 }
 
 func (s *Service) PrepareWITAuthority(ctx context.Context, _ *localauthorityv1.PrepareWITAuthorityRequest) (*localauthorityv1.PrepareWITAuthorityResponse, error) {
 	log := rpccontext.Logger(ctx)
-	return nil, commonapi.MakeErr(log, codes.Unimplemented, "WIT-SVID functionality is not yet implemented", nil)
+	if s.isWITSVIDsDisabled() {
+		return nil, commonapi.MakeErr(log, codes.Unimplemented, "WIT functionality is disabled", nil)
+	}
+
+	current := s.ca.GetCurrentWITKeySlot()
+	if current.Status() != journal.Status_ACTIVE {
+		return nil, commonapi.MakeErr(log, codes.Unavailable, "server is initializing", nil)
+	}
+
+	if err := s.ca.PrepareWITKey(ctx); err != nil {
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to prepare WIT authority", err)
+	}
+
+	slot := s.ca.GetNextWITKeySlot()
+
+	rpccontext.AuditRPC(ctx)
+
+	return &localauthorityv1.PrepareWITAuthorityResponse{
+		PreparedAuthority: &localauthorityv1.AuthorityState{
+			AuthorityId: slot.AuthorityID(),
+			ExpiresAt:   slot.NotAfter().Unix(),
+		},
+	}, nil
 }
 
 func (s *Service) ActivateWITAuthority(ctx context.Context, req *localauthorityv1.ActivateWITAuthorityRequest) (*localauthorityv1.ActivateWITAuthorityResponse, error) {
+	rpccontext.AddRPCAuditFields(ctx, buildAuditLogFields(req.AuthorityId))
 	log := rpccontext.Logger(ctx)
-	return nil, commonapi.MakeErr(log, codes.Unimplemented, "WIT-SVID functionality is not yet implemented", nil)
+
+	if s.isWITSVIDsDisabled() {
+		return nil, commonapi.MakeErr(log, codes.Unimplemented, "WIT functionality is disabled", nil)
+	}
+
+	if req.AuthorityId != "" {
+		log = log.WithField(telemetry.LocalAuthorityID, req.AuthorityId)
+	}
+
+	nextSlot := s.ca.GetNextWITKeySlot()
+
+	switch {
+	// Authority ID is required
+	case req.AuthorityId == "":
+		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "no authority ID provided", nil)
+
+	/// Only next local authority can be Activated
+	case req.AuthorityId != nextSlot.AuthorityID():
+		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "unexpected authority ID", nil)
+
+	// Only PREPARED local authorities can be Activated
+	case nextSlot.Status() != journal.Status_PREPARED:
+		return nil, commonapi.MakeErr(log, codes.Internal, "only Prepared authorities can be activated", fmt.Errorf("unsupported local authority status: %v", nextSlot.Status()))
+	}
+
+	s.ca.RotateWITKey(ctx)
+
+	current := s.ca.GetCurrentWITKeySlot()
+	state := &localauthorityv1.AuthorityState{
+		AuthorityId: current.AuthorityID(),
+		ExpiresAt:   current.NotAfter().Unix(),
+	}
+	rpccontext.AuditRPC(ctx)
+
+	return &localauthorityv1.ActivateWITAuthorityResponse{
+		ActivatedAuthority: state,
+	}, nil
 }
 
 func (s *Service) TaintWITAuthority(ctx context.Context, req *localauthorityv1.TaintWITAuthorityRequest) (*localauthorityv1.TaintWITAuthorityResponse, error) {
+	rpccontext.AddRPCAuditFields(ctx, buildAuditLogFields(req.AuthorityId))
 	log := rpccontext.Logger(ctx)
-	return nil, commonapi.MakeErr(log, codes.Unimplemented, "WIT-SVID functionality is not yet implemented", nil)
+
+	if s.isWITSVIDsDisabled() {
+		return nil, commonapi.MakeErr(log, codes.Unimplemented, "WIT functionality is disabled", nil)
+	}
+
+	if req.AuthorityId != "" {
+		log = log.WithField(telemetry.LocalAuthorityID, req.AuthorityId)
+	}
+
+	nextSlot := s.ca.GetNextWITKeySlot()
+
+	switch {
+	// Authority ID is required
+	case req.AuthorityId == "":
+		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "no authority ID provided", nil)
+
+	// It is not possible to taint Active authority
+	case req.AuthorityId == s.ca.GetCurrentWITKeySlot().AuthorityID():
+		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "unable to taint current local authority", nil)
+
+	// Only next local authority can be tainted
+	case req.AuthorityId != nextSlot.AuthorityID():
+		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "unexpected authority ID", nil)
+
+	// Only OLD authorities can be tainted
+	case nextSlot.Status() != journal.Status_OLD:
+		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "only Old local authorities can be tainted", fmt.Errorf("unsupported local authority status: %v", nextSlot.Status()))
+	}
+
+	if _, err := s.ds.TaintWITKey(ctx, s.td.IDString(), nextSlot.AuthorityID()); err != nil {
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to taint WIT authority", err)
+	}
+
+	state := &localauthorityv1.AuthorityState{
+		AuthorityId: nextSlot.AuthorityID(),
+	}
+
+	rpccontext.AuditRPC(ctx)
+	log.Info("WIT authority tainted successfully")
+
+	return &localauthorityv1.TaintWITAuthorityResponse{
+		TaintedAuthority: state,
+	}, nil
 }
 
 func (s *Service) RevokeWITAuthority(ctx context.Context, req *localauthorityv1.RevokeWITAuthorityRequest) (*localauthorityv1.RevokeWITAuthorityResponse, error) {
+	rpccontext.AddRPCAuditFields(ctx, buildAuditLogFields(req.AuthorityId))
 	log := rpccontext.Logger(ctx)
-	return nil, commonapi.MakeErr(log, codes.Unimplemented, "WIT-SVID functionality is not yet implemented", nil)
+	if s.isWITSVIDsDisabled() {
+		return nil, commonapi.MakeErr(log, codes.Unimplemented, "WIT functionality is disabled", nil)
+	}
+
+	authorityID := req.AuthorityId
+
+	if err := s.validateWITAuthorityID(ctx, authorityID); err != nil {
+		if req.AuthorityId != "" {
+			log = log.WithField(telemetry.LocalAuthorityID, req.AuthorityId)
+		}
+		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "invalid authority ID", err)
+	}
+
+	log = log.WithField(telemetry.LocalAuthorityID, authorityID)
+	if _, err := s.ds.RevokeWITKey(ctx, s.td.IDString(), authorityID); err != nil {
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to revoke WIT authority", err)
+	}
+
+	state := &localauthorityv1.AuthorityState{
+		AuthorityId: authorityID,
+	}
+
+	rpccontext.AuditRPC(ctx)
+	log.Info("WIT authority revoked successfully")
+
+	return &localauthorityv1.RevokeWITAuthorityResponse{
+		RevokedAuthority: state,
+	}, nil
 }
 
 func (s *Service) isJWTSVIDsDisabled() bool {
 	return s.ca.IsJWTSVIDsDisabled()
 }
 
+func (s *Service) isWITSVIDsDisabled() bool {
+	return s.ca.IsWITSVIDsDisabled()
+}
+
 // validateLocalAuthorityID validates provided authority ID, and return OLD associated public key
 func (s *Service) validateLocalAuthorityID(authorityID string) error {
 	nextSlot := s.ca.GetNextX509CASlot()
@@ -605,6 +778,40 @@ func (s *Service) validateAuthorityID(ctx context.Context, authorityID string) e
 	return errors.New("no JWT authority found with provided authority ID")
 }
 
+// validateWITAuthorityID validates provided WIT authority ID
+func (s *Service) validateWITAuthorityID(ctx context.Context, authorityID string) error {
+	if authorityID == "" {
+		return errors.New("no authority ID provided")
+	}
+
+	nextSlot := s.ca.GetNextWITKeySlot()
+	if authorityID == nextSlot.AuthorityID() {
+		if nextSlot.Status() == journal.Status_PREPARED {
+			return errors.New("unable to use a prepared key")
+		}
+
+		return nil
+	}
+
+	currentSlot := s.ca.GetCurrentWITKeySlot()
+	if currentSlot.AuthorityID() == authorityID {
+		return errors.New("unable to use current authority")
+	}
+
+	bundle, err := s.ds.FetchBundle(ctx, s.td.IDString())
+	if err != nil {
+		return err
+	}
+
+	for _, witAuthority := range bundle.WitSigningKeys {
+		if witAuthority.Kid == authorityID {
+			return nil
+		}
+	}
+
+	return errors.New("no WIT authority found with provided authority ID")
+}
+
 func buildAuditLogFields(authorityID string) logrus.Fields {
 	fields := logrus.Fields{}
 	if authorityID != "" {
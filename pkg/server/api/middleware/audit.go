@@ -19,14 +19,32 @@ func WithAuditLog(localTrackerEnabled bool) Middleware {
 	}
 }
 
+// WithAuditLogger is like WithAuditLog, but audit entries are emitted to the
+// given logger instead of the RPC logger. The fields of the RPC logger are
+// carried over to the audit entries.
+func WithAuditLogger(localTrackerEnabled bool, logger *logrus.Logger) Middleware {
+	return auditLogMiddleware{
+		localTrackerEnabled: localTrackerEnabled,
+		logger:              logger,
+	}
+}
+
 type auditLogMiddleware struct {
 	Middleware
 
 	localTrackerEnabled bool
+	logger              *logrus.Logger
 }
 
 func (m auditLogMiddleware) Preprocess(ctx context.Context, _ string, _ any) (context.Context, error) {
 	log := rpccontext.Logger(ctx)
+	if m.logger != nil {
+		if entry, ok := log.(*logrus.Entry); ok {
+			log = m.logger.WithFields(entry.Data)
+		} else {
+			log = m.logger
+		}
+	}
 	if rpccontext.CallerIsLocal(ctx) && m.localTrackerEnabled {
 		fields, err := fieldsFromTracker(ctx)
 		if err != nil {
@@ -58,6 +76,7 @@ func fieldsFromTracker(ctx context.Context) (logrus.Fields, error) {
 		return nil, status.Error(codes.Internal, "failed to get peertracker")
 	}
 	pID := watcher.PID()
+	fields[telemetry.CallerPID] = pID
 
 	p, err := process.NewProcess(pID)
 	if err != nil {
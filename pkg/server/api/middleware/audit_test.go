@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/pkg/server/api/rpccontext"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuditLog(t *testing.T) {
+	log, logHook := test.NewNullLogger()
+	ctx := rpccontext.WithLogger(context.Background(), log.WithField(telemetry.Method, "SomeMethod"))
+
+	m := WithAuditLog(false)
+	ctx, err := m.Preprocess(ctx, "", nil)
+	require.NoError(t, err)
+	m.Postprocess(ctx, "", true, status.Error(codes.PermissionDenied, "denied"))
+
+	spiretest.AssertLogs(t, logHook.AllEntries(), []spiretest.LogEntry{
+		{
+			Level:   logrus.InfoLevel,
+			Message: "API accessed",
+			Data: logrus.Fields{
+				telemetry.Method:        "SomeMethod",
+				telemetry.Status:        "error",
+				telemetry.StatusCode:    "PermissionDenied",
+				telemetry.StatusMessage: "denied",
+				telemetry.Type:          "audit",
+			},
+		},
+	})
+}
+
+func TestAuditLogger(t *testing.T) {
+	log, logHook := test.NewNullLogger()
+	auditLog, auditLogHook := test.NewNullLogger()
+	ctx := rpccontext.WithLogger(context.Background(), log.WithField(telemetry.Method, "SomeMethod"))
+
+	m := WithAuditLogger(false, auditLog)
+	ctx, err := m.Preprocess(ctx, "", nil)
+	require.NoError(t, err)
+	auditLogger, ok := rpccontext.AuditLog(ctx)
+	require.True(t, ok)
+	auditLogger.Audit()
+
+	// Audit entries carry the RPC logger fields but are not emitted to it
+	require.Empty(t, logHook.AllEntries())
+	spiretest.AssertLogs(t, auditLogHook.AllEntries(), []spiretest.LogEntry{
+		{
+			Level:   logrus.InfoLevel,
+			Message: "API accessed",
+			Data: logrus.Fields{
+				telemetry.Method: "SomeMethod",
+				telemetry.Status: "success",
+				telemetry.Type:   "audit",
+			},
+		},
+	})
+}
@@ -29,9 +29,10 @@ func (m *authorizationMiddleware) opaAuth(ctx context.Context, req any, fullMeth
 	}
 
 	input := authpolicy.Input{
-		Caller:     spiffeID,
-		FullMethod: fullMethod,
-		Req:        req,
+		Caller:        spiffeID,
+		CallerIsLocal: rpccontext.CallerIsLocal(ctx),
+		FullMethod:    fullMethod,
+		Req:           req,
 	}
 
 	if input.Caller == "" {
@@ -57,6 +58,9 @@ func (m *authorizationMiddleware) opaAuth(ctx context.Context, req any, fullMeth
 
 func (m *authorizationMiddleware) reconcileResult(ctx context.Context, res authpolicy.Result) (context.Context, bool, error) {
 	ctx = setAuthorizationLogFields(ctx, "nobody", "")
+	if len(res.Scopes) > 0 {
+		ctx = rpccontext.WithCallerScopes(ctx, res.Scopes)
+	}
 
 	// Check things in order of cost
 	if res.Allow {
@@ -13,6 +13,8 @@ import (
 	"github.com/open-policy-agent/opa/v1/storage/inmem"
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	bundlev1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/bundle/v1"
+	entryv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/entry/v1"
 	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
 	"github.com/spiffe/spire/pkg/server/api/middleware"
 	"github.com/spiffe/spire/pkg/server/api/rpccontext"
@@ -371,6 +373,98 @@ func TestWithAuthorizationPreprocess(t *testing.T) {
 	}
 }
 
+func TestWithAuthorizationScopes(t *testing.T) {
+	ctx := context.Background()
+	rego := `
+    package spire
+    result = {
+      "allow": false,
+      "allow_if_admin": true,
+      "allow_if_local": true,
+      "allow_if_downstream": false,
+      "allow_if_agent": false,
+      "scopes": scopes
+    }
+    default scopes = []
+    scopes = [{"spiffe_id_prefix": "spiffe://example.org/team-a"}] if {
+        input.caller == "spiffe://example.org/admin"
+    }
+    scopes = [{"parent_id": "spiffe://example.org/node"}] if {
+        input.caller_is_local
+    }
+    `
+	policyEngine, err := authpolicy.NewEngineFromRego(ctx, rego, inmem.NewFromObject(map[string]any{}))
+	require.NoError(t, err)
+	m := middleware.WithAuthorization(policyEngine, entryFetcher, noAgentAuthorizer, []spiffeid.ID{staticAdminID})
+
+	peerWithID := func(id spiffeid.ID) *peer.Peer {
+		return &peer.Peer{
+			Addr: &net.TCPAddr{IP: net.ParseIP("2.2.2.2"), Port: 2},
+			AuthInfo: credentials.TLSInfo{
+				State: tls.ConnectionState{
+					HandshakeComplete: true,
+					PeerCertificates:  []*x509.Certificate{{URIs: []*url.URL{id.URL()}}},
+				},
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name         string
+		peer         *peer.Peer
+		fullMethod   string
+		expectScopes authpolicy.Scopes
+		expectErr    string
+	}{
+		{
+			name:       "scoped admin",
+			peer:       peerWithID(adminID),
+			fullMethod: entryv1.Entry_ListEntries_FullMethodName,
+			expectScopes: authpolicy.Scopes{
+				{SPIFFEIDPrefix: spiffeid.RequireFromString("spiffe://example.org/team-a")},
+			},
+		},
+		{
+			name:       "scoped admin calling a method without scopes",
+			peer:       peerWithID(adminID),
+			fullMethod: bundlev1.Bundle_BatchSetFederatedBundle_FullMethodName,
+			expectErr:  "authorization denied for method " + bundlev1.Bundle_BatchSetFederatedBundle_FullMethodName,
+		},
+		{
+			name:       "unscoped admin",
+			peer:       peerWithID(staticAdminID),
+			fullMethod: bundlev1.Bundle_BatchSetFederatedBundle_FullMethodName,
+		},
+		{
+			name:       "scoped local caller",
+			peer:       &peer.Peer{Addr: &net.UnixAddr{Net: "unix", Name: "/not/a/real/path.sock"}},
+			fullMethod: entryv1.Entry_BatchCreateEntry_FullMethodName,
+			expectScopes: authpolicy.Scopes{
+				{ParentID: spiffeid.RequireFromString("spiffe://example.org/node")},
+			},
+		},
+		{
+			name:       "scoped local caller calling a method without scopes",
+			peer:       &peer.Peer{Addr: &net.UnixAddr{Net: "unix", Name: "/not/a/real/path.sock"}},
+			fullMethod: fakeFullMethod,
+			expectErr:  "authorization denied for method " + fakeFullMethod,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			log, _ := test.NewNullLogger()
+			ctxIn := peer.NewContext(rpccontext.WithLogger(ctx, log), tt.peer)
+
+			ctxOut, err := m.Preprocess(ctxIn, tt.fullMethod, nil)
+			if tt.expectErr != "" {
+				spiretest.RequireGRPCStatus(t, err, codes.PermissionDenied, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectScopes, rpccontext.CallerScopes(ctxOut))
+		})
+	}
+}
+
 func TestWithAuthorizationPostprocess(t *testing.T) {
 	// Postprocess doesn't do anything. Let's just make sure it doesn't panic.
 	ctx := context.Background()
@@ -7,6 +7,7 @@ import (
 
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"github.com/spiffe/spire/pkg/server/authpolicy"
 )
 
 type callerAddrKey struct{}
@@ -16,6 +17,7 @@ type callerDownstreamEntriesKey struct{}
 type callerAdminTagKey struct{}
 type callerLocalTagKey struct{}
 type callerAgentTagKey struct{}
+type callerScopesKey struct{}
 
 // WithCallerAddr returns a context with the given address.
 func WithCallerAddr(ctx context.Context, addr net.Addr) context.Context {
@@ -99,3 +101,16 @@ func CallerIsAgent(ctx context.Context) bool {
 	_, ok := ctx.Value(callerAgentTagKey{}).(struct{})
 	return ok
 }
+
+// WithCallerScopes returns a context where the caller is restricted to the
+// given scopes.
+func WithCallerScopes(ctx context.Context, scopes authpolicy.Scopes) context.Context {
+	return context.WithValue(ctx, callerScopesKey{}, scopes)
+}
+
+// CallerScopes returns the scopes restricting the resources the caller can
+// manage. Empty scopes do not restrict the caller.
+func CallerScopes(ctx context.Context) authpolicy.Scopes {
+	scopes, _ := ctx.Value(callerScopesKey{}).(authpolicy.Scopes)
+	return scopes
+}
@@ -25,7 +25,9 @@ import (
 	"github.com/spiffe/spire/pkg/server/api"
 	"github.com/spiffe/spire/pkg/server/api/rpccontext"
 	"github.com/spiffe/spire/pkg/server/ca"
+	"github.com/spiffe/spire/pkg/server/catalog"
 	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/pkg/server/plugin/credentialcomposer"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -46,15 +48,22 @@ type Config struct {
 	ServerCA     ca.ServerCA
 	TrustDomain  spiffeid.TrustDomain
 	DataStore    datastore.DataStore
+	Catalog      catalog.Catalog
+
+	// AgentSelectors, if set, is looked up for the selectors of the calling
+	// agent before falling back to the datastore.
+	AgentSelectors api.AgentSelectorsCache
 }
 
 // New creates a new SVID service
 func New(config Config) *Service {
 	return &Service{
-		ca: config.ServerCA,
-		ef: config.EntryFetcher,
-		td: config.TrustDomain,
-		ds: config.DataStore,
+		ca:  config.ServerCA,
+		ef:  config.EntryFetcher,
+		td:  config.TrustDomain,
+		ds:  config.DataStore,
+		cat: config.Catalog,
+		asc: config.AgentSelectors,
 	}
 }
 
@@ -66,6 +75,8 @@ type Service struct {
 	ef                           api.AuthorizedEntryFetcher
 	td                           spiffeid.TrustDomain
 	ds                           datastore.DataStore
+	cat                          catalog.Catalog
+	asc                          api.AgentSelectorsCache
 	useLegacyDownstreamX509CATTL bool
 }
 
@@ -105,6 +116,10 @@ func (s *Service) MintX509SVID(ctx context.Context, req *svidv1.MintX509SVIDRequ
 		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "CSR URI SAN is invalid", err)
 	}
 
+	if !rpccontext.CallerScopes(ctx).AllowsID(id) {
+		return nil, commonapi.MakeErr(log, codes.PermissionDenied, "SPIFFE ID is outside of the caller scopes", nil)
+	}
+
 	dnsNames := make([]string, 0, len(csr.DNSNames))
 	for _, dnsName := range csr.DNSNames {
 		err := x509util.ValidateLabel(dnsName)
@@ -161,7 +176,7 @@ func (s *Service) MintJWTSVID(ctx context.Context, req *svidv1.MintJWTSVIDReques
 	}
 
 	rpccontext.AddRPCAuditFields(ctx, s.fieldsFromJWTSvidParams(ctx, req.Id, req.Audience, req.Ttl))
-	jwtsvid, err := s.mintJWTSVID(ctx, req.Id, req.Audience, req.Ttl, false)
+	jwtsvid, err := s.mintJWTSVID(ctx, req.Id, req.Audience, req.Ttl, false, credentialcomposer.SVIDContext{})
 	if err != nil {
 		return nil, err
 	}
@@ -212,10 +227,15 @@ func (s *Service) BatchNewX509SVID(ctx context.Context, req *svidv1.BatchNewX509
 		return nil, err
 	}
 
+	svidContext, err := s.callerSVIDContext(ctx, log)
+	if err != nil {
+		return nil, err
+	}
+
 	var results []*svidv1.BatchNewX509SVIDResponse_Result
 	for _, svidParam := range req.Params {
 		//  Create new SVID
-		r := s.newX509SVID(ctx, svidParam, entriesMap)
+		r := s.newX509SVID(ctx, svidParam, entriesMap, svidContext)
 		results = append(results, r)
 		spiffeID := ""
 		if r.Svid != nil {
@@ -256,8 +276,41 @@ func (s *Service) findEntries(ctx context.Context, log logrus.FieldLogger, entri
 	return foundEntries, nil
 }
 
+// callerSVIDContext returns the credential composer context of the agent
+// calling the API. The entry is set per SVID.
+func (s *Service) callerSVIDContext(ctx context.Context, log logrus.FieldLogger) (credentialcomposer.SVIDContext, error) {
+	callerID, ok := rpccontext.CallerID(ctx)
+	if !ok {
+		return credentialcomposer.SVIDContext{}, commonapi.MakeErr(log, codes.Internal, "caller ID missing from request context", nil)
+	}
+
+	svidContext := credentialcomposer.SVIDContext{
+		AgentID: callerID,
+	}
+
+	// The agent selectors are only used by the credential composers, so
+	// don't look them up for every SVID when there are none.
+	if len(s.cat.GetCredentialComposers()) == 0 {
+		return svidContext, nil
+	}
+
+	if s.asc != nil {
+		if selectors, ok := s.asc.LookupAgentSelectors(callerID); ok {
+			svidContext.AgentSelectors = selectors
+			return svidContext, nil
+		}
+	}
+
+	selectors, err := s.ds.GetNodeSelectors(ctx, callerID.String(), datastore.TolerateStale)
+	if err != nil {
+		return credentialcomposer.SVIDContext{}, commonapi.MakeErr(log, codes.Internal, "failed to fetch agent selectors", err)
+	}
+	svidContext.AgentSelectors = api.ProtoFromSelectors(selectors)
+	return svidContext, nil
+}
+
 // newX509SVID creates an X509-SVID using data from registration entry and key from CSR
-func (s *Service) newX509SVID(ctx context.Context, param *svidv1.NewX509SVIDParams, entries map[string]api.ReadOnlyEntry) *svidv1.BatchNewX509SVIDResponse_Result {
+func (s *Service) newX509SVID(ctx context.Context, param *svidv1.NewX509SVIDParams, entries map[string]api.ReadOnlyEntry, svidContext credentialcomposer.SVIDContext) *svidv1.BatchNewX509SVIDResponse_Result {
 	log := rpccontext.Logger(ctx)
 
 	switch {
@@ -302,11 +355,13 @@ func (s *Service) newX509SVID(ctx context.Context, param *svidv1.NewX509SVIDPara
 	}
 	log = log.WithField(telemetry.SPIFFEID, spiffeID.String())
 
+	svidContext.Entry = entry.Clone(nil)
 	x509Svid, err := s.ca.SignWorkloadX509SVID(ctx, ca.WorkloadX509SVIDParams{
-		SPIFFEID:  spiffeID,
-		PublicKey: csr.PublicKey,
-		DNSNames:  entry.GetDnsNames(),
-		TTL:       time.Duration(entry.GetX509SvidTtl()) * time.Second,
+		SPIFFEID:    spiffeID,
+		PublicKey:   csr.PublicKey,
+		DNSNames:    entry.GetDnsNames(),
+		TTL:         time.Duration(entry.GetX509SvidTtl()) * time.Second,
+		SVIDContext: svidContext,
 	})
 	if err != nil {
 		return &svidv1.BatchNewX509SVIDResponse_Result{
@@ -329,7 +384,7 @@ func (s *Service) newX509SVID(ctx context.Context, param *svidv1.NewX509SVIDPara
 	}
 }
 
-func (s *Service) mintJWTSVID(ctx context.Context, protoID *types.SPIFFEID, audience []string, ttl int32, includeJTI bool) (*types.JWTSVID, error) {
+func (s *Service) mintJWTSVID(ctx context.Context, protoID *types.SPIFFEID, audience []string, ttl int32, includeJTI bool, svidContext credentialcomposer.SVIDContext) (*types.JWTSVID, error) {
 	log := rpccontext.Logger(ctx)
 
 	id, err := api.TrustDomainWorkloadIDFromProto(ctx, s.td, protoID)
@@ -342,15 +397,20 @@ func (s *Service) mintJWTSVID(ctx context.Context, protoID *types.SPIFFEID, audi
 		telemetry.SPIFFEID: id,
 	})
 
+	if !rpccontext.CallerScopes(ctx).AllowsID(id) {
+		return nil, commonapi.MakeErr(log, codes.PermissionDenied, "SPIFFE ID is outside of the caller scopes", nil)
+	}
+
 	if len(audience) == 0 {
 		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "at least one audience is required", nil)
 	}
 
 	token, err := s.ca.SignWorkloadJWTSVID(ctx, ca.WorkloadJWTSVIDParams{
-		SPIFFEID:   id,
-		TTL:        time.Duration(ttl) * time.Second,
-		Audience:   audience,
-		IncludeJTI: includeJTI,
+		SPIFFEID:    id,
+		TTL:         time.Duration(ttl) * time.Second,
+		Audience:    audience,
+		IncludeJTI:  includeJTI,
+		SVIDContext: svidContext,
 	})
 	if err != nil {
 		return nil, commonapi.MakeErr(log, codes.Internal, "failed to sign JWT-SVID", err)
@@ -408,7 +468,13 @@ func (s *Service) NewJWTSVID(ctx context.Context, req *svidv1.NewJWTSVIDRequest)
 	if attrs := entry.GetAdditionalAttributes(); attrs != nil {
 		includeJTI = attrs.GetJwtSvidIncludeJti()
 	}
-	jwtsvid, err := s.mintJWTSVID(ctx, entry.GetSpiffeId(), req.Audience, entry.GetJwtSvidTtl(), includeJTI)
+	svidContext, err := s.callerSVIDContext(ctx, log)
+	if err != nil {
+		return nil, err
+	}
+	svidContext.Entry = entry.Clone(nil)
+
+	jwtsvid, err := s.mintJWTSVID(ctx, entry.GetSpiffeId(), req.Audience, entry.GetJwtSvidTtl(), includeJTI, svidContext)
 	if err != nil {
 		return nil, err
 	}
@@ -483,6 +549,10 @@ func (s *Service) mintWITSVID(ctx context.Context, protoID *types.SPIFFEID, publ
 
 	log = log.WithField(telemetry.SPIFFEID, id.String())
 
+	if !rpccontext.CallerScopes(ctx).AllowsID(id) {
+		return nil, commonapi.MakeErr(log, codes.PermissionDenied, "SPIFFE ID is outside of the caller scopes", nil)
+	}
+
 	publicKey, err := x509.ParsePKIXPublicKey(publicKeyDer)
 	if err != nil {
 		return nil, commonapi.MakeErr(log, codes.InvalidArgument, "invalid public key", err)
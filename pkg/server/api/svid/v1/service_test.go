@@ -21,6 +21,7 @@ import (
 
 	svidv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/svid/v1"
 	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"github.com/spiffe/spire/pkg/common/catalog"
 	"github.com/spiffe/spire/pkg/common/idutil"
 	"github.com/spiffe/spire/pkg/common/jwtsvid"
 	"github.com/spiffe/spire/pkg/common/telemetry"
@@ -29,10 +30,13 @@ import (
 	"github.com/spiffe/spire/pkg/server/api/middleware"
 	"github.com/spiffe/spire/pkg/server/api/rpccontext"
 	svid "github.com/spiffe/spire/pkg/server/api/svid/v1"
+	"github.com/spiffe/spire/pkg/server/authpolicy"
 	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/pkg/server/plugin/credentialcomposer"
 	"github.com/spiffe/spire/proto/spire/common"
 	"github.com/spiffe/spire/test/fakes/fakedatastore"
 	"github.com/spiffe/spire/test/fakes/fakeserverca"
+	"github.com/spiffe/spire/test/fakes/fakeservercatalog"
 	"github.com/spiffe/spire/test/grpctest"
 	"github.com/spiffe/spire/test/spiretest"
 	"github.com/spiffe/spire/test/testkey"
@@ -1107,6 +1111,38 @@ func TestServiceMintWITSVID(t *testing.T) {
 	}
 }
 
+func TestServiceMintWithCallerScopes(t *testing.T) {
+	test := setupServiceTest(t)
+	defer test.Cleanup()
+	test.callerScopes = authpolicy.Scopes{
+		{SPIFFEIDPrefix: workloadID},
+	}
+
+	otherID := spiffeid.RequireFromPath(td, "/workload2")
+
+	_, err := test.client.MintX509SVID(context.Background(), &svidv1.MintX509SVIDRequest{
+		Csr: createCSR(t, &x509.CertificateRequest{URIs: []*url.URL{workloadID.URL()}}),
+	})
+	require.NoError(t, err)
+
+	_, err = test.client.MintX509SVID(context.Background(), &svidv1.MintX509SVIDRequest{
+		Csr: createCSR(t, &x509.CertificateRequest{URIs: []*url.URL{otherID.URL()}}),
+	})
+	spiretest.RequireGRPCStatus(t, err, codes.PermissionDenied, "SPIFFE ID is outside of the caller scopes")
+
+	_, err = test.client.MintJWTSVID(context.Background(), &svidv1.MintJWTSVIDRequest{
+		Id:       api.ProtoFromID(workloadID),
+		Audience: []string{"AUDIENCE"},
+	})
+	require.NoError(t, err)
+
+	_, err = test.client.MintJWTSVID(context.Background(), &svidv1.MintJWTSVIDRequest{
+		Id:       api.ProtoFromID(otherID),
+		Audience: []string{"AUDIENCE"},
+	})
+	spiretest.RequireGRPCStatus(t, err, codes.PermissionDenied, "SPIFFE ID is outside of the caller scopes")
+}
+
 func TestServiceNewJWTSVID(t *testing.T) {
 	test := setupServiceTest(t)
 	defer test.Cleanup()
@@ -2751,6 +2787,95 @@ func BatchNewWITSVID(t *testing.T) {
 	}
 }
 
+func TestServiceSVIDContext(t *testing.T) {
+	cc := &recordingCredentialComposer{}
+	test := setupServiceTestWithCAOptions(t, &fakeserverca.Options{
+		CredentialComposers: []credentialcomposer.CredentialComposer{cc},
+	})
+	defer test.Cleanup()
+	test.withCallerID = true
+	test.rateLimiter.count = 1
+
+	entry := &types.Entry{
+		Id:        "workload",
+		ParentId:  api.ProtoFromID(agentID),
+		SpiffeId:  &types.SPIFFEID{TrustDomain: "example.org", Path: "/workload1"},
+		Selectors: []*types.Selector{{Type: "k8s", Value: "ns:foo"}},
+		Hint:      "hint",
+	}
+	test.ef.entries = []*types.Entry{entry}
+
+	err := test.ds.SetNodeSelectors(context.Background(), agentID.String(), []*common.Selector{
+		{Type: "k8s_psat", Value: "cluster:demo"},
+	})
+	require.NoError(t, err)
+
+	agentSelectors := []*types.Selector{{Type: "k8s_psat", Value: "cluster:demo"}}
+	assertSVIDContext := func(t *testing.T, svidContext credentialcomposer.SVIDContext) {
+		spiretest.AssertProtoEqual(t, entry, svidContext.Entry)
+		require.Equal(t, agentID, svidContext.AgentID)
+		spiretest.AssertProtoListEqual(t, agentSelectors, svidContext.AgentSelectors)
+	}
+
+	t.Run("BatchNewX509SVID", func(t *testing.T) {
+		resp, err := test.client.BatchNewX509SVID(context.Background(), &svidv1.BatchNewX509SVIDRequest{
+			Params: []*svidv1.NewX509SVIDParams{{EntryId: entry.Id, Csr: createCSR(t, &x509.CertificateRequest{})}},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Results, 1)
+		spiretest.AssertProtoEqual(t, &types.Status{Code: int32(codes.OK), Message: "OK"}, resp.Results[0].Status)
+		assertSVIDContext(t, cc.workloadX509SVIDContext)
+	})
+
+	t.Run("NewJWTSVID", func(t *testing.T) {
+		_, err := test.client.NewJWTSVID(context.Background(), &svidv1.NewJWTSVIDRequest{
+			EntryId:  entry.Id,
+			Audience: []string{"AUDIENCE"},
+		})
+		require.NoError(t, err)
+		assertSVIDContext(t, cc.workloadJWTSVIDContext)
+	})
+
+	t.Run("cached agent selectors", func(t *testing.T) {
+		// The cached selectors are used instead of the ones in the datastore
+		agentSelectors = []*types.Selector{{Type: "k8s_psat", Value: "cluster:cached"}}
+		test.selectors.selectors = map[spiffeid.ID][]*types.Selector{agentID: agentSelectors}
+		test.ds.SetNextError(errors.New("datastore should not be used"))
+		defer test.ds.SetNextError(nil)
+
+		_, err := test.client.NewJWTSVID(context.Background(), &svidv1.NewJWTSVIDRequest{
+			EntryId:  entry.Id,
+			Audience: []string{"AUDIENCE"},
+		})
+		require.NoError(t, err)
+		assertSVIDContext(t, cc.workloadJWTSVIDContext)
+	})
+}
+
+func TestServiceSVIDContextWithoutCredentialComposers(t *testing.T) {
+	test := setupServiceTest(t)
+	defer test.Cleanup()
+	test.withCallerID = true
+	test.rateLimiter.count = 1
+
+	entry := &types.Entry{
+		Id:       "workload",
+		ParentId: api.ProtoFromID(agentID),
+		SpiffeId: &types.SPIFFEID{TrustDomain: "example.org", Path: "/workload1"},
+	}
+	test.ef.entries = []*types.Entry{entry}
+
+	// The agent selectors are not looked up without credential composers
+	test.ds.SetNextError(errors.New("datastore should not be used"))
+
+	resp, err := test.client.BatchNewX509SVID(context.Background(), &svidv1.BatchNewX509SVIDRequest{
+		Params: []*svidv1.NewX509SVIDParams{{EntryId: entry.Id, Csr: createCSR(t, &x509.CertificateRequest{})}},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	spiretest.AssertProtoEqual(t, &types.Status{Code: int32(codes.OK), Message: "OK"}, resp.Results[0].Status)
+}
+
 func TestNewDownstreamX509CA(t *testing.T) {
 	type downstreamCaTest struct {
 		name           string
@@ -3059,9 +3184,11 @@ type serviceTest struct {
 	downstream   *entryFetcher // Stores Downstream entries which end up in the context
 	ca           *fakeserverca.CA
 	ds           *fakedatastore.DataStore
+	selectors    *fakeAgentSelectorsCache
 	logHook      *test.Hook
 	rateLimiter  *fakeRateLimiter
 	withCallerID bool
+	callerScopes authpolicy.Scopes
 	done         func()
 }
 
@@ -3070,18 +3197,29 @@ func (c *serviceTest) Cleanup() {
 }
 
 func setupServiceTest(t *testing.T) *serviceTest {
+	return setupServiceTestWithCAOptions(t, &fakeserverca.Options{})
+}
+
+func setupServiceTestWithCAOptions(t *testing.T, caOptions *fakeserverca.Options) *serviceTest {
 	trustDomain := spiffeid.RequireTrustDomainFromString("example.org")
-	ca := fakeserverca.New(t, trustDomain, &fakeserverca.Options{})
+	ca := fakeserverca.New(t, trustDomain, caOptions)
 	ef := &entryFetcher{}
 	downstream := &entryFetcher{}
 	ds := fakedatastore.New(t)
+	cat := fakeservercatalog.New()
+	for _, cc := range caOptions.CredentialComposers {
+		cat.AddCredentialComposer(cc)
+	}
+	selectors := &fakeAgentSelectorsCache{}
 
 	rateLimiter := &fakeRateLimiter{}
 	service := svid.New(svid.Config{
-		EntryFetcher: ef,
-		ServerCA:     ca,
-		TrustDomain:  trustDomain,
-		DataStore:    ds,
+		EntryFetcher:   ef,
+		ServerCA:       ca,
+		TrustDomain:    trustDomain,
+		DataStore:      ds,
+		Catalog:        cat,
+		AgentSelectors: selectors,
 	})
 
 	log, logHook := test.NewNullLogger()
@@ -3090,6 +3228,7 @@ func setupServiceTest(t *testing.T) *serviceTest {
 		ef:          ef,
 		downstream:  downstream,
 		ds:          ds,
+		selectors:   selectors,
 		logHook:     logHook,
 		rateLimiter: rateLimiter,
 	}
@@ -3102,6 +3241,9 @@ func setupServiceTest(t *testing.T) *serviceTest {
 		if test.downstream.entries != nil {
 			ctx = rpccontext.WithCallerDownstreamEntries(ctx, downstream.entries)
 		}
+		if len(test.callerScopes) > 0 {
+			ctx = rpccontext.WithCallerScopes(ctx, test.callerScopes)
+		}
 		return ctx
 	}
 
@@ -3188,6 +3330,35 @@ func verifyWITSVIDResponse(t *testing.T, svid *types.WITSVID, id spiffeid.ID, is
 	}
 }
 
+type recordingCredentialComposer struct {
+	catalog.PluginInfo
+
+	workloadX509SVIDContext credentialcomposer.SVIDContext
+	workloadJWTSVIDContext  credentialcomposer.SVIDContext
+}
+
+func (*recordingCredentialComposer) ComposeServerX509CA(_ context.Context, attributes credentialcomposer.X509CAAttributes) (credentialcomposer.X509CAAttributes, error) {
+	return attributes, nil
+}
+
+func (*recordingCredentialComposer) ComposeServerX509SVID(_ context.Context, attributes credentialcomposer.X509SVIDAttributes) (credentialcomposer.X509SVIDAttributes, error) {
+	return attributes, nil
+}
+
+func (*recordingCredentialComposer) ComposeAgentX509SVID(_ context.Context, _ spiffeid.ID, _ crypto.PublicKey, _ credentialcomposer.SVIDContext, attributes credentialcomposer.X509SVIDAttributes) (credentialcomposer.X509SVIDAttributes, error) {
+	return attributes, nil
+}
+
+func (cc *recordingCredentialComposer) ComposeWorkloadX509SVID(_ context.Context, _ spiffeid.ID, _ crypto.PublicKey, svidContext credentialcomposer.SVIDContext, attributes credentialcomposer.X509SVIDAttributes) (credentialcomposer.X509SVIDAttributes, error) {
+	cc.workloadX509SVIDContext = svidContext
+	return attributes, nil
+}
+
+func (cc *recordingCredentialComposer) ComposeWorkloadJWTSVID(_ context.Context, _ spiffeid.ID, svidContext credentialcomposer.SVIDContext, attributes credentialcomposer.JWTSVIDAttributes) (credentialcomposer.JWTSVIDAttributes, error) {
+	cc.workloadJWTSVIDContext = svidContext
+	return attributes, nil
+}
+
 type entryFetcher struct {
 	err     string
 	entries []*types.Entry
@@ -3241,3 +3412,12 @@ func (f *fakeRateLimiter) RateLimit(_ context.Context, count int) error {
 
 	return f.err
 }
+
+type fakeAgentSelectorsCache struct {
+	selectors map[spiffeid.ID][]*types.Selector
+}
+
+func (c *fakeAgentSelectorsCache) LookupAgentSelectors(agentID spiffeid.ID) ([]*types.Selector, bool) {
+	selectors, ok := c.selectors[agentID]
+	return selectors, ok
+}
@@ -38,6 +38,8 @@ func ProtoToFederationRelationshipWithMask(f *types.FederationRelationship, mask
 		switch {
 		case err != nil:
 			return nil, fmt.Errorf("failed to parse bundle endpoint URL: %w", err)
+		case bundleEndpointURL.Scheme == "file":
+			return nil, errors.New("bundle endpoint URL must use the https scheme; file URLs are only supported by the file profile, which can only be configured in the server configuration file")
 		case bundleEndpointURL.Scheme != "https":
 			return nil, errors.New("bundle endpoint URL must use the https scheme")
 		case bundleEndpointURL.Host == "":
@@ -7,6 +7,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	commonapi "github.com/spiffe/spire/pkg/common/api"
+	"github.com/spiffe/spire/pkg/common/bundlequarantine"
 	"github.com/spiffe/spire/pkg/common/protoutil"
 	"github.com/spiffe/spire/pkg/common/telemetry"
 	"github.com/spiffe/spire/pkg/server/api"
@@ -118,6 +119,26 @@ func (s *Service) GetFederationRelationship(ctx context.Context, req *trustdomai
 		return nil, commonapi.MakeErr(log, codes.Internal, "failed to convert datastore response", err)
 	}
 
+	// A bundle held back by the bundle safeguards is returned in the response
+	// header, since the API federation relationship type has no field for it
+	quarantined, err := s.ds.FetchQuarantinedBundle(ctx, trustDomain.IDString())
+	if err != nil {
+		return nil, commonapi.MakeErr(log, codes.Internal, "failed to fetch quarantined bundle", err)
+	}
+	if quarantined != nil {
+		current, err := s.ds.FetchBundle(ctx, trustDomain.IDString())
+		if err != nil {
+			return nil, commonapi.MakeErr(log, codes.Internal, "failed to fetch bundle", err)
+		}
+		header, err := bundlequarantine.HeaderFromQuarantinedBundle(quarantined, current)
+		if err != nil {
+			return nil, commonapi.MakeErr(log, codes.Internal, "failed to build quarantined bundle header", err)
+		}
+		if err := grpc.SetHeader(ctx, header); err != nil {
+			return nil, commonapi.MakeErr(log, codes.Internal, "failed to set quarantined bundle header", err)
+		}
+	}
+
 	rpccontext.AuditRPC(ctx)
 	return tFederationRelationship, nil
 }
@@ -13,6 +13,7 @@ import (
 	trustdomainv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/trustdomain/v1"
 	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
 	commonapi "github.com/spiffe/spire/pkg/common/api"
+	"github.com/spiffe/spire/pkg/common/bundlequarantine"
 	"github.com/spiffe/spire/pkg/common/telemetry"
 	"github.com/spiffe/spire/pkg/server/api"
 	"github.com/spiffe/spire/pkg/server/api/middleware"
@@ -28,6 +29,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 )
 
 var (
@@ -2142,6 +2144,58 @@ func TestRefreshBundle(t *testing.T) {
 	}
 }
 
+func TestGetFederationRelationshipQuarantinedBundle(t *testing.T) {
+	ds := fakedatastore.New(t)
+	test := setupServiceTest(t, ds)
+	defer test.Cleanup()
+
+	bundleEndpointURL, err := url.Parse("https://federated-td-web.org/bundleendpoint")
+	require.NoError(t, err)
+	createTestRelationships(t, ds, &datastore.FederationRelationship{
+		TrustDomain:           federatedTd,
+		BundleEndpointURL:     bundleEndpointURL,
+		BundleEndpointProfile: datastore.BundleEndpointWeb,
+	})
+
+	getHeader := func() metadata.MD {
+		var header metadata.MD
+		_, err := test.client.GetFederationRelationship(ctx, &trustdomainv1.GetFederationRelationshipRequest{
+			TrustDomain: federatedTd.Name(),
+		}, grpc.Header(&header))
+		require.NoError(t, err)
+		return header
+	}
+
+	// No quarantined bundle
+	quarantined, current, err := bundlequarantine.QuarantinedBundleFromHeader(getHeader())
+	require.NoError(t, err)
+	require.Nil(t, quarantined)
+	require.Nil(t, current)
+
+	currentBundle := &common.Bundle{
+		TrustDomainId: federatedTd.IDString(),
+		RootCas:       []*common.Certificate{{DerBytes: []byte("current")}},
+	}
+	_, err = ds.CreateBundle(ctx, currentBundle)
+	require.NoError(t, err)
+	quarantinedBundle := &common.QuarantinedBundle{
+		TrustDomainId: federatedTd.IDString(),
+		Bundle: &common.Bundle{
+			TrustDomainId: federatedTd.IDString(),
+			RootCas:       []*common.Certificate{{DerBytes: []byte("quarantined")}},
+		},
+		Violations:    []string{"no key overlaps with the current bundle"},
+		QuarantinedAt: 1234,
+	}
+	_, err = ds.SetQuarantinedBundle(ctx, quarantinedBundle)
+	require.NoError(t, err)
+
+	quarantined, current, err = bundlequarantine.QuarantinedBundleFromHeader(getHeader())
+	require.NoError(t, err)
+	spiretest.RequireProtoEqual(t, quarantinedBundle, quarantined)
+	spiretest.RequireProtoEqual(t, currentBundle, current)
+}
+
 func createTestRelationships(t *testing.T, ds datastore.DataStore, relationships ...*datastore.FederationRelationship) {
 	for _, fr := range relationships {
 		_, err := ds.CreateFederationRelationship(ctx, fr)
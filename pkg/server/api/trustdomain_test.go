@@ -198,6 +198,15 @@ func TestProtoToFederationRelationshipWithMask(t *testing.T) {
 			},
 			expectErr: "bundle endpoint URL must use the https scheme",
 		},
+		{
+			name: "BundleEndpointUrl with the file scheme",
+			proto: &types.FederationRelationship{
+				TrustDomain:           "example.org",
+				BundleEndpointUrl:     "file:///var/lib/spire/bundles",
+				BundleEndpointProfile: &types.FederationRelationship_HttpsWeb{},
+			},
+			expectErr: "bundle endpoint URL must use the https scheme; file URLs are only supported by the file profile, which can only be configured in the server configuration file",
+		},
 		{
 			name: "BundleEndpointUrl with user info",
 			proto: &types.FederationRelationship{
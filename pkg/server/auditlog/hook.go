@@ -0,0 +1,40 @@
+package auditlog
+
+import (
+	"io"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NewLogger returns a logger that writes its entries to the audit log
+// instead of the main log output. Each entry becomes a record holding the
+// entry fields and message.
+func NewLogger(w *Writer) *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	log.SetLevel(logrus.InfoLevel)
+	log.AddHook(hook{w: w})
+	return log
+}
+
+type hook struct {
+	w *Writer
+}
+
+func (hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h hook) Fire(entry *logrus.Entry) error {
+	data := make(map[string]any, len(entry.Data)+1)
+	for k, v := range entry.Data {
+		// Errors do not marshal to JSON in a useful way, so record their
+		// message as the JSON formatter does.
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		data[k] = v
+	}
+	data[logrus.FieldKeyMsg] = entry.Message
+	return h.w.Append(entry.Time, data)
+}
@@ -0,0 +1,94 @@
+package auditlog
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spiffe/spire/pkg/common/diskutil"
+	"github.com/spiffe/spire/pkg/common/pemutil"
+)
+
+// PublicKeySuffix is appended to the signing key path to name the file
+// where the public key is written for use with "audit verify".
+const PublicKeySuffix = ".pub"
+
+// KeyID returns the identifier of the given audit log key, as recorded in
+// each record.
+func KeyID(publicKey ed25519.PublicKey) string {
+	sum := sha256.Sum256(publicKey)
+	return hex.EncodeToString(sum[:8])
+}
+
+// LoadPublicKey loads the key used to verify an audit log. The file may hold
+// either the PEM encoded public key or the signing key itself.
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if publicKey, err := pemutil.ParsePublicKey(pemBytes); err == nil {
+		edKey, ok := publicKey.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("expected Ed25519 public key; got %T", publicKey)
+		}
+		return edKey, nil
+	}
+
+	privateKey, err := pemutil.ParsePrivateKey(pemBytes)
+	if err != nil {
+		return nil, errors.New("expected PEM encoded Ed25519 public or private key")
+	}
+	edKey, ok := privateKey.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("expected Ed25519 private key; got %T", privateKey)
+	}
+	return edKey.Public().(ed25519.PublicKey), nil
+}
+
+// loadOrGenerateKey loads the signing key at the given path. If the file
+// does not exist, a new key is generated and written along with its public
+// key.
+func loadOrGenerateKey(path string) (ed25519.PrivateKey, error) {
+	privateKey, err := pemutil.LoadPrivateKey(path)
+	switch {
+	case err == nil:
+		edKey, ok := privateKey.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("expected Ed25519 signing key; got %T", privateKey)
+		}
+		return edKey, nil
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("unable to load signing key: %w", err)
+	}
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate signing key: %w", err)
+	}
+
+	keyPEM, err := pemutil.EncodePKCS8PrivateKey(edKey)
+	if err != nil {
+		return nil, err
+	}
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(edKey.Public())
+	if err != nil {
+		return nil, err
+	}
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDER})
+
+	if err := diskutil.AtomicWritePrivateFile(path, keyPEM); err != nil {
+		return nil, fmt.Errorf("unable to write signing key: %w", err)
+	}
+	if err := diskutil.AtomicWritePubliclyReadableFile(path+PublicKeySuffix, publicKeyPEM); err != nil {
+		return nil, fmt.Errorf("unable to write public key: %w", err)
+	}
+	return edKey, nil
+}
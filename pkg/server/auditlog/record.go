@@ -0,0 +1,99 @@
+package auditlog
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Record is a single line of the audit log. Records are chained together:
+// the hash of each record covers the hash of the record before it, and is
+// signed with the server audit log signing key.
+type Record struct {
+	// Seq is the position of the record in the chain, starting at 1.
+	Seq uint64 `json:"seq"`
+	// Time is the time of the audited event, in RFC 3339 format.
+	Time string `json:"time"`
+	// KeyID identifies the key used to sign the record.
+	KeyID string `json:"key_id"`
+	// PrevHash is the hash of the previous record in the chain. It is empty
+	// for the first record.
+	PrevHash string `json:"prev_hash"`
+	// Data holds the audited fields (caller, method, request fields and
+	// status).
+	Data json.RawMessage `json:"data"`
+	// Hash is the hex encoded SHA-256 hash of the record contents,
+	// excluding Hash and Signature.
+	Hash string `json:"hash"`
+	// Signature is the base64 encoded Ed25519 signature of the hash.
+	Signature string `json:"signature"`
+}
+
+// recordContents holds the fields of a record that are covered by its hash.
+type recordContents struct {
+	Seq      uint64          `json:"seq"`
+	Time     string          `json:"time"`
+	KeyID    string          `json:"key_id"`
+	PrevHash string          `json:"prev_hash"`
+	Data     json.RawMessage `json:"data"`
+}
+
+func (r *Record) computeHash() ([]byte, error) {
+	contents, err := json.Marshal(recordContents{
+		Seq:      r.Seq,
+		Time:     r.Time,
+		KeyID:    r.KeyID,
+		PrevHash: r.PrevHash,
+		Data:     r.Data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(contents)
+	return sum[:], nil
+}
+
+func (r *Record) seal(key ed25519.PrivateKey) error {
+	hash, err := r.computeHash()
+	if err != nil {
+		return err
+	}
+	r.Hash = hex.EncodeToString(hash)
+	r.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, hash))
+	return nil
+}
+
+// check verifies that the record hash matches its contents and that the
+// hash was signed with the given key.
+func (r *Record) check(publicKey ed25519.PublicKey) error {
+	hash, err := r.computeHash()
+	if err != nil {
+		return err
+	}
+	if hex.EncodeToString(hash) != r.Hash {
+		return errors.New("record hash does not match its contents")
+	}
+	signature, err := base64.StdEncoding.DecodeString(r.Signature)
+	if err != nil {
+		return fmt.Errorf("malformed signature: %w", err)
+	}
+	if !ed25519.Verify(publicKey, hash, signature) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func parseRecord(line []byte) (*Record, error) {
+	r := new(Record)
+	if err := json.Unmarshal(line, r); err != nil {
+		return nil, err
+	}
+	if r.Seq == 0 {
+		return nil, errors.New("missing sequence number")
+	}
+	return r, nil
+}
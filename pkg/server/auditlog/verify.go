@@ -0,0 +1,120 @@
+package auditlog
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// VerifyResult summarizes the verification of an audit log.
+type VerifyResult struct {
+	// Records is the number of records verified.
+	Records int
+	// FirstSeq and LastSeq are the sequence numbers of the first and last
+	// records found.
+	FirstSeq uint64
+	LastSeq  uint64
+	// Problems describes every inconsistency found. The audit log is intact
+	// only if it is empty.
+	Problems []string
+}
+
+type sourcedRecord struct {
+	*Record
+	source string
+}
+
+// VerifyFiles verifies the chain formed by the records in the given files,
+// which may be passed in any order. Every record must be correctly hashed,
+// signed with the given key and linked to the previous one, with no
+// sequence numbers missing. Records before the first one found cannot be
+// checked, so a chain that does not start at sequence 1 is only verified
+// from that point on.
+func VerifyFiles(paths []string, publicKey ed25519.PublicKey) (*VerifyResult, error) {
+	result := new(VerifyResult)
+
+	var records []sourcedRecord
+	for _, path := range paths {
+		fileRecords, err := readRecords(path, result)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, fileRecords...)
+	}
+	if len(records) == 0 {
+		result.Problems = append(result.Problems, "no records found")
+		return result, nil
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Seq < records[j].Seq
+	})
+
+	keyID := KeyID(publicKey)
+	result.Records = len(records)
+	result.FirstSeq = records[0].Seq
+	result.LastSeq = records[len(records)-1].Seq
+
+	if first := records[0]; first.Seq == 1 && first.PrevHash != "" {
+		result.addProblem(first, "first record of the chain references a previous record")
+	}
+
+	for i, r := range records {
+		if r.KeyID != keyID {
+			result.addProblem(r, fmt.Sprintf("signed with key %q, expected %q", r.KeyID, keyID))
+		} else if err := r.check(publicKey); err != nil {
+			result.addProblem(r, err.Error())
+		}
+
+		if i == 0 {
+			continue
+		}
+		prev := records[i-1]
+		switch {
+		case r.Seq == prev.Seq:
+			result.addProblem(r, fmt.Sprintf("duplicated sequence number (also found at %s)", prev.source))
+		case r.Seq != prev.Seq+1:
+			result.addProblem(r, fmt.Sprintf("records %d to %d are missing", prev.Seq+1, r.Seq-1))
+		case r.PrevHash != prev.Hash:
+			result.addProblem(r, "previous hash does not match the preceding record")
+		}
+	}
+
+	return result, nil
+}
+
+func (r *VerifyResult) addProblem(record sourcedRecord, problem string) {
+	r.Problems = append(r.Problems, fmt.Sprintf("%s: record %d: %s", record.source, record.Seq, problem))
+}
+
+func readRecords(path string, result *VerifyResult) ([]sourcedRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []sourcedRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16*1024*1024)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		source := fmt.Sprintf("%s:%d", path, lineNum)
+		r, err := parseRecord(line)
+		if err != nil {
+			result.Problems = append(result.Problems, fmt.Sprintf("%s: malformed record: %v", source, err))
+			continue
+		}
+		records = append(records, sourcedRecord{Record: r, source: source})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	return records, nil
+}
@@ -0,0 +1,248 @@
+package auditlog
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spiffe/spire/test/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyFiles(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		tamper       func(t *testing.T, lines [][]byte) [][]byte
+		expectFirst  uint64
+		expectLast   uint64
+		expectRecord int
+		expectIssues []string
+	}{
+		{
+			name:         "intact",
+			expectFirst:  1,
+			expectLast:   5,
+			expectRecord: 5,
+		},
+		{
+			name: "record modified",
+			tamper: func(t *testing.T, lines [][]byte) [][]byte {
+				lines[2] = bytes.Replace(lines[2], []byte(`"method":"A"`), []byte(`"method":"B"`), 1)
+				return lines
+			},
+			expectFirst:  1,
+			expectLast:   5,
+			expectRecord: 5,
+			expectIssues: []string{
+				"audit.log:3: record 3: record hash does not match its contents",
+			},
+		},
+		{
+			name: "record removed",
+			tamper: func(t *testing.T, lines [][]byte) [][]byte {
+				return append(lines[:2], lines[3:]...)
+			},
+			expectFirst:  1,
+			expectLast:   5,
+			expectRecord: 4,
+			expectIssues: []string{
+				"audit.log:3: record 4: records 3 to 3 are missing",
+			},
+		},
+		{
+			name: "records reordered",
+			tamper: func(t *testing.T, lines [][]byte) [][]byte {
+				lines[1], lines[3] = lines[3], lines[1]
+				return lines
+			},
+			expectFirst:  1,
+			expectLast:   5,
+			expectRecord: 5,
+		},
+		{
+			name: "leading records removed",
+			tamper: func(t *testing.T, lines [][]byte) [][]byte {
+				return lines[2:]
+			},
+			expectFirst:  3,
+			expectLast:   5,
+			expectRecord: 3,
+		},
+		{
+			name: "record duplicated",
+			tamper: func(t *testing.T, lines [][]byte) [][]byte {
+				return append(lines, lines[4])
+			},
+			expectFirst:  1,
+			expectLast:   5,
+			expectRecord: 6,
+			expectIssues: []string{
+				"audit.log:6: record 5: duplicated sequence number (also found at audit.log:5)",
+			},
+		},
+		{
+			name: "malformed record",
+			tamper: func(t *testing.T, lines [][]byte) [][]byte {
+				return append(lines, []byte("garbage"))
+			},
+			expectFirst:  1,
+			expectLast:   5,
+			expectRecord: 5,
+			expectIssues: []string{
+				"audit.log:6: malformed record: invalid character 'g' looking for beginning of value",
+			},
+		},
+		{
+			name: "empty",
+			tamper: func(t *testing.T, lines [][]byte) [][]byte {
+				return nil
+			},
+			expectIssues: []string{
+				"no records found",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Chdir(dir)
+
+			config := Config{
+				Path:           "audit.log",
+				SigningKeyPath: "audit_key.pem",
+			}
+			lines := writeTestLog(t, config, 5)
+			if tt.tamper != nil {
+				lines = tt.tamper(t, lines)
+			}
+			writeTestLines(t, config.Path, lines)
+
+			publicKey, err := LoadPublicKey(config.SigningKeyPath + PublicKeySuffix)
+			require.NoError(t, err)
+
+			result, err := VerifyFiles([]string{config.Path}, publicKey)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectIssues, result.Problems)
+			require.Equal(t, tt.expectRecord, result.Records)
+			require.Equal(t, tt.expectFirst, result.FirstSeq)
+			require.Equal(t, tt.expectLast, result.LastSeq)
+		})
+	}
+}
+
+func TestVerifyFilesAcrossRotatedFiles(t *testing.T) {
+	dir := t.TempDir()
+	clk := clock.NewMock(t)
+	config := Config{
+		Path:           filepath.Join(dir, "audit.log"),
+		SigningKeyPath: filepath.Join(dir, "audit_key.pem"),
+		MaxSize:        1,
+		Clock:          clk,
+	}
+
+	w, err := Open(config)
+	require.NoError(t, err)
+	for range 3 {
+		require.NoError(t, w.Append(clk.Now(), map[string]any{"method": "A"}))
+		clk.Add(time.Second)
+	}
+	require.NoError(t, w.Close())
+
+	backups, err := listBackups(config.Path)
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+
+	publicKey, err := LoadPublicKey(config.SigningKeyPath)
+	require.NoError(t, err)
+
+	// Files can be passed in any order
+	result, err := VerifyFiles([]string{config.Path, backups[1], backups[0]}, publicKey)
+	require.NoError(t, err)
+	require.Empty(t, result.Problems)
+	require.Equal(t, 3, result.Records)
+
+	// A missing rotated file shows up as a gap
+	result, err = VerifyFiles([]string{config.Path, backups[0]}, publicKey)
+	require.NoError(t, err)
+	require.Equal(t, []string{config.Path + ":1: record 3: records 2 to 2 are missing"}, result.Problems)
+
+	_, err = VerifyFiles([]string{filepath.Join(dir, "missing.log")}, publicKey)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestVerifyFilesWithWrongKey(t *testing.T) {
+	dir := t.TempDir()
+	config := Config{
+		Path:           filepath.Join(dir, "audit.log"),
+		SigningKeyPath: filepath.Join(dir, "audit_key.pem"),
+	}
+	writeTestLog(t, config, 1)
+
+	publicKey, err := LoadPublicKey(config.SigningKeyPath)
+	require.NoError(t, err)
+	otherKey, _ := newTestKey(t)
+
+	result, err := VerifyFiles([]string{config.Path}, otherKey)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		fmt.Sprintf("%s:1: record 1: signed with key %q, expected %q", config.Path, KeyID(publicKey), KeyID(otherKey)),
+	}, result.Problems)
+}
+
+func TestVerifyFilesRejectsForgedRecord(t *testing.T) {
+	dir := t.TempDir()
+	config := Config{
+		Path:           filepath.Join(dir, "audit.log"),
+		SigningKeyPath: filepath.Join(dir, "audit_key.pem"),
+	}
+	lines := writeTestLog(t, config, 2)
+
+	// Re-seal the last record with a different key but claiming the server
+	// key ID, as an attacker without the signing key would have to.
+	publicKey, err := LoadPublicKey(config.SigningKeyPath)
+	require.NoError(t, err)
+	forged, err := parseRecord(lines[1])
+	require.NoError(t, err)
+	_, otherKey := newTestKey(t)
+	forged.Data = []byte(`{"method":"B"}`)
+	require.NoError(t, forged.seal(otherKey))
+	lines[1], err = json.Marshal(forged)
+	require.NoError(t, err)
+	writeTestLines(t, config.Path, lines)
+
+	result, err := VerifyFiles([]string{config.Path}, publicKey)
+	require.NoError(t, err)
+	require.Equal(t, []string{config.Path + ":2: record 2: invalid signature"}, result.Problems)
+}
+
+func writeTestLog(t *testing.T, config Config, n int) [][]byte {
+	w, err := Open(config)
+	require.NoError(t, err)
+	for range n {
+		require.NoError(t, w.Append(time.Now(), map[string]any{"method": "A"}))
+	}
+	require.NoError(t, w.Close())
+
+	data, err := os.ReadFile(config.Path)
+	require.NoError(t, err)
+	return bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+}
+
+func writeTestLines(t *testing.T, path string, lines [][]byte) {
+	var data []byte
+	for _, line := range lines {
+		data = append(data, line...)
+		data = append(data, '\n')
+	}
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func newTestKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	return publicKey, privateKey
+}
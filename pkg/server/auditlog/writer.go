@@ -0,0 +1,358 @@
+package auditlog
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andres-erbsen/clock"
+)
+
+const (
+	// DefaultMaxSize is the size at which the audit log file is rotated
+	// when no size is configured.
+	DefaultMaxSize = 100 * 1024 * 1024
+
+	// DefaultMaxBackups is the number of rotated files kept when no value
+	// is configured.
+	DefaultMaxBackups = 10
+
+	// backupTimeFormat is used to suffix rotated files. It sorts
+	// lexicographically in chronological order.
+	backupTimeFormat = "20060102T150405.000000000Z"
+)
+
+// Config configures the audit log Writer.
+type Config struct {
+	// Path is the path of the active audit log file. Rotated files are kept
+	// in the same directory, suffixed with the rotation time.
+	Path string
+
+	// MaxSize is the size in bytes after which the active file is rotated.
+	// Zero disables rotation.
+	MaxSize int64
+
+	// MaxBackups is the number of rotated files to keep. Zero keeps all of
+	// them.
+	MaxBackups int
+
+	// SigningKeyPath is the path to the PEM encoded Ed25519 key used to sign
+	// the records. The key is generated if it does not exist.
+	SigningKeyPath string
+
+	// Clock is used to timestamp rotated files. Defaults to the real clock.
+	Clock clock.Clock
+}
+
+// Writer appends hash-chained, signed records to the audit log file. It is
+// safe for concurrent use.
+type Writer struct {
+	config Config
+	key    ed25519.PrivateKey
+	keyID  string
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	seq      uint64
+	prevHash string
+}
+
+// Open opens the audit log for appending. The chain is resumed from the last
+// record written, so restarts do not break it. A partly written line left at
+// the end of the active file by a crash is discarded, since the record it
+// held was never added to the chain.
+func Open(config Config) (*Writer, error) {
+	if config.Path == "" {
+		return nil, errors.New("audit log path is required")
+	}
+	if config.SigningKeyPath == "" {
+		return nil, errors.New("audit log signing key path is required")
+	}
+	if config.Clock == nil {
+		config.Clock = clock.New()
+	}
+
+	key, err := loadOrGenerateKey(config.SigningKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Writer{
+		config: config,
+		key:    key,
+		keyID:  KeyID(key.Public().(ed25519.PublicKey)),
+	}
+
+	if err := truncatePartialLine(config.Path); err != nil {
+		return nil, fmt.Errorf("unable to resume audit log chain: %w", err)
+	}
+	last, err := w.lastRecord()
+	if err != nil {
+		return nil, fmt.Errorf("unable to resume audit log chain: %w", err)
+	}
+	if last != nil {
+		w.seq = last.Seq
+		w.prevHash = last.Hash
+	}
+
+	if err := w.openFile(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// KeyID returns the identifier of the signing key.
+func (w *Writer) KeyID() string {
+	return w.keyID
+}
+
+// Append adds a record with the given time and data to the chain.
+func (w *Writer) Append(t time.Time, data map[string]any) error {
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("unable to marshal audit data: %w", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return errors.New("audit log is closed")
+	}
+
+	r := &Record{
+		Seq:      w.seq + 1,
+		Time:     t.UTC().Format(time.RFC3339Nano),
+		KeyID:    w.keyID,
+		PrevHash: w.prevHash,
+		Data:     dataJSON,
+	}
+	if err := r.seal(w.key); err != nil {
+		return err
+	}
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if w.config.MaxSize > 0 && w.size > 0 && w.size+int64(len(line)) > w.config.MaxSize {
+		if err := w.rotate(); err != nil {
+			return fmt.Errorf("unable to rotate audit log: %w", err)
+		}
+	}
+
+	n, err := w.file.Write(line)
+	w.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("unable to write audit log: %w", err)
+	}
+
+	w.seq = r.Seq
+	w.prevHash = r.Hash
+	return nil
+}
+
+// Close closes the active audit log file.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *Writer) openFile() error {
+	f, err := os.OpenFile(w.config.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("unable to open audit log: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("unable to open audit log: %w", err)
+	}
+	w.file = f
+	w.size = info.Size()
+	return nil
+}
+
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	w.file = nil
+
+	backup := w.config.Path + "." + w.config.Clock.Now().UTC().Format(backupTimeFormat)
+	if err := os.Rename(w.config.Path, backup); err != nil {
+		return err
+	}
+	if err := w.openFile(); err != nil {
+		return err
+	}
+	return w.pruneBackups()
+}
+
+func (w *Writer) pruneBackups() error {
+	if w.config.MaxBackups <= 0 {
+		return nil
+	}
+	backups, err := listBackups(w.config.Path)
+	if err != nil {
+		return err
+	}
+	for len(backups) > w.config.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// lastRecord returns the last record written to the active file or, when
+// it is empty, to the most recent rotated file.
+func (w *Writer) lastRecord() (*Record, error) {
+	backups, err := listBackups(w.config.Path)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(backups)
+	for _, path := range append([]string{w.config.Path}, backups...) {
+		line, err := readLastLine(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			continue
+		case err != nil:
+			return nil, err
+		case len(line) == 0:
+			continue
+		}
+		r, err := parseRecord(line)
+		if err != nil {
+			return nil, fmt.Errorf("malformed last record in %s: %w", path, err)
+		}
+		return r, nil
+	}
+	return nil, nil
+}
+
+// listBackups returns the rotated files of the audit log at path, oldest
+// first.
+func listBackups(path string) ([]string, error) {
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		suffix, ok := strings.CutPrefix(entry.Name(), base+".")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if _, err := time.Parse(backupTimeFormat, suffix); err != nil {
+			continue
+		}
+		backups = append(backups, filepath.Join(dir, entry.Name()))
+	}
+	slices.Sort(backups)
+	return backups, nil
+}
+
+// truncatePartialLine removes the trailing bytes of the file after its last
+// newline, which are what remains of a record whose write was interrupted.
+// Records are written with a single append ending in a newline, so complete
+// records are never affected.
+func truncatePartialLine(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	const chunkSize = 4096
+	end := info.Size()
+	for offset := end; offset > 0; {
+		n := min(chunkSize, offset)
+		offset -= n
+		chunk := make([]byte, n)
+		if _, err := f.ReadAt(chunk, offset); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if i := bytes.LastIndexByte(chunk, '\n'); i >= 0 {
+			end = offset + int64(i) + 1
+			break
+		}
+		end = offset
+	}
+	if end == info.Size() {
+		return nil
+	}
+	if err := f.Truncate(end); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// readLastLine returns the last non-empty line of the file, reading it
+// backwards so large files are not read in full.
+func readLastLine(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	const chunkSize = 4096
+	var buf []byte
+	for offset := info.Size(); offset > 0; {
+		n := min(chunkSize, offset)
+		offset -= n
+		chunk := make([]byte, n)
+		if _, err := f.ReadAt(chunk, offset); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		buf = append(chunk, buf...)
+
+		trimmed := bytes.TrimRight(buf, "\r\n")
+		if i := bytes.LastIndexByte(trimmed, '\n'); i >= 0 {
+			return trimmed[i+1:], nil
+		}
+	}
+	return bytes.TrimRight(buf, "\r\n"), nil
+}
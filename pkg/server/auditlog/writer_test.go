@@ -0,0 +1,236 @@
+package auditlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spiffe/spire/test/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterChainsRecords(t *testing.T) {
+	dir := t.TempDir()
+	config := Config{
+		Path:           filepath.Join(dir, "audit.log"),
+		SigningKeyPath: filepath.Join(dir, "audit_key.pem"),
+	}
+
+	w, err := Open(config)
+	require.NoError(t, err)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)
+	require.NoError(t, w.Append(now, map[string]any{"method": "A"}))
+	require.NoError(t, w.Append(now, map[string]any{"method": "B"}))
+	require.NoError(t, w.Close())
+
+	records := readTestRecords(t, config.Path)
+	require.Len(t, records, 2)
+
+	require.Equal(t, uint64(1), records[0].Seq)
+	require.Equal(t, "2026-01-02T03:04:05.000000006Z", records[0].Time)
+	require.Empty(t, records[0].PrevHash)
+	require.JSONEq(t, `{"method":"A"}`, string(records[0].Data))
+	require.Equal(t, w.KeyID(), records[0].KeyID)
+
+	require.Equal(t, uint64(2), records[1].Seq)
+	require.Equal(t, records[0].Hash, records[1].PrevHash)
+
+	// The public key is written next to the signing key
+	publicKey, err := LoadPublicKey(config.SigningKeyPath + PublicKeySuffix)
+	require.NoError(t, err)
+	require.Equal(t, w.KeyID(), KeyID(publicKey))
+	for _, r := range records {
+		require.NoError(t, r.check(publicKey))
+	}
+
+	// The signing key can be used for verification as well
+	signingPublicKey, err := LoadPublicKey(config.SigningKeyPath)
+	require.NoError(t, err)
+	require.Equal(t, publicKey, signingPublicKey)
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(config.Path)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+}
+
+func TestWriterResumesChain(t *testing.T) {
+	dir := t.TempDir()
+	config := Config{
+		Path:           filepath.Join(dir, "audit.log"),
+		SigningKeyPath: filepath.Join(dir, "audit_key.pem"),
+	}
+
+	w, err := Open(config)
+	require.NoError(t, err)
+	require.NoError(t, w.Append(time.Now(), map[string]any{"method": "A"}))
+	require.NoError(t, w.Close())
+
+	w, err = Open(config)
+	require.NoError(t, err)
+	require.NoError(t, w.Append(time.Now(), map[string]any{"method": "B"}))
+	require.NoError(t, w.Close())
+
+	records := readTestRecords(t, config.Path)
+	require.Len(t, records, 2)
+	require.Equal(t, uint64(2), records[1].Seq)
+	require.Equal(t, records[0].Hash, records[1].PrevHash)
+	require.Equal(t, records[0].KeyID, records[1].KeyID)
+
+	require.EqualError(t, w.Append(time.Now(), nil), "audit log is closed")
+}
+
+func TestWriterRefusesMalformedLastRecord(t *testing.T) {
+	dir := t.TempDir()
+	config := Config{
+		Path:           filepath.Join(dir, "audit.log"),
+		SigningKeyPath: filepath.Join(dir, "audit_key.pem"),
+	}
+	require.NoError(t, os.WriteFile(config.Path, []byte("{\"seq\":1}\n{\"seq\":\n"), 0o600))
+
+	_, err := Open(config)
+	require.ErrorContains(t, err, "unable to resume audit log chain: malformed last record in")
+}
+
+func TestWriterDiscardsPartialLastLine(t *testing.T) {
+	dir := t.TempDir()
+	config := Config{
+		Path:           filepath.Join(dir, "audit.log"),
+		SigningKeyPath: filepath.Join(dir, "audit_key.pem"),
+	}
+
+	w, err := Open(config)
+	require.NoError(t, err)
+	require.NoError(t, w.Append(time.Now(), map[string]any{"method": "A"}))
+	require.NoError(t, w.Close())
+
+	// Simulate a crash in the middle of writing the second record
+	f, err := os.OpenFile(config.Path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"seq":2,"time":"2026-01-02T03:`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	w, err = Open(config)
+	require.NoError(t, err)
+	require.NoError(t, w.Append(time.Now(), map[string]any{"method": "B"}))
+	require.NoError(t, w.Close())
+
+	records := readTestRecords(t, config.Path)
+	require.Len(t, records, 2)
+	require.Equal(t, uint64(2), records[1].Seq)
+	require.Equal(t, records[0].Hash, records[1].PrevHash)
+
+	publicKey, err := LoadPublicKey(config.SigningKeyPath + PublicKeySuffix)
+	require.NoError(t, err)
+	result, err := VerifyFiles([]string{config.Path}, publicKey)
+	require.NoError(t, err)
+	require.Empty(t, result.Problems)
+}
+
+func TestWriterRotates(t *testing.T) {
+	dir := t.TempDir()
+	clk := clock.NewMock(t)
+	config := Config{
+		Path:           filepath.Join(dir, "audit.log"),
+		SigningKeyPath: filepath.Join(dir, "audit_key.pem"),
+		MaxSize:        1,
+		MaxBackups:     2,
+		Clock:          clk,
+	}
+
+	w, err := Open(config)
+	require.NoError(t, err)
+	for range 4 {
+		require.NoError(t, w.Append(clk.Now(), map[string]any{"method": "A"}))
+		clk.Add(time.Second)
+	}
+	require.NoError(t, w.Close())
+
+	// Every record exceeds the maximum size, so each one ends up in its own
+	// file, and only the two most recent rotated files are kept.
+	backups, err := listBackups(config.Path)
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+
+	var seqs []uint64
+	for _, path := range append(backups, config.Path) {
+		for _, r := range readTestRecords(t, path) {
+			seqs = append(seqs, r.Seq)
+		}
+	}
+	require.Equal(t, []uint64{2, 3, 4}, seqs)
+
+	// Reopening resumes from the active file
+	w, err = Open(config)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), w.seq)
+	require.NoError(t, w.Close())
+
+	// Reopening resumes from the most recent rotated file when the active
+	// file is missing
+	require.NoError(t, os.Remove(config.Path))
+	w, err = Open(config)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), w.seq)
+	require.NoError(t, w.Close())
+}
+
+func TestLogger(t *testing.T) {
+	dir := t.TempDir()
+	config := Config{
+		Path:           filepath.Join(dir, "audit.log"),
+		SigningKeyPath: filepath.Join(dir, "audit_key.pem"),
+	}
+
+	w, err := Open(config)
+	require.NoError(t, err)
+	defer w.Close()
+
+	log := NewLogger(w)
+	log.WithFields(logrus.Fields{
+		"caller_id": "spiffe://example.org/admin",
+		"error":     errors.New("oh no"),
+	}).Info("API accessed")
+
+	records := readTestRecords(t, config.Path)
+	require.Len(t, records, 1)
+	require.JSONEq(t, `{
+		"caller_id": "spiffe://example.org/admin",
+		"error": "oh no",
+		"msg": "API accessed"
+	}`, string(records[0].Data))
+}
+
+func TestLoadPublicKeyFailures(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadPublicKey(filepath.Join(dir, "missing.pem"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	path := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(path, []byte("not a key"), 0o600))
+	_, err = LoadPublicKey(path)
+	require.EqualError(t, err, "expected PEM encoded Ed25519 public or private key")
+}
+
+func readTestRecords(t *testing.T, path string) []*Record {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var records []*Record
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for decoder.More() {
+		r := new(Record)
+		require.NoError(t, decoder.Decode(r))
+		records = append(records, r)
+	}
+	return records
+}
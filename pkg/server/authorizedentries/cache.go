@@ -1,6 +1,9 @@
 package authorizedentries
 
 import (
+	"cmp"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -115,6 +118,26 @@ func (c *Cache) GetAuthorizedEntries(agentID spiffeid.ID) []api.ReadOnlyEntry {
 	return records
 }
 
+// LookupAgentSelectors returns the selectors of the agent, sorted by type and
+// value, and whether the agent is in the cache.
+func (c *Cache) LookupAgentSelectors(agentID spiffeid.ID) ([]*types.Selector, bool) {
+	c.mu.RLock()
+	agent, ok := c.agentsByID[agentID.String()]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	selectors := make([]*types.Selector, 0, len(agent.Selectors))
+	for selector := range agent.Selectors {
+		selectors = append(selectors, &types.Selector{Type: selector.Type, Value: selector.Value})
+	}
+	slices.SortFunc(selectors, func(a, b *types.Selector) int {
+		return cmp.Or(strings.Compare(a.Type, b.Type), strings.Compare(a.Value, b.Value))
+	})
+	return selectors, true
+}
+
 func (c *Cache) UpdateEntry(entry *types.Entry) {
 	// Ensure that the trust domain of the entry matches the expected trust domain.
 	// This allows us to use only the path component as a key in maps.
@@ -542,6 +542,27 @@ func TestLookupEntries(t *testing.T) {
 	require.Contains(t, found, "workload-child-7")
 }
 
+func TestLookupAgentSelectors(t *testing.T) {
+	cache := NewCache(clock.NewMock(t), "domain.test")
+	cache.UpdateAgent(agent1.String(), now.Add(time.Hour), []*types.Selector{sel2, sel1})
+	cache.UpdateAgent(agent2.String(), now.Add(time.Hour), nil)
+
+	selectors, ok := cache.LookupAgentSelectors(agent1)
+	require.True(t, ok)
+	spiretest.RequireProtoListEqual(t, []*types.Selector{sel1, sel2}, selectors)
+
+	selectors, ok = cache.LookupAgentSelectors(agent2)
+	require.True(t, ok)
+	require.Empty(t, selectors)
+
+	_, ok = cache.LookupAgentSelectors(agent3)
+	require.False(t, ok)
+
+	cache.RemoveAgent(agent1.String())
+	_, ok = cache.LookupAgentSelectors(agent1)
+	require.False(t, ok)
+}
+
 func BenchmarkEntryLookup(b *testing.B) {
 	numEntries := 256
 	cache := setupLookupTest(b, numEntries)
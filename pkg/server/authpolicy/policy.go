@@ -2,9 +2,12 @@ package authpolicy
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
+	"sync/atomic"
 
 	"github.com/open-policy-agent/opa/v1/ast"
 	"github.com/open-policy-agent/opa/v1/rego"
@@ -24,7 +27,17 @@ const (
 
 // Engine drives policy management.
 type Engine struct {
+	// policy is swapped atomically when the policy is reloaded, so calls
+	// being authorized always evaluate a complete policy.
+	policy atomic.Pointer[preparedPolicy]
+}
+
+type preparedPolicy struct {
 	query rego.PreparedEvalQuery
+
+	// hash identifies the policy files the policy was loaded from. It is
+	// empty for policies that are not loaded from files.
+	hash string
 }
 
 type OpaEngineConfig struct {
@@ -44,6 +57,10 @@ type Input struct {
 	// CallerFilePath is the file path of a local actor making a request.
 	CallerFilePath string `json:"caller_file_path"`
 
+	// CallerIsLocal is true when the request is made through the local
+	// server API socket.
+	CallerIsLocal bool `json:"caller_is_local"`
+
 	// FullMethod is the fully-qualified name of the proto rpc service method.
 	FullMethod string `json:"full_method"`
 
@@ -59,6 +76,10 @@ type Result struct {
 	AllowIfLocal      bool `json:"allow_if_local"`
 	AllowIfDownstream bool `json:"allow_if_downstream"`
 	AllowIfAgent      bool `json:"allow_if_agent"`
+
+	// Scopes restricts the resources the caller can manage once the call
+	// is authorized. It is optional in the policy result.
+	Scopes Scopes `json:"scopes"`
 }
 
 // NewEngineFromConfigOrDefault returns a new policy engine. Or if no
@@ -80,23 +101,53 @@ func newEngine(ctx context.Context, cfg *OpaEngineConfig) (*Engine, error) {
 		return nil, errors.New("policy engine configuration must define a provider")
 	}
 
-	module, err := os.ReadFile(cfg.LocalOpaProvider.RegoPath)
+	files, err := readLocalPolicy(cfg.LocalOpaProvider)
 	if err != nil {
 		return nil, err
 	}
+	return newEngineFromFiles(ctx, files)
+}
 
-	var store storage.Store
-	// If permissions file is defined use it, else provide empty store
-	if cfg.LocalOpaProvider.PolicyDataPath != "" {
-		storefile, err := os.Open(cfg.LocalOpaProvider.PolicyDataPath)
+// localPolicy holds the contents of the files of a local OPA provider.
+type localPolicy struct {
+	module []byte
+	data   []byte
+}
+
+// hash returns a hash that identifies the policy files contents.
+func (p localPolicy) hash() string {
+	h := sha256.New()
+	h.Write(p.module)
+	// Separate the rego policy from the databindings so moving content
+	// from one file to the other changes the hash.
+	h.Write([]byte{0})
+	h.Write(p.data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func readLocalPolicy(cfg *LocalOpaProviderConfig) (localPolicy, error) {
+	module, err := os.ReadFile(cfg.RegoPath)
+	if err != nil {
+		return localPolicy{}, err
+	}
+
+	var data []byte
+	if cfg.PolicyDataPath != "" {
+		data, err = os.ReadFile(cfg.PolicyDataPath)
 		if err != nil {
-			return nil, err
+			return localPolicy{}, err
 		}
-		defer storefile.Close()
+	}
 
-		d := util.NewJSONDecoder(storefile)
+	return localPolicy{module: module, data: data}, nil
+}
+
+func newEngineFromFiles(ctx context.Context, files localPolicy) (*Engine, error) {
+	var store storage.Store
+	// If permissions file is defined use it, else provide empty store
+	if files.data != nil {
 		var data map[string]any
-		if err := d.Decode(&data); err != nil {
+		if err := util.UnmarshalJSON(files.data, &data); err != nil {
 			return nil, fmt.Errorf("error decoding JSON databindings: %w", err)
 		}
 		store = inmem.NewFromObject(data)
@@ -104,7 +155,12 @@ func newEngine(ctx context.Context, cfg *OpaEngineConfig) (*Engine, error) {
 		store = inmem.NewFromObject(map[string]any{})
 	}
 
-	return NewEngineFromRego(ctx, string(module), store)
+	e, err := NewEngineFromRego(ctx, string(files.module), store)
+	if err != nil {
+		return nil, err
+	}
+	e.policy.Load().hash = files.hash()
+	return e, nil
 }
 
 // NewEngineFromRego is a helper to create the Engine object
@@ -121,9 +177,8 @@ func NewEngineFromRego(ctx context.Context, regoPolicy string, dataStore storage
 		return nil, err
 	}
 
-	e := &Engine{
-		query: query,
-	}
+	e := new(Engine)
+	e.policy.Store(&preparedPolicy{query: query})
 
 	// Test policy with some simple calls to ensure that the
 	// policy can be evaluated properly.
@@ -134,9 +189,15 @@ func NewEngineFromRego(ctx context.Context, regoPolicy string, dataStore storage
 	return e, nil
 }
 
+// Hash returns the hash of the policy files the current policy was loaded
+// from, or an empty string if the policy was not loaded from files.
+func (e *Engine) Hash() string {
+	return e.policy.Load().hash
+}
+
 // Eval determines whether access should be allowed on a resource.
 func (e *Engine) Eval(ctx context.Context, input Input) (result Result, err error) {
-	rs, err := e.query.Eval(ctx, rego.EvalInput(input))
+	rs, err := e.policy.Load().query.Eval(ctx, rego.EvalInput(input))
 	if err != nil {
 		return Result{}, err
 	}
@@ -179,5 +240,17 @@ func (e *Engine) Eval(ctx context.Context, input Input) (result Result, err erro
 		return Result{}, err
 	}
 
+	if result.Scopes, err = scopesFromResult(resultMap); err != nil {
+		return Result{}, err
+	}
+
+	// Admin rights of scoped callers are limited to the methods enforcing
+	// the scopes. Rights that do not depend on the caller being an admin
+	// are kept.
+	if len(result.Scopes) > 0 && !scopedMethods[input.FullMethod] {
+		result.AllowIfAdmin = false
+		result.AllowIfLocal = false
+	}
+
 	return result, nil
 }
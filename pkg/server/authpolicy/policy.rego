@@ -12,6 +12,9 @@ package spire
 #   only if the caller has a downstream SPIFFE ID
 # - `allow_if_agent`: a boolean that if true, will authorize the call only if
 #   the caller is an agent
+# - `scopes`: an optional list of scopes that restrict the registration
+#   entries, agents and SVIDs the caller can manage. An empty list does not
+#   restrict the caller
 
 result = {
   "allow": allow, 
@@ -19,6 +22,7 @@ result = {
   "allow_if_local": allow_if_local,
   "allow_if_downstream": allow_if_downstream,
   "allow_if_agent": allow_if_agent,
+  "scopes": scopes,
 }
 
 
@@ -29,6 +33,7 @@ default allow_if_downstream = false
 default allow_if_local = false
 default allow_if_agent = false
 default allow = false 
+default scopes = []
 
 
 # Admin allow check
@@ -73,4 +78,10 @@ allow = true if {
     r.allow_any
 }
 
+# Scopes of the caller, looked up by caller SPIFFE ID in the optional
+# `admin_scopes` databinding
+scopes = s if {
+    s := data.admin_scopes[input.caller]
+}
+
 ### DEFAULT POLICY END  ###
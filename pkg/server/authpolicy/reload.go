@@ -0,0 +1,173 @@
+package authpolicy
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/andres-erbsen/clock"
+	"github.com/sirupsen/logrus"
+	"github.com/spiffe/spire/pkg/common/health"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	telemetry_server "github.com/spiffe/spire/pkg/common/telemetry/server"
+)
+
+const defaultFileSyncInterval = 5 * time.Second
+
+// ReloaderConfig is the configuration of a Reloader.
+type ReloaderConfig struct {
+	Log     logrus.FieldLogger
+	Metrics telemetry.Metrics
+	Clock   clock.Clock
+
+	// Engine is the engine whose policy is reloaded.
+	Engine *Engine
+
+	// Provider is the local OPA provider the engine was loaded from.
+	Provider *LocalOpaProviderConfig
+
+	// FileSyncInterval is how often the policy files are checked for
+	// changes. Defaults to 5 seconds.
+	FileSyncInterval time.Duration
+}
+
+// Reloader reloads the policy of an engine when the files of its local OPA
+// provider change, or when a reload is requested. A policy that fails to
+// load or validate is not swapped in, so the engine keeps running the last
+// good policy.
+type Reloader struct {
+	c ReloaderConfig
+
+	mu sync.Mutex
+	// failedHash is the hash of the policy files that last failed to load,
+	// used to avoid retrying them on every file sync.
+	failedHash string
+	lastErr    error
+}
+
+// NewReloader creates a new policy reloader.
+func NewReloader(c ReloaderConfig) (*Reloader, error) {
+	switch {
+	case c.Engine == nil:
+		return nil, errors.New("policy engine is required")
+	case c.Provider == nil:
+		return nil, errors.New("local OPA provider configuration is required")
+	}
+	if c.Clock == nil {
+		c.Clock = clock.New()
+	}
+	if c.FileSyncInterval <= 0 {
+		c.FileSyncInterval = defaultFileSyncInterval
+	}
+	return &Reloader{c: c}, nil
+}
+
+// Run watches the policy files for changes until the context is done.
+func (r *Reloader) Run(ctx context.Context) error {
+	ticker := r.c.Clock.Ticker(r.c.FileSyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.reload(ctx, false)
+		}
+	}
+}
+
+// Reconfigure reloads the policy files. It allows reloads to be triggered
+// by the same signal that reconfigures plugins.
+func (r *Reloader) Reconfigure(ctx context.Context) {
+	r.reload(ctx, true)
+}
+
+// Reload reloads the policy files, returning an error if the policy could
+// not be loaded.
+func (r *Reloader) Reload(ctx context.Context) error {
+	return r.reload(ctx, true)
+}
+
+func (r *Reloader) reload(ctx context.Context, force bool) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	oldHash := r.c.Engine.Hash()
+	files, err := readLocalPolicy(r.c.Provider)
+	var newHash string
+	if err == nil {
+		newHash = files.hash()
+		if newHash == oldHash {
+			if force {
+				r.c.Log.WithField(telemetry.Hash, newHash).Info("Authorization policy not reloaded since it is unchanged")
+			}
+			r.failedHash = ""
+			r.lastErr = nil
+			return nil
+		}
+	}
+
+	// Policy files that already failed to load are not retried until they
+	// change, unless the reload is requested, so failures are not reported
+	// on every file sync.
+	if !force && r.lastErr != nil && r.failedHash == newHash {
+		return r.lastErr
+	}
+
+	counter := telemetry_server.StartAuthPolicyReloadCall(r.c.Metrics)
+	defer counter.Done(&err)
+
+	log := r.c.Log.WithFields(logrus.Fields{
+		telemetry.OldHash: oldHash,
+		telemetry.NewHash: newHash,
+	})
+
+	var next *Engine
+	if err == nil {
+		next, err = newEngineFromFiles(ctx, files)
+	}
+	if err != nil {
+		log.WithError(err).Error("Failed to reload authorization policy; keeping the current policy")
+		r.failedHash = newHash
+		r.lastErr = err
+		return err
+	}
+
+	r.c.Engine.policy.Store(next.policy.Load())
+	r.failedHash = ""
+	r.lastErr = nil
+	log.Info("Authorization policy reloaded")
+	return nil
+}
+
+// CheckHealth reports the result of the last reload. The server keeps
+// running the last good policy when a reload fails, so the failure is
+// reported in the details without affecting liveness or readiness.
+func (r *Reloader) CheckHealth() health.State {
+	r.mu.Lock()
+	details := reloaderHealthDetails{
+		PolicyHash: r.c.Engine.Hash(),
+		ReloadErr:  errString(r.lastErr),
+	}
+	r.mu.Unlock()
+
+	return health.State{
+		Live:         true,
+		Ready:        true,
+		LiveDetails:  details,
+		ReadyDetails: details,
+	}
+}
+
+type reloaderHealthDetails struct {
+	PolicyHash string `json:"policy_hash,omitempty"`
+	ReloadErr  string `json:"reload_err,omitempty"`
+}
+
+func errString(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return ""
+}
@@ -0,0 +1,193 @@
+package authpolicy_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/pkg/server/authpolicy"
+	"github.com/spiffe/spire/test/clock"
+	"github.com/spiffe/spire/test/fakes/fakemetrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloader(t *testing.T) {
+	ctx := context.Background()
+	provider := writePolicyFiles(t, t.TempDir(), simpleRego(map[string]bool{}), "{}")
+
+	engine, err := authpolicy.NewEngineFromConfigOrDefault(ctx, nil, &authpolicy.OpaEngineConfig{
+		LocalOpaProvider: provider,
+	})
+	require.NoError(t, err)
+	initialHash := engine.Hash()
+	require.NotEmpty(t, initialHash)
+
+	log, logHook := test.NewNullLogger()
+	metrics := fakemetrics.New()
+	reloader, err := authpolicy.NewReloader(authpolicy.ReloaderConfig{
+		Log:      log,
+		Metrics:  metrics,
+		Engine:   engine,
+		Provider: provider,
+	})
+	require.NoError(t, err)
+
+	requireAllow := func(expected bool) {
+		result, err := engine.Eval(ctx, authpolicy.Input{})
+		require.NoError(t, err)
+		require.Equal(t, expected, result.Allow)
+	}
+	requireAllow(false)
+
+	// Unchanged files are not reloaded
+	require.NoError(t, reloader.Reload(ctx))
+	require.Equal(t, initialHash, engine.Hash())
+	require.Equal(t, "Authorization policy not reloaded since it is unchanged", logHook.LastEntry().Message)
+	require.Empty(t, metrics.AllMetrics())
+
+	// Changed files are swapped in
+	require.NoError(t, os.WriteFile(provider.RegoPath, []byte(simpleRego(map[string]bool{"allow": true})), 0o600))
+	require.NoError(t, reloader.Reload(ctx))
+	requireAllow(true)
+	reloadedHash := engine.Hash()
+	require.NotEqual(t, initialHash, reloadedHash)
+	require.Equal(t, "Authorization policy reloaded", logHook.LastEntry().Message)
+	require.Equal(t, logrus.Fields{
+		telemetry.OldHash: initialHash,
+		telemetry.NewHash: reloadedHash,
+	}, logHook.LastEntry().Data)
+	requireReloadCount(t, metrics, "OK", 1)
+
+	// A policy that fails validation keeps the current policy
+	require.NoError(t, os.WriteFile(provider.RegoPath, []byte(`package spire
+result = {"allow": true}`), 0o600))
+	err = reloader.Reload(ctx)
+	require.ErrorContains(t, err, "authpolicy engine failed to validate on sample test inputs")
+	requireAllow(true)
+	require.Equal(t, reloadedHash, engine.Hash())
+	require.Equal(t, logrus.ErrorLevel, logHook.LastEntry().Level)
+	require.Equal(t, "Failed to reload authorization policy; keeping the current policy", logHook.LastEntry().Message)
+	requireReloadCount(t, metrics, "Unknown", 1)
+
+	state := reloader.CheckHealth()
+	require.True(t, state.Live)
+	require.True(t, state.Ready)
+	requireHealthDetails(t, state.ReadyDetails, reloadedHash, err.Error())
+
+	// Malformed databindings keep the current policy
+	require.NoError(t, os.WriteFile(provider.RegoPath, []byte(simpleRego(map[string]bool{})), 0o600))
+	require.NoError(t, os.WriteFile(provider.PolicyDataPath, []byte("{"), 0o600))
+	require.ErrorContains(t, reloader.Reload(ctx), "error decoding JSON databindings")
+	requireAllow(true)
+
+	// Missing files keep the current policy
+	require.NoError(t, os.Remove(provider.PolicyDataPath))
+	require.ErrorIs(t, reloader.Reload(ctx), os.ErrNotExist)
+	requireAllow(true)
+	requireReloadCount(t, metrics, "Unknown", 3)
+
+	// Fixing the files clears the failure
+	require.NoError(t, os.WriteFile(provider.PolicyDataPath, []byte("{}"), 0o600))
+	require.NoError(t, reloader.Reload(ctx))
+	requireAllow(false)
+	require.Equal(t, initialHash, engine.Hash())
+	requireHealthDetails(t, reloader.CheckHealth().ReadyDetails, initialHash, "")
+}
+
+func TestReloaderWatchesFiles(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	provider := writePolicyFiles(t, t.TempDir(), simpleRego(map[string]bool{}), "{}")
+	engine, err := authpolicy.NewEngineFromConfigOrDefault(ctx, nil, &authpolicy.OpaEngineConfig{
+		LocalOpaProvider: provider,
+	})
+	require.NoError(t, err)
+
+	log, logHook := test.NewNullLogger()
+	clk := clock.NewMock(t)
+	reloader, err := authpolicy.NewReloader(authpolicy.ReloaderConfig{
+		Log:              log,
+		Metrics:          fakemetrics.New(),
+		Clock:            clk,
+		Engine:           engine,
+		Provider:         provider,
+		FileSyncInterval: time.Second,
+	})
+	require.NoError(t, err)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- reloader.Run(ctx)
+	}()
+	clk.WaitForTicker(time.Minute, "waiting for the file sync ticker")
+
+	require.NoError(t, os.WriteFile(provider.RegoPath, []byte(simpleRego(map[string]bool{"allow": true})), 0o600))
+	require.Eventually(t, func() bool {
+		clk.Add(time.Second)
+		result, err := engine.Eval(ctx, authpolicy.Input{})
+		return err == nil && result.Allow
+	}, time.Minute, 10*time.Millisecond)
+
+	// A bad policy is only reported once until the files change
+	require.NoError(t, os.WriteFile(provider.RegoPath, []byte("bad policy"), 0o600))
+	require.Eventually(t, func() bool {
+		clk.Add(time.Second)
+		return logHook.LastEntry().Level == logrus.ErrorLevel
+	}, time.Minute, 10*time.Millisecond)
+	for range 3 {
+		clk.Add(time.Second)
+	}
+	cancel()
+	require.NoError(t, <-errCh)
+
+	var failures int
+	for _, entry := range logHook.AllEntries() {
+		if entry.Level == logrus.ErrorLevel {
+			failures++
+		}
+	}
+	require.Equal(t, 1, failures)
+}
+
+func writePolicyFiles(t *testing.T, dir, rego, data string) *authpolicy.LocalOpaProviderConfig {
+	provider := &authpolicy.LocalOpaProviderConfig{
+		RegoPath:       filepath.Join(dir, "policy.rego"),
+		PolicyDataPath: filepath.Join(dir, "policy_data.json"),
+	}
+	require.NoError(t, os.WriteFile(provider.RegoPath, []byte(rego), 0o600))
+	require.NoError(t, os.WriteFile(provider.PolicyDataPath, []byte(data), 0o600))
+	return provider
+}
+
+func requireHealthDetails(t *testing.T, details any, expectHash, expectErr string) {
+	b, err := json.Marshal(details)
+	require.NoError(t, err)
+	var actual struct {
+		PolicyHash string `json:"policy_hash"`
+		ReloadErr  string `json:"reload_err"`
+	}
+	require.NoError(t, json.Unmarshal(b, &actual))
+	require.Equal(t, expectHash, actual.PolicyHash)
+	require.Equal(t, expectErr, actual.ReloadErr)
+}
+
+func requireReloadCount(t *testing.T, metrics *fakemetrics.FakeMetrics, status string, expected int) {
+	var count int
+	for _, m := range metrics.AllMetrics() {
+		if m.Type != fakemetrics.IncrCounterWithLabelsType {
+			continue
+		}
+		require.Equal(t, []string{telemetry.AuthPolicy, telemetry.Reload}, m.Key)
+		if m.Labels[0].Value == status {
+			count++
+		}
+	}
+	require.Equal(t, expected, count)
+}
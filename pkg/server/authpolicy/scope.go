@@ -0,0 +1,166 @@
+package authpolicy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	agentv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/agent/v1"
+	entryv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/entry/v1"
+	svidv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/svid/v1"
+	agentstatusv1 "github.com/spiffe/spire/proto/private/server/agentstatus"
+)
+
+const scopesKey = "scopes"
+
+// scopedMethods are the methods that enforce the caller scopes. Scoped
+// callers are not granted admin rights on any other method, since those
+// methods know nothing about scopes and would let the callers escape them.
+var scopedMethods = map[string]bool{
+	entryv1.Entry_CountEntries_FullMethodName:     true,
+	entryv1.Entry_ListEntries_FullMethodName:      true,
+	entryv1.Entry_GetEntry_FullMethodName:         true,
+	entryv1.Entry_BatchCreateEntry_FullMethodName: true,
+	entryv1.Entry_BatchUpdateEntry_FullMethodName: true,
+	entryv1.Entry_BatchDeleteEntry_FullMethodName: true,
+	agentv1.Agent_CountAgents_FullMethodName:      true,
+	agentv1.Agent_ListAgents_FullMethodName:       true,
+	agentv1.Agent_GetAgent_FullMethodName:         true,
+	agentv1.Agent_DeleteAgent_FullMethodName:      true,
+	agentv1.Agent_BanAgent_FullMethodName:         true,
+	agentv1.Agent_CreateJoinToken_FullMethodName:  true,
+	svidv1.SVID_MintX509SVID_FullMethodName:       true,
+	svidv1.SVID_MintJWTSVID_FullMethodName:        true,
+	svidv1.SVID_MintWITSVID_FullMethodName:        true,
+
+	agentstatusv1.AgentStatus_ListAgentStatuses_FullMethodName: true,
+}
+
+// Scope restricts the resources a caller can manage. A resource is within
+// the scope when it matches every field set on the scope.
+type Scope struct {
+	// SPIFFEIDPrefix matches resources whose SPIFFE ID is equal to the
+	// prefix or a descendant of it (e.g. spiffe://example.org/team-a matches
+	// spiffe://example.org/team-a/db but not spiffe://example.org/team-ab).
+	SPIFFEIDPrefix spiffeid.ID
+
+	// ParentID matches registration entries with the given parent ID.
+	ParentID spiffeid.ID
+}
+
+// Scopes restricts the resources a caller can manage to those within at
+// least one of the scopes. Empty scopes do not restrict the caller.
+type Scopes []Scope
+
+// AllowsEntry returns true if a registration entry with the given SPIFFE ID
+// and parent ID is within the scopes.
+func (s Scopes) AllowsEntry(spiffeID, parentID spiffeid.ID) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for _, scope := range s {
+		if !scope.SPIFFEIDPrefix.IsZero() && !hasIDPrefix(spiffeID, scope.SPIFFEIDPrefix) {
+			continue
+		}
+		if !scope.ParentID.IsZero() && parentID != scope.ParentID {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// AllowsID returns true if a resource that is only identified by a SPIFFE ID,
+// like an agent or a minted SVID, is within the scopes. Scopes restricting
+// the parent ID never match such resources.
+func (s Scopes) AllowsID(id spiffeid.ID) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for _, scope := range s {
+		if scope.ParentID.IsZero() && hasIDPrefix(id, scope.SPIFFEIDPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IDPrefixes returns the SPIFFE ID prefixes of the scopes that match
+// resources only identified by a SPIFFE ID, i.e. the resources allowed by
+// AllowsID.
+func (s Scopes) IDPrefixes() []spiffeid.ID {
+	var prefixes []spiffeid.ID
+	for _, scope := range s {
+		if scope.ParentID.IsZero() {
+			prefixes = append(prefixes, scope.SPIFFEIDPrefix)
+		}
+	}
+	return prefixes
+}
+
+func hasIDPrefix(id, prefix spiffeid.ID) bool {
+	if id.TrustDomain() != prefix.TrustDomain() {
+		return false
+	}
+	return id.Path() == prefix.Path() || strings.HasPrefix(id.Path(), prefix.Path()+"/")
+}
+
+// scopeResult is the representation of a scope in the policy result.
+type scopeResult struct {
+	SPIFFEIDPrefix string `json:"spiffe_id_prefix"`
+	ParentID       string `json:"parent_id"`
+}
+
+// scopesFromResult parses the optional scopes of the policy result. Policies
+// written before scopes were introduced do not return them, which leaves
+// callers unrestricted.
+func scopesFromResult(resultMap map[string]any) (Scopes, error) {
+	value, ok := resultMap[scopesKey]
+	if !ok || value == nil {
+		return nil, nil
+	}
+
+	// The value is made of plain JSON types, so round-trip it to decode it.
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("policy: unable to marshal %q: %w", scopesKey, err)
+	}
+	var results []scopeResult
+	if err := json.Unmarshal(raw, &results); err != nil {
+		return nil, fmt.Errorf("policy: result %q must be a list of scopes: %w", scopesKey, err)
+	}
+
+	var scopes Scopes
+	for _, r := range results {
+		scope, err := r.toScope()
+		if err != nil {
+			return nil, fmt.Errorf("policy: invalid scope: %w", err)
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
+
+func (r scopeResult) toScope() (Scope, error) {
+	var scope Scope
+	if r.SPIFFEIDPrefix == "" && r.ParentID == "" {
+		return Scope{}, errors.New("spiffe_id_prefix or parent_id must be set")
+	}
+	if r.SPIFFEIDPrefix != "" {
+		id, err := spiffeid.FromString(r.SPIFFEIDPrefix)
+		if err != nil {
+			return Scope{}, fmt.Errorf("malformed spiffe_id_prefix %q: %w", r.SPIFFEIDPrefix, err)
+		}
+		scope.SPIFFEIDPrefix = id
+	}
+	if r.ParentID != "" {
+		id, err := spiffeid.FromString(r.ParentID)
+		if err != nil {
+			return Scope{}, fmt.Errorf("malformed parent_id %q: %w", r.ParentID, err)
+		}
+		scope.ParentID = id
+	}
+	return scope, nil
+}
@@ -0,0 +1,251 @@
+package authpolicy_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/server/authpolicy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopesAllowsEntry(t *testing.T) {
+	teamA := spiffeid.RequireFromString("spiffe://example.org/team-a")
+	teamADB := spiffeid.RequireFromString("spiffe://example.org/team-a/db")
+	teamAB := spiffeid.RequireFromString("spiffe://example.org/team-ab")
+	otherTD := spiffeid.RequireFromString("spiffe://other.org/team-a/db")
+	nodeA := spiffeid.RequireFromString("spiffe://example.org/spire/agent/node-a")
+	nodeB := spiffeid.RequireFromString("spiffe://example.org/spire/agent/node-b")
+
+	for _, tt := range []struct {
+		name     string
+		scopes   authpolicy.Scopes
+		spiffeID spiffeid.ID
+		parentID spiffeid.ID
+		expect   bool
+	}{
+		{
+			name:     "no scopes",
+			spiffeID: teamAB,
+			parentID: nodeB,
+			expect:   true,
+		},
+		{
+			name:     "prefix matches itself",
+			scopes:   authpolicy.Scopes{{SPIFFEIDPrefix: teamA}},
+			spiffeID: teamA,
+			parentID: nodeB,
+			expect:   true,
+		},
+		{
+			name:     "prefix matches descendant",
+			scopes:   authpolicy.Scopes{{SPIFFEIDPrefix: teamA}},
+			spiffeID: teamADB,
+			parentID: nodeB,
+			expect:   true,
+		},
+		{
+			name:     "prefix does not match sibling sharing the prefix string",
+			scopes:   authpolicy.Scopes{{SPIFFEIDPrefix: teamA}},
+			spiffeID: teamAB,
+			parentID: nodeB,
+			expect:   false,
+		},
+		{
+			name:     "prefix does not match other trust domain",
+			scopes:   authpolicy.Scopes{{SPIFFEIDPrefix: teamA}},
+			spiffeID: otherTD,
+			parentID: nodeB,
+			expect:   false,
+		},
+		{
+			name:     "parent ID matches",
+			scopes:   authpolicy.Scopes{{ParentID: nodeA}},
+			spiffeID: teamAB,
+			parentID: nodeA,
+			expect:   true,
+		},
+		{
+			name:     "parent ID does not match",
+			scopes:   authpolicy.Scopes{{ParentID: nodeA}},
+			spiffeID: teamAB,
+			parentID: nodeB,
+			expect:   false,
+		},
+		{
+			name:     "every field of a scope must match",
+			scopes:   authpolicy.Scopes{{SPIFFEIDPrefix: teamA, ParentID: nodeA}},
+			spiffeID: teamADB,
+			parentID: nodeB,
+			expect:   false,
+		},
+		{
+			name:     "any scope can match",
+			scopes:   authpolicy.Scopes{{ParentID: nodeA}, {SPIFFEIDPrefix: teamA}},
+			spiffeID: teamADB,
+			parentID: nodeB,
+			expect:   true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expect, tt.scopes.AllowsEntry(tt.spiffeID, tt.parentID))
+		})
+	}
+}
+
+func TestScopesAllowsID(t *testing.T) {
+	agents := spiffeid.RequireFromString("spiffe://example.org/spire/agent/k8s_psat/cluster-a")
+	agent := spiffeid.RequireFromString("spiffe://example.org/spire/agent/k8s_psat/cluster-a/node")
+	otherAgent := spiffeid.RequireFromString("spiffe://example.org/spire/agent/k8s_psat/cluster-b/node")
+
+	require.True(t, authpolicy.Scopes(nil).AllowsID(otherAgent))
+	require.True(t, authpolicy.Scopes{{SPIFFEIDPrefix: agents}}.AllowsID(agent))
+	require.False(t, authpolicy.Scopes{{SPIFFEIDPrefix: agents}}.AllowsID(otherAgent))
+
+	// Scopes restricting the parent ID never match resources without one
+	require.False(t, authpolicy.Scopes{{SPIFFEIDPrefix: agents, ParentID: agent}}.AllowsID(agent))
+	require.False(t, authpolicy.Scopes{{ParentID: agent}}.AllowsID(agent))
+
+	require.Empty(t, authpolicy.Scopes(nil).IDPrefixes())
+	require.Equal(t, []spiffeid.ID{agents}, authpolicy.Scopes{
+		{SPIFFEIDPrefix: agents},
+		{SPIFFEIDPrefix: agents, ParentID: agent},
+		{ParentID: agent},
+	}.IDPrefixes())
+}
+
+func TestEvalScopes(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		scopes       string
+		expectScopes authpolicy.Scopes
+		expectErr    string
+	}{
+		{
+			name:   "empty",
+			scopes: `[]`,
+		},
+		{
+			name:   "scopes",
+			scopes: `[{"spiffe_id_prefix": "spiffe://example.org/team-a"}, {"parent_id": "spiffe://example.org/node"}]`,
+			expectScopes: authpolicy.Scopes{
+				{SPIFFEIDPrefix: spiffeid.RequireFromString("spiffe://example.org/team-a")},
+				{ParentID: spiffeid.RequireFromString("spiffe://example.org/node")},
+			},
+		},
+		{
+			name:      "not a list",
+			scopes:    `"scope"`,
+			expectErr: `policy: result "scopes" must be a list of scopes`,
+		},
+		{
+			name:      "empty scope",
+			scopes:    `[{}]`,
+			expectErr: "policy: invalid scope: spiffe_id_prefix or parent_id must be set",
+		},
+		{
+			name:      "malformed prefix",
+			scopes:    `[{"spiffe_id_prefix": "/team-a"}]`,
+			expectErr: `policy: invalid scope: malformed spiffe_id_prefix "/team-a"`,
+		},
+		{
+			name:      "malformed parent ID",
+			scopes:    `[{"parent_id": "node"}]`,
+			expectErr: `policy: invalid scope: malformed parent_id "node"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			rego := `
+    package spire
+    result = {
+      "allow": true,
+      "allow_if_admin": false,
+      "allow_if_local": false,
+      "allow_if_downstream": false,
+      "allow_if_agent": false,
+      "scopes": scopes
+    }
+    default scopes = []
+    scopes = data.scopes if {
+        input.caller == "spiffe://example.org/scoped"
+    }
+    `
+			var scopes any
+			require.NoError(t, json.Unmarshal([]byte(tt.scopes), &scopes))
+			pe, err := authpolicy.NewEngineFromRego(ctx, rego, inmem.NewFromObject(map[string]any{"scopes": scopes}))
+			require.NoError(t, err)
+
+			res, err := pe.Eval(ctx, authpolicy.Input{Caller: "spiffe://example.org/scoped"})
+			if tt.expectErr != "" {
+				require.ErrorContains(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectScopes, res.Scopes)
+		})
+	}
+}
+
+func TestDefaultPolicyScopes(t *testing.T) {
+	ctx := context.Background()
+
+	rego, err := os.ReadFile("policy.rego")
+	require.NoError(t, err)
+	dataJSON, err := os.ReadFile("policy_data.json")
+	require.NoError(t, err)
+	var data map[string]any
+	require.NoError(t, json.Unmarshal(dataJSON, &data))
+
+	input := authpolicy.Input{
+		Caller:     "spiffe://example.org/team-a/registrar",
+		FullMethod: "/spire.api.server.entry.v1.Entry/BatchCreateEntry",
+	}
+
+	// Without admin scopes, the default policy does not restrict callers
+	pe, err := authpolicy.NewEngineFromRego(ctx, string(rego), inmem.NewFromObject(data))
+	require.NoError(t, err)
+	res, err := pe.Eval(ctx, input)
+	require.NoError(t, err)
+	require.Equal(t, authpolicy.Result{AllowIfAdmin: true, AllowIfLocal: true}, res)
+
+	data["admin_scopes"] = map[string]any{
+		"spiffe://example.org/team-a/registrar": []any{
+			map[string]any{"spiffe_id_prefix": "spiffe://example.org/team-a"},
+		},
+	}
+	pe, err = authpolicy.NewEngineFromRego(ctx, string(rego), inmem.NewFromObject(data))
+	require.NoError(t, err)
+	res, err = pe.Eval(ctx, input)
+	require.NoError(t, err)
+	require.Equal(t, authpolicy.Result{
+		AllowIfAdmin: true,
+		AllowIfLocal: true,
+		Scopes: authpolicy.Scopes{
+			{SPIFFEIDPrefix: spiffeid.RequireFromString("spiffe://example.org/team-a")},
+		},
+	}, res)
+
+	// Scoped callers are not granted admin rights on the methods that do
+	// not enforce scopes, but keep the rights granted to everyone
+	input.FullMethod = "/spire.api.server.bundle.v1.Bundle/BatchSetFederatedBundle"
+	res, err = pe.Eval(ctx, input)
+	require.NoError(t, err)
+	require.False(t, res.AllowIfAdmin)
+	require.False(t, res.AllowIfLocal)
+	input.FullMethod = "/spire.api.server.bundle.v1.Bundle/GetBundle"
+	res, err = pe.Eval(ctx, input)
+	require.NoError(t, err)
+	require.True(t, res.Allow)
+
+	// Other callers are not restricted
+	input.Caller = "spiffe://example.org/team-b/registrar"
+	input.FullMethod = "/spire.api.server.bundle.v1.Bundle/BatchSetFederatedBundle"
+	res, err = pe.Eval(ctx, input)
+	require.NoError(t, err)
+	require.Empty(t, res.Scopes)
+	require.True(t, res.AllowIfAdmin)
+}
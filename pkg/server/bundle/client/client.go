@@ -38,6 +38,14 @@ type ClientConfig struct { //revive:disable-line:exported name stutter is intent
 	// is authenticated via Web PKI.
 	SPIFFEAuth *SPIFFEAuthConfig
 
+	// SignedFile contains the configuration to read signed bundles from the
+	// local filesystem instead of fetching them from a bundle endpoint.
+	SignedFile *SignedFileConfig
+
+	// Hub indicates the endpoint is a federation hub, serving the bundles of
+	// many trust domains instead of a single bundle.
+	Hub bool
+
 	// TLSPolicy specifies the post-quantum-security policy used for TLS
 	// connections.
 	TLSPolicy tlspolicy.Policy
@@ -52,12 +60,22 @@ type Client interface {
 	FetchBundle(context.Context) (*spiffebundle.Bundle, error)
 }
 
+// HubClient is used to fetch the bundles of many trust domains from a
+// federation hub in a single request.
+type HubClient interface {
+	FetchHubBundles(context.Context) (map[spiffeid.TrustDomain]*spiffebundle.Bundle, error)
+}
+
 type client struct {
 	c      ClientConfig
 	client *http.Client
 }
 
 func NewClient(config ClientConfig) (Client, error) {
+	if config.SignedFile != nil {
+		return newSignedFileClient(config)
+	}
+
 	transport := newTransport()
 	if config.SPIFFEAuth != nil {
 		endpointID := config.SPIFFEAuth.EndpointSpiffeID
@@ -85,7 +103,44 @@ func NewClient(config ClientConfig) (Client, error) {
 	}, nil
 }
 
-func (c *client) FetchBundle(context.Context) (*spiffebundle.Bundle, error) {
+func (c *client) FetchBundle(ctx context.Context) (*spiffebundle.Bundle, error) {
+	if c.c.Hub {
+		bundles, err := c.FetchHubBundles(ctx)
+		if err != nil {
+			return nil, err
+		}
+		b, ok := bundles[c.c.TrustDomain]
+		if !ok {
+			return nil, notServedByHubError(c.c.TrustDomain)
+		}
+		return b, nil
+	}
+
+	resp, err := c.get()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := bundleutil.Decode(c.c.TrustDomain, resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (c *client) FetchHubBundles(context.Context) (map[spiffeid.TrustDomain]*spiffebundle.Bundle, error) {
+	resp, err := c.get()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return bundleutil.DecodeHub(resp.Body)
+}
+
+func (c *client) get() (*http.Response, error) {
 	resp, err := c.client.Get(c.c.EndpointURL)
 	if err != nil {
 		var hostnameError x509.HostnameError
@@ -96,18 +151,16 @@ func (c *client) FetchBundle(context.Context) (*spiffebundle.Bundle, error) {
 		}
 		return nil, fmt.Errorf("failed to fetch bundle: %w", err)
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
 		return nil, fmt.Errorf("unexpected status %d fetching bundle: %s", resp.StatusCode, tryRead(resp.Body))
 	}
+	return resp, nil
+}
 
-	b, err := bundleutil.Decode(c.c.TrustDomain, resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	return b, nil
+func notServedByHubError(td spiffeid.TrustDomain) error {
+	return fmt.Errorf("bundle for %q is not served by the federation hub", td.Name())
 }
 
 func tryRead(r io.Reader) string {
@@ -76,6 +76,38 @@ func TestClient(t *testing.T) {
 			expectedID:     serverID,
 			fetchBundleErr: "failed to decode bundle",
 		},
+		{
+			name:       "hub",
+			status:     http.StatusOK,
+			body:       `{"bundles": {"domain.test": {"spiffe_refresh_hint": 10}, "other.test": {}}}`,
+			serverID:   serverID,
+			expectedID: serverID,
+			mutateConfig: func(c *ClientConfig) {
+				c.Hub = true
+			},
+		},
+		{
+			name:           "hub does not serve the trust domain",
+			status:         http.StatusOK,
+			body:           `{"bundles": {"other.test": {}}}`,
+			serverID:       serverID,
+			expectedID:     serverID,
+			fetchBundleErr: `bundle for "domain.test" is not served by the federation hub`,
+			mutateConfig: func(c *ClientConfig) {
+				c.Hub = true
+			},
+		},
+		{
+			name:           "invalid hub content",
+			status:         http.StatusOK,
+			body:           "NOT JSON",
+			serverID:       serverID,
+			expectedID:     serverID,
+			fetchBundleErr: "failed to decode hub bundles",
+			mutateConfig: func(c *ClientConfig) {
+				c.Hub = true
+			},
+		},
 		{
 			name:           "hostname validation fails",
 			status:         http.StatusOK,
@@ -0,0 +1,199 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/spiffe/go-spiffe/v2/bundle/spiffebundle"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/common/bundleutil"
+	"github.com/spiffe/spire/pkg/common/pemutil"
+)
+
+const (
+	// signatureFileExt is the extension appended to the path of a bundle file
+	// to get the path of its detached JWS signature.
+	signatureFileExt = ".jws"
+
+	// trustDomainHeader is the protected header of the detached JWS that
+	// names the trust domain of the bundle. Bundles do not name their trust
+	// domain, so without it a bundle signed for one trust domain could be
+	// dropped in place of the bundle of another trust domain trusting the
+	// same signer.
+	trustDomainHeader = "spiffe_trust_domain"
+)
+
+var signedBundleAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256,
+	jose.RS384,
+	jose.RS512,
+	jose.ES256,
+	jose.ES384,
+	jose.ES512,
+	jose.PS256,
+	jose.PS384,
+	jose.PS512,
+	jose.EdDSA,
+}
+
+type SignedFileConfig struct {
+	// Path is the path of the bundle file, or of a directory holding the
+	// bundle file of the trust domain, named "<trust domain>.json". The
+	// detached JWS signature of the bundle file is read from the same path
+	// with the ".jws" extension appended.
+	Path string
+
+	// TrustedSignerKeysPath is the path of a PEM file with the public keys,
+	// or certificates, of the signers trusted to sign the bundle.
+	TrustedSignerKeysPath string
+
+	// StoredSequenceNumber is the sequence number of the bundle currently
+	// stored for the trust domain. Bundles with a lower sequence number are
+	// rejected, so an old signed bundle cannot be replayed to roll back the
+	// trust domain keys.
+	StoredSequenceNumber uint64
+}
+
+type signedFileClient struct {
+	c ClientConfig
+}
+
+func newSignedFileClient(config ClientConfig) (Client, error) {
+	if config.SignedFile.Path == "" {
+		return nil, fmt.Errorf("no bundle path specified for federation with %q", config.TrustDomain.Name())
+	}
+	if config.SignedFile.TrustedSignerKeysPath == "" {
+		return nil, fmt.Errorf("no trusted signer keys specified for federation with %q", config.TrustDomain.Name())
+	}
+	return &signedFileClient{c: config}, nil
+}
+
+// FetchBundle reads the bundle file, verifies its signature was made by one
+// of the trusted signers, and decodes it. Files are read on every fetch so
+// new bundles and signer keys can be dropped in place. Bundles older than the
+// stored bundle, according to their sequence number, are rejected.
+func (c *signedFileClient) FetchBundle(context.Context) (*spiffebundle.Bundle, error) {
+	path := c.c.SignedFile.Path
+	if info, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to fetch bundle: %w", err)
+	} else if info.IsDir() {
+		path = filepath.Join(path, c.c.TrustDomain.Name()+".json")
+	}
+
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bundle: %w", err)
+	}
+	signature, err := os.ReadFile(path + signatureFileExt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bundle signature: %w", err)
+	}
+
+	signerKeys, err := loadTrustedSignerKeys(c.c.SignedFile.TrustedSignerKeysPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyDetachedSignature(payload, signature, signerKeys, c.c.TrustDomain); err != nil {
+		return nil, fmt.Errorf("failed to verify bundle signature of %q: %w", path, err)
+	}
+
+	bundle, err := bundleutil.Decode(c.c.TrustDomain, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	// Bundles without a sequence number are treated as the first one
+	sequenceNumber, _ := bundle.SequenceNumber()
+	if sequenceNumber < c.c.SignedFile.StoredSequenceNumber {
+		return nil, fmt.Errorf("bundle sequence number %d of %q is lower than the sequence number %d of the stored bundle", sequenceNumber, path, c.c.SignedFile.StoredSequenceNumber)
+	}
+	return bundle, nil
+}
+
+func loadTrustedSignerKeys(path string) ([]crypto.PublicKey, error) {
+	blocks, err := pemutil.LoadBlocks(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trusted signer keys: %w", err)
+	}
+
+	var keys []crypto.PublicKey
+	for _, block := range blocks {
+		switch block.Type {
+		case "CERTIFICATE":
+			if cert, ok := block.Object.(*x509.Certificate); ok {
+				keys = append(keys, cert.PublicKey)
+			}
+		case "PUBLIC KEY":
+			keys = append(keys, block.Object)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("failed to load trusted signer keys: no public key or certificate found in %q", path)
+	}
+	return keys, nil
+}
+
+// verifyDetachedSignature verifies the signature was made over the payload by
+// one of the signer keys, for the given trust domain.
+func verifyDetachedSignature(payload, signature []byte, signerKeys []crypto.PublicKey, td spiffeid.TrustDomain) error {
+	jws, err := jose.ParseDetached(strings.TrimSpace(string(signature)), payload, signedBundleAlgorithms)
+	if err != nil {
+		return fmt.Errorf("malformed detached JWS: %w", err)
+	}
+	for _, key := range signerKeys {
+		_, verified, err := jws.DetachedVerifyMulti(payload, key)
+		if err != nil {
+			continue
+		}
+
+		// Only the protected header is covered by the signature
+		value, ok := verified.Protected.ExtraHeaders[trustDomainHeader]
+		if !ok {
+			return fmt.Errorf("signature has no %q protected header", trustDomainHeader)
+		}
+		signedTD, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("signature %q protected header is not a string", trustDomainHeader)
+		}
+		if signedTD != td.Name() {
+			return fmt.Errorf("signature was made for trust domain %q, not %q", signedTD, td.Name())
+		}
+		return nil
+	}
+	return errors.New("signature was not made by a trusted signer")
+}
+
+// FilePathFromURL returns the local path of a file URL (e.g.
+// file:///var/lib/spire/bundles).
+func FilePathFromURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("expected file URL; got scheme %q", u.Scheme)
+	}
+	if u.Host != "" && u.Host != "localhost" {
+		return "", fmt.Errorf("file URL must not have a remote host; got %q", u.Host)
+	}
+	if u.Path == "" {
+		return "", errors.New("file URL has no path")
+	}
+
+	path := u.Path
+	// Drop the leading slash of Windows paths (e.g. file:///C:/bundles)
+	if runtime.GOOS == "windows" && len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+	return filepath.FromSlash(path), nil
+}
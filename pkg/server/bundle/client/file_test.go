@@ -0,0 +1,285 @@
+package client
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/spiffe/go-spiffe/v2/bundle/spiffebundle"
+	"github.com/spiffe/spire/pkg/common/pemutil"
+	"github.com/spiffe/spire/test/testkey"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedFileClient(t *testing.T) {
+	signerKey := testkey.NewEC256(t)
+	otherSignerKey := testkey.NewEC256(t)
+
+	bundle := spiffebundle.FromX509Authorities(trustDomain, []*x509.Certificate{createCACertificate(t, "bundle")})
+	bundle.SetRefreshHint(0)
+	bundleBytes, err := bundle.Marshal()
+	require.NoError(t, err)
+
+	signerCert := createCACertificateWithKey(t, "signer", otherSignerKey)
+
+	for _, tt := range []struct {
+		name string
+		// setup writes the bundle files into dir and returns the path
+		// configured on the client
+		setup      func(t *testing.T, dir string) string
+		signerKeys []byte
+		expectErr  string
+	}{
+		{
+			name: "bundle file",
+			setup: func(t *testing.T, dir string) string {
+				return writeSignedBundle(t, filepath.Join(dir, "bundle.json"), bundleBytes, signerKey)
+			},
+			signerKeys: encodePublicKey(t, signerKey.Public()),
+		},
+		{
+			name: "bundle directory",
+			setup: func(t *testing.T, dir string) string {
+				writeSignedBundle(t, filepath.Join(dir, "domain.test.json"), bundleBytes, signerKey)
+				return dir
+			},
+			signerKeys: encodePublicKey(t, signerKey.Public()),
+		},
+		{
+			name: "trusted signer certificate",
+			setup: func(t *testing.T, dir string) string {
+				return writeSignedBundle(t, filepath.Join(dir, "bundle.json"), bundleBytes, otherSignerKey)
+			},
+			signerKeys: append(encodePublicKey(t, signerKey.Public()), pemutil.EncodeCertificate(signerCert)...),
+		},
+		{
+			name: "untrusted signer",
+			setup: func(t *testing.T, dir string) string {
+				return writeSignedBundle(t, filepath.Join(dir, "bundle.json"), bundleBytes, otherSignerKey)
+			},
+			signerKeys: encodePublicKey(t, signerKey.Public()),
+			expectErr:  "signature was not made by a trusted signer",
+		},
+		{
+			name: "tampered bundle",
+			setup: func(t *testing.T, dir string) string {
+				path := writeSignedBundle(t, filepath.Join(dir, "bundle.json"), bundleBytes, signerKey)
+				require.NoError(t, os.WriteFile(path, append(bundleBytes, ' '), 0o600))
+				return path
+			},
+			signerKeys: encodePublicKey(t, signerKey.Public()),
+			expectErr:  "signature was not made by a trusted signer",
+		},
+		{
+			name: "bundle signed for another trust domain",
+			setup: func(t *testing.T, dir string) string {
+				// The signer also signs the bundles of other trust domains,
+				// which must not be accepted in place of this one
+				return writeSignedBundleWithHeaders(t, filepath.Join(dir, "bundle.json"), bundleBytes, signerKey, map[jose.HeaderKey]any{
+					trustDomainHeader: "other.test",
+				})
+			},
+			signerKeys: encodePublicKey(t, signerKey.Public()),
+			expectErr:  `signature was made for trust domain "other.test", not "domain.test"`,
+		},
+		{
+			name: "bundle directory with a bundle signed for another trust domain",
+			setup: func(t *testing.T, dir string) string {
+				writeSignedBundleWithHeaders(t, filepath.Join(dir, "domain.test.json"), bundleBytes, signerKey, map[jose.HeaderKey]any{
+					trustDomainHeader: "other.test",
+				})
+				return dir
+			},
+			signerKeys: encodePublicKey(t, signerKey.Public()),
+			expectErr:  `signature was made for trust domain "other.test", not "domain.test"`,
+		},
+		{
+			name: "signature without trust domain",
+			setup: func(t *testing.T, dir string) string {
+				return writeSignedBundleWithHeaders(t, filepath.Join(dir, "bundle.json"), bundleBytes, signerKey, nil)
+			},
+			signerKeys: encodePublicKey(t, signerKey.Public()),
+			expectErr:  `signature has no "spiffe_trust_domain" protected header`,
+		},
+		{
+			name: "malformed trust domain header",
+			setup: func(t *testing.T, dir string) string {
+				return writeSignedBundleWithHeaders(t, filepath.Join(dir, "bundle.json"), bundleBytes, signerKey, map[jose.HeaderKey]any{
+					trustDomainHeader: 42,
+				})
+			},
+			signerKeys: encodePublicKey(t, signerKey.Public()),
+			expectErr:  `signature "spiffe_trust_domain" protected header is not a string`,
+		},
+		{
+			name: "missing signature",
+			setup: func(t *testing.T, dir string) string {
+				path := filepath.Join(dir, "bundle.json")
+				require.NoError(t, os.WriteFile(path, bundleBytes, 0o600))
+				return path
+			},
+			signerKeys: encodePublicKey(t, signerKey.Public()),
+			expectErr:  "failed to fetch bundle signature",
+		},
+		{
+			name: "malformed signature",
+			setup: func(t *testing.T, dir string) string {
+				path := filepath.Join(dir, "bundle.json")
+				require.NoError(t, os.WriteFile(path, bundleBytes, 0o600))
+				require.NoError(t, os.WriteFile(path+".jws", []byte("malformed"), 0o600))
+				return path
+			},
+			signerKeys: encodePublicKey(t, signerKey.Public()),
+			expectErr:  "malformed detached JWS",
+		},
+		{
+			name: "missing bundle",
+			setup: func(t *testing.T, dir string) string {
+				return filepath.Join(dir, "bundle.json")
+			},
+			signerKeys: encodePublicKey(t, signerKey.Public()),
+			expectErr:  "failed to fetch bundle",
+		},
+		{
+			name: "no trusted signer keys",
+			setup: func(t *testing.T, dir string) string {
+				return writeSignedBundle(t, filepath.Join(dir, "bundle.json"), bundleBytes, signerKey)
+			},
+			signerKeys: pemutil.EncodeCertificates(nil),
+			expectErr:  "failed to load trusted signer keys",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			signerKeysPath := filepath.Join(t.TempDir(), "signers.pem")
+			require.NoError(t, os.WriteFile(signerKeysPath, tt.signerKeys, 0o600))
+
+			client, err := NewClient(ClientConfig{
+				TrustDomain: trustDomain,
+				SignedFile: &SignedFileConfig{
+					Path:                  tt.setup(t, dir),
+					TrustedSignerKeysPath: signerKeysPath,
+				},
+			})
+			require.NoError(t, err)
+
+			fetched, err := client.FetchBundle(context.Background())
+			if tt.expectErr != "" {
+				require.ErrorContains(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, fetched.Equal(bundle))
+		})
+	}
+}
+
+func TestSignedFileClientSequenceNumber(t *testing.T) {
+	signerKey := testkey.NewEC256(t)
+	signerKeysPath := filepath.Join(t.TempDir(), "signers.pem")
+	require.NoError(t, os.WriteFile(signerKeysPath, encodePublicKey(t, signerKey.Public()), 0o600))
+
+	bundle := spiffebundle.FromX509Authorities(trustDomain, []*x509.Certificate{createCACertificate(t, "bundle")})
+	bundle.SetSequenceNumber(5)
+	bundleBytes, err := bundle.Marshal()
+	require.NoError(t, err)
+	path := writeSignedBundle(t, filepath.Join(t.TempDir(), "bundle.json"), bundleBytes, signerKey)
+
+	fetchBundle := func(storedSequenceNumber uint64) (*spiffebundle.Bundle, error) {
+		client, err := NewClient(ClientConfig{
+			TrustDomain: trustDomain,
+			SignedFile: &SignedFileConfig{
+				Path:                  path,
+				TrustedSignerKeysPath: signerKeysPath,
+				StoredSequenceNumber:  storedSequenceNumber,
+			},
+		})
+		require.NoError(t, err)
+		return client.FetchBundle(context.Background())
+	}
+
+	// Newer and same bundles are accepted
+	for _, storedSequenceNumber := range []uint64{0, 4, 5} {
+		fetched, err := fetchBundle(storedSequenceNumber)
+		require.NoError(t, err)
+		sequenceNumber, ok := fetched.SequenceNumber()
+		require.True(t, ok)
+		require.Equal(t, uint64(5), sequenceNumber)
+	}
+
+	// Older bundles are rejected
+	_, err = fetchBundle(6)
+	require.EqualError(t, err, `bundle sequence number 5 of "`+path+`" is lower than the sequence number 6 of the stored bundle`)
+}
+
+func TestSignedFileClientConfig(t *testing.T) {
+	_, err := NewClient(ClientConfig{
+		TrustDomain: trustDomain,
+		SignedFile:  &SignedFileConfig{TrustedSignerKeysPath: "signers.pem"},
+	})
+	require.EqualError(t, err, `no bundle path specified for federation with "domain.test"`)
+
+	_, err = NewClient(ClientConfig{
+		TrustDomain: trustDomain,
+		SignedFile:  &SignedFileConfig{Path: "bundle.json"},
+	})
+	require.EqualError(t, err, `no trusted signer keys specified for federation with "domain.test"`)
+}
+
+func TestFilePathFromURL(t *testing.T) {
+	path, err := FilePathFromURL("file:///var/lib/spire/bundles")
+	require.NoError(t, err)
+	require.Equal(t, filepath.FromSlash("/var/lib/spire/bundles"), path)
+
+	path, err = FilePathFromURL("file://localhost/var/lib/spire/bundle.json")
+	require.NoError(t, err)
+	require.Equal(t, filepath.FromSlash("/var/lib/spire/bundle.json"), path)
+
+	if runtime.GOOS == "windows" {
+		path, err = FilePathFromURL("file:///C:/spire/bundles")
+		require.NoError(t, err)
+		require.Equal(t, `C:\spire\bundles`, path)
+	}
+
+	_, err = FilePathFromURL("https://example.org/bundle")
+	require.EqualError(t, err, `expected file URL; got scheme "https"`)
+
+	_, err = FilePathFromURL("file://remote.test/bundle.json")
+	require.EqualError(t, err, `file URL must not have a remote host; got "remote.test"`)
+
+	_, err = FilePathFromURL("file://")
+	require.EqualError(t, err, "file URL has no path")
+}
+
+func writeSignedBundle(t *testing.T, path string, bundleBytes []byte, key crypto.Signer) string {
+	return writeSignedBundleWithHeaders(t, path, bundleBytes, key, map[jose.HeaderKey]any{
+		trustDomainHeader: trustDomain.Name(),
+	})
+}
+
+func writeSignedBundleWithHeaders(t *testing.T, path string, bundleBytes []byte, key crypto.Signer, headers map[jose.HeaderKey]any) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: key}, &jose.SignerOptions{
+		ExtraHeaders: headers,
+	})
+	require.NoError(t, err)
+	jws, err := signer.Sign(bundleBytes)
+	require.NoError(t, err)
+	signature, err := jws.DetachedCompactSerialize()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, bundleBytes, 0o600))
+	require.NoError(t, os.WriteFile(path+".jws", []byte(signature+"\n"), 0o600))
+	return path
+}
+
+func encodePublicKey(t *testing.T, publicKey crypto.PublicKey) []byte {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"maps"
 	"sync"
 	"time"
@@ -42,6 +43,11 @@ type TrustDomainConfig struct {
 	// EndpointProfile is the bundle endpoint profile used by the
 	// SPIFFE bundle endpoint server.
 	EndpointProfile EndpointProfileInfo
+
+	// Hub indicates the endpoint is a federation hub serving the bundles of
+	// many trust domains. The trust domains sharing the same hub endpoint
+	// are refreshed together with a single poll.
+	Hub bool
 }
 
 type EndpointProfileInfo interface {
@@ -64,6 +70,18 @@ func (p HTTPSSPIFFEProfile) Name() string {
 	return "https_spiffe"
 }
 
+// FileProfile reads the bundle from the local filesystem, for trust domains
+// that cannot be reached over the network. The endpoint URL is a file URL.
+type FileProfile struct {
+	// TrustedSignerKeysPath is the path of a PEM file with the public keys,
+	// or certificates, of the signers trusted to sign the bundle.
+	TrustedSignerKeysPath string
+}
+
+func (p FileProfile) Name() string {
+	return "file"
+}
+
 type ManagerConfig struct {
 	Log       logrus.FieldLogger
 	Metrics   telemetry.Metrics
@@ -94,6 +112,10 @@ type Manager struct {
 	updatersMtx      sync.RWMutex
 	updaters         map[spiffeid.TrustDomain]*managedBundleUpdater
 
+	// hubs holds the pollers of the federation hubs, keyed by the trust
+	// domain config shared by the trust domains refreshed from each of them.
+	hubs map[TrustDomainConfig]*managedHub
+
 	// test hooks
 	newBundleUpdater  func(BundleUpdaterConfig) BundleUpdater
 	configRefreshedCh chan time.Duration
@@ -113,6 +135,27 @@ func (m *managedBundleUpdater) Stop() {
 	m.wg.Wait()
 }
 
+type managedHub struct {
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+	wakeCh chan struct{}
+}
+
+func (m *managedHub) Stop() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+// Wake makes the hub poller reevaluate which trust domains are due to be
+// refreshed, so trust domains that started being refreshed from the hub
+// don't wait for the next scheduled poll.
+func (m *managedHub) Wake() {
+	select {
+	case m.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
 func NewManager(config ManagerConfig) *Manager {
 	if config.Clock == nil {
 		config.Clock = clock.New()
@@ -132,6 +175,7 @@ func NewManager(config ManagerConfig) *Manager {
 		configRefreshedCh: config.configRefreshedCh,
 		bundleRefreshedCh: config.bundleRefreshedCh,
 		updaters:          make(map[spiffeid.TrustDomain]*managedBundleUpdater),
+		hubs:              make(map[TrustDomainConfig]*managedHub),
 	}
 }
 
@@ -185,6 +229,23 @@ func (m *Manager) RefreshBundleFor(ctx context.Context, td spiffeid.TrustDomain)
 	return true, err
 }
 
+// loadSafeguards returns the bundle safeguards of each federated trust
+// domain, as stored in the options of its federation relationship.
+func (m *Manager) loadSafeguards(ctx context.Context) (map[spiffeid.TrustDomain]BundleSafeguards, error) {
+	resp, err := m.ds.ListFederationRelationships(ctx, &datastore.ListFederationRelationshipsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list federation relationships: %w", err)
+	}
+
+	safeguards := make(map[spiffeid.TrustDomain]BundleSafeguards)
+	for _, fr := range resp.FederationRelationships {
+		if fr.Options != nil && fr.Options.BundleSafeguards != nil {
+			safeguards[fr.TrustDomain] = BundleSafeguardsFromProto(fr.Options.BundleSafeguards)
+		}
+	}
+	return safeguards, nil
+}
+
 func (m *Manager) refreshConfigs(ctx context.Context) error {
 	m.configRefreshMtx.Lock()
 	defer m.configRefreshMtx.Unlock()
@@ -198,6 +259,11 @@ func (m *Manager) refreshConfigs(ctx context.Context) error {
 	// out what needs to be started/updated/stopped.
 	configs = cloneTrustDomainConfigs(configs)
 
+	safeguards, err := m.loadSafeguards(ctx)
+	if err != nil {
+		return err
+	}
+
 	var toStop []func()
 	defer func() {
 		if len(toStop) > 0 {
@@ -212,9 +278,27 @@ func (m *Manager) refreshConfigs(ctx context.Context) error {
 	m.updatersMtx.Lock()
 	defer m.updatersMtx.Unlock()
 
+	// Hub configs that trust domains started being refreshed with. The
+	// pollers of these hubs need to refresh those trust domains right away.
+	wakeHubs := make(map[TrustDomainConfig]struct{})
+
 	for td, updater := range m.updaters {
 		tdLog := m.log.WithField(telemetry.Entry, td)
-		if config, ok := configs[td]; ok {
+		config, ok := configs[td]
+		switch {
+		case !ok:
+			// Updater no longer needed. Stage it to be stopped and remove it
+			// from the updaters list.
+			tdLog.Info("Trust domain no longer managed")
+			toStop = append(toStop, updater.Stop)
+			delete(m.updaters, td)
+		case config.Hub != updater.GetTrustDomainConfig().Hub:
+			// Updater still needed, but switching between being refreshed
+			// on its own and from a federation hub. Stage it to be stopped
+			// and keep the config so a new updater is started.
+			toStop = append(toStop, updater.Stop)
+			delete(m.updaters, td)
+		default:
 			// Updater still needed. Update the configuration and remove it
 			// from the configs list since so a new updater isn't started for
 			// this trust domain.
@@ -223,14 +307,14 @@ func (m *Manager) refreshConfigs(ctx context.Context) error {
 					telemetry.BundleEndpointURL:     config.EndpointURL,
 					telemetry.BundleEndpointProfile: config.EndpointProfile.Name(),
 				}).Info("Updated configuration for managed trust domain")
+				if config.Hub {
+					wakeHubs[config] = struct{}{}
+				}
+			}
+			if updater.SetSafeguards(safeguards[td]) {
+				tdLog.Info("Updated bundle safeguards for managed trust domain")
 			}
 			delete(configs, td)
-		} else {
-			// Updater no longer needed. Stage it to be stopped and remove it
-			// from the updaters list.
-			tdLog.Info("Trust domain no longer managed")
-			toStop = append(toStop, updater.Stop)
-			delete(m.updaters, td)
 		}
 	}
 
@@ -248,15 +332,58 @@ func (m *Manager) refreshConfigs(ctx context.Context) error {
 				TrustDomainConfig: config,
 				TrustDomain:       td,
 				DataStore:         m.ds,
+				Safeguards:        safeguards[td],
+				Clock:             m.clock,
 			}),
 			cancel: cancel,
 			runCh:  make(chan chan error),
 		}
 		m.updaters[td] = updater
+		if config.Hub {
+			// Refreshed by the poller of the federation hub
+			wakeHubs[config] = struct{}{}
+			continue
+		}
 		updater.wg.Go(func() {
 			m.runUpdater(ctx, td, updater)
 		})
 	}
+
+	// Start a poller for each federation hub newly in use, and stop those
+	// of hubs no longer in use.
+	hubConfigs := make(map[TrustDomainConfig]struct{})
+	for _, updater := range m.updaters {
+		if config := updater.GetTrustDomainConfig(); config.Hub {
+			hubConfigs[config] = struct{}{}
+		}
+	}
+	for config, hub := range m.hubs {
+		if _, ok := hubConfigs[config]; ok {
+			if _, ok := wakeHubs[config]; ok {
+				hub.Wake()
+			}
+			delete(hubConfigs, config)
+			continue
+		}
+		m.log.WithField(telemetry.BundleEndpointURL, config.EndpointURL).Info("Federation hub no longer used")
+		toStop = append(toStop, hub.Stop)
+		delete(m.hubs, config)
+	}
+	for config := range hubConfigs {
+		m.log.WithFields(logrus.Fields{
+			telemetry.BundleEndpointURL:     config.EndpointURL,
+			telemetry.BundleEndpointProfile: config.EndpointProfile.Name(),
+		}).Info("Federation hub is now used")
+		ctx, cancel := context.WithCancel(ctx)
+		hub := &managedHub{
+			cancel: cancel,
+			wakeCh: make(chan struct{}, 1),
+		}
+		m.hubs[config] = hub
+		hub.wg.Go(func() {
+			m.runHub(ctx, config, hub.wakeCh)
+		})
+	}
 	return nil
 }
 
@@ -268,7 +395,7 @@ func (m *Manager) runUpdater(ctx context.Context, trustDomain spiffeid.TrustDoma
 
 	log := m.log.WithField("trust_domain", trustDomain.Name())
 	for {
-		nextRefresh := m.runUpdateOnce(ctx, log, trustDomain, updater)
+		nextRefresh := m.runUpdateOnce(ctx, log, trustDomain, updater.UpdateBundle)
 
 		log.WithFields(logrus.Fields{
 			"at": m.clock.Now().Add(nextRefresh).UTC().Format(time.RFC3339),
@@ -288,7 +415,103 @@ func (m *Manager) runUpdater(ctx context.Context, trustDomain spiffeid.TrustDoma
 	}
 }
 
-func (m *Manager) runUpdateOnce(ctx context.Context, log *logrus.Entry, trustDomain spiffeid.TrustDomain, updater BundleUpdater) time.Duration {
+// runHub polls a federation hub and updates the bundles of the trust domains
+// refreshed from it. Each trust domain is refreshed on its own schedule,
+// according to the refresh hint of its bundle, and the hub is only polled
+// when at least one of them is due.
+func (m *Manager) runHub(ctx context.Context, config TrustDomainConfig, wakeCh <-chan struct{}) {
+	// Initialize the timer. The initial duration does not matter since it will
+	// be reset with the actual refresh interval before first use.
+	timer := m.clock.Timer(time.Hour)
+	defer timer.Stop()
+
+	// When the bundle of each trust domain refreshed from the hub is due to
+	// be refreshed next.
+	nextRefreshes := make(map[spiffeid.TrustDomain]time.Time)
+
+	log := m.log.WithField(telemetry.BundleEndpointURL, config.EndpointURL)
+	for {
+		nextRefresh := m.runHubUpdateOnce(ctx, log, config, nextRefreshes)
+
+		log.WithFields(logrus.Fields{
+			"at": m.clock.Now().Add(nextRefresh).UTC().Format(time.RFC3339),
+		}).Debug("Scheduling next federation hub refresh")
+
+		// Notify the test hook
+		timer.Reset(nextRefresh)
+
+		m.notifyBundleRefreshed(ctx, nextRefresh)
+
+		select {
+		case <-timer.C:
+		case <-wakeCh:
+		case <-ctx.Done():
+			log.Info("No longer polling federation hub for updates")
+			return
+		}
+	}
+}
+
+func (m *Manager) runHubUpdateOnce(ctx context.Context, log *logrus.Entry, config TrustDomainConfig, nextRefreshes map[spiffeid.TrustDomain]time.Time) time.Duration {
+	updaters := make(map[spiffeid.TrustDomain]BundleUpdater)
+	m.updatersMtx.RLock()
+	for td, updater := range m.updaters {
+		if updater.GetTrustDomainConfig() == config {
+			updaters[td] = updater
+		}
+	}
+	m.updatersMtx.RUnlock()
+
+	// Forget the trust domains no longer refreshed from the hub
+	for td := range nextRefreshes {
+		if _, ok := updaters[td]; !ok {
+			delete(nextRefreshes, td)
+		}
+	}
+
+	// Only the trust domains that are due are refreshed. Trust domains that
+	// were not refreshed from the hub before are due right away. Every
+	// updater shares the hub configuration, so any of them can fetch the
+	// bundles for all of them.
+	now := m.clock.Now()
+	var hubFetcher BundleUpdater
+	dueUpdaters := make(map[spiffeid.TrustDomain]BundleUpdater)
+	for td, updater := range updaters {
+		if nextRefresh, ok := nextRefreshes[td]; !ok || !nextRefresh.After(now) {
+			dueUpdaters[td] = updater
+			hubFetcher = updater
+		}
+	}
+
+	if hubFetcher != nil {
+		log.Debug("Polling federation hub for bundle updates")
+		hubBundles, hubErr := hubFetcher.FetchHubBundles(ctx)
+
+		for td, updater := range dueUpdaters {
+			tdNextRefresh := m.runUpdateOnce(ctx, log.WithField("trust_domain", td.Name()), td, func(ctx context.Context) (*spiffebundle.Bundle, *spiffebundle.Bundle, error) {
+				return updater.UpdateBundleFromHub(ctx, hubBundles, hubErr)
+			})
+			nextRefreshes[td] = now.Add(tdNextRefresh)
+		}
+	}
+
+	if len(nextRefreshes) == 0 {
+		return bundleutil.MinimumRefreshHint
+	}
+
+	// The hub is polled again when the first trust domain is due
+	var nextRefresh time.Duration
+	first := true
+	for _, tdNextRefresh := range nextRefreshes {
+		if d := tdNextRefresh.Sub(now); first || d < nextRefresh {
+			nextRefresh = d
+			first = false
+		}
+	}
+	return nextRefresh
+}
+
+func (m *Manager) runUpdateOnce(ctx context.Context, log *logrus.Entry, trustDomain spiffeid.TrustDomain, updateBundle func(context.Context) (*spiffebundle.Bundle, *spiffebundle.Bundle, error)) time.Duration {
 	log.Debug("Polling for bundle update")
 
 	counter := telemetry_server.StartBundleManagerFetchFederatedBundleCall(m.metrics)
@@ -297,7 +520,7 @@ func (m *Manager) runUpdateOnce(ctx context.Context, log *logrus.Entry, trustDom
 	defer counter.Done(&err)
 
 	var localBundle, endpointBundle *spiffebundle.Bundle
-	localBundle, endpointBundle, err = updater.UpdateBundle(ctx)
+	localBundle, endpointBundle, err = updateBundle(ctx)
 	if err != nil {
 		log.WithError(err).Error("Error updating bundle")
 	}
@@ -5,6 +5,7 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"net/url"
 	"sync"
 	"testing"
 	"time"
@@ -14,6 +15,8 @@ import (
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	"github.com/spiffe/spire/pkg/common/bundleutil"
 	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/proto/spire/common"
 	"github.com/spiffe/spire/test/clock"
 	"github.com/spiffe/spire/test/fakes/fakedatastore"
 	"github.com/stretchr/testify/assert"
@@ -235,6 +238,149 @@ func TestManagerConfigManualRefresh(t *testing.T) {
 	}, test.GetTrustDomainConfigs())
 }
 
+func TestManagerRelationshipSafeguards(t *testing.T) {
+	td := spiffeid.RequireTrustDomainFromString("domain1.test")
+	endpointURL, err := url.Parse("https://domain1.test/bundle")
+	require.NoError(t, err)
+
+	test := newManagerTest(t, NewTrustDomainConfigSet(TrustDomainConfigMap{
+		td: {
+			EndpointURL:     endpointURL.String(),
+			EndpointProfile: HTTPSWebProfile{},
+		},
+	}), nil, nil)
+	test.WaitForConfigRefresh()
+	require.Equal(t, BundleSafeguards{}, test.Safeguards(td))
+
+	_, err = test.ds.CreateFederationRelationship(context.Background(), &datastore.FederationRelationship{
+		TrustDomain:           td,
+		BundleEndpointURL:     endpointURL,
+		BundleEndpointProfile: datastore.BundleEndpointWeb,
+		Options: &common.FederationRelationshipOptions{
+			BundleSafeguards: &common.BundleSafeguards{
+				RequireOverlap: true,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// The safeguards stored on the relationship are applied to the updater
+	test.manager.TriggerConfigReload()
+	test.WaitForConfigRefresh()
+	require.Equal(t, BundleSafeguards{RequireOverlap: true}, test.Safeguards(td))
+
+	// Changes to the stored safeguards are applied as well
+	_, err = test.ds.SetFederationRelationshipOptions(context.Background(), td, &common.FederationRelationshipOptions{
+		BundleSafeguards: &common.BundleSafeguards{
+			MaxKeys:              5,
+			MinRemainingValidity: 60,
+		},
+	})
+	require.NoError(t, err)
+	test.manager.TriggerConfigReload()
+	test.WaitForConfigRefresh()
+	require.Equal(t, BundleSafeguards{MaxKeys: 5, MinRemainingValidity: time.Minute}, test.Safeguards(td))
+}
+
+func TestManagerHubBundleRefresh(t *testing.T) {
+	td1 := spiffeid.RequireTrustDomainFromString("domain1.test")
+	td2 := spiffeid.RequireTrustDomainFromString("domain2.test")
+	td3 := spiffeid.RequireTrustDomainFromString("domain3.test")
+
+	// The bundles have distinct refresh hints so we can assert each trust
+	// domain is refreshed on its own schedule.
+	bundle1 := spiffebundle.FromX509Authorities(td1, []*x509.Certificate{createCACertificate(t, "domain1")})
+	bundle1.SetRefreshHint(time.Hour*2 + time.Minute*30)
+	bundle2 := spiffebundle.FromX509Authorities(td2, []*x509.Certificate{createCACertificate(t, "domain2")})
+	bundle2.SetRefreshHint(time.Hour)
+	endpointBundles := map[spiffeid.TrustDomain]*spiffebundle.Bundle{
+		td1: bundle1,
+		td2: bundle2,
+	}
+	nextUpdate1 := calculateNextUpdate(bundle1)
+	nextUpdate2 := calculateNextUpdate(bundle2)
+
+	hubConfig := TrustDomainConfig{
+		EndpointURL:     "https://hub.test/federated",
+		EndpointProfile: HTTPSWebProfile{},
+		Hub:             true,
+	}
+	configSet := NewTrustDomainConfigSet(TrustDomainConfigMap{
+		td1: hubConfig,
+		td2: hubConfig,
+	})
+
+	test := newManagerTest(t, configSet, nil, func(td spiffeid.TrustDomain) *spiffebundle.Bundle {
+		return endpointBundles[td]
+	})
+
+	// Both trust domains are refreshed with a single poll of the hub. The
+	// hub is polled again when the first of them is due.
+	test.WaitForConfigRefresh()
+	test.WaitForBundleRefresh(nextUpdate2)
+	assert.Equal(t, 1, test.HubFetchCount())
+	assert.Equal(t, 1, test.UpdateCount(td1))
+	assert.Equal(t, 1, test.UpdateCount(td2))
+
+	// Only domain2.test is due
+	test.AdvanceTime(nextUpdate2)
+	test.WaitForBundleRefresh(nextUpdate2)
+	assert.Equal(t, 2, test.HubFetchCount())
+	assert.Equal(t, 1, test.UpdateCount(td1))
+	assert.Equal(t, 2, test.UpdateCount(td2))
+
+	test.AdvanceTime(nextUpdate2)
+	test.WaitForBundleRefresh(nextUpdate1 - 2*nextUpdate2)
+	assert.Equal(t, 3, test.HubFetchCount())
+	assert.Equal(t, 1, test.UpdateCount(td1))
+	assert.Equal(t, 3, test.UpdateCount(td2))
+
+	// Only domain1.test is due
+	test.AdvanceTime(nextUpdate1 - 2*nextUpdate2)
+	test.WaitForBundleRefresh(3*nextUpdate2 - nextUpdate1)
+	assert.Equal(t, 4, test.HubFetchCount())
+	assert.Equal(t, 2, test.UpdateCount(td1))
+	assert.Equal(t, 3, test.UpdateCount(td2))
+
+	// Refreshing the bundle of a single trust domain on demand is still
+	// possible
+	has, err := test.RefreshBundleFor(td1)
+	assert.True(t, has)
+	assert.EqualError(t, err, "OHNO")
+	assert.Equal(t, 3, test.UpdateCount(td1))
+
+	// Once no longer refreshed from the hub, the trust domain gets an
+	// updater of its own.
+	webConfig := TrustDomainConfig{
+		EndpointURL:     "https://domain2.test/bundle",
+		EndpointProfile: HTTPSWebProfile{},
+	}
+	configSet.Set(td2, webConfig)
+	test.manager.TriggerConfigReload()
+	test.WaitForConfigRefresh()
+	test.WaitForBundleRefresh(nextUpdate2)
+	require.Equal(t, map[spiffeid.TrustDomain]TrustDomainConfig{
+		td1: hubConfig,
+		td2: webConfig,
+	}, test.GetTrustDomainConfigs())
+	assert.Equal(t, 1, test.UpdateCount(td2))
+
+	// A trust domain newly refreshed from the hub is refreshed right away,
+	// without refreshing the trust domains that are not due. Without a
+	// bundle, it is refreshed again after the minimum refresh hint.
+	configSet.Set(td3, hubConfig)
+	test.manager.TriggerConfigReload()
+	test.WaitForConfigRefresh()
+	test.WaitForBundleRefresh(bundleutil.MinimumRefreshHint)
+	assert.Equal(t, 3, test.UpdateCount(td1))
+	assert.Equal(t, 1, test.UpdateCount(td3))
+
+	test.AdvanceTime(bundleutil.MinimumRefreshHint)
+	test.WaitForBundleRefresh(bundleutil.MinimumRefreshHint)
+	assert.Equal(t, 3, test.UpdateCount(td1))
+	assert.Equal(t, 2, test.UpdateCount(td3))
+}
+
 type managerTest struct {
 	t                 *testing.T
 	clock             *clock.Mock
@@ -244,6 +390,7 @@ type managerTest struct {
 	bundleUpdaters    map[spiffeid.TrustDomain]*fakeBundleUpdater
 	configRefreshedCh chan time.Duration
 	bundleRefreshedCh chan time.Duration
+	ds                *fakedatastore.DataStore
 	manager           *Manager
 }
 
@@ -265,12 +412,13 @@ func newManagerTest(t *testing.T, source TrustDomainConfigSource, localBundles,
 		bundleUpdaters:    make(map[spiffeid.TrustDomain]*fakeBundleUpdater),
 		configRefreshedCh: make(chan time.Duration),
 		bundleRefreshedCh: make(chan time.Duration),
+		ds:                fakedatastore.New(t),
 	}
 
 	test.manager = NewManager(ManagerConfig{
 		Log:               log,
 		Metrics:           telemetry.Blackhole{},
-		DataStore:         fakedatastore.New(t),
+		DataStore:         test.ds,
 		Clock:             test.clock,
 		Source:            source,
 		newBundleUpdater:  test.newBundleUpdater,
@@ -325,6 +473,14 @@ func (test *managerTest) GetTrustDomainConfigs() map[spiffeid.TrustDomain]TrustD
 	return configs
 }
 
+func (test *managerTest) Safeguards(td spiffeid.TrustDomain) BundleSafeguards {
+	bundleUpdater, ok := test.bundleUpdaterFor(td)
+	require.True(test.t, ok, "no updater for %q", td)
+	bundleUpdater.mtx.Lock()
+	defer bundleUpdater.mtx.Unlock()
+	return bundleUpdater.config.Safeguards
+}
+
 func (test *managerTest) WaitForConfigRefresh() {
 	select {
 	case d := <-test.configRefreshedCh:
@@ -343,12 +499,25 @@ func (test *managerTest) WaitForBundleRefresh(expectNextRefresh time.Duration) {
 	}
 }
 
+func (test *managerTest) WaitForBundleRefreshes(expectNextRefreshes ...time.Duration) {
+	var nextRefreshes []time.Duration
+	for range expectNextRefreshes {
+		select {
+		case d := <-test.bundleRefreshedCh:
+			nextRefreshes = append(nextRefreshes, d)
+		case <-time.After(time.Second * 10):
+			require.Fail(test.t, "timed out waiting for bundle refresh")
+		}
+	}
+	require.ElementsMatch(test.t, expectNextRefreshes, nextRefreshes, "next bundle refreshes not at the expected intervals")
+}
+
 func (test *managerTest) RefreshBundleFor(td spiffeid.TrustDomain) (bool, error) {
 	return test.manager.RefreshBundleFor(context.Background(), td)
 }
 
 func (test *managerTest) newBundleUpdater(config BundleUpdaterConfig) BundleUpdater {
-	bundleUpdater := newFakeBundleUpdater(config)
+	bundleUpdater := newFakeBundleUpdater(config, test.hubBundles)
 	bundleUpdater.SetBundles(
 		test.localBundles(config.TrustDomain),
 		test.endpointBundles(config.TrustDomain),
@@ -360,6 +529,32 @@ func (test *managerTest) newBundleUpdater(config BundleUpdaterConfig) BundleUpda
 	return bundleUpdater
 }
 
+// hubBundles returns the endpoint bundles of every managed trust domain, as
+// served by a federation hub shared by all of them.
+func (test *managerTest) hubBundles() map[spiffeid.TrustDomain]*spiffebundle.Bundle {
+	test.bundleUpdatersMtx.Lock()
+	defer test.bundleUpdatersMtx.Unlock()
+
+	bundles := make(map[spiffeid.TrustDomain]*spiffebundle.Bundle)
+	for td := range test.bundleUpdaters {
+		if bundle := test.endpointBundles(td); bundle != nil {
+			bundles[td] = bundle
+		}
+	}
+	return bundles
+}
+
+func (test *managerTest) HubFetchCount() int {
+	test.bundleUpdatersMtx.Lock()
+	defer test.bundleUpdatersMtx.Unlock()
+
+	count := 0
+	for _, bundleUpdater := range test.bundleUpdaters {
+		count += bundleUpdater.HubFetchCount()
+	}
+	return count
+}
+
 func (test *managerTest) bundleUpdaterFor(td spiffeid.TrustDomain) (*fakeBundleUpdater, bool) {
 	test.bundleUpdatersMtx.Lock()
 	defer test.bundleUpdatersMtx.Unlock()
@@ -372,12 +567,15 @@ type fakeBundleUpdater struct {
 	localBundle    *spiffebundle.Bundle
 	endpointBundle *spiffebundle.Bundle
 	updateCount    int
+	hubFetchCount  int
 	config         BundleUpdaterConfig
+	hubBundles     func() map[spiffeid.TrustDomain]*spiffebundle.Bundle
 }
 
-func newFakeBundleUpdater(config BundleUpdaterConfig) *fakeBundleUpdater {
+func newFakeBundleUpdater(config BundleUpdaterConfig, hubBundles func() map[spiffeid.TrustDomain]*spiffebundle.Bundle) *fakeBundleUpdater {
 	return &fakeBundleUpdater{
-		config: config,
+		config:     config,
+		hubBundles: hubBundles,
 	}
 }
 
@@ -401,12 +599,42 @@ func (u *fakeBundleUpdater) UpdateBundle(context.Context) (*spiffebundle.Bundle,
 	return u.localBundle, u.endpointBundle, errors.New("OHNO")
 }
 
+func (u *fakeBundleUpdater) HubFetchCount() int {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	return u.hubFetchCount
+}
+
+func (u *fakeBundleUpdater) FetchHubBundles(context.Context) (map[spiffeid.TrustDomain]*spiffebundle.Bundle, error) {
+	u.mtx.Lock()
+	u.hubFetchCount++
+	u.mtx.Unlock()
+	return u.hubBundles(), nil
+}
+
+func (u *fakeBundleUpdater) UpdateBundleFromHub(_ context.Context, hubBundles map[spiffeid.TrustDomain]*spiffebundle.Bundle, hubErr error) (*spiffebundle.Bundle, *spiffebundle.Bundle, error) {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	u.updateCount++
+	return u.localBundle, hubBundles[u.config.TrustDomain], hubErr
+}
+
 func (u *fakeBundleUpdater) GetTrustDomainConfig() TrustDomainConfig {
 	u.mtx.Lock()
 	defer u.mtx.Unlock()
 	return u.config.TrustDomainConfig
 }
 
+func (u *fakeBundleUpdater) SetSafeguards(safeguards BundleSafeguards) bool {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	if !u.config.Safeguards.Equal(safeguards) {
+		u.config.Safeguards = safeguards
+		return true
+	}
+	return false
+}
+
 func (u *fakeBundleUpdater) SetTrustDomainConfig(trustDomainConfig TrustDomainConfig) bool {
 	u.mtx.Lock()
 	defer u.mtx.Unlock()
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/spiffe/spire/pkg/common/bundlequarantine"
+	"github.com/spiffe/spire/proto/spire/common"
+)
+
+// BundleSafeguards are checks that a bundle fetched from the bundle endpoint
+// of a federated trust domain must pass before it replaces the stored bundle.
+// Bundles that fail any of the checks are quarantined until approved by an
+// operator.
+type BundleSafeguards struct {
+	// RequireOverlap requires the fetched bundle to share at least one key
+	// with the stored bundle, so every key cannot be swapped at once.
+	RequireOverlap bool
+
+	// MaxKeys is the maximum number of X.509 and JWT authorities in the
+	// fetched bundle. Zero means no limit.
+	MaxKeys int
+
+	// MinRemainingValidity is how long at least one X.509 authority of the
+	// fetched bundle must remain valid. Zero means no requirement.
+	MinRemainingValidity time.Duration
+
+	// PinnedKeys are the SHA-256 fingerprints of the SubjectPublicKeyInfo of
+	// keys that must remain present in the fetched bundle.
+	PinnedKeys []string
+}
+
+// IsZero returns true if no safeguard is enabled.
+func (s BundleSafeguards) IsZero() bool {
+	return !s.RequireOverlap && s.MaxKeys == 0 && s.MinRemainingValidity == 0 && len(s.PinnedKeys) == 0
+}
+
+// Equal returns true if both safeguards enable the same checks.
+func (s BundleSafeguards) Equal(other BundleSafeguards) bool {
+	return s.RequireOverlap == other.RequireOverlap &&
+		s.MaxKeys == other.MaxKeys &&
+		s.MinRemainingValidity == other.MinRemainingValidity &&
+		slices.Equal(s.PinnedKeys, other.PinnedKeys)
+}
+
+// BundleSafeguardsFromProto converts the safeguards stored on a federation
+// relationship. A nil value enables no safeguard.
+func BundleSafeguardsFromProto(safeguards *common.BundleSafeguards) BundleSafeguards {
+	if safeguards == nil {
+		return BundleSafeguards{}
+	}
+	return BundleSafeguards{
+		RequireOverlap:       safeguards.RequireOverlap,
+		MaxKeys:              int(safeguards.MaxKeys),
+		MinRemainingValidity: time.Duration(safeguards.MinRemainingValidity) * time.Second,
+		PinnedKeys:           slices.Clone(safeguards.PinnedKeys),
+	}
+}
+
+// Check returns the safeguards violated by the next bundle. The current
+// bundle is nil if the trust domain has no bundle yet, in which case there is
+// nothing to overlap with.
+func (s BundleSafeguards) Check(current, next *common.Bundle, now time.Time) ([]string, error) {
+	nextKeys, err := bundlequarantine.Keys(next)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []string
+	if s.RequireOverlap && current != nil {
+		currentKeys, err := bundlequarantine.Keys(current)
+		if err != nil {
+			return nil, err
+		}
+		overlaps := slices.ContainsFunc(nextKeys, func(next bundlequarantine.Key) bool {
+			return slices.ContainsFunc(currentKeys, func(current bundlequarantine.Key) bool {
+				return current.Type == next.Type && current.Fingerprint == next.Fingerprint
+			})
+		})
+		if !overlaps {
+			violations = append(violations, "no key overlaps with the current bundle")
+		}
+	}
+
+	if s.MaxKeys > 0 && len(nextKeys) > s.MaxKeys {
+		violations = append(violations, fmt.Sprintf("bundle has %d keys, more than the maximum of %d", len(nextKeys), s.MaxKeys))
+	}
+
+	if s.MinRemainingValidity > 0 {
+		validUntil := now.Add(s.MinRemainingValidity)
+		valid := slices.ContainsFunc(nextKeys, func(key bundlequarantine.Key) bool {
+			return key.Type == bundlequarantine.KeyTypeX509 && !key.NotAfter.Before(validUntil)
+		})
+		if !valid {
+			violations = append(violations, fmt.Sprintf("no X.509 authority remains valid for at least %s", s.MinRemainingValidity))
+		}
+	}
+
+	for _, pinnedKey := range s.PinnedKeys {
+		present := slices.ContainsFunc(nextKeys, func(key bundlequarantine.Key) bool {
+			return key.Fingerprint == pinnedKey
+		})
+		if !present {
+			violations = append(violations, fmt.Sprintf("pinned key %s is missing", pinnedKey))
+		}
+	}
+
+	return violations, nil
+}
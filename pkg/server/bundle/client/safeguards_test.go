@@ -0,0 +1,135 @@
+package client
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/spiffe/spire/pkg/common/bundlequarantine"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/spiffe/spire/test/testkey"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleSafeguardsCheck(t *testing.T) {
+	now := time.Now()
+	ca1 := createCACertificateWithKey(t, "ca1", testkey.NewEC256(t))
+	ca2 := createCACertificateWithKey(t, "ca2", testkey.NewEC256(t))
+	jwtKey := testkey.NewEC256(t)
+	jwtKeyPKIX, err := x509.MarshalPKIXPublicKey(jwtKey.Public())
+	require.NoError(t, err)
+
+	newBundle := func(cas ...*x509.Certificate) *common.Bundle {
+		bundle := &common.Bundle{TrustDomainId: trustDomain.IDString()}
+		for _, ca := range cas {
+			bundle.RootCas = append(bundle.RootCas, &common.Certificate{DerBytes: ca.Raw})
+		}
+		return bundle
+	}
+	withJWTKey := func(bundle *common.Bundle) *common.Bundle {
+		bundle.JwtSigningKeys = append(bundle.JwtSigningKeys, &common.PublicKey{Kid: "kid", PkixBytes: jwtKeyPKIX})
+		return bundle
+	}
+
+	ca1Fingerprint := bundlequarantine.Fingerprint(ca1.RawSubjectPublicKeyInfo)
+	ca2Fingerprint := bundlequarantine.Fingerprint(ca2.RawSubjectPublicKeyInfo)
+
+	for _, tt := range []struct {
+		name             string
+		safeguards       BundleSafeguards
+		current          *common.Bundle
+		next             *common.Bundle
+		expectViolations []string
+	}{
+		{
+			name:    "no safeguards",
+			current: newBundle(ca1),
+			next:    newBundle(ca2),
+		},
+		{
+			name:       "overlap",
+			safeguards: BundleSafeguards{RequireOverlap: true},
+			current:    newBundle(ca1),
+			next:       newBundle(ca1, ca2),
+		},
+		{
+			name:       "overlap without current bundle",
+			safeguards: BundleSafeguards{RequireOverlap: true},
+			next:       newBundle(ca2),
+		},
+		{
+			name:             "no overlap",
+			safeguards:       BundleSafeguards{RequireOverlap: true},
+			current:          withJWTKey(newBundle(ca1)),
+			next:             newBundle(ca2),
+			expectViolations: []string{"no key overlaps with the current bundle"},
+		},
+		{
+			name:       "overlap on JWT key",
+			safeguards: BundleSafeguards{RequireOverlap: true},
+			current:    withJWTKey(newBundle(ca1)),
+			next:       withJWTKey(newBundle(ca2)),
+		},
+		{
+			name:       "within max keys",
+			safeguards: BundleSafeguards{MaxKeys: 2},
+			next:       withJWTKey(newBundle(ca1)),
+		},
+		{
+			name:             "too many keys",
+			safeguards:       BundleSafeguards{MaxKeys: 2},
+			next:             withJWTKey(newBundle(ca1, ca2)),
+			expectViolations: []string{"bundle has 3 keys, more than the maximum of 2"},
+		},
+		{
+			name:       "remaining validity",
+			safeguards: BundleSafeguards{MinRemainingValidity: 30 * time.Minute},
+			next:       newBundle(ca1),
+		},
+		{
+			name:             "not enough remaining validity",
+			safeguards:       BundleSafeguards{MinRemainingValidity: 2 * time.Hour},
+			next:             newBundle(ca1),
+			expectViolations: []string{"no X.509 authority remains valid for at least 2h0m0s"},
+		},
+		{
+			name:       "pinned keys present",
+			safeguards: BundleSafeguards{PinnedKeys: []string{ca1Fingerprint, ca2Fingerprint}},
+			next:       newBundle(ca1, ca2),
+		},
+		{
+			name:             "pinned key missing",
+			safeguards:       BundleSafeguards{PinnedKeys: []string{ca1Fingerprint, ca2Fingerprint}},
+			next:             newBundle(ca2),
+			expectViolations: []string{"pinned key " + ca1Fingerprint + " is missing"},
+		},
+		{
+			name: "multiple violations",
+			safeguards: BundleSafeguards{
+				RequireOverlap: true,
+				MaxKeys:        1,
+				PinnedKeys:     []string{ca1Fingerprint},
+			},
+			current: newBundle(ca1),
+			next:    withJWTKey(newBundle(ca2)),
+			expectViolations: []string{
+				"no key overlaps with the current bundle",
+				"bundle has 2 keys, more than the maximum of 1",
+				"pinned key " + ca1Fingerprint + " is missing",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := tt.safeguards.Check(tt.current, tt.next, now)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectViolations, violations)
+		})
+	}
+}
+
+func TestBundleSafeguardsCheckMalformedBundle(t *testing.T) {
+	_, err := BundleSafeguards{MaxKeys: 1}.Check(nil, &common.Bundle{
+		RootCas: []*common.Certificate{{DerBytes: []byte("malformed")}},
+	}, time.Now())
+	require.ErrorContains(t, err, "unable to parse X.509 authority")
+}
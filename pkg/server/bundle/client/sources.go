@@ -87,6 +87,7 @@ func DataStoreTrustDomainConfigSource(log logrus.FieldLogger, ds datastore.DataS
 		for _, fr := range resp.FederationRelationships {
 			config := TrustDomainConfig{
 				EndpointURL: fr.BundleEndpointURL.String(),
+				Hub:         fr.Options.GetBundleEndpointHub(),
 			}
 			switch fr.BundleEndpointProfile {
 			case datastore.BundleEndpointSPIFFE:
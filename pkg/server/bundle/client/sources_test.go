@@ -10,6 +10,7 @@ import (
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	"github.com/spiffe/spire/pkg/server/bundle/client"
 	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/proto/spire/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -122,6 +123,42 @@ func TestDataStoreTrustDomainConfigSource(t *testing.T) {
 		}, configs)
 		assert.NoError(t, err)
 	})
+
+	t.Run("federation hub", func(t *testing.T) {
+		log, _ := test.NewNullLogger()
+		ds := &fakeDataStore{frs: []*datastore.FederationRelationship{
+			{
+				TrustDomain:           domain1,
+				BundleEndpointURL:     parseURL(t, "https://hub.test/federated"),
+				BundleEndpointProfile: datastore.BundleEndpointWeb,
+				Options: &common.FederationRelationshipOptions{
+					BundleEndpointHub: true,
+				},
+			},
+			{
+				TrustDomain:           domain2,
+				BundleEndpointURL:     parseURL(t, "https://domain2.test/bundle"),
+				BundleEndpointProfile: datastore.BundleEndpointWeb,
+				Options: &common.FederationRelationshipOptions{
+					BundleSafeguards: &common.BundleSafeguards{MaxKeys: 5},
+				},
+			},
+		}}
+		source := client.DataStoreTrustDomainConfigSource(log, ds)
+		configs, err := source.GetTrustDomainConfigs(context.Background())
+		assert.Equal(t, map[spiffeid.TrustDomain]client.TrustDomainConfig{
+			domain1: {
+				EndpointURL:     "https://hub.test/federated",
+				EndpointProfile: client.HTTPSWebProfile{},
+				Hub:             true,
+			},
+			domain2: {
+				EndpointURL:     "https://domain2.test/bundle",
+				EndpointProfile: client.HTTPSWebProfile{},
+			},
+		}, configs)
+		assert.NoError(t, err)
+	})
 }
 
 type fakeDataStore struct {
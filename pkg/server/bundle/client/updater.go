@@ -4,12 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 
+	"github.com/andres-erbsen/clock"
 	"github.com/spiffe/go-spiffe/v2/bundle/spiffebundle"
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	"github.com/spiffe/spire/pkg/common/bundleutil"
 	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/proto/spire/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 type BundleUpdaterConfig struct {
@@ -18,6 +24,12 @@ type BundleUpdaterConfig struct {
 
 	TrustDomainConfig TrustDomainConfig
 
+	// Safeguards are the checks a fetched bundle must pass before it is
+	// stored. Bundles failing them are quarantined instead.
+	Safeguards BundleSafeguards
+
+	Clock clock.Clock
+
 	// newClientHook is a test hook for injecting client behavior
 	newClientHook func(ClientConfig) (Client, error)
 }
@@ -30,32 +42,55 @@ type BundleUpdater interface {
 	// bundle will always be returned if it was fetched, independent of any
 	// other failures performing the update. The endpoint bundle is ONLY
 	// returned if it can be successfully downloaded, is different from the
-	// local bundle, and is successfully stored.
+	// local bundle, passes the safeguards, and is successfully stored.
+	// Endpoint bundles that fail the safeguards are quarantined.
 	UpdateBundle(ctx context.Context) (*spiffebundle.Bundle, *spiffebundle.Bundle, error)
 
+	// FetchHubBundles fetches the bundles served by the federation hub
+	// endpoint of the updater.
+	FetchHubBundles(ctx context.Context) (map[spiffeid.TrustDomain]*spiffebundle.Bundle, error)
+
+	// UpdateBundleFromHub behaves like UpdateBundle, but takes the endpoint
+	// bundle from bundles already fetched from a federation hub. The hubErr
+	// is the error fetching them, if any.
+	UpdateBundleFromHub(ctx context.Context, hubBundles map[spiffeid.TrustDomain]*spiffebundle.Bundle, hubErr error) (*spiffebundle.Bundle, *spiffebundle.Bundle, error)
+
 	// GetTrustDomainConfig returns the configuration for the updater
 	GetTrustDomainConfig() TrustDomainConfig
 
 	// SetTrustDomainConfig sets the configuration for the updater
 	SetTrustDomainConfig(TrustDomainConfig) bool
+
+	// SetSafeguards sets the safeguards checked by the updater. It returns
+	// true if the safeguards changed.
+	SetSafeguards(BundleSafeguards) bool
 }
 
 type bundleUpdater struct {
 	td            spiffeid.TrustDomain
 	ds            datastore.DataStore
+	clock         clock.Clock
 	newClientHook func(ClientConfig) (Client, error)
 
 	trustDomainConfigMtx sync.Mutex
 	trustDomainConfig    TrustDomainConfig
+
+	safeguardsMtx sync.Mutex
+	safeguards    BundleSafeguards
 }
 
 func NewBundleUpdater(config BundleUpdaterConfig) BundleUpdater {
 	if config.newClientHook == nil {
 		config.newClientHook = NewClient
 	}
+	if config.Clock == nil {
+		config.Clock = clock.New()
+	}
 	return &bundleUpdater{
 		td:                config.TrustDomain,
 		ds:                config.DataStore,
+		safeguards:        config.Safeguards,
+		clock:             config.Clock,
 		newClientHook:     config.newClientHook,
 		trustDomainConfig: config.TrustDomainConfig,
 	}
@@ -69,17 +104,54 @@ func (u *bundleUpdater) UpdateBundle(ctx context.Context) (*spiffebundle.Bundle,
 		return nil, nil, err
 	}
 
+	return u.updateBundle(ctx, client.FetchBundle)
+}
+
+func (u *bundleUpdater) FetchHubBundles(ctx context.Context) (map[spiffeid.TrustDomain]*spiffebundle.Bundle, error) {
+	trustDomainConfig := u.GetTrustDomainConfig()
+
+	client, err := u.newClient(ctx, trustDomainConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	hubClient, ok := client.(HubClient)
+	if !ok {
+		return nil, fmt.Errorf("bundle endpoint profile %q cannot be used with a federation hub", trustDomainConfig.EndpointProfile.Name())
+	}
+	return hubClient.FetchHubBundles(ctx)
+}
+
+func (u *bundleUpdater) UpdateBundleFromHub(ctx context.Context, hubBundles map[spiffeid.TrustDomain]*spiffebundle.Bundle, hubErr error) (*spiffebundle.Bundle, *spiffebundle.Bundle, error) {
+	return u.updateBundle(ctx, func(context.Context) (*spiffebundle.Bundle, error) {
+		if hubErr != nil {
+			return nil, hubErr
+		}
+		bundle, ok := hubBundles[u.td]
+		if !ok {
+			return nil, notServedByHubError(u.td)
+		}
+		return bundle, nil
+	})
+}
+
+func (u *bundleUpdater) updateBundle(ctx context.Context, fetchBundle func(context.Context) (*spiffebundle.Bundle, error)) (*spiffebundle.Bundle, *spiffebundle.Bundle, error) {
 	localFederatedBundleOrNil, err := fetchBundleIfExists(ctx, u.ds, u.td)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to fetch local federated bundle: %w", err)
 	}
 
-	fetchedFederatedBundle, err := client.FetchBundle(ctx)
+	fetchedFederatedBundle, err := fetchBundle(ctx)
 	if err != nil {
 		return localFederatedBundleOrNil, nil, fmt.Errorf("failed to fetch federated bundle from endpoint: %w", err)
 	}
 
 	if localFederatedBundleOrNil != nil && fetchedFederatedBundle.Equal(localFederatedBundleOrNil) {
+		// The endpoint went back to serving the stored bundle, so a bundle
+		// quarantined in the meantime no longer needs approval.
+		if err := u.clearQuarantine(ctx); err != nil {
+			return localFederatedBundleOrNil, nil, err
+		}
 		return localFederatedBundleOrNil, nil, nil
 	}
 
@@ -87,7 +159,16 @@ func (u *bundleUpdater) UpdateBundle(ctx context.Context) (*spiffebundle.Bundle,
 	if err != nil {
 		return nil, nil, err
 	}
-	_, err = u.ds.SetBundle(ctx, bundle)
+
+	if safeguards := u.getSafeguards(); !safeguards.IsZero() {
+		if err := u.checkSafeguards(ctx, safeguards, localFederatedBundleOrNil, bundle); err != nil {
+			return localFederatedBundleOrNil, nil, err
+		}
+	}
+
+	// Storing the fetched bundle supersedes any bundle previously
+	// quarantined for the trust domain.
+	_, err = u.ds.ReleaseQuarantinedBundle(ctx, bundle)
 	if err != nil {
 		return localFederatedBundleOrNil, nil, fmt.Errorf("failed to store fetched federated bundle: %w", err)
 	}
@@ -95,6 +176,63 @@ func (u *bundleUpdater) UpdateBundle(ctx context.Context) (*spiffebundle.Bundle,
 	return localFederatedBundleOrNil, fetchedFederatedBundle, nil
 }
 
+// checkSafeguards checks the fetched bundle against the safeguards. If any is
+// violated, the fetched bundle is quarantined and an error is returned.
+func (u *bundleUpdater) checkSafeguards(ctx context.Context, safeguards BundleSafeguards, localBundleOrNil *spiffebundle.Bundle, bundle *common.Bundle) error {
+	var current *common.Bundle
+	if localBundleOrNil != nil {
+		var err error
+		current, err = bundleutil.SPIFFEBundleToProto(localBundleOrNil)
+		if err != nil {
+			return err
+		}
+	}
+
+	violations, err := safeguards.Check(current, bundle, u.clock.Now())
+	if err != nil {
+		return fmt.Errorf("failed to check bundle safeguards: %w", err)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	quarantined, err := u.ds.FetchQuarantinedBundle(ctx, u.td.IDString())
+	if err != nil {
+		return fmt.Errorf("failed to fetch quarantined bundle: %w", err)
+	}
+
+	// Keep the original quarantine time while the endpoint keeps serving
+	// the same bundle.
+	if quarantined == nil || !proto.Equal(quarantined.Bundle, bundle) {
+		quarantined = &common.QuarantinedBundle{
+			TrustDomainId: u.td.IDString(),
+			Bundle:        bundle,
+			QuarantinedAt: u.clock.Now().Unix(),
+		}
+	}
+	quarantined.Violations = violations
+	if _, err := u.ds.SetQuarantinedBundle(ctx, quarantined); err != nil {
+		return fmt.Errorf("failed to quarantine fetched federated bundle: %w", err)
+	}
+
+	return fmt.Errorf("fetched federated bundle quarantined: %s", strings.Join(violations, "; "))
+}
+
+// clearQuarantine deletes the bundle quarantined for the trust domain, if any.
+func (u *bundleUpdater) clearQuarantine(ctx context.Context) error {
+	quarantined, err := u.ds.FetchQuarantinedBundle(ctx, u.td.IDString())
+	if err != nil {
+		return fmt.Errorf("failed to fetch quarantined bundle: %w", err)
+	}
+	if quarantined == nil {
+		return nil
+	}
+	if err := u.ds.DeleteQuarantinedBundle(ctx, u.td.IDString()); err != nil && status.Code(err) != codes.NotFound {
+		return fmt.Errorf("failed to delete quarantined bundle: %w", err)
+	}
+	return nil
+}
+
 func (u *bundleUpdater) GetTrustDomainConfig() TrustDomainConfig {
 	u.trustDomainConfigMtx.Lock()
 	trustDomainConfig := u.trustDomainConfig
@@ -112,10 +250,47 @@ func (u *bundleUpdater) SetTrustDomainConfig(trustDomainConfig TrustDomainConfig
 	return false
 }
 
+func (u *bundleUpdater) SetSafeguards(safeguards BundleSafeguards) bool {
+	u.safeguardsMtx.Lock()
+	defer u.safeguardsMtx.Unlock()
+	if !u.safeguards.Equal(safeguards) {
+		u.safeguards = safeguards
+		return true
+	}
+	return false
+}
+
+func (u *bundleUpdater) getSafeguards() BundleSafeguards {
+	u.safeguardsMtx.Lock()
+	defer u.safeguardsMtx.Unlock()
+	return u.safeguards
+}
+
 func (u *bundleUpdater) newClient(ctx context.Context, trustDomainConfig TrustDomainConfig) (Client, error) {
 	clientConfig := ClientConfig{
 		TrustDomain: u.td,
 		EndpointURL: trustDomainConfig.EndpointURL,
+		Hub:         trustDomainConfig.Hub,
+	}
+
+	if fileProfile, ok := trustDomainConfig.EndpointProfile.(FileProfile); ok {
+		path, err := FilePathFromURL(trustDomainConfig.EndpointURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bundle file URL: %w", err)
+		}
+		storedBundle, err := fetchBundleIfExists(ctx, u.ds, u.td)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch local federated bundle: %w", err)
+		}
+		var storedSequenceNumber uint64
+		if storedBundle != nil {
+			storedSequenceNumber, _ = storedBundle.SequenceNumber()
+		}
+		clientConfig.SignedFile = &SignedFileConfig{
+			Path:                  path,
+			TrustedSignerKeysPath: fileProfile.TrustedSignerKeysPath,
+			StoredSequenceNumber:  storedSequenceNumber,
+		}
 	}
 
 	if spiffeAuth, ok := trustDomainConfig.EndpointProfile.(HTTPSSPIFFEProfile); ok {
@@ -2,18 +2,22 @@ package client
 
 import (
 	"context"
+	"crypto"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"errors"
 	"math/big"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/spiffe/go-spiffe/v2/bundle/spiffebundle"
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	"github.com/spiffe/spire/pkg/common/bundleutil"
+	"github.com/spiffe/spire/test/clock"
 	"github.com/spiffe/spire/test/fakes/fakedatastore"
 	"github.com/spiffe/spire/test/spiretest"
+	"github.com/spiffe/spire/test/testkey"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -147,6 +151,215 @@ func TestBundleUpdaterUpdateBundle(t *testing.T) {
 	}
 }
 
+func TestBundleUpdaterQuarantine(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock(t)
+	ds := fakedatastore.New(t)
+
+	// The safeguards compare keys, so the authorities need distinct keys
+	ca1 := createCACertificateWithKey(t, "bundle1", testkey.NewEC256(t))
+	ca2 := createCACertificateWithKey(t, "bundle2", testkey.NewEC256(t))
+	bundle1 := spiffebundle.FromX509Authorities(trustDomain, []*x509.Certificate{ca1})
+	bundle2 := spiffebundle.FromX509Authorities(trustDomain, []*x509.Certificate{ca2})
+	bundle12 := spiffebundle.FromX509Authorities(trustDomain, []*x509.Certificate{ca1, ca2})
+
+	bundle1Proto, err := bundleutil.SPIFFEBundleToProto(bundle1)
+	require.NoError(t, err)
+	bundle2Proto, err := bundleutil.SPIFFEBundleToProto(bundle2)
+	require.NoError(t, err)
+	_, err = ds.CreateBundle(ctx, bundle1Proto)
+	require.NoError(t, err)
+
+	client := fakeClient{bundle: bundle2}
+	updater := NewBundleUpdater(BundleUpdaterConfig{
+		DataStore:   ds,
+		TrustDomain: trustDomain,
+		TrustDomainConfig: TrustDomainConfig{
+			EndpointURL: "ENDPOINT_ADDRESS",
+			EndpointProfile: HTTPSSPIFFEProfile{
+				EndpointSPIFFEID: trustDomain.ID(),
+			},
+		},
+		Safeguards: BundleSafeguards{
+			RequireOverlap: true,
+			MaxKeys:        2,
+		},
+		Clock: clk,
+		newClientHook: func(ClientConfig) (Client, error) {
+			return client, nil
+		},
+	})
+
+	requireQuarantined := func(quarantinedAt time.Time) {
+		localBundle, endpointBundle, err := updater.UpdateBundle(ctx)
+		require.EqualError(t, err, "fetched federated bundle quarantined: no key overlaps with the current bundle")
+		require.Equal(t, bundle1.X509Authorities(), localBundle.X509Authorities())
+		require.Nil(t, endpointBundle)
+
+		stored, err := ds.FetchBundle(ctx, trustDomain.IDString())
+		require.NoError(t, err)
+		spiretest.RequireProtoEqual(t, bundle1Proto, stored)
+
+		quarantined, err := ds.FetchQuarantinedBundle(ctx, trustDomain.IDString())
+		require.NoError(t, err)
+		require.NotNil(t, quarantined)
+		spiretest.RequireProtoEqual(t, bundle2Proto, quarantined.Bundle)
+		require.Equal(t, []string{"no key overlaps with the current bundle"}, quarantined.Violations)
+		require.Equal(t, quarantinedAt.Unix(), quarantined.QuarantinedAt)
+	}
+
+	// The bundle swapping every key is quarantined
+	quarantinedAt := clk.Now()
+	requireQuarantined(quarantinedAt)
+
+	// The quarantine time is kept while the endpoint serves the same bundle
+	clk.Add(time.Minute)
+	requireQuarantined(quarantinedAt)
+
+	// Bundles passing the safeguards are stored as usual and supersede the
+	// quarantined bundle
+	client.bundle = bundle12
+	localBundle, endpointBundle, err := updater.UpdateBundle(ctx)
+	require.NoError(t, err)
+	require.Equal(t, bundle1.X509Authorities(), localBundle.X509Authorities())
+	require.Equal(t, bundle12.X509Authorities(), endpointBundle.X509Authorities())
+
+	quarantined, err := ds.FetchQuarantinedBundle(ctx, trustDomain.IDString())
+	require.NoError(t, err)
+	require.Nil(t, quarantined)
+
+	// The quarantine is lifted when the endpoint goes back to serving the
+	// stored bundle
+	ca3 := createCACertificateWithKey(t, "bundle3", testkey.NewEC256(t))
+	client.bundle = spiffebundle.FromX509Authorities(trustDomain, []*x509.Certificate{ca3})
+	_, _, err = updater.UpdateBundle(ctx)
+	require.EqualError(t, err, "fetched federated bundle quarantined: no key overlaps with the current bundle")
+	quarantined, err = ds.FetchQuarantinedBundle(ctx, trustDomain.IDString())
+	require.NoError(t, err)
+	require.NotNil(t, quarantined)
+
+	stored, err := ds.FetchBundle(ctx, trustDomain.IDString())
+	require.NoError(t, err)
+	client.bundle, err = bundleutil.SPIFFEBundleFromProto(stored)
+	require.NoError(t, err)
+	localBundle, endpointBundle, err = updater.UpdateBundle(ctx)
+	require.NoError(t, err)
+	require.Equal(t, bundle12.X509Authorities(), localBundle.X509Authorities())
+	require.Nil(t, endpointBundle)
+
+	quarantined, err = ds.FetchQuarantinedBundle(ctx, trustDomain.IDString())
+	require.NoError(t, err)
+	require.Nil(t, quarantined)
+}
+
+func TestBundleUpdaterFileProfile(t *testing.T) {
+	ds := fakedatastore.New(t)
+	bundle := spiffebundle.FromX509Authorities(trustDomain, []*x509.Certificate{createCACertificate(t, "bundle")})
+	bundle.SetSequenceNumber(7)
+
+	var clientConfig ClientConfig
+	updater := NewBundleUpdater(BundleUpdaterConfig{
+		DataStore:   ds,
+		TrustDomain: trustDomain,
+		TrustDomainConfig: TrustDomainConfig{
+			EndpointURL: "file:///var/lib/spire/bundles",
+			EndpointProfile: FileProfile{
+				TrustedSignerKeysPath: "/etc/spire/signers.pem",
+			},
+		},
+		newClientHook: func(config ClientConfig) (Client, error) {
+			clientConfig = config
+			return fakeClient{bundle: bundle}, nil
+		},
+	})
+
+	// No local bundle is needed to bootstrap the trust domain, since the
+	// bundle is authenticated by its signature
+	localBundle, endpointBundle, err := updater.UpdateBundle(context.Background())
+	require.NoError(t, err)
+	require.Nil(t, localBundle)
+	require.Equal(t, bundle.X509Authorities(), endpointBundle.X509Authorities())
+	require.Nil(t, clientConfig.SPIFFEAuth)
+	require.Equal(t, &SignedFileConfig{
+		Path:                  filepath.FromSlash("/var/lib/spire/bundles"),
+		TrustedSignerKeysPath: "/etc/spire/signers.pem",
+	}, clientConfig.SignedFile)
+
+	// The sequence number of the stored bundle is passed to the client, so
+	// older bundles are rejected
+	_, _, err = updater.UpdateBundle(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), clientConfig.SignedFile.StoredSequenceNumber)
+
+	updater.SetTrustDomainConfig(TrustDomainConfig{
+		EndpointURL:     "https://example.org/bundle",
+		EndpointProfile: FileProfile{TrustedSignerKeysPath: "/etc/spire/signers.pem"},
+	})
+	_, _, err = updater.UpdateBundle(context.Background())
+	require.EqualError(t, err, `invalid bundle file URL: expected file URL; got scheme "https"`)
+}
+
+func TestBundleUpdaterHub(t *testing.T) {
+	ds := fakedatastore.New(t)
+	otherTrustDomain := spiffeid.RequireTrustDomainFromString("other.test")
+	bundle := spiffebundle.FromX509Authorities(trustDomain, []*x509.Certificate{createCACertificate(t, "bundle")})
+	otherBundle := spiffebundle.FromX509Authorities(otherTrustDomain, []*x509.Certificate{createCACertificate(t, "other")})
+	hubBundles := map[spiffeid.TrustDomain]*spiffebundle.Bundle{
+		trustDomain:      bundle,
+		otherTrustDomain: otherBundle,
+	}
+
+	var clientConfig ClientConfig
+	var client Client = fakeHubClient{bundles: hubBundles}
+	updater := NewBundleUpdater(BundleUpdaterConfig{
+		DataStore:   ds,
+		TrustDomain: trustDomain,
+		TrustDomainConfig: TrustDomainConfig{
+			EndpointURL:     "https://hub.test/federated",
+			EndpointProfile: HTTPSWebProfile{},
+			Hub:             true,
+		},
+		newClientHook: func(config ClientConfig) (Client, error) {
+			clientConfig = config
+			return client, nil
+		},
+	})
+
+	fetched, err := updater.FetchHubBundles(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, hubBundles, fetched)
+	require.True(t, clientConfig.Hub)
+
+	// The bundle of the trust domain is taken from the hub bundles
+	localBundle, endpointBundle, err := updater.UpdateBundleFromHub(context.Background(), fetched, nil)
+	require.NoError(t, err)
+	require.Nil(t, localBundle)
+	require.Equal(t, bundle.X509Authorities(), endpointBundle.X509Authorities())
+	stored, err := ds.FetchBundle(context.Background(), trustDomain.IDString())
+	require.NoError(t, err)
+	require.Equal(t, bundle.X509Authorities()[0].Raw, stored.RootCas[0].DerBytes)
+
+	// The local bundle is still returned when the hub cannot be fetched or
+	// does not serve the trust domain
+	localBundle, endpointBundle, err = updater.UpdateBundleFromHub(context.Background(), nil, errors.New("oh no"))
+	require.EqualError(t, err, "failed to fetch federated bundle from endpoint: oh no")
+	require.Equal(t, bundle.X509Authorities(), localBundle.X509Authorities())
+	require.Nil(t, endpointBundle)
+
+	localBundle, endpointBundle, err = updater.UpdateBundleFromHub(context.Background(), map[spiffeid.TrustDomain]*spiffebundle.Bundle{
+		otherTrustDomain: otherBundle,
+	}, nil)
+	require.EqualError(t, err, `failed to fetch federated bundle from endpoint: bundle for "domain.test" is not served by the federation hub`)
+	require.Equal(t, bundle.X509Authorities(), localBundle.X509Authorities())
+	require.Nil(t, endpointBundle)
+
+	// Clients that do not support federation hubs fail to fetch the hub
+	// bundles
+	client = fakeClient{bundle: bundle}
+	_, err = updater.FetchHubBundles(context.Background())
+	require.EqualError(t, err, `bundle endpoint profile "https_web" cannot be used with a federation hub`)
+}
+
 func TestBundleUpdaterConfiguration(t *testing.T) {
 	configs := []TrustDomainConfig{
 		{
@@ -188,14 +401,29 @@ func (c fakeClient) FetchBundle(context.Context) (*spiffebundle.Bundle, error) {
 	return c.bundle, c.err
 }
 
+type fakeHubClient struct {
+	bundles map[spiffeid.TrustDomain]*spiffebundle.Bundle
+}
+
+func (c fakeHubClient) FetchBundle(context.Context) (*spiffebundle.Bundle, error) {
+	return nil, errors.New("unexpected fetch of a single bundle")
+}
+
+func (c fakeHubClient) FetchHubBundles(context.Context) (map[spiffeid.TrustDomain]*spiffebundle.Bundle, error) {
+	return c.bundles, nil
+}
+
 func createCACertificate(t *testing.T, cn string) *x509.Certificate {
+	return createCACertificateWithKey(t, cn, spiretest.DefaultKey)
+}
+
+func createCACertificateWithKey(t *testing.T, cn string, key crypto.Signer) *x509.Certificate {
 	now := time.Now()
-	cert, _ := spiretest.SelfSignCertificate(t, &x509.Certificate{
+	return spiretest.SelfSignCertificateWithKey(t, &x509.Certificate{
 		SerialNumber: big.NewInt(0),
 		NotBefore:    now,
 		NotAfter:     now.Add(time.Hour),
 		IsCA:         true,
 		Subject:      pkix.Name{CommonName: cn},
-	})
-	return cert
+	}, key)
 }
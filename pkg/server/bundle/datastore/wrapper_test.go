@@ -28,6 +28,7 @@ func TestWithBundlePublisher(t *testing.T) {
 		TrustDomainId:  td.IDString(),
 		RootCas:        []*common.Certificate{{DerBytes: rootCA.Raw}},
 		JwtSigningKeys: []*common.PublicKey{{Kid: keyID2, PkixBytes: []byte{}, NotAfter: 2000}},
+		WitSigningKeys: []*common.PublicKey{{Kid: keyID2, PkixBytes: []byte{}, NotAfter: 2000}},
 	}
 
 	for _, tt := range []struct {
@@ -77,6 +78,20 @@ func TestWithBundlePublisher(t *testing.T) {
 				require.True(t, wt.callbackCalled)
 			},
 		},
+		{
+			name: "RevokeWITKey",
+			assertCallingCallback: func(ctx context.Context, t *testing.T, ds datastore.DataStore, wt *wrapperTest) {
+				_, err := ds.TaintWITKey(ctx, "spiffe://example.org", keyID2)
+				require.NoError(t, err)
+
+				// TaintWITKey should not call the callback function
+				require.False(t, wt.callbackCalled)
+
+				_, err = ds.RevokeWITKey(ctx, "spiffe://example.org", keyID2)
+				require.NoError(t, err)
+				require.True(t, wt.callbackCalled)
+			},
+		},
 	} {
 		ctx := context.Background()
 		t.Run(tt.name, func(t *testing.T) {
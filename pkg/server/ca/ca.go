@@ -23,6 +23,8 @@ import (
 	"github.com/spiffe/spire/pkg/common/x509util"
 	"github.com/spiffe/spire/pkg/server/credtemplate"
 	"github.com/spiffe/spire/pkg/server/credvalidator"
+	"github.com/spiffe/spire/pkg/server/plugin/credentialcomposer"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -65,6 +67,9 @@ type AgentX509SVIDParams struct {
 
 	// SPIFFE ID of the agent
 	SPIFFEID spiffeid.ID
+
+	// SVIDContext is passed to the credential composers
+	SVIDContext credentialcomposer.SVIDContext
 }
 
 // WorkloadX509SVIDParams are parameters relevant to workload X509-SVID creation
@@ -85,6 +90,9 @@ type WorkloadX509SVIDParams struct {
 
 	// Subject of the SVID. Default subject is used if it is empty.
 	Subject pkix.Name
+
+	// SVIDContext is passed to the credential composers
+	SVIDContext credentialcomposer.SVIDContext
 }
 
 // WorkloadJWTSVIDParams are parameters relevant to workload JWT-SVID creation
@@ -102,6 +110,9 @@ type WorkloadJWTSVIDParams struct {
 	// IncludeJTI, when true, instructs the CA to include a unique "jti" (JWT ID)
 	// claim in the issued token.
 	IncludeJTI bool
+
+	// SVIDContext is passed to the credential composers
+	SVIDContext credentialcomposer.SVIDContext
 }
 
 // WorkloadWITSVIDParams are parameters relevant to workload WIT-SVID creation
@@ -250,7 +261,10 @@ func (ca *CA) TaintedAuthorities() <-chan []*x509.Certificate {
 	return ca.taintedAuthoritiesCh
 }
 
-func (ca *CA) SignDownstreamX509CA(ctx context.Context, params DownstreamX509CAParams) ([]*x509.Certificate, error) {
+func (ca *CA) SignDownstreamX509CA(ctx context.Context, params DownstreamX509CAParams) (_ []*x509.Certificate, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "server.ca.SignDownstreamX509CA")
+	defer telemetry.EndSpan(span, &err)
+
 	x509CA, caChain, err := ca.getX509CA()
 	if err != nil {
 		return nil, err
@@ -279,7 +293,10 @@ func (ca *CA) SignDownstreamX509CA(ctx context.Context, params DownstreamX509CAP
 	return makeCertChain(x509CA, downstreamCA), nil
 }
 
-func (ca *CA) SignServerX509SVID(ctx context.Context, params ServerX509SVIDParams) ([]*x509.Certificate, error) {
+func (ca *CA) SignServerX509SVID(ctx context.Context, params ServerX509SVIDParams) (_ []*x509.Certificate, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "server.ca.SignServerX509SVID")
+	defer telemetry.EndSpan(span, &err)
+
 	x509CA, caChain, err := ca.getX509CA()
 	if err != nil {
 		return nil, err
@@ -305,7 +322,10 @@ func (ca *CA) SignServerX509SVID(ctx context.Context, params ServerX509SVIDParam
 	return svidChain, nil
 }
 
-func (ca *CA) SignAgentX509SVID(ctx context.Context, params AgentX509SVIDParams) ([]*x509.Certificate, error) {
+func (ca *CA) SignAgentX509SVID(ctx context.Context, params AgentX509SVIDParams) (_ []*x509.Certificate, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "server.ca.SignAgentX509SVID", attribute.String(telemetry.SPIFFEID, params.SPIFFEID.String()))
+	defer telemetry.EndSpan(span, &err)
+
 	x509CA, caChain, err := ca.getX509CA()
 	if err != nil {
 		return nil, err
@@ -315,6 +335,7 @@ func (ca *CA) SignAgentX509SVID(ctx context.Context, params AgentX509SVIDParams)
 		ParentChain: caChain,
 		PublicKey:   params.PublicKey,
 		SPIFFEID:    params.SPIFFEID,
+		SVIDContext: params.SVIDContext,
 	})
 	if err != nil {
 		return nil, err
@@ -332,7 +353,10 @@ func (ca *CA) SignAgentX509SVID(ctx context.Context, params AgentX509SVIDParams)
 	return svidChain, nil
 }
 
-func (ca *CA) SignWorkloadX509SVID(ctx context.Context, params WorkloadX509SVIDParams) ([]*x509.Certificate, error) {
+func (ca *CA) SignWorkloadX509SVID(ctx context.Context, params WorkloadX509SVIDParams) (_ []*x509.Certificate, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "server.ca.SignWorkloadX509SVID", attribute.String(telemetry.SPIFFEID, params.SPIFFEID.String()))
+	defer telemetry.EndSpan(span, &err)
+
 	x509CA, caChain, err := ca.getX509CA()
 	if err != nil {
 		return nil, err
@@ -345,6 +369,7 @@ func (ca *CA) SignWorkloadX509SVID(ctx context.Context, params WorkloadX509SVIDP
 		DNSNames:    params.DNSNames,
 		TTL:         params.TTL,
 		Subject:     params.Subject,
+		SVIDContext: params.SVIDContext,
 	})
 	if err != nil {
 		return nil, err
@@ -362,7 +387,10 @@ func (ca *CA) SignWorkloadX509SVID(ctx context.Context, params WorkloadX509SVIDP
 	return svidChain, nil
 }
 
-func (ca *CA) SignWorkloadJWTSVID(ctx context.Context, params WorkloadJWTSVIDParams) (string, error) {
+func (ca *CA) SignWorkloadJWTSVID(ctx context.Context, params WorkloadJWTSVIDParams) (_ string, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "server.ca.SignWorkloadJWTSVID", attribute.String(telemetry.SPIFFEID, params.SPIFFEID.String()))
+	defer telemetry.EndSpan(span, &err)
+
 	jwtKey := ca.JWTKey()
 	if jwtKey == nil {
 		return "", errors.New("JWT key is not available for signing")
@@ -374,6 +402,7 @@ func (ca *CA) SignWorkloadJWTSVID(ctx context.Context, params WorkloadJWTSVIDPar
 		TTL:           params.TTL,
 		ExpirationCap: jwtKey.NotAfter,
 		IncludeJTI:    params.IncludeJTI,
+		SVIDContext:   params.SVIDContext,
 	})
 	if err != nil {
 		return "", err
@@ -392,7 +421,10 @@ func (ca *CA) SignWorkloadJWTSVID(ctx context.Context, params WorkloadJWTSVIDPar
 	return token, nil
 }
 
-func (ca *CA) SignWorkloadWITSVID(ctx context.Context, params WorkloadWITSVIDParams) (string, error) {
+func (ca *CA) SignWorkloadWITSVID(ctx context.Context, params WorkloadWITSVIDParams) (_ string, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "server.ca.SignWorkloadWITSVID", attribute.String(telemetry.SPIFFEID, params.SPIFFEID.String()))
+	defer telemetry.EndSpan(span, &err)
+
 	witKey := ca.WITKey()
 	if witKey == nil {
 		return "", errors.New("WIT key is not available for signing")
@@ -60,6 +60,10 @@ type JwtKeyPublisher interface {
 	PublishJWTKey(ctx context.Context, jwtKey *common.PublicKey) ([]*common.PublicKey, error)
 }
 
+type WITKeyPublisher interface {
+	PublishWITKey(ctx context.Context, witKey *common.PublicKey) ([]*common.PublicKey, error)
+}
+
 type AuthorityManager interface {
 	GetCurrentJWTKeySlot() Slot
 	GetNextJWTKeySlot() Slot
@@ -77,6 +81,7 @@ type AuthorityManager interface {
 	IsJWTSVIDsDisabled() bool
 	IsWITSVIDsDisabled() bool
 	PublishJWTKey(ctx context.Context, jwtKey *common.PublicKey) ([]*common.PublicKey, error)
+	PublishWITKey(ctx context.Context, witKey *common.PublicKey) ([]*common.PublicKey, error)
 	NotifyTaintedX509Authority(ctx context.Context, authorityID string) error
 	SubscribeToLocalBundle(ctx context.Context) error
 }
@@ -140,8 +145,13 @@ func NewManager(ctx context.Context, c Config) (*Manager, error) {
 	}
 
 	if upstreamAuthority, ok := c.Catalog.GetUpstreamAuthority(); ok {
+		// The UpstreamWITAuthority service is optional, only the
+		// UpstreamAuthority plugins that provide the WIT authorities of the
+		// upstream bundle serve it.
+		upstreamWITAuthority, _ := c.Catalog.GetUpstreamWITAuthority()
 		m.upstreamClient = ca.NewUpstreamClient(ca.UpstreamClientConfig{
-			UpstreamAuthority: upstreamAuthority,
+			UpstreamAuthority:    upstreamAuthority,
+			UpstreamWITAuthority: upstreamWITAuthority,
 			BundleUpdater: &bundleUpdater{
 				log:                         c.Log,
 				trustDomainID:               c.TrustDomain.IDString(),
@@ -149,6 +159,7 @@ func NewManager(ctx context.Context, c Config) (*Manager, error) {
 				updated:                     m.bundleUpdated,
 				upstreamAuthoritiesTainted:  m.notifyUpstreamAuthoritiesTainted,
 				processedTaintedAuthorities: map[string]struct{}{},
+				upstreamWITKeys:             map[string]struct{}{},
 			},
 		})
 		m.upstreamPluginName = upstreamAuthority.Name()
@@ -495,6 +506,19 @@ func (m *Manager) PublishJWTKey(ctx context.Context, jwtKey *common.PublicKey) (
 	return bundle.JwtSigningKeys, nil
 }
 
+// PublishWITKey appends the passed WIT key to the bundle and returns the
+// updated list of WIT keys contained in the bundle, including their tainted
+// status. UpstreamAuthority plugins have no support for publishing WIT keys,
+// so the key is always appended to the local bundle.
+func (m *Manager) PublishWITKey(ctx context.Context, witKey *common.PublicKey) ([]*common.PublicKey, error) {
+	bundle, err := m.appendBundle(ctx, nil, nil, []*common.PublicKey{witKey})
+	if err != nil {
+		return nil, err
+	}
+
+	return bundle.WitSigningKeys, nil
+}
+
 func (m *Manager) GetCurrentWITKeySlot() Slot {
 	m.witKeyMutex.RLock()
 	defer m.witKeyMutex.RUnlock()
@@ -550,8 +574,7 @@ func (m *Manager) PrepareWITKey(ctx context.Context) (err error) {
 		return err
 	}
 
-	_, err = m.appendBundle(ctx, nil, nil, []*common.PublicKey{publicKey})
-	if err != nil {
+	if _, err := m.PublishWITKey(ctx, publicKey); err != nil {
 		return err
 	}
 
@@ -613,6 +636,9 @@ func (m *Manager) SubscribeToLocalBundle(ctx context.Context) error {
 			return ctx.Err()
 		case <-time.After(5 * time.Second):
 			err := m.upstreamClient.SubscribeToLocalBundle(ctx)
+			if err == nil {
+				err = m.upstreamClient.SubscribeToLocalWITAuthorities(ctx)
+			}
 			switch {
 			case status.Code(err) == codes.Unimplemented:
 				return nil
@@ -1089,6 +1115,11 @@ type bundleUpdater struct {
 	updated                     func()
 	upstreamAuthoritiesTainted  func([]*x509.Certificate)
 	processedTaintedAuthorities map[string]struct{}
+
+	// upstreamWITKeys holds the IDs of the WIT keys received from the
+	// upstream bundle, to tell them apart from the WIT keys of this server,
+	// which are never published upstream.
+	upstreamWITKeys map[string]struct{}
 }
 
 func (u *bundleUpdater) SyncX509Roots(ctx context.Context, roots []*x509certificate.X509Authority) error {
@@ -1168,6 +1199,68 @@ func (u *bundleUpdater) AppendJWTKeys(ctx context.Context, keys []*common.Public
 	return bundle.JwtSigningKeys, nil
 }
 
+// SyncWITKeys syncs the WIT keys of the bundle with the WIT keys of the
+// upstream bundle. New keys are appended, keys tainted upstream are tainted,
+// and tainted keys that are no longer in the upstream bundle are revoked,
+// following the same rules as SyncX509Roots. The WIT keys of this server are
+// left untouched.
+func (u *bundleUpdater) SyncWITKeys(ctx context.Context, keys []*common.PublicKey) error {
+	witKeys, err := u.fetchWITKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	newKeys := make(map[string]struct{}, len(keys))
+	var keysToAppend []*common.PublicKey
+	for _, key := range keys {
+		newKeys[key.Kid] = struct{}{}
+		u.upstreamWITKeys[key.Kid] = struct{}{}
+
+		found, ok := witKeys[key.Kid]
+		if !ok {
+			keysToAppend = append(keysToAppend, key)
+			continue
+		}
+		if key.TaintedKey && !found.TaintedKey {
+			if _, err := u.ds.TaintWITKey(ctx, u.trustDomainID, key.Kid); err != nil {
+				return fmt.Errorf("failed to taint WIT key %q: %w", key.Kid, err)
+			}
+			u.log.WithField(telemetry.WITAuthorityKeyID, key.Kid).Info("WIT authority tainted")
+			changed = true
+		}
+	}
+
+	for kid := range u.upstreamWITKeys {
+		if _, ok := newKeys[kid]; ok {
+			continue
+		}
+		delete(u.upstreamWITKeys, kid)
+
+		// Only tainted keys can be revoked. Keys that left the upstream
+		// bundle untainted expired there, and are pruned here as well.
+		if found, ok := witKeys[kid]; ok && found.TaintedKey {
+			if _, err := u.ds.RevokeWITKey(ctx, u.trustDomainID, kid); err != nil {
+				return fmt.Errorf("failed to revoke a tainted WIT key %q: %w", kid, err)
+			}
+			u.log.WithField(telemetry.WITAuthorityKeyID, kid).Info("WIT authority revoked")
+			changed = true
+		}
+	}
+
+	if len(keysToAppend) > 0 {
+		_, err := u.appendBundle(ctx, &common.Bundle{
+			TrustDomainId:  u.trustDomainID,
+			WitSigningKeys: keysToAppend,
+		})
+		return err
+	}
+	if changed {
+		u.updated()
+	}
+	return nil
+}
+
 func (u *bundleUpdater) LogError(err error, msg string) {
 	u.log.WithError(err).Error(msg)
 }
@@ -1198,6 +1291,23 @@ func (u *bundleUpdater) fetchX509Authorities(ctx context.Context) (map[string]*x
 	return authorities, nil
 }
 
+func (u *bundleUpdater) fetchWITKeys(ctx context.Context) (map[string]*common.PublicKey, error) {
+	bundle, err := u.ds.FetchBundle(ctx, u.trustDomainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bundle: %w", err)
+	}
+	// Bundle not found
+	if bundle == nil {
+		return nil, nil
+	}
+
+	witKeys := make(map[string]*common.PublicKey, len(bundle.WitSigningKeys))
+	for _, witKey := range bundle.WitSigningKeys {
+		witKeys[witKey.Kid] = witKey
+	}
+	return witKeys, nil
+}
+
 func (u *bundleUpdater) appendBundle(ctx context.Context, bundle *common.Bundle) (*common.Bundle, error) {
 	dsBundle, err := u.ds.AppendBundle(ctx, bundle)
 	if err != nil {
@@ -40,6 +40,7 @@ import (
 	"github.com/spiffe/spire/test/fakes/fakeupstreamauthority"
 	"github.com/spiffe/spire/test/spiretest"
 	"github.com/spiffe/spire/test/testca"
+	"github.com/spiffe/spire/test/testkey"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
@@ -751,6 +752,59 @@ func TestUpstreamAuthorityWithSubscribeToBundleUpdate(t *testing.T) {
 	spiretest.AssertProtoListEqual(t, ua.JWTKeys(), test.fetchBundle(ctx).JwtSigningKeys)
 }
 
+func TestUpstreamAuthorityWithWITAuthorities(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	test := setupTest(t)
+	upstreamAuthority, upstreamWITAuthority, ua := fakeupstreamauthority.LoadWithWITAuthority(t, fakeupstreamauthority.Config{
+		TrustDomain: testTrustDomain,
+		Clock:       test.clock,
+	})
+	test.cat.SetUpstreamWITAuthority(upstreamWITAuthority)
+	test.initAndActivateUpstreamSignedManager(ctx, upstreamAuthority)
+	localWITKey := test.currentWITKey()
+
+	upstreamWITKey := makeWITPublicKey(t, "UPSTREAM-KEY")
+	ua.AppendWITKey(upstreamWITKey)
+	require.NoError(t, test.m.upstreamClient.SubscribeToLocalWITAuthorities(ctx))
+
+	requireBundleWITKey := func(tainted bool) {
+		require.EventuallyWithT(t, func(c *assert.CollectT) {
+			bundle := test.fetchBundle(ctx)
+			keys := make(map[string]*common.PublicKey)
+			for _, key := range bundle.WitSigningKeys {
+				keys[key.Kid] = key
+			}
+			// The local WIT authority is untouched by the sync.
+			assert.Contains(c, keys, localWITKey.Kid)
+			if assert.Contains(c, keys, upstreamWITKey.Kid) {
+				assert.Equal(c, tainted, keys[upstreamWITKey.Kid].TaintedKey)
+			}
+		}, time.Minute, 10*time.Millisecond)
+	}
+
+	// The upstream WIT authority is added to the local bundle.
+	requireBundleWITKey(false)
+
+	// Tainting the WIT authority upstream taints it in the local bundle.
+	require.NoError(t, ua.TaintWITKey(upstreamWITKey.Kid))
+	requireBundleWITKey(true)
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		assert.Equal(c, 1, test.countLogEntries(logrus.InfoLevel, "WIT authority tainted"))
+	}, time.Minute, 10*time.Millisecond)
+
+	// Revoking the WIT authority upstream revokes it in the local bundle.
+	require.NoError(t, ua.RemoveWITKey(upstreamWITKey.Kid))
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		bundle := test.fetchBundle(ctx)
+		if assert.Len(c, bundle.WitSigningKeys, 1) {
+			assert.Equal(c, localWITKey.Kid, bundle.WitSigningKeys[0].Kid)
+		}
+	}, time.Minute, 10*time.Millisecond)
+	assert.Equal(t, 1, test.countLogEntries(logrus.InfoLevel, "WIT authority revoked"))
+}
+
 func TestX509CARotation(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 	defer cancel()
@@ -1735,6 +1789,16 @@ func (m *managerTest) requireBundleWITKeys(ctx context.Context, t *testing.T, wi
 	})
 }
 
+func makeWITPublicKey(t *testing.T, kid string) *common.PublicKey {
+	pkixBytes, err := x509.MarshalPKIXPublicKey(testkey.NewEC256(t).Public())
+	require.NoError(t, err)
+	return &common.PublicKey{
+		Kid:       kid,
+		PkixBytes: pkixBytes,
+		NotAfter:  time.Now().Add(time.Hour).Unix(),
+	}
+}
+
 func (m *managerTest) createBundle(ctx context.Context) *common.Bundle {
 	bundle, err := m.ds.CreateBundle(ctx, &common.Bundle{
 		TrustDomainId: testTrustDomain.IDString(),
@@ -1921,14 +1985,14 @@ func (cc fakeCC) ComposeServerX509SVID(_ context.Context, attributes credentialc
 	return attributes, nil
 }
 
-func (cc fakeCC) ComposeAgentX509SVID(_ context.Context, _ spiffeid.ID, _ crypto.PublicKey, attributes credentialcomposer.X509SVIDAttributes) (credentialcomposer.X509SVIDAttributes, error) {
+func (cc fakeCC) ComposeAgentX509SVID(_ context.Context, _ spiffeid.ID, _ crypto.PublicKey, _ credentialcomposer.SVIDContext, attributes credentialcomposer.X509SVIDAttributes) (credentialcomposer.X509SVIDAttributes, error) {
 	return attributes, nil
 }
 
-func (cc fakeCC) ComposeWorkloadX509SVID(_ context.Context, _ spiffeid.ID, _ crypto.PublicKey, attributes credentialcomposer.X509SVIDAttributes) (credentialcomposer.X509SVIDAttributes, error) {
+func (cc fakeCC) ComposeWorkloadX509SVID(_ context.Context, _ spiffeid.ID, _ crypto.PublicKey, _ credentialcomposer.SVIDContext, attributes credentialcomposer.X509SVIDAttributes) (credentialcomposer.X509SVIDAttributes, error) {
 	return attributes, nil
 }
 
-func (cc fakeCC) ComposeWorkloadJWTSVID(_ context.Context, _ spiffeid.ID, attributes credentialcomposer.JWTSVIDAttributes) (credentialcomposer.JWTSVIDAttributes, error) {
+func (cc fakeCC) ComposeWorkloadJWTSVID(_ context.Context, _ spiffeid.ID, _ credentialcomposer.SVIDContext, attributes credentialcomposer.JWTSVIDAttributes) (credentialcomposer.JWTSVIDAttributes, error) {
 	return attributes, nil
 }
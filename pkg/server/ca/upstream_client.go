@@ -20,6 +20,7 @@ import (
 type BundleUpdater interface {
 	SyncX509Roots(ctx context.Context, roots []*x509certificate.X509Authority) error
 	AppendJWTKeys(ctx context.Context, keys []*common.PublicKey) ([]*common.PublicKey, error)
+	SyncWITKeys(ctx context.Context, keys []*common.PublicKey) error
 	LogError(err error, msg string)
 }
 
@@ -28,10 +29,12 @@ type BundleUpdater interface {
 type ValidateX509CAFunc = func(x509CA, x509Roots []*x509.Certificate) error
 
 // UpstreamClientConfig is the configuration for an UpstreamClient. Each field
-// is required.
+// is required, except UpstreamWITAuthority, which is only set when the
+// UpstreamAuthority plugin provides the WIT authorities of the upstream bundle.
 type UpstreamClientConfig struct {
-	UpstreamAuthority upstreamauthority.UpstreamAuthority
-	BundleUpdater     BundleUpdater
+	UpstreamAuthority    upstreamauthority.UpstreamAuthority
+	UpstreamWITAuthority upstreamauthority.UpstreamWITAuthority
+	BundleUpdater        BundleUpdater
 }
 
 // UpstreamClient is used to interact with and stream updates from the
@@ -39,21 +42,24 @@ type UpstreamClientConfig struct {
 type UpstreamClient struct {
 	c UpstreamClientConfig
 
-	mintX509CAMtx                   sync.Mutex
-	mintX509CAStream                *streamState
-	publishJWTKeyMtx                sync.Mutex
-	publishJWTKeyStream             *streamState
-	subscribeToLocalBundleStreamMtx sync.Mutex
-	subscribeToLocalBundleStream    *streamState
+	mintX509CAMtx                      sync.Mutex
+	mintX509CAStream                   *streamState
+	publishJWTKeyMtx                   sync.Mutex
+	publishJWTKeyStream                *streamState
+	subscribeToLocalBundleStreamMtx    sync.Mutex
+	subscribeToLocalBundleStream       *streamState
+	subscribeToWITAuthoritiesStreamMtx sync.Mutex
+	subscribeToWITAuthoritiesStream    *streamState
 }
 
 // NewUpstreamClient returns a new UpstreamAuthority plugin client.
 func NewUpstreamClient(config UpstreamClientConfig) *UpstreamClient {
 	return &UpstreamClient{
-		c:                            config,
-		mintX509CAStream:             newStreamState(),
-		publishJWTKeyStream:          newStreamState(),
-		subscribeToLocalBundleStream: newStreamState(),
+		c:                               config,
+		mintX509CAStream:                newStreamState(),
+		publishJWTKeyStream:             newStreamState(),
+		subscribeToLocalBundleStream:    newStreamState(),
+		subscribeToWITAuthoritiesStream: newStreamState(),
 	}
 }
 
@@ -75,6 +81,11 @@ func (u *UpstreamClient) Close() error {
 		defer u.subscribeToLocalBundleStreamMtx.Unlock()
 		u.subscribeToLocalBundleStream.Stop()
 	}()
+	func() {
+		u.subscribeToWITAuthoritiesStreamMtx.Lock()
+		defer u.subscribeToWITAuthoritiesStreamMtx.Unlock()
+		u.subscribeToWITAuthoritiesStream.Stop()
+	}()
 	return nil
 }
 
@@ -152,6 +163,37 @@ func (u *UpstreamClient) SubscribeToLocalBundle(ctx context.Context) (err error)
 	}
 }
 
+// SubscribeToLocalWITAuthorities subscribes to the WIT authorities of the
+// upstream bundle, when the UpstreamAuthority plugin provides them. It
+// maintains an open stream to the UpstreamAuthority plugin to receive and sync
+// WIT authority updates to the bundle. The stream remains open until another
+// call to SubscribeToLocalWITAuthorities happens or the client is closed.
+func (u *UpstreamClient) SubscribeToLocalWITAuthorities(ctx context.Context) (err error) {
+	if u.c.UpstreamWITAuthority == nil {
+		return nil
+	}
+
+	u.subscribeToWITAuthoritiesStreamMtx.Lock()
+	defer u.subscribeToWITAuthoritiesStreamMtx.Unlock()
+
+	firstResultCh := make(chan error, 1)
+	u.subscribeToWITAuthoritiesStream.Start(func(streamCtx context.Context) {
+		u.runSubscribeToLocalWITAuthoritiesStream(streamCtx, firstResultCh)
+	})
+	defer func() {
+		if err != nil {
+			u.subscribeToWITAuthoritiesStream.Stop()
+		}
+	}()
+
+	select {
+	case err := <-firstResultCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (u *UpstreamClient) runMintX509CAStream(ctx context.Context, csr []byte, ttl time.Duration, validateX509CA ValidateX509CAFunc, firstResultCh chan<- mintX509CAResult) {
 	x509CA, x509Roots, x509RootsStream, err := u.c.UpstreamAuthority.MintX509CA(ctx, csr, ttl)
 	if err != nil {
@@ -300,6 +342,43 @@ func (u *UpstreamClient) runSubscribeToLocalBundleStream(ctx context.Context, fi
 	}
 }
 
+func (u *UpstreamClient) runSubscribeToLocalWITAuthoritiesStream(ctx context.Context, firstResultCh chan<- error) {
+	witKeys, witKeysStream, err := u.c.UpstreamWITAuthority.SubscribeToLocalWITAuthorities(ctx)
+	if err != nil {
+		firstResultCh <- err
+		return
+	}
+	defer witKeysStream.Close()
+
+	if err := u.c.BundleUpdater.SyncWITKeys(ctx, witKeys); err != nil {
+		firstResultCh <- err
+		return
+	}
+	firstResultCh <- nil
+
+	for {
+		witKeys, err := witKeysStream.RecvUpstreamWITAuthorities()
+		if err != nil {
+			switch {
+			case errors.Is(err, io.EOF):
+				// This is normal if the plugin does not support streaming
+				// bundle updates.
+			case status.Code(err) == codes.Canceled:
+				// This is normal. This client cancels this stream when opening
+				// a new stream.
+			default:
+				u.c.BundleUpdater.LogError(err, "The upstream authority plugin stopped streaming WIT authority updates prematurely. Please report this bug. Will retry later.")
+			}
+			return
+		}
+
+		if err := u.c.BundleUpdater.SyncWITKeys(ctx, witKeys); err != nil {
+			u.c.BundleUpdater.LogError(err, "Failed to store WIT authorities received by the upstream authority plugin.")
+			continue
+		}
+	}
+}
+
 type mintX509CAResult struct {
 	x509CA []*x509.Certificate
 	err    error
@@ -188,6 +188,53 @@ func TestUpstreamClientSubscribeToLocalBundle(t *testing.T) {
 	spiretest.RequireProtoListEqual(t, []*common.PublicKey{key1, key2}, updater.WaitForAppendedJWTKeys(t))
 }
 
+func TestUpstreamClientSubscribeToLocalWITAuthorities(t *testing.T) {
+	upstreamAuthority, upstreamWITAuthority, ua := fakeupstreamauthority.LoadWithWITAuthority(t, fakeupstreamauthority.Config{
+		TrustDomain: trustDomain,
+	})
+	updater := newFakeBundleUpdater()
+
+	client := ca.NewUpstreamClient(ca.UpstreamClientConfig{
+		UpstreamAuthority:    upstreamAuthority,
+		UpstreamWITAuthority: upstreamWITAuthority,
+		BundleUpdater:        updater,
+	})
+	t.Cleanup(func() {
+		assert.NoError(t, client.Close())
+	})
+
+	err := client.SubscribeToLocalWITAuthorities(t.Context())
+	require.NoError(t, err)
+
+	// We should get an update with an empty list of WIT keys since the
+	// fakeupstreamauthority does not create one by default.
+	require.Empty(t, updater.WaitForSyncedWITKeys(t))
+
+	key1 := makePublicKey(t, "KEY1")
+	ua.AppendWITKey(key1)
+	spiretest.RequireProtoListEqual(t, []*common.PublicKey{key1}, updater.WaitForSyncedWITKeys(t))
+
+	// Tainting the key upstream is carried to the bundle updater.
+	require.NoError(t, ua.TaintWITKey("KEY1"))
+	spiretest.RequireProtoListEqual(t, []*common.PublicKey{
+		{Kid: "KEY1", PkixBytes: key1.PkixBytes, TaintedKey: true},
+	}, updater.WaitForSyncedWITKeys(t))
+
+	// Removing (revoking) the key upstream is carried to the bundle updater.
+	require.NoError(t, ua.RemoveWITKey("KEY1"))
+	require.Empty(t, updater.WaitForSyncedWITKeys(t))
+}
+
+func TestUpstreamClientSubscribeToLocalWITAuthoritiesNotServed(t *testing.T) {
+	client, _, _ := setupUpstreamClientTest(t, fakeupstreamauthority.Config{
+		TrustDomain: trustDomain,
+	})
+
+	// The plugin does not serve the WIT authorities, so there is nothing to
+	// subscribe to.
+	require.NoError(t, client.SubscribeToLocalWITAuthorities(t.Context()))
+}
+
 func setupUpstreamClientTest(t *testing.T, config fakeupstreamauthority.Config) (*ca.UpstreamClient, *fakeBundleUpdater, *fakeupstreamauthority.UpstreamAuthority) {
 	plugin, upstreamAuthority := fakeupstreamauthority.Load(t, config)
 	updater := newFakeBundleUpdater()
@@ -211,6 +258,7 @@ type bundleUpdateErr struct {
 type fakeBundleUpdater struct {
 	x509RootsCh chan []*x509certificate.X509Authority
 	jwtKeysCh   chan []*common.PublicKey
+	witKeysCh   chan []*common.PublicKey
 	errorCh     chan bundleUpdateErr
 }
 
@@ -218,6 +266,7 @@ func newFakeBundleUpdater() *fakeBundleUpdater {
 	return &fakeBundleUpdater{
 		x509RootsCh: make(chan []*x509certificate.X509Authority, 1),
 		jwtKeysCh:   make(chan []*common.PublicKey, 1),
+		witKeysCh:   make(chan []*common.PublicKey, 1),
 		errorCh:     make(chan bundleUpdateErr, 1),
 	}
 }
@@ -260,6 +309,25 @@ func (u *fakeBundleUpdater) WaitForAppendedJWTKeys(t *testing.T) []*common.Publi
 	}
 }
 
+func (u *fakeBundleUpdater) SyncWITKeys(ctx context.Context, witKeys []*common.PublicKey) error {
+	select {
+	case u.witKeysCh <- witKeys:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (u *fakeBundleUpdater) WaitForSyncedWITKeys(t *testing.T) []*common.PublicKey {
+	select {
+	case <-time.After(time.Minute):
+		require.FailNow(t, "timed out waiting for WIT keys to be synced")
+		return nil // unreachable
+	case witKeys := <-u.witKeysCh:
+		return witKeys
+	}
+}
+
 func (u *fakeBundleUpdater) LogError(err error, msg string) {
 	e := bundleUpdateErr{
 		err: err,
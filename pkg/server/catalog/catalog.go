@@ -47,6 +47,8 @@ const (
 
 var ReconfigureTask = catalog.ReconfigureTask
 
+type Reconfigurers = catalog.Reconfigurers
+
 type Catalog interface {
 	GetBundlePublishers() []bundlepublisher.BundlePublisher
 	GetCredentialComposers() []credentialcomposer.CredentialComposer
@@ -55,6 +57,7 @@ type Catalog interface {
 	GetKeyManager() keymanager.KeyManager
 	GetNotifiers() []notifier.Notifier
 	GetUpstreamAuthority() (upstreamauthority.UpstreamAuthority, bool)
+	GetUpstreamWITAuthority() (upstreamauthority.UpstreamWITAuthority, bool)
 }
 
 type PluginConfigs = catalog.PluginConfigs
@@ -110,7 +113,9 @@ func (repo *Repository) Plugins() map[string]catalog.PluginRepo {
 }
 
 func (repo *Repository) Services() []catalog.ServiceRepo {
-	return nil
+	return []catalog.ServiceRepo{
+		upstreamWITAuthorityRepository{repo: &repo.upstreamAuthorityRepository.Repository},
+	}
 }
 
 func (repo *Repository) Reconfigure(ctx context.Context) {
@@ -194,6 +199,18 @@ func Load(ctx context.Context, config Config) (_ *Repository, err error) {
 	return repo, nil
 }
 
+// LoadDataStore loads only the DataStore from the plugin configuration. It
+// is intended for offline tooling (e.g. datastore export and import) that
+// needs direct access to the datastore without loading the other plugins.
+// The caller is responsible for closing the returned DataStore.
+func LoadDataStore(ctx context.Context, config Config) (*ds_sql.Plugin, error) {
+	coreConfig := catalog.CoreConfig{
+		TrustDomain: config.TrustDomain,
+	}
+	dataStoreConfigs, _ := config.PluginConfigs.FilterByType(dataStoreType)
+	return loadSQLDataStore(ctx, config, coreConfig, dataStoreConfigs)
+}
+
 func ValidateConfig(ctx context.Context, config Config) (pluginNotes map[string][]string, err error) {
 	if c, ok := config.PluginConfigs.Find(nodeAttestorType, jointoken.PluginName); ok && c.IsEnabled() && c.IsExternal() {
 		return nil, errors.New("the built-in join_token node attestor cannot be overridden by an external plugin")
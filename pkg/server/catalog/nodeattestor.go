@@ -9,6 +9,7 @@ import (
 	"github.com/spiffe/spire/pkg/server/plugin/nodeattestor/gcpiit"
 	"github.com/spiffe/spire/pkg/server/plugin/nodeattestor/httpchallenge"
 	"github.com/spiffe/spire/pkg/server/plugin/nodeattestor/jointoken"
+	"github.com/spiffe/spire/pkg/server/plugin/nodeattestor/jwtoidc"
 	"github.com/spiffe/spire/pkg/server/plugin/nodeattestor/k8spsat"
 	"github.com/spiffe/spire/pkg/server/plugin/nodeattestor/sshpop"
 	"github.com/spiffe/spire/pkg/server/plugin/nodeattestor/tpmdevid"
@@ -41,6 +42,7 @@ func (repo *nodeAttestorRepository) BuiltIns() []catalog.BuiltIn {
 		gcpiit.BuiltIn(),
 		httpchallenge.BuiltIn(),
 		jointoken.BuiltIn(),
+		jwtoidc.BuiltIn(),
 		k8spsat.BuiltIn(),
 		sshpop.BuiltIn(),
 		tpmdevid.BuiltIn(),
@@ -48,3 +48,28 @@ type upstreamAuthorityV1 struct{}
 
 func (upstreamAuthorityV1) New() catalog.Facade { return new(upstreamauthority.V1) }
 func (upstreamAuthorityV1) Deprecated() bool    { return false }
+
+// upstreamWITAuthorityRepository binds the UpstreamWITAuthority service that
+// UpstreamAuthority plugins can serve next to the plugin interface.
+type upstreamWITAuthorityRepository struct {
+	repo *upstreamauthority.Repository
+}
+
+func (r upstreamWITAuthorityRepository) Binder() any {
+	return r.repo.SetUpstreamWITAuthority
+}
+
+func (r upstreamWITAuthorityRepository) Versions() []catalog.Version {
+	return []catalog.Version{
+		upstreamWITAuthorityV1{},
+	}
+}
+
+func (r upstreamWITAuthorityRepository) Clear() {
+	r.repo.ClearUpstreamWITAuthority()
+}
+
+type upstreamWITAuthorityV1 struct{}
+
+func (upstreamWITAuthorityV1) New() catalog.Facade { return new(upstreamauthority.WITV1) }
+func (upstreamWITAuthorityV1) Deprecated() bool    { return false }
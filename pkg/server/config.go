@@ -12,10 +12,12 @@ import (
 	"github.com/spiffe/spire/pkg/common/telemetry"
 	"github.com/spiffe/spire/pkg/common/tlspolicy"
 	loggerv1 "github.com/spiffe/spire/pkg/server/api/logger/v1"
+	"github.com/spiffe/spire/pkg/server/auditlog"
 	"github.com/spiffe/spire/pkg/server/authpolicy"
 	bundle_client "github.com/spiffe/spire/pkg/server/bundle/client"
 	"github.com/spiffe/spire/pkg/server/endpoints"
 	"github.com/spiffe/spire/pkg/server/endpoints/bundle"
+	"github.com/spiffe/spire/pkg/server/node"
 	"github.com/spiffe/spire/pkg/server/plugin/keymanager"
 )
 
@@ -28,9 +30,18 @@ type Config struct {
 	// LogReopener facilitates handling a signal to rotate log file.
 	LogReopener func(context.Context) error
 
+	// ConfigReloader, if set, is reconfigured along with the plugins when the
+	// server receives the reconfigure signal. It is used to reload the
+	// settings that can be changed without restarting the server.
+	ConfigReloader common.Reconfigurer
+
 	// If true enables audit logs
 	AuditLogEnabled bool
 
+	// AuditLog configures the dedicated, tamper-evident audit log. When nil,
+	// audit logs are emitted to the main log.
+	AuditLog *auditlog.Config
+
 	// ProxyProtocolTrustedCIDRs is a list of trusted CIDRs for PROXY protocol.
 	// When non-empty, the server enables PROXY protocol on the TCP listener and
 	// restricts PROXY header acceptance to connections originating from these
@@ -149,6 +160,15 @@ type Config struct {
 	// when PruneAttestedNodesExpiredFor is set.
 	PruneAttestedNodesBatchSize int
 
+	// StaleAgentsNotSeenFor enables periodic handling of agents that have
+	// not contacted the server within the given duration. Agents that never
+	// reported their status and banned agents are not considered.
+	StaleAgentsNotSeenFor time.Duration
+
+	// StaleAgentsAction is the action taken on stale agents. Only applies
+	// when StaleAgentsNotSeenFor is set.
+	StaleAgentsAction node.StaleAgentAction
+
 	// MaxAttestedNodeInfoStaleness determines how long to trust cached attested
 	// node information, before requiring refreshing it from the datastore.
 	MaxAttestedNodeInfoStaleness time.Duration
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"math/big"
 	"net/url"
+	"sync/atomic"
 	"time"
 
 	"github.com/andres-erbsen/clock"
@@ -88,6 +89,7 @@ type AgentX509SVIDParams struct {
 	ParentChain []*x509.Certificate
 	PublicKey   crypto.PublicKey
 	SPIFFEID    spiffeid.ID
+	SVIDContext credentialcomposer.SVIDContext
 }
 
 type WorkloadX509SVIDParams struct {
@@ -97,6 +99,7 @@ type WorkloadX509SVIDParams struct {
 	DNSNames    []string
 	TTL         time.Duration
 	Subject     pkix.Name
+	SVIDContext credentialcomposer.SVIDContext
 }
 
 type WorkloadJWTSVIDParams struct {
@@ -106,7 +109,8 @@ type WorkloadJWTSVIDParams struct {
 	ExpirationCap time.Time
 	// IncludeJTI, when true, causes the issued JWT-SVID to include a unique "jti"
 	// (JWT ID) claim, making each token individually auditable.
-	IncludeJTI bool
+	IncludeJTI  bool
+	SVIDContext credentialcomposer.SVIDContext
 }
 
 type WorkloadWITSVIDParams struct {
@@ -135,11 +139,24 @@ type Config struct {
 
 type Builder struct {
 	config Config
+	ttls   atomic.Pointer[svidTTLs]
+
+	// agentTTLFollowsX509 is true when the agent SVID TTL was not
+	// configured and therefore tracks the X509-SVID TTL.
+	agentTTLFollowsX509 bool
 
 	x509CAID spiffeid.ID
 	serverID spiffeid.ID
 }
 
+// svidTTLs holds the default SVID TTLs, which can be updated while the
+// builder is in use.
+type svidTTLs struct {
+	x509SVID  time.Duration
+	jwtSVID   time.Duration
+	agentSVID time.Duration
+}
+
 func NewBuilder(config Config) (*Builder, error) {
 	if config.TrustDomain.IsZero() {
 		return nil, errors.New("trust domain must be set")
@@ -165,7 +182,8 @@ func NewBuilder(config Config) (*Builder, error) {
 	if config.WITSVIDTTL == 0 {
 		config.WITSVIDTTL = DefaultWITSVIDTTL
 	}
-	if config.AgentSVIDTTL == 0 {
+	agentTTLFollowsX509 := config.AgentSVIDTTL == 0
+	if agentTTLFollowsX509 {
 		// config.X509SVIDTTL should be initialized by the code above and
 		// therefore safe to use to initialize the AgentSVIDTTL.
 		config.AgentSVIDTTL = config.X509SVIDTTL
@@ -180,15 +198,48 @@ func NewBuilder(config Config) (*Builder, error) {
 		return nil, err
 	}
 
-	return &Builder{
-		config:   config,
-		x509CAID: config.TrustDomain.ID(),
-		serverID: serverID,
-	}, nil
+	b := &Builder{
+		config:              config,
+		agentTTLFollowsX509: agentTTLFollowsX509,
+		x509CAID:            config.TrustDomain.ID(),
+		serverID:            serverID,
+	}
+	b.ttls.Store(&svidTTLs{
+		x509SVID:  config.X509SVIDTTL,
+		jwtSVID:   config.JWTSVIDTTL,
+		agentSVID: config.AgentSVIDTTL,
+	})
+	return b, nil
 }
 
 func (b *Builder) Config() Config {
-	return b.config
+	config := b.config
+	ttls := b.ttls.Load()
+	config.X509SVIDTTL = ttls.x509SVID
+	config.JWTSVIDTTL = ttls.jwtSVID
+	config.AgentSVIDTTL = ttls.agentSVID
+	return config
+}
+
+// SetDefaultSVIDTTLs updates the TTLs given to X509-SVIDs and JWT-SVIDs
+// when a TTL is not requested. Zero values restore the defaults. The agent
+// SVID TTL follows the X509-SVID TTL unless it was configured explicitly.
+func (b *Builder) SetDefaultSVIDTTLs(x509SVIDTTL, jwtSVIDTTL time.Duration) {
+	if x509SVIDTTL == 0 {
+		x509SVIDTTL = DefaultX509SVIDTTL
+	}
+	if jwtSVIDTTL == 0 {
+		jwtSVIDTTL = DefaultJWTSVIDTTL
+	}
+	agentSVIDTTL := b.config.AgentSVIDTTL
+	if b.agentTTLFollowsX509 {
+		agentSVIDTTL = x509SVIDTTL
+	}
+	b.ttls.Store(&svidTTLs{
+		x509SVID:  x509SVIDTTL,
+		jwtSVID:   jwtSVIDTTL,
+		agentSVID: agentSVIDTTL,
+	})
 }
 
 func (b *Builder) BuildSelfSignedX509CATemplate(ctx context.Context, params SelfSignedX509CAParams) (*x509.Certificate, error) {
@@ -276,13 +327,13 @@ func (b *Builder) BuildServerX509SVIDTemplate(ctx context.Context, params Server
 }
 
 func (b *Builder) BuildAgentX509SVIDTemplate(ctx context.Context, params AgentX509SVIDParams) (*x509.Certificate, error) {
-	tmpl, err := b.buildX509SVIDTemplate(params.SPIFFEID, params.PublicKey, params.ParentChain, pkix.Name{}, b.config.AgentSVIDTTL)
+	tmpl, err := b.buildX509SVIDTemplate(params.SPIFFEID, params.PublicKey, params.ParentChain, pkix.Name{}, b.ttls.Load().agentSVID)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, cc := range b.config.CredentialComposers {
-		attributes, err := cc.ComposeAgentX509SVID(ctx, params.SPIFFEID, params.PublicKey, x509SVIDAttributesFromTemplate(tmpl))
+		attributes, err := cc.ComposeAgentX509SVID(ctx, params.SPIFFEID, params.PublicKey, params.SVIDContext, x509SVIDAttributesFromTemplate(tmpl))
 		if err != nil {
 			return nil, err
 		}
@@ -313,7 +364,7 @@ func (b *Builder) BuildWorkloadX509SVIDTemplate(ctx context.Context, params Work
 	}
 
 	for _, cc := range b.config.CredentialComposers {
-		attributes, err := cc.ComposeWorkloadX509SVID(ctx, params.SPIFFEID, params.PublicKey, x509SVIDAttributesFromTemplate(tmpl))
+		attributes, err := cc.ComposeWorkloadX509SVID(ctx, params.SPIFFEID, params.PublicKey, params.SVIDContext, x509SVIDAttributesFromTemplate(tmpl))
 		if err != nil {
 			return nil, err
 		}
@@ -340,7 +391,7 @@ func (b *Builder) BuildWorkloadJWTSVIDClaims(ctx context.Context, params Workloa
 
 	ttl := params.TTL
 	if ttl <= 0 {
-		ttl = b.config.JWTSVIDTTL
+		ttl = b.ttls.Load().jwtSVID
 	}
 	_, expiresAt := computeCappedLifetime(b.config.Clock, ttl, params.ExpirationCap)
 
@@ -366,7 +417,7 @@ func (b *Builder) BuildWorkloadJWTSVIDClaims(ctx context.Context, params Workloa
 
 	for _, cc := range b.config.CredentialComposers {
 		var err error
-		attributes, err = cc.ComposeWorkloadJWTSVID(ctx, params.SPIFFEID, attributes)
+		attributes, err = cc.ComposeWorkloadJWTSVID(ctx, params.SPIFFEID, params.SVIDContext, attributes)
 		if err != nil {
 			return nil, err
 		}
@@ -505,7 +556,7 @@ func (b *Builder) computeX509CALifetime(parentChain []*x509.Certificate, ttl tim
 
 func (b *Builder) computeX509SVIDLifetime(parentChain []*x509.Certificate, ttl time.Duration) (notBefore, notAfter time.Time) {
 	if ttl <= 0 {
-		ttl = b.config.X509SVIDTTL
+		ttl = b.ttls.Load().x509SVID
 	}
 	return computeCappedLifetime(b.config.Clock, ttl, parentChainExpiration(parentChain))
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/go-jose/go-jose/v4"
 	"github.com/go-jose/go-jose/v4/jwt"
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
 	credentialcomposerv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/server/credentialcomposer/v1"
 	"github.com/spiffe/spire/pkg/common/catalog"
 	"github.com/spiffe/spire/pkg/common/x509util"
@@ -122,6 +123,65 @@ func TestNewBuilderAllowsConfigOverrides(t *testing.T) {
 	assert.Equal(t, configIn, configOut)
 }
 
+func TestSetDefaultSVIDTTLs(t *testing.T) {
+	t.Run("agent TTL follows X509-SVID TTL", func(t *testing.T) {
+		builder, err := credtemplate.NewBuilder(credtemplate.Config{
+			TrustDomain: td,
+		})
+		require.NoError(t, err)
+
+		builder.SetDefaultSVIDTTLs(2*time.Minute, 3*time.Minute)
+		config := builder.Config()
+		assert.Equal(t, 2*time.Minute, config.X509SVIDTTL)
+		assert.Equal(t, 3*time.Minute, config.JWTSVIDTTL)
+		assert.Equal(t, 2*time.Minute, config.AgentSVIDTTL)
+
+		builder.SetDefaultSVIDTTLs(0, 0)
+		config = builder.Config()
+		assert.Equal(t, credtemplate.DefaultX509SVIDTTL, config.X509SVIDTTL)
+		assert.Equal(t, credtemplate.DefaultJWTSVIDTTL, config.JWTSVIDTTL)
+		assert.Equal(t, credtemplate.DefaultX509SVIDTTL, config.AgentSVIDTTL)
+	})
+
+	t.Run("agent TTL configured", func(t *testing.T) {
+		builder, err := credtemplate.NewBuilder(credtemplate.Config{
+			TrustDomain:  td,
+			AgentSVIDTTL: 4 * time.Minute,
+		})
+		require.NoError(t, err)
+
+		builder.SetDefaultSVIDTTLs(2*time.Minute, 3*time.Minute)
+		assert.Equal(t, 4*time.Minute, builder.Config().AgentSVIDTTL)
+	})
+
+	t.Run("applies to new SVIDs", func(t *testing.T) {
+		clk := clock.NewMock(t)
+		clk.Set(now)
+		builder, err := credtemplate.NewBuilder(credtemplate.Config{
+			TrustDomain: td,
+			Clock:       clk,
+		})
+		require.NoError(t, err)
+		builder.SetDefaultSVIDTTLs(2*time.Minute, 3*time.Minute)
+
+		tmpl, err := builder.BuildWorkloadX509SVIDTemplate(ctx, credtemplate.WorkloadX509SVIDParams{
+			ParentChain: parentChain,
+			PublicKey:   publicKey,
+			SPIFFEID:    workloadID,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, now.Add(2*time.Minute), tmpl.NotAfter)
+
+		claims, err := builder.BuildWorkloadJWTSVIDClaims(ctx, credtemplate.WorkloadJWTSVIDParams{
+			SPIFFEID:      workloadID,
+			Audience:      []string{"AUDIENCE"},
+			ExpirationCap: now.Add(time.Hour),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, jwt.NewNumericDate(now.Add(3*time.Minute)), claims["exp"])
+	})
+}
+
 func TestBuildSelfSignedX509CATemplate(t *testing.T) {
 	oneTwoThreeFourOID, err := x509.ParseOID("1.2.3.4")
 	require.NoError(t, err)
@@ -1351,6 +1411,49 @@ func TestBuildWorkloadJWTSVIDClaimsIncludeJTI(t *testing.T) {
 	})
 }
 
+func TestBuildersPassSVIDContextToComposers(t *testing.T) {
+	svidContext := credentialcomposer.SVIDContext{
+		Entry: &types.Entry{
+			Id:        "ENTRYID",
+			SpiffeId:  &types.SPIFFEID{TrustDomain: td.Name(), Path: workloadID.Path()},
+			Selectors: []*types.Selector{{Type: "k8s", Value: "ns:foo"}},
+		},
+		AgentID:        agentID,
+		AgentSelectors: []*types.Selector{{Type: "k8s_psat", Value: "cluster:demo"}},
+	}
+
+	cc := &recordingCC{}
+	testBuilder(t, func(config *credtemplate.Config) {
+		config.CredentialComposers = []credentialcomposer.CredentialComposer{cc}
+	}, func(t *testing.T, credBuilder *credtemplate.Builder) {
+		_, err := credBuilder.BuildAgentX509SVIDTemplate(ctx, credtemplate.AgentX509SVIDParams{
+			ParentChain: parentChain,
+			PublicKey:   publicKey,
+			SPIFFEID:    agentID,
+			SVIDContext: svidContext,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, svidContext, cc.agentX509SVIDContext)
+
+		_, err = credBuilder.BuildWorkloadX509SVIDTemplate(ctx, credtemplate.WorkloadX509SVIDParams{
+			ParentChain: parentChain,
+			PublicKey:   publicKey,
+			SPIFFEID:    workloadID,
+			SVIDContext: svidContext,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, svidContext, cc.workloadX509SVIDContext)
+
+		_, err = credBuilder.BuildWorkloadJWTSVIDClaims(ctx, credtemplate.WorkloadJWTSVIDParams{
+			SPIFFEID:    workloadID,
+			Audience:    []string{"AUDIENCE"},
+			SVIDContext: svidContext,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, svidContext, cc.workloadJWTSVIDContext)
+	})
+}
+
 func testBuilder(t *testing.T, overrideConfig func(config *credtemplate.Config), fn func(*testing.T, *credtemplate.Builder)) {
 	config := credtemplate.Config{
 		TrustDomain:     td,
@@ -1379,18 +1482,49 @@ func (badCC) ComposeServerX509SVID(context.Context, credentialcomposer.X509SVIDA
 	return credentialcomposer.X509SVIDAttributes{}, errors.New("oh no")
 }
 
-func (badCC) ComposeAgentX509SVID(context.Context, spiffeid.ID, crypto.PublicKey, credentialcomposer.X509SVIDAttributes) (credentialcomposer.X509SVIDAttributes, error) {
+func (badCC) ComposeAgentX509SVID(context.Context, spiffeid.ID, crypto.PublicKey, credentialcomposer.SVIDContext, credentialcomposer.X509SVIDAttributes) (credentialcomposer.X509SVIDAttributes, error) {
 	return credentialcomposer.X509SVIDAttributes{}, errors.New("oh no")
 }
 
-func (badCC) ComposeWorkloadX509SVID(context.Context, spiffeid.ID, crypto.PublicKey, credentialcomposer.X509SVIDAttributes) (credentialcomposer.X509SVIDAttributes, error) {
+func (badCC) ComposeWorkloadX509SVID(context.Context, spiffeid.ID, crypto.PublicKey, credentialcomposer.SVIDContext, credentialcomposer.X509SVIDAttributes) (credentialcomposer.X509SVIDAttributes, error) {
 	return credentialcomposer.X509SVIDAttributes{}, errors.New("oh no")
 }
 
-func (badCC) ComposeWorkloadJWTSVID(context.Context, spiffeid.ID, credentialcomposer.JWTSVIDAttributes) (credentialcomposer.JWTSVIDAttributes, error) {
+func (badCC) ComposeWorkloadJWTSVID(context.Context, spiffeid.ID, credentialcomposer.SVIDContext, credentialcomposer.JWTSVIDAttributes) (credentialcomposer.JWTSVIDAttributes, error) {
 	return credentialcomposer.JWTSVIDAttributes{}, errors.New("oh no")
 }
 
+type recordingCC struct {
+	catalog.PluginInfo
+
+	agentX509SVIDContext    credentialcomposer.SVIDContext
+	workloadX509SVIDContext credentialcomposer.SVIDContext
+	workloadJWTSVIDContext  credentialcomposer.SVIDContext
+}
+
+func (*recordingCC) ComposeServerX509CA(_ context.Context, attributes credentialcomposer.X509CAAttributes) (credentialcomposer.X509CAAttributes, error) {
+	return attributes, nil
+}
+
+func (*recordingCC) ComposeServerX509SVID(_ context.Context, attributes credentialcomposer.X509SVIDAttributes) (credentialcomposer.X509SVIDAttributes, error) {
+	return attributes, nil
+}
+
+func (cc *recordingCC) ComposeAgentX509SVID(_ context.Context, _ spiffeid.ID, _ crypto.PublicKey, svidContext credentialcomposer.SVIDContext, attributes credentialcomposer.X509SVIDAttributes) (credentialcomposer.X509SVIDAttributes, error) {
+	cc.agentX509SVIDContext = svidContext
+	return attributes, nil
+}
+
+func (cc *recordingCC) ComposeWorkloadX509SVID(_ context.Context, _ spiffeid.ID, _ crypto.PublicKey, svidContext credentialcomposer.SVIDContext, attributes credentialcomposer.X509SVIDAttributes) (credentialcomposer.X509SVIDAttributes, error) {
+	cc.workloadX509SVIDContext = svidContext
+	return attributes, nil
+}
+
+func (cc *recordingCC) ComposeWorkloadJWTSVID(_ context.Context, _ spiffeid.ID, svidContext credentialcomposer.SVIDContext, attributes credentialcomposer.JWTSVIDAttributes) (credentialcomposer.JWTSVIDAttributes, error) {
+	cc.workloadJWTSVIDContext = svidContext
+	return attributes, nil
+}
+
 type fakeCC struct {
 	catalog.PluginInfo
 
@@ -1423,15 +1557,15 @@ func (cc fakeCC) ComposeServerX509SVID(_ context.Context, attributes credentialc
 	return cc.overrideX509SVIDAttributes(attributes), nil
 }
 
-func (cc fakeCC) ComposeAgentX509SVID(_ context.Context, _ spiffeid.ID, _ crypto.PublicKey, attributes credentialcomposer.X509SVIDAttributes) (credentialcomposer.X509SVIDAttributes, error) {
+func (cc fakeCC) ComposeAgentX509SVID(_ context.Context, _ spiffeid.ID, _ crypto.PublicKey, _ credentialcomposer.SVIDContext, attributes credentialcomposer.X509SVIDAttributes) (credentialcomposer.X509SVIDAttributes, error) {
 	return cc.overrideX509SVIDAttributes(attributes), nil
 }
 
-func (cc fakeCC) ComposeWorkloadX509SVID(_ context.Context, _ spiffeid.ID, _ crypto.PublicKey, attributes credentialcomposer.X509SVIDAttributes) (credentialcomposer.X509SVIDAttributes, error) {
+func (cc fakeCC) ComposeWorkloadX509SVID(_ context.Context, _ spiffeid.ID, _ crypto.PublicKey, _ credentialcomposer.SVIDContext, attributes credentialcomposer.X509SVIDAttributes) (credentialcomposer.X509SVIDAttributes, error) {
 	return cc.overrideX509SVIDAttributes(attributes), nil
 }
 
-func (cc fakeCC) ComposeWorkloadJWTSVID(_ context.Context, _ spiffeid.ID, attributes credentialcomposer.JWTSVIDAttributes) (credentialcomposer.JWTSVIDAttributes, error) {
+func (cc fakeCC) ComposeWorkloadJWTSVID(_ context.Context, _ spiffeid.ID, _ credentialcomposer.SVIDContext, attributes credentialcomposer.JWTSVIDAttributes) (credentialcomposer.JWTSVIDAttributes, error) {
 	attributes.Claims["foo"] = cc.applySuffix("VALUE")
 	if !cc.onlyFoo {
 		attributes.Claims["bar"] = cc.applySuffix("VALUE")
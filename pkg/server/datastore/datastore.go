@@ -28,6 +28,8 @@ type DataStore interface {
 	RevokeX509CA(ctx context.Context, trustDomainID string, subjectKeyIDToRevoke string) error
 	TaintJWTKey(ctx context.Context, trustDomainID string, authorityID string) (*common.PublicKey, error)
 	RevokeJWTKey(ctx context.Context, trustDomainID string, authorityID string) (*common.PublicKey, error)
+	TaintWITKey(ctx context.Context, trustDomainID string, authorityID string) (*common.PublicKey, error)
+	RevokeWITKey(ctx context.Context, trustDomainID string, authorityID string) (*common.PublicKey, error)
 
 	// Entries
 	CountRegistrationEntries(context.Context, *CountRegistrationEntriesRequest) (int32, error)
@@ -68,9 +70,11 @@ type DataStore interface {
 	SetNodeSelectors(ctx context.Context, spiffeID string, selectors []*common.Selector) error
 
 	// Tokens
+	ConsumeJoinToken(ctx context.Context, token string, now time.Time, checkUse func(*JoinToken) error) (*JoinToken, error)
 	CreateJoinToken(context.Context, *JoinToken) error
 	DeleteJoinToken(ctx context.Context, token string) error
 	FetchJoinToken(ctx context.Context, token string) (*JoinToken, error)
+	ListJoinTokens(context.Context, *ListJoinTokensRequest) (*ListJoinTokensResponse, error)
 	PruneJoinTokens(context.Context, time.Time) error
 
 	// Federation Relationships
@@ -79,10 +83,18 @@ type DataStore interface {
 	ListFederationRelationships(context.Context, *ListFederationRelationshipsRequest) (*ListFederationRelationshipsResponse, error)
 	DeleteFederationRelationship(context.Context, spiffeid.TrustDomain) error
 	UpdateFederationRelationship(context.Context, *FederationRelationship, *types.FederationRelationshipMask) (*FederationRelationship, error)
+	SetFederationRelationshipOptions(context.Context, spiffeid.TrustDomain, *common.FederationRelationshipOptions) (*FederationRelationship, error)
+
+	// Quarantined bundles
+	SetQuarantinedBundle(context.Context, *common.QuarantinedBundle) (*common.QuarantinedBundle, error)
+	FetchQuarantinedBundle(ctx context.Context, trustDomainID string) (*common.QuarantinedBundle, error)
+	DeleteQuarantinedBundle(ctx context.Context, trustDomainID string) error
+	ReleaseQuarantinedBundle(context.Context, *common.Bundle) (*common.Bundle, error)
 
 	// CA Journals
 	SetCAJournal(ctx context.Context, caJournal *CAJournal) (*CAJournal, error)
 	FetchCAJournal(ctx context.Context, activeX509AuthorityID string) (*CAJournal, error)
+	ListCAJournals(context.Context, *ListCAJournalsRequest) (*ListCAJournalsResponse, error)
 	PruneCAJournals(ctx context.Context, allCAsExpireBefore int64) error
 }
 
@@ -158,9 +170,51 @@ type BySelectors struct {
 	Match     MatchBehavior
 }
 
+// EntryScope matches the registration entries that match every field set on
+// it. Admin and downstream entries never match a scope, since their SVIDs
+// grant rights beyond it.
+type EntryScope struct {
+	// SPIFFEIDPrefix matches entries whose SPIFFE ID is equal to the prefix
+	// or a descendant of it.
+	SPIFFEIDPrefix string
+	// ParentID matches entries with the given parent ID.
+	ParentID string
+}
+
 type JoinToken struct {
 	Token  string
 	Expiry time.Time
+
+	// MaxUses is the number of agents that can be attested with the token.
+	// Zero (and one) means the token can only be used once, while a
+	// negative value means it can be used until it expires.
+	MaxUses int32
+
+	// UseCount is the number of agents attested with the token so far.
+	UseCount int32
+
+	// Selectors are added to the node selectors of the agents attested with
+	// the token.
+	Selectors []*common.Selector
+
+	// AgentPathTemplate, if set, is used to build the path of the SPIFFE ID
+	// of the agents attested with the token.
+	AgentPathTemplate string
+}
+
+// UnlimitedJoinTokenUses is the MaxUses value of the join tokens that can
+// be used any number of times until they expire.
+const UnlimitedJoinTokenUses = -1
+
+// IsMultiUse returns true if the token can be used to attest more than one
+// agent.
+func (t *JoinToken) IsMultiUse() bool {
+	return t.MaxUses < 0 || t.MaxUses > 1
+}
+
+// IsExhausted returns true if the token cannot be used anymore.
+func (t *JoinToken) IsExhausted() bool {
+	return t.MaxUses >= 0 && t.UseCount >= max(t.MaxUses, 1)
 }
 
 type Pagination struct {
@@ -172,12 +226,17 @@ type ListAttestedNodesRequest struct {
 	ByAttestationType string
 	ByBanned          *bool
 	ByExpiresBefore   time.Time
+	ByLastSeenBefore  time.Time
+	ByLastSeenAfter   time.Time
 	BySelectorMatch   *BySelectors
 	BySpiffeIDs       []string
 	FetchSelectors    bool
 	Pagination        *Pagination
 	ByCanReattest     *bool
 	ValidAt           time.Time
+	// BySpiffeIDPrefixes matches the nodes whose SPIFFE ID is equal to or
+	// a descendant of one of the prefixes.
+	BySpiffeIDPrefixes []string
 }
 
 type ListAttestedNodesResponse struct {
@@ -227,6 +286,8 @@ type ListRegistrationEntriesRequest struct {
 	ByFederatesWith *ByFederatesWith
 	ByHint          string
 	ByDownstream    *bool
+	// ByScopes matches the entries within at least one of the scopes.
+	ByScopes []EntryScope
 }
 
 type CAJournal struct {
@@ -235,6 +296,24 @@ type CAJournal struct {
 	ActiveX509AuthorityID string
 }
 
+type ListCAJournalsRequest struct {
+	Pagination *Pagination
+}
+
+type ListCAJournalsResponse struct {
+	CAJournals []*CAJournal
+	Pagination *Pagination
+}
+
+type ListJoinTokensRequest struct {
+	Pagination *Pagination
+}
+
+type ListJoinTokensResponse struct {
+	JoinTokens []*JoinToken
+	Pagination *Pagination
+}
+
 type ListRegistrationEntriesResponse struct {
 	Entries    []*common.RegistrationEntry
 	Pagination *Pagination
@@ -268,9 +347,14 @@ type CountAttestedNodesRequest struct {
 	ByAttestationType string
 	ByBanned          *bool
 	ByExpiresBefore   time.Time
+	ByLastSeenBefore  time.Time
+	ByLastSeenAfter   time.Time
 	BySelectorMatch   *BySelectors
 	FetchSelectors    bool
 	ByCanReattest     *bool
+	// BySpiffeIDPrefixes matches the nodes whose SPIFFE ID is equal to or
+	// a descendant of one of the prefixes.
+	BySpiffeIDPrefixes []string
 }
 
 type CountRegistrationEntriesRequest struct {
@@ -281,6 +365,8 @@ type CountRegistrationEntriesRequest struct {
 	ByFederatesWith *ByFederatesWith
 	ByHint          string
 	ByDownstream    *bool
+	// ByScopes matches the entries within at least one of the scopes.
+	ByScopes []EntryScope
 }
 
 type BundleEndpointType string
@@ -298,4 +384,10 @@ type FederationRelationship struct {
 
 	// Fields only used for 'https_spiffe' bundle endpoint profile
 	EndpointSPIFFEID spiffeid.ID
+
+	// Options are the settings of the relationship that the SPIRE API
+	// federation relationship type has no fields for. They are only set
+	// when the relationship is created and through
+	// SetFederationRelationshipOptions.
+	Options *common.FederationRelationshipOptions
 }
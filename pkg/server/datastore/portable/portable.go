@@ -0,0 +1,640 @@
+// Package portable implements a versioned, dialect-independent representation
+// of the SPIRE server datastore contents. It is used to move data between
+// datastore backends (e.g. from SQLite3 to PostgreSQL) or between deployments.
+//
+// All access goes through the datastore.DataStore interface, so the exported
+// document does not depend on the SQL dialect or schema version of either the
+// source or the target datastore.
+package portable
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/proto/spire/common"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// Version is the version of the document format produced by Export.
+	// Version 2 added the options and quarantined bundle of federation
+	// relationships. Documents of older versions can still be restored.
+	Version = 2
+
+	// minVersion is the oldest document version that can be restored.
+	minVersion = 1
+
+	// pageSize is the page size used when listing datastore records.
+	pageSize = 1000
+)
+
+// Document is the portable representation of the datastore contents.
+// Protobuf-backed records are encoded using the canonical protobuf JSON
+// mapping so they remain stable across SQL dialects.
+type Document struct {
+	Version                 int                      `json:"version"`
+	Bundles                 []json.RawMessage        `json:"bundles"`
+	FederationRelationships []FederationRelationship `json:"federation_relationships"`
+	RegistrationEntries     []json.RawMessage        `json:"registration_entries"`
+	AttestedNodes           []json.RawMessage        `json:"attested_nodes"`
+	JoinTokens              []JoinToken              `json:"join_tokens"`
+	CAJournals              []CAJournal              `json:"ca_journals"`
+}
+
+// FederationRelationship is the portable representation of a federation
+// relationship. The trust domain bundle is exported along with the rest of
+// the bundles. The options and the bundle quarantined by the bundle
+// safeguards, if any, are encoded using the protobuf JSON mapping.
+type FederationRelationship struct {
+	TrustDomain           string          `json:"trust_domain"`
+	BundleEndpointURL     string          `json:"bundle_endpoint_url"`
+	BundleEndpointProfile string          `json:"bundle_endpoint_profile"`
+	EndpointSPIFFEID      string          `json:"endpoint_spiffe_id,omitempty"`
+	Options               json.RawMessage `json:"options,omitempty"`
+	QuarantinedBundle     json.RawMessage `json:"quarantined_bundle,omitempty"`
+}
+
+// JoinToken is the portable representation of a join token.
+type JoinToken struct {
+	Token             string     `json:"token"`
+	Expiry            int64      `json:"expiry"`
+	MaxUses           int32      `json:"max_uses,omitempty"`
+	UseCount          int32      `json:"use_count,omitempty"`
+	Selectors         []Selector `json:"selectors,omitempty"`
+	AgentPathTemplate string     `json:"agent_path_template,omitempty"`
+}
+
+// Selector is the portable representation of a selector.
+type Selector struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// CAJournal is the portable representation of a CA journal.
+type CAJournal struct {
+	ActiveX509AuthorityID string `json:"active_x509_authority_id"`
+	Data                  []byte `json:"data"`
+}
+
+// Export reads the contents of the datastore and writes them to w as a
+// versioned JSON document.
+func Export(ctx context.Context, ds datastore.DataStore, w io.Writer) error {
+	doc, err := Collect(ctx, ds)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode document: %w", err)
+	}
+	return nil
+}
+
+// Import reads a document produced by Export from r and restores it into the
+// datastore. See Restore for the requirements on the datastore.
+func Import(ctx context.Context, ds datastore.DataStore, r io.Reader) error {
+	doc := new(Document)
+	if err := json.NewDecoder(r).Decode(doc); err != nil {
+		return fmt.Errorf("failed to decode document: %w", err)
+	}
+	return Restore(ctx, ds, doc)
+}
+
+// Collect reads the contents of the datastore into a document.
+func Collect(ctx context.Context, ds datastore.DataStore) (*Document, error) {
+	doc := &Document{
+		Version:                 Version,
+		Bundles:                 []json.RawMessage{},
+		FederationRelationships: []FederationRelationship{},
+		RegistrationEntries:     []json.RawMessage{},
+		AttestedNodes:           []json.RawMessage{},
+		JoinTokens:              []JoinToken{},
+		CAJournals:              []CAJournal{},
+	}
+
+	if err := collectBundles(ctx, ds, doc); err != nil {
+		return nil, err
+	}
+	if err := collectFederationRelationships(ctx, ds, doc); err != nil {
+		return nil, err
+	}
+	if err := collectRegistrationEntries(ctx, ds, doc); err != nil {
+		return nil, err
+	}
+	if err := collectAttestedNodes(ctx, ds, doc); err != nil {
+		return nil, err
+	}
+	if err := collectJoinTokens(ctx, ds, doc); err != nil {
+		return nil, err
+	}
+	if err := collectCAJournals(ctx, ds, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Restore writes the contents of the document into the datastore.
+//
+// The datastore API has no transaction spanning several calls, so the restore
+// is made safe to retry instead: the whole document is decoded and validated
+// before anything is written, and records already in the datastore are
+// skipped. The datastore must be empty, or only hold records of the same
+// document left by a restore that failed midway, which is then completed by
+// restoring the document again.
+//
+// Records are created in dependency order: bundles are restored before the
+// federation relationships and registration entries that reference them, and
+// attested nodes before their selectors.
+func Restore(ctx context.Context, ds datastore.DataStore, doc *Document) error {
+	if doc.Version < minVersion || doc.Version > Version {
+		return fmt.Errorf("unsupported document version %d; expected at most %d", doc.Version, Version)
+	}
+
+	restored, err := decodeDocument(doc)
+	if err != nil {
+		return err
+	}
+
+	existing, err := collectKeys(ctx, ds)
+	if err != nil {
+		return err
+	}
+	if err := checkRestorable(existing, restored.keys()); err != nil {
+		return err
+	}
+
+	for _, bundle := range restored.bundles {
+		if existing.bundles.has(bundle.TrustDomainId) {
+			continue
+		}
+		if _, err := ds.CreateBundle(ctx, bundle); err != nil {
+			return fmt.Errorf("failed to create bundle %q: %w", bundle.TrustDomainId, err)
+		}
+	}
+
+	for _, fr := range restored.federationRelationships {
+		if existing.federationRelationships.has(fr.TrustDomain.Name()) {
+			continue
+		}
+		if _, err := ds.CreateFederationRelationship(ctx, fr); err != nil {
+			return fmt.Errorf("failed to create federation relationship %q: %w", fr.TrustDomain, err)
+		}
+	}
+
+	// Setting a quarantined bundle replaces it, so quarantined bundles are
+	// set again for relationships created by a previous attempt.
+	for _, quarantined := range restored.quarantinedBundles {
+		if _, err := ds.SetQuarantinedBundle(ctx, quarantined); err != nil {
+			return fmt.Errorf("failed to set quarantined bundle %q: %w", quarantined.TrustDomainId, err)
+		}
+	}
+
+	for _, entry := range restored.entries {
+		if existing.entries.has(entry.EntryId) {
+			continue
+		}
+		if _, err := ds.CreateRegistrationEntry(ctx, entry); err != nil {
+			return fmt.Errorf("failed to create registration entry %q: %w", entry.EntryId, err)
+		}
+	}
+
+	for _, node := range restored.nodes {
+		if !existing.nodes.has(node.SpiffeId) {
+			if _, err := ds.CreateAttestedNode(ctx, node); err != nil {
+				return fmt.Errorf("failed to create attested node %q: %w", node.SpiffeId, err)
+			}
+		}
+		// Setting the selectors replaces them, so they are set again for
+		// nodes created by a previous attempt that failed before setting them.
+		if len(node.Selectors) > 0 {
+			if err := ds.SetNodeSelectors(ctx, node.SpiffeId, node.Selectors); err != nil {
+				return fmt.Errorf("failed to set selectors for attested node %q: %w", node.SpiffeId, err)
+			}
+		}
+	}
+
+	for _, joinToken := range restored.joinTokens {
+		if existing.joinTokens.has(joinToken.Token) {
+			continue
+		}
+		if err := ds.CreateJoinToken(ctx, joinToken); err != nil {
+			return fmt.Errorf("failed to create join token: %w", err)
+		}
+	}
+
+	for _, caJournal := range restored.caJournals {
+		if existing.caJournals.has(caJournal.ActiveX509AuthorityID) {
+			continue
+		}
+		if _, err := ds.SetCAJournal(ctx, caJournal); err != nil {
+			return fmt.Errorf("failed to create CA journal: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// records holds the decoded records of a document.
+type records struct {
+	bundles                 []*common.Bundle
+	federationRelationships []*datastore.FederationRelationship
+	quarantinedBundles      []*common.QuarantinedBundle
+	entries                 []*common.RegistrationEntry
+	nodes                   []*common.AttestedNode
+	joinTokens              []*datastore.JoinToken
+	caJournals              []*datastore.CAJournal
+}
+
+// decodeDocument decodes every record of the document, so a malformed
+// document is rejected before anything is written to the datastore.
+func decodeDocument(doc *Document) (*records, error) {
+	out := new(records)
+
+	for _, data := range doc.Bundles {
+		bundle := new(common.Bundle)
+		if err := protojson.Unmarshal(data, bundle); err != nil {
+			return nil, fmt.Errorf("failed to decode bundle: %w", err)
+		}
+		out.bundles = append(out.bundles, bundle)
+	}
+
+	for _, fr := range doc.FederationRelationships {
+		relationship, err := fr.toDatastore()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode federation relationship %q: %w", fr.TrustDomain, err)
+		}
+		out.federationRelationships = append(out.federationRelationships, relationship)
+
+		if fr.QuarantinedBundle != nil {
+			quarantined := new(common.QuarantinedBundle)
+			if err := protojson.Unmarshal(fr.QuarantinedBundle, quarantined); err != nil {
+				return nil, fmt.Errorf("failed to decode quarantined bundle of federation relationship %q: %w", fr.TrustDomain, err)
+			}
+			if quarantined.TrustDomainId != relationship.TrustDomain.IDString() {
+				return nil, fmt.Errorf("failed to decode quarantined bundle of federation relationship %q: trust domain ID %q does not match", fr.TrustDomain, quarantined.TrustDomainId)
+			}
+			out.quarantinedBundles = append(out.quarantinedBundles, quarantined)
+		}
+	}
+
+	for _, data := range doc.RegistrationEntries {
+		entry := new(common.RegistrationEntry)
+		if err := protojson.Unmarshal(data, entry); err != nil {
+			return nil, fmt.Errorf("failed to decode registration entry: %w", err)
+		}
+		if entry.EntryId == "" {
+			return nil, errors.New("failed to decode registration entry: entry ID is required")
+		}
+		out.entries = append(out.entries, entry)
+	}
+
+	for _, data := range doc.AttestedNodes {
+		node := new(common.AttestedNode)
+		if err := protojson.Unmarshal(data, node); err != nil {
+			return nil, fmt.Errorf("failed to decode attested node: %w", err)
+		}
+		out.nodes = append(out.nodes, node)
+	}
+
+	for _, joinToken := range doc.JoinTokens {
+		var selectors []*common.Selector
+		for _, selector := range joinToken.Selectors {
+			selectors = append(selectors, &common.Selector{Type: selector.Type, Value: selector.Value})
+		}
+		out.joinTokens = append(out.joinTokens, &datastore.JoinToken{
+			Token:             joinToken.Token,
+			Expiry:            time.Unix(joinToken.Expiry, 0),
+			MaxUses:           joinToken.MaxUses,
+			UseCount:          joinToken.UseCount,
+			Selectors:         selectors,
+			AgentPathTemplate: joinToken.AgentPathTemplate,
+		})
+	}
+
+	for _, caJournal := range doc.CAJournals {
+		out.caJournals = append(out.caJournals, &datastore.CAJournal{
+			ActiveX509AuthorityID: caJournal.ActiveX509AuthorityID,
+			Data:                  caJournal.Data,
+		})
+	}
+
+	return out, nil
+}
+
+func (r *records) keys() *recordKeys {
+	keys := newRecordKeys()
+	for _, bundle := range r.bundles {
+		keys.bundles.add(bundle.TrustDomainId)
+	}
+	for _, fr := range r.federationRelationships {
+		keys.federationRelationships.add(fr.TrustDomain.Name())
+	}
+	for _, entry := range r.entries {
+		keys.entries.add(entry.EntryId)
+	}
+	for _, node := range r.nodes {
+		keys.nodes.add(node.SpiffeId)
+	}
+	for _, joinToken := range r.joinTokens {
+		keys.joinTokens.add(joinToken.Token)
+	}
+	for _, caJournal := range r.caJournals {
+		keys.caJournals.add(caJournal.ActiveX509AuthorityID)
+	}
+	return keys
+}
+
+// recordKeys holds the keys identifying records of each type: the trust
+// domain ID of bundles, the trust domain name of federation relationships,
+// the ID of registration entries, the SPIFFE ID of attested nodes, the value
+// of join tokens and the active X.509 authority ID of CA journals.
+type recordKeys struct {
+	bundles                 keySet
+	federationRelationships keySet
+	entries                 keySet
+	nodes                   keySet
+	joinTokens              keySet
+	caJournals              keySet
+}
+
+func newRecordKeys() *recordKeys {
+	return &recordKeys{
+		bundles:                 make(keySet),
+		federationRelationships: make(keySet),
+		entries:                 make(keySet),
+		nodes:                   make(keySet),
+		joinTokens:              make(keySet),
+		caJournals:              make(keySet),
+	}
+}
+
+type keySet map[string]struct{}
+
+func (s keySet) add(key string) {
+	s[key] = struct{}{}
+}
+
+func (s keySet) has(key string) bool {
+	_, ok := s[key]
+	return ok
+}
+
+// collectKeys reads the keys of the records held by the datastore.
+func collectKeys(ctx context.Context, ds datastore.DataStore) (*recordKeys, error) {
+	doc, err := Collect(ctx, ds)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := decodeDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	return existing.keys(), nil
+}
+
+// checkRestorable returns an error if the datastore holds records that are
+// not in the document being restored. Restoring into a datastore populated
+// by something else could silently merge unrelated deployments.
+func checkRestorable(existing, restored *recordKeys) error {
+	for _, check := range []struct {
+		kind     string
+		existing keySet
+		restored keySet
+	}{
+		{kind: "bundles", existing: existing.bundles, restored: restored.bundles},
+		{kind: "federation relationships", existing: existing.federationRelationships, restored: restored.federationRelationships},
+		{kind: "registration entries", existing: existing.entries, restored: restored.entries},
+		{kind: "attested nodes", existing: existing.nodes, restored: restored.nodes},
+		{kind: "join tokens", existing: existing.joinTokens, restored: restored.joinTokens},
+		{kind: "CA journals", existing: existing.caJournals, restored: restored.caJournals},
+	} {
+		for key := range check.existing {
+			if !check.restored.has(key) {
+				return fmt.Errorf("target datastore is not empty: it holds %s that are not in the document", check.kind)
+			}
+		}
+	}
+	return nil
+}
+
+func collectBundles(ctx context.Context, ds datastore.DataStore, doc *Document) error {
+	req := &datastore.ListBundlesRequest{
+		Pagination: &datastore.Pagination{PageSize: pageSize},
+	}
+	for {
+		resp, err := ds.ListBundles(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to list bundles: %w", err)
+		}
+		for _, bundle := range resp.Bundles {
+			data, err := protojson.Marshal(bundle)
+			if err != nil {
+				return fmt.Errorf("failed to encode bundle %q: %w", bundle.TrustDomainId, err)
+			}
+			doc.Bundles = append(doc.Bundles, data)
+		}
+		if !nextPage(&req.Pagination, resp.Pagination, len(resp.Bundles)) {
+			return nil
+		}
+	}
+}
+
+func collectFederationRelationships(ctx context.Context, ds datastore.DataStore, doc *Document) error {
+	req := &datastore.ListFederationRelationshipsRequest{
+		Pagination: &datastore.Pagination{PageSize: pageSize},
+	}
+	for {
+		resp, err := ds.ListFederationRelationships(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to list federation relationships: %w", err)
+		}
+		for _, fr := range resp.FederationRelationships {
+			relationship, err := federationRelationshipFromDatastore(fr)
+			if err != nil {
+				return fmt.Errorf("failed to encode federation relationship %q: %w", fr.TrustDomain, err)
+			}
+
+			quarantined, err := ds.FetchQuarantinedBundle(ctx, fr.TrustDomain.IDString())
+			if err != nil {
+				return fmt.Errorf("failed to fetch quarantined bundle %q: %w", fr.TrustDomain, err)
+			}
+			if quarantined != nil {
+				relationship.QuarantinedBundle, err = protojson.Marshal(quarantined)
+				if err != nil {
+					return fmt.Errorf("failed to encode quarantined bundle %q: %w", fr.TrustDomain, err)
+				}
+			}
+
+			doc.FederationRelationships = append(doc.FederationRelationships, relationship)
+		}
+		if !nextPage(&req.Pagination, resp.Pagination, len(resp.FederationRelationships)) {
+			return nil
+		}
+	}
+}
+
+func collectRegistrationEntries(ctx context.Context, ds datastore.DataStore, doc *Document) error {
+	req := &datastore.ListRegistrationEntriesRequest{
+		DataConsistency: datastore.RequireCurrent,
+		Pagination:      &datastore.Pagination{PageSize: pageSize},
+	}
+	for {
+		resp, err := ds.ListRegistrationEntries(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to list registration entries: %w", err)
+		}
+		for _, entry := range resp.Entries {
+			data, err := protojson.Marshal(entry)
+			if err != nil {
+				return fmt.Errorf("failed to encode registration entry %q: %w", entry.EntryId, err)
+			}
+			doc.RegistrationEntries = append(doc.RegistrationEntries, data)
+		}
+		if !nextPage(&req.Pagination, resp.Pagination, len(resp.Entries)) {
+			return nil
+		}
+	}
+}
+
+func collectAttestedNodes(ctx context.Context, ds datastore.DataStore, doc *Document) error {
+	req := &datastore.ListAttestedNodesRequest{
+		FetchSelectors: true,
+		Pagination:     &datastore.Pagination{PageSize: pageSize},
+	}
+	for {
+		resp, err := ds.ListAttestedNodes(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to list attested nodes: %w", err)
+		}
+		for _, node := range resp.Nodes {
+			data, err := protojson.Marshal(node)
+			if err != nil {
+				return fmt.Errorf("failed to encode attested node %q: %w", node.SpiffeId, err)
+			}
+			doc.AttestedNodes = append(doc.AttestedNodes, data)
+		}
+		if !nextPage(&req.Pagination, resp.Pagination, len(resp.Nodes)) {
+			return nil
+		}
+	}
+}
+
+func collectJoinTokens(ctx context.Context, ds datastore.DataStore, doc *Document) error {
+	req := &datastore.ListJoinTokensRequest{
+		Pagination: &datastore.Pagination{PageSize: pageSize},
+	}
+	for {
+		resp, err := ds.ListJoinTokens(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to list join tokens: %w", err)
+		}
+		for _, joinToken := range resp.JoinTokens {
+			var selectors []Selector
+			for _, selector := range joinToken.Selectors {
+				selectors = append(selectors, Selector{Type: selector.Type, Value: selector.Value})
+			}
+			doc.JoinTokens = append(doc.JoinTokens, JoinToken{
+				Token:             joinToken.Token,
+				Expiry:            joinToken.Expiry.Unix(),
+				MaxUses:           joinToken.MaxUses,
+				UseCount:          joinToken.UseCount,
+				Selectors:         selectors,
+				AgentPathTemplate: joinToken.AgentPathTemplate,
+			})
+		}
+		if !nextPage(&req.Pagination, resp.Pagination, len(resp.JoinTokens)) {
+			return nil
+		}
+	}
+}
+
+func collectCAJournals(ctx context.Context, ds datastore.DataStore, doc *Document) error {
+	req := &datastore.ListCAJournalsRequest{
+		Pagination: &datastore.Pagination{PageSize: pageSize},
+	}
+	for {
+		resp, err := ds.ListCAJournals(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to list CA journals: %w", err)
+		}
+		for _, caJournal := range resp.CAJournals {
+			doc.CAJournals = append(doc.CAJournals, CAJournal{
+				ActiveX509AuthorityID: caJournal.ActiveX509AuthorityID,
+				Data:                  caJournal.Data,
+			})
+		}
+		if !nextPage(&req.Pagination, resp.Pagination, len(resp.CAJournals)) {
+			return nil
+		}
+	}
+}
+
+// nextPage advances the request pagination using the response pagination.
+// It returns false when there are no more pages to fetch.
+func nextPage(reqPagination **datastore.Pagination, respPagination *datastore.Pagination, count int) bool {
+	if count == 0 || respPagination == nil || respPagination.Token == "" {
+		return false
+	}
+	*reqPagination = respPagination
+	return true
+}
+
+func federationRelationshipFromDatastore(fr *datastore.FederationRelationship) (FederationRelationship, error) {
+	out := FederationRelationship{
+		TrustDomain:           fr.TrustDomain.Name(),
+		BundleEndpointProfile: string(fr.BundleEndpointProfile),
+	}
+	if fr.BundleEndpointURL != nil {
+		out.BundleEndpointURL = fr.BundleEndpointURL.String()
+	}
+	if !fr.EndpointSPIFFEID.IsZero() {
+		out.EndpointSPIFFEID = fr.EndpointSPIFFEID.String()
+	}
+	if fr.Options != nil {
+		options, err := protojson.Marshal(fr.Options)
+		if err != nil {
+			return FederationRelationship{}, fmt.Errorf("failed to encode options: %w", err)
+		}
+		out.Options = options
+	}
+	return out, nil
+}
+
+func (fr FederationRelationship) toDatastore() (*datastore.FederationRelationship, error) {
+	trustDomain, err := spiffeid.TrustDomainFromString(fr.TrustDomain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trust domain: %w", err)
+	}
+	bundleEndpointURL, err := url.Parse(fr.BundleEndpointURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle endpoint URL: %w", err)
+	}
+
+	out := &datastore.FederationRelationship{
+		TrustDomain:           trustDomain,
+		BundleEndpointURL:     bundleEndpointURL,
+		BundleEndpointProfile: datastore.BundleEndpointType(fr.BundleEndpointProfile),
+	}
+	if fr.EndpointSPIFFEID != "" {
+		out.EndpointSPIFFEID, err = spiffeid.FromString(fr.EndpointSPIFFEID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint SPIFFE ID: %w", err)
+		}
+	}
+	if fr.Options != nil {
+		out.Options = new(common.FederationRelationshipOptions)
+		if err := protojson.Unmarshal(fr.Options, out.Options); err != nil {
+			return nil, fmt.Errorf("invalid options: %w", err)
+		}
+	}
+	return out, nil
+}
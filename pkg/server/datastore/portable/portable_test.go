@@ -0,0 +1,375 @@
+package portable_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/common/bundleutil"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/pkg/server/datastore/portable"
+	"github.com/spiffe/spire/pkg/server/datastore/sqlstore"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/spiffe/spire/test/testca"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var (
+	ctx = context.Background()
+
+	td          = spiffeid.RequireTrustDomainFromString("example.org")
+	federatedTD = spiffeid.RequireTrustDomainFromString("federated.test")
+
+	federationOptions = &common.FederationRelationshipOptions{
+		BundleSafeguards: &common.BundleSafeguards{
+			RequireOverlap: true,
+			MaxKeys:        4,
+		},
+		BundleEndpointHub: true,
+	}
+)
+
+func TestExportImport(t *testing.T) {
+	source := newDataStore(t)
+	populate(t, source)
+
+	exported := new(bytes.Buffer)
+	require.NoError(t, portable.Export(ctx, source, exported))
+
+	// Import into a fresh schema created by the sqlstore migrations
+	target := newDataStore(t)
+	require.NoError(t, portable.Import(ctx, target, bytes.NewReader(exported.Bytes())))
+
+	expected, err := portable.Collect(ctx, source)
+	require.NoError(t, err)
+	actual, err := portable.Collect(ctx, target)
+	require.NoError(t, err)
+
+	require.Equal(t, portable.Version, actual.Version)
+	require.Len(t, actual.Bundles, 2)
+	require.Len(t, actual.FederationRelationships, 1)
+	require.Len(t, actual.RegistrationEntries, 3)
+	require.Len(t, actual.AttestedNodes, 2)
+	require.Len(t, actual.JoinTokens, 2)
+	require.Len(t, actual.CAJournals, 1)
+
+	require.Equal(t, decodeBundles(t, expected), decodeBundles(t, actual))
+	require.Equal(t, expected.FederationRelationships, actual.FederationRelationships)
+	require.Equal(t, decodeEntries(t, expected), decodeEntries(t, actual))
+	require.Equal(t, decodeNodes(t, expected), decodeNodes(t, actual))
+	require.Equal(t, expected.JoinTokens, actual.JoinTokens)
+	require.Equal(t, expected.CAJournals, actual.CAJournals)
+
+	// Selectors must have been restored along with the node
+	selectors, err := target.GetNodeSelectors(ctx, "spiffe://example.org/spire/agent/a", datastore.RequireCurrent)
+	require.NoError(t, err)
+	require.Len(t, selectors, 2)
+
+	// The options and quarantined bundle must have been restored along with
+	// the federation relationship
+	fr, err := target.FetchFederationRelationship(ctx, federatedTD)
+	require.NoError(t, err)
+	spiretest.RequireProtoEqual(t, federationOptions, fr.Options)
+
+	quarantined, err := target.FetchQuarantinedBundle(ctx, federatedTD.IDString())
+	require.NoError(t, err)
+	expectedQuarantined, err := source.FetchQuarantinedBundle(ctx, federatedTD.IDString())
+	require.NoError(t, err)
+	require.NotNil(t, quarantined)
+	spiretest.RequireProtoEqual(t, expectedQuarantined, quarantined)
+}
+
+func TestImportVersion1(t *testing.T) {
+	source := newDataStore(t)
+	populate(t, source)
+
+	// Version 1 documents have no federation relationship options nor
+	// quarantined bundles
+	doc, err := portable.Collect(ctx, source)
+	require.NoError(t, err)
+	doc.Version = 1
+	for i := range doc.FederationRelationships {
+		doc.FederationRelationships[i].Options = nil
+		doc.FederationRelationships[i].QuarantinedBundle = nil
+	}
+
+	target := newDataStore(t)
+	require.NoError(t, portable.Restore(ctx, target, doc))
+
+	fr, err := target.FetchFederationRelationship(ctx, federatedTD)
+	require.NoError(t, err)
+	require.Nil(t, fr.Options)
+
+	quarantined, err := target.FetchQuarantinedBundle(ctx, federatedTD.IDString())
+	require.NoError(t, err)
+	require.Nil(t, quarantined)
+}
+
+func TestExportEmpty(t *testing.T) {
+	exported := new(bytes.Buffer)
+	require.NoError(t, portable.Export(ctx, newDataStore(t), exported))
+
+	doc := new(portable.Document)
+	require.NoError(t, json.Unmarshal(exported.Bytes(), doc))
+	require.Equal(t, &portable.Document{
+		Version:                 portable.Version,
+		Bundles:                 []json.RawMessage{},
+		FederationRelationships: []portable.FederationRelationship{},
+		RegistrationEntries:     []json.RawMessage{},
+		AttestedNodes:           []json.RawMessage{},
+		JoinTokens:              []portable.JoinToken{},
+		CAJournals:              []portable.CAJournal{},
+	}, doc)
+}
+
+func TestImportFailsIfTargetIsNotEmpty(t *testing.T) {
+	source := newDataStore(t)
+	populate(t, source)
+
+	exported := new(bytes.Buffer)
+	require.NoError(t, portable.Export(ctx, source, exported))
+
+	target := newDataStore(t)
+	require.NoError(t, target.CreateJoinToken(ctx, &datastore.JoinToken{
+		Token:  "existing",
+		Expiry: time.Now().Add(time.Hour),
+	}))
+
+	err := portable.Import(ctx, target, exported)
+	require.EqualError(t, err, "target datastore is not empty: it holds join tokens that are not in the document")
+
+	// Nothing was restored
+	doc, err := portable.Collect(ctx, target)
+	require.NoError(t, err)
+	require.Empty(t, doc.Bundles)
+}
+
+func TestImportResumesAfterFailure(t *testing.T) {
+	source := newDataStore(t)
+	populate(t, source)
+
+	exported := new(bytes.Buffer)
+	require.NoError(t, portable.Export(ctx, source, exported))
+
+	// Fail midway, after the bundles, the federation relationship and the
+	// first registration entry were restored.
+	target := newDataStore(t)
+	failing := &failingDataStore{DataStore: target, failEntryCreation: 2}
+	err := portable.Import(ctx, failing, bytes.NewReader(exported.Bytes()))
+	require.EqualError(t, err, `failed to create registration entry "entry-1": oh no`)
+
+	partial, err := portable.Collect(ctx, target)
+	require.NoError(t, err)
+	require.Len(t, partial.Bundles, 2)
+	require.Len(t, partial.FederationRelationships, 1)
+	require.Len(t, partial.RegistrationEntries, 1)
+	require.Empty(t, partial.AttestedNodes)
+
+	// Importing the same document again completes the restore
+	require.NoError(t, portable.Import(ctx, target, bytes.NewReader(exported.Bytes())))
+
+	expected, err := portable.Collect(ctx, source)
+	require.NoError(t, err)
+	actual, err := portable.Collect(ctx, target)
+	require.NoError(t, err)
+	require.Equal(t, decodeBundles(t, expected), decodeBundles(t, actual))
+	require.Equal(t, expected.FederationRelationships, actual.FederationRelationships)
+	require.Equal(t, decodeEntries(t, expected), decodeEntries(t, actual))
+	require.Equal(t, decodeNodes(t, expected), decodeNodes(t, actual))
+	require.Equal(t, expected.JoinTokens, actual.JoinTokens)
+	require.Equal(t, expected.CAJournals, actual.CAJournals)
+}
+
+func TestImportValidatesDocumentBeforeWriting(t *testing.T) {
+	source := newDataStore(t)
+	populate(t, source)
+
+	doc, err := portable.Collect(ctx, source)
+	require.NoError(t, err)
+	doc.AttestedNodes = append(doc.AttestedNodes, json.RawMessage(`{"spiffe_id": 1}`))
+
+	target := newDataStore(t)
+	err = portable.Restore(ctx, target, doc)
+	require.ErrorContains(t, err, "failed to decode attested node")
+
+	// Nothing was restored
+	actual, err := portable.Collect(ctx, target)
+	require.NoError(t, err)
+	require.Empty(t, actual.Bundles)
+	require.Empty(t, actual.RegistrationEntries)
+
+	// Quarantined bundles must belong to their federation relationship
+	doc, err = portable.Collect(ctx, source)
+	require.NoError(t, err)
+	doc.FederationRelationships[0].QuarantinedBundle = json.RawMessage(`{"trust_domain_id": "spiffe://other.test"}`)
+	err = portable.Restore(ctx, target, doc)
+	require.EqualError(t, err, `failed to decode quarantined bundle of federation relationship "federated.test": trust domain ID "spiffe://other.test" does not match`)
+
+	actual, err = portable.Collect(ctx, target)
+	require.NoError(t, err)
+	require.Empty(t, actual.Bundles)
+	require.Empty(t, actual.FederationRelationships)
+}
+
+func TestImportFailsOnUnsupportedVersion(t *testing.T) {
+	err := portable.Import(ctx, newDataStore(t), bytes.NewBufferString(`{"version": 3}`))
+	require.EqualError(t, err, "unsupported document version 3; expected at most 2")
+}
+
+func TestImportFailsOnMalformedDocument(t *testing.T) {
+	err := portable.Import(ctx, newDataStore(t), bytes.NewBufferString(`{`))
+	require.ErrorContains(t, err, "failed to decode document")
+}
+
+func newDataStore(t *testing.T) *sqlstore.Plugin {
+	log, _ := test.NewNullLogger()
+	ds := sqlstore.New(log)
+	t.Cleanup(func() {
+		ds.Close()
+	})
+
+	dbPath := filepath.ToSlash(filepath.Join(t.TempDir(), "db.sqlite3"))
+	err := ds.Configure(ctx, fmt.Sprintf(`
+		database_type = "sqlite3"
+		connection_string = "%s"
+	`, dbPath))
+	require.NoError(t, err)
+	return ds
+}
+
+func populate(t *testing.T, ds datastore.DataStore) {
+	ca := testca.New(t, td)
+	federatedCA := testca.New(t, federatedTD)
+
+	_, err := ds.CreateBundle(ctx, bundleutil.BundleProtoFromRootCA(td.IDString(), ca.X509Authorities()[0]))
+	require.NoError(t, err)
+	_, err = ds.CreateBundle(ctx, bundleutil.BundleProtoFromRootCA(federatedTD.IDString(), federatedCA.X509Authorities()[0]))
+	require.NoError(t, err)
+
+	_, err = ds.CreateFederationRelationship(ctx, &datastore.FederationRelationship{
+		TrustDomain:           federatedTD,
+		BundleEndpointURL:     &url.URL{Scheme: "https", Host: "federated.test", Path: "/bundle"},
+		BundleEndpointProfile: datastore.BundleEndpointSPIFFE,
+		EndpointSPIFFEID:      spiffeid.RequireFromPath(federatedTD, "/bundle-server"),
+		Options:               federationOptions,
+	})
+	require.NoError(t, err)
+	_, err = ds.SetQuarantinedBundle(ctx, &common.QuarantinedBundle{
+		TrustDomainId: federatedTD.IDString(),
+		Bundle:        bundleutil.BundleProtoFromRootCA(federatedTD.IDString(), testca.New(t, federatedTD).X509Authorities()[0]),
+		QuarantinedAt: time.Now().Unix(),
+		Violations:    []string{"no key overlaps with the current bundle"},
+	})
+	require.NoError(t, err)
+
+	for i := range 3 {
+		_, err := ds.CreateRegistrationEntry(ctx, &common.RegistrationEntry{
+			EntryId:       fmt.Sprintf("entry-%d", i),
+			ParentId:      "spiffe://example.org/spire/agent/a",
+			SpiffeId:      fmt.Sprintf("spiffe://example.org/workload-%d", i),
+			Selectors:     []*common.Selector{{Type: "unix", Value: fmt.Sprintf("uid:%d", i)}},
+			FederatesWith: []string{federatedTD.IDString()},
+			DnsNames:      []string{fmt.Sprintf("workload-%d.example.org", i)},
+			X509SvidTtl:   3600,
+			JwtSvidTtl:    300,
+			Hint:          "external",
+		})
+		require.NoError(t, err)
+	}
+
+	_, err = ds.CreateAttestedNode(ctx, &common.AttestedNode{
+		SpiffeId:            "spiffe://example.org/spire/agent/a",
+		AttestationDataType: "join_token",
+		CertSerialNumber:    "1234",
+		CertNotAfter:        time.Now().Add(time.Hour).Unix(),
+		CanReattest:         true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, ds.SetNodeSelectors(ctx, "spiffe://example.org/spire/agent/a", []*common.Selector{
+		{Type: "a", Value: "1"},
+		{Type: "b", Value: "2"},
+	}))
+
+	// Banned node
+	_, err = ds.CreateAttestedNode(ctx, &common.AttestedNode{
+		SpiffeId:            "spiffe://example.org/spire/agent/b",
+		AttestationDataType: "x509pop",
+		CertNotAfter:        time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, ds.CreateJoinToken(ctx, &datastore.JoinToken{Token: "token-a", Expiry: time.Now().Add(time.Hour)}))
+	require.NoError(t, ds.CreateJoinToken(ctx, &datastore.JoinToken{
+		Token:             "token-b",
+		Expiry:            time.Now().Add(2 * time.Hour),
+		MaxUses:           5,
+		UseCount:          2,
+		Selectors:         []*common.Selector{{Type: "ci", Value: "runner"}},
+		AgentPathTemplate: "/join_token/ci/{{ .UseNumber }}",
+	}))
+
+	_, err = ds.SetCAJournal(ctx, &datastore.CAJournal{
+		ActiveX509AuthorityID: "authority-id",
+		Data:                  []byte("journal-data"),
+	})
+	require.NoError(t, err)
+}
+
+func decodeBundles(t *testing.T, doc *portable.Document) []string {
+	var out []string
+	for _, data := range doc.Bundles {
+		bundle := new(common.Bundle)
+		require.NoError(t, protojson.Unmarshal(data, bundle))
+		out = append(out, protojson.Format(bundle))
+	}
+	return out
+}
+
+func decodeEntries(t *testing.T, doc *portable.Document) []string {
+	var out []string
+	for _, data := range doc.RegistrationEntries {
+		entry := new(common.RegistrationEntry)
+		require.NoError(t, protojson.Unmarshal(data, entry))
+		// Creation time and revision are assigned by the target datastore
+		entry.CreatedAt = 0
+		entry.RevisionNumber = 0
+		out = append(out, protojson.Format(entry))
+	}
+	return out
+}
+
+func decodeNodes(t *testing.T, doc *portable.Document) []string {
+	var out []string
+	for _, data := range doc.AttestedNodes {
+		node := new(common.AttestedNode)
+		require.NoError(t, protojson.Unmarshal(data, node))
+		out = append(out, protojson.Format(node))
+	}
+	return out
+}
+
+// failingDataStore fails the nth registration entry creation.
+type failingDataStore struct {
+	datastore.DataStore
+
+	failEntryCreation int
+	entryCreations    int
+}
+
+func (ds *failingDataStore) CreateRegistrationEntry(ctx context.Context, entry *common.RegistrationEntry) (*common.RegistrationEntry, error) {
+	ds.entryCreations++
+	if ds.entryCreations == ds.failEntryCreation {
+		return nil, errors.New("oh no")
+	}
+	return ds.DataStore.CreateRegistrationEntry(ctx, entry)
+}
@@ -286,7 +286,7 @@ import (
 
 const (
 	// the latest schema version of the database in the code
-	latestSchemaVersion = 25
+	latestSchemaVersion = 29
 
 	// lastMinorReleaseSchemaVersion is the schema version supported by the
 	// last minor release. When the migrations are opportunistically pruned
@@ -441,11 +441,13 @@ func initDB(db *gorm.DB, dbType string, log logrus.FieldLogger) (err error) {
 		&RegisteredEntry{},
 		&RegisteredEntryEvent{},
 		&JoinToken{},
+		&JoinTokenSelector{},
 		&Selector{},
 		&Migration{},
 		&DNSName{},
 		&FederatedTrustDomain{},
 		CAJournal{},
+		&QuarantinedBundle{},
 	}
 
 	if err := tableOptionsForDialect(tx, dbType).AutoMigrate(tables...).Error; err != nil {
@@ -521,6 +523,14 @@ func migrateVersion(tx *gorm.DB, currVersion int, log logrus.FieldLogger) (versi
 		err = migrateToV24(tx)
 	case 24:
 		err = migrateToV25(tx)
+	case 25:
+		err = migrateToV26(tx)
+	case 26:
+		err = migrateToV27(tx)
+	case 27:
+		err = migrateToV28(tx)
+	case 28:
+		err = migrateToV29(tx)
 	default:
 		err = sqlcommon.NewSQLError("no migration support for unknown schema version %d", currVersion)
 	}
@@ -547,6 +557,44 @@ func migrateToV25(tx *gorm.DB) error {
 	return nil
 }
 
+func migrateToV26(tx *gorm.DB) error {
+	// Add max_uses, use_count and agent_path_template columns to join_tokens
+	// table and the join_token_selectors table
+	if err := tx.AutoMigrate(&JoinToken{}, &JoinTokenSelector{}).Error; err != nil {
+		return sqlcommon.NewWrappedSQLError(err)
+	}
+	// Existing tokens are single-use tokens that have not been used
+	if err := tx.Exec("UPDATE join_tokens SET max_uses = 0, use_count = 0").Error; err != nil {
+		return sqlcommon.NewWrappedSQLError(err)
+	}
+	return nil
+}
+
+func migrateToV27(tx *gorm.DB) error {
+	// Add last_seen_at and status_report columns to attested_node_entries
+	// table
+	if err := tx.AutoMigrate(&AttestedNode{}).Error; err != nil {
+		return sqlcommon.NewWrappedSQLError(err)
+	}
+	return nil
+}
+
+func migrateToV28(tx *gorm.DB) error {
+	// Add quarantined_bundles table
+	if err := tx.AutoMigrate(&QuarantinedBundle{}).Error; err != nil {
+		return sqlcommon.NewWrappedSQLError(err)
+	}
+	return nil
+}
+
+func migrateToV29(tx *gorm.DB) error {
+	// Add options column to federated_trust_domains table
+	if err := tx.AutoMigrate(&FederatedTrustDomain{}).Error; err != nil {
+		return sqlcommon.NewWrappedSQLError(err)
+	}
+	return nil
+}
+
 func addFederatedRegistrationEntriesRegisteredEntryIDIndex(tx *gorm.DB) error {
 	// GORM creates the federated_registration_entries implicitly with a primary
 	// key tuple (bundle_id, registered_entry_id). Unfortunately, MySQL5 does
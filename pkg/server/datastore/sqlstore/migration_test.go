@@ -98,6 +98,180 @@ var (
             CREATE INDEX idx_federated_registration_entries_registered_entry_id ON "federated_registration_entries"(registered_entry_id) ;
             COMMIT;
 		    `,
+		25: `
+            PRAGMA foreign_keys=OFF;
+            BEGIN TRANSACTION;
+            CREATE TABLE IF NOT EXISTS "federated_registration_entries" ("bundle_id" integer,"registered_entry_id" integer, PRIMARY KEY ("bundle_id","registered_entry_id"));
+            CREATE TABLE IF NOT EXISTS "bundles" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"trust_domain" varchar(255) NOT NULL,"data" blob );
+            CREATE TABLE IF NOT EXISTS "attested_node_entries" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"spiffe_id" varchar(255),"data_type" varchar(255),"serial_number" varchar(255),"expires_at" datetime,"new_serial_number" varchar(255),"new_expires_at" datetime,"can_reattest" bool,"agent_version" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "attested_node_entries_events" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"spiffe_id" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "node_resolver_map_entries" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"spiffe_id" varchar(255),"type" varchar(255),"value" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "registered_entries" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"entry_id" varchar(255),"spiffe_id" varchar(255),"parent_id" varchar(255),"ttl" integer,"admin" bool,"downstream" bool,"expiry" bigint,"revision_number" bigint,"store_svid" bool,"hint" varchar(255),"jwt_svid_ttl" integer,"additional_attributes" blob );
+            CREATE TABLE IF NOT EXISTS "registered_entries_events" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"entry_id" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "join_tokens" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"token" varchar(255),"expiry" bigint );
+            INSERT INTO join_tokens VALUES(1,'2026-10-17 07:27:21.630666346+00:00','2026-10-17 07:27:21.630666346+00:00','token',1792225641);
+            CREATE TABLE IF NOT EXISTS "selectors" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"registered_entry_id" integer,"type" varchar(255),"value" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "migrations" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"version" integer,"code_version" varchar(255) );
+            INSERT INTO migrations VALUES(1,'2026-10-17 07:27:21.629896197+00:00','2026-10-17 07:27:21.629896197+00:00',25,'1.15.3-dev-unk');
+            CREATE TABLE IF NOT EXISTS "dns_names" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"registered_entry_id" integer,"value" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "federated_trust_domains" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"trust_domain" varchar(255) NOT NULL,"bundle_endpoint_url" varchar(255),"bundle_endpoint_profile" varchar(255),"endpoint_spiffe_id" varchar(255),"implicit" bool );
+            CREATE TABLE IF NOT EXISTS "ca_journals" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"data" blob,"active_x509_authority_id" varchar(255),"active_jwt_authority_id" varchar(255) );
+            INSERT INTO sqlite_sequence VALUES('migrations',1);
+            INSERT INTO sqlite_sequence VALUES('join_tokens',1);
+            CREATE UNIQUE INDEX uix_bundles_trust_domain ON "bundles"(trust_domain) ;
+            CREATE INDEX idx_attested_node_entries_expires_at ON "attested_node_entries"(expires_at) ;
+            CREATE UNIQUE INDEX uix_attested_node_entries_spiffe_id ON "attested_node_entries"(spiffe_id) ;
+            CREATE UNIQUE INDEX idx_node_resolver_map ON "node_resolver_map_entries"(spiffe_id, "type", "value") ;
+            CREATE INDEX idx_registered_entries_spiffe_id ON "registered_entries"(spiffe_id) ;
+            CREATE INDEX idx_registered_entries_parent_id ON "registered_entries"(parent_id) ;
+            CREATE INDEX idx_registered_entries_expiry ON "registered_entries"("expiry") ;
+            CREATE INDEX idx_registered_entries_hint ON "registered_entries"("hint") ;
+            CREATE UNIQUE INDEX uix_registered_entries_entry_id ON "registered_entries"(entry_id) ;
+            CREATE UNIQUE INDEX uix_join_tokens_token ON "join_tokens"("token") ;
+            CREATE INDEX idx_selectors_type_value ON "selectors"("type", "value") ;
+            CREATE UNIQUE INDEX idx_selector_entry ON "selectors"(registered_entry_id, "type", "value") ;
+            CREATE UNIQUE INDEX idx_dns_entry ON "dns_names"(registered_entry_id, "value") ;
+            CREATE UNIQUE INDEX uix_federated_trust_domains_trust_domain ON "federated_trust_domains"(trust_domain) ;
+            CREATE INDEX idx_ca_journals_active_x509_authority_id ON "ca_journals"(active_x509_authority_id) ;
+            CREATE INDEX idx_ca_journals_active_jwt_authority_id ON "ca_journals"(active_jwt_authority_id) ;
+            CREATE INDEX idx_federated_registration_entries_registered_entry_id ON "federated_registration_entries"(registered_entry_id) ;
+            COMMIT;
+            `,
+		26: `
+            PRAGMA foreign_keys=OFF;
+            BEGIN TRANSACTION;
+            CREATE TABLE IF NOT EXISTS "federated_registration_entries" ("bundle_id" integer,"registered_entry_id" integer, PRIMARY KEY ("bundle_id","registered_entry_id"));
+            CREATE TABLE IF NOT EXISTS "bundles" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"trust_domain" varchar(255) NOT NULL,"data" blob );
+            CREATE TABLE IF NOT EXISTS "attested_node_entries" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"spiffe_id" varchar(255),"data_type" varchar(255),"serial_number" varchar(255),"expires_at" datetime,"new_serial_number" varchar(255),"new_expires_at" datetime,"can_reattest" bool,"agent_version" varchar(255) );
+            INSERT INTO attested_node_entries VALUES(1,'2026-10-17 09:24:30.219333949+00:00','2026-10-17 09:24:30.219333949+00:00','spiffe://example.org/spire/agent/test','test','1234','2027-10-17 09:24:30+00:00','',NULL,0,'1.15.3');
+            CREATE TABLE IF NOT EXISTS "attested_node_entries_events" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"spiffe_id" varchar(255) );
+            INSERT INTO attested_node_entries_events VALUES(1,'2026-10-17 09:24:30.21942547+00:00','2026-10-17 09:24:30.21942547+00:00','spiffe://example.org/spire/agent/test');
+            CREATE TABLE IF NOT EXISTS "node_resolver_map_entries" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"spiffe_id" varchar(255),"type" varchar(255),"value" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "registered_entries" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"entry_id" varchar(255),"spiffe_id" varchar(255),"parent_id" varchar(255),"ttl" integer,"admin" bool,"downstream" bool,"expiry" bigint,"revision_number" bigint,"store_svid" bool,"hint" varchar(255),"jwt_svid_ttl" integer,"additional_attributes" blob );
+            CREATE TABLE IF NOT EXISTS "registered_entries_events" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"entry_id" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "join_tokens" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"token" varchar(255),"expiry" bigint,"max_uses" integer,"use_count" integer,"agent_path_template" varchar(255) );
+            INSERT INTO join_tokens VALUES(1,'2026-10-17 09:24:30.219158324+00:00','2026-10-17 09:24:30.219158324+00:00','token',1823765070,0,0,'');
+            CREATE TABLE IF NOT EXISTS "join_token_selectors" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"join_token_id" integer,"type" varchar(255),"value" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "selectors" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"registered_entry_id" integer,"type" varchar(255),"value" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "migrations" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"version" integer,"code_version" varchar(255) );
+            INSERT INTO migrations VALUES(1,'2026-10-17 09:24:30.217999999+00:00','2026-10-17 09:24:30.217999999+00:00',26,'1.15.3-dev-unk');
+            CREATE TABLE IF NOT EXISTS "dns_names" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"registered_entry_id" integer,"value" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "federated_trust_domains" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"trust_domain" varchar(255) NOT NULL,"bundle_endpoint_url" varchar(255),"bundle_endpoint_profile" varchar(255),"endpoint_spiffe_id" varchar(255),"implicit" bool );
+            CREATE TABLE IF NOT EXISTS "ca_journals" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"data" blob,"active_x509_authority_id" varchar(255),"active_jwt_authority_id" varchar(255) );
+            INSERT INTO sqlite_sequence VALUES('migrations',1);
+            INSERT INTO sqlite_sequence VALUES('join_tokens',1);
+            INSERT INTO sqlite_sequence VALUES('attested_node_entries',1);
+            INSERT INTO sqlite_sequence VALUES('attested_node_entries_events',1);
+            CREATE UNIQUE INDEX uix_bundles_trust_domain ON "bundles"(trust_domain) ;
+            CREATE INDEX idx_attested_node_entries_expires_at ON "attested_node_entries"(expires_at) ;
+            CREATE UNIQUE INDEX uix_attested_node_entries_spiffe_id ON "attested_node_entries"(spiffe_id) ;
+            CREATE UNIQUE INDEX idx_node_resolver_map ON "node_resolver_map_entries"(spiffe_id, "type", "value") ;
+            CREATE INDEX idx_registered_entries_spiffe_id ON "registered_entries"(spiffe_id) ;
+            CREATE INDEX idx_registered_entries_parent_id ON "registered_entries"(parent_id) ;
+            CREATE INDEX idx_registered_entries_expiry ON "registered_entries"("expiry") ;
+            CREATE INDEX idx_registered_entries_hint ON "registered_entries"("hint") ;
+            CREATE UNIQUE INDEX uix_registered_entries_entry_id ON "registered_entries"(entry_id) ;
+            CREATE UNIQUE INDEX uix_join_tokens_token ON "join_tokens"("token") ;
+            CREATE UNIQUE INDEX idx_join_token_selector ON "join_token_selectors"(join_token_id, "type", "value") ;
+            CREATE INDEX idx_selectors_type_value ON "selectors"("type", "value") ;
+            CREATE UNIQUE INDEX idx_selector_entry ON "selectors"(registered_entry_id, "type", "value") ;
+            CREATE UNIQUE INDEX idx_dns_entry ON "dns_names"(registered_entry_id, "value") ;
+            CREATE UNIQUE INDEX uix_federated_trust_domains_trust_domain ON "federated_trust_domains"(trust_domain) ;
+            CREATE INDEX idx_ca_journals_active_x509_authority_id ON "ca_journals"(active_x509_authority_id) ;
+            CREATE INDEX idx_ca_journals_active_jwt_authority_id ON "ca_journals"(active_jwt_authority_id) ;
+            CREATE INDEX idx_federated_registration_entries_registered_entry_id ON "federated_registration_entries"(registered_entry_id) ;
+            COMMIT;
+            `,
+		27: `
+            PRAGMA foreign_keys=OFF;
+            BEGIN TRANSACTION;
+            CREATE TABLE IF NOT EXISTS "federated_registration_entries" ("bundle_id" integer,"registered_entry_id" integer, PRIMARY KEY ("bundle_id","registered_entry_id"));
+            CREATE TABLE IF NOT EXISTS "bundles" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"trust_domain" varchar(255) NOT NULL,"data" blob );
+            CREATE TABLE IF NOT EXISTS "attested_node_entries" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"spiffe_id" varchar(255),"data_type" varchar(255),"serial_number" varchar(255),"expires_at" datetime,"new_serial_number" varchar(255),"new_expires_at" datetime,"can_reattest" bool,"agent_version" varchar(255),"last_seen_at" datetime,"status_report" blob );
+            INSERT INTO attested_node_entries VALUES(1,'2026-10-17 09:53:37.971480582+00:00','2026-10-17 09:53:37.971480582+00:00','spiffe://example.org/spire/agent/test','test','1234','2027-10-17 09:53:37+00:00','',NULL,0,'1.15.3','2026-10-17 09:53:37+00:00',NULL);
+            CREATE TABLE IF NOT EXISTS "attested_node_entries_events" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"spiffe_id" varchar(255) );
+            INSERT INTO attested_node_entries_events VALUES(1,'2026-10-17 09:53:37.971638221+00:00','2026-10-17 09:53:37.971638221+00:00','spiffe://example.org/spire/agent/test');
+            CREATE TABLE IF NOT EXISTS "node_resolver_map_entries" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"spiffe_id" varchar(255),"type" varchar(255),"value" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "registered_entries" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"entry_id" varchar(255),"spiffe_id" varchar(255),"parent_id" varchar(255),"ttl" integer,"admin" bool,"downstream" bool,"expiry" bigint,"revision_number" bigint,"store_svid" bool,"hint" varchar(255),"jwt_svid_ttl" integer,"additional_attributes" blob );
+            CREATE TABLE IF NOT EXISTS "registered_entries_events" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"entry_id" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "join_tokens" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"token" varchar(255),"expiry" bigint,"max_uses" integer,"use_count" integer,"agent_path_template" varchar(255) );
+            INSERT INTO join_tokens VALUES(1,'2026-10-17 09:53:37.971749111+00:00','2026-10-17 09:53:37.971749111+00:00','token',1823766817,0,0,'');
+            CREATE TABLE IF NOT EXISTS "join_token_selectors" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"join_token_id" integer,"type" varchar(255),"value" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "selectors" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"registered_entry_id" integer,"type" varchar(255),"value" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "migrations" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"version" integer,"code_version" varchar(255) );
+            INSERT INTO migrations VALUES(1,'2026-10-17 09:53:37.97019393+00:00','2026-10-17 09:53:37.97019393+00:00',27,'1.15.3-dev-unk');
+            CREATE TABLE IF NOT EXISTS "dns_names" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"registered_entry_id" integer,"value" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "federated_trust_domains" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"trust_domain" varchar(255) NOT NULL,"bundle_endpoint_url" varchar(255),"bundle_endpoint_profile" varchar(255),"endpoint_spiffe_id" varchar(255),"implicit" bool );
+            CREATE TABLE IF NOT EXISTS "ca_journals" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"data" blob,"active_x509_authority_id" varchar(255),"active_jwt_authority_id" varchar(255) );
+            INSERT INTO sqlite_sequence VALUES('migrations',1);
+            INSERT INTO sqlite_sequence VALUES('attested_node_entries',1);
+            INSERT INTO sqlite_sequence VALUES('attested_node_entries_events',1);
+            INSERT INTO sqlite_sequence VALUES('join_tokens',1);
+            CREATE UNIQUE INDEX uix_bundles_trust_domain ON "bundles"(trust_domain) ;
+            CREATE INDEX idx_attested_node_entries_expires_at ON "attested_node_entries"(expires_at) ;
+            CREATE INDEX idx_attested_node_entries_last_seen_at ON "attested_node_entries"(last_seen_at) ;
+            CREATE UNIQUE INDEX uix_attested_node_entries_spiffe_id ON "attested_node_entries"(spiffe_id) ;
+            CREATE UNIQUE INDEX idx_node_resolver_map ON "node_resolver_map_entries"(spiffe_id, "type", "value") ;
+            CREATE INDEX idx_registered_entries_spiffe_id ON "registered_entries"(spiffe_id) ;
+            CREATE INDEX idx_registered_entries_parent_id ON "registered_entries"(parent_id) ;
+            CREATE INDEX idx_registered_entries_expiry ON "registered_entries"("expiry") ;
+            CREATE INDEX idx_registered_entries_hint ON "registered_entries"("hint") ;
+            CREATE UNIQUE INDEX uix_registered_entries_entry_id ON "registered_entries"(entry_id) ;
+            CREATE UNIQUE INDEX uix_join_tokens_token ON "join_tokens"("token") ;
+            CREATE UNIQUE INDEX idx_join_token_selector ON "join_token_selectors"(join_token_id, "type", "value") ;
+            CREATE INDEX idx_selectors_type_value ON "selectors"("type", "value") ;
+            CREATE UNIQUE INDEX idx_selector_entry ON "selectors"(registered_entry_id, "type", "value") ;
+            CREATE UNIQUE INDEX idx_dns_entry ON "dns_names"(registered_entry_id, "value") ;
+            CREATE UNIQUE INDEX uix_federated_trust_domains_trust_domain ON "federated_trust_domains"(trust_domain) ;
+            CREATE INDEX idx_ca_journals_active_x509_authority_id ON "ca_journals"(active_x509_authority_id) ;
+            CREATE INDEX idx_ca_journals_active_jwt_authority_id ON "ca_journals"(active_jwt_authority_id) ;
+            CREATE INDEX idx_federated_registration_entries_registered_entry_id ON "federated_registration_entries"(registered_entry_id) ;
+            COMMIT;
+            `,
+		28: `
+            PRAGMA foreign_keys=OFF;
+            BEGIN TRANSACTION;
+            CREATE TABLE IF NOT EXISTS "federated_registration_entries" ("bundle_id" integer,"registered_entry_id" integer, PRIMARY KEY ("bundle_id","registered_entry_id"));
+            CREATE TABLE IF NOT EXISTS "bundles" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"trust_domain" varchar(255) NOT NULL,"data" blob );
+            CREATE TABLE IF NOT EXISTS "attested_node_entries" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"spiffe_id" varchar(255),"data_type" varchar(255),"serial_number" varchar(255),"expires_at" datetime,"new_serial_number" varchar(255),"new_expires_at" datetime,"can_reattest" bool,"agent_version" varchar(255),"last_seen_at" datetime,"status_report" blob );
+            CREATE TABLE IF NOT EXISTS "attested_node_entries_events" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"spiffe_id" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "node_resolver_map_entries" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"spiffe_id" varchar(255),"type" varchar(255),"value" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "registered_entries" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"entry_id" varchar(255),"spiffe_id" varchar(255),"parent_id" varchar(255),"ttl" integer,"admin" bool,"downstream" bool,"expiry" bigint,"revision_number" bigint,"store_svid" bool,"hint" varchar(255),"jwt_svid_ttl" integer,"additional_attributes" blob );
+            CREATE TABLE IF NOT EXISTS "registered_entries_events" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"entry_id" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "join_tokens" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"token" varchar(255),"expiry" bigint,"max_uses" integer,"use_count" integer,"agent_path_template" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "join_token_selectors" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"join_token_id" integer,"type" varchar(255),"value" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "selectors" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"registered_entry_id" integer,"type" varchar(255),"value" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "migrations" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"version" integer,"code_version" varchar(255) );
+            INSERT INTO migrations VALUES(1,'2026-10-17 12:06:39.729222467+00:00','2026-10-17 12:06:39.729222467+00:00',28,'1.15.3-dev-unk');
+            CREATE TABLE IF NOT EXISTS "dns_names" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"registered_entry_id" integer,"value" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "federated_trust_domains" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"trust_domain" varchar(255) NOT NULL,"bundle_endpoint_url" varchar(255),"bundle_endpoint_profile" varchar(255),"endpoint_spiffe_id" varchar(255),"implicit" bool );
+            INSERT INTO federated_trust_domains VALUES(1,'2026-10-17 12:06:39.731413689+00:00','2026-10-17 12:06:39.731413689+00:00','federated.test','https://federated.test/bundle','https_web','',0);
+            CREATE TABLE IF NOT EXISTS "ca_journals" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"data" blob,"active_x509_authority_id" varchar(255),"active_jwt_authority_id" varchar(255) );
+            CREATE TABLE IF NOT EXISTS "quarantined_bundles" ("id" integer primary key autoincrement,"created_at" datetime,"updated_at" datetime,"trust_domain" varchar(255) NOT NULL,"data" blob );
+            INSERT INTO sqlite_sequence VALUES('migrations',1);
+            INSERT INTO sqlite_sequence VALUES('federated_trust_domains',1);
+            CREATE UNIQUE INDEX uix_bundles_trust_domain ON "bundles"(trust_domain) ;
+            CREATE INDEX idx_attested_node_entries_expires_at ON "attested_node_entries"(expires_at) ;
+            CREATE INDEX idx_attested_node_entries_last_seen_at ON "attested_node_entries"(last_seen_at) ;
+            CREATE UNIQUE INDEX uix_attested_node_entries_spiffe_id ON "attested_node_entries"(spiffe_id) ;
+            CREATE UNIQUE INDEX idx_node_resolver_map ON "node_resolver_map_entries"(spiffe_id, "type", "value") ;
+            CREATE INDEX idx_registered_entries_parent_id ON "registered_entries"(parent_id) ;
+            CREATE INDEX idx_registered_entries_expiry ON "registered_entries"("expiry") ;
+            CREATE INDEX idx_registered_entries_hint ON "registered_entries"("hint") ;
+            CREATE INDEX idx_registered_entries_spiffe_id ON "registered_entries"(spiffe_id) ;
+            CREATE UNIQUE INDEX uix_registered_entries_entry_id ON "registered_entries"(entry_id) ;
+            CREATE UNIQUE INDEX uix_join_tokens_token ON "join_tokens"("token") ;
+            CREATE UNIQUE INDEX idx_join_token_selector ON "join_token_selectors"(join_token_id, "type", "value") ;
+            CREATE INDEX idx_selectors_type_value ON "selectors"("type", "value") ;
+            CREATE UNIQUE INDEX idx_selector_entry ON "selectors"(registered_entry_id, "type", "value") ;
+            CREATE UNIQUE INDEX idx_dns_entry ON "dns_names"(registered_entry_id, "value") ;
+            CREATE UNIQUE INDEX uix_federated_trust_domains_trust_domain ON "federated_trust_domains"(trust_domain) ;
+            CREATE INDEX idx_ca_journals_active_x509_authority_id ON "ca_journals"(active_x509_authority_id) ;
+            CREATE INDEX idx_ca_journals_active_jwt_authority_id ON "ca_journals"(active_jwt_authority_id) ;
+            CREATE UNIQUE INDEX uix_quarantined_bundles_trust_domain ON "quarantined_bundles"(trust_domain) ;
+            CREATE INDEX idx_federated_registration_entries_registered_entry_id ON "federated_registration_entries"(registered_entry_id) ;
+            COMMIT;
+            `,
 	}
 )
 
@@ -34,6 +34,8 @@ type AttestedNode struct {
 	NewExpiresAt    *time.Time
 	CanReattest     bool
 	AgentVersion    string
+	LastSeenAt      *time.Time `gorm:"index"`
+	StatusReport    []byte
 
 	Selectors []*NodeSelector
 }
@@ -125,6 +127,24 @@ type JoinToken struct {
 
 	Token  string `gorm:"unique_index"`
 	Expiry int64
+
+	// MaxUses is the number of times the token can be used, zero meaning
+	// once and a negative value meaning until it expires
+	MaxUses  int32
+	UseCount int32
+
+	Selectors         []JoinTokenSelector
+	AgentPathTemplate string
+}
+
+// JoinTokenSelector holds a selector added to the agents attested with a
+// join token
+type JoinTokenSelector struct {
+	Model
+
+	JoinTokenID uint   `gorm:"unique_index:idx_join_token_selector"`
+	Type        string `gorm:"unique_index:idx_join_token_selector"`
+	Value       string `gorm:"unique_index:idx_join_token_selector"`
 }
 
 type Selector struct {
@@ -172,6 +192,10 @@ type FederatedTrustDomain struct {
 	// Implicit indicates whether the trust domain automatically federates with
 	// all registration entries by default or not.
 	Implicit bool
+
+	// Options holds the protobuf encoded FederationRelationshipOptions of
+	// the relationship.
+	Options []byte
 }
 
 // TableName gets table name of FederatedTrustDomain
@@ -197,6 +221,15 @@ type CAJournal struct {
 	ActiveJWTAuthorityID string `gorm:"index:idx_ca_journals_active_jwt_authority_id"`
 }
 
+// QuarantinedBundle holds a federated bundle update that violated the bundle
+// safeguards of its federation relationship, until it is approved.
+type QuarantinedBundle struct {
+	Model
+
+	TrustDomain string `gorm:"not null;unique_index"`
+	Data        []byte `gorm:"size:16777215"` // make MySQL to use MEDIUMBLOB (max 16MB) - doesn't affect PostgreSQL/SQLite
+}
+
 // Migration holds database schema version number, and
 // the SPIRE Code version number
 type Migration struct {
@@ -34,6 +34,8 @@ import (
 	"github.com/spiffe/spire/pkg/server/datastore/sqlcommon"
 	"github.com/spiffe/spire/proto/private/server/journal"
 	"github.com/spiffe/spire/proto/spire/common"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
@@ -72,6 +74,9 @@ const (
 	// Maximum size for additional attributes message in a registration entry
 	maxAdditionalAttributesSize = 65535
 
+	// Maximum size for the status report of an attested node
+	maxStatusReportSize = 65535
+
 	// defaultPruneAttestedNodesBatchSize is the number of expired attested
 	// nodes pruned per call when no batch size (or a non-positive one) is
 	// provided.
@@ -293,6 +298,30 @@ func (ds *Plugin) RevokeJWTKey(ctx context.Context, trustDoaminID string, author
 	return revokedKey, nil
 }
 
+// TaintWITKey taints a WIT Authority key
+func (ds *Plugin) TaintWITKey(ctx context.Context, trustDomainID string, authorityID string) (*common.PublicKey, error) {
+	var taintedKey *common.PublicKey
+	if err := ds.withReadModifyWriteTx(ctx, func(tx *gorm.DB) (err error) {
+		taintedKey, err = taintWITKey(tx, trustDomainID, authorityID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return taintedKey, nil
+}
+
+// RevokeWITKey removes WIT key from the bundle
+func (ds *Plugin) RevokeWITKey(ctx context.Context, trustDomainID string, authorityID string) (*common.PublicKey, error) {
+	var revokedKey *common.PublicKey
+	if err := ds.withReadModifyWriteTx(ctx, func(tx *gorm.DB) (err error) {
+		revokedKey, err = revokeWITKey(tx, trustDomainID, authorityID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return revokedKey, nil
+}
+
 // CreateAttestedNode stores the given attested node
 func (ds *Plugin) CreateAttestedNode(ctx context.Context, node *common.AttestedNode) (attestedNode *common.AttestedNode, err error) {
 	if node == nil {
@@ -377,10 +406,17 @@ func (ds *Plugin) ListAttestedNodes(ctx context.Context,
 // UpdateAttestedNode updates the given node's cert serial and expiration.
 func (ds *Plugin) UpdateAttestedNode(ctx context.Context, n *common.AttestedNode, mask *common.AttestedNodeMask) (node *common.AttestedNode, err error) {
 	if err = ds.withReadModifyWriteTx(ctx, func(tx *gorm.DB) (err error) {
-		node, err = updateAttestedNode(tx, n, mask)
+		var statusOnly bool
+		node, statusOnly, err = updateAttestedNode(tx, n, mask)
 		if err != nil {
 			return err
 		}
+		if statusOnly {
+			// The last seen time and status report are refreshed
+			// periodically by every agent and are not used by the node
+			// caches, so they do not produce events.
+			return nil
+		}
 		return createAttestedNodeEvent(tx, &datastore.AttestedNodeEvent{
 			SpiffeID: n.SpiffeId,
 		})
@@ -665,6 +701,22 @@ func (ds *Plugin) FetchRegistrationEntryEvent(ctx context.Context, eventID uint)
 	return event, nil
 }
 
+// ConsumeJoinToken records a use of the given join token and returns it,
+// with the use count updated. The token is deleted once it has no uses
+// left. If the token does not exist, has expired at the given time or has no
+// uses left, nil is returned. If checkUse is set, it is called with the
+// consumed token and the use is undone if it returns an error.
+func (ds *Plugin) ConsumeJoinToken(ctx context.Context, token string, now time.Time, checkUse func(*datastore.JoinToken) error) (resp *datastore.JoinToken, err error) {
+	if err = ds.withWriteTx(ctx, func(tx *gorm.DB) (err error) {
+		resp, err = consumeJoinToken(tx, token, now, checkUse)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 // CreateJoinToken takes a Token message and stores it
 func (ds *Plugin) CreateJoinToken(ctx context.Context, token *datastore.JoinToken) (err error) {
 	if token == nil || token.Token == "" || token.Expiry.IsZero() {
@@ -690,6 +742,17 @@ func (ds *Plugin) FetchJoinToken(ctx context.Context, token string) (resp *datas
 	return resp, nil
 }
 
+// ListJoinTokens lists join tokens
+func (ds *Plugin) ListJoinTokens(ctx context.Context, req *datastore.ListJoinTokensRequest) (resp *datastore.ListJoinTokensResponse, err error) {
+	if err = ds.withReadTx(ctx, func(tx *gorm.DB) (err error) {
+		resp, err = listJoinTokens(tx, req)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // DeleteJoinToken deletes the given join token
 func (ds *Plugin) DeleteJoinToken(ctx context.Context, token string) (err error) {
 	return ds.withReadModifyWriteTx(ctx, func(tx *gorm.DB) (err error) {
@@ -724,7 +787,8 @@ func (ds *Plugin) CreateFederationRelationship(ctx context.Context, fr *datastor
 }
 
 // DeleteFederationRelationship deletes the federation relationship to the
-// given trust domain. The associated trust bundle is not deleted.
+// given trust domain, along with any quarantined bundle. The associated trust
+// bundle is not deleted.
 func (ds *Plugin) DeleteFederationRelationship(ctx context.Context, trustDomain spiffeid.TrustDomain) error {
 	if trustDomain.IsZero() {
 		return status.Error(codes.InvalidArgument, "trust domain is required")
@@ -777,6 +841,70 @@ func (ds *Plugin) UpdateFederationRelationship(ctx context.Context, fr *datastor
 	})
 }
 
+// SetFederationRelationshipOptions sets the options of the federation
+// relationship with the given trust domain, replacing the options previously
+// set.
+func (ds *Plugin) SetFederationRelationshipOptions(ctx context.Context, trustDomain spiffeid.TrustDomain, options *common.FederationRelationshipOptions) (fr *datastore.FederationRelationship, err error) {
+	if err = ds.withReadModifyWriteTx(ctx, func(tx *gorm.DB) (err error) {
+		fr, err = setFederationRelationshipOptions(tx, trustDomain, options)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return fr, nil
+}
+
+// SetQuarantinedBundle sets the quarantined bundle of a federated trust
+// domain, replacing any bundle previously quarantined for it.
+func (ds *Plugin) SetQuarantinedBundle(ctx context.Context, qb *common.QuarantinedBundle) (quarantinedBundle *common.QuarantinedBundle, err error) {
+	if err = ds.withReadModifyWriteTx(ctx, func(tx *gorm.DB) (err error) {
+		quarantinedBundle, err = setQuarantinedBundle(tx, qb)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return quarantinedBundle, nil
+}
+
+// FetchQuarantinedBundle fetches the quarantined bundle of the given trust
+// domain. If there is no quarantined bundle, nil is returned.
+func (ds *Plugin) FetchQuarantinedBundle(ctx context.Context, trustDomainID string) (quarantinedBundle *common.QuarantinedBundle, err error) {
+	if err = ds.withReadTx(ctx, func(tx *gorm.DB) (err error) {
+		quarantinedBundle, err = fetchQuarantinedBundle(tx, trustDomainID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return quarantinedBundle, nil
+}
+
+// DeleteQuarantinedBundle deletes the quarantined bundle of the given trust
+// domain.
+func (ds *Plugin) DeleteQuarantinedBundle(ctx context.Context, trustDomainID string) error {
+	return ds.withWriteTx(ctx, func(tx *gorm.DB) (err error) {
+		err = deleteQuarantinedBundle(tx, trustDomainID)
+		return err
+	})
+}
+
+// ReleaseQuarantinedBundle sets the bundle of a federated trust domain and
+// deletes any bundle quarantined for it, in a single transaction.
+func (ds *Plugin) ReleaseQuarantinedBundle(ctx context.Context, b *common.Bundle) (bundle *common.Bundle, err error) {
+	if err = ds.withWriteTx(ctx, func(tx *gorm.DB) (err error) {
+		bundle, err = setBundle(tx, b)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("trust_domain = ?", b.TrustDomainId).Delete(&QuarantinedBundle{}).Error; err != nil {
+			return sqlcommon.NewWrappedSQLError(err)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
 // SetUseServerTimestamps controls whether server-generated timestamps should be used in the database.
 // This is only intended to be used by tests in order to produce deterministic timestamp data,
 // since some databases round off timestamp data with lower precision.
@@ -801,6 +929,17 @@ func (ds *Plugin) FetchCAJournal(ctx context.Context, activeX509AuthorityID stri
 	return caJournal, nil
 }
 
+// ListCAJournals lists CA journals
+func (ds *Plugin) ListCAJournals(ctx context.Context, req *datastore.ListCAJournalsRequest) (resp *datastore.ListCAJournalsResponse, err error) {
+	if err = ds.withReadTx(ctx, func(tx *gorm.DB) (err error) {
+		resp, err = listCAJournals(tx, req)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // ListCAJournalsForTesting returns all the CA journal records, and is meant to
 // be used in tests.
 func (ds *Plugin) ListCAJournalsForTesting(ctx context.Context) (caJournals []*datastore.CAJournal, err error) {
@@ -1052,11 +1191,23 @@ func (ds *Plugin) withReadTx(ctx context.Context, op func(tx *gorm.DB) error) er
 	return ds.withTx(ctx, op, true)
 }
 
-func (ds *Plugin) withTx(ctx context.Context, op func(tx *gorm.DB) error, readOnly bool) error {
+func (ds *Plugin) withTx(ctx context.Context, op func(tx *gorm.DB) error, readOnly bool) (err error) {
 	ds.mu.Lock()
 	db := ds.db
 	ds.mu.Unlock()
 
+	// Only trace transactions that are part of a sampled trace, so background
+	// tasks (e.g. event polling and pruning) do not produce root spans. The
+	// datastore operation itself is traced by the datastore telemetry wrapper.
+	if telemetry.IsTracing(ctx) {
+		var span trace.Span
+		ctx, span = telemetry.StartSpan(ctx, "sqlstore.transaction",
+			attribute.String("db.system", db.databaseType),
+			attribute.Bool("db.read_only", readOnly),
+		)
+		defer telemetry.EndSpan(span, &err)
+	}
+
 	if db.databaseType == SQLite && !readOnly {
 		// sqlite3 can only have one writer at a time. since we're in WAL mode,
 		// there can be concurrent reads and writes, so no lock is necessary
@@ -1629,6 +1780,84 @@ func revokeJWTKey(tx *gorm.DB, trustDomainID string, authorityID string) (*commo
 	return revokedKey, nil
 }
 
+func taintWITKey(tx *gorm.DB, trustDomainID string, authorityID string) (*common.PublicKey, error) {
+	bundle, err := getBundle(tx, trustDomainID)
+	if err != nil {
+		return nil, err
+	}
+
+	var taintedKey *common.PublicKey
+	for _, witKey := range bundle.WitSigningKeys {
+		if witKey.Kid != authorityID {
+			continue
+		}
+
+		if witKey.TaintedKey {
+			return nil, status.Error(codes.InvalidArgument, "key is already tainted")
+		}
+
+		// Check if a WIT Key with the provided keyID was already
+		// tainted in this loop. This is purely defensive since we do not
+		// allow to have repeated key IDs.
+		if taintedKey != nil {
+			return nil, status.Error(codes.Internal, "another WIT Key found with the same KeyID")
+		}
+		taintedKey = witKey
+		witKey.TaintedKey = true
+	}
+
+	if taintedKey == nil {
+		return nil, status.Error(codes.NotFound, "no WIT Key found with provided key ID")
+	}
+
+	bundle.SequenceNumber++
+	if _, err := updateBundle(tx, bundle, nil); err != nil {
+		return nil, err
+	}
+
+	return taintedKey, nil
+}
+
+func revokeWITKey(tx *gorm.DB, trustDomainID string, authorityID string) (*common.PublicKey, error) {
+	bundle, err := getBundle(tx, trustDomainID)
+	if err != nil {
+		return nil, err
+	}
+
+	var publicKeys []*common.PublicKey
+	var revokedKey *common.PublicKey
+	for _, key := range bundle.WitSigningKeys {
+		if key.Kid == authorityID {
+			// Check if a WIT Key with the provided keyID was already
+			// found in this loop. This is purely defensive since we do not
+			// allow to have repeated key IDs.
+			if revokedKey != nil {
+				return nil, status.Error(codes.Internal, "another key found with the same KeyID")
+			}
+
+			if !key.TaintedKey {
+				return nil, status.Error(codes.InvalidArgument, "it is not possible to revoke an untainted key")
+			}
+
+			revokedKey = key
+			continue
+		}
+		publicKeys = append(publicKeys, key)
+	}
+	bundle.WitSigningKeys = publicKeys
+
+	if revokedKey == nil {
+		return nil, status.Error(codes.NotFound, "no WIT Key found with provided key ID")
+	}
+
+	bundle.SequenceNumber++
+	if _, err := updateBundle(tx, bundle, nil); err != nil {
+		return nil, err
+	}
+
+	return revokedKey, nil
+}
+
 func getBundle(tx *gorm.DB, trustDomainID string) (*common.Bundle, error) {
 	model := &Bundle{}
 	if err := tx.Find(model, "trust_domain = ?", trustDomainID).Error; err != nil {
@@ -1644,6 +1873,11 @@ func getBundle(tx *gorm.DB, trustDomainID string) (*common.Bundle, error) {
 }
 
 func createAttestedNode(tx *gorm.DB, node *common.AttestedNode) (*common.AttestedNode, error) {
+	statusReport, err := marshalAndValidateStatusReport(node.StatusReport)
+	if err != nil {
+		return nil, err
+	}
+
 	model := AttestedNode{
 		SpiffeID:        node.SpiffeId,
 		DataType:        node.AttestationDataType,
@@ -1653,13 +1887,15 @@ func createAttestedNode(tx *gorm.DB, node *common.AttestedNode) (*common.Atteste
 		NewExpiresAt:    nullableUnixTimeToDBTime(node.NewCertNotAfter),
 		CanReattest:     node.CanReattest,
 		AgentVersion:    node.AgentVersion,
+		LastSeenAt:      nullableUnixTimeToDBTime(node.LastSeenAt),
+		StatusReport:    statusReport,
 	}
 
 	if err := tx.Create(&model).Error; err != nil {
 		return nil, sqlcommon.NewWrappedSQLError(err)
 	}
 
-	return modelToAttestedNode(model), nil
+	return modelToAttestedNode(model)
 }
 
 func fetchAttestedNode(tx *gorm.DB, spiffeID string) (*common.AttestedNode, error) {
@@ -1671,7 +1907,7 @@ func fetchAttestedNode(tx *gorm.DB, spiffeID string) (*common.AttestedNode, erro
 	case err != nil:
 		return nil, sqlcommon.NewWrappedSQLError(err)
 	}
-	return modelToAttestedNode(model), nil
+	return modelToAttestedNode(model)
 }
 
 func countAttestedNodes(tx *gorm.DB) (int32, error) {
@@ -1687,9 +1923,15 @@ func countAttestedNodesHasFilters(req *datastore.CountAttestedNodesRequest) bool
 	if req.ByAttestationType != "" || req.ByBanned != nil || !req.ByExpiresBefore.IsZero() {
 		return true
 	}
+	if !req.ByLastSeenBefore.IsZero() || !req.ByLastSeenAfter.IsZero() {
+		return true
+	}
 	if req.BySelectorMatch != nil || !req.FetchSelectors || req.ByCanReattest != nil {
 		return true
 	}
+	if len(req.BySpiffeIDPrefixes) > 0 {
+		return true
+	}
 	return false
 }
 
@@ -1750,12 +1992,15 @@ func countAttestedNodesWithFilters(ctx context.Context, db *sqlDB, _ logrus.Fiel
 
 	var val int32
 	listReq := &datastore.ListAttestedNodesRequest{
-		ByAttestationType: req.ByAttestationType,
-		ByBanned:          req.ByBanned,
-		ByExpiresBefore:   req.ByExpiresBefore,
-		BySelectorMatch:   req.BySelectorMatch,
-		FetchSelectors:    req.FetchSelectors,
-		ByCanReattest:     req.ByCanReattest,
+		ByAttestationType:  req.ByAttestationType,
+		ByBanned:           req.ByBanned,
+		ByExpiresBefore:    req.ByExpiresBefore,
+		ByLastSeenBefore:   req.ByLastSeenBefore,
+		ByLastSeenAfter:    req.ByLastSeenAfter,
+		BySelectorMatch:    req.BySelectorMatch,
+		FetchSelectors:     req.FetchSelectors,
+		ByCanReattest:      req.ByCanReattest,
+		BySpiffeIDPrefixes: req.BySpiffeIDPrefixes,
 		Pagination: &datastore.Pagination{
 			Token:    "",
 			PageSize: 1000,
@@ -2051,6 +2296,16 @@ func buildListAttestedNodesQueryCTE(req *datastore.ListAttestedNodesRequest, dbT
 		args = append(args, req.ValidAt)
 	}
 
+	// Filter by last seen time. Nodes that were never seen do not match
+	if !req.ByLastSeenBefore.IsZero() {
+		builder.WriteString("\t\tAND last_seen_at < ?\n")
+		args = append(args, req.ByLastSeenBefore)
+	}
+	if !req.ByLastSeenAfter.IsZero() {
+		builder.WriteString("\t\tAND last_seen_at > ?\n")
+		args = append(args, req.ByLastSeenAfter)
+	}
+
 	// Filter by Attestation type
 	if req.ByAttestationType != "" {
 		builder.WriteString("\t\tAND data_type = ?\n")
@@ -2089,6 +2344,15 @@ func buildListAttestedNodesQueryCTE(req *datastore.ListAttestedNodesRequest, dbT
 		args = append(args, buildArgs(req.BySpiffeIDs)...)
 	}
 
+	// Filter by SPIFFE ID prefixes
+	if len(req.BySpiffeIDPrefixes) > 0 {
+		condition, prefixArgs := buildIDPrefixesCondition("spiffe_id", req.BySpiffeIDPrefixes)
+		builder.WriteString("\t\tAND ")
+		builder.WriteString(condition)
+		builder.WriteString("\n")
+		args = append(args, prefixArgs...)
+	}
+
 	builder.WriteString(")")
 	// Fetch all selectors from filtered entries
 	if fetchSelectors {
@@ -2116,7 +2380,9 @@ SELECT
 	new_serial_number,
 	new_expires_at,
 	can_reattest,
-	agent_version,`)
+	agent_version,
+	last_seen_at,
+	status_report,`)
 
 	// Add "optional" fields for selectors
 	if fetchSelectors {
@@ -2250,7 +2516,9 @@ SELECT
 	N.new_serial_number,
 	N.new_expires_at,
 	N.can_reattest,
-	N.agent_version,`)
+	N.agent_version,
+	N.last_seen_at,
+	N.status_report,`)
 	// Add "optional" fields for selectors
 	if fetchSelectors {
 		builder.WriteString(`
@@ -2295,6 +2563,16 @@ FROM attested_node_entries N
 			args = append(args, req.ValidAt)
 		}
 
+		// Filter by last seen time. Nodes that were never seen do not match
+		if !req.ByLastSeenBefore.IsZero() {
+			builder.WriteString(" AND N.last_seen_at < ?")
+			args = append(args, req.ByLastSeenBefore)
+		}
+		if !req.ByLastSeenAfter.IsZero() {
+			builder.WriteString(" AND N.last_seen_at > ?")
+			args = append(args, req.ByLastSeenAfter)
+		}
+
 		// Filter by Attestation type
 		if req.ByAttestationType != "" {
 			builder.WriteString(" AND N.data_type = ?")
@@ -2330,6 +2608,14 @@ FROM attested_node_entries N
 			builder.WriteString(")")
 			args = append(args, buildArgs(req.BySpiffeIDs)...)
 		}
+
+		// Filter by SPIFFE ID prefixes
+		if len(req.BySpiffeIDPrefixes) > 0 {
+			condition, prefixArgs := buildIDPrefixesCondition("N.spiffe_id", req.BySpiffeIDPrefixes)
+			builder.WriteString(" AND ")
+			builder.WriteString(condition)
+			args = append(args, prefixArgs...)
+		}
 		return nil
 	}
 
@@ -2407,16 +2693,27 @@ FROM attested_node_entries N
 	return builder.String(), args, nil
 }
 
-func updateAttestedNode(tx *gorm.DB, n *common.AttestedNode, mask *common.AttestedNodeMask) (*common.AttestedNode, error) {
+// updateAttestedNode updates the node fields selected by the mask. It also
+// returns true if the update only changed the last seen time and status
+// report of the node, which agents refresh periodically.
+func updateAttestedNode(tx *gorm.DB, n *common.AttestedNode, mask *common.AttestedNodeMask) (*common.AttestedNode, bool, error) {
 	var model AttestedNode
 	if err := tx.Find(&model, "spiffe_id = ?", n.SpiffeId).Error; err != nil {
-		return nil, sqlcommon.NewWrappedSQLError(err)
+		return nil, false, sqlcommon.NewWrappedSQLError(err)
 	}
 
 	if mask == nil {
 		mask = protoutil.AllTrueCommonAgentMask
 	}
 
+	// Agents post their version along with their status, which is not a
+	// change unless the version differs from the stored one
+	changed := mask
+	if mask.AgentVersion && n.AgentVersion == model.AgentVersion {
+		changed = proto.Clone(mask).(*common.AttestedNodeMask)
+		changed.AgentVersion = false
+	}
+
 	updates := make(map[string]any)
 	if mask.CertNotAfter {
 		updates["expires_at"] = time.Unix(n.CertNotAfter, 0)
@@ -2433,14 +2730,41 @@ func updateAttestedNode(tx *gorm.DB, n *common.AttestedNode, mask *common.Attest
 	if mask.CanReattest {
 		updates["can_reattest"] = n.CanReattest
 	}
-	if mask.AgentVersion {
+	if changed.AgentVersion {
 		updates["agent_version"] = n.AgentVersion
 	}
+	if mask.LastSeenAt {
+		updates["last_seen_at"] = nullableUnixTimeToDBTime(n.LastSeenAt)
+	}
+	if mask.StatusReport {
+		statusReport, err := marshalAndValidateStatusReport(n.StatusReport)
+		if err != nil {
+			return nil, false, err
+		}
+		updates["status_report"] = statusReport
+	}
 	if err := tx.Model(&model).Updates(updates).Error; err != nil {
-		return nil, sqlcommon.NewWrappedSQLError(err)
+		return nil, false, sqlcommon.NewWrappedSQLError(err)
+	}
+
+	node, err := modelToAttestedNode(model)
+	if err != nil {
+		return nil, false, err
 	}
+	return node, isStatusOnlyAttestedNodeMask(changed), nil
+}
 
-	return modelToAttestedNode(model), nil
+// isStatusOnlyAttestedNodeMask returns true if the mask only selects the
+// last seen time and status report of the node.
+func isStatusOnlyAttestedNodeMask(mask *common.AttestedNodeMask) bool {
+	if !mask.LastSeenAt && !mask.StatusReport {
+		return false
+	}
+	statusOnly := &common.AttestedNodeMask{
+		LastSeenAt:   mask.LastSeenAt,
+		StatusReport: mask.StatusReport,
+	}
+	return proto.Equal(mask, statusOnly)
 }
 
 func deleteAttestedNodeAndSelectors(tx *gorm.DB, spiffeID string, logger logrus.FieldLogger) (*common.AttestedNode, error) {
@@ -2497,7 +2821,7 @@ func deleteAttestedNodeAndSelectors(tx *gorm.DB, spiffeID string, logger logrus.
 		return nil, sqlcommon.NewWrappedSQLError(err)
 	}
 
-	return modelToAttestedNode(nodeModel), nil
+	return modelToAttestedNode(nodeModel)
 }
 
 func setNodeSelectors(tx *gorm.DB, spiffeID string, selectors []*common.Selector) error {
@@ -3490,6 +3814,7 @@ func countRegistrationEntries(ctx context.Context, db *sqlDB, _ logrus.FieldLogg
 		ByFederatesWith: req.ByFederatesWith,
 		ByHint:          req.ByHint,
 		ByDownstream:    req.ByDownstream,
+		ByScopes:        req.ByScopes,
 		Pagination: &datastore.Pagination{
 			Token:    "",
 			PageSize: 1000,
@@ -3786,6 +4111,15 @@ func appendListRegistrationEntriesFilterQuery(filterExp string, builder *strings
 		root.children = append(root.children, filterNode)
 	}
 
+	if len(req.ByScopes) > 0 {
+		query, scopeArgs := buildEntryScopesQuery(req.ByScopes)
+		root.children = append(root.children, idFilterNode{
+			idColumn: "id",
+			query:    []string{query},
+		})
+		args = append(args, scopeArgs...)
+	}
+
 	filtered := false
 	filter := func() {
 		if !filtered {
@@ -3868,6 +4202,8 @@ type nodeRow struct {
 	NewExpiresAt    sql.NullTime
 	CanReattest     sql.NullBool
 	AgentVersion    sql.NullString
+	LastSeenAt      sql.NullTime
+	StatusReport    sql.Null[[]byte]
 	SelectorType    sql.NullString
 	SelectorValue   sql.NullString
 }
@@ -3883,6 +4219,8 @@ func scanNodeRow(rs *sql.Rows, r *nodeRow) error {
 		&r.NewExpiresAt,
 		&r.CanReattest,
 		&r.AgentVersion,
+		&r.LastSeenAt,
+		&r.StatusReport,
 		&r.SelectorType,
 		&r.SelectorValue,
 	))
@@ -3931,6 +4269,18 @@ func fillNodeFromRow(node *common.AttestedNode, r *nodeRow) error {
 		node.AgentVersion = r.AgentVersion.String
 	}
 
+	if r.LastSeenAt.Valid {
+		node.LastSeenAt = r.LastSeenAt.Time.Unix()
+	}
+
+	if r.StatusReport.Valid {
+		statusReport, err := unmarshalStatusReport(r.StatusReport.V)
+		if err != nil {
+			return err
+		}
+		node.StatusReport = statusReport
+	}
+
 	return nil
 }
 
@@ -4368,20 +4718,33 @@ func buildListEventsQueryString(greaterThanEventID, lessThanEventID uint) (*stri
 
 func createJoinToken(tx *gorm.DB, token *datastore.JoinToken) error {
 	t := JoinToken{
-		Token:  token.Token,
-		Expiry: token.Expiry.Unix(),
+		Token:             token.Token,
+		Expiry:            token.Expiry.Unix(),
+		MaxUses:           token.MaxUses,
+		UseCount:          token.UseCount,
+		AgentPathTemplate: token.AgentPathTemplate,
 	}
 
 	if err := tx.Create(&t).Error; err != nil {
 		return sqlcommon.NewWrappedSQLError(err)
 	}
 
+	for _, selector := range token.Selectors {
+		if err := tx.Create(&JoinTokenSelector{
+			JoinTokenID: t.ID,
+			Type:        selector.Type,
+			Value:       selector.Value,
+		}).Error; err != nil {
+			return sqlcommon.NewWrappedSQLError(err)
+		}
+	}
+
 	return nil
 }
 
 func fetchJoinToken(tx *gorm.DB, token string) (*datastore.JoinToken, error) {
 	var model JoinToken
-	err := tx.Find(&model, "token = ?", token).Error
+	err := preloadJoinTokenSelectors(tx).Find(&model, "token = ?", token).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	} else if err != nil {
@@ -4391,6 +4754,85 @@ func fetchJoinToken(tx *gorm.DB, token string) (*datastore.JoinToken, error) {
 	return modelToJoinToken(model), nil
 }
 
+// preloadJoinTokenSelectors loads the selectors of the join tokens in the
+// order they were created.
+func preloadJoinTokenSelectors(tx *gorm.DB) *gorm.DB {
+	return tx.Preload("Selectors", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	})
+}
+
+func consumeJoinToken(tx *gorm.DB, token string, now time.Time, checkUse func(*datastore.JoinToken) error) (*datastore.JoinToken, error) {
+	// The use count is only incremented while the token is unexpired and has
+	// uses left, so concurrent attestations cannot use the token more times
+	// than allowed. Tokens with a max_uses of zero can be used once.
+	result := tx.Model(&JoinToken{}).
+		Where("token = ? AND expiry > ? AND (max_uses < 0 OR use_count < max_uses OR use_count = 0)", token, now.Unix()).
+		UpdateColumn("use_count", gorm.Expr("use_count + 1"))
+	if result.Error != nil {
+		return nil, sqlcommon.NewWrappedSQLError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	joinToken, err := fetchJoinToken(tx, token)
+	if err != nil {
+		return nil, err
+	}
+	if joinToken == nil {
+		return nil, sqlcommon.NewSQLError("join token %q not found after being consumed", token)
+	}
+
+	// Failing the check rolls back the transaction, undoing the use.
+	if checkUse != nil {
+		if err := checkUse(joinToken); err != nil {
+			return nil, err
+		}
+	}
+
+	if joinToken.IsExhausted() {
+		if err := deleteJoinToken(tx, token); err != nil {
+			return nil, err
+		}
+	}
+
+	return joinToken, nil
+}
+
+func listJoinTokens(tx *gorm.DB, req *datastore.ListJoinTokensRequest) (*datastore.ListJoinTokensResponse, error) {
+	p := req.Pagination
+	var err error
+	if p != nil {
+		tx, err = applyPagination(p, tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var models []JoinToken
+	if err := preloadJoinTokenSelectors(tx).Find(&models).Error; err != nil {
+		return nil, sqlcommon.NewWrappedSQLError(err)
+	}
+
+	if p != nil {
+		p.Token = ""
+		if len(models) > 0 {
+			p.Token = fmt.Sprint(models[len(models)-1].ID)
+		}
+	}
+
+	resp := &datastore.ListJoinTokensResponse{
+		Pagination: p,
+		JoinTokens: []*datastore.JoinToken{},
+	}
+	for _, model := range models {
+		resp.JoinTokens = append(resp.JoinTokens, modelToJoinToken(model))
+	}
+
+	return resp, nil
+}
+
 func deleteJoinToken(tx *gorm.DB, token string) error {
 	var model JoinToken
 	if err := tx.Find(&model, "token = ?", token).Error; err != nil {
@@ -4406,10 +4848,18 @@ func deleteJoinToken(tx *gorm.DB, token string) error {
 		return sqlcommon.NewSQLError("expected to delete one row, but %d rows were affected", result.RowsAffected)
 	}
 
+	if err := tx.Exec("DELETE FROM join_token_selectors WHERE join_token_id = ?", model.ID).Error; err != nil {
+		return sqlcommon.NewWrappedSQLError(err)
+	}
+
 	return nil
 }
 
 func pruneJoinTokens(tx *gorm.DB, expiresBefore time.Time) error {
+	if err := tx.Exec("DELETE FROM join_token_selectors WHERE join_token_id IN (SELECT id FROM join_tokens WHERE expiry < ?)", expiresBefore.Unix()).Error; err != nil {
+		return sqlcommon.NewWrappedSQLError(err)
+	}
+
 	if err := tx.Where("expiry < ?", expiresBefore.Unix()).Delete(&JoinToken{}).Error; err != nil {
 		return sqlcommon.NewWrappedSQLError(err)
 	}
@@ -4428,6 +4878,14 @@ func createFederationRelationship(tx *gorm.DB, fr *datastore.FederationRelations
 		model.EndpointSPIFFEID = fr.EndpointSPIFFEID.String()
 	}
 
+	if fr.Options != nil {
+		options, err := proto.Marshal(fr.Options)
+		if err != nil {
+			return nil, sqlcommon.NewWrappedSQLError(err)
+		}
+		model.Options = options
+	}
+
 	if fr.TrustDomainBundle != nil {
 		// overwrite current bundle
 		_, err := setBundle(tx, fr.TrustDomainBundle)
@@ -4451,9 +4909,91 @@ func deleteFederationRelationship(tx *gorm.DB, trustDomain spiffeid.TrustDomain)
 	if err := tx.Delete(model).Error; err != nil {
 		return sqlcommon.NewWrappedSQLError(err)
 	}
+
+	// A bundle update quarantined for the relationship is no longer pending
+	if err := tx.Where("trust_domain = ?", trustDomain.IDString()).Delete(&QuarantinedBundle{}).Error; err != nil {
+		return sqlcommon.NewWrappedSQLError(err)
+	}
+	return nil
+}
+
+func setQuarantinedBundle(tx *gorm.DB, qb *common.QuarantinedBundle) (*common.QuarantinedBundle, error) {
+	newModel, err := quarantinedBundleToModel(qb)
+	if err != nil {
+		return nil, err
+	}
+
+	model := new(QuarantinedBundle)
+	result := tx.Find(model, "trust_domain = ?", newModel.TrustDomain)
+	switch {
+	case result.RecordNotFound():
+		if err := tx.Create(newModel).Error; err != nil {
+			return nil, sqlcommon.NewWrappedSQLError(err)
+		}
+	case result.Error != nil:
+		return nil, sqlcommon.NewWrappedSQLError(result.Error)
+	default:
+		if err := tx.Model(model).Update("data", newModel.Data).Error; err != nil {
+			return nil, sqlcommon.NewWrappedSQLError(err)
+		}
+	}
+
+	return qb, nil
+}
+
+func fetchQuarantinedBundle(tx *gorm.DB, trustDomainID string) (*common.QuarantinedBundle, error) {
+	model := new(QuarantinedBundle)
+	err := tx.Find(model, "trust_domain = ?", trustDomainID).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, sqlcommon.NewWrappedSQLError(err)
+	}
+
+	return modelToQuarantinedBundle(model)
+}
+
+func deleteQuarantinedBundle(tx *gorm.DB, trustDomainID string) error {
+	model := new(QuarantinedBundle)
+	if err := tx.Find(model, "trust_domain = ?", trustDomainID).Error; err != nil {
+		return sqlcommon.NewWrappedSQLError(err)
+	}
+	if err := tx.Delete(model).Error; err != nil {
+		return sqlcommon.NewWrappedSQLError(err)
+	}
 	return nil
 }
 
+func quarantinedBundleToModel(qb *common.QuarantinedBundle) (*QuarantinedBundle, error) {
+	if qb == nil {
+		return nil, sqlcommon.NewSQLError("missing quarantined bundle in request")
+	}
+	if qb.TrustDomainId == "" {
+		return nil, sqlcommon.NewValidationError("invalid quarantined bundle: missing trust domain")
+	}
+	if qb.Bundle == nil {
+		return nil, sqlcommon.NewValidationError("invalid quarantined bundle: missing bundle")
+	}
+	data, err := proto.Marshal(qb)
+	if err != nil {
+		return nil, sqlcommon.NewWrappedSQLError(err)
+	}
+
+	return &QuarantinedBundle{
+		TrustDomain: qb.TrustDomainId,
+		Data:        data,
+	}, nil
+}
+
+func modelToQuarantinedBundle(model *QuarantinedBundle) (*common.QuarantinedBundle, error) {
+	qb := new(common.QuarantinedBundle)
+	if err := proto.Unmarshal(model.Data, qb); err != nil {
+		return nil, sqlcommon.NewWrappedSQLError(err)
+	}
+	return qb, nil
+}
+
 func fetchFederationRelationship(tx *gorm.DB, trustDomain spiffeid.TrustDomain) (*datastore.FederationRelationship, error) {
 	var model FederatedTrustDomain
 	err := tx.Find(&model, "trust_domain = ?", trustDomain.Name()).Error
@@ -4546,6 +5086,28 @@ func updateFederationRelationship(tx *gorm.DB, fr *datastore.FederationRelations
 	return modelToFederationRelationship(tx, &model)
 }
 
+func setFederationRelationshipOptions(tx *gorm.DB, trustDomain spiffeid.TrustDomain, options *common.FederationRelationshipOptions) (*datastore.FederationRelationship, error) {
+	var model FederatedTrustDomain
+	if err := tx.Find(&model, "trust_domain = ?", trustDomain.Name()).Error; err != nil {
+		return nil, sqlcommon.NewWrappedSQLError(err)
+	}
+
+	model.Options = nil
+	if options != nil {
+		data, err := proto.Marshal(options)
+		if err != nil {
+			return nil, sqlcommon.NewWrappedSQLError(err)
+		}
+		model.Options = data
+	}
+
+	if err := tx.Save(&model).Error; err != nil {
+		return nil, sqlcommon.NewWrappedSQLError(err)
+	}
+
+	return modelToFederationRelationship(tx, &model)
+}
+
 func validateFederationRelationship(fr *datastore.FederationRelationship, mask *types.FederationRelationshipMask) error {
 	if fr == nil {
 		return status.Error(codes.InvalidArgument, "federation relationship is nil")
@@ -4603,6 +5165,14 @@ func modelToFederationRelationship(tx *gorm.DB, model *FederatedTrustDomain) (*d
 		return nil, fmt.Errorf("unknown bundle endpoint profile type: %q", model.BundleEndpointProfile)
 	}
 
+	if len(model.Options) > 0 {
+		options := new(common.FederationRelationshipOptions)
+		if err := proto.Unmarshal(model.Options, options); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal federation relationship options: %w", err)
+		}
+		fr.Options = options
+	}
+
 	trustDomainBundle, err := fetchBundle(tx, td.IDString())
 	if err != nil {
 		return nil, fmt.Errorf("unable to fetch bundle: %w", err)
@@ -4639,6 +5209,34 @@ func marshalAndValidateAdditionalAttributes(additionalAttributes *common.Registr
 	return marshaledAdditionalAttributes, nil
 }
 
+func marshalAndValidateStatusReport(statusReport *common.AgentStatusReport) ([]byte, error) {
+	if statusReport == nil {
+		return nil, nil
+	}
+
+	marshaledStatusReport, err := proto.Marshal(statusReport)
+	if err != nil {
+		return nil, sqlcommon.NewValidationError("invalid status report: %s", err)
+	}
+	if len(marshaledStatusReport) > maxStatusReportSize {
+		return nil, sqlcommon.NewValidationError("invalid attested node: status report size exceeds the maximum allowed size of %d bytes", maxStatusReportSize)
+	}
+
+	return marshaledStatusReport, nil
+}
+
+func unmarshalStatusReport(data []byte) (*common.AgentStatusReport, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	statusReport := new(common.AgentStatusReport)
+	if err := proto.Unmarshal(data, statusReport); err != nil {
+		return nil, sqlcommon.NewSQLError("could not parse status report: %s", err)
+	}
+	return statusReport, nil
+}
+
 func validateRegistrationEntry(entry *common.RegistrationEntry) error {
 	if entry == nil {
 		return sqlcommon.NewValidationError("invalid request: missing registered entry")
@@ -4831,7 +5429,11 @@ func newRegistrationEntryID() (string, error) {
 	return u.String(), nil
 }
 
-func modelToAttestedNode(model AttestedNode) *common.AttestedNode {
+func modelToAttestedNode(model AttestedNode) (*common.AttestedNode, error) {
+	statusReport, err := unmarshalStatusReport(model.StatusReport)
+	if err != nil {
+		return nil, err
+	}
 	return &common.AttestedNode{
 		SpiffeId:            model.SpiffeID,
 		AttestationDataType: model.DataType,
@@ -4841,13 +5443,26 @@ func modelToAttestedNode(model AttestedNode) *common.AttestedNode {
 		NewCertNotAfter:     nullableDBTimeToUnixTime(model.NewExpiresAt),
 		CanReattest:         model.CanReattest,
 		AgentVersion:        model.AgentVersion,
-	}
+		LastSeenAt:          nullableDBTimeToUnixTime(model.LastSeenAt),
+		StatusReport:        statusReport,
+	}, nil
 }
 
 func modelToJoinToken(model JoinToken) *datastore.JoinToken {
+	var selectors []*common.Selector
+	for _, selector := range model.Selectors {
+		selectors = append(selectors, &common.Selector{
+			Type:  selector.Type,
+			Value: selector.Value,
+		})
+	}
 	return &datastore.JoinToken{
-		Token:  model.Token,
-		Expiry: time.Unix(model.Expiry, 0),
+		Token:             model.Token,
+		Expiry:            time.Unix(model.Expiry, 0),
+		MaxUses:           model.MaxUses,
+		UseCount:          model.UseCount,
+		Selectors:         selectors,
+		AgentPathTemplate: model.AgentPathTemplate,
 	}
 }
 
@@ -5082,6 +5697,39 @@ func fetchCAJournal(tx *gorm.DB, activeX509AuthorityID string) (*datastore.CAJou
 	return modelToCAJournal(model), nil
 }
 
+func listCAJournals(tx *gorm.DB, req *datastore.ListCAJournalsRequest) (*datastore.ListCAJournalsResponse, error) {
+	p := req.Pagination
+	var err error
+	if p != nil {
+		tx, err = applyPagination(p, tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var models []CAJournal
+	if err := tx.Find(&models).Error; err != nil {
+		return nil, sqlcommon.NewWrappedSQLError(err)
+	}
+
+	if p != nil {
+		p.Token = ""
+		if len(models) > 0 {
+			p.Token = fmt.Sprint(models[len(models)-1].ID)
+		}
+	}
+
+	resp := &datastore.ListCAJournalsResponse{
+		Pagination: p,
+		CAJournals: []*datastore.CAJournal{},
+	}
+	for _, model := range models {
+		resp.CAJournals = append(resp.CAJournals, modelToCAJournal(model))
+	}
+
+	return resp, nil
+}
+
 func listCAJournalsForTesting(tx *gorm.DB) (caJournals []*datastore.CAJournal, err error) {
 	var caJournalsModel []CAJournal
 	if err := tx.Find(&caJournalsModel).Error; err != nil {
@@ -5222,6 +5870,40 @@ func buildPlaceholders(args []string) string {
 	return strings.Join(placeholders, ",")
 }
 
+// buildIDPrefixesCondition builds a condition matching the SPIFFE IDs in the
+// given column that are equal to or a descendant of one of the prefixes.
+// SUBSTR is used instead of LIKE so the prefixes do not need to be escaped.
+func buildIDPrefixesCondition(column string, prefixes []string) (string, []any) {
+	conditions := make([]string, 0, len(prefixes))
+	args := make([]any, 0, 3*len(prefixes))
+	for _, prefix := range prefixes {
+		conditions = append(conditions, fmt.Sprintf("%s = ? OR SUBSTR(%s, 1, ?) = ?", column, column))
+		args = append(args, prefix, len(prefix)+1, prefix+"/")
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+// buildEntryScopesQuery builds a query selecting the IDs of the registration
+// entries within at least one of the scopes.
+func buildEntryScopesQuery(scopes []datastore.EntryScope) (string, []any) {
+	var args []any
+	conditions := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		parts := []string{"true"}
+		if scope.SPIFFEIDPrefix != "" {
+			condition, prefixArgs := buildIDPrefixesCondition("spiffe_id", []string{scope.SPIFFEIDPrefix})
+			parts = append(parts, condition)
+			args = append(args, prefixArgs...)
+		}
+		if scope.ParentID != "" {
+			parts = append(parts, "parent_id = ?")
+			args = append(args, scope.ParentID)
+		}
+		conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "SELECT id AS e_id FROM registered_entries WHERE admin = false AND downstream = false AND (" + strings.Join(conditions, " OR ") + ")", args
+}
+
 // buildArgs convert as slice of strings to a slice of any
 func buildArgs(args []string) []any {
 	anyArgs := make([]any, 0, len(args))
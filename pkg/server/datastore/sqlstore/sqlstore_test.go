@@ -266,6 +266,22 @@ func TestMigration(t *testing.T) {
 			case 24:
 				// Migration from v24 to v25 adds additional_attributes column
 				prepareDB(true)
+			case 25:
+				// Migration from v25 to v26 adds the join token usage columns
+				// and join_token_selectors table
+				prepareDB(true)
+			case 26:
+				// Migration from v26 to v27 adds the last_seen_at and
+				// status_report columns to attested_node_entries
+				prepareDB(true)
+			case 27:
+				// Migration from v27 to v28 adds the quarantined_bundles
+				// table
+				prepareDB(true)
+			case 28:
+				// Migration from v28 to v29 adds the options column to
+				// federated_trust_domains
+				prepareDB(true)
 			default:
 				t.Fatalf("no migration test added for schema version %d", schemaVersion)
 			}
@@ -899,6 +899,139 @@ func (s *Suite) TestRevokeJWTKey() {
 	validateBundle(expectedJWTKeys, 1)
 }
 
+func (s *Suite) TestTaintWITKey() {
+	t := s.T()
+	// Setup
+	// Create new bundle with two WIT Keys
+	bundle := bundleutil.BundleProtoFromRootCAs("spiffe://foo", nil)
+	originalKeys := []*common.PublicKey{
+		{Kid: "key1"},
+		{Kid: "key2"},
+		{Kid: "key2"},
+	}
+	bundle.WitSigningKeys = originalKeys
+
+	// Bundle not found
+	publicKey, err := s.ds.TaintWITKey(ctx, "spiffe://foo", "key1")
+	spiretest.RequireGRPCStatus(t, err, codes.NotFound, _notFoundErrMsg)
+	require.Nil(t, publicKey)
+
+	_, err = s.ds.CreateBundle(ctx, bundle)
+	require.NoError(t, err)
+
+	// Bundle contains repeated key
+	publicKey, err = s.ds.TaintWITKey(ctx, "spiffe://foo", "key2")
+	spiretest.RequireGRPCStatus(t, err, codes.Internal, "another WIT Key found with the same KeyID")
+	require.Nil(t, publicKey)
+
+	// Key not found
+	publicKey, err = s.ds.TaintWITKey(ctx, "spiffe://foo", "no id")
+	spiretest.RequireGRPCStatus(t, err, codes.NotFound, "no WIT Key found with provided key ID")
+	require.Nil(t, publicKey)
+
+	validateBundle := func(expectedKeys []*common.PublicKey, expectSequenceNumber uint64) {
+		fetchedBundle, err := s.ds.FetchBundle(ctx, "spiffe://foo")
+		require.NoError(t, err)
+
+		spiretest.RequireProtoListEqual(t, expectedKeys, fetchedBundle.WitSigningKeys)
+		require.Equal(t, expectSequenceNumber, fetchedBundle.SequenceNumber)
+	}
+
+	// Validate no changes
+	validateBundle(originalKeys, 0)
+
+	// Taint successfully
+	publicKey, err = s.ds.TaintWITKey(ctx, "spiffe://foo", "key1")
+	require.NoError(t, err)
+	require.NotNil(t, publicKey)
+
+	taintedKey := []*common.PublicKey{
+		{Kid: "key1", TaintedKey: true},
+		{Kid: "key2"},
+		{Kid: "key2"},
+	}
+	// Validate expected response
+	validateBundle(taintedKey, 1)
+
+	// No able to taint Key again
+	publicKey, err = s.ds.TaintWITKey(ctx, "spiffe://foo", "key1")
+	spiretest.RequireGRPCStatus(t, err, codes.InvalidArgument, "key is already tainted")
+	require.Nil(t, publicKey)
+
+	// No changes
+	validateBundle(taintedKey, 1)
+}
+
+func (s *Suite) TestRevokeWITKey() {
+	t := s.T()
+	// Setup
+	// Create new bundle with two WIT Keys
+	bundle := bundleutil.BundleProtoFromRootCAs("spiffe://foo", nil)
+	bundle.WitSigningKeys = []*common.PublicKey{
+		{Kid: "key1"},
+		{Kid: "key2"},
+	}
+
+	// Bundle not found
+	publicKey, err := s.ds.RevokeWITKey(ctx, "spiffe://foo", "key1")
+	spiretest.RequireGRPCStatus(t, err, codes.NotFound, _notFoundErrMsg)
+	require.Nil(t, publicKey)
+
+	_, err = s.ds.CreateBundle(ctx, bundle)
+	require.NoError(t, err)
+
+	// Key not found
+	publicKey, err = s.ds.RevokeWITKey(ctx, "spiffe://foo", "no id")
+	spiretest.RequireGRPCStatus(t, err, codes.NotFound, "no WIT Key found with provided key ID")
+	require.Nil(t, publicKey)
+
+	// No allow to revoke untainted key
+	publicKey, err = s.ds.RevokeWITKey(ctx, "spiffe://foo", "key1")
+	spiretest.RequireGRPCStatus(t, err, codes.InvalidArgument, "it is not possible to revoke an untainted key")
+	require.Nil(t, publicKey)
+
+	// Add a duplicated key and taint it
+	bundle.WitSigningKeys = []*common.PublicKey{
+		{Kid: "key1"},
+		{Kid: "key2", TaintedKey: true},
+		{Kid: "key2", TaintedKey: true},
+	}
+	_, err = s.ds.UpdateBundle(ctx, bundle, nil)
+	require.NoError(t, err)
+
+	// No allow to revoke because a duplicated key is found
+	publicKey, err = s.ds.RevokeWITKey(ctx, "spiffe://foo", "key2")
+	spiretest.RequireGRPCStatus(t, err, codes.Internal, "another key found with the same KeyID")
+	require.Nil(t, publicKey)
+
+	// Remove duplicated key
+	originalKeys := []*common.PublicKey{
+		{Kid: "key1"},
+		{Kid: "key2", TaintedKey: true},
+	}
+	bundle.WitSigningKeys = originalKeys
+	_, err = s.ds.UpdateBundle(ctx, bundle, nil)
+	require.NoError(t, err)
+
+	validateBundle := func(expectedKeys []*common.PublicKey, expectSequenceNumber uint64) {
+		fetchedBundle, err := s.ds.FetchBundle(ctx, "spiffe://foo")
+		require.NoError(t, err)
+
+		spiretest.RequireProtoListEqual(t, expectedKeys, fetchedBundle.WitSigningKeys)
+		require.Equal(t, expectSequenceNumber, fetchedBundle.SequenceNumber)
+	}
+
+	validateBundle(originalKeys, 0)
+
+	// Revoke successfully
+	publicKey, err = s.ds.RevokeWITKey(ctx, "spiffe://foo", "key2")
+	require.NoError(t, err)
+	require.Equal(t, &common.PublicKey{Kid: "key2", TaintedKey: true}, publicKey)
+
+	expectedWITKeys := []*common.PublicKey{{Kid: "key1"}}
+	validateBundle(expectedWITKeys, 1)
+}
+
 func (s *Suite) TestCreateAttestedNode() {
 	node := &common.AttestedNode{
 		SpiffeId:            "foo",
@@ -1365,6 +1498,202 @@ func (s *Suite) TestListAttestedNodes() {
 	}
 }
 
+func (s *Suite) TestListAttestedNodesByLastSeen() {
+	now := time.Now().Truncate(time.Second)
+
+	createNode := func(node *common.AttestedNode) *common.AttestedNode {
+		_, err := s.ds.CreateAttestedNode(ctx, node)
+		s.Require().NoError(err)
+		return node
+	}
+
+	neverSeen := createNode(&common.AttestedNode{
+		SpiffeId:            "spiffe://example.org/never-seen",
+		AttestationDataType: "t",
+		CertSerialNumber:    "1",
+		CertNotAfter:        now.Add(time.Hour).Unix(),
+	})
+	seenLongAgo := createNode(&common.AttestedNode{
+		SpiffeId:            "spiffe://example.org/seen-long-ago",
+		AttestationDataType: "t",
+		CertSerialNumber:    "2",
+		CertNotAfter:        now.Add(time.Hour).Unix(),
+		LastSeenAt:          now.Add(-time.Hour).Unix(),
+	})
+	seenRecently := createNode(&common.AttestedNode{
+		SpiffeId:            "spiffe://example.org/seen-recently",
+		AttestationDataType: "t",
+		CertSerialNumber:    "3",
+		CertNotAfter:        now.Add(time.Hour).Unix(),
+		LastSeenAt:          now.Add(-time.Minute).Unix(),
+	})
+
+	for _, tt := range []struct {
+		name             string
+		byLastSeenBefore time.Time
+		byLastSeenAfter  time.Time
+		expected         []*common.AttestedNode
+	}{
+		{
+			name:     "no filter",
+			expected: []*common.AttestedNode{neverSeen, seenLongAgo, seenRecently},
+		},
+		{
+			name:             "seen before",
+			byLastSeenBefore: now.Add(-30 * time.Minute),
+			expected:         []*common.AttestedNode{seenLongAgo},
+		},
+		{
+			name:            "seen after",
+			byLastSeenAfter: now.Add(-30 * time.Minute),
+			expected:        []*common.AttestedNode{seenRecently},
+		},
+		{
+			name:             "seen before and after",
+			byLastSeenBefore: now,
+			byLastSeenAfter:  now.Add(-2 * time.Hour),
+			expected:         []*common.AttestedNode{seenLongAgo, seenRecently},
+		},
+	} {
+		s.T().Run(tt.name, func(t *testing.T) {
+			resp, err := s.ds.ListAttestedNodes(ctx, &datastore.ListAttestedNodesRequest{
+				ByLastSeenBefore: tt.byLastSeenBefore,
+				ByLastSeenAfter:  tt.byLastSeenAfter,
+			})
+			require.NoError(t, err)
+			spiretest.AssertProtoListEqual(t, tt.expected, resp.Nodes)
+
+			count, err := s.ds.CountAttestedNodes(ctx, &datastore.CountAttestedNodesRequest{
+				ByLastSeenBefore: tt.byLastSeenBefore,
+				ByLastSeenAfter:  tt.byLastSeenAfter,
+			})
+			require.NoError(t, err)
+			require.Equal(t, int32(len(tt.expected)), count)
+		})
+	}
+}
+
+func (s *Suite) TestListAttestedNodesBySpiffeIDPrefixes() {
+	var nodes []*common.AttestedNode
+	for i, id := range []string{
+		"spiffe://example.org/spire/agent/team-a",
+		"spiffe://example.org/spire/agent/team-a/node",
+		"spiffe://example.org/spire/agent/team-ab/node",
+		"spiffe://example.org/spire/agent/team-b/node",
+		"spiffe://other.org/spire/agent/team-a/node",
+	} {
+		node := &common.AttestedNode{
+			SpiffeId:            id,
+			AttestationDataType: "t",
+			CertSerialNumber:    strconv.Itoa(i + 1),
+			CertNotAfter:        time.Now().Add(time.Hour).Unix(),
+		}
+		_, err := s.ds.CreateAttestedNode(ctx, node)
+		s.Require().NoError(err)
+		nodes = append(nodes, node)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		prefixes []string
+		expected []*common.AttestedNode
+	}{
+		{
+			name:     "no filter",
+			expected: nodes,
+		},
+		{
+			name:     "single prefix",
+			prefixes: []string{"spiffe://example.org/spire/agent/team-a"},
+			expected: nodes[:2],
+		},
+		{
+			name:     "multiple prefixes",
+			prefixes: []string{"spiffe://example.org/spire/agent/team-a/node", "spiffe://example.org/spire/agent/team-b"},
+			expected: []*common.AttestedNode{nodes[1], nodes[3]},
+		},
+		{
+			name:     "no match",
+			prefixes: []string{"spiffe://example.org/spire/agent/team-c"},
+		},
+	} {
+		s.T().Run(tt.name, func(t *testing.T) {
+			// List one node per page to check that pages are filtered by
+			// the query and not after it
+			var listed []*common.AttestedNode
+			req := &datastore.ListAttestedNodesRequest{
+				BySpiffeIDPrefixes: tt.prefixes,
+				Pagination:         &datastore.Pagination{PageSize: 1},
+			}
+			for {
+				resp, err := s.ds.ListAttestedNodes(ctx, req)
+				require.NoError(t, err)
+				if len(resp.Nodes) == 0 {
+					break
+				}
+				require.Len(t, resp.Nodes, 1)
+				listed = append(listed, resp.Nodes...)
+				req.Pagination = resp.Pagination
+			}
+			spiretest.AssertProtoListEqual(t, tt.expected, listed)
+
+			count, err := s.ds.CountAttestedNodes(ctx, &datastore.CountAttestedNodesRequest{
+				BySpiffeIDPrefixes: tt.prefixes,
+			})
+			require.NoError(t, err)
+			require.Equal(t, int32(len(tt.expected)), count)
+		})
+	}
+}
+
+func (s *Suite) TestUpdateAttestedNodeStatusDoesNotCreateEvents() {
+	node := &common.AttestedNode{
+		SpiffeId:            "spiffe://example.org/foo",
+		AttestationDataType: "t",
+		CertSerialNumber:    "1",
+		CertNotAfter:        time.Now().Add(time.Hour).Unix(),
+	}
+	_, err := s.ds.CreateAttestedNode(ctx, node)
+	s.Require().NoError(err)
+
+	listEvents := func() []datastore.AttestedNodeEvent {
+		resp, err := s.ds.ListAttestedNodeEvents(ctx, &datastore.ListAttestedNodeEventsRequest{})
+		s.Require().NoError(err)
+		return resp.Events
+	}
+	events := listEvents()
+
+	_, err = s.ds.UpdateAttestedNode(ctx, &common.AttestedNode{
+		SpiffeId:     node.SpiffeId,
+		LastSeenAt:   time.Now().Unix(),
+		StatusReport: &common.AgentStatusReport{Uptime: 1},
+	}, &common.AttestedNodeMask{LastSeenAt: true, StatusReport: true})
+	s.Require().NoError(err)
+	s.Require().Equal(events, listEvents())
+
+	_, err = s.ds.UpdateAttestedNode(ctx, &common.AttestedNode{
+		SpiffeId:     node.SpiffeId,
+		LastSeenAt:   time.Now().Unix(),
+		AgentVersion: "1.5.0",
+	}, &common.AttestedNodeMask{LastSeenAt: true, AgentVersion: true})
+	s.Require().NoError(err)
+	s.Require().Len(listEvents(), len(events)+1)
+
+	// Posting the same version again is not a change
+	events = listEvents()
+	_, err = s.ds.UpdateAttestedNode(ctx, &common.AttestedNode{
+		SpiffeId:     node.SpiffeId,
+		LastSeenAt:   time.Now().Unix(),
+		AgentVersion: "1.5.0",
+	}, &common.AttestedNodeMask{LastSeenAt: true, AgentVersion: true})
+	s.Require().NoError(err)
+	s.Require().Equal(events, listEvents())
+
+	updated, err := s.ds.FetchAttestedNode(ctx, node.SpiffeId)
+	s.Require().NoError(err)
+	s.Require().Equal("1.5.0", updated.AgentVersion)
+}
+
 func (s *Suite) TestUpdateAttestedNode() {
 	// Current nodes values
 	nodeID := "spiffe-id"
@@ -1479,6 +1808,42 @@ func (s *Suite) TestUpdateAttestedNode() {
 				AgentVersion:        "1.5.0",
 			},
 		},
+		{
+			name: "update attested node last seen and status report",
+			updateNode: &common.AttestedNode{
+				SpiffeId:   nodeID,
+				LastSeenAt: updatedExpires,
+				StatusReport: &common.AgentStatusReport{
+					Uptime: 60,
+					Plugins: []*common.AgentPlugin{
+						{Type: "KeyManager", Name: "disk", Version: "1.5.0"},
+					},
+					CachedX509Svids: 2,
+					LastSyncError:   "oh no",
+				},
+			},
+			updateNodeMask: &common.AttestedNodeMask{
+				LastSeenAt:   true,
+				StatusReport: true,
+			},
+			expUpdatedNode: &common.AttestedNode{
+				SpiffeId:            nodeID,
+				AttestationDataType: attestationType,
+				CertSerialNumber:    serial,
+				CertNotAfter:        expires,
+				NewCertNotAfter:     newExpires,
+				NewCertSerialNumber: newSerial,
+				LastSeenAt:          updatedExpires,
+				StatusReport: &common.AgentStatusReport{
+					Uptime: 60,
+					Plugins: []*common.AgentPlugin{
+						{Type: "KeyManager", Name: "disk", Version: "1.5.0"},
+					},
+					CachedX509Svids: 2,
+					LastSyncError:   "oh no",
+				},
+			},
+		},
 	} {
 		s.T().Run(tt.name, func(t *testing.T) {
 			s.ds = s.newDataStore()
@@ -4038,6 +4403,104 @@ func (s *Suite) TestListParentIDEntries() {
 	}
 }
 
+func (s *Suite) TestListEntriesByScopes() {
+	selectors := []*common.Selector{{Type: "a", Value: "1"}}
+	var entries []*common.RegistrationEntry
+	for _, entry := range []*common.RegistrationEntry{
+		{SpiffeId: "spiffe://example.org/team-a", ParentId: "spiffe://example.org/node-1"},
+		{SpiffeId: "spiffe://example.org/team-a/db", ParentId: "spiffe://example.org/node-1"},
+		{SpiffeId: "spiffe://example.org/team-a/web", ParentId: "spiffe://example.org/node-2"},
+		{SpiffeId: "spiffe://example.org/team-ab/db", ParentId: "spiffe://example.org/node-1"},
+		{SpiffeId: "spiffe://example.org/team-b/db", ParentId: "spiffe://example.org/node-2"},
+		{SpiffeId: "spiffe://example.org/team-a/admin", ParentId: "spiffe://example.org/node-1", Admin: true},
+		{SpiffeId: "spiffe://example.org/team-a/downstream", ParentId: "spiffe://example.org/node-1", Downstream: true},
+	} {
+		entry.Selectors = selectors
+		entries = append(entries, s.createRegistrationEntry(entry))
+	}
+
+	for _, tt := range []struct {
+		name       string
+		scopes     []datastore.EntryScope
+		byParentID string
+		expected   []*common.RegistrationEntry
+	}{
+		{
+			name:     "no filter",
+			expected: entries,
+		},
+		{
+			name:     "SPIFFE ID prefix",
+			scopes:   []datastore.EntryScope{{SPIFFEIDPrefix: "spiffe://example.org/team-a"}},
+			expected: entries[:3],
+		},
+		{
+			name:     "parent ID",
+			scopes:   []datastore.EntryScope{{ParentID: "spiffe://example.org/node-2"}},
+			expected: []*common.RegistrationEntry{entries[2], entries[4]},
+		},
+		{
+			name:     "SPIFFE ID prefix and parent ID",
+			scopes:   []datastore.EntryScope{{SPIFFEIDPrefix: "spiffe://example.org/team-a", ParentID: "spiffe://example.org/node-1"}},
+			expected: entries[:2],
+		},
+		{
+			name: "multiple scopes",
+			scopes: []datastore.EntryScope{
+				{SPIFFEIDPrefix: "spiffe://example.org/team-a/db"},
+				{SPIFFEIDPrefix: "spiffe://example.org/team-b"},
+			},
+			expected: []*common.RegistrationEntry{entries[1], entries[4]},
+		},
+		{
+			name:       "scopes and other filters",
+			scopes:     []datastore.EntryScope{{SPIFFEIDPrefix: "spiffe://example.org/team-a"}},
+			byParentID: "spiffe://example.org/node-2",
+			expected:   []*common.RegistrationEntry{entries[2]},
+		},
+		{
+			name:   "no match",
+			scopes: []datastore.EntryScope{{SPIFFEIDPrefix: "spiffe://example.org/team-c"}},
+		},
+	} {
+		s.T().Run(tt.name, func(t *testing.T) {
+			resp, err := s.ds.ListRegistrationEntries(ctx, &datastore.ListRegistrationEntriesRequest{
+				ByScopes:   tt.scopes,
+				ByParentID: tt.byParentID,
+			})
+			require.NoError(t, err)
+			spiretest.AssertProtoListEqual(t, tt.expected, resp.Entries)
+
+			// List one entry per page to check that pages are filtered by
+			// the query and not after it
+			var listed []*common.RegistrationEntry
+			req := &datastore.ListRegistrationEntriesRequest{
+				ByScopes:   tt.scopes,
+				ByParentID: tt.byParentID,
+				Pagination: &datastore.Pagination{PageSize: 1},
+			}
+			for {
+				resp, err := s.ds.ListRegistrationEntries(ctx, req)
+				require.NoError(t, err)
+				if len(resp.Entries) == 0 {
+					break
+				}
+				require.Len(t, resp.Entries, 1)
+				listed = append(listed, resp.Entries...)
+				req.Pagination = resp.Pagination
+			}
+			spiretest.AssertProtoListEqual(t, tt.expected, listed)
+
+			count, err := s.ds.CountRegistrationEntries(ctx, &datastore.CountRegistrationEntriesRequest{
+				ByScopes:   tt.scopes,
+				ByParentID: tt.byParentID,
+			})
+			require.NoError(t, err)
+			require.Equal(t, int32(len(tt.expected)), count)
+		})
+	}
+}
+
 func (s *Suite) TestListSelectorEntries() {
 	now := time.Now().Unix()
 	allEntries := make([]*common.RegistrationEntry, 0)
@@ -4886,6 +5349,165 @@ func (s *Suite) TestCreateAndFetchJoinToken() {
 	s.Require().NoError(err)
 	s.Equal("foobar", res.Token)
 	s.Equal(now, res.Expiry)
+
+	multiUseToken := &datastore.JoinToken{
+		Token:   "multi",
+		Expiry:  now,
+		MaxUses: 3,
+		Selectors: []*common.Selector{
+			{Type: "ci", Value: "runner"},
+			{Type: "ci", Value: "pool:a"},
+		},
+		AgentPathTemplate: "/join_token/ci/{{ .UseNumber }}",
+	}
+	err = s.ds.CreateJoinToken(ctx, multiUseToken)
+	s.Require().NoError(err)
+
+	res, err = s.ds.FetchJoinToken(ctx, multiUseToken.Token)
+	s.Require().NoError(err)
+	s.Equal(multiUseToken, res)
+}
+
+func (s *Suite) TestConsumeJoinToken() {
+	now := time.Now().Truncate(time.Second)
+	selectors := []*common.Selector{{Type: "ci", Value: "runner"}}
+
+	for _, tt := range []struct {
+		name      string
+		maxUses   int32
+		expectUse []int32
+	}{
+		{name: "single use", maxUses: 0, expectUse: []int32{1}},
+		{name: "one use", maxUses: 1, expectUse: []int32{1}},
+		{name: "multiple uses", maxUses: 3, expectUse: []int32{1, 2, 3}},
+	} {
+		s.Run(tt.name, func() {
+			token := &datastore.JoinToken{
+				Token:     "token-" + strings.ReplaceAll(tt.name, " ", "-"),
+				Expiry:    now.Add(time.Hour),
+				MaxUses:   tt.maxUses,
+				Selectors: selectors,
+			}
+			s.Require().NoError(s.ds.CreateJoinToken(ctx, token))
+
+			for _, useCount := range tt.expectUse {
+				resp, err := s.ds.ConsumeJoinToken(ctx, token.Token, now, nil)
+				s.Require().NoError(err)
+				s.Require().NotNil(resp)
+				s.Equal(useCount, resp.UseCount)
+				s.Equal(selectors, resp.Selectors)
+			}
+
+			// The token is deleted once it has no uses left
+			resp, err := s.ds.ConsumeJoinToken(ctx, token.Token, now, nil)
+			s.Require().NoError(err)
+			s.Nil(resp)
+
+			resp, err = s.ds.FetchJoinToken(ctx, token.Token)
+			s.Require().NoError(err)
+			s.Nil(resp)
+		})
+	}
+
+	s.Run("unlimited uses", func() {
+		token := &datastore.JoinToken{
+			Token:   "token-unlimited",
+			Expiry:  now.Add(time.Hour),
+			MaxUses: datastore.UnlimitedJoinTokenUses,
+		}
+		s.Require().NoError(s.ds.CreateJoinToken(ctx, token))
+
+		for i := range int32(5) {
+			resp, err := s.ds.ConsumeJoinToken(ctx, token.Token, now, nil)
+			s.Require().NoError(err)
+			s.Require().NotNil(resp)
+			s.Equal(i+1, resp.UseCount)
+		}
+
+		resp, err := s.ds.FetchJoinToken(ctx, token.Token)
+		s.Require().NoError(err)
+		s.Require().NotNil(resp)
+		s.Equal(int32(5), resp.UseCount)
+	})
+
+	s.Run("expired", func() {
+		token := &datastore.JoinToken{
+			Token:   "token-expired",
+			Expiry:  now,
+			MaxUses: 3,
+		}
+		s.Require().NoError(s.ds.CreateJoinToken(ctx, token))
+
+		resp, err := s.ds.ConsumeJoinToken(ctx, token.Token, now, nil)
+		s.Require().NoError(err)
+		s.Nil(resp)
+
+		// The use count is untouched
+		resp, err = s.ds.FetchJoinToken(ctx, token.Token)
+		s.Require().NoError(err)
+		s.Require().NotNil(resp)
+		s.Equal(int32(0), resp.UseCount)
+	})
+
+	s.Run("check fails", func() {
+		token := &datastore.JoinToken{
+			Token:  "token-check-fails",
+			Expiry: now.Add(time.Hour),
+		}
+		s.Require().NoError(s.ds.CreateJoinToken(ctx, token))
+
+		var checked *datastore.JoinToken
+		resp, err := s.ds.ConsumeJoinToken(ctx, token.Token, now, func(joinToken *datastore.JoinToken) error {
+			checked = joinToken
+			return status.Error(codes.PermissionDenied, "ohno")
+		})
+		s.RequireGRPCStatus(err, codes.PermissionDenied, "ohno")
+		s.Nil(resp)
+		s.Require().NotNil(checked)
+		s.Equal(int32(1), checked.UseCount)
+
+		// The use is undone, so the single use token is still usable
+		resp, err = s.ds.FetchJoinToken(ctx, token.Token)
+		s.Require().NoError(err)
+		s.Require().NotNil(resp)
+		s.Equal(int32(0), resp.UseCount)
+
+		resp, err = s.ds.ConsumeJoinToken(ctx, token.Token, now, func(*datastore.JoinToken) error {
+			return nil
+		})
+		s.Require().NoError(err)
+		s.Require().NotNil(resp)
+		s.Equal(int32(1), resp.UseCount)
+	})
+
+	s.Run("does not exist", func() {
+		resp, err := s.ds.ConsumeJoinToken(ctx, "does-not-exist", now, nil)
+		s.Require().NoError(err)
+		s.Nil(resp)
+	})
+}
+
+func (s *Suite) TestConsumeJoinTokenRace() {
+	token := &datastore.JoinToken{
+		Token:   "foobar",
+		Expiry:  time.Now().Add(time.Hour),
+		MaxUses: 3,
+	}
+	s.Require().NoError(s.ds.CreateJoinToken(ctx, token))
+
+	var attempts, uses atomic.Int32
+	testutil.RaceTest(s.T(), func(t *testing.T) {
+		attempts.Add(1)
+		resp, err := s.ds.ConsumeJoinToken(ctx, token.Token, time.Now(), nil)
+		require.NoError(t, err)
+		if resp != nil {
+			uses.Add(1)
+		}
+	})
+
+	// The token cannot be used more times than allowed, regardless of the
+	// number of concurrent attempts
+	s.Equal(min(token.MaxUses, attempts.Load()), uses.Load())
 }
 
 func (s *Suite) TestDeleteJoinToken() {
@@ -4923,8 +5545,9 @@ func (s *Suite) TestDeleteJoinToken() {
 func (s *Suite) TestPruneJoinTokens() {
 	now := time.Now().Truncate(time.Second)
 	joinToken := &datastore.JoinToken{
-		Token:  "foobar",
-		Expiry: now,
+		Token:     "foobar",
+		Expiry:    now,
+		Selectors: []*common.Selector{{Type: "ci", Value: "runner"}},
 	}
 
 	err := s.ds.CreateJoinToken(ctx, joinToken)
@@ -4956,6 +5579,46 @@ func (s *Suite) TestPruneJoinTokens() {
 	s.Nil(resp)
 }
 
+func (s *Suite) TestListJoinTokens() {
+	now := time.Now().Truncate(time.Second)
+	var expected []*datastore.JoinToken
+	for i := range 3 {
+		joinToken := &datastore.JoinToken{
+			Token:  fmt.Sprintf("token-%d", i),
+			Expiry: now.Add(time.Duration(i) * time.Hour),
+		}
+		s.Require().NoError(s.ds.CreateJoinToken(ctx, joinToken))
+		expected = append(expected, joinToken)
+	}
+
+	// List without pagination
+	resp, err := s.ds.ListJoinTokens(ctx, &datastore.ListJoinTokensRequest{})
+	s.Require().NoError(err)
+	s.Require().Equal(expected, resp.JoinTokens)
+	s.Require().Nil(resp.Pagination)
+
+	// List with pagination
+	pagination := &datastore.Pagination{PageSize: 2}
+	resp, err = s.ds.ListJoinTokens(ctx, &datastore.ListJoinTokensRequest{Pagination: pagination})
+	s.Require().NoError(err)
+	s.Require().Equal(expected[:2], resp.JoinTokens)
+	s.Require().NotEmpty(resp.Pagination.Token)
+
+	resp, err = s.ds.ListJoinTokens(ctx, &datastore.ListJoinTokensRequest{Pagination: resp.Pagination})
+	s.Require().NoError(err)
+	s.Require().Equal(expected[2:], resp.JoinTokens)
+	s.Require().NotEmpty(resp.Pagination.Token)
+
+	resp, err = s.ds.ListJoinTokens(ctx, &datastore.ListJoinTokensRequest{Pagination: resp.Pagination})
+	s.Require().NoError(err)
+	s.Require().Empty(resp.JoinTokens)
+	s.Require().Empty(resp.Pagination.Token)
+
+	// Invalid page size
+	_, err = s.ds.ListJoinTokens(ctx, &datastore.ListJoinTokensRequest{Pagination: &datastore.Pagination{}})
+	s.RequireGRPCStatus(err, codes.InvalidArgument, "cannot paginate with pagesize = 0")
+}
+
 func (s *Suite) TestDeleteFederationRelationship() {
 	testCases := []struct {
 		name        string
@@ -5006,6 +5669,104 @@ func (s *Suite) TestDeleteFederationRelationship() {
 	}
 }
 
+func (s *Suite) TestQuarantinedBundle() {
+	quarantined := &common.QuarantinedBundle{
+		TrustDomainId: "spiffe://foo",
+		Bundle:        bundleutil.BundleProtoFromRootCA("spiffe://foo", s.cert),
+		Violations:    []string{"no overlap with the current bundle"},
+		QuarantinedAt: 1000,
+	}
+	quarantined2 := &common.QuarantinedBundle{
+		TrustDomainId: "spiffe://foo",
+		Bundle:        bundleutil.BundleProtoFromRootCA("spiffe://foo", s.cacert),
+		Violations:    []string{"too many keys"},
+		QuarantinedAt: 2000,
+	}
+
+	// fetching a quarantined bundle that does not exist returns nil
+	qb, err := s.ds.FetchQuarantinedBundle(ctx, "spiffe://foo")
+	s.Require().NoError(err)
+	s.Require().Nil(qb)
+
+	// set the quarantined bundle and make sure it is created
+	_, err = s.ds.SetQuarantinedBundle(ctx, quarantined)
+	s.Require().NoError(err)
+	qb, err = s.ds.FetchQuarantinedBundle(ctx, "spiffe://foo")
+	s.Require().NoError(err)
+	s.RequireProtoEqual(quarantined, qb)
+
+	// set the quarantined bundle and make sure it is replaced
+	_, err = s.ds.SetQuarantinedBundle(ctx, quarantined2)
+	s.Require().NoError(err)
+	qb, err = s.ds.FetchQuarantinedBundle(ctx, "spiffe://foo")
+	s.Require().NoError(err)
+	s.RequireProtoEqual(quarantined2, qb)
+
+	// quarantined bundles must have a trust domain and a bundle
+	_, err = s.ds.SetQuarantinedBundle(ctx, &common.QuarantinedBundle{Bundle: quarantined.Bundle})
+	s.Require().EqualError(err, "rpc error: code = InvalidArgument desc = datastore-validation: invalid quarantined bundle: missing trust domain")
+	_, err = s.ds.SetQuarantinedBundle(ctx, &common.QuarantinedBundle{TrustDomainId: "spiffe://foo"})
+	s.Require().EqualError(err, "rpc error: code = InvalidArgument desc = datastore-validation: invalid quarantined bundle: missing bundle")
+
+	// delete the quarantined bundle
+	s.Require().NoError(s.ds.DeleteQuarantinedBundle(ctx, "spiffe://foo"))
+	qb, err = s.ds.FetchQuarantinedBundle(ctx, "spiffe://foo")
+	s.Require().NoError(err)
+	s.Require().Nil(qb)
+
+	// deleting a quarantined bundle that does not exist returns not found
+	err = s.ds.DeleteQuarantinedBundle(ctx, "spiffe://foo")
+	s.Require().EqualError(err, "rpc error: code = NotFound desc = datastore-sql: record not found")
+}
+
+func (s *Suite) TestDeleteFederationRelationshipDeletesQuarantinedBundle() {
+	td := spiffeid.RequireTrustDomainFromString("federated-td-web.org")
+	_, err := s.ds.CreateFederationRelationship(ctx, &datastore.FederationRelationship{
+		TrustDomain:           td,
+		BundleEndpointURL:     requireURLFromString(s.T(), "federated-td-web.org/bundleendpoint"),
+		BundleEndpointProfile: datastore.BundleEndpointWeb,
+	})
+	s.Require().NoError(err)
+	_, err = s.ds.SetQuarantinedBundle(ctx, &common.QuarantinedBundle{
+		TrustDomainId: td.IDString(),
+		Bundle:        bundleutil.BundleProtoFromRootCA(td.IDString(), s.cert),
+	})
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.ds.DeleteFederationRelationship(ctx, td))
+
+	qb, err := s.ds.FetchQuarantinedBundle(ctx, td.IDString())
+	s.Require().NoError(err)
+	s.Require().Nil(qb)
+}
+
+func (s *Suite) TestReleaseQuarantinedBundle() {
+	current := bundleutil.BundleProtoFromRootCA("spiffe://foo", s.cert)
+	released := bundleutil.BundleProtoFromRootCA("spiffe://foo", s.cacert)
+
+	// releasing without a quarantined bundle stores the bundle
+	bundle, err := s.ds.ReleaseQuarantinedBundle(ctx, current)
+	s.Require().NoError(err)
+	s.RequireProtoEqual(current, bundle)
+	s.RequireProtoEqual(current, s.fetchBundle("spiffe://foo"))
+
+	_, err = s.ds.SetQuarantinedBundle(ctx, &common.QuarantinedBundle{
+		TrustDomainId: "spiffe://foo",
+		Bundle:        released,
+	})
+	s.Require().NoError(err)
+
+	// releasing replaces the bundle and deletes the quarantined bundle
+	bundle, err = s.ds.ReleaseQuarantinedBundle(ctx, released)
+	s.Require().NoError(err)
+	s.RequireProtoEqual(released, bundle)
+	s.RequireProtoEqual(released, s.fetchBundle("spiffe://foo"))
+
+	qb, err := s.ds.FetchQuarantinedBundle(ctx, "spiffe://foo")
+	s.Require().NoError(err)
+	s.Require().Nil(qb)
+}
+
 func (s *Suite) TestCreateFederationRelationship() {
 	s.createBundle("spiffe://federated-td-spiffe.org")
 	s.createBundle("spiffe://federated-td-spiffe-with-bundle.org")
@@ -5626,6 +6387,58 @@ func (s *Suite) TestUpdateFederationRelationship() {
 	}
 }
 
+func (s *Suite) TestSetFederationRelationshipOptions() {
+	td := spiffeid.RequireTrustDomainFromString("federated-td-web.org")
+	options := &common.FederationRelationshipOptions{
+		BundleSafeguards: &common.BundleSafeguards{
+			RequireOverlap: true,
+			MaxKeys:        10,
+		},
+	}
+
+	// setting the options of a relationship that does not exist fails
+	_, err := s.ds.SetFederationRelationshipOptions(ctx, td, options)
+	s.Require().EqualError(err, "rpc error: code = NotFound desc = datastore-sql: record not found")
+
+	// options set on creation are returned
+	_, err = s.ds.CreateFederationRelationship(ctx, &datastore.FederationRelationship{
+		TrustDomain:           td,
+		BundleEndpointURL:     requireURLFromString(s.T(), "federated-td-web.org/bundleendpoint"),
+		BundleEndpointProfile: datastore.BundleEndpointWeb,
+		Options:               options,
+	})
+	s.Require().NoError(err)
+	fr, err := s.ds.FetchFederationRelationship(ctx, td)
+	s.Require().NoError(err)
+	s.RequireProtoEqual(options, fr.Options)
+
+	// options are replaced
+	newOptions := &common.FederationRelationshipOptions{
+		BundleSafeguards: &common.BundleSafeguards{
+			PinnedKeys: []string{"0102"},
+		},
+	}
+	fr, err = s.ds.SetFederationRelationshipOptions(ctx, td, newOptions)
+	s.Require().NoError(err)
+	s.RequireProtoEqual(newOptions, fr.Options)
+
+	// options are kept when the relationship is updated
+	_, err = s.ds.UpdateFederationRelationship(ctx, &datastore.FederationRelationship{
+		TrustDomain:       td,
+		BundleEndpointURL: requireURLFromString(s.T(), "federated-td-web.org/other"),
+	}, &types.FederationRelationshipMask{BundleEndpointUrl: true})
+	s.Require().NoError(err)
+	resp, err := s.ds.ListFederationRelationships(ctx, &datastore.ListFederationRelationshipsRequest{})
+	s.Require().NoError(err)
+	s.Require().Len(resp.FederationRelationships, 1)
+	s.RequireProtoEqual(newOptions, resp.FederationRelationships[0].Options)
+
+	// options are cleared
+	fr, err = s.ds.SetFederationRelationshipOptions(ctx, td, nil)
+	s.Require().NoError(err)
+	s.Require().Nil(fr.Options)
+}
+
 func (s *Suite) TestRace() {
 	next := int64(0)
 	exp := time.Now().Add(time.Hour).Unix()
@@ -5784,6 +6597,44 @@ func (s *Suite) TestPruneCAJournal() {
 	s.Require().Nil(caj)
 }
 
+func (s *Suite) TestListCAJournals() {
+	var expected []*datastore.CAJournal
+	for i := range 3 {
+		caJournal, err := s.ds.SetCAJournal(ctx, &datastore.CAJournal{
+			ActiveX509AuthorityID: fmt.Sprintf("x509-authority-%d", i),
+			Data:                  fmt.Appendf(nil, "data-%d", i),
+		})
+		s.Require().NoError(err)
+		expected = append(expected, caJournal)
+	}
+
+	// List without pagination
+	resp, err := s.ds.ListCAJournals(ctx, &datastore.ListCAJournalsRequest{})
+	s.Require().NoError(err)
+	s.Require().Equal(expected, resp.CAJournals)
+	s.Require().Nil(resp.Pagination)
+
+	// List with pagination
+	pagination := &datastore.Pagination{PageSize: 2}
+	resp, err = s.ds.ListCAJournals(ctx, &datastore.ListCAJournalsRequest{Pagination: pagination})
+	s.Require().NoError(err)
+	s.Require().Equal(expected[:2], resp.CAJournals)
+	s.Require().NotEmpty(resp.Pagination.Token)
+
+	resp, err = s.ds.ListCAJournals(ctx, &datastore.ListCAJournalsRequest{Pagination: resp.Pagination})
+	s.Require().NoError(err)
+	s.Require().Equal(expected[2:], resp.CAJournals)
+
+	resp, err = s.ds.ListCAJournals(ctx, &datastore.ListCAJournalsRequest{Pagination: resp.Pagination})
+	s.Require().NoError(err)
+	s.Require().Empty(resp.CAJournals)
+	s.Require().Empty(resp.Pagination.Token)
+
+	// Invalid page size
+	_, err = s.ds.ListCAJournals(ctx, &datastore.ListCAJournalsRequest{Pagination: &datastore.Pagination{}})
+	s.RequireGRPCStatus(err, codes.InvalidArgument, "cannot paginate with pagesize = 0")
+}
+
 // getTestDataFromJSONFile reads a JSON fixture using a path relative to the
 // test binary's working directory. Go sets that directory to the package dir
 // of the package whose test invoked sqltest.Run — so any package consuming this
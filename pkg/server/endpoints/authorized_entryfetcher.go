@@ -9,6 +9,7 @@ import (
 	"github.com/andres-erbsen/clock"
 	"github.com/sirupsen/logrus"
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
 	"github.com/spiffe/spire/pkg/common/telemetry"
 	"github.com/spiffe/spire/pkg/server/api"
 	"github.com/spiffe/spire/pkg/server/authorizedentries"
@@ -16,7 +17,10 @@ import (
 	"github.com/spiffe/spire/pkg/server/datastore"
 )
 
-var _ api.AuthorizedEntryFetcher = (*AuthorizedEntryFetcherEvents)(nil)
+var (
+	_ api.AuthorizedEntryFetcher = (*AuthorizedEntryFetcherEvents)(nil)
+	_ api.AgentSelectorsCache    = (*AuthorizedEntryFetcherEvents)(nil)
+)
 
 const pageSize = 10000
 
@@ -78,6 +82,16 @@ func (a *AuthorizedEntryFetcherEvents) FetchAuthorizedEntries(_ context.Context,
 	return cache.GetAuthorizedEntries(agentID), nil
 }
 
+// LookupAgentSelectors returns the selectors of the agent from the in-memory
+// cache, and whether the agent is in the cache.
+func (a *AuthorizedEntryFetcherEvents) LookupAgentSelectors(agentID spiffeid.ID) ([]*types.Selector, bool) {
+	a.mu.RLock()
+	cache := a.cache
+	a.mu.RUnlock()
+
+	return cache.LookupAgentSelectors(agentID)
+}
+
 // RunUpdateCacheTask starts a ticker which rebuilds the in-memory entry cache.
 func (a *AuthorizedEntryFetcherEvents) RunUpdateCacheTask(ctx context.Context) error {
 	var fullCacheReload bool
@@ -4,6 +4,7 @@ import (
 	"net"
 	"time"
 
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	"github.com/spiffe/spire/pkg/common/diskcertmanager"
 )
 
@@ -18,4 +19,17 @@ type EndpointConfig struct {
 	DiskCertManager *diskcertmanager.DiskCertManager
 
 	RefreshHint time.Duration
+
+	// Hub is the federation hub configuration for the bundle endpoint.
+	// If unset, only the bundle of the local trust domain is served.
+	Hub *HubConfig
+}
+
+// HubConfig configures the bundle endpoint to re-serve the bundles of
+// federated trust domains held by the server, so other servers can federate
+// with many trust domains through a single endpoint.
+type HubConfig struct {
+	// TrustDomains are the federated trust domains whose bundles are
+	// re-published.
+	TrustDomains []spiffeid.TrustDomain
 }
@@ -6,11 +6,15 @@ import (
 	"crypto/x509"
 	"net"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spiffe/go-spiffe/v2/bundle/spiffebundle"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	"github.com/spiffe/spire/pkg/common/bundleutil"
+	"github.com/spiffe/spire/pkg/common/telemetry"
 	"github.com/spiffe/spire/pkg/common/tlspolicy"
 )
 
@@ -24,6 +28,24 @@ func (fn GetterFunc) GetBundle(ctx context.Context) (*spiffebundle.Bundle, error
 	return fn(ctx)
 }
 
+// hubPath is the path under which the bundles of federated trust domains are
+// served in hub mode. The bundles of all the re-published trust domains are
+// served at the path itself, and the bundle of each of them under
+// "<hubPath>/<trust domain>".
+const hubPath = "/federated"
+
+type FederatedGetter interface {
+	// GetFederatedBundle returns the bundle of a federated trust domain, or
+	// nil if the server holds no bundle for it.
+	GetFederatedBundle(ctx context.Context, td spiffeid.TrustDomain) (*spiffebundle.Bundle, error)
+}
+
+type FederatedGetterFunc func(ctx context.Context, td spiffeid.TrustDomain) (*spiffebundle.Bundle, error)
+
+func (fn FederatedGetterFunc) GetFederatedBundle(ctx context.Context, td spiffeid.TrustDomain) (*spiffebundle.Bundle, error) {
+	return fn(ctx, td)
+}
+
 type ServerAuth interface {
 	GetTLSConfig() *tls.Config
 }
@@ -36,6 +58,14 @@ type ServerConfig struct {
 	RefreshHint time.Duration
 	TLSPolicy   tlspolicy.Policy
 
+	// FederatedGetter retrieves the federated bundles re-published in hub
+	// mode. Hub mode is disabled when unset.
+	FederatedGetter FederatedGetter
+
+	// HubTrustDomains are the federated trust domains re-published in hub
+	// mode.
+	HubTrustDomains []spiffeid.TrustDomain
+
 	// test hooks
 	listen func(network, address string) (net.Listener, error)
 }
@@ -101,6 +131,16 @@ func (s *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
 		http.Error(w, "405 method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
+	if s.c.FederatedGetter != nil {
+		switch {
+		case req.URL.Path == hubPath:
+			s.serveHub(w, req)
+			return
+		case strings.HasPrefix(req.URL.Path, hubPath+"/"):
+			s.serveFederatedBundle(w, req, strings.TrimPrefix(req.URL.Path, hubPath+"/"))
+			return
+		}
+	}
 	if req.URL.Path != "/" {
 		http.NotFound(w, req)
 		return
@@ -129,6 +169,69 @@ func (s *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
 	_, _ = w.Write(jsonBytes)
 }
 
+func (s *Server) serveHub(w http.ResponseWriter, req *http.Request) {
+	var bundles []*spiffebundle.Bundle
+	for _, td := range s.c.HubTrustDomains {
+		b, err := s.c.FederatedGetter.GetFederatedBundle(req.Context(), td)
+		if err != nil {
+			s.c.Log.WithError(err).WithField(telemetry.TrustDomain, td).Error("Unable to retrieve federated bundle")
+			http.Error(w, "500 unable to retrieve federated bundles", http.StatusInternalServerError)
+			return
+		}
+		// Trust domains without a bundle yet are left out until the
+		// bundle is fetched.
+		if b != nil {
+			bundles = append(bundles, b)
+		}
+	}
+
+	jsonBytes, err := bundleutil.MarshalHub(bundles, s.c.RefreshHint)
+	if err != nil {
+		s.c.Log.WithError(err).Error("Unable to marshal federated bundles")
+		http.Error(w, "500 unable to marshal federated bundles", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(jsonBytes)
+}
+
+func (s *Server) serveFederatedBundle(w http.ResponseWriter, req *http.Request, name string) {
+	td, err := spiffeid.TrustDomainFromString(name)
+	if err != nil || !slices.Contains(s.c.HubTrustDomains, td) {
+		http.NotFound(w, req)
+		return
+	}
+
+	b, err := s.c.FederatedGetter.GetFederatedBundle(req.Context(), td)
+	if err != nil {
+		s.c.Log.WithError(err).WithField(telemetry.TrustDomain, td).Error("Unable to retrieve federated bundle")
+		http.Error(w, "500 unable to retrieve federated bundle", http.StatusInternalServerError)
+		return
+	}
+	if b == nil {
+		http.NotFound(w, req)
+		return
+	}
+
+	// Keep the refresh hint of the federated trust domain, since the bundle
+	// is refreshed from its endpoint at that pace.
+	var opts []bundleutil.MarshalOption
+	if refreshHint, ok := b.RefreshHint(); !ok || refreshHint == 0 {
+		opts = append(opts, bundleutil.OverrideRefreshHint(s.c.RefreshHint))
+	}
+
+	jsonBytes, err := bundleutil.Marshal(b, opts...)
+	if err != nil {
+		s.c.Log.WithError(err).Error("Unable to marshal federated bundle")
+		http.Error(w, "500 unable to marshal federated bundle", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(jsonBytes)
+}
+
 func chainDER(chain []*x509.Certificate) [][]byte {
 	var der [][]byte
 	for _, cert := range chain {
@@ -1,6 +1,7 @@
 package bundle
 
 import (
+	"bytes"
 	"context"
 	"crypto"
 	"crypto/tls"
@@ -22,6 +23,7 @@ import (
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/spiffe/go-spiffe/v2/bundle/spiffebundle"
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/common/bundleutil"
 	"github.com/spiffe/spire/pkg/common/diskcertmanager"
 	"github.com/spiffe/spire/pkg/common/pemutil"
 	"github.com/spiffe/spire/pkg/server/endpoints/bundle/internal/acmetest"
@@ -180,6 +182,141 @@ func TestServer(t *testing.T) {
 	}
 }
 
+func TestHubServer(t *testing.T) {
+	serverCert, serverKey := createServerCertificate(t)
+
+	td1 := spiffeid.RequireTrustDomainFromString("domain1.test")
+	td2 := spiffeid.RequireTrustDomainFromString("domain2.test")
+	td3 := spiffeid.RequireTrustDomainFromString("domain3.test")
+	td4 := spiffeid.RequireTrustDomainFromString("domain4.test")
+
+	bundle1 := spiffebundle.FromX509Authorities(td1, []*x509.Certificate{serverCert})
+	bundle1.SetRefreshHint(time.Minute)
+	bundle2 := spiffebundle.FromX509Authorities(td2, []*x509.Certificate{serverCert})
+	bundle4 := spiffebundle.FromX509Authorities(td4, []*x509.Certificate{serverCert})
+
+	// domain3.test is re-published but has no bundle yet, and domain4.test
+	// has a bundle but is not re-published.
+	federatedBundles := map[spiffeid.TrustDomain]*spiffebundle.Bundle{
+		td1: bundle1,
+		td2: bundle2,
+		td4: bundle4,
+	}
+	federatedGetter := FederatedGetterFunc(func(ctx context.Context, td spiffeid.TrustDomain) (*spiffebundle.Bundle, error) {
+		return federatedBundles[td], nil
+	})
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(serverCert)
+	client := http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs:    rootCAs,
+				MinVersion: tls.VersionTLS12,
+			},
+		},
+	}
+
+	addr, done := newTestServerWithConfig(t, ServerConfig{
+		Getter:          testGetter(nil),
+		ServerAuth:      testSPIFFEAuth(serverCert, serverKey),
+		RefreshHint:     5 * time.Minute,
+		FederatedGetter: federatedGetter,
+		HubTrustDomains: []spiffeid.TrustDomain{td1, td2, td3},
+	})
+	defer done()
+
+	get := func(addr net.Addr, path string) (int, []byte) {
+		resp, err := client.Get(fmt.Sprintf("https://%s%s", addr, path))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, body
+	}
+
+	t.Run("hub bundles", func(t *testing.T) {
+		status, body := get(addr, "/federated")
+		require.Equal(t, http.StatusOK, status)
+
+		bundles, err := bundleutil.DecodeHub(bytes.NewReader(body))
+		require.NoError(t, err)
+		require.Len(t, bundles, 2)
+		require.True(t, bundles[td1].Equal(bundle1))
+		require.Equal(t, bundle2.X509Authorities(), bundles[td2].X509Authorities())
+
+		// The bundle without a refresh hint gets the one of the endpoint
+		refreshHint, _ := bundles[td2].RefreshHint()
+		require.Equal(t, 5*time.Minute, refreshHint)
+	})
+
+	t.Run("federated bundle", func(t *testing.T) {
+		status, body := get(addr, "/federated/domain1.test")
+		require.Equal(t, http.StatusOK, status)
+
+		bundle, err := bundleutil.Unmarshal(td1, body)
+		require.NoError(t, err)
+		require.True(t, bundle.Equal(bundle1))
+	})
+
+	t.Run("federated bundle not held", func(t *testing.T) {
+		status, _ := get(addr, "/federated/domain3.test")
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("federated bundle not re-published", func(t *testing.T) {
+		status, _ := get(addr, "/federated/domain4.test")
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("invalid trust domain", func(t *testing.T) {
+		status, _ := get(addr, "/federated/Domain1.test")
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("fail to retrieve federated bundles", func(t *testing.T) {
+		addr, done := newTestServerWithConfig(t, ServerConfig{
+			Getter:     testGetter(nil),
+			ServerAuth: testSPIFFEAuth(serverCert, serverKey),
+			FederatedGetter: FederatedGetterFunc(func(ctx context.Context, td spiffeid.TrustDomain) (*spiffebundle.Bundle, error) {
+				return nil, errors.New("oh no")
+			}),
+			HubTrustDomains: []spiffeid.TrustDomain{td1},
+		})
+		defer done()
+
+		status, body := get(addr, "/federated")
+		require.Equal(t, http.StatusInternalServerError, status)
+		require.Equal(t, "500 unable to retrieve federated bundles\n", string(body))
+
+		status, body = get(addr, "/federated/domain1.test")
+		require.Equal(t, http.StatusInternalServerError, status)
+		require.Equal(t, "500 unable to retrieve federated bundle\n", string(body))
+	})
+}
+
+func TestHubServerDisabled(t *testing.T) {
+	serverCert, serverKey := createServerCertificate(t)
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(serverCert)
+	client := http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs:    rootCAs,
+				MinVersion: tls.VersionTLS12,
+			},
+		},
+	}
+
+	addr, done := newTestServer(t, testGetter(nil), testSPIFFEAuth(serverCert, serverKey), time.Minute)
+	defer done()
+
+	resp, err := client.Get(fmt.Sprintf("https://%s/federated", addr))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
 func TestDiskCertManagerAuth(t *testing.T) {
 	dir := spiretest.TempDir(t)
 	serverCert, serverKey := createServerCertificate(t)
@@ -359,6 +496,14 @@ func TestACMEAuth(t *testing.T) {
 }
 
 func newTestServer(t *testing.T, getter Getter, serverAuth ServerAuth, refreshHint time.Duration) (net.Addr, func()) {
+	return newTestServerWithConfig(t, ServerConfig{
+		Getter:      getter,
+		ServerAuth:  serverAuth,
+		RefreshHint: refreshHint,
+	})
+}
+
+func newTestServerWithConfig(t *testing.T, config ServerConfig) (net.Addr, func()) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	addrCh := make(chan net.Addr, 1)
@@ -372,14 +517,10 @@ func newTestServer(t *testing.T, getter Getter, serverAuth ServerAuth, refreshHi
 	}
 
 	log, _ := test.NewNullLogger()
-	server := NewServer(ServerConfig{
-		Log:         log,
-		Address:     "localhost:0",
-		Getter:      getter,
-		ServerAuth:  serverAuth,
-		listen:      listen,
-		RefreshHint: refreshHint,
-	})
+	config.Log = log
+	config.Address = "localhost:0"
+	config.listen = listen
+	server := NewServer(config)
 
 	errCh := make(chan error, 1)
 	go func() {
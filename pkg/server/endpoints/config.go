@@ -17,10 +17,13 @@ import (
 	"github.com/spiffe/spire/pkg/common/tlspolicy"
 	"github.com/spiffe/spire/pkg/server/api"
 	agentv1 "github.com/spiffe/spire/pkg/server/api/agent/v1"
+	agentstatusv1 "github.com/spiffe/spire/pkg/server/api/agentstatus/v1"
 	bundlev1 "github.com/spiffe/spire/pkg/server/api/bundle/v1"
 	debugv1 "github.com/spiffe/spire/pkg/server/api/debug/v1"
 	entryv1 "github.com/spiffe/spire/pkg/server/api/entry/v1"
+	federationv1 "github.com/spiffe/spire/pkg/server/api/federation/v1"
 	healthv1 "github.com/spiffe/spire/pkg/server/api/health/v1"
+	jointokenv1 "github.com/spiffe/spire/pkg/server/api/jointoken/v1"
 	localauthorityv1 "github.com/spiffe/spire/pkg/server/api/localauthority/v1"
 	loggerv1 "github.com/spiffe/spire/pkg/server/api/logger/v1"
 	svidv1 "github.com/spiffe/spire/pkg/server/api/svid/v1"
@@ -99,6 +102,10 @@ type Config struct {
 
 	AuditLogEnabled bool
 
+	// AuditLogger, when set, receives the audit log entries instead of the
+	// main logger.
+	AuditLogger *logrus.Logger
+
 	// ProxyProtocolTrustedCIDRs is a list of trusted CIDRs for PROXY protocol.
 	// When non-empty, PROXY protocol is enabled and only connections from
 	// these CIDRs are allowed to send PROXY headers.
@@ -145,6 +152,24 @@ func (c *Config) maybeMakeBundleEndpointServer() (Server, func(context.Context)
 	}
 
 	ds := c.Catalog.GetDataStore()
+
+	var federatedGetter bundle.FederatedGetter
+	var hubTrustDomains []spiffeid.TrustDomain
+	if c.BundleEndpoint.Hub != nil {
+		c.Log.WithField("trust_domains", c.BundleEndpoint.Hub.TrustDomains).Info("Serving federated bundles in hub mode")
+		federatedGetter = bundle.FederatedGetterFunc(func(ctx context.Context, td spiffeid.TrustDomain) (*spiffebundle.Bundle, error) {
+			commonBundle, err := ds.FetchBundle(dscache.WithCache(ctx), td.IDString())
+			if err != nil {
+				return nil, err
+			}
+			if commonBundle == nil {
+				return nil, nil
+			}
+			return bundleutil.SPIFFEBundleFromProto(commonBundle)
+		})
+		hubTrustDomains = c.BundleEndpoint.Hub.TrustDomains
+	}
+
 	return bundle.NewServer(bundle.ServerConfig{
 		Log:     c.Log.WithField(telemetry.SubsystemName, "bundle_endpoint"),
 		Address: c.BundleEndpoint.Address.String(),
@@ -158,9 +183,11 @@ func (c *Config) maybeMakeBundleEndpointServer() (Server, func(context.Context)
 			}
 			return bundleutil.SPIFFEBundleFromProto(commonBundle)
 		}),
-		RefreshHint: c.BundleEndpoint.RefreshHint,
-		ServerAuth:  serverAuth,
-		TLSPolicy:   c.TLSPolicy,
+		RefreshHint:     c.BundleEndpoint.RefreshHint,
+		ServerAuth:      serverAuth,
+		TLSPolicy:       c.TLSPolicy,
+		FederatedGetter: federatedGetter,
+		HubTrustDomains: hubTrustDomains,
 	}), certificateReloadTask
 }
 
@@ -168,6 +195,9 @@ func (c *Config) makeAPIServers(entryFetcher api.AuthorizedEntryFetcher) APIServ
 	ds := c.Catalog.GetDataStore()
 	upstreamPublisher := UpstreamPublisher(c.AuthorityManager)
 
+	// Only the events based entry fetcher keeps the agent selectors in memory
+	agentSelectors, _ := entryFetcher.(api.AgentSelectorsCache)
+
 	return APIServers{
 		AgentServer: agentv1.New(agentv1.Config{
 			DataStore:               ds,
@@ -181,6 +211,7 @@ func (c *Config) makeAPIServers(entryFetcher api.AuthorizedEntryFetcher) APIServ
 			TrustDomain:       c.TrustDomain,
 			DataStore:         ds,
 			UpstreamPublisher: upstreamPublisher,
+			WITKeyPublisher:   WITKeyPublisher(c.AuthorityManager),
 		}),
 		DebugServer: debugv1.New(debugv1.Config{
 			TrustDomain:  c.TrustDomain,
@@ -202,10 +233,12 @@ func (c *Config) makeAPIServers(entryFetcher api.AuthorizedEntryFetcher) APIServ
 			Log: c.RootLog,
 		}),
 		SVIDServer: svidv1.New(svidv1.Config{
-			TrustDomain:  c.TrustDomain,
-			EntryFetcher: entryFetcher,
-			ServerCA:     c.ServerCA,
-			DataStore:    ds,
+			TrustDomain:    c.TrustDomain,
+			EntryFetcher:   entryFetcher,
+			ServerCA:       c.ServerCA,
+			DataStore:      ds,
+			Catalog:        c.Catalog,
+			AgentSelectors: agentSelectors,
 		}),
 		TrustDomainServer: trustdomainv1.New(trustdomainv1.Config{
 			TrustDomain:     c.TrustDomain,
@@ -217,5 +250,17 @@ func (c *Config) makeAPIServers(entryFetcher api.AuthorizedEntryFetcher) APIServ
 			CAManager:   c.AuthorityManager,
 			DataStore:   ds,
 		}),
+		FederationServer: federationv1.New(federationv1.Config{
+			DataStore:       ds,
+			BundleRefresher: c.BundleManager,
+		}),
+		JoinTokenServer: jointokenv1.New(jointokenv1.Config{
+			DataStore: ds,
+			Clock:     c.Clock,
+		}),
+		AgentStatusServer: agentstatusv1.New(agentstatusv1.Config{
+			DataStore: ds,
+			Clock:     c.Clock,
+		}),
 	}
 }
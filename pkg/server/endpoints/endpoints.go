@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
@@ -42,6 +43,9 @@ import (
 	"github.com/spiffe/spire/pkg/server/authpolicy"
 	"github.com/spiffe/spire/pkg/server/datastore"
 	"github.com/spiffe/spire/pkg/server/svid"
+	agentstatusv1 "github.com/spiffe/spire/proto/private/server/agentstatus"
+	federationv1 "github.com/spiffe/spire/proto/private/server/federation"
+	jointokenv1 "github.com/spiffe/spire/proto/private/server/jointoken"
 )
 
 const (
@@ -98,6 +102,7 @@ type Endpoints struct {
 	EntryFetcherPruneEventsTask  func(context.Context) error
 	CertificateReloadTask        func(context.Context) error
 	AuditLogEnabled              bool
+	AuditLogger                  *logrus.Logger
 	ProxyProtocolTrustedCIDRs    []string
 	AuthPolicyEngine             *authpolicy.Engine
 	AdminIDs                     []spiffeid.ID
@@ -105,6 +110,15 @@ type Endpoints struct {
 	MaxAttestedNodeInfoStaleness time.Duration
 	nodeCache                    api.AttestedNodeCache
 
+	// mtx guards the settings that can be changed with UpdateMiddleware
+	// while the endpoints are serving.
+	mtx           sync.RWMutex
+	apiMiddleware *swappableMiddleware
+	// localCallerTracking is true when the local listener tracks the
+	// calling processes, which the audit log needs to identify local
+	// callers. It is decided when the endpoints start serving.
+	localCallerTracking bool
+
 	hooks struct {
 		// test hook used to indicate that is listening
 		listening chan struct{}
@@ -121,6 +135,9 @@ type APIServers struct {
 	SVIDServer           svidv1.SVIDServer
 	TrustDomainServer    trustdomainv1.TrustDomainServer
 	LocalAUthorityServer localauthorityv1.LocalAuthorityServer
+	FederationServer     federationv1.FederationServer
+	JoinTokenServer      jointokenv1.JoinTokenServer
+	AgentStatusServer    agentstatusv1.AgentStatusServer
 }
 
 // RateLimitConfig holds rate limiting configurations.
@@ -227,6 +244,7 @@ func New(ctx context.Context, c Config) (*Endpoints, error) {
 		EntryFetcherPruneEventsTask:  pruneEventsTask,
 		CertificateReloadTask:        certificateReloadTask,
 		AuditLogEnabled:              c.AuditLogEnabled,
+		AuditLogger:                  c.AuditLogger,
 		ProxyProtocolTrustedCIDRs:    c.ProxyProtocolTrustedCIDRs,
 		AuthPolicyEngine:             c.AuthPolicyEngine,
 		AdminIDs:                     c.AdminIDs,
@@ -266,6 +284,12 @@ func (e *Endpoints) ListenAndServe(ctx context.Context) error {
 	trustdomainv1.RegisterTrustDomainServer(udsServer, e.APIServers.TrustDomainServer)
 	localauthorityv1.RegisterLocalAuthorityServer(tcpServer, e.APIServers.LocalAUthorityServer)
 	localauthorityv1.RegisterLocalAuthorityServer(udsServer, e.APIServers.LocalAUthorityServer)
+	federationv1.RegisterFederationServer(tcpServer, e.APIServers.FederationServer)
+	federationv1.RegisterFederationServer(udsServer, e.APIServers.FederationServer)
+	jointokenv1.RegisterJoinTokenServer(tcpServer, e.APIServers.JoinTokenServer)
+	jointokenv1.RegisterJoinTokenServer(udsServer, e.APIServers.JoinTokenServer)
+	agentstatusv1.RegisterAgentStatusServer(tcpServer, e.APIServers.AgentStatusServer)
+	agentstatusv1.RegisterAgentStatusServer(udsServer, e.APIServers.AgentStatusServer)
 
 	// UDS only
 	loggerv1.RegisterLoggerServer(udsServer, e.APIServers.LoggerServer)
@@ -327,7 +351,7 @@ func (e *Endpoints) createUDSServer(unaryInterceptor grpc.UnaryServerInterceptor
 		grpc.StreamInterceptor(streamInterceptor),
 	}
 
-	if e.AuditLogEnabled {
+	if e.localCallerTracking {
 		options = append(options, grpc.Creds(peertracker.NewCredentials()))
 	} else {
 		options = append(options, grpc.Creds(auth.UntrackedUDSCredentials()))
@@ -391,7 +415,7 @@ func (e *Endpoints) runLocalAccess(ctx context.Context, server *grpc.Server) err
 	os.Remove(e.LocalAddr.String())
 	var l net.Listener
 	var err error
-	if e.AuditLogEnabled {
+	if e.localCallerTracking {
 		l, err = e.listenWithAuditLog()
 	} else {
 		l, err = e.listen()
@@ -480,9 +504,20 @@ func (e *Endpoints) getTLSConfig(ctx context.Context) func(*tls.ClientHelloInfo)
 }
 
 func (e *Endpoints) makeInterceptors() (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	e.localCallerTracking = e.AuditLogEnabled
+	e.apiMiddleware = newSwappableMiddleware(e.makeMiddleware())
+	return middleware.Interceptors(e.apiMiddleware)
+}
+
+// makeMiddleware builds the API middleware from the current settings. The
+// caller must hold the lock.
+func (e *Endpoints) makeMiddleware() middleware.Middleware {
 	log := e.Log.WithField(telemetry.SubsystemName, "api")
 
-	return middleware.Interceptors(Middleware(log, e.Metrics, e.DataStore, e.nodeCache, e.MaxAttestedNodeInfoStaleness, clock.New(), e.RateLimit, e.AuthPolicyEngine, e.AuditLogEnabled, e.AdminIDs))
+	return Middleware(log, e.Metrics, e.DataStore, e.nodeCache, e.MaxAttestedNodeInfoStaleness, clock.New(), e.RateLimit, e.AuthPolicyEngine, e.AuditLogEnabled, e.localCallerTracking, e.AuditLogger, e.AdminIDs)
 }
 
 func (e *Endpoints) triggerListeningHook() {
@@ -35,6 +35,8 @@ import (
 	"github.com/spiffe/spire/pkg/server/datastore"
 	"github.com/spiffe/spire/pkg/server/endpoints/bundle"
 	"github.com/spiffe/spire/pkg/server/svid"
+	federationv1 "github.com/spiffe/spire/proto/private/server/federation"
+	jointokenv1 "github.com/spiffe/spire/proto/private/server/jointoken"
 	"github.com/spiffe/spire/proto/spire/common"
 	"github.com/spiffe/spire/test/clock"
 	"github.com/spiffe/spire/test/fakes/fakedatastore"
@@ -124,6 +126,8 @@ func TestNew(t *testing.T) {
 	assert.NotNil(t, endpoints.APIServers.SVIDServer)
 	assert.NotNil(t, endpoints.BundleEndpointServer)
 	assert.NotNil(t, endpoints.APIServers.LocalAUthorityServer)
+	assert.NotNil(t, endpoints.APIServers.FederationServer)
+	assert.NotNil(t, endpoints.APIServers.JoinTokenServer)
 	assert.NotNil(t, endpoints.EntryFetcherPruneEventsTask)
 	assert.True(t, endpoints.TLSPolicy.RequirePQKEM)
 	assert.Equal(t, cat.GetDataStore(), endpoints.DataStore)
@@ -234,6 +238,8 @@ func TestListenAndServe(t *testing.T) {
 			SVIDServer:           svidServer{},
 			TrustDomainServer:    trustDomainServer{},
 			LocalAUthorityServer: localAuthorityServer{},
+			FederationServer:     federationServer{},
+			JoinTokenServer:      joinTokenServer{},
 		},
 		BundleEndpointServer:         bundleEndpointServer,
 		Log:                          log,
@@ -365,6 +371,14 @@ func TestListenAndServe(t *testing.T) {
 		testLocalAuthorityAPI(ctx, t, conns)
 	})
 
+	t.Run("Federation", func(t *testing.T) {
+		testFederationAPI(ctx, t, conns)
+	})
+
+	t.Run("JoinToken", func(t *testing.T) {
+		testJoinTokenAPI(ctx, t, conns)
+	})
+
 	t.Run("Access denied to remote caller", func(t *testing.T) {
 		testRemoteCaller(t, target)
 	})
@@ -1077,6 +1091,106 @@ func testLocalAuthorityAPI(ctx context.Context, t *testing.T, conns testConns) {
 	})
 }
 
+func testFederationAPI(ctx context.Context, t *testing.T, conns testConns) {
+	t.Run("Local", func(t *testing.T) {
+		testAuthorization(ctx, t, federationv1.NewFederationClient(conns.local), map[string]bool{
+			"ApproveQuarantinedBundle":         true,
+			"GetFederationRelationshipOptions": true,
+			"SetFederationRelationshipOptions": true,
+		})
+	})
+
+	t.Run("NoAuth", func(t *testing.T) {
+		testAuthorization(ctx, t, federationv1.NewFederationClient(conns.noAuth), map[string]bool{
+			"ApproveQuarantinedBundle":         false,
+			"GetFederationRelationshipOptions": false,
+			"SetFederationRelationshipOptions": false,
+		})
+	})
+
+	t.Run("Agent", func(t *testing.T) {
+		testAuthorization(ctx, t, federationv1.NewFederationClient(conns.agent), map[string]bool{
+			"ApproveQuarantinedBundle":         false,
+			"GetFederationRelationshipOptions": false,
+			"SetFederationRelationshipOptions": false,
+		})
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		testAuthorization(ctx, t, federationv1.NewFederationClient(conns.admin), map[string]bool{
+			"ApproveQuarantinedBundle":         true,
+			"GetFederationRelationshipOptions": true,
+			"SetFederationRelationshipOptions": true,
+		})
+	})
+
+	t.Run("Federated Admin", func(t *testing.T) {
+		testAuthorization(ctx, t, federationv1.NewFederationClient(conns.federatedAdmin), map[string]bool{
+			"ApproveQuarantinedBundle":         true,
+			"GetFederationRelationshipOptions": true,
+			"SetFederationRelationshipOptions": true,
+		})
+	})
+
+	t.Run("Downstream", func(t *testing.T) {
+		testAuthorization(ctx, t, federationv1.NewFederationClient(conns.downstream), map[string]bool{
+			"ApproveQuarantinedBundle":         false,
+			"GetFederationRelationshipOptions": false,
+			"SetFederationRelationshipOptions": false,
+		})
+	})
+}
+
+func testJoinTokenAPI(ctx context.Context, t *testing.T, conns testConns) {
+	t.Run("Local", func(t *testing.T) {
+		testAuthorization(ctx, t, jointokenv1.NewJoinTokenClient(conns.local), map[string]bool{
+			"CreateJoinToken": true,
+			"ListJoinTokens":  true,
+			"RevokeJoinToken": true,
+		})
+	})
+
+	t.Run("NoAuth", func(t *testing.T) {
+		testAuthorization(ctx, t, jointokenv1.NewJoinTokenClient(conns.noAuth), map[string]bool{
+			"CreateJoinToken": false,
+			"ListJoinTokens":  false,
+			"RevokeJoinToken": false,
+		})
+	})
+
+	t.Run("Agent", func(t *testing.T) {
+		testAuthorization(ctx, t, jointokenv1.NewJoinTokenClient(conns.agent), map[string]bool{
+			"CreateJoinToken": false,
+			"ListJoinTokens":  false,
+			"RevokeJoinToken": false,
+		})
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		testAuthorization(ctx, t, jointokenv1.NewJoinTokenClient(conns.admin), map[string]bool{
+			"CreateJoinToken": true,
+			"ListJoinTokens":  true,
+			"RevokeJoinToken": true,
+		})
+	})
+
+	t.Run("Federated Admin", func(t *testing.T) {
+		testAuthorization(ctx, t, jointokenv1.NewJoinTokenClient(conns.federatedAdmin), map[string]bool{
+			"CreateJoinToken": true,
+			"ListJoinTokens":  true,
+			"RevokeJoinToken": true,
+		})
+	})
+
+	t.Run("Downstream", func(t *testing.T) {
+		testAuthorization(ctx, t, jointokenv1.NewJoinTokenClient(conns.downstream), map[string]bool{
+			"CreateJoinToken": false,
+			"ListJoinTokens":  false,
+			"RevokeJoinToken": false,
+		})
+	})
+}
+
 // testAuthorization issues an RPC for each method on the client interface and
 // asserts whether the RPC was authorized or not. If a method is not
 // represented in the expectedAuthResults, or a method in expectedAuthResults
@@ -1466,6 +1580,38 @@ func (trustDomainServer) RefreshBundle(_ context.Context, _ *trustdomainv1.Refre
 	return &emptypb.Empty{}, nil
 }
 
+type federationServer struct {
+	federationv1.UnsafeFederationServer
+}
+
+func (federationServer) ApproveQuarantinedBundle(context.Context, *federationv1.ApproveQuarantinedBundleRequest) (*federationv1.ApproveQuarantinedBundleResponse, error) {
+	return &federationv1.ApproveQuarantinedBundleResponse{}, nil
+}
+
+func (federationServer) GetFederationRelationshipOptions(context.Context, *federationv1.GetFederationRelationshipOptionsRequest) (*federationv1.GetFederationRelationshipOptionsResponse, error) {
+	return &federationv1.GetFederationRelationshipOptionsResponse{}, nil
+}
+
+func (federationServer) SetFederationRelationshipOptions(context.Context, *federationv1.SetFederationRelationshipOptionsRequest) (*federationv1.SetFederationRelationshipOptionsResponse, error) {
+	return &federationv1.SetFederationRelationshipOptionsResponse{}, nil
+}
+
+type joinTokenServer struct {
+	jointokenv1.UnsafeJoinTokenServer
+}
+
+func (joinTokenServer) CreateJoinToken(context.Context, *jointokenv1.CreateJoinTokenRequest) (*jointokenv1.Token, error) {
+	return &jointokenv1.Token{}, nil
+}
+
+func (joinTokenServer) ListJoinTokens(context.Context, *jointokenv1.ListJoinTokensRequest) (*jointokenv1.ListJoinTokensResponse, error) {
+	return &jointokenv1.ListJoinTokensResponse{}, nil
+}
+
+func (joinTokenServer) RevokeJoinToken(context.Context, *jointokenv1.RevokeJoinTokenRequest) (*jointokenv1.RevokeJoinTokenResponse, error) {
+	return &jointokenv1.RevokeJoinTokenResponse{}, nil
+}
+
 type localAuthorityServer struct {
 	localauthorityv1.UnsafeLocalAuthorityServer
 }
@@ -24,17 +24,22 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-func Middleware(log logrus.FieldLogger, metrics telemetry.Metrics, ds datastore.DataStore, nodeCache api.AttestedNodeCache, maxAttestedNodeInfoStaleness time.Duration, clk clock.Clock, rlConf RateLimitConfig, policyEngine *authpolicy.Engine, auditLogEnabled bool, adminIDs []spiffeid.ID) middleware.Middleware {
+func Middleware(log logrus.FieldLogger, metrics telemetry.Metrics, ds datastore.DataStore, nodeCache api.AttestedNodeCache, maxAttestedNodeInfoStaleness time.Duration, clk clock.Clock, rlConf RateLimitConfig, policyEngine *authpolicy.Engine, auditLogEnabled, localCallerTracking bool, auditLogger *logrus.Logger, adminIDs []spiffeid.ID) middleware.Middleware {
 	chain := []middleware.Middleware{
 		middleware.WithLogger(log),
+		middleware.WithTracing(),
 		middleware.WithMetrics(metrics),
 		middleware.WithAuthorization(policyEngine, EntryFetcher(ds), AgentAuthorizer(ds, nodeCache, maxAttestedNodeInfoStaleness, clk), adminIDs),
 		middleware.WithRateLimits(RateLimits(rlConf), metrics),
 	}
 
-	if auditLogEnabled {
-		// Add audit log with local tracking enabled
-		chain = append(chain, middleware.WithAuditLog(true))
+	switch {
+	case auditLogEnabled && auditLogger != nil:
+		// Add audit log, emitting to the dedicated audit log
+		chain = append(chain, middleware.WithAuditLogger(localCallerTracking, auditLogger))
+	case auditLogEnabled:
+		// Add audit log
+		chain = append(chain, middleware.WithAuditLog(localCallerTracking))
 	}
 
 	return middleware.Chain(
@@ -58,6 +63,10 @@ func UpstreamPublisher(jwtKeyPublisher manager.JwtKeyPublisher) bundle.UpstreamP
 	return bundle.UpstreamPublisherFunc(jwtKeyPublisher.PublishJWTKey)
 }
 
+func WITKeyPublisher(witKeyPublisher manager.WITKeyPublisher) bundle.WITKeyPublisher {
+	return bundle.WITKeyPublisherFunc(witKeyPublisher.PublishWITKey)
+}
+
 func AgentAuthorizer(ds datastore.DataStore, nodeCache api.AttestedNodeCache, maxAttestedNodeInfoStaleness time.Duration, clk clock.Clock) middleware.AgentAuthorizer {
 	return middleware.AgentAuthorizerFunc(func(ctx context.Context, agentID spiffeid.ID, agentSVID *x509.Certificate) error {
 		id := agentID.String()
@@ -192,12 +201,20 @@ func RateLimits(config RateLimitConfig) map[string]api.RateLimiter {
 		"/spire.api.server.agent.v1.Agent/RenewAgent":                                    csrLimit,
 		"/spire.api.server.agent.v1.Agent/PostStatus":                                    postStatusLimit,
 		"/spire.api.server.agent.v1.Agent/CreateJoinToken":                               noLimit,
+		"/spire.private.server.jointoken.JoinToken/CreateJoinToken":                      noLimit,
+		"/spire.private.server.jointoken.JoinToken/ListJoinTokens":                       noLimit,
+		"/spire.private.server.jointoken.JoinToken/RevokeJoinToken":                      noLimit,
+		"/spire.private.server.agentstatus.AgentStatus/PostStatus":                       postStatusLimit,
+		"/spire.private.server.agentstatus.AgentStatus/ListAgentStatuses":                noLimit,
 		"/spire.api.server.trustdomain.v1.TrustDomain/ListFederationRelationships":       noLimit,
 		"/spire.api.server.trustdomain.v1.TrustDomain/GetFederationRelationship":         noLimit,
 		"/spire.api.server.trustdomain.v1.TrustDomain/BatchCreateFederationRelationship": noLimit,
 		"/spire.api.server.trustdomain.v1.TrustDomain/BatchUpdateFederationRelationship": noLimit,
 		"/spire.api.server.trustdomain.v1.TrustDomain/BatchDeleteFederationRelationship": noLimit,
 		"/spire.api.server.trustdomain.v1.TrustDomain/RefreshBundle":                     noLimit,
+		"/spire.private.server.federation.Federation/ApproveQuarantinedBundle":           noLimit,
+		"/spire.private.server.federation.Federation/GetFederationRelationshipOptions":   noLimit,
+		"/spire.private.server.federation.Federation/SetFederationRelationshipOptions":   noLimit,
 		"/spire.api.server.localauthority.v1.LocalAuthority/GetJWTAuthorityState":        noLimit,
 		"/spire.api.server.localauthority.v1.LocalAuthority/PrepareJWTAuthority":         noLimit,
 		"/spire.api.server.localauthority.v1.LocalAuthority/ActivateJWTAuthority":        noLimit,
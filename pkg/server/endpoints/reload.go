@@ -0,0 +1,87 @@
+package endpoints
+
+import (
+	"context"
+	"slices"
+	"sync/atomic"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/server/api/middleware"
+)
+
+// MiddlewareConfig holds the API settings that can be updated while the
+// endpoints are serving.
+type MiddlewareConfig struct {
+	// RateLimit holds rate limiting configurations.
+	RateLimit RateLimitConfig
+
+	// AuditLogEnabled enables audit logging. When audit logging was disabled
+	// when the endpoints started serving, calls over the local listener are
+	// audited without the details of the calling process.
+	AuditLogEnabled bool
+
+	// AdminIDs are the SPIFFE IDs that are granted admin access.
+	AdminIDs []spiffeid.ID
+}
+
+// UpdateMiddleware replaces the settings used to authorize, rate limit and
+// audit API calls. Calls in flight complete with the settings they started
+// with. Since the rate limiters are rebuilt, their state is reset when the
+// settings change.
+func (e *Endpoints) UpdateMiddleware(c MiddlewareConfig) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	if e.RateLimit == c.RateLimit && e.AuditLogEnabled == c.AuditLogEnabled && slices.Equal(e.AdminIDs, c.AdminIDs) {
+		return
+	}
+
+	e.RateLimit = c.RateLimit
+	e.AuditLogEnabled = c.AuditLogEnabled
+	e.AdminIDs = slices.Clone(c.AdminIDs)
+	if e.apiMiddleware != nil {
+		e.apiMiddleware.swap(e.makeMiddleware())
+	}
+}
+
+func (e *Endpoints) adminIDs() []spiffeid.ID {
+	e.mtx.RLock()
+	defer e.mtx.RUnlock()
+	return e.AdminIDs
+}
+
+type swappableMiddlewareKey struct{}
+
+// swappableMiddleware delegates to a middleware that can be replaced at any
+// time. The middleware that preprocessed a call is remembered in the call
+// context so the same one postprocesses it.
+type swappableMiddleware struct {
+	current atomic.Pointer[middleware.Middleware]
+}
+
+func newSwappableMiddleware(m middleware.Middleware) *swappableMiddleware {
+	s := new(swappableMiddleware)
+	s.swap(m)
+	return s
+}
+
+func (s *swappableMiddleware) swap(m middleware.Middleware) {
+	s.current.Store(&m)
+}
+
+func (s *swappableMiddleware) Preprocess(ctx context.Context, fullMethod string, req any) (context.Context, error) {
+	m := *s.current.Load()
+	ctx, err := m.Preprocess(ctx, fullMethod, req)
+	if err != nil {
+		return nil, err
+	}
+	return context.WithValue(ctx, swappableMiddlewareKey{}, m), nil
+}
+
+func (s *swappableMiddleware) Postprocess(ctx context.Context, fullMethod string, handlerInvoked bool, rpcErr error) {
+	m, ok := ctx.Value(swappableMiddlewareKey{}).(middleware.Middleware)
+	if !ok {
+		m = *s.current.Load()
+	}
+	m.Postprocess(ctx, fullMethod, handlerInvoked, rpcErr)
+}
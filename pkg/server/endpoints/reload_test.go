@@ -0,0 +1,75 @@
+package endpoints
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/server/api/middleware"
+	"github.com/spiffe/spire/test/fakes/fakemetrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwappableMiddleware(t *testing.T) {
+	var calls []string
+	record := func(name string) middleware.Middleware {
+		return middleware.Funcs(
+			func(ctx context.Context, _ string, _ any) (context.Context, error) {
+				calls = append(calls, name+" preprocess")
+				return ctx, nil
+			},
+			func(context.Context, string, bool, error) {
+				calls = append(calls, name+" postprocess")
+			},
+		)
+	}
+
+	m := newSwappableMiddleware(record("first"))
+	ctx, err := m.Preprocess(context.Background(), "", nil)
+	require.NoError(t, err)
+
+	// The call that is in flight is postprocessed by the middleware that
+	// preprocessed it, while new calls use the new middleware.
+	m.swap(record("second"))
+	m.Postprocess(ctx, "", true, nil)
+	ctx, err = m.Preprocess(context.Background(), "", nil)
+	require.NoError(t, err)
+	m.Postprocess(ctx, "", true, nil)
+
+	require.Equal(t, []string{
+		"first preprocess",
+		"first postprocess",
+		"second preprocess",
+		"second postprocess",
+	}, calls)
+}
+
+func TestUpdateMiddleware(t *testing.T) {
+	log, _ := test.NewNullLogger()
+	e := &Endpoints{
+		Log:       log,
+		Metrics:   fakemetrics.New(),
+		RateLimit: rateLimit,
+	}
+	e.makeInterceptors()
+	initial := e.apiMiddleware.current.Load()
+
+	// Unchanged settings keep the middleware, and the rate limiter state
+	e.UpdateMiddleware(MiddlewareConfig{RateLimit: rateLimit})
+	require.Same(t, initial, e.apiMiddleware.current.Load())
+
+	adminID := spiffeid.RequireFromString("spiffe://example.org/admin")
+	e.UpdateMiddleware(MiddlewareConfig{
+		RateLimit:       RateLimitConfig{},
+		AuditLogEnabled: true,
+		AdminIDs:        []spiffeid.ID{adminID},
+	})
+	require.NotSame(t, initial, e.apiMiddleware.current.Load())
+	require.Equal(t, RateLimitConfig{}, e.RateLimit)
+	require.True(t, e.AuditLogEnabled)
+	require.Equal(t, []spiffeid.ID{adminID}, e.adminIDs())
+
+	// The local listener keeps the caller tracking it started with
+	require.False(t, e.localCallerTracking)
+}
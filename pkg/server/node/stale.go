@@ -0,0 +1,172 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andres-erbsen/clock"
+	"github.com/sirupsen/logrus"
+	"github.com/spiffe/spire/pkg/common/agentstatus"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/proto/spire/common"
+)
+
+const (
+	minStaleAgentInterval = time.Minute
+	staleAgentPageSize    = 1000
+)
+
+// MinStaleAgentsNotSeenFor is the shortest window an agent can go without
+// contacting the server before it is considered stale. It leaves room for
+// several missed status reports, so agents are not acted on because of a
+// short network outage or a slow status report.
+const MinStaleAgentsNotSeenFor = max(10*agentstatus.DefaultReportInterval, 10*time.Minute)
+
+// StaleAgentAction is the action taken on agents that have not contacted the
+// server within the configured window.
+type StaleAgentAction string
+
+const (
+	// StaleAgentActionLog only logs stale agents, which leaves the decision
+	// to ban or evict them to an operator.
+	StaleAgentActionLog StaleAgentAction = "log"
+
+	// StaleAgentActionBan bans stale agents, which keeps their record but
+	// prevents them from renewing or re-attesting.
+	StaleAgentActionBan StaleAgentAction = "ban"
+
+	// StaleAgentActionEvict deletes the record of stale agents, which
+	// forces them to re-attest if they come back.
+	StaleAgentActionEvict StaleAgentAction = "evict"
+)
+
+// ParseStaleAgentAction parses a stale agent action.
+func ParseStaleAgentAction(s string) (StaleAgentAction, error) {
+	switch action := StaleAgentAction(s); action {
+	case StaleAgentActionLog, StaleAgentActionBan, StaleAgentActionEvict:
+		return action, nil
+	default:
+		return "", fmt.Errorf("unsupported stale agent action %q", s)
+	}
+}
+
+type StaleAgentReaperConfig struct {
+	DataStore datastore.DataStore
+
+	Log   logrus.FieldLogger
+	Clock clock.Clock
+
+	// NotSeenFor is how long an agent can go without contacting the server
+	// before it is considered stale. Agents that never reported their status
+	// (e.g. agents that have not been upgraded yet) are never stale.
+	NotSeenFor time.Duration
+
+	// Action is the action taken on stale agents.
+	Action StaleAgentAction
+}
+
+// StaleAgentReaper periodically logs, bans or evicts agents that have not
+// contacted the server within a configured window. Banned agents are left
+// alone.
+type StaleAgentReaper struct {
+	c        StaleAgentReaperConfig
+	log      logrus.FieldLogger
+	interval time.Duration
+}
+
+func NewStaleAgentReaper(c StaleAgentReaperConfig) *StaleAgentReaper {
+	if c.Clock == nil {
+		c.Clock = clock.New()
+	}
+
+	// Check a few times per window so agents are acted on shortly after
+	// they become stale, without hammering the datastore on short windows.
+	interval := min(max(c.NotSeenFor/4, minStaleAgentInterval), defaultJobInterval)
+
+	return &StaleAgentReaper{
+		c: c,
+		log: c.Log.WithFields(logrus.Fields{
+			"not_seen_for": c.NotSeenFor,
+			"action":       c.Action,
+		}),
+		interval: interval,
+	}
+}
+
+func (r *StaleAgentReaper) Run(ctx context.Context) error {
+	r.log.Info("Periodic check for stale agents started")
+
+	ticker := r.c.Clock.Ticker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.reap(ctx); err != nil && ctx.Err() == nil {
+				r.log.WithError(err).Error("Failed during periodic check for stale agents")
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (r *StaleAgentReaper) reap(ctx context.Context) error {
+	notBanned := false
+	req := &datastore.ListAttestedNodesRequest{
+		ByBanned:         &notBanned,
+		ByLastSeenBefore: r.c.Clock.Now().Add(-r.c.NotSeenFor),
+		Pagination: &datastore.Pagination{
+			PageSize: staleAgentPageSize,
+		},
+	}
+
+	for {
+		resp, err := r.c.DataStore.ListAttestedNodes(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to list stale agents: %w", err)
+		}
+
+		for _, node := range resp.Nodes {
+			if err := r.act(ctx, node); err != nil {
+				return err
+			}
+			r.log.WithFields(logrus.Fields{
+				telemetry.SPIFFEID: node.SpiffeId,
+				"last_seen_at":     time.Unix(node.LastSeenAt, 0),
+			}).Warn("Found stale agent")
+		}
+
+		if resp.Pagination == nil || resp.Pagination.Token == "" {
+			return nil
+		}
+		req.Pagination.Token = resp.Pagination.Token
+	}
+}
+
+func (r *StaleAgentReaper) act(ctx context.Context, node *common.AttestedNode) error {
+	switch r.c.Action {
+	case StaleAgentActionLog:
+		// Logged by the caller
+	case StaleAgentActionBan:
+		// Banned agents have an empty serial number
+		_, err := r.c.DataStore.UpdateAttestedNode(ctx, &common.AttestedNode{
+			SpiffeId: node.SpiffeId,
+		}, &common.AttestedNodeMask{
+			CertSerialNumber:    true,
+			NewCertSerialNumber: true,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to ban stale agent %q: %w", node.SpiffeId, err)
+		}
+	case StaleAgentActionEvict:
+		if _, err := r.c.DataStore.DeleteAttestedNode(ctx, node.SpiffeId); err != nil {
+			return fmt.Errorf("failed to evict stale agent %q: %w", node.SpiffeId, err)
+		}
+	default:
+		return fmt.Errorf("unsupported stale agent action %q", r.c.Action)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/spiffe/spire/test/clock"
+	"github.com/spiffe/spire/test/fakes/fakedatastore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStaleAgentAction(t *testing.T) {
+	action, err := ParseStaleAgentAction("log")
+	require.NoError(t, err)
+	require.Equal(t, StaleAgentActionLog, action)
+
+	action, err = ParseStaleAgentAction("ban")
+	require.NoError(t, err)
+	require.Equal(t, StaleAgentActionBan, action)
+
+	action, err = ParseStaleAgentAction("evict")
+	require.NoError(t, err)
+	require.Equal(t, StaleAgentActionEvict, action)
+
+	_, err = ParseStaleAgentAction("purge")
+	require.EqualError(t, err, `unsupported stale agent action "purge"`)
+}
+
+func TestStaleAgentReaper(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		action      StaleAgentAction
+		expectStale func(t *testing.T, ds datastore.DataStore)
+	}{
+		{
+			name:   "log",
+			action: StaleAgentActionLog,
+			expectStale: func(t *testing.T, ds datastore.DataStore) {
+				node, err := ds.FetchAttestedNode(context.Background(), "spiffe://example.org/stale")
+				require.NoError(t, err)
+				require.NotNil(t, node)
+				require.Equal(t, "1", node.CertSerialNumber)
+			},
+		},
+		{
+			name:   "ban",
+			action: StaleAgentActionBan,
+			expectStale: func(t *testing.T, ds datastore.DataStore) {
+				node, err := ds.FetchAttestedNode(context.Background(), "spiffe://example.org/stale")
+				require.NoError(t, err)
+				require.NotNil(t, node)
+				require.Empty(t, node.CertSerialNumber)
+				require.Empty(t, node.NewCertSerialNumber)
+			},
+		},
+		{
+			name:   "evict",
+			action: StaleAgentActionEvict,
+			expectStale: func(t *testing.T, ds datastore.DataStore) {
+				node, err := ds.FetchAttestedNode(context.Background(), "spiffe://example.org/stale")
+				require.NoError(t, err)
+				require.Nil(t, node)
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			clk := clock.NewMock(t)
+			log, hook := test.NewNullLogger()
+			ds := fakedatastore.New(t)
+
+			notSeenFor := 4 * time.Hour
+			createNode := func(id string, lastSeenAt time.Time, serialNumber string) {
+				node := &common.AttestedNode{
+					SpiffeId:         id,
+					CertSerialNumber: serialNumber,
+					CertNotAfter:     clk.Now().Add(time.Hour).Unix(),
+				}
+				if !lastSeenAt.IsZero() {
+					node.LastSeenAt = lastSeenAt.Unix()
+				}
+				_, err := ds.CreateAttestedNode(ctx, node)
+				require.NoError(t, err)
+			}
+			createNode("spiffe://example.org/stale", clk.Now().Add(-time.Minute), "1")
+			createNode("spiffe://example.org/fresh", clk.Now().Add(time.Hour), "2")
+			createNode("spiffe://example.org/never-seen", time.Time{}, "3")
+			createNode("spiffe://example.org/banned", clk.Now().Add(-time.Minute), "")
+
+			reaper := NewStaleAgentReaper(StaleAgentReaperConfig{
+				DataStore:  ds,
+				Log:        log,
+				Clock:      clk,
+				NotSeenFor: notSeenFor,
+				Action:     tt.action,
+			})
+			require.Equal(t, time.Hour, reaper.interval)
+
+			ctx, cancel := context.WithCancel(ctx)
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- reaper.Run(ctx)
+			}()
+			defer func() {
+				cancel()
+				require.NoError(t, <-errCh)
+			}()
+
+			clk.WaitForTicker(time.Minute, "waiting for the stale agent ticker")
+			clk.Add(notSeenFor)
+			require.Eventually(t, func() bool {
+				for _, entry := range hook.AllEntries() {
+					if entry.Message == "Found stale agent" {
+						return true
+					}
+				}
+				return false
+			}, time.Minute, 10*time.Millisecond)
+			tt.expectStale(t, ds)
+
+			// Agents seen within the window, agents that never reported
+			// their status and banned agents are left alone
+			for id, serialNumber := range map[string]string{
+				"spiffe://example.org/fresh":      "2",
+				"spiffe://example.org/never-seen": "3",
+				"spiffe://example.org/banned":     "",
+			} {
+				node, err := ds.FetchAttestedNode(ctx, id)
+				require.NoError(t, err)
+				require.NotNil(t, node, id)
+				require.Equal(t, serialNumber, node.CertSerialNumber, id)
+			}
+		})
+	}
+}
+
+func TestStaleAgentReaperInterval(t *testing.T) {
+	log, _ := test.NewNullLogger()
+	newInterval := func(notSeenFor time.Duration) time.Duration {
+		return NewStaleAgentReaper(StaleAgentReaperConfig{
+			Log:        log,
+			NotSeenFor: notSeenFor,
+			Action:     StaleAgentActionBan,
+		}).interval
+	}
+	require.Equal(t, minStaleAgentInterval, newInterval(time.Second))
+	require.Equal(t, 15*time.Minute, newInterval(time.Hour))
+	require.Equal(t, defaultJobInterval, newInterval(30*24*time.Hour))
+}
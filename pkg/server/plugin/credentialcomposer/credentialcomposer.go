@@ -7,6 +7,7 @@ import (
 	"crypto/x509/pkix"
 
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
 	"github.com/spiffe/spire/pkg/common/catalog"
 )
 
@@ -15,9 +16,24 @@ type CredentialComposer interface {
 
 	ComposeServerX509CA(ctx context.Context, attributes X509CAAttributes) (X509CAAttributes, error)
 	ComposeServerX509SVID(ctx context.Context, attributes X509SVIDAttributes) (X509SVIDAttributes, error)
-	ComposeAgentX509SVID(ctx context.Context, id spiffeid.ID, publicKey crypto.PublicKey, attributes X509SVIDAttributes) (X509SVIDAttributes, error)
-	ComposeWorkloadX509SVID(ctx context.Context, id spiffeid.ID, publicKey crypto.PublicKey, attributes X509SVIDAttributes) (X509SVIDAttributes, error)
-	ComposeWorkloadJWTSVID(ctx context.Context, id spiffeid.ID, attributes JWTSVIDAttributes) (JWTSVIDAttributes, error)
+	ComposeAgentX509SVID(ctx context.Context, id spiffeid.ID, publicKey crypto.PublicKey, svidContext SVIDContext, attributes X509SVIDAttributes) (X509SVIDAttributes, error)
+	ComposeWorkloadX509SVID(ctx context.Context, id spiffeid.ID, publicKey crypto.PublicKey, svidContext SVIDContext, attributes X509SVIDAttributes) (X509SVIDAttributes, error)
+	ComposeWorkloadJWTSVID(ctx context.Context, id spiffeid.ID, svidContext SVIDContext, attributes JWTSVIDAttributes) (JWTSVIDAttributes, error)
+}
+
+// SVIDContext describes what an agent or workload SVID is being composed
+// for. Fields are left empty when they are not known to the caller, e.g. the
+// entry is unset for SVIDs minted directly through the SVID API.
+type SVIDContext struct {
+	// Entry is the registration entry the workload SVID is issued for.
+	Entry *types.Entry
+
+	// AgentID is the SPIFFE ID of the agent the SVID is issued to (or for,
+	// in the case of agent SVIDs).
+	AgentID spiffeid.ID
+
+	// AgentSelectors are the node selectors of the agent.
+	AgentSelectors []*types.Selector
 }
 
 type X509CAAttributes struct {
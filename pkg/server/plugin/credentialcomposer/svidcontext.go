@@ -0,0 +1,93 @@
+package credentialcomposer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// SVIDContextKey is the binary gRPC metadata key used to forward the
+// SVIDContext to credential composer plugins, since the plugin SDK requests
+// only carry the SPIFFE ID and public key. The value is a JSON object with the
+// "entry" (in the protojson encoding of the SPIRE API Entry type), "agent_id"
+// and "agent_selectors" fields.
+const SVIDContextKey = "spire-svid-context-bin"
+
+type svidContextJSON struct {
+	Entry          json.RawMessage    `json:"entry,omitempty"`
+	AgentID        string             `json:"agent_id,omitempty"`
+	AgentSelectors []svidSelectorJSON `json:"agent_selectors,omitempty"`
+}
+
+type svidSelectorJSON struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// SVIDContextFromIncomingContext returns the SVIDContext forwarded to a
+// credential composer plugin. A zero SVIDContext is returned if none was
+// forwarded.
+func SVIDContextFromIncomingContext(ctx context.Context) (SVIDContext, error) {
+	values := metadata.ValueFromIncomingContext(ctx, SVIDContextKey)
+	if len(values) == 0 {
+		return SVIDContext{}, nil
+	}
+
+	var in svidContextJSON
+	if err := json.Unmarshal([]byte(values[0]), &in); err != nil {
+		return SVIDContext{}, fmt.Errorf("malformed SVID context: %w", err)
+	}
+
+	var svidContext SVIDContext
+	if len(in.Entry) > 0 {
+		svidContext.Entry = new(types.Entry)
+		if err := protojson.Unmarshal(in.Entry, svidContext.Entry); err != nil {
+			return SVIDContext{}, fmt.Errorf("malformed SVID context entry: %w", err)
+		}
+	}
+	if in.AgentID != "" {
+		agentID, err := spiffeid.FromString(in.AgentID)
+		if err != nil {
+			return SVIDContext{}, fmt.Errorf("malformed SVID context agent ID: %w", err)
+		}
+		svidContext.AgentID = agentID
+	}
+	for _, selector := range in.AgentSelectors {
+		svidContext.AgentSelectors = append(svidContext.AgentSelectors, &types.Selector{
+			Type:  selector.Type,
+			Value: selector.Value,
+		})
+	}
+	return svidContext, nil
+}
+
+func appendSVIDContextToOutgoingContext(ctx context.Context, svidContext SVIDContext) (context.Context, error) {
+	var out svidContextJSON
+	if svidContext.Entry != nil {
+		entry, err := protojson.Marshal(svidContext.Entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal entry: %w", err)
+		}
+		out.Entry = entry
+	}
+	if !svidContext.AgentID.IsZero() {
+		out.AgentID = svidContext.AgentID.String()
+	}
+	for _, selector := range svidContext.AgentSelectors {
+		out.AgentSelectors = append(out.AgentSelectors, svidSelectorJSON{
+			Type:  selector.Type,
+			Value: selector.Value,
+		})
+	}
+
+	value, err := json.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal SVID context: %w", err)
+	}
+	return metadata.AppendToOutgoingContext(ctx, SVIDContextKey, string(value)), nil
+}
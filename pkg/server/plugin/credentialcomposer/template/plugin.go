@@ -0,0 +1,506 @@
+package template
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+
+	"github.com/hashicorp/hcl"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	credentialcomposerv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/server/credentialcomposer/v1"
+	configv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/service/common/config/v1"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/common/pluginconf"
+	"github.com/spiffe/spire/pkg/common/x509util"
+	"github.com/spiffe/spire/pkg/server/plugin/credentialcomposer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	pluginName = "template"
+)
+
+var (
+	// reservedClaims are the claims set by SPIRE that cannot be templated.
+	reservedClaims = []string{"aud", "exp", "iat", "iss", "jti", "nbf", "sub"}
+
+	templateFuncs = texttemplate.FuncMap{
+		"selector": selectorValue,
+		"lower":    strings.ToLower,
+		"upper":    strings.ToUpper,
+	}
+)
+
+func BuiltIn() catalog.BuiltIn {
+	return builtIn(New())
+}
+
+func builtIn(p *Plugin) catalog.BuiltIn {
+	return catalog.MakeBuiltIn(pluginName,
+		credentialcomposerv1.CredentialComposerPluginServer(p),
+		configv1.ConfigServiceServer(p),
+	)
+}
+
+// Config is the HCL configuration of the plugin.
+type Config struct {
+	// AgentX509SVID holds the templates applied to agent X509-SVIDs.
+	AgentX509SVID *X509SVIDConfig `hcl:"agent_x509_svid" json:"agent_x509_svid"`
+
+	// WorkloadX509SVID holds the templates applied to workload X509-SVIDs.
+	WorkloadX509SVID *X509SVIDConfig `hcl:"workload_x509_svid" json:"workload_x509_svid"`
+
+	// WorkloadJWTSVID holds the templates applied to workload JWT-SVIDs.
+	WorkloadJWTSVID *JWTSVIDConfig `hcl:"workload_jwt_svid" json:"workload_jwt_svid"`
+}
+
+// X509SVIDConfig holds the templates applied to an X509-SVID.
+type X509SVIDConfig struct {
+	// Subject holds the templates of the subject fields.
+	Subject *SubjectConfig `hcl:"subject" json:"subject"`
+
+	// DNSNames are templates of DNS SANs added to the SVID.
+	DNSNames []string `hcl:"dns_names" json:"dns_names"`
+}
+
+// SubjectConfig holds the templates of the subject fields. Fields that are
+// not configured are left unchanged.
+type SubjectConfig struct {
+	CommonName         string   `hcl:"common_name" json:"common_name"`
+	Organization       []string `hcl:"organization" json:"organization"`
+	OrganizationalUnit []string `hcl:"organizational_unit" json:"organizational_unit"`
+	Country            []string `hcl:"country" json:"country"`
+	Province           []string `hcl:"province" json:"province"`
+	Locality           []string `hcl:"locality" json:"locality"`
+}
+
+// JWTSVIDConfig holds the templates applied to a JWT-SVID.
+type JWTSVIDConfig struct {
+	// Claims maps claim names to the template of their value.
+	Claims map[string]string `hcl:"claims" json:"claims"`
+}
+
+type x509SVIDTemplates struct {
+	commonName         *texttemplate.Template
+	organization       []*texttemplate.Template
+	organizationalUnit []*texttemplate.Template
+	country            []*texttemplate.Template
+	province           []*texttemplate.Template
+	locality           []*texttemplate.Template
+	dnsNames           []*texttemplate.Template
+}
+
+type jwtSVIDTemplates struct {
+	claims map[string]*texttemplate.Template
+}
+
+type composerConfig struct {
+	agentX509SVID    *x509SVIDTemplates
+	workloadX509SVID *x509SVIDTemplates
+	workloadJWTSVID  *jwtSVIDTemplates
+}
+
+func buildConfig(_ catalog.CoreConfig, hclText string, status *pluginconf.Status) *composerConfig {
+	hclConfig := new(Config)
+	if err := hcl.Decode(hclConfig, hclText); err != nil {
+		status.ReportErrorf("unable to decode configuration: %v", err)
+		return nil
+	}
+
+	if hclConfig.AgentX509SVID == nil && hclConfig.WorkloadX509SVID == nil && hclConfig.WorkloadJWTSVID == nil {
+		status.ReportError("at least one of agent_x509_svid, workload_x509_svid or workload_jwt_svid must be configured")
+	}
+
+	return &composerConfig{
+		agentX509SVID:    parseX509SVIDConfig("agent_x509_svid", hclConfig.AgentX509SVID, status),
+		workloadX509SVID: parseX509SVIDConfig("workload_x509_svid", hclConfig.WorkloadX509SVID, status),
+		workloadJWTSVID:  parseJWTSVIDConfig("workload_jwt_svid", hclConfig.WorkloadJWTSVID, status),
+	}
+}
+
+func parseX509SVIDConfig(name string, config *X509SVIDConfig, status *pluginconf.Status) *x509SVIDTemplates {
+	if config == nil {
+		return nil
+	}
+
+	templates := &x509SVIDTemplates{
+		dnsNames: parseTemplates(name+".dns_names", config.DNSNames, status),
+	}
+	if subject := config.Subject; subject != nil {
+		if subject.CommonName != "" {
+			templates.commonName = parseTemplate(name+".subject.common_name", subject.CommonName, status)
+		}
+		templates.organization = parseTemplates(name+".subject.organization", subject.Organization, status)
+		templates.organizationalUnit = parseTemplates(name+".subject.organizational_unit", subject.OrganizationalUnit, status)
+		templates.country = parseTemplates(name+".subject.country", subject.Country, status)
+		templates.province = parseTemplates(name+".subject.province", subject.Province, status)
+		templates.locality = parseTemplates(name+".subject.locality", subject.Locality, status)
+	}
+	return templates
+}
+
+func parseJWTSVIDConfig(name string, config *JWTSVIDConfig, status *pluginconf.Status) *jwtSVIDTemplates {
+	if config == nil {
+		return nil
+	}
+
+	templates := &jwtSVIDTemplates{
+		claims: make(map[string]*texttemplate.Template, len(config.Claims)),
+	}
+	for claim, text := range config.Claims {
+		if slices.Contains(reservedClaims, claim) {
+			status.ReportErrorf("%s.claims: the %q claim is reserved", name, claim)
+			continue
+		}
+		templates.claims[claim] = parseTemplate(fmt.Sprintf("%s.claims.%s", name, claim), text, status)
+	}
+	return templates
+}
+
+func parseTemplates(name string, texts []string, status *pluginconf.Status) []*texttemplate.Template {
+	var templates []*texttemplate.Template
+	for i, text := range texts {
+		templates = append(templates, parseTemplate(fmt.Sprintf("%s[%d]", name, i), text, status))
+	}
+	return templates
+}
+
+func parseTemplate(name, text string, status *pluginconf.Status) *texttemplate.Template {
+	tmpl, err := texttemplate.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		status.ReportErrorf("invalid %s template: %v", name, err)
+		return nil
+	}
+	return tmpl
+}
+
+// templateData is the data the templates are executed against.
+type templateData struct {
+	// SPIFFEID is the SPIFFE ID of the SVID.
+	SPIFFEID string
+
+	// TrustDomain is the trust domain name of the SVID.
+	TrustDomain string
+
+	// Path is the path of the SPIFFE ID of the SVID.
+	Path string
+
+	// Entry is the registration entry the workload SVID is issued for. It is
+	// empty for agent SVIDs and for SVIDs minted without an entry.
+	Entry entryData
+
+	// AgentID is the SPIFFE ID of the agent.
+	AgentID string
+
+	// AgentSelectors are the node selectors of the agent.
+	AgentSelectors []selectorData
+}
+
+type entryData struct {
+	ID         string
+	SPIFFEID   string
+	ParentID   string
+	Hint       string
+	DNSNames   []string
+	Selectors  []selectorData
+	Admin      bool
+	Downstream bool
+}
+
+type selectorData struct {
+	Type  string
+	Value string
+}
+
+func newTemplateData(id spiffeid.ID, svidContext credentialcomposer.SVIDContext) templateData {
+	data := templateData{
+		SPIFFEID:       id.String(),
+		TrustDomain:    id.TrustDomain().Name(),
+		Path:           id.Path(),
+		AgentSelectors: selectorsData(svidContext.AgentSelectors),
+	}
+	if !svidContext.AgentID.IsZero() {
+		data.AgentID = svidContext.AgentID.String()
+	}
+	if entry := svidContext.Entry; entry != nil {
+		data.Entry = entryData{
+			ID:         entry.Id,
+			SPIFFEID:   idString(entry.SpiffeId),
+			ParentID:   idString(entry.ParentId),
+			Hint:       entry.Hint,
+			DNSNames:   entry.DnsNames,
+			Selectors:  selectorsData(entry.Selectors),
+			Admin:      entry.Admin,
+			Downstream: entry.Downstream,
+		}
+	}
+	return data
+}
+
+func selectorsData(selectors []*types.Selector) []selectorData {
+	var data []selectorData
+	for _, selector := range selectors {
+		data = append(data, selectorData{Type: selector.Type, Value: selector.Value})
+	}
+	return data
+}
+
+func idString(id *types.SPIFFEID) string {
+	if id == nil {
+		return ""
+	}
+	return "spiffe://" + id.TrustDomain + id.Path
+}
+
+// selectorValue returns the value of the first selector with the given type
+// whose value is prefixed by "<key>:", with the prefix removed. For example,
+// the "k8s" selector with the "ns" key is "foo" for the "k8s:ns:foo" selector.
+// If the key is empty, the whole value of the first selector with the given
+// type is returned. An empty string is returned if no selector matches.
+func selectorValue(selectors []selectorData, selectorType, key string) string {
+	for _, selector := range selectors {
+		if selector.Type != selectorType {
+			continue
+		}
+		if key == "" {
+			return selector.Value
+		}
+		if value, ok := strings.CutPrefix(selector.Value, key+":"); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// Plugin is a credential composer that shapes SVIDs using Go templates
+// executed against the SPIFFE ID, registration entry and agent data.
+type Plugin struct {
+	credentialcomposerv1.UnsafeCredentialComposerServer
+	configv1.UnsafeConfigServer
+
+	mu     sync.RWMutex
+	config *composerConfig
+}
+
+func New() *Plugin {
+	return &Plugin{}
+}
+
+func (p *Plugin) Configure(_ context.Context, req *configv1.ConfigureRequest) (*configv1.ConfigureResponse, error) {
+	newConfig, _, err := pluginconf.Build(req, buildConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.config = newConfig
+
+	return &configv1.ConfigureResponse{}, nil
+}
+
+func (p *Plugin) Validate(_ context.Context, req *configv1.ValidateRequest) (*configv1.ValidateResponse, error) {
+	_, notes, err := pluginconf.Build(req, buildConfig)
+
+	return &configv1.ValidateResponse{
+		Valid: err == nil,
+		Notes: notes,
+	}, nil
+}
+
+func (p *Plugin) ComposeServerX509CA(context.Context, *credentialcomposerv1.ComposeServerX509CARequest) (*credentialcomposerv1.ComposeServerX509CAResponse, error) {
+	// Intentionally not implemented.
+	return nil, status.Error(codes.Unimplemented, "not implemented")
+}
+
+func (p *Plugin) ComposeServerX509SVID(context.Context, *credentialcomposerv1.ComposeServerX509SVIDRequest) (*credentialcomposerv1.ComposeServerX509SVIDResponse, error) {
+	// Intentionally not implemented.
+	return nil, status.Error(codes.Unimplemented, "not implemented")
+}
+
+func (p *Plugin) ComposeAgentX509SVID(ctx context.Context, req *credentialcomposerv1.ComposeAgentX509SVIDRequest) (*credentialcomposerv1.ComposeAgentX509SVIDResponse, error) {
+	config, err := p.getConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config.agentX509SVID == nil {
+		return nil, status.Error(codes.Unimplemented, "agent X509-SVID templates are not configured")
+	}
+
+	attributes, err := composeX509SVID(ctx, config.agentX509SVID, req.SpiffeId, req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	return &credentialcomposerv1.ComposeAgentX509SVIDResponse{
+		Attributes: attributes,
+	}, nil
+}
+
+func (p *Plugin) ComposeWorkloadX509SVID(ctx context.Context, req *credentialcomposerv1.ComposeWorkloadX509SVIDRequest) (*credentialcomposerv1.ComposeWorkloadX509SVIDResponse, error) {
+	config, err := p.getConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config.workloadX509SVID == nil {
+		return nil, status.Error(codes.Unimplemented, "workload X509-SVID templates are not configured")
+	}
+
+	attributes, err := composeX509SVID(ctx, config.workloadX509SVID, req.SpiffeId, req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	return &credentialcomposerv1.ComposeWorkloadX509SVIDResponse{
+		Attributes: attributes,
+	}, nil
+}
+
+func (p *Plugin) ComposeWorkloadJWTSVID(ctx context.Context, req *credentialcomposerv1.ComposeWorkloadJWTSVIDRequest) (*credentialcomposerv1.ComposeWorkloadJWTSVIDResponse, error) {
+	config, err := p.getConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config.workloadJWTSVID == nil {
+		return nil, status.Error(codes.Unimplemented, "workload JWT-SVID templates are not configured")
+	}
+
+	if req.Attributes == nil {
+		return nil, status.Error(codes.InvalidArgument, "request missing attributes")
+	}
+	data, err := newTemplateDataFromRequest(ctx, req.SpiffeId)
+	if err != nil {
+		return nil, err
+	}
+
+	// No need to clone
+	attributes := req.Attributes
+	if attributes.Claims == nil {
+		attributes.Claims = &structpb.Struct{}
+	}
+	if attributes.Claims.Fields == nil {
+		attributes.Claims.Fields = make(map[string]*structpb.Value)
+	}
+	for claim, tmpl := range config.workloadJWTSVID.claims {
+		value, err := render(tmpl, data)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to render %q claim: %v", claim, err)
+		}
+		if value != "" {
+			attributes.Claims.Fields[claim] = structpb.NewStringValue(value)
+		}
+	}
+
+	return &credentialcomposerv1.ComposeWorkloadJWTSVIDResponse{
+		Attributes: attributes,
+	}, nil
+}
+
+func (p *Plugin) getConfig() (*composerConfig, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.config == nil {
+		return nil, status.Error(codes.FailedPrecondition, "not configured")
+	}
+	return p.config, nil
+}
+
+func composeX509SVID(ctx context.Context, templates *x509SVIDTemplates, id string, attributes *credentialcomposerv1.X509SVIDAttributes) (*credentialcomposerv1.X509SVIDAttributes, error) {
+	if attributes == nil {
+		return nil, status.Error(codes.InvalidArgument, "request missing attributes")
+	}
+	data, err := newTemplateDataFromRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// No need to clone
+	if attributes.Subject == nil {
+		attributes.Subject = &credentialcomposerv1.DistinguishedName{}
+	}
+	subject := attributes.Subject
+
+	if templates.commonName != nil {
+		commonName, err := render(templates.commonName, data)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to render common name: %v", err)
+		}
+		if commonName != "" {
+			subject.CommonName = commonName
+		}
+	}
+	for _, field := range []struct {
+		name      string
+		templates []*texttemplate.Template
+		values    *[]string
+	}{
+		{name: "organization", templates: templates.organization, values: &subject.Organization},
+		{name: "organizational unit", templates: templates.organizationalUnit, values: &subject.OrganizationalUnit},
+		{name: "country", templates: templates.country, values: &subject.Country},
+		{name: "province", templates: templates.province, values: &subject.Province},
+		{name: "locality", templates: templates.locality, values: &subject.Locality},
+	} {
+		values, err := renderAll(field.templates, data)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to render %s: %v", field.name, err)
+		}
+		if len(values) > 0 {
+			*field.values = values
+		}
+	}
+
+	dnsNames, err := renderAll(templates.dnsNames, data)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to render DNS name: %v", err)
+	}
+	for _, dnsName := range dnsNames {
+		if err := x509util.ValidateLabel(dnsName); err != nil {
+			return nil, status.Errorf(codes.Internal, "rendered DNS name %q is invalid: %v", dnsName, err)
+		}
+		if !slices.Contains(attributes.DnsSans, dnsName) {
+			attributes.DnsSans = append(attributes.DnsSans, dnsName)
+		}
+	}
+
+	return attributes, nil
+}
+
+func newTemplateDataFromRequest(ctx context.Context, id string) (templateData, error) {
+	spiffeID, err := spiffeid.FromString(id)
+	if err != nil {
+		return templateData{}, status.Errorf(codes.InvalidArgument, "malformed SPIFFE ID: %v", err)
+	}
+	svidContext, err := credentialcomposer.SVIDContextFromIncomingContext(ctx)
+	if err != nil {
+		return templateData{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return newTemplateData(spiffeID, svidContext), nil
+}
+
+// renderAll renders the templates, dropping values that render empty.
+func renderAll(templates []*texttemplate.Template, data templateData) ([]string, error) {
+	var values []string
+	for _, tmpl := range templates {
+		value, err := render(tmpl, data)
+		if err != nil {
+			return nil, err
+		}
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+func render(tmpl *texttemplate.Template, data templateData) (string, error) {
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
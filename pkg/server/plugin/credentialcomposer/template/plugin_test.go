@@ -0,0 +1,284 @@
+package template_test
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/server/plugin/credentialcomposer"
+	"github.com/spiffe/spire/pkg/server/plugin/credentialcomposer/template"
+	"github.com/spiffe/spire/test/plugintest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/spiffe/spire/test/testkey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	ctx        = context.Background()
+	key        = testkey.MustEC256()
+	td         = spiffeid.RequireTrustDomainFromString("example.org")
+	agentID    = spiffeid.RequireFromPath(td, "/spire/agent/k8s_psat/demo/node1")
+	workloadID = spiffeid.RequireFromPath(td, "/ns/payments/sa/billing")
+
+	svidContext = credentialcomposer.SVIDContext{
+		Entry: &types.Entry{
+			Id:       "ENTRYID",
+			SpiffeId: &types.SPIFFEID{TrustDomain: "example.org", Path: "/ns/payments/sa/billing"},
+			ParentId: &types.SPIFFEID{TrustDomain: "example.org", Path: "/spire/agent/k8s_psat/demo/node1"},
+			Selectors: []*types.Selector{
+				{Type: "k8s", Value: "ns:payments"},
+				{Type: "k8s", Value: "sa:billing"},
+			},
+			DnsNames: []string{"billing.payments.svc"},
+			Hint:     "billing",
+		},
+		AgentID: agentID,
+		AgentSelectors: []*types.Selector{
+			{Type: "k8s_psat", Value: "cluster:demo"},
+		},
+	}
+)
+
+func TestConfigure(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		config    string
+		expectErr string
+	}{
+		{
+			name:      "malformed",
+			config:    `workload_x509_svid = [`,
+			expectErr: "unable to decode configuration",
+		},
+		{
+			name:      "nothing configured",
+			config:    ``,
+			expectErr: "at least one of agent_x509_svid, workload_x509_svid or workload_jwt_svid must be configured",
+		},
+		{
+			name: "invalid template",
+			config: `
+				workload_x509_svid {
+					subject {
+						common_name = "{{ .Entry.Hint"
+					}
+				}
+			`,
+			expectErr: "invalid workload_x509_svid.subject.common_name template",
+		},
+		{
+			name: "invalid DNS name template",
+			config: `
+				agent_x509_svid {
+					dns_names = ["ok", "{{ bogus }}"]
+				}
+			`,
+			expectErr: "invalid agent_x509_svid.dns_names[1] template",
+		},
+		{
+			name: "reserved claim",
+			config: `
+				workload_jwt_svid {
+					claims {
+						sub = "{{ .Entry.Hint }}"
+					}
+				}
+			`,
+			expectErr: `workload_jwt_svid.claims: the "sub" claim is reserved`,
+		},
+		{
+			name: "success",
+			config: `
+				workload_x509_svid {
+					subject {
+						common_name = "{{ .Entry.Hint }}"
+					}
+				}
+			`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			plugintest.Load(t, template.BuiltIn(), nil,
+				plugintest.CaptureConfigureError(&err),
+				plugintest.CoreConfig(catalog.CoreConfig{TrustDomain: td}),
+				plugintest.Configure(tt.config),
+			)
+			if tt.expectErr != "" {
+				spiretest.RequireGRPCStatusContains(t, err, codes.InvalidArgument, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestComposeNotConfigured(t *testing.T) {
+	cc := new(credentialcomposer.V1)
+	plugintest.Load(t, template.BuiltIn(), cc)
+
+	_, err := cc.ComposeWorkloadX509SVID(ctx, workloadID, key.Public(), svidContext, credentialcomposer.X509SVIDAttributes{})
+	spiretest.RequireGRPCStatus(t, err, codes.FailedPrecondition, "credentialcomposer(template): not configured")
+}
+
+func TestComposeWorkloadX509SVID(t *testing.T) {
+	cc := loadPlugin(t, `
+		workload_x509_svid {
+			subject {
+				common_name = "{{ selector .Entry.Selectors \"k8s\" \"sa\" }}"
+				organizational_unit = ["{{ selector .Entry.Selectors \"k8s\" \"ns\" }}", "{{ selector .Entry.Selectors \"k8s\" \"pod-name\" }}"]
+				organization = ["{{ upper (selector .AgentSelectors \"k8s_psat\" \"cluster\") }}"]
+			}
+			dns_names = [
+				"{{ .Entry.Hint }}.{{ selector .Entry.Selectors \"k8s\" \"ns\" }}.svc",
+				"billing.payments.svc",
+			]
+		}
+	`)
+
+	t.Run("with entry and agent context", func(t *testing.T) {
+		got, err := cc.ComposeWorkloadX509SVID(ctx, workloadID, key.Public(), svidContext, credentialcomposer.X509SVIDAttributes{
+			Subject: pkix.Name{
+				Country:    []string{"US"},
+				CommonName: "billing.payments.svc",
+			},
+			DNSNames: []string{"billing.payments.svc"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, credentialcomposer.X509SVIDAttributes{
+			Subject: pkix.Name{
+				Country:            []string{"US"},
+				Organization:       []string{"DEMO"},
+				OrganizationalUnit: []string{"payments"},
+				CommonName:         "billing",
+			},
+			DNSNames: []string{"billing.payments.svc"},
+		}, got)
+	})
+
+	t.Run("without context", func(t *testing.T) {
+		// Values that render empty leave the subject unchanged and are not
+		// added as DNS names. The ".svc" name is invalid without the entry.
+		_, err := cc.ComposeWorkloadX509SVID(ctx, workloadID, key.Public(), credentialcomposer.SVIDContext{}, credentialcomposer.X509SVIDAttributes{})
+		spiretest.RequireGRPCStatusContains(t, err, codes.Internal, `rendered DNS name "..svc" is invalid`)
+	})
+}
+
+func TestComposeWorkloadX509SVIDKeepsUntemplatedFields(t *testing.T) {
+	cc := loadPlugin(t, `
+		workload_x509_svid {
+			subject {
+				common_name = "{{ selector .Entry.Selectors \"k8s\" \"sa\" }}"
+				organizational_unit = ["{{ selector .Entry.Selectors \"k8s\" \"ns\" }}"]
+			}
+			dns_names = [
+				"{{ with selector .Entry.Selectors \"k8s\" \"sa\" }}{{ . }}.{{ selector $.Entry.Selectors \"k8s\" \"ns\" }}.svc{{ end }}",
+			]
+		}
+	`)
+
+	attributes := credentialcomposer.X509SVIDAttributes{
+		Subject: pkix.Name{
+			Country:            []string{"US"},
+			Organization:       []string{"SPIRE"},
+			OrganizationalUnit: []string{"default"},
+			CommonName:         "default",
+		},
+	}
+	got, err := cc.ComposeWorkloadX509SVID(ctx, workloadID, key.Public(), credentialcomposer.SVIDContext{}, attributes)
+	require.NoError(t, err)
+	assert.Equal(t, attributes, got)
+}
+
+func TestComposeAgentX509SVID(t *testing.T) {
+	cc := loadPlugin(t, `
+		agent_x509_svid {
+			subject {
+				common_name = "{{ .Path }}"
+				organizational_unit = ["{{ selector .AgentSelectors \"k8s_psat\" \"cluster\" }}"]
+			}
+		}
+	`)
+
+	got, err := cc.ComposeAgentX509SVID(ctx, agentID, key.Public(), credentialcomposer.SVIDContext{
+		AgentID:        agentID,
+		AgentSelectors: svidContext.AgentSelectors,
+	}, credentialcomposer.X509SVIDAttributes{})
+	require.NoError(t, err)
+	assert.Equal(t, credentialcomposer.X509SVIDAttributes{
+		Subject: pkix.Name{
+			OrganizationalUnit: []string{"demo"},
+			CommonName:         "/spire/agent/k8s_psat/demo/node1",
+		},
+	}, got)
+
+	t.Run("workload SVIDs unchanged", func(t *testing.T) {
+		want := credentialcomposer.X509SVIDAttributes{Subject: pkix.Name{CommonName: "unchanged"}}
+		got, err := cc.ComposeWorkloadX509SVID(ctx, workloadID, key.Public(), svidContext, want)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+}
+
+func TestComposeWorkloadJWTSVID(t *testing.T) {
+	cc := loadPlugin(t, `
+		workload_jwt_svid {
+			claims {
+				namespace = "{{ selector .Entry.Selectors \"k8s\" \"ns\" }}"
+				cluster = "{{ selector .AgentSelectors \"k8s_psat\" \"cluster\" }}"
+				hint = "{{ .Entry.Hint }}"
+			}
+		}
+	`)
+
+	got, err := cc.ComposeWorkloadJWTSVID(ctx, workloadID, svidContext, credentialcomposer.JWTSVIDAttributes{
+		Claims: map[string]any{"sub": workloadID.String()},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, credentialcomposer.JWTSVIDAttributes{
+		Claims: map[string]any{
+			"sub":       workloadID.String(),
+			"namespace": "payments",
+			"cluster":   "demo",
+			"hint":      "billing",
+		},
+	}, got)
+
+	t.Run("claims that render empty are omitted", func(t *testing.T) {
+		got, err := cc.ComposeWorkloadJWTSVID(ctx, workloadID, credentialcomposer.SVIDContext{}, credentialcomposer.JWTSVIDAttributes{
+			Claims: map[string]any{"sub": workloadID.String()},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, credentialcomposer.JWTSVIDAttributes{
+			Claims: map[string]any{"sub": workloadID.String()},
+		}, got)
+	})
+
+	t.Run("template fails", func(t *testing.T) {
+		cc := loadPlugin(t, `
+			workload_jwt_svid {
+				claims {
+					dns = "{{ index .Entry.DNSNames 1 }}"
+				}
+			}
+		`)
+		_, err := cc.ComposeWorkloadJWTSVID(ctx, workloadID, svidContext, credentialcomposer.JWTSVIDAttributes{
+			Claims: map[string]any{"sub": workloadID.String()},
+		})
+		spiretest.RequireGRPCStatusContains(t, err, codes.Internal, `failed to render "dns" claim`)
+	})
+}
+
+func loadPlugin(t *testing.T, config string) credentialcomposer.CredentialComposer {
+	cc := new(credentialcomposer.V1)
+	plugintest.Load(t, template.BuiltIn(), cc,
+		plugintest.CoreConfig(catalog.CoreConfig{TrustDomain: td}),
+		plugintest.Configure(config),
+	)
+	return cc
+}
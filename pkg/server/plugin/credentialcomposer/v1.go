@@ -49,7 +49,7 @@ func (v1 V1) ComposeServerX509SVID(ctx context.Context, attributes X509SVIDAttri
 	return v1.handleX509SVIDAttributesResponse(attributes, resp, err)
 }
 
-func (v1 V1) ComposeAgentX509SVID(ctx context.Context, id spiffeid.ID, publicKey crypto.PublicKey, attributes X509SVIDAttributes) (X509SVIDAttributes, error) {
+func (v1 V1) ComposeAgentX509SVID(ctx context.Context, id spiffeid.ID, publicKey crypto.PublicKey, svidContext SVIDContext, attributes X509SVIDAttributes) (X509SVIDAttributes, error) {
 	if id.IsZero() {
 		return X509SVIDAttributes{}, v1.Error(codes.Internal, "invalid agent ID: empty")
 	}
@@ -63,6 +63,10 @@ func (v1 V1) ComposeAgentX509SVID(ctx context.Context, id spiffeid.ID, publicKey
 	if err != nil {
 		return X509SVIDAttributes{}, v1.Errorf(codes.Internal, "invalid agent X509SVID attributes: %v", err)
 	}
+	ctx, err = appendSVIDContextToOutgoingContext(ctx, svidContext)
+	if err != nil {
+		return X509SVIDAttributes{}, v1.Errorf(codes.Internal, "invalid agent X509SVID context: %v", err)
+	}
 	resp, err := v1.CredentialComposerPluginClient.ComposeAgentX509SVID(ctx, &credentialcomposerv1.ComposeAgentX509SVIDRequest{
 		Attributes: attributesIn,
 		SpiffeId:   id.String(),
@@ -71,7 +75,7 @@ func (v1 V1) ComposeAgentX509SVID(ctx context.Context, id spiffeid.ID, publicKey
 	return v1.handleX509SVIDAttributesResponse(attributes, resp, err)
 }
 
-func (v1 V1) ComposeWorkloadX509SVID(ctx context.Context, id spiffeid.ID, publicKey crypto.PublicKey, attributes X509SVIDAttributes) (X509SVIDAttributes, error) {
+func (v1 V1) ComposeWorkloadX509SVID(ctx context.Context, id spiffeid.ID, publicKey crypto.PublicKey, svidContext SVIDContext, attributes X509SVIDAttributes) (X509SVIDAttributes, error) {
 	if id.IsZero() {
 		return X509SVIDAttributes{}, v1.Error(codes.Internal, "invalid workload ID: empty")
 	}
@@ -85,6 +89,10 @@ func (v1 V1) ComposeWorkloadX509SVID(ctx context.Context, id spiffeid.ID, public
 	if err != nil {
 		return X509SVIDAttributes{}, v1.Errorf(codes.Internal, "invalid workload X509SVID attributes: %v", err)
 	}
+	ctx, err = appendSVIDContextToOutgoingContext(ctx, svidContext)
+	if err != nil {
+		return X509SVIDAttributes{}, v1.Errorf(codes.Internal, "invalid workload X509SVID context: %v", err)
+	}
 	resp, err := v1.CredentialComposerPluginClient.ComposeWorkloadX509SVID(ctx, &credentialcomposerv1.ComposeWorkloadX509SVIDRequest{
 		Attributes: attributesIn,
 		SpiffeId:   id.String(),
@@ -93,7 +101,7 @@ func (v1 V1) ComposeWorkloadX509SVID(ctx context.Context, id spiffeid.ID, public
 	return v1.handleX509SVIDAttributesResponse(attributes, resp, err)
 }
 
-func (v1 V1) ComposeWorkloadJWTSVID(ctx context.Context, id spiffeid.ID, attributes JWTSVIDAttributes) (JWTSVIDAttributes, error) {
+func (v1 V1) ComposeWorkloadJWTSVID(ctx context.Context, id spiffeid.ID, svidContext SVIDContext, attributes JWTSVIDAttributes) (JWTSVIDAttributes, error) {
 	if id.IsZero() {
 		return JWTSVIDAttributes{}, v1.Error(codes.Internal, "invalid workload ID: empty")
 	}
@@ -101,6 +109,10 @@ func (v1 V1) ComposeWorkloadJWTSVID(ctx context.Context, id spiffeid.ID, attribu
 	if err != nil {
 		return JWTSVIDAttributes{}, v1.Errorf(codes.Internal, "invalid workload JWTSVID attributes: %v", err)
 	}
+	ctx, err = appendSVIDContextToOutgoingContext(ctx, svidContext)
+	if err != nil {
+		return JWTSVIDAttributes{}, v1.Errorf(codes.Internal, "invalid workload JWTSVID context: %v", err)
+	}
 	resp, err := v1.CredentialComposerPluginClient.ComposeWorkloadJWTSVID(ctx, &credentialcomposerv1.ComposeWorkloadJWTSVIDRequest{
 		SpiffeId:   id.String(),
 		Attributes: attributesIn,
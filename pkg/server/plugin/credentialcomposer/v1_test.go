@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
 	credentialcomposerv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/server/credentialcomposer/v1"
 	"github.com/spiffe/spire/pkg/common/catalog"
 	"github.com/spiffe/spire/pkg/server/plugin/credentialcomposer"
@@ -568,7 +569,7 @@ func TestV1ComposeAgentX509SVID(t *testing.T) {
 		t.Run(tt.test, func(t *testing.T) {
 			plugin := &fakeV1Plugin{err: tt.pluginErr, composeAgentX509SVIDResponseOut: tt.responseOut}
 			cc := loadV1Plugin(t, plugin)
-			attributesOut, err := cc.ComposeAgentX509SVID(context.Background(), tt.idIn, tt.publicKeyIn, tt.attributesIn)
+			attributesOut, err := cc.ComposeAgentX509SVID(context.Background(), tt.idIn, tt.publicKeyIn, credentialcomposer.SVIDContext{}, tt.attributesIn)
 			if tt.expectCode != codes.OK {
 				spiretest.RequireGRPCStatusContains(t, err, tt.expectCode, tt.expectMessage)
 				return
@@ -750,7 +751,7 @@ func TestV1ComposeWorkloadX509SVID(t *testing.T) {
 		t.Run(tt.test, func(t *testing.T) {
 			plugin := &fakeV1Plugin{err: tt.pluginErr, composeWorkloadX509SVIDResponseOut: tt.responseOut}
 			cc := loadV1Plugin(t, plugin)
-			attributesOut, err := cc.ComposeWorkloadX509SVID(context.Background(), tt.idIn, tt.publicKeyIn, tt.attributesIn)
+			attributesOut, err := cc.ComposeWorkloadX509SVID(context.Background(), tt.idIn, tt.publicKeyIn, credentialcomposer.SVIDContext{}, tt.attributesIn)
 			if tt.expectCode != codes.OK {
 				spiretest.RequireGRPCStatusContains(t, err, tt.expectCode, tt.expectMessage)
 				return
@@ -846,7 +847,7 @@ func TestV1ComposeWorkloadJWTSVID(t *testing.T) {
 		t.Run(tt.test, func(t *testing.T) {
 			plugin := &fakeV1Plugin{err: tt.pluginErr, composeWorkloadJWTSVIDResponseOut: tt.responseOut}
 			cc := loadV1Plugin(t, plugin)
-			attributesOut, err := cc.ComposeWorkloadJWTSVID(context.Background(), tt.idIn, tt.attributesIn)
+			attributesOut, err := cc.ComposeWorkloadJWTSVID(context.Background(), tt.idIn, credentialcomposer.SVIDContext{}, tt.attributesIn)
 			if tt.expectCode != codes.OK {
 				spiretest.RequireGRPCStatusContains(t, err, tt.expectCode, tt.expectMessage)
 				return
@@ -858,6 +859,65 @@ func TestV1ComposeWorkloadJWTSVID(t *testing.T) {
 	}
 }
 
+func TestV1SVIDContext(t *testing.T) {
+	agentID := spiffeid.RequireFromString("spiffe://domain.test/spire/agent/foo")
+	workloadID := spiffeid.RequireFromString("spiffe://domain.test/workload")
+	svidContext := credentialcomposer.SVIDContext{
+		Entry: &types.Entry{
+			Id:        "ENTRYID",
+			SpiffeId:  &types.SPIFFEID{TrustDomain: "domain.test", Path: "/workload"},
+			ParentId:  &types.SPIFFEID{TrustDomain: "domain.test", Path: "/spire/agent/foo"},
+			Selectors: []*types.Selector{{Type: "k8s", Value: "ns:foo"}},
+			DnsNames:  []string{"foo.example.org"},
+			Hint:      "hint",
+		},
+		AgentID:        agentID,
+		AgentSelectors: []*types.Selector{{Type: "k8s_psat", Value: "cluster:demo"}},
+	}
+
+	assertSVIDContext := func(t *testing.T, plugin *fakeV1Plugin, expected credentialcomposer.SVIDContext) {
+		require.NoError(t, plugin.svidContextErr)
+		spiretest.AssertProtoEqual(t, expected.Entry, plugin.svidContextIn.Entry)
+		assert.Equal(t, expected.AgentID, plugin.svidContextIn.AgentID)
+		spiretest.AssertProtoListEqual(t, expected.AgentSelectors, plugin.svidContextIn.AgentSelectors)
+	}
+
+	t.Run("agent X509-SVID", func(t *testing.T) {
+		plugin := &fakeV1Plugin{composeAgentX509SVIDResponseOut: &credentialcomposerv1.ComposeAgentX509SVIDResponse{}}
+		cc := loadV1Plugin(t, plugin)
+		agentContext := credentialcomposer.SVIDContext{AgentID: agentID, AgentSelectors: svidContext.AgentSelectors}
+		_, err := cc.ComposeAgentX509SVID(context.Background(), agentID, publicKey, agentContext, credentialcomposer.X509SVIDAttributes{})
+		require.NoError(t, err)
+		assertSVIDContext(t, plugin, agentContext)
+	})
+
+	t.Run("workload X509-SVID", func(t *testing.T) {
+		plugin := &fakeV1Plugin{composeWorkloadX509SVIDResponseOut: &credentialcomposerv1.ComposeWorkloadX509SVIDResponse{}}
+		cc := loadV1Plugin(t, plugin)
+		_, err := cc.ComposeWorkloadX509SVID(context.Background(), workloadID, publicKey, svidContext, credentialcomposer.X509SVIDAttributes{})
+		require.NoError(t, err)
+		assertSVIDContext(t, plugin, svidContext)
+	})
+
+	t.Run("workload JWT-SVID", func(t *testing.T) {
+		plugin := &fakeV1Plugin{composeWorkloadJWTSVIDResponseOut: &credentialcomposerv1.ComposeWorkloadJWTSVIDResponse{}}
+		cc := loadV1Plugin(t, plugin)
+		_, err := cc.ComposeWorkloadJWTSVID(context.Background(), workloadID, svidContext, credentialcomposer.JWTSVIDAttributes{
+			Claims: map[string]any{"sub": workloadID.String()},
+		})
+		require.NoError(t, err)
+		assertSVIDContext(t, plugin, svidContext)
+	})
+
+	t.Run("empty context", func(t *testing.T) {
+		plugin := &fakeV1Plugin{composeWorkloadX509SVIDResponseOut: &credentialcomposerv1.ComposeWorkloadX509SVIDResponse{}}
+		cc := loadV1Plugin(t, plugin)
+		_, err := cc.ComposeWorkloadX509SVID(context.Background(), workloadID, publicKey, credentialcomposer.SVIDContext{}, credentialcomposer.X509SVIDAttributes{})
+		require.NoError(t, err)
+		assertSVIDContext(t, plugin, credentialcomposer.SVIDContext{})
+	})
+}
+
 func loadV1Plugin(t *testing.T, plugin *fakeV1Plugin) credentialcomposer.CredentialComposer {
 	server := credentialcomposerv1.CredentialComposerPluginServer(plugin)
 	cc := new(credentialcomposer.V1)
@@ -879,6 +939,8 @@ type fakeV1Plugin struct {
 	composeWorkloadX509SVIDResponseOut *credentialcomposerv1.ComposeWorkloadX509SVIDResponse
 	composeWorkloadJWTSVIDRequestIn    *credentialcomposerv1.ComposeWorkloadJWTSVIDRequest
 	composeWorkloadJWTSVIDResponseOut  *credentialcomposerv1.ComposeWorkloadJWTSVIDResponse
+	svidContextIn                      credentialcomposer.SVIDContext
+	svidContextErr                     error
 }
 
 func (p *fakeV1Plugin) ComposeServerX509CA(_ context.Context, req *credentialcomposerv1.ComposeServerX509CARequest) (*credentialcomposerv1.ComposeServerX509CAResponse, error) {
@@ -891,18 +953,21 @@ func (p *fakeV1Plugin) ComposeServerX509SVID(_ context.Context, req *credentialc
 	return p.composeServerX509SVIDResponseOut, p.err
 }
 
-func (p *fakeV1Plugin) ComposeAgentX509SVID(_ context.Context, req *credentialcomposerv1.ComposeAgentX509SVIDRequest) (*credentialcomposerv1.ComposeAgentX509SVIDResponse, error) {
+func (p *fakeV1Plugin) ComposeAgentX509SVID(ctx context.Context, req *credentialcomposerv1.ComposeAgentX509SVIDRequest) (*credentialcomposerv1.ComposeAgentX509SVIDResponse, error) {
 	p.composeAgentX509SVIDRequestIn = req
+	p.svidContextIn, p.svidContextErr = credentialcomposer.SVIDContextFromIncomingContext(ctx)
 	return p.composeAgentX509SVIDResponseOut, p.err
 }
 
-func (p *fakeV1Plugin) ComposeWorkloadX509SVID(_ context.Context, req *credentialcomposerv1.ComposeWorkloadX509SVIDRequest) (*credentialcomposerv1.ComposeWorkloadX509SVIDResponse, error) {
+func (p *fakeV1Plugin) ComposeWorkloadX509SVID(ctx context.Context, req *credentialcomposerv1.ComposeWorkloadX509SVIDRequest) (*credentialcomposerv1.ComposeWorkloadX509SVIDResponse, error) {
 	p.composeWorkloadX509SVIDRequestIn = req
+	p.svidContextIn, p.svidContextErr = credentialcomposer.SVIDContextFromIncomingContext(ctx)
 	return p.composeWorkloadX509SVIDResponseOut, p.err
 }
 
-func (p *fakeV1Plugin) ComposeWorkloadJWTSVID(_ context.Context, req *credentialcomposerv1.ComposeWorkloadJWTSVIDRequest) (*credentialcomposerv1.ComposeWorkloadJWTSVIDResponse, error) {
+func (p *fakeV1Plugin) ComposeWorkloadJWTSVID(ctx context.Context, req *credentialcomposerv1.ComposeWorkloadJWTSVIDRequest) (*credentialcomposerv1.ComposeWorkloadJWTSVIDResponse, error) {
 	p.composeWorkloadJWTSVIDRequestIn = req
+	p.svidContextIn, p.svidContextErr = credentialcomposer.SVIDContextFromIncomingContext(ctx)
 	return p.composeWorkloadJWTSVIDResponseOut, p.err
 }
 
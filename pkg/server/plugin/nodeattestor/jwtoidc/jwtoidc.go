@@ -0,0 +1,381 @@
+package jwtoidc
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	nodeattestorv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/server/nodeattestor/v1"
+	configv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/service/common/config/v1"
+	"github.com/spiffe/spire/pkg/common/agentpathtemplate"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/common/jwtutil"
+	"github.com/spiffe/spire/pkg/common/plugin/jwtoidc"
+	"github.com/spiffe/spire/pkg/common/pluginconf"
+	nodeattestorbase "github.com/spiffe/spire/pkg/server/plugin/nodeattestor/base"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	pluginName = jwtoidc.PluginName
+
+	// Give a little leeway to the time based claims to account for clock
+	// differences between the issuer and the server.
+	tokenLeeway = time.Minute
+
+	defaultKeySetRefreshInterval = 5 * time.Minute
+)
+
+var (
+	// Accept the most common signature algorithms that are known to be
+	// secure, since issuers differ in the algorithm used to sign ID tokens.
+	allowedJWTSignatureAlgorithms = []jose.SignatureAlgorithm{
+		jose.RS256,
+		jose.RS384,
+		jose.RS512,
+		jose.ES256,
+		jose.ES384,
+		jose.ES512,
+		jose.PS256,
+		jose.PS384,
+		jose.PS512,
+		jose.EdDSA,
+	}
+)
+
+func BuiltIn() catalog.BuiltIn {
+	return builtin(New())
+}
+
+func builtin(p *AttestorPlugin) catalog.BuiltIn {
+	return catalog.MakeBuiltIn(pluginName,
+		nodeattestorv1.NodeAttestorPluginServer(p),
+		configv1.ConfigServiceServer(p),
+	)
+}
+
+// Config is the HCL configuration of the plugin.
+type Config struct {
+	// Issuer is the expected value of the "iss" claim. When neither JWKSURL
+	// nor JWKSPath are set, the key set is discovered through the OpenID
+	// configuration of the issuer.
+	Issuer string `hcl:"issuer" json:"issuer"`
+	// JWKSURL is the URL of the key set used to verify the tokens.
+	JWKSURL string `hcl:"jwks_url" json:"jwks_url"`
+	// JWKSPath is the path to a local file holding the key set used to
+	// verify the tokens.
+	JWKSPath string `hcl:"jwks_path" json:"jwks_path"`
+	// JWKSRefreshInterval is how often the key set is reloaded to pick up
+	// rotated keys.
+	JWKSRefreshInterval string `hcl:"jwks_refresh_interval" json:"jwks_refresh_interval"`
+	// Audience is the list of accepted audiences. Tokens must have at least
+	// one of them in the "aud" claim.
+	Audience []string `hcl:"audience" json:"audience"`
+	// ClaimConstraints maps claim names to the values allowed for them. A
+	// token must carry every constrained claim with one of the allowed
+	// values.
+	ClaimConstraints map[string][]string `hcl:"claim_constraints" json:"claim_constraints"`
+	// SelectorClaims lists the claims turned into selectors.
+	SelectorClaims []string `hcl:"selector_claims" json:"selector_claims"`
+	// AgentPathTemplate is the template used to build the agent ID path.
+	AgentPathTemplate string `hcl:"agent_path_template" json:"agent_path_template"`
+	// TOFU controls whether a token subject can attest a single agent ID
+	// (trust on first use). Defaults to true.
+	TOFU *bool `hcl:"tofu" json:"tofu"`
+}
+
+type attestorConfig struct {
+	td               spiffeid.TrustDomain
+	issuer           string
+	keySetProvider   *jwtutil.CachingKeySetProvider
+	audience         []string
+	claimConstraints map[string][]string
+	selectorClaims   []string
+	idPathTemplate   *agentpathtemplate.Template
+	tofu             bool
+}
+
+func buildConfig(coreConfig catalog.CoreConfig, hclText string, status *pluginconf.Status) *attestorConfig {
+	hclConfig := new(Config)
+	if err := hcl.Decode(hclConfig, hclText); err != nil {
+		status.ReportErrorf("unable to decode configuration: %v", err)
+		return nil
+	}
+
+	if hclConfig.Issuer == "" {
+		status.ReportError("issuer is required")
+	}
+	if hclConfig.JWKSURL != "" && hclConfig.JWKSPath != "" {
+		status.ReportError("jwks_url and jwks_path are mutually exclusive")
+	}
+	if len(hclConfig.Audience) == 0 {
+		status.ReportError("audience is required")
+	}
+	for claim, values := range hclConfig.ClaimConstraints {
+		if len(values) == 0 {
+			status.ReportErrorf("claim constraint for %q must allow at least one value", claim)
+		}
+	}
+
+	refreshInterval := defaultKeySetRefreshInterval
+	if hclConfig.JWKSRefreshInterval != "" {
+		var err error
+		refreshInterval, err = time.ParseDuration(hclConfig.JWKSRefreshInterval)
+		switch {
+		case err != nil:
+			status.ReportErrorf("invalid jwks_refresh_interval: %v", err)
+		case refreshInterval <= 0:
+			status.ReportError("jwks_refresh_interval must be positive")
+		}
+	}
+
+	var keySetProvider jwtutil.KeySetProvider
+	switch {
+	case hclConfig.JWKSPath != "":
+		keySetProvider = jwtutil.KeySetFile(hclConfig.JWKSPath)
+	case hclConfig.JWKSURL != "":
+		keySetProvider = jwtutil.KeySetURL(hclConfig.JWKSURL)
+	default:
+		keySetProvider = jwtutil.OIDCIssuer(hclConfig.Issuer)
+	}
+
+	selectorClaims := hclConfig.SelectorClaims
+	if len(selectorClaims) == 0 {
+		selectorClaims = []string{"sub"}
+	}
+
+	tmpl := jwtoidc.DefaultAgentPathTemplate
+	if len(hclConfig.AgentPathTemplate) > 0 {
+		var err error
+		tmpl, err = agentpathtemplate.Parse(hclConfig.AgentPathTemplate)
+		if err != nil {
+			status.ReportErrorf("failed to parse agent path template: %q", hclConfig.AgentPathTemplate)
+		}
+	}
+
+	tofu := true
+	if hclConfig.TOFU != nil {
+		tofu = *hclConfig.TOFU
+	}
+
+	return &attestorConfig{
+		td:               coreConfig.TrustDomain,
+		issuer:           hclConfig.Issuer,
+		keySetProvider:   jwtutil.NewCachingKeySetProvider(keySetProvider, refreshInterval),
+		audience:         hclConfig.Audience,
+		claimConstraints: hclConfig.ClaimConstraints,
+		selectorClaims:   selectorClaims,
+		idPathTemplate:   tmpl,
+		tofu:             tofu,
+	}
+}
+
+// AttestorPlugin attests agents presenting an OIDC ID token or any other JWT
+// signed by a configured issuer.
+type AttestorPlugin struct {
+	nodeattestorbase.Base
+	nodeattestorv1.UnsafeNodeAttestorServer
+	configv1.UnsafeConfigServer
+
+	log hclog.Logger
+
+	mu     sync.RWMutex
+	config *attestorConfig
+
+	hooks struct {
+		now func() time.Time
+	}
+}
+
+var _ nodeattestorv1.NodeAttestorServer = (*AttestorPlugin)(nil)
+
+func New() *AttestorPlugin {
+	p := &AttestorPlugin{}
+	p.hooks.now = time.Now
+	return p
+}
+
+func (p *AttestorPlugin) SetLogger(log hclog.Logger) {
+	p.log = log
+}
+
+func (p *AttestorPlugin) Attest(stream nodeattestorv1.NodeAttestor_AttestServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	config, err := p.getConfig()
+	if err != nil {
+		return err
+	}
+
+	payload := req.GetPayload()
+	if payload == nil {
+		return status.Error(codes.InvalidArgument, "missing attestation payload")
+	}
+
+	attestationData := new(jwtoidc.AttestationData)
+	if err := json.Unmarshal(payload, attestationData); err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to unmarshal data payload: %v", err)
+	}
+
+	if attestationData.Token == "" {
+		return status.Error(codes.InvalidArgument, "missing token from attestation data")
+	}
+
+	token, err := jwt.ParseSigned(attestationData.Token, allowedJWTSignatureAlgorithms)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "unable to parse token: %v", err)
+	}
+
+	keySet, err := config.keySetProvider.GetKeySet(stream.Context())
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to obtain JWKS: %v", err)
+	}
+
+	keyID, err := getTokenKeyID(token)
+	if err != nil {
+		return err
+	}
+
+	keys := keySet.Key(keyID)
+	if len(keys) == 0 {
+		// The issuer may have rotated its keys since the key set was
+		// cached. The refresh is rate limited by the provider.
+		keySet, err = config.keySetProvider.RefreshKeySet(stream.Context())
+		if err != nil {
+			return status.Errorf(codes.Internal, "unable to obtain JWKS: %v", err)
+		}
+		keys = keySet.Key(keyID)
+	}
+	if len(keys) == 0 {
+		return status.Errorf(codes.InvalidArgument, "key id %q not found", keyID)
+	}
+	key := &keys[0]
+
+	claims := new(jwt.Claims)
+	allClaims := make(map[string]any)
+	if err := token.Claims(key, claims, &allClaims); err != nil {
+		return status.Errorf(codes.InvalidArgument, "unable to verify token: %v", err)
+	}
+
+	if err := claims.ValidateWithLeeway(jwt.Expected{
+		Issuer:      config.issuer,
+		AnyAudience: config.audience,
+		Time:        p.hooks.now(),
+	}, tokenLeeway); err != nil {
+		return status.Errorf(codes.PermissionDenied, "unable to validate token claims: %v", err)
+	}
+
+	switch {
+	case claims.Subject == "":
+		return status.Error(codes.InvalidArgument, "token missing subject claim")
+	case claims.Expiry == nil:
+		return status.Error(codes.InvalidArgument, "token missing expiration claim")
+	}
+
+	if err := checkClaimConstraints(allClaims, config.claimConstraints); err != nil {
+		return err
+	}
+
+	agentID, err := jwtoidc.MakeAgentID(config.td, config.idPathTemplate, allClaims)
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to make agent ID: %v", err)
+	}
+
+	if config.tofu {
+		if err := p.AssessTOFU(stream.Context(), agentID.String(), p.log); err != nil {
+			return err
+		}
+	}
+
+	return stream.Send(&nodeattestorv1.AttestResponse{
+		Response: &nodeattestorv1.AttestResponse_AgentAttributes{
+			AgentAttributes: &nodeattestorv1.AgentAttributes{
+				SpiffeId:       agentID.String(),
+				CanReattest:    !config.tofu,
+				SelectorValues: buildSelectorValues(allClaims, config.selectorClaims),
+			},
+		},
+	})
+}
+
+func (p *AttestorPlugin) Configure(_ context.Context, req *configv1.ConfigureRequest) (*configv1.ConfigureResponse, error) {
+	newConfig, _, err := pluginconf.Build(req, buildConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.config = newConfig
+
+	return &configv1.ConfigureResponse{}, nil
+}
+
+func (p *AttestorPlugin) Validate(_ context.Context, req *configv1.ValidateRequest) (*configv1.ValidateResponse, error) {
+	_, notes, err := pluginconf.Build(req, buildConfig)
+
+	return &configv1.ValidateResponse{
+		Valid: err == nil,
+		Notes: notes,
+	}, nil
+}
+
+func (p *AttestorPlugin) getConfig() (*attestorConfig, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.config == nil {
+		return nil, status.Error(codes.FailedPrecondition, "not configured")
+	}
+	return p.config, nil
+}
+
+func getTokenKeyID(token *jwt.JSONWebToken) (string, error) {
+	for _, h := range token.Headers {
+		if h.KeyID != "" {
+			return h.KeyID, nil
+		}
+	}
+	return "", status.Error(codes.InvalidArgument, "token missing key id")
+}
+
+func checkClaimConstraints(claims map[string]any, constraints map[string][]string) error {
+	// Sort the claim names so the error reported is deterministic
+	names := make([]string, 0, len(constraints))
+	for name := range constraints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		values := jwtoidc.ClaimValues(claims, name)
+		if !slices.ContainsFunc(values, func(value string) bool {
+			return slices.Contains(constraints[name], value)
+		}) {
+			return status.Errorf(codes.PermissionDenied, "claim %q does not have an allowed value", name)
+		}
+	}
+	return nil
+}
+
+func buildSelectorValues(claims map[string]any, selectorClaims []string) []string {
+	var selectorValues []string
+	for _, name := range selectorClaims {
+		for _, value := range jwtoidc.ClaimValues(claims, name) {
+			selectorValues = append(selectorValues, name+":"+value)
+		}
+	}
+	sort.Strings(selectorValues)
+	return slices.Compact(selectorValues)
+}
@@ -0,0 +1,424 @@
+package jwtoidc
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	agentstorev1 "github.com/spiffe/spire-plugin-sdk/proto/spire/hostservice/server/agentstore/v1"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/common/plugin/jwtoidc"
+	"github.com/spiffe/spire/pkg/server/plugin/nodeattestor"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/spiffe/spire/test/fakes/fakeagentstore"
+	"github.com/spiffe/spire/test/plugintest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/spiffe/spire/test/testkey"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	testIssuer = "https://token.example.org"
+	testKeyID  = "KEYID"
+)
+
+var (
+	now = time.Now().Truncate(time.Second)
+
+	testKey  = testkey.MustEC256()
+	otherKey = testkey.MustEC256()
+)
+
+func TestConfigure(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		config    string
+		expectErr string
+	}{
+		{
+			name:      "malformed",
+			config:    "blah",
+			expectErr: "unable to decode configuration",
+		},
+		{
+			name:      "missing issuer",
+			config:    `audience = ["spire"]`,
+			expectErr: "issuer is required",
+		},
+		{
+			name:      "missing audience",
+			config:    `issuer = "https://token.example.org"`,
+			expectErr: "audience is required",
+		},
+		{
+			name: "jwks_url and jwks_path",
+			config: `
+				issuer = "https://token.example.org"
+				audience = ["spire"]
+				jwks_url = "https://token.example.org/keys"
+				jwks_path = "/keys.json"
+			`,
+			expectErr: "jwks_url and jwks_path are mutually exclusive",
+		},
+		{
+			name: "invalid refresh interval",
+			config: `
+				issuer = "https://token.example.org"
+				audience = ["spire"]
+				jwks_refresh_interval = "soon"
+			`,
+			expectErr: `invalid jwks_refresh_interval: time: invalid duration "soon"`,
+		},
+		{
+			name: "non-positive refresh interval",
+			config: `
+				issuer = "https://token.example.org"
+				audience = ["spire"]
+				jwks_refresh_interval = "0s"
+			`,
+			expectErr: "jwks_refresh_interval must be positive",
+		},
+		{
+			name: "empty claim constraint",
+			config: `
+				issuer = "https://token.example.org"
+				audience = ["spire"]
+				claim_constraints = { repository = [] }
+			`,
+			expectErr: `claim constraint for "repository" must allow at least one value`,
+		},
+		{
+			name: "invalid agent path template",
+			config: `
+				issuer = "https://token.example.org"
+				audience = ["spire"]
+				agent_path_template = "/{{ .Subject"
+			`,
+			expectErr: "failed to parse agent path template",
+		},
+		{
+			name: "success",
+			config: `
+				issuer = "https://token.example.org"
+				audience = ["spire"]
+			`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			plugintest.Load(t, BuiltIn(), nil,
+				plugintest.CaptureConfigureError(&err),
+				plugintest.HostServices(agentstorev1.AgentStoreServiceServer(fakeagentstore.New())),
+				plugintest.CoreConfig(catalog.CoreConfig{
+					TrustDomain: spiffeid.RequireTrustDomainFromString("example.org"),
+				}),
+				plugintest.Configure(tt.config),
+			)
+			if tt.expectErr != "" {
+				spiretest.RequireGRPCStatusContains(t, err, codes.InvalidArgument, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAttestFailsWhenNotConfigured(t *testing.T) {
+	attestor := new(nodeattestor.V1)
+	plugintest.Load(t, BuiltIn(), attestor,
+		plugintest.HostServices(agentstorev1.AgentStoreServiceServer(fakeagentstore.New())),
+	)
+	_, err := attestor.Attest(context.Background(), []byte("payload"), expectNoChallenge)
+	spiretest.RequireGRPCStatus(t, err, codes.FailedPrecondition, "nodeattestor(jwt_oidc): not configured")
+}
+
+func TestAttestWithJWKSFile(t *testing.T) {
+	jwksPath := writeKeySet(t, testKey.Public())
+	agentStore := fakeagentstore.New()
+	attestor := loadPlugin(t, agentStore, fmt.Sprintf(`
+		issuer = %q
+		jwks_path = %q
+		audience = ["spire", "other"]
+		claim_constraints = {
+			repository_owner = ["acme"]
+			ref = ["refs/heads/main", "refs/heads/release"]
+		}
+		selector_claims = ["repository", "ref", "groups", "run_attempt"]
+		agent_path_template = "/ci/{{ .Claims.repository_owner }}/{{ .Claims.run_id }}"
+	`, testIssuer, jwksPath))
+
+	for _, tt := range []struct {
+		name         string
+		token        string
+		attested     string
+		expectCode   codes.Code
+		expectMsg    string
+		expectResult *nodeattestor.AttestResult
+	}{
+		{
+			name:       "missing token",
+			token:      "",
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "nodeattestor(jwt_oidc): missing token from attestation data",
+		},
+		{
+			name:       "malformed token",
+			token:      "blah",
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "nodeattestor(jwt_oidc): unable to parse token",
+		},
+		{
+			name:       "missing key id",
+			token:      signToken(t, testKey, "", validClaims()),
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "nodeattestor(jwt_oidc): token missing key id",
+		},
+		{
+			name:       "unknown key id",
+			token:      signToken(t, testKey, "OTHER", validClaims()),
+			expectCode: codes.InvalidArgument,
+			expectMsg:  `nodeattestor(jwt_oidc): key id "OTHER" not found`,
+		},
+		{
+			name:       "bad signature",
+			token:      signToken(t, otherKey, testKeyID, validClaims()),
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "nodeattestor(jwt_oidc): unable to verify token",
+		},
+		{
+			name:       "wrong issuer",
+			token:      signToken(t, testKey, testKeyID, withClaim(validClaims(), "iss", "https://evil.example.org")),
+			expectCode: codes.PermissionDenied,
+			expectMsg:  "nodeattestor(jwt_oidc): unable to validate token claims: go-jose/go-jose/jwt: validation failed, invalid issuer claim (iss)",
+		},
+		{
+			name:       "wrong audience",
+			token:      signToken(t, testKey, testKeyID, withClaim(validClaims(), "aud", []string{"someone-else"})),
+			expectCode: codes.PermissionDenied,
+			expectMsg:  "nodeattestor(jwt_oidc): unable to validate token claims: go-jose/go-jose/jwt: validation failed, invalid audience claim (aud)",
+		},
+		{
+			name:       "expired",
+			token:      signToken(t, testKey, testKeyID, withClaim(validClaims(), "exp", now.Add(-2*time.Minute).Unix())),
+			expectCode: codes.PermissionDenied,
+			expectMsg:  "nodeattestor(jwt_oidc): unable to validate token claims: go-jose/go-jose/jwt: validation failed, token is expired (exp)",
+		},
+		{
+			name:       "missing expiration",
+			token:      signToken(t, testKey, testKeyID, withoutClaim(validClaims(), "exp")),
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "nodeattestor(jwt_oidc): token missing expiration claim",
+		},
+		{
+			name:       "missing subject",
+			token:      signToken(t, testKey, testKeyID, withoutClaim(validClaims(), "sub")),
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "nodeattestor(jwt_oidc): token missing subject claim",
+		},
+		{
+			name:       "constrained claim missing",
+			token:      signToken(t, testKey, testKeyID, withoutClaim(validClaims(), "repository_owner")),
+			expectCode: codes.PermissionDenied,
+			expectMsg:  `nodeattestor(jwt_oidc): claim "repository_owner" does not have an allowed value`,
+		},
+		{
+			name:       "constrained claim not allowed",
+			token:      signToken(t, testKey, testKeyID, withClaim(validClaims(), "ref", "refs/heads/feature")),
+			expectCode: codes.PermissionDenied,
+			expectMsg:  `nodeattestor(jwt_oidc): claim "ref" does not have an allowed value`,
+		},
+		{
+			name:       "agent path template fails",
+			token:      signToken(t, testKey, testKeyID, withClaim(validClaims(), "run_id", "not/valid:")),
+			expectCode: codes.Internal,
+			expectMsg:  "nodeattestor(jwt_oidc): unable to make agent ID",
+		},
+		{
+			name:  "success",
+			token: signToken(t, testKey, testKeyID, validClaims()),
+			expectResult: &nodeattestor.AttestResult{
+				AgentID: "spiffe://example.org/spire/agent/ci/acme/1234567890",
+				Selectors: []*common.Selector{
+					{Type: "jwt_oidc", Value: "groups:admins"},
+					{Type: "jwt_oidc", Value: "groups:developers"},
+					{Type: "jwt_oidc", Value: "ref:refs/heads/main"},
+					{Type: "jwt_oidc", Value: "repository:acme/app"},
+					{Type: "jwt_oidc", Value: "run_attempt:1"},
+				},
+			},
+		},
+		{
+			name:       "subject already attested",
+			token:      signToken(t, testKey, testKeyID, validClaims()),
+			attested:   "spiffe://example.org/spire/agent/ci/acme/1234567890",
+			expectCode: codes.PermissionDenied,
+			expectMsg:  "nodeattestor(jwt_oidc): attestation data has already been used to attest an agent",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.attested != "" {
+				agentStore.SetAgentInfo(&agentstorev1.AgentInfo{AgentId: tt.attested})
+			}
+			result, err := attestor.Attest(context.Background(), makePayload(t, tt.token), expectNoChallenge)
+			if tt.expectCode != codes.OK {
+				spiretest.RequireGRPCStatusContains(t, err, tt.expectCode, tt.expectMsg)
+				require.Nil(t, result)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectResult.AgentID, result.AgentID)
+			spiretest.RequireProtoListEqual(t, tt.expectResult.Selectors, result.Selectors)
+		})
+	}
+}
+
+func TestAttestWithTestIssuer(t *testing.T) {
+	var keySet *jose.JSONWebKeySet
+	var keySetFetches int
+	issuer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]string{"jwks_uri": "http://" + req.Host + "/keys"})
+		case "/keys":
+			keySetFetches++
+			_ = json.NewEncoder(w).Encode(keySet)
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer issuer.Close()
+
+	keySet = makeKeySet(testKey.Public())
+	agentStore := fakeagentstore.New()
+	attestor := loadPlugin(t, agentStore, fmt.Sprintf(`
+		issuer = %q
+		audience = ["spire"]
+		tofu = false
+	`, issuer.URL))
+
+	claims := withClaim(validClaims(), "iss", issuer.URL)
+	result, err := attestor.Attest(context.Background(), makePayload(t, signToken(t, testKey, testKeyID, claims)), expectNoChallenge)
+	require.NoError(t, err)
+	require.Equal(t, "spiffe://example.org/spire/agent/jwt_oidc/fc58e6ceb596cd5e8b6a7de78fd8e8fedbe9e31545e920c8e6589a16c9a98cf1", result.AgentID)
+	spiretest.RequireProtoListEqual(t, []*common.Selector{
+		{Type: "jwt_oidc", Value: "sub:repo:acme/app:ref:refs/heads/main"},
+	}, result.Selectors)
+
+	// The agent can reattest with a new token for the same subject since
+	// TOFU is disabled.
+	agentStore.SetAgentInfo(&agentstorev1.AgentInfo{AgentId: result.AgentID})
+	_, err = attestor.Attest(context.Background(), makePayload(t, signToken(t, testKey, testKeyID, claims)), expectNoChallenge)
+	require.NoError(t, err)
+
+	// Keys rotated by the issuer are not trusted until the key set is
+	// refreshed.
+	keySet = makeKeySet(otherKey.Public())
+	_, err = attestor.Attest(context.Background(), makePayload(t, signToken(t, otherKey, testKeyID, claims)), expectNoChallenge)
+	spiretest.RequireGRPCStatusContains(t, err, codes.InvalidArgument, "nodeattestor(jwt_oidc): unable to verify token")
+	require.Equal(t, 1, keySetFetches)
+
+	// Keys rotated by the issuer under a new key ID are picked up right
+	// away by refreshing the key set.
+	keySet = &jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{{Key: otherKey.Public(), KeyID: "ROTATED"}},
+	}
+	_, err = attestor.Attest(context.Background(), makePayload(t, signToken(t, otherKey, "ROTATED", claims)), expectNoChallenge)
+	require.NoError(t, err)
+	require.Equal(t, 2, keySetFetches)
+
+	// Refreshes for unknown key IDs are rate limited.
+	_, err = attestor.Attest(context.Background(), makePayload(t, signToken(t, otherKey, "UNKNOWN", claims)), expectNoChallenge)
+	spiretest.RequireGRPCStatus(t, err, codes.InvalidArgument, `nodeattestor(jwt_oidc): key id "UNKNOWN" not found`)
+	require.Equal(t, 2, keySetFetches)
+}
+
+func loadPlugin(t *testing.T, agentStore *fakeagentstore.AgentStore, config string) nodeattestor.NodeAttestor {
+	p := New()
+	p.hooks.now = func() time.Time { return now }
+
+	attestor := new(nodeattestor.V1)
+	plugintest.Load(t, builtin(p), attestor,
+		plugintest.HostServices(agentstorev1.AgentStoreServiceServer(agentStore)),
+		plugintest.CoreConfig(catalog.CoreConfig{
+			TrustDomain: spiffeid.RequireTrustDomainFromString("example.org"),
+		}),
+		plugintest.Configure(config),
+	)
+	return attestor
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"iss":              testIssuer,
+		"sub":              "repo:acme/app:ref:refs/heads/main",
+		"aud":              []string{"spire"},
+		"iat":              now.Unix(),
+		"exp":              now.Add(5 * time.Minute).Unix(),
+		"repository":       "acme/app",
+		"repository_owner": "acme",
+		"ref":              "refs/heads/main",
+		"run_id":           1234567890,
+		"run_attempt":      1,
+		"groups":           []string{"developers", "admins", "developers"},
+	}
+}
+
+func withClaim(claims map[string]any, name string, value any) map[string]any {
+	claims[name] = value
+	return claims
+}
+
+func withoutClaim(claims map[string]any, name string) map[string]any {
+	delete(claims, name)
+	return claims
+}
+
+func signToken(t *testing.T, key crypto.Signer, keyID string, claims map[string]any) string {
+	opts := new(jose.SignerOptions)
+	if keyID != "" {
+		opts = opts.WithHeader(jose.HeaderKey("kid"), keyID)
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: key}, opts)
+	require.NoError(t, err)
+
+	token, err := jwt.Signed(signer).Claims(claims).Serialize()
+	require.NoError(t, err)
+	return token
+}
+
+func makeKeySet(publicKey crypto.PublicKey) *jose.JSONWebKeySet {
+	return &jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{{Key: publicKey, KeyID: testKeyID}},
+	}
+}
+
+func writeKeySet(t *testing.T, publicKey crypto.PublicKey) string {
+	data, err := json.Marshal(makeKeySet(publicKey))
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
+
+func makePayload(t *testing.T, token string) []byte {
+	payload, err := json.Marshal(jwtoidc.AttestationData{Token: token})
+	require.NoError(t, err)
+	return payload
+}
+
+func expectNoChallenge(context.Context, []byte) ([]byte, error) {
+	return nil, errors.New("challenge is not expected")
+}
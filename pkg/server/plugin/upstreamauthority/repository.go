@@ -1,7 +1,8 @@
 package upstreamauthority
 
 type Repository struct {
-	UpstreamAuthority UpstreamAuthority
+	UpstreamAuthority    UpstreamAuthority
+	UpstreamWITAuthority UpstreamWITAuthority
 }
 
 func (repo *Repository) GetUpstreamAuthority() (UpstreamAuthority, bool) {
@@ -16,6 +17,19 @@ func (repo *Repository) ClearUpstreamAuthority() {
 	repo.UpstreamAuthority = nil
 }
 
+func (repo *Repository) GetUpstreamWITAuthority() (UpstreamWITAuthority, bool) {
+	return repo.UpstreamWITAuthority, repo.UpstreamWITAuthority != nil
+}
+
+func (repo *Repository) SetUpstreamWITAuthority(upstreamWITAuthority UpstreamWITAuthority) {
+	repo.UpstreamWITAuthority = upstreamWITAuthority
+}
+
+func (repo *Repository) ClearUpstreamWITAuthority() {
+	repo.UpstreamWITAuthority = nil
+}
+
 func (repo *Repository) Clear() {
 	repo.UpstreamAuthority = nil
+	repo.UpstreamWITAuthority = nil
 }
@@ -161,6 +161,34 @@ func (h *handler) appendKey(key *types.JWTKey) *types.Bundle {
 	return cloneBundle(h.bundle)
 }
 
+func (h *handler) appendWITKey(key *types.WITKey) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.bundle.WitAuthorities = append(h.bundle.WitAuthorities, key)
+}
+
+func (h *handler) taintWITKey(keyID string) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	for _, key := range h.bundle.WitAuthorities {
+		if key.KeyId == keyID {
+			key.Tainted = true
+		}
+	}
+}
+
+func (h *handler) removeWITKey(keyID string) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	var witAuthorities []*types.WITKey
+	for _, key := range h.bundle.WitAuthorities {
+		if key.KeyId != keyID {
+			witAuthorities = append(witAuthorities, key)
+		}
+	}
+	h.bundle.WitAuthorities = witAuthorities
+}
+
 func (h *handler) appendRootCA(rootCA *types.X509Certificate) *types.Bundle { //nolint: unparam // Keeping return for future use
 	h.mtx.Lock()
 	defer h.mtx.Unlock()
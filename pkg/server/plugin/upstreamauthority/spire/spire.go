@@ -17,10 +17,12 @@ import (
 	"github.com/spiffe/spire/pkg/common/catalog"
 	"github.com/spiffe/spire/pkg/common/coretypes/bundle"
 	"github.com/spiffe/spire/pkg/common/coretypes/jwtkey"
+	"github.com/spiffe/spire/pkg/common/coretypes/witkey"
 	"github.com/spiffe/spire/pkg/common/coretypes/x509certificate"
 	"github.com/spiffe/spire/pkg/common/idutil"
 	"github.com/spiffe/spire/pkg/common/pluginconf"
 	"github.com/spiffe/spire/pkg/common/tlspolicy"
+	"github.com/spiffe/spire/proto/private/server/upstreamwitauthority"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
@@ -68,12 +70,14 @@ func BuiltIn() catalog.BuiltIn {
 func builtin(p *Plugin) catalog.BuiltIn {
 	return catalog.MakeBuiltIn(pluginName,
 		upstreamauthorityv1.UpstreamAuthorityPluginServer(p),
+		upstreamwitauthority.UpstreamWITAuthorityServiceServer(p),
 		configv1.ConfigServiceServer(p),
 	)
 }
 
 type Plugin struct {
 	upstreamauthorityv1.UnsafeUpstreamAuthorityServer
+	upstreamwitauthority.UnsafeUpstreamWITAuthorityServer
 	configv1.UnsafeConfigServer
 
 	clk clock.Clock
@@ -270,6 +274,51 @@ func (p *Plugin) SubscribeToLocalBundle(req *upstreamauthorityv1.SubscribeToLoca
 	}
 }
 
+func (p *Plugin) SubscribeToLocalWITAuthorities(req *upstreamwitauthority.SubscribeToLocalWITAuthoritiesRequest, stream upstreamwitauthority.UpstreamWITAuthority_SubscribeToLocalWITAuthoritiesServer) error {
+	err := p.subscribeToPolling(stream.Context())
+	if err != nil {
+		return err
+	}
+	defer p.unsubscribeToPolling()
+
+	serverBundle, err := p.serverClient.getBundle(stream.Context())
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to fetch bundle from upstream server: %v", err)
+	}
+
+	witKeys, err := witkey.ToPluginFromAPIProtos(serverBundle.WitAuthorities)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to parse WIT authorities: %v", err)
+	}
+
+	if err := sendWITAuthorities(stream, witKeys); err != nil {
+		return err
+	}
+
+	p.setBundleWITAuthorities(witKeys)
+
+	ticker := p.clk.Ticker(internalPollFreq)
+	defer ticker.Stop()
+	for {
+		// Capture the notification channel before reading the bundle, for
+		// the same reason as in SubscribeToLocalBundle.
+		updateCh := p.getBundleUpdateCh()
+
+		newWITKeys := p.getBundle().WitAuthorities
+		if !areWITKeysEqual(witKeys, newWITKeys) {
+			if err := sendWITAuthorities(stream, newWITKeys); err == nil {
+				witKeys = newWITKeys
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-updateCh:
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
 func (p *Plugin) PublishJWTKeyAndSubscribe(req *upstreamauthorityv1.PublishJWTKeyRequest, stream upstreamauthorityv1.UpstreamAuthority_PublishJWTKeyAndSubscribeServer) error {
 	err := p.subscribeToPolling(stream.Context())
 	if err != nil {
@@ -398,6 +447,14 @@ func (p *Plugin) setBundleJWTAuthorities(keys []*plugintypes.JWTKey) {
 	p.signalBundleUpdate()
 }
 
+func (p *Plugin) setBundleWITAuthorities(keys []*plugintypes.WITKey) {
+	p.bundleMtx.Lock()
+	defer p.bundleMtx.Unlock()
+	p.currentBundle.WitAuthorities = keys
+	p.bundleVersion++
+	p.signalBundleUpdate()
+}
+
 func (p *Plugin) setBundleX509Authorities(rootCAs []*plugintypes.X509Certificate) {
 	p.bundleMtx.Lock()
 	defer p.bundleMtx.Unlock()
@@ -478,3 +535,28 @@ func arePublicKeysEqual(a, b []*plugintypes.JWTKey) bool {
 	}
 	return true
 }
+
+func areWITKeysEqual(a, b []*plugintypes.WITKey) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, key := range a {
+		if !proto.Equal(key, b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sendWITAuthorities sends the WIT authorities over the stream, in the form
+// they are stored in the bundle, since the UpstreamWITAuthority service is
+// not part of the plugin SDK.
+func sendWITAuthorities(stream upstreamwitauthority.UpstreamWITAuthority_SubscribeToLocalWITAuthoritiesServer, keys []*plugintypes.WITKey) error {
+	witKeys, err := witkey.ToCommonFromPluginProtos(keys)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to convert WIT authorities: %v", err)
+	}
+	return stream.Send(&upstreamwitauthority.SubscribeToLocalWITAuthoritiesResponse{
+		UpstreamWitKeys: witKeys,
+	})
+}
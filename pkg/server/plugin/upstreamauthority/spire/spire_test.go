@@ -480,6 +480,66 @@ func TestGetTrustBundle(t *testing.T) {
 	spiretest.RequireGRPCStatusHasPrefix(t, err, codes.Canceled, "upstreamauthority(spire): context canceled")
 }
 
+func TestSubscribeToLocalWITAuthorities(t *testing.T) {
+	ca := testca.New(t, trustDomain)
+	serverCert, serverKey := ca.CreateX509Certificate(
+		testca.WithID(spiffeid.RequireFromPath(trustDomain, "/spire/server")),
+	)
+	s := ca.CreateX509SVID(
+		spiffeid.RequireFromPath(trustDomain, "/workload"),
+	)
+	svidCert, svidKey, err := s.MarshalRaw()
+	require.NoError(t, err)
+
+	key := testkey.NewEC256(t)
+	pkixBytes, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+
+	// Setup servers
+	mockClock := clock.NewMock(t)
+	server := testHandler{}
+	server.startTestServers(t, mockClock, ca, serverCert, serverKey, svidCert, svidKey)
+	server.sAPIServer.appendWITKey(&types.WITKey{KeyId: "wit-kid", PublicKey: pkixBytes, ExpiresAt: 12345})
+	ua := newWITWithDefault(t, mockClock, server.sAPIServer.addr, server.wAPIServer.workloadAPIAddr)
+
+	// Get first response
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	upstreamWITKeys, stream, err := ua.SubscribeToLocalWITAuthorities(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, stream)
+	spiretest.RequireProtoListEqual(t, []*common.PublicKey{
+		{Kid: "wit-kid", PkixBytes: pkixBytes, NotAfter: 12345},
+	}, upstreamWITKeys)
+
+	// Taint the WIT authority upstream and advance the clock past the
+	// upstream poll frequency to trigger a fetch.
+	server.sAPIServer.taintWITKey("wit-kid")
+	mockClock.Add(upstreamPollFreq)
+	mockClock.Add(upstreamPollFreq)
+
+	upstreamWITKeys, err = stream.RecvUpstreamWITAuthorities()
+	require.NoError(t, err)
+	spiretest.RequireProtoListEqual(t, []*common.PublicKey{
+		{Kid: "wit-kid", PkixBytes: pkixBytes, NotAfter: 12345, TaintedKey: true},
+	}, upstreamWITKeys)
+
+	// Revoking the WIT authority upstream removes it from the bundle.
+	server.sAPIServer.removeWITKey("wit-kid")
+	mockClock.Add(upstreamPollFreq)
+	mockClock.Add(upstreamPollFreq)
+
+	upstreamWITKeys, err = stream.RecvUpstreamWITAuthorities()
+	require.NoError(t, err)
+	require.Empty(t, upstreamWITKeys)
+
+	cancel()
+
+	// Verify stream is closed
+	upstreamWITKeys, err = stream.RecvUpstreamWITAuthorities()
+	require.Nil(t, upstreamWITKeys)
+	spiretest.RequireGRPCStatusHasPrefix(t, err, codes.Canceled, "upstreamauthority(spire): context canceled")
+}
+
 func newWithDefault(t *testing.T, mockClock *clock.Mock, serverAddr string, workloadAPIAddr net.Addr) *upstreamauthority.V1 {
 	host, port, _ := net.SplitHostPort(serverAddr)
 	config := Configuration{
@@ -502,6 +562,29 @@ func newWithDefault(t *testing.T, mockClock *clock.Mock, serverAddr string, work
 	return ua
 }
 
+func newWITWithDefault(t *testing.T, mockClock *clock.Mock, serverAddr string, workloadAPIAddr net.Addr) *upstreamauthority.WITV1 {
+	host, port, _ := net.SplitHostPort(serverAddr)
+	config := Configuration{
+		ServerAddr: host,
+		ServerPort: port,
+	}
+	setWorkloadAPIAddr(&config, workloadAPIAddr)
+
+	p := New()
+	p.clk = mockClock
+
+	ua := new(upstreamauthority.WITV1)
+	plugintest.Load(t, builtin(p), nil,
+		plugintest.Services(ua),
+		plugintest.CoreConfig(catalog.CoreConfig{
+			TrustDomain: trustDomain,
+		}),
+		plugintest.ConfigureJSON(config),
+	)
+
+	return ua
+}
+
 func certChainURIs(chain []*x509.Certificate) []string {
 	var uris []string
 	for _, cert := range chain {
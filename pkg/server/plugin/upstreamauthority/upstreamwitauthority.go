@@ -0,0 +1,37 @@
+package upstreamauthority
+
+import (
+	"context"
+
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/proto/spire/common"
+)
+
+// UpstreamWITAuthority is served by UpstreamAuthority plugins that provide the
+// WIT authorities of the upstream trust domain bundle, which the
+// UpstreamAuthority interface has no support for.
+type UpstreamWITAuthority interface {
+	catalog.PluginInfo
+
+	// SubscribeToLocalWITAuthorities can be used to sync the WIT authorities
+	// of the local trust bundle with the upstream trust bundle.
+	// The function returns the latest set of upstream WIT authorities, with
+	// their tainted status, and a stream for streaming upstream WIT
+	// authority updates. The returned stream MUST be closed when the caller
+	// is no longer interested in updates. If the upstream authority does not
+	// support streaming updates, the stream will return io.EOF when called.
+	SubscribeToLocalWITAuthorities(ctx context.Context) (witAuthorities []*common.PublicKey, stream UpstreamWITAuthorityStream, err error)
+}
+
+type UpstreamWITAuthorityStream interface {
+	// RecvUpstreamWITAuthorities returns the latest set of upstream WIT
+	// authorities. The call blocks until the update is received, the Close()
+	// method is called, or the context originally passed into
+	// SubscribeToLocalWITAuthorities is canceled. If the function returns an
+	// error, no more updates will be available over the stream.
+	RecvUpstreamWITAuthorities() ([]*common.PublicKey, error)
+
+	// Close() closes the stream. It MUST be called by callers of
+	// SubscribeToLocalWITAuthorities when they are done with the stream.
+	Close()
+}
@@ -0,0 +1,95 @@
+package upstreamauthority
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/spiffe/spire/pkg/common/coretypes/witkey"
+	"github.com/spiffe/spire/pkg/common/plugin"
+	"github.com/spiffe/spire/proto/private/server/upstreamwitauthority"
+	"github.com/spiffe/spire/proto/spire/common"
+	"google.golang.org/grpc/codes"
+)
+
+type WITV1 struct {
+	plugin.Facade
+	upstreamwitauthority.UpstreamWITAuthorityServiceClient
+}
+
+// SubscribeToLocalWITAuthorities provides the V1 implementation of the
+// UpstreamWITAuthority interface method of the same name.
+func (v1 *WITV1) SubscribeToLocalWITAuthorities(ctx context.Context) (_ []*common.PublicKey, _ UpstreamWITAuthorityStream, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer func() {
+		// Only cancel the context if the function fails. Otherwise, the
+		// returned stream will be in charge of cancellation.
+		if err != nil {
+			defer cancel()
+		}
+	}()
+
+	stream, err := v1.UpstreamWITAuthorityServiceClient.SubscribeToLocalWITAuthorities(ctx, &upstreamwitauthority.SubscribeToLocalWITAuthoritiesRequest{})
+	if err != nil {
+		return nil, nil, v1.WrapErr(err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, nil, v1.streamError(err)
+	}
+
+	witKeys, err := v1.parseWITKeys(resp.UpstreamWitKeys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return witKeys, &v1UpstreamWITAuthorityStream{v1: v1, stream: stream, cancel: cancel}, nil
+}
+
+func (v1 *WITV1) streamError(err error) error {
+	if errors.Is(err, io.EOF) {
+		return v1.Error(codes.Internal, "plugin closed stream unexpectedly")
+	}
+	return v1.WrapErr(err)
+}
+
+// parseWITKeys validates the WIT keys in the plugin response. The keys are
+// already in the form stored in the bundle.
+func (v1 *WITV1) parseWITKeys(pbs []*common.PublicKey) ([]*common.PublicKey, error) {
+	if _, err := witkey.FromCommonProtos(pbs); err != nil {
+		return nil, v1.Errorf(codes.Internal, "invalid plugin response: %v", err)
+	}
+	return pbs, nil
+}
+
+type v1UpstreamWITAuthorityStream struct {
+	v1     *WITV1
+	stream upstreamwitauthority.UpstreamWITAuthority_SubscribeToLocalWITAuthoritiesClient
+	cancel context.CancelFunc
+}
+
+func (s *v1UpstreamWITAuthorityStream) RecvUpstreamWITAuthorities() ([]*common.PublicKey, error) {
+	for {
+		resp, err := s.stream.Recv()
+		switch {
+		case errors.Is(err, io.EOF):
+			// This is expected if the plugin does not support streaming
+			// authority updates.
+			return nil, io.EOF
+		case err != nil:
+			return nil, s.v1.WrapErr(err)
+		}
+
+		witKeys, err := s.v1.parseWITKeys(resp.UpstreamWitKeys)
+		if err != nil {
+			s.v1.Log.WithError(err).Warn("Failed to parse a WIT key update from the upstream authority plugin. Please report this bug.")
+			continue
+		}
+		return witKeys, nil
+	}
+}
+
+func (s *v1UpstreamWITAuthorityStream) Close() {
+	s.cancel()
+}
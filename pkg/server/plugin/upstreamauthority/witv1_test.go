@@ -0,0 +1,237 @@
+package upstreamauthority_test
+
+import (
+	"crypto/x509"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	upstreamauthorityv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/server/upstreamauthority/v1"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/server/plugin/upstreamauthority"
+	"github.com/spiffe/spire/proto/private/server/upstreamwitauthority"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/spiffe/spire/test/plugintest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/spiffe/spire/test/testkey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestWITV1SubscribeToLocalWITAuthorities(t *testing.T) {
+	pkixBytes, err := x509.MarshalPKIXPublicKey(testkey.NewEC256(t).Public())
+	require.NoError(t, err)
+
+	witKeys := []*common.PublicKey{
+		{
+			Kid:       "UPSTREAM KEY",
+			PkixBytes: pkixBytes,
+			NotAfter:  12345,
+		},
+	}
+	taintedWITKeys := []*common.PublicKey{
+		{
+			Kid:        "UPSTREAM KEY",
+			PkixBytes:  pkixBytes,
+			NotAfter:   12345,
+			TaintedKey: true,
+		},
+	}
+	invalidWITKeys := []*common.PublicKey{
+		{
+			Kid:       "UPSTREAM KEY",
+			PkixBytes: []byte("malformed"),
+		},
+	}
+
+	builder := BuildWITV1()
+	for _, tt := range []struct {
+		test                string
+		builder             *WITV1Builder
+		expectCode          codes.Code
+		expectMessage       string
+		expectStreamUpdates bool
+		expectStreamCode    codes.Code
+		expectStreamMessage string
+		expectStreamKeys    []*common.PublicKey
+		expectLogs          []spiretest.LogEntry
+	}{
+		{
+			test:          "plugin returns before sending first response",
+			builder:       builder.WithPreSendError(nil),
+			expectCode:    codes.Internal,
+			expectMessage: "upstreamauthority(test): plugin closed stream unexpectedly",
+		},
+		{
+			test:          "plugin fails before sending first response",
+			builder:       builder.WithPreSendError(errors.New("ohno")),
+			expectCode:    codes.Unknown,
+			expectMessage: "upstreamauthority(test): ohno",
+		},
+		{
+			test:          "plugin sends invalid WIT keys",
+			builder:       builder.WithWITKeysResponse(invalidWITKeys),
+			expectCode:    codes.Internal,
+			expectMessage: "upstreamauthority(test): invalid plugin response: ",
+		},
+		{
+			test:    "success but plugin does not support streaming updates",
+			builder: builder.WithWITKeysResponse(witKeys),
+		},
+		{
+			test: "success and plugin streams the tainted status",
+			builder: builder.
+				WithWITKeysResponse(witKeys).
+				WithWITKeysResponse(taintedWITKeys),
+			expectStreamUpdates: true,
+			expectStreamKeys:    taintedWITKeys,
+		},
+		{
+			test: "plugin streams invalid WIT keys",
+			builder: builder.
+				WithWITKeysResponse(witKeys).
+				WithWITKeysResponse(invalidWITKeys).
+				WithWITKeysResponse(taintedWITKeys),
+			expectStreamUpdates: true,
+			expectStreamKeys:    taintedWITKeys,
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.WarnLevel,
+					Message: "Failed to parse a WIT key update from the upstream authority plugin. Please report this bug.",
+					Data: logrus.Fields{
+						logrus.ErrorKey: "rpc error: code = Internal desc = upstreamauthority(test): invalid plugin response: failed to unmarshal public key for WIT key \"UPSTREAM KEY\": asn1: structure error: tags don't match (16 vs {class:1 tag:13 length:97 isCompound:true}) {optional:false explicit:false application:false private:false defaultValue:<nil> tag:<nil> stringType:0 timeType:0 set:false omitEmpty:false} publicKeyInfo @2",
+					},
+				},
+			},
+		},
+		{
+			test: "plugin fails to stream updates",
+			builder: builder.
+				WithWITKeysResponse(witKeys).
+				WithPostSendError(errors.New("ohno")),
+			expectStreamUpdates: true,
+			expectStreamCode:    codes.Unknown,
+			expectStreamMessage: "upstreamauthority(test): ohno",
+		},
+	} {
+		t.Run(tt.test, func(t *testing.T) {
+			log, logHook := test.NewNullLogger()
+
+			ua := tt.builder.WithLog(log).Load(t)
+
+			upstreamWITKeys, stream, err := ua.SubscribeToLocalWITAuthorities(t.Context())
+			spiretest.RequireGRPCStatusHasPrefix(t, err, tt.expectCode, tt.expectMessage)
+			if tt.expectCode != codes.OK {
+				return
+			}
+			require.NotNil(t, stream, "valid stream should have been returned")
+			defer stream.Close()
+			spiretest.AssertProtoListEqual(t, witKeys, upstreamWITKeys)
+
+			upstreamWITKeys, err = stream.RecvUpstreamWITAuthorities()
+			switch {
+			case !tt.expectStreamUpdates:
+				assert.Equal(t, io.EOF, err, "stream should have returned EOF")
+				assert.Nil(t, upstreamWITKeys, "no keys should be received")
+			case tt.expectStreamCode == codes.OK:
+				assert.NoError(t, err, "stream should have returned update")
+				spiretest.AssertProtoListEqual(t, tt.expectStreamKeys, upstreamWITKeys)
+			default:
+				spiretest.RequireGRPCStatusHasPrefix(t, err, tt.expectStreamCode, tt.expectStreamMessage)
+				assert.Nil(t, upstreamWITKeys)
+			}
+
+			spiretest.AssertLogs(t, logHook.AllEntries(), tt.expectLogs)
+		})
+	}
+}
+
+type WITV1Builder struct {
+	p   *witV1Plugin
+	log logrus.FieldLogger
+}
+
+func BuildWITV1() *WITV1Builder {
+	return new(WITV1Builder)
+}
+
+func (b *WITV1Builder) WithLog(log logrus.FieldLogger) *WITV1Builder {
+	b = b.clone()
+	b.log = log
+	return b
+}
+
+func (b *WITV1Builder) WithPreSendError(err error) *WITV1Builder {
+	b = b.clone()
+	b.p.preSendErr = &err
+	return b
+}
+
+func (b *WITV1Builder) WithPostSendError(err error) *WITV1Builder {
+	b = b.clone()
+	b.p.postSendErr = err
+	return b
+}
+
+func (b *WITV1Builder) WithWITKeysResponse(witKeys []*common.PublicKey) *WITV1Builder {
+	b = b.clone()
+	b.p.responses = append(b.p.responses, &upstreamwitauthority.SubscribeToLocalWITAuthoritiesResponse{
+		UpstreamWitKeys: witKeys,
+	})
+	return b
+}
+
+func (b *WITV1Builder) clone() *WITV1Builder {
+	return &WITV1Builder{
+		p:   b.p.clone(),
+		log: b.log,
+	}
+}
+
+func (b *WITV1Builder) Load(t *testing.T) upstreamauthority.UpstreamWITAuthority {
+	p := b.clone().p
+	server := upstreamauthorityv1.UpstreamAuthorityPluginServer(&v1Plugin{})
+	witServer := upstreamwitauthority.UpstreamWITAuthorityServiceServer(p)
+
+	ua := new(upstreamauthority.WITV1)
+	opts := []plugintest.Option{plugintest.Services(ua)}
+	if b.log != nil {
+		opts = append(opts, plugintest.Log(b.log))
+	}
+
+	plugintest.Load(t, catalog.MakeBuiltIn("test", server, witServer), nil, opts...)
+	return ua
+}
+
+type witV1Plugin struct {
+	upstreamwitauthority.UnimplementedUpstreamWITAuthorityServer
+
+	preSendErr  *error
+	postSendErr error
+	responses   []*upstreamwitauthority.SubscribeToLocalWITAuthoritiesResponse
+}
+
+func (p *witV1Plugin) SubscribeToLocalWITAuthorities(_ *upstreamwitauthority.SubscribeToLocalWITAuthoritiesRequest, stream upstreamwitauthority.UpstreamWITAuthority_SubscribeToLocalWITAuthoritiesServer) error {
+	if p.preSendErr != nil {
+		return *p.preSendErr
+	}
+
+	for _, response := range p.responses {
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+	}
+
+	return p.postSendErr
+}
+
+func (p *witV1Plugin) clone() *witV1Plugin {
+	if p == nil {
+		return &witV1Plugin{}
+	}
+	clone := *p
+	return &clone
+}
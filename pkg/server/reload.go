@@ -0,0 +1,94 @@
+package server
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	bundle_client "github.com/spiffe/spire/pkg/server/bundle/client"
+	"github.com/spiffe/spire/pkg/server/credtemplate"
+	"github.com/spiffe/spire/pkg/server/endpoints"
+)
+
+// ReloadableConfig holds the server settings that can be changed while the
+// server is running.
+type ReloadableConfig struct {
+	AdminIDs        []spiffeid.ID
+	FederatesWith   map[spiffeid.TrustDomain]bundle_client.TrustDomainConfig
+	RateLimit       endpoints.RateLimitConfig
+	X509SVIDTTL     time.Duration
+	JWTSVIDTTL      time.Duration
+	LogLevel        logrus.Level
+	AuditLogEnabled bool
+}
+
+type liveComponents struct {
+	endpoints     *endpoints.Endpoints
+	federatesWith *bundle_client.TrustDomainConfigSet
+	bundleManager *bundle_client.Manager
+	credBuilder   *credtemplate.Builder
+
+	// applied holds the settings last applied to the components
+	applied ReloadableConfig
+}
+
+// UpdateConfig applies the reloadable settings to the running server. Only
+// the settings that differ from the ones last applied are updated, so the
+// log level set through the Logger API is kept unless the configured level
+// changes.
+func (s *Server) UpdateConfig(config ReloadableConfig) error {
+	s.liveMtx.Lock()
+	defer s.liveMtx.Unlock()
+
+	live := s.live
+	if live == nil {
+		return errors.New("server is not running")
+	}
+	applied := live.applied
+
+	if !slices.Equal(applied.AdminIDs, config.AdminIDs) ||
+		applied.RateLimit != config.RateLimit ||
+		applied.AuditLogEnabled != config.AuditLogEnabled {
+		live.endpoints.UpdateMiddleware(endpoints.MiddlewareConfig{
+			RateLimit:       config.RateLimit,
+			AuditLogEnabled: config.AuditLogEnabled,
+			AdminIDs:        config.AdminIDs,
+		})
+	}
+	if !maps.Equal(applied.FederatesWith, config.FederatesWith) {
+		live.federatesWith.SetAll(config.FederatesWith)
+		live.bundleManager.TriggerConfigReload()
+	}
+	if applied.X509SVIDTTL != config.X509SVIDTTL || applied.JWTSVIDTTL != config.JWTSVIDTTL {
+		live.credBuilder.SetDefaultSVIDTTLs(config.X509SVIDTTL, config.JWTSVIDTTL)
+	}
+	if applied.LogLevel != config.LogLevel {
+		s.config.Log.SetLevel(config.LogLevel)
+	}
+
+	live.applied = config
+	return nil
+}
+
+func (s *Server) setLive(live *liveComponents) {
+	s.liveMtx.Lock()
+	defer s.liveMtx.Unlock()
+	s.live = live
+}
+
+// Reloadable returns the settings of the configuration that can be changed
+// while the server is running.
+func (c *Config) Reloadable() ReloadableConfig {
+	return ReloadableConfig{
+		AdminIDs:        c.AdminIDs,
+		FederatesWith:   c.Federation.FederatesWith,
+		RateLimit:       c.RateLimit,
+		X509SVIDTTL:     c.X509SVIDTTL,
+		JWTSVIDTTL:      c.JWTSVIDTTL,
+		LogLevel:        c.Log.GetLevel(),
+		AuditLogEnabled: c.AuditLogEnabled,
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	bundle_client "github.com/spiffe/spire/pkg/server/bundle/client"
+	"github.com/spiffe/spire/pkg/server/credtemplate"
+	"github.com/spiffe/spire/pkg/server/endpoints"
+	"github.com/spiffe/spire/test/fakes/fakedatastore"
+	"github.com/spiffe/spire/test/fakes/fakemetrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateConfig(t *testing.T) {
+	td := spiffeid.RequireTrustDomainFromString("example.org")
+	adminID := spiffeid.RequireFromString("spiffe://example.org/admin")
+	federatedTD := spiffeid.RequireTrustDomainFromString("domain1.test")
+
+	log, _ := test.NewNullLogger()
+	log.SetLevel(logrus.InfoLevel)
+	s := New(Config{
+		Log:         log,
+		TrustDomain: td,
+	})
+
+	require.EqualError(t, s.UpdateConfig(ReloadableConfig{}), "server is not running")
+
+	credBuilder, err := credtemplate.NewBuilder(credtemplate.Config{TrustDomain: td})
+	require.NoError(t, err)
+	federatesWith := bundle_client.NewTrustDomainConfigSet(nil)
+	e := &endpoints.Endpoints{Log: log}
+	s.setLive(&liveComponents{
+		endpoints:     e,
+		federatesWith: federatesWith,
+		bundleManager: bundle_client.NewManager(bundle_client.ManagerConfig{
+			Log:       log,
+			Metrics:   fakemetrics.New(),
+			DataStore: fakedatastore.New(t),
+			Source:    federatesWith,
+		}),
+		credBuilder: credBuilder,
+		applied:     s.config.Reloadable(),
+	})
+
+	// The log level changed through the Logger API is kept while the
+	// configured level does not change
+	log.SetLevel(logrus.WarnLevel)
+	config := ReloadableConfig{
+		AdminIDs: []spiffeid.ID{adminID},
+		FederatesWith: map[spiffeid.TrustDomain]bundle_client.TrustDomainConfig{
+			federatedTD: {EndpointURL: "https://domain1.test/bundle", EndpointProfile: bundle_client.HTTPSWebProfile{}},
+		},
+		RateLimit:       endpoints.RateLimitConfig{Attestation: true},
+		X509SVIDTTL:     2 * time.Hour,
+		JWTSVIDTTL:      10 * time.Minute,
+		LogLevel:        logrus.InfoLevel,
+		AuditLogEnabled: true,
+	}
+	require.NoError(t, s.UpdateConfig(config))
+	require.Equal(t, logrus.WarnLevel, log.GetLevel())
+
+	require.Equal(t, []spiffeid.ID{adminID}, e.AdminIDs)
+	require.Equal(t, endpoints.RateLimitConfig{Attestation: true}, e.RateLimit)
+	require.True(t, e.AuditLogEnabled)
+
+	configs, err := federatesWith.GetTrustDomainConfigs(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, config.FederatesWith, configs)
+
+	builderConfig := credBuilder.Config()
+	require.Equal(t, 2*time.Hour, builderConfig.X509SVIDTTL)
+	require.Equal(t, 10*time.Minute, builderConfig.JWTSVIDTTL)
+
+	config.LogLevel = logrus.DebugLevel
+	require.NoError(t, s.UpdateConfig(config))
+	require.Equal(t, logrus.DebugLevel, log.GetLevel())
+
+	s.setLive(nil)
+	require.EqualError(t, s.UpdateConfig(config), "server is not running")
+}
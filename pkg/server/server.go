@@ -26,6 +26,7 @@ import (
 	"github.com/spiffe/spire/pkg/common/uptime"
 	"github.com/spiffe/spire/pkg/common/util"
 	"github.com/spiffe/spire/pkg/common/version"
+	"github.com/spiffe/spire/pkg/server/auditlog"
 	"github.com/spiffe/spire/pkg/server/authpolicy"
 	bundle_client "github.com/spiffe/spire/pkg/server/bundle/client"
 	ds_pubmanager "github.com/spiffe/spire/pkg/server/bundle/datastore"
@@ -37,6 +38,7 @@ import (
 	"github.com/spiffe/spire/pkg/server/credtemplate"
 	"github.com/spiffe/spire/pkg/server/credvalidator"
 	"github.com/spiffe/spire/pkg/server/datastore"
+	ds_sql "github.com/spiffe/spire/pkg/server/datastore/sqlstore"
 	"github.com/spiffe/spire/pkg/server/endpoints"
 	"github.com/spiffe/spire/pkg/server/endpoints/bundle"
 	"github.com/spiffe/spire/pkg/server/hostservice/agentstore"
@@ -62,6 +64,11 @@ const (
 
 type Server struct {
 	config Config
+
+	// liveMtx guards live, which holds the components that the reloadable
+	// settings are applied to while the server is running.
+	liveMtx sync.Mutex
+	live    *liveComponents
 }
 
 // Run the server
@@ -87,6 +94,17 @@ func (s *Server) ValidateConfig(ctx context.Context) (map[string][]string, error
 	})
 }
 
+// LoadDataStore loads the DataStore configured for the server without
+// starting the server. The caller is responsible for closing it.
+func (s *Server) LoadDataStore(ctx context.Context) (*ds_sql.Plugin, error) {
+	return catalog.LoadDataStore(ctx, catalog.Config{
+		Log:           s.config.Log.WithField(telemetry.SubsystemName, telemetry.Catalog),
+		Metrics:       telemetry.Blackhole{},
+		TrustDomain:   s.config.TrustDomain,
+		PluginConfigs: s.config.PluginConfigs,
+	})
+}
+
 func (s *Server) run(ctx context.Context) (err error) {
 	// Log configuration values that are useful for debugging
 	s.config.Log.WithFields(logrus.Fields{
@@ -106,6 +124,21 @@ func (s *Server) run(ctx context.Context) (err error) {
 		defer stopProfiling()
 	}
 
+	var auditLogger *logrus.Logger
+	if s.config.AuditLog != nil {
+		auditLogWriter, err := auditlog.Open(*s.config.AuditLog)
+		if err != nil {
+			return fmt.Errorf("unable to open audit log: %w", err)
+		}
+		defer auditLogWriter.Close()
+		auditLogger = auditlog.NewLogger(auditLogWriter)
+
+		s.config.Log.WithFields(logrus.Fields{
+			telemetry.Path:  s.config.AuditLog.Path,
+			telemetry.KeyID: auditLogWriter.KeyID(),
+		}).Info("Audit log opened")
+	}
+
 	var svidRotator *svid.Rotator
 	var bundleCache *bundle.Cache
 	metrics, err := telemetry.NewMetrics(&telemetry.MetricsConfig{
@@ -158,6 +191,13 @@ func (s *Server) run(ctx context.Context) (err error) {
 	telemetry.EmitStarted(metrics, s.config.TrustDomain)
 	uptime.ReportMetrics(ctx, metrics)
 
+	tracing, err := telemetry.NewTracing(ctx, s.config.Telemetry.Tracing,
+		s.config.Log.WithField(telemetry.SubsystemName, telemetry.Telemetry),
+		telemetry.SpireServer, s.config.TrustDomain.Name())
+	if err != nil {
+		return err
+	}
+
 	// Create the identity provider host service. It will not be functional
 	// until the call to SetDeps() below. There is some tricky initialization
 	// stuff going on since the identity provider host service requires plugins
@@ -226,9 +266,29 @@ func (s *Server) run(ctx context.Context) (err error) {
 		return fmt.Errorf("unable to obtain authpolicy engine: %w", err)
 	}
 
-	bundleManager := s.newBundleManager(cat, metrics)
+	reconfigurers := catalog.Reconfigurers{cat}
+	if s.config.ConfigReloader != nil {
+		reconfigurers = append(reconfigurers, s.config.ConfigReloader)
+	}
+	var authPolicyReloader *authpolicy.Reloader
+	if policyConfig := s.config.AuthOpaPolicyEngineConfig; policyConfig != nil && policyConfig.LocalOpaProvider != nil {
+		s.config.Log.WithField(telemetry.Hash, authPolicyEngine.Hash()).Info("Authorization policy loaded")
+		authPolicyReloader, err = authpolicy.NewReloader(authpolicy.ReloaderConfig{
+			Log:      s.config.Log.WithField(telemetry.SubsystemName, telemetry.AuthPolicy),
+			Metrics:  metrics,
+			Engine:   authPolicyEngine,
+			Provider: policyConfig.LocalOpaProvider,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to create authpolicy reloader: %w", err)
+		}
+		reconfigurers = append(reconfigurers, authPolicyReloader)
+	}
+
+	federatesWith := bundle_client.NewTrustDomainConfigSet(s.config.Federation.FederatesWith)
+	bundleManager := s.newBundleManager(cat, metrics, federatesWith)
 
-	endpointsServer, err := s.newEndpointsServer(ctx, cat, svidRotator, serverCA, metrics, caManager, authPolicyEngine, bundleManager)
+	endpointsServer, err := s.newEndpointsServer(ctx, cat, svidRotator, serverCA, metrics, caManager, authPolicyEngine, bundleManager, auditLogger)
 	if err != nil {
 		return err
 	}
@@ -260,16 +320,26 @@ func (s *Server) run(ctx context.Context) (err error) {
 	if err := healthChecker.AddCheck("server", s); err != nil {
 		return fmt.Errorf("failed adding healthcheck: %w", err)
 	}
+	if authPolicyReloader != nil {
+		if err := healthChecker.AddCheck("authpolicy", authPolicyReloader); err != nil {
+			return fmt.Errorf("failed adding healthcheck: %w", err)
+		}
+	}
 
 	tasks := []func(context.Context) error{
 		caSync.Run,
 		svidRotator.Run,
 		endpointsServer.ListenAndServe,
 		metrics.ListenAndServe,
+		tracing.Run,
 		bundleManager.Run,
 		registrationManager.Run,
 		bundlePublishingManager.Run,
-		catalog.ReconfigureTask(s.config.Log.WithField(telemetry.SubsystemName, "reconfigurer"), cat),
+		catalog.ReconfigureTask(s.config.Log.WithField(telemetry.SubsystemName, "reconfigurer"), reconfigurers),
+	}
+
+	if authPolicyReloader != nil {
+		tasks = append(tasks, authPolicyReloader.Run)
 	}
 
 	if s.config.LogReopener != nil {
@@ -281,6 +351,20 @@ func (s *Server) run(ctx context.Context) (err error) {
 		tasks = append(tasks, nodeManager.Run)
 	}
 
+	if s.config.StaleAgentsNotSeenFor != 0 {
+		staleAgentReaper := s.newStaleAgentReaper(cat)
+		tasks = append(tasks, staleAgentReaper.Run)
+	}
+
+	s.setLive(&liveComponents{
+		endpoints:     endpointsServer,
+		federatesWith: federatesWith,
+		bundleManager: bundleManager,
+		credBuilder:   credBuilder,
+		applied:       s.config.Reloadable(),
+	})
+	defer s.setLive(nil)
+
 	ctx, cancel := context.WithCancelCause(ctx)
 	defer cancel(nil)
 	taskRunner := util.NewTaskRunner(ctx, cancel)
@@ -456,6 +540,15 @@ func (s *Server) newNodeManager(cat catalog.Catalog, metrics telemetry.Metrics)
 	return nodeManager
 }
 
+func (s *Server) newStaleAgentReaper(cat catalog.Catalog) *node.StaleAgentReaper {
+	return node.NewStaleAgentReaper(node.StaleAgentReaperConfig{
+		DataStore:  cat.GetDataStore(),
+		Log:        s.config.Log.WithField(telemetry.SubsystemName, telemetry.NodeManager),
+		NotSeenFor: s.config.StaleAgentsNotSeenFor,
+		Action:     s.config.StaleAgentsAction,
+	})
+}
+
 func (s *Server) newSVIDRotator(ctx context.Context, serverCA ca.ServerCA, metrics telemetry.Metrics) (*svid.Rotator, error) {
 	svidRotator := svid.NewRotator(&svid.RotatorConfig{
 		ServerCA: serverCA,
@@ -469,7 +562,7 @@ func (s *Server) newSVIDRotator(ctx context.Context, serverCA ca.ServerCA, metri
 	return svidRotator, nil
 }
 
-func (s *Server) newEndpointsServer(ctx context.Context, catalog catalog.Catalog, svidObserver svid.Observer, serverCA ca.ServerCA, metrics telemetry.Metrics, authorityManager manager.AuthorityManager, authPolicyEngine *authpolicy.Engine, bundleManager *bundle_client.Manager) (endpoints.Server, error) {
+func (s *Server) newEndpointsServer(ctx context.Context, catalog catalog.Catalog, svidObserver svid.Observer, serverCA ca.ServerCA, metrics telemetry.Metrics, authorityManager manager.AuthorityManager, authPolicyEngine *authpolicy.Engine, bundleManager *bundle_client.Manager, auditLogger *logrus.Logger) (*endpoints.Endpoints, error) {
 	config := endpoints.Config{
 		TCPAddr:                      s.config.BindAddress,
 		LocalAddr:                    s.config.BindLocalAddress,
@@ -490,6 +583,7 @@ func (s *Server) newEndpointsServer(ctx context.Context, catalog catalog.Catalog
 		PruneEventsOlderThan:         s.config.PruneEventsOlderThan,
 		EventTimeout:                 s.config.EventTimeout,
 		AuditLogEnabled:              s.config.AuditLogEnabled,
+		AuditLogger:                  auditLogger,
 		ProxyProtocolTrustedCIDRs:    s.config.ProxyProtocolTrustedCIDRs,
 		AuthPolicyEngine:             authPolicyEngine,
 		BundleManager:                bundleManager,
@@ -502,18 +596,19 @@ func (s *Server) newEndpointsServer(ctx context.Context, catalog catalog.Catalog
 		config.BundleEndpoint.RefreshHint = s.config.Federation.BundleEndpoint.RefreshHint
 		config.BundleEndpoint.ACME = s.config.Federation.BundleEndpoint.ACME
 		config.BundleEndpoint.DiskCertManager = s.config.Federation.BundleEndpoint.DiskCertManager
+		config.BundleEndpoint.Hub = s.config.Federation.BundleEndpoint.Hub
 	}
 	return endpoints.New(ctx, config)
 }
 
-func (s *Server) newBundleManager(cat catalog.Catalog, metrics telemetry.Metrics) *bundle_client.Manager {
+func (s *Server) newBundleManager(cat catalog.Catalog, metrics telemetry.Metrics, federatesWith *bundle_client.TrustDomainConfigSet) *bundle_client.Manager {
 	log := s.config.Log.WithField(telemetry.SubsystemName, "bundle_client")
 	return bundle_client.NewManager(bundle_client.ManagerConfig{
 		Log:       log,
 		Metrics:   metrics,
 		DataStore: cat.GetDataStore(),
 		Source: bundle_client.MergeTrustDomainConfigSources(
-			bundle_client.NewTrustDomainConfigSet(s.config.Federation.FederatesWith),
+			federatesWith,
 			bundle_client.DataStoreTrustDomainConfigSource(log, cat.GetDataStore()),
 		),
 	})
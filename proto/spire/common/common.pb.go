@@ -61,9 +61,9 @@ func (*Empty) Descriptor() ([]byte, []int) {
 // * A type which contains attestation data for specific platform.
 type AttestationData struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// * Type of attestation to perform.
+	//* Type of attestation to perform.
 	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
-	// * The attestation data.
+	//* The attestation data.
 	Data          []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -117,10 +117,10 @@ func (x *AttestationData) GetData() []byte {
 // entry is matched.
 type Selector struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// * A selector type represents the type of attestation used in attesting
-	// the entity (Eg: AWS, K8).
+	//* A selector type represents the type of attestation used in attesting
+	//the entity (Eg: AWS, K8).
 	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
-	// * The value to be attested.
+	//* The value to be attested.
 	Value         string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -173,7 +173,7 @@ func (x *Selector) GetValue() string {
 // * Represents a type with a list of Selector.
 type Selectors struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// * A list of Selector.
+	//* A list of Selector.
 	Entries       []*Selector `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -236,7 +236,11 @@ type AttestedNode struct {
 	// CanReattest field (can the attestation safely be deleted and recreated automatically)
 	CanReattest bool `protobuf:"varint,8,opt,name=can_reattest,json=canReattest,proto3" json:"can_reattest,omitempty"`
 	// AgentVersion is the version of the SPIRE agent
-	AgentVersion  string `protobuf:"bytes,9,opt,name=agent_version,json=agentVersion,proto3" json:"agent_version,omitempty"`
+	AgentVersion string `protobuf:"bytes,9,opt,name=agent_version,json=agentVersion,proto3" json:"agent_version,omitempty"`
+	// Last time the agent contacted the server (seconds since unix epoch)
+	LastSeenAt int64 `protobuf:"varint,10,opt,name=last_seen_at,json=lastSeenAt,proto3" json:"last_seen_at,omitempty"`
+	// Status last reported by the agent
+	StatusReport  *AgentStatusReport `protobuf:"bytes,11,opt,name=status_report,json=statusReport,proto3" json:"status_report,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -334,45 +338,214 @@ func (x *AttestedNode) GetAgentVersion() string {
 	return ""
 }
 
+func (x *AttestedNode) GetLastSeenAt() int64 {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return 0
+}
+
+func (x *AttestedNode) GetStatusReport() *AgentStatusReport {
+	if x != nil {
+		return x.StatusReport
+	}
+	return nil
+}
+
+// AgentStatusReport is the status an agent reports to the server
+type AgentStatusReport struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Agent uptime (seconds)
+	Uptime int64 `protobuf:"varint,1,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	// Plugins loaded by the agent
+	Plugins []*AgentPlugin `protobuf:"bytes,2,rep,name=plugins,proto3" json:"plugins,omitempty"`
+	// Number of X509-SVIDs cached by the agent
+	CachedX509Svids int32 `protobuf:"varint,3,opt,name=cached_x509_svids,json=cachedX509Svids,proto3" json:"cached_x509_svids,omitempty"`
+	// Number of JWT-SVIDs cached by the agent
+	CachedJwtSvids int32 `protobuf:"varint,4,opt,name=cached_jwt_svids,json=cachedJwtSvids,proto3" json:"cached_jwt_svids,omitempty"`
+	// Error of the last synchronization with the server, empty if it succeeded
+	LastSyncError string `protobuf:"bytes,5,opt,name=last_sync_error,json=lastSyncError,proto3" json:"last_sync_error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentStatusReport) Reset() {
+	*x = AgentStatusReport{}
+	mi := &file_spire_common_common_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentStatusReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentStatusReport) ProtoMessage() {}
+
+func (x *AgentStatusReport) ProtoReflect() protoreflect.Message {
+	mi := &file_spire_common_common_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentStatusReport.ProtoReflect.Descriptor instead.
+func (*AgentStatusReport) Descriptor() ([]byte, []int) {
+	return file_spire_common_common_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AgentStatusReport) GetUptime() int64 {
+	if x != nil {
+		return x.Uptime
+	}
+	return 0
+}
+
+func (x *AgentStatusReport) GetPlugins() []*AgentPlugin {
+	if x != nil {
+		return x.Plugins
+	}
+	return nil
+}
+
+func (x *AgentStatusReport) GetCachedX509Svids() int32 {
+	if x != nil {
+		return x.CachedX509Svids
+	}
+	return 0
+}
+
+func (x *AgentStatusReport) GetCachedJwtSvids() int32 {
+	if x != nil {
+		return x.CachedJwtSvids
+	}
+	return 0
+}
+
+func (x *AgentStatusReport) GetLastSyncError() string {
+	if x != nil {
+		return x.LastSyncError
+	}
+	return ""
+}
+
+// AgentPlugin describes a plugin loaded by an agent
+type AgentPlugin struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Plugin type (e.g. KeyManager)
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// Plugin name (e.g. disk)
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Plugin version, the agent version for built-in plugins
+	Version string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	// Plugin checksum, for external plugins
+	Checksum      string `protobuf:"bytes,4,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentPlugin) Reset() {
+	*x = AgentPlugin{}
+	mi := &file_spire_common_common_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentPlugin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentPlugin) ProtoMessage() {}
+
+func (x *AgentPlugin) ProtoReflect() protoreflect.Message {
+	mi := &file_spire_common_common_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentPlugin.ProtoReflect.Descriptor instead.
+func (*AgentPlugin) Descriptor() ([]byte, []int) {
+	return file_spire_common_common_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AgentPlugin) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *AgentPlugin) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AgentPlugin) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *AgentPlugin) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
 // * This is a curated record that the Server uses to set up and
 // manage the various registered nodes and workloads that are controlled by it.
 type RegistrationEntry struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// * A list of selectors.
+	//* A list of selectors.
 	Selectors []*Selector `protobuf:"bytes,1,rep,name=selectors,proto3" json:"selectors,omitempty"`
-	// * The SPIFFE ID of an entity that is authorized to attest the validity
-	// of a selector
+	//* The SPIFFE ID of an entity that is authorized to attest the validity
+	//of a selector
 	ParentId string `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
-	// * The SPIFFE ID is a structured string used to identify a resource or
-	// caller. It is defined as a URI comprising a “trust domain” and an
-	// associated path.
+	//* The SPIFFE ID is a structured string used to identify a resource or
+	//caller. It is defined as a URI comprising a “trust domain” and an
+	//associated path.
 	SpiffeId string `protobuf:"bytes,3,opt,name=spiffe_id,json=spiffeId,proto3" json:"spiffe_id,omitempty"`
-	// * Time to live for X509-SVIDs generated from this entry. Was previously called 'ttl'.
+	//* Time to live for X509-SVIDs generated from this entry. Was previously called 'ttl'.
 	X509SvidTtl int32 `protobuf:"varint,4,opt,name=x509_svid_ttl,json=x509SvidTtl,proto3" json:"x509_svid_ttl,omitempty"`
-	// * A list of federated trust domain SPIFFE IDs.
+	//* A list of federated trust domain SPIFFE IDs.
 	FederatesWith []string `protobuf:"bytes,5,rep,name=federates_with,json=federatesWith,proto3" json:"federates_with,omitempty"`
-	// * Entry ID
+	//* Entry ID
 	EntryId string `protobuf:"bytes,6,opt,name=entry_id,json=entryId,proto3" json:"entry_id,omitempty"`
-	// * whether the workload is an admin workload. Admin workloads
-	// can use their SVID's to authenticate with the Server APIs, for
-	// example.
+	//* whether the workload is an admin workload. Admin workloads
+	//can use their SVID's to authenticate with the Server APIs, for
+	//example.
 	Admin bool `protobuf:"varint,7,opt,name=admin,proto3" json:"admin,omitempty"`
-	// * To enable signing CA CSR in upstream spire server
+	//* To enable signing CA CSR in upstream spire server
 	Downstream bool `protobuf:"varint,8,opt,name=downstream,proto3" json:"downstream,omitempty"`
-	// * Expiration of this entry, in seconds from epoch
+	//* Expiration of this entry, in seconds from epoch
 	EntryExpiry int64 `protobuf:"varint,9,opt,name=entryExpiry,proto3" json:"entryExpiry,omitempty"`
-	// * DNS entries
+	//* DNS entries
 	DnsNames []string `protobuf:"bytes,10,rep,name=dns_names,json=dnsNames,proto3" json:"dns_names,omitempty"`
-	// * Revision number is bumped every time the entry is updated
+	//* Revision number is bumped every time the entry is updated
 	RevisionNumber int64 `protobuf:"varint,11,opt,name=revision_number,json=revisionNumber,proto3" json:"revision_number,omitempty"`
-	// * Determines if the issued SVID must be stored through an SVIDStore plugin
+	//* Determines if the issued SVID must be stored through an SVIDStore plugin
 	StoreSvid bool `protobuf:"varint,12,opt,name=store_svid,json=storeSvid,proto3" json:"store_svid,omitempty"`
-	// * Time to live for JWT-SVIDs generated from this entry, if set will override ttl field.
+	//* Time to live for JWT-SVIDs generated from this entry, if set will override ttl field.
 	JwtSvidTtl int32 `protobuf:"varint,13,opt,name=jwt_svid_ttl,json=jwtSvidTtl,proto3" json:"jwt_svid_ttl,omitempty"`
-	// * An operator-specified string used to provide guidance on how this
-	// identity should be used by a workload when more than one SVID is returned.
+	//* An operator-specified string used to provide guidance on how this
+	//identity should be used by a workload when more than one SVID is returned.
 	Hint string `protobuf:"bytes,14,opt,name=hint,proto3" json:"hint,omitempty"`
-	// * Time of creation, in seconds from epoch
+	//* Time of creation, in seconds from epoch
 	CreatedAt            int64                                   `protobuf:"varint,15,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	AdditionalAttributes *RegistrationEntry_AdditionalAttributes `protobuf:"bytes,16,opt,name=additional_attributes,json=additionalAttributes,proto3,oneof" json:"additional_attributes,omitempty"`
 	unknownFields        protoimpl.UnknownFields
@@ -381,7 +554,7 @@ type RegistrationEntry struct {
 
 func (x *RegistrationEntry) Reset() {
 	*x = RegistrationEntry{}
-	mi := &file_spire_common_common_proto_msgTypes[5]
+	mi := &file_spire_common_common_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -393,7 +566,7 @@ func (x *RegistrationEntry) String() string {
 func (*RegistrationEntry) ProtoMessage() {}
 
 func (x *RegistrationEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_spire_common_common_proto_msgTypes[5]
+	mi := &file_spire_common_common_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -406,7 +579,7 @@ func (x *RegistrationEntry) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegistrationEntry.ProtoReflect.Descriptor instead.
 func (*RegistrationEntry) Descriptor() ([]byte, []int) {
-	return file_spire_common_common_proto_rawDescGZIP(), []int{5}
+	return file_spire_common_common_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *RegistrationEntry) GetSelectors() []*Selector {
@@ -544,7 +717,7 @@ type RegistrationEntryMask struct {
 
 func (x *RegistrationEntryMask) Reset() {
 	*x = RegistrationEntryMask{}
-	mi := &file_spire_common_common_proto_msgTypes[6]
+	mi := &file_spire_common_common_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -556,7 +729,7 @@ func (x *RegistrationEntryMask) String() string {
 func (*RegistrationEntryMask) ProtoMessage() {}
 
 func (x *RegistrationEntryMask) ProtoReflect() protoreflect.Message {
-	mi := &file_spire_common_common_proto_msgTypes[6]
+	mi := &file_spire_common_common_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -569,7 +742,7 @@ func (x *RegistrationEntryMask) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegistrationEntryMask.ProtoReflect.Descriptor instead.
 func (*RegistrationEntryMask) Descriptor() ([]byte, []int) {
-	return file_spire_common_common_proto_rawDescGZIP(), []int{6}
+	return file_spire_common_common_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *RegistrationEntryMask) GetSelectors() bool {
@@ -673,7 +846,7 @@ func (x *RegistrationEntryMask) GetAdditionalAttributes() bool {
 // * A list of registration entries.
 type RegistrationEntries struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// * A list of RegistrationEntry.
+	//* A list of RegistrationEntry.
 	Entries       []*RegistrationEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -681,7 +854,7 @@ type RegistrationEntries struct {
 
 func (x *RegistrationEntries) Reset() {
 	*x = RegistrationEntries{}
-	mi := &file_spire_common_common_proto_msgTypes[7]
+	mi := &file_spire_common_common_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -693,7 +866,7 @@ func (x *RegistrationEntries) String() string {
 func (*RegistrationEntries) ProtoMessage() {}
 
 func (x *RegistrationEntries) ProtoReflect() protoreflect.Message {
-	mi := &file_spire_common_common_proto_msgTypes[7]
+	mi := &file_spire_common_common_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -706,7 +879,7 @@ func (x *RegistrationEntries) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegistrationEntries.ProtoReflect.Descriptor instead.
 func (*RegistrationEntries) Descriptor() ([]byte, []int) {
-	return file_spire_common_common_proto_rawDescGZIP(), []int{7}
+	return file_spire_common_common_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *RegistrationEntries) GetEntries() []*RegistrationEntry {
@@ -727,7 +900,7 @@ type Certificate struct {
 
 func (x *Certificate) Reset() {
 	*x = Certificate{}
-	mi := &file_spire_common_common_proto_msgTypes[8]
+	mi := &file_spire_common_common_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -739,7 +912,7 @@ func (x *Certificate) String() string {
 func (*Certificate) ProtoMessage() {}
 
 func (x *Certificate) ProtoReflect() protoreflect.Message {
-	mi := &file_spire_common_common_proto_msgTypes[8]
+	mi := &file_spire_common_common_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -752,7 +925,7 @@ func (x *Certificate) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Certificate.ProtoReflect.Descriptor instead.
 func (*Certificate) Descriptor() ([]byte, []int) {
-	return file_spire_common_common_proto_rawDescGZIP(), []int{8}
+	return file_spire_common_common_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *Certificate) GetDerBytes() []byte {
@@ -772,13 +945,13 @@ func (x *Certificate) GetTaintedKey() bool {
 // * PublicKey represents a PKIX encoded public key
 type PublicKey struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// * PKIX encoded key data
+	//* PKIX encoded key data
 	PkixBytes []byte `protobuf:"bytes,1,opt,name=pkix_bytes,json=pkixBytes,proto3" json:"pkix_bytes,omitempty"`
-	// * key identifier
+	//* key identifier
 	Kid string `protobuf:"bytes,2,opt,name=kid,proto3" json:"kid,omitempty"`
-	// * not after (seconds since unix epoch, 0 means "never expires")
+	//* not after (seconds since unix epoch, 0 means "never expires")
 	NotAfter int64 `protobuf:"varint,3,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"`
-	// * whether the key is tainted
+	//* whether the key is tainted
 	TaintedKey    bool `protobuf:"varint,4,opt,name=tainted_key,json=taintedKey,proto3" json:"tainted_key,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -786,7 +959,7 @@ type PublicKey struct {
 
 func (x *PublicKey) Reset() {
 	*x = PublicKey{}
-	mi := &file_spire_common_common_proto_msgTypes[9]
+	mi := &file_spire_common_common_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -798,7 +971,7 @@ func (x *PublicKey) String() string {
 func (*PublicKey) ProtoMessage() {}
 
 func (x *PublicKey) ProtoReflect() protoreflect.Message {
-	mi := &file_spire_common_common_proto_msgTypes[9]
+	mi := &file_spire_common_common_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -811,7 +984,7 @@ func (x *PublicKey) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PublicKey.ProtoReflect.Descriptor instead.
 func (*PublicKey) Descriptor() ([]byte, []int) {
-	return file_spire_common_common_proto_rawDescGZIP(), []int{9}
+	return file_spire_common_common_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *PublicKey) GetPkixBytes() []byte {
@@ -844,19 +1017,19 @@ func (x *PublicKey) GetTaintedKey() bool {
 
 type Bundle struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// * the SPIFFE ID of the trust domain the bundle belongs to
+	//* the SPIFFE ID of the trust domain the bundle belongs to
 	TrustDomainId string `protobuf:"bytes,1,opt,name=trust_domain_id,json=trustDomainId,proto3" json:"trust_domain_id,omitempty"`
-	// * list of root CA certificates
+	//* list of root CA certificates
 	RootCas []*Certificate `protobuf:"bytes,2,rep,name=root_cas,json=rootCas,proto3" json:"root_cas,omitempty"`
-	// * list of JWT signing keys
+	//* list of JWT signing keys
 	JwtSigningKeys []*PublicKey `protobuf:"bytes,3,rep,name=jwt_signing_keys,json=jwtSigningKeys,proto3" json:"jwt_signing_keys,omitempty"`
-	// * refresh hint is a hint, in seconds, on how often a bundle consumer
+	//* refresh hint is a hint, in seconds, on how often a bundle consumer
 	// should poll for bundle updates
 	RefreshHint int64 `protobuf:"varint,4,opt,name=refresh_hint,json=refreshHint,proto3" json:"refresh_hint,omitempty"`
-	// * sequence number is a monotonically increasing number that is
+	//* sequence number is a monotonically increasing number that is
 	// incremented every time the bundle is updated
 	SequenceNumber uint64 `protobuf:"varint,5,opt,name=sequence_number,json=sequenceNumber,proto3" json:"sequence_number,omitempty"`
-	// * list of WIT signing keys
+	//* list of WIT signing keys
 	WitSigningKeys []*PublicKey `protobuf:"bytes,6,rep,name=wit_signing_keys,json=witSigningKeys,proto3" json:"wit_signing_keys,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
@@ -864,7 +1037,7 @@ type Bundle struct {
 
 func (x *Bundle) Reset() {
 	*x = Bundle{}
-	mi := &file_spire_common_common_proto_msgTypes[10]
+	mi := &file_spire_common_common_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -876,7 +1049,7 @@ func (x *Bundle) String() string {
 func (*Bundle) ProtoMessage() {}
 
 func (x *Bundle) ProtoReflect() protoreflect.Message {
-	mi := &file_spire_common_common_proto_msgTypes[10]
+	mi := &file_spire_common_common_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -889,7 +1062,7 @@ func (x *Bundle) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Bundle.ProtoReflect.Descriptor instead.
 func (*Bundle) Descriptor() ([]byte, []int) {
-	return file_spire_common_common_proto_rawDescGZIP(), []int{10}
+	return file_spire_common_common_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *Bundle) GetTrustDomainId() string {
@@ -934,6 +1107,214 @@ func (x *Bundle) GetWitSigningKeys() []*PublicKey {
 	return nil
 }
 
+// * A federated bundle update that violated the bundle safeguards of the
+// federation relationship and is held back until approved
+type QuarantinedBundle struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	//* the SPIFFE ID of the federated trust domain
+	TrustDomainId string `protobuf:"bytes,1,opt,name=trust_domain_id,json=trustDomainId,proto3" json:"trust_domain_id,omitempty"`
+	//* the bundle served by the bundle endpoint
+	Bundle *Bundle `protobuf:"bytes,2,opt,name=bundle,proto3" json:"bundle,omitempty"`
+	//* the safeguards violated by the bundle
+	Violations []string `protobuf:"bytes,3,rep,name=violations,proto3" json:"violations,omitempty"`
+	//* seconds since unix epoch when the bundle was first quarantined
+	QuarantinedAt int64 `protobuf:"varint,4,opt,name=quarantined_at,json=quarantinedAt,proto3" json:"quarantined_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuarantinedBundle) Reset() {
+	*x = QuarantinedBundle{}
+	mi := &file_spire_common_common_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuarantinedBundle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuarantinedBundle) ProtoMessage() {}
+
+func (x *QuarantinedBundle) ProtoReflect() protoreflect.Message {
+	mi := &file_spire_common_common_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuarantinedBundle.ProtoReflect.Descriptor instead.
+func (*QuarantinedBundle) Descriptor() ([]byte, []int) {
+	return file_spire_common_common_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *QuarantinedBundle) GetTrustDomainId() string {
+	if x != nil {
+		return x.TrustDomainId
+	}
+	return ""
+}
+
+func (x *QuarantinedBundle) GetBundle() *Bundle {
+	if x != nil {
+		return x.Bundle
+	}
+	return nil
+}
+
+func (x *QuarantinedBundle) GetViolations() []string {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+func (x *QuarantinedBundle) GetQuarantinedAt() int64 {
+	if x != nil {
+		return x.QuarantinedAt
+	}
+	return 0
+}
+
+// * Checks a bundle fetched from the bundle endpoint of a federated trust
+// domain must pass before it is stored
+type BundleSafeguards struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	//* requires the fetched bundle to share a key with the stored bundle
+	RequireOverlap bool `protobuf:"varint,1,opt,name=require_overlap,json=requireOverlap,proto3" json:"require_overlap,omitempty"`
+	//* the maximum number of authorities in the fetched bundle, or zero for
+	// no limit
+	MaxKeys int32 `protobuf:"varint,2,opt,name=max_keys,json=maxKeys,proto3" json:"max_keys,omitempty"`
+	//* seconds at least one X.509 authority of the fetched bundle must
+	// remain valid for, or zero for no requirement
+	MinRemainingValidity int64 `protobuf:"varint,3,opt,name=min_remaining_validity,json=minRemainingValidity,proto3" json:"min_remaining_validity,omitempty"`
+	//* hex encoded SHA-256 fingerprints of the SubjectPublicKeyInfo of keys
+	// that must remain in the fetched bundle
+	PinnedKeys    []string `protobuf:"bytes,4,rep,name=pinned_keys,json=pinnedKeys,proto3" json:"pinned_keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BundleSafeguards) Reset() {
+	*x = BundleSafeguards{}
+	mi := &file_spire_common_common_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BundleSafeguards) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BundleSafeguards) ProtoMessage() {}
+
+func (x *BundleSafeguards) ProtoReflect() protoreflect.Message {
+	mi := &file_spire_common_common_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BundleSafeguards.ProtoReflect.Descriptor instead.
+func (*BundleSafeguards) Descriptor() ([]byte, []int) {
+	return file_spire_common_common_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BundleSafeguards) GetRequireOverlap() bool {
+	if x != nil {
+		return x.RequireOverlap
+	}
+	return false
+}
+
+func (x *BundleSafeguards) GetMaxKeys() int32 {
+	if x != nil {
+		return x.MaxKeys
+	}
+	return 0
+}
+
+func (x *BundleSafeguards) GetMinRemainingValidity() int64 {
+	if x != nil {
+		return x.MinRemainingValidity
+	}
+	return 0
+}
+
+func (x *BundleSafeguards) GetPinnedKeys() []string {
+	if x != nil {
+		return x.PinnedKeys
+	}
+	return nil
+}
+
+// * Settings of a federation relationship that the SPIRE API federation
+// relationship type has no fields for
+type FederationRelationshipOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	//* the bundle safeguards of the relationship
+	BundleSafeguards *BundleSafeguards `protobuf:"bytes,1,opt,name=bundle_safeguards,json=bundleSafeguards,proto3" json:"bundle_safeguards,omitempty"`
+	//* whether the bundle endpoint URL is the /federated path of a
+	// federation hub serving the bundles of many trust domains
+	BundleEndpointHub bool `protobuf:"varint,2,opt,name=bundle_endpoint_hub,json=bundleEndpointHub,proto3" json:"bundle_endpoint_hub,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *FederationRelationshipOptions) Reset() {
+	*x = FederationRelationshipOptions{}
+	mi := &file_spire_common_common_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FederationRelationshipOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FederationRelationshipOptions) ProtoMessage() {}
+
+func (x *FederationRelationshipOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_spire_common_common_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FederationRelationshipOptions.ProtoReflect.Descriptor instead.
+func (*FederationRelationshipOptions) Descriptor() ([]byte, []int) {
+	return file_spire_common_common_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *FederationRelationshipOptions) GetBundleSafeguards() *BundleSafeguards {
+	if x != nil {
+		return x.BundleSafeguards
+	}
+	return nil
+}
+
+func (x *FederationRelationshipOptions) GetBundleEndpointHub() bool {
+	if x != nil {
+		return x.BundleEndpointHub
+	}
+	return false
+}
+
 type BundleMask struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	RootCas         bool                   `protobuf:"varint,1,opt,name=root_cas,json=rootCas,proto3" json:"root_cas,omitempty"`
@@ -948,7 +1329,7 @@ type BundleMask struct {
 
 func (x *BundleMask) Reset() {
 	*x = BundleMask{}
-	mi := &file_spire_common_common_proto_msgTypes[11]
+	mi := &file_spire_common_common_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -960,7 +1341,7 @@ func (x *BundleMask) String() string {
 func (*BundleMask) ProtoMessage() {}
 
 func (x *BundleMask) ProtoReflect() protoreflect.Message {
-	mi := &file_spire_common_common_proto_msgTypes[11]
+	mi := &file_spire_common_common_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -973,7 +1354,7 @@ func (x *BundleMask) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BundleMask.ProtoReflect.Descriptor instead.
 func (*BundleMask) Descriptor() ([]byte, []int) {
-	return file_spire_common_common_proto_rawDescGZIP(), []int{11}
+	return file_spire_common_common_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *BundleMask) GetRootCas() bool {
@@ -1027,13 +1408,15 @@ type AttestedNodeMask struct {
 	NewCertNotAfter     bool                   `protobuf:"varint,5,opt,name=new_cert_not_after,json=newCertNotAfter,proto3" json:"new_cert_not_after,omitempty"`
 	CanReattest         bool                   `protobuf:"varint,6,opt,name=can_reattest,json=canReattest,proto3" json:"can_reattest,omitempty"`
 	AgentVersion        bool                   `protobuf:"varint,7,opt,name=agent_version,json=agentVersion,proto3" json:"agent_version,omitempty"`
+	LastSeenAt          bool                   `protobuf:"varint,8,opt,name=last_seen_at,json=lastSeenAt,proto3" json:"last_seen_at,omitempty"`
+	StatusReport        bool                   `protobuf:"varint,9,opt,name=status_report,json=statusReport,proto3" json:"status_report,omitempty"`
 	unknownFields       protoimpl.UnknownFields
 	sizeCache           protoimpl.SizeCache
 }
 
 func (x *AttestedNodeMask) Reset() {
 	*x = AttestedNodeMask{}
-	mi := &file_spire_common_common_proto_msgTypes[12]
+	mi := &file_spire_common_common_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1045,7 +1428,7 @@ func (x *AttestedNodeMask) String() string {
 func (*AttestedNodeMask) ProtoMessage() {}
 
 func (x *AttestedNodeMask) ProtoReflect() protoreflect.Message {
-	mi := &file_spire_common_common_proto_msgTypes[12]
+	mi := &file_spire_common_common_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1058,7 +1441,7 @@ func (x *AttestedNodeMask) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AttestedNodeMask.ProtoReflect.Descriptor instead.
 func (*AttestedNodeMask) Descriptor() ([]byte, []int) {
-	return file_spire_common_common_proto_rawDescGZIP(), []int{12}
+	return file_spire_common_common_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *AttestedNodeMask) GetAttestationDataType() bool {
@@ -1110,6 +1493,20 @@ func (x *AttestedNodeMask) GetAgentVersion() bool {
 	return false
 }
 
+func (x *AttestedNodeMask) GetLastSeenAt() bool {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return false
+}
+
+func (x *AttestedNodeMask) GetStatusReport() bool {
+	if x != nil {
+		return x.StatusReport
+	}
+	return false
+}
+
 // * This nested message is reserved to contain a number of optional fields
 // controlling the various aspects of the agent's behaviour with respect to a
 // given registration entry. It serves to enable introducing and testing out new
@@ -1118,16 +1515,16 @@ func (x *AttestedNodeMask) GetAgentVersion() bool {
 // attributes in the datastore.
 type RegistrationEntry_AdditionalAttributes struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// * Flag indicating whether the agent should prefetch and cache X509 SVID.
-	// Can be set to `true` if the workload is unlikely to request an X509 SVID.
-	// This is meant to prevent unnecessary effort spent on generating SVIDs of types,
-	// which are unlikely to be needed.
+	//* Flag indicating whether the agent should prefetch and cache X509 SVID.
+	//Can be set to `true` if the workload is unlikely to request an X509 SVID.
+	//This is meant to prevent unnecessary effort spent on generating SVIDs of types,
+	//which are unlikely to be needed.
 	DisableX509SvidPrefetch bool `protobuf:"varint,1,opt,name=disable_x509_svid_prefetch,json=disableX509SvidPrefetch,proto3" json:"disable_x509_svid_prefetch,omitempty"`
-	// * Flag indicating whether JWT-SVIDs issued for this entry should include
-	// a "jti" (JWT ID) claim. When true, the agent bypasses the JWT-SVID cache so
-	// each request yields a fresh token with a unique JTI — useful for audit trails
-	// and replay protection. When false (default), behavior is backwards compatible:
-	// no JTI claim, caching enabled.
+	//* Flag indicating whether JWT-SVIDs issued for this entry should include
+	//a "jti" (JWT ID) claim. When true, the agent bypasses the JWT-SVID cache so
+	//each request yields a fresh token with a unique JTI — useful for audit trails
+	//and replay protection. When false (default), behavior is backwards compatible:
+	//no JTI claim, caching enabled.
 	JwtSvidIncludeJti bool `protobuf:"varint,2,opt,name=jwt_svid_include_jti,json=jwtSvidIncludeJti,proto3" json:"jwt_svid_include_jti,omitempty"`
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
@@ -1135,7 +1532,7 @@ type RegistrationEntry_AdditionalAttributes struct {
 
 func (x *RegistrationEntry_AdditionalAttributes) Reset() {
 	*x = RegistrationEntry_AdditionalAttributes{}
-	mi := &file_spire_common_common_proto_msgTypes[13]
+	mi := &file_spire_common_common_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1147,7 +1544,7 @@ func (x *RegistrationEntry_AdditionalAttributes) String() string {
 func (*RegistrationEntry_AdditionalAttributes) ProtoMessage() {}
 
 func (x *RegistrationEntry_AdditionalAttributes) ProtoReflect() protoreflect.Message {
-	mi := &file_spire_common_common_proto_msgTypes[13]
+	mi := &file_spire_common_common_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1160,7 +1557,7 @@ func (x *RegistrationEntry_AdditionalAttributes) ProtoReflect() protoreflect.Mes
 
 // Deprecated: Use RegistrationEntry_AdditionalAttributes.ProtoReflect.Descriptor instead.
 func (*RegistrationEntry_AdditionalAttributes) Descriptor() ([]byte, []int) {
-	return file_spire_common_common_proto_rawDescGZIP(), []int{5, 0}
+	return file_spire_common_common_proto_rawDescGZIP(), []int{7, 0}
 }
 
 func (x *RegistrationEntry_AdditionalAttributes) GetDisableX509SvidPrefetch() bool {
@@ -1190,7 +1587,7 @@ const file_spire_common_common_proto_rawDesc = "" +
 	"\x04type\x18\x01 \x01(\tR\x04type\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value\"=\n" +
 	"\tSelectors\x120\n" +
-	"\aentries\x18\x01 \x03(\v2\x16.spire.common.SelectorR\aentries\"\x93\x03\n" +
+	"\aentries\x18\x01 \x03(\v2\x16.spire.common.SelectorR\aentries\"\xfb\x03\n" +
 	"\fAttestedNode\x12\x1b\n" +
 	"\tspiffe_id\x18\x01 \x01(\tR\bspiffeId\x122\n" +
 	"\x15attestation_data_type\x18\x02 \x01(\tR\x13attestationDataType\x12,\n" +
@@ -1200,7 +1597,22 @@ const file_spire_common_common_proto_rawDesc = "" +
 	"\x12new_cert_not_after\x18\x06 \x01(\x03R\x0fnewCertNotAfter\x124\n" +
 	"\tselectors\x18\a \x03(\v2\x16.spire.common.SelectorR\tselectors\x12!\n" +
 	"\fcan_reattest\x18\b \x01(\bR\vcanReattest\x12#\n" +
-	"\ragent_version\x18\t \x01(\tR\fagentVersion\"\x8c\x06\n" +
+	"\ragent_version\x18\t \x01(\tR\fagentVersion\x12 \n" +
+	"\flast_seen_at\x18\n" +
+	" \x01(\x03R\n" +
+	"lastSeenAt\x12D\n" +
+	"\rstatus_report\x18\v \x01(\v2\x1f.spire.common.AgentStatusReportR\fstatusReport\"\xde\x01\n" +
+	"\x11AgentStatusReport\x12\x16\n" +
+	"\x06uptime\x18\x01 \x01(\x03R\x06uptime\x123\n" +
+	"\aplugins\x18\x02 \x03(\v2\x19.spire.common.AgentPluginR\aplugins\x12*\n" +
+	"\x11cached_x509_svids\x18\x03 \x01(\x05R\x0fcachedX509Svids\x12(\n" +
+	"\x10cached_jwt_svids\x18\x04 \x01(\x05R\x0ecachedJwtSvids\x12&\n" +
+	"\x0flast_sync_error\x18\x05 \x01(\tR\rlastSyncError\"k\n" +
+	"\vAgentPlugin\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12\x1a\n" +
+	"\bchecksum\x18\x04 \x01(\tR\bchecksum\"\x8c\x06\n" +
 	"\x11RegistrationEntry\x124\n" +
 	"\tselectors\x18\x01 \x03(\v2\x16.spire.common.SelectorR\tselectors\x12\x1b\n" +
 	"\tparent_id\x18\x02 \x01(\tR\bparentId\x12\x1b\n" +
@@ -1267,7 +1679,23 @@ const file_spire_common_common_proto_rawDesc = "" +
 	"\x10jwt_signing_keys\x18\x03 \x03(\v2\x17.spire.common.PublicKeyR\x0ejwtSigningKeys\x12!\n" +
 	"\frefresh_hint\x18\x04 \x01(\x03R\vrefreshHint\x12'\n" +
 	"\x0fsequence_number\x18\x05 \x01(\x04R\x0esequenceNumber\x12A\n" +
-	"\x10wit_signing_keys\x18\x06 \x03(\v2\x17.spire.common.PublicKeyR\x0ewitSigningKeys\"\xf3\x01\n" +
+	"\x10wit_signing_keys\x18\x06 \x03(\v2\x17.spire.common.PublicKeyR\x0ewitSigningKeys\"\xb0\x01\n" +
+	"\x11QuarantinedBundle\x12&\n" +
+	"\x0ftrust_domain_id\x18\x01 \x01(\tR\rtrustDomainId\x12,\n" +
+	"\x06bundle\x18\x02 \x01(\v2\x14.spire.common.BundleR\x06bundle\x12\x1e\n" +
+	"\n" +
+	"violations\x18\x03 \x03(\tR\n" +
+	"violations\x12%\n" +
+	"\x0equarantined_at\x18\x04 \x01(\x03R\rquarantinedAt\"\xad\x01\n" +
+	"\x10BundleSafeguards\x12'\n" +
+	"\x0frequire_overlap\x18\x01 \x01(\bR\x0erequireOverlap\x12\x19\n" +
+	"\bmax_keys\x18\x02 \x01(\x05R\amaxKeys\x124\n" +
+	"\x16min_remaining_validity\x18\x03 \x01(\x03R\x14minRemainingValidity\x12\x1f\n" +
+	"\vpinned_keys\x18\x04 \x03(\tR\n" +
+	"pinnedKeys\"\x9c\x01\n" +
+	"\x1dFederationRelationshipOptions\x12K\n" +
+	"\x11bundle_safeguards\x18\x01 \x01(\v2\x1e.spire.common.BundleSafeguardsR\x10bundleSafeguards\x12.\n" +
+	"\x13bundle_endpoint_hub\x18\x02 \x01(\bR\x11bundleEndpointHub\"\xf3\x01\n" +
 	"\n" +
 	"BundleMask\x12\x19\n" +
 	"\broot_cas\x18\x01 \x01(\bR\arootCas\x12(\n" +
@@ -1275,7 +1703,7 @@ const file_spire_common_common_proto_rawDesc = "" +
 	"\frefresh_hint\x18\x03 \x01(\bR\vrefreshHint\x12'\n" +
 	"\x0fsequence_number\x18\x04 \x01(\bR\x0esequenceNumber\x12*\n" +
 	"\x11x509_tainted_keys\x18\x05 \x01(\bR\x0fx509TaintedKeys\x12(\n" +
-	"\x10wit_signing_keys\x18\x06 \x01(\bR\x0ewitSigningKeys\"\xc4\x02\n" +
+	"\x10wit_signing_keys\x18\x06 \x01(\bR\x0ewitSigningKeys\"\x8b\x03\n" +
 	"\x10AttestedNodeMask\x122\n" +
 	"\x15attestation_data_type\x18\x01 \x01(\bR\x13attestationDataType\x12,\n" +
 	"\x12cert_serial_number\x18\x02 \x01(\bR\x10certSerialNumber\x12$\n" +
@@ -1283,7 +1711,10 @@ const file_spire_common_common_proto_rawDesc = "" +
 	"\x16new_cert_serial_number\x18\x04 \x01(\bR\x13newCertSerialNumber\x12+\n" +
 	"\x12new_cert_not_after\x18\x05 \x01(\bR\x0fnewCertNotAfter\x12!\n" +
 	"\fcan_reattest\x18\x06 \x01(\bR\vcanReattest\x12#\n" +
-	"\ragent_version\x18\a \x01(\bR\fagentVersionB,Z*github.com/spiffe/spire/proto/spire/commonb\x06proto3"
+	"\ragent_version\x18\a \x01(\bR\fagentVersion\x12 \n" +
+	"\flast_seen_at\x18\b \x01(\bR\n" +
+	"lastSeenAt\x12#\n" +
+	"\rstatus_report\x18\t \x01(\bR\fstatusReportB,Z*github.com/spiffe/spire/proto/spire/commonb\x06proto3"
 
 var (
 	file_spire_common_common_proto_rawDescOnce sync.Once
@@ -1297,37 +1728,46 @@ func file_spire_common_common_proto_rawDescGZIP() []byte {
 	return file_spire_common_common_proto_rawDescData
 }
 
-var file_spire_common_common_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_spire_common_common_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
 var file_spire_common_common_proto_goTypes = []any{
 	(*Empty)(nil),                                  // 0: spire.common.Empty
 	(*AttestationData)(nil),                        // 1: spire.common.AttestationData
 	(*Selector)(nil),                               // 2: spire.common.Selector
 	(*Selectors)(nil),                              // 3: spire.common.Selectors
 	(*AttestedNode)(nil),                           // 4: spire.common.AttestedNode
-	(*RegistrationEntry)(nil),                      // 5: spire.common.RegistrationEntry
-	(*RegistrationEntryMask)(nil),                  // 6: spire.common.RegistrationEntryMask
-	(*RegistrationEntries)(nil),                    // 7: spire.common.RegistrationEntries
-	(*Certificate)(nil),                            // 8: spire.common.Certificate
-	(*PublicKey)(nil),                              // 9: spire.common.PublicKey
-	(*Bundle)(nil),                                 // 10: spire.common.Bundle
-	(*BundleMask)(nil),                             // 11: spire.common.BundleMask
-	(*AttestedNodeMask)(nil),                       // 12: spire.common.AttestedNodeMask
-	(*RegistrationEntry_AdditionalAttributes)(nil), // 13: spire.common.RegistrationEntry.AdditionalAttributes
+	(*AgentStatusReport)(nil),                      // 5: spire.common.AgentStatusReport
+	(*AgentPlugin)(nil),                            // 6: spire.common.AgentPlugin
+	(*RegistrationEntry)(nil),                      // 7: spire.common.RegistrationEntry
+	(*RegistrationEntryMask)(nil),                  // 8: spire.common.RegistrationEntryMask
+	(*RegistrationEntries)(nil),                    // 9: spire.common.RegistrationEntries
+	(*Certificate)(nil),                            // 10: spire.common.Certificate
+	(*PublicKey)(nil),                              // 11: spire.common.PublicKey
+	(*Bundle)(nil),                                 // 12: spire.common.Bundle
+	(*QuarantinedBundle)(nil),                      // 13: spire.common.QuarantinedBundle
+	(*BundleSafeguards)(nil),                       // 14: spire.common.BundleSafeguards
+	(*FederationRelationshipOptions)(nil),          // 15: spire.common.FederationRelationshipOptions
+	(*BundleMask)(nil),                             // 16: spire.common.BundleMask
+	(*AttestedNodeMask)(nil),                       // 17: spire.common.AttestedNodeMask
+	(*RegistrationEntry_AdditionalAttributes)(nil), // 18: spire.common.RegistrationEntry.AdditionalAttributes
 }
 var file_spire_common_common_proto_depIdxs = []int32{
 	2,  // 0: spire.common.Selectors.entries:type_name -> spire.common.Selector
 	2,  // 1: spire.common.AttestedNode.selectors:type_name -> spire.common.Selector
-	2,  // 2: spire.common.RegistrationEntry.selectors:type_name -> spire.common.Selector
-	13, // 3: spire.common.RegistrationEntry.additional_attributes:type_name -> spire.common.RegistrationEntry.AdditionalAttributes
-	5,  // 4: spire.common.RegistrationEntries.entries:type_name -> spire.common.RegistrationEntry
-	8,  // 5: spire.common.Bundle.root_cas:type_name -> spire.common.Certificate
-	9,  // 6: spire.common.Bundle.jwt_signing_keys:type_name -> spire.common.PublicKey
-	9,  // 7: spire.common.Bundle.wit_signing_keys:type_name -> spire.common.PublicKey
-	8,  // [8:8] is the sub-list for method output_type
-	8,  // [8:8] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	5,  // 2: spire.common.AttestedNode.status_report:type_name -> spire.common.AgentStatusReport
+	6,  // 3: spire.common.AgentStatusReport.plugins:type_name -> spire.common.AgentPlugin
+	2,  // 4: spire.common.RegistrationEntry.selectors:type_name -> spire.common.Selector
+	18, // 5: spire.common.RegistrationEntry.additional_attributes:type_name -> spire.common.RegistrationEntry.AdditionalAttributes
+	7,  // 6: spire.common.RegistrationEntries.entries:type_name -> spire.common.RegistrationEntry
+	10, // 7: spire.common.Bundle.root_cas:type_name -> spire.common.Certificate
+	11, // 8: spire.common.Bundle.jwt_signing_keys:type_name -> spire.common.PublicKey
+	11, // 9: spire.common.Bundle.wit_signing_keys:type_name -> spire.common.PublicKey
+	12, // 10: spire.common.QuarantinedBundle.bundle:type_name -> spire.common.Bundle
+	14, // 11: spire.common.FederationRelationshipOptions.bundle_safeguards:type_name -> spire.common.BundleSafeguards
+	12, // [12:12] is the sub-list for method output_type
+	12, // [12:12] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_spire_common_common_proto_init() }
@@ -1335,14 +1775,14 @@ func file_spire_common_common_proto_init() {
 	if File_spire_common_common_proto != nil {
 		return
 	}
-	file_spire_common_common_proto_msgTypes[5].OneofWrappers = []any{}
+	file_spire_common_common_proto_msgTypes[7].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_spire_common_common_proto_rawDesc), len(file_spire_common_common_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   14,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -246,6 +246,20 @@ func (s *DataStore) RevokeJWTKey(ctx context.Context, trustDomainID string, auth
 	return s.ds.RevokeJWTKey(ctx, trustDomainID, authorityID)
 }
 
+func (s *DataStore) TaintWITKey(ctx context.Context, trustDomainID string, authorityID string) (*common.PublicKey, error) {
+	if err := s.getNextError(); err != nil {
+		return nil, err
+	}
+	return s.ds.TaintWITKey(ctx, trustDomainID, authorityID)
+}
+
+func (s *DataStore) RevokeWITKey(ctx context.Context, trustDomainID string, authorityID string) (*common.PublicKey, error) {
+	if err := s.getNextError(); err != nil {
+		return nil, err
+	}
+	return s.ds.RevokeWITKey(ctx, trustDomainID, authorityID)
+}
+
 func (s *DataStore) SetNodeSelectors(ctx context.Context, spiffeID string, selectors []*common.Selector) error {
 	if err := s.getNextError(); err != nil {
 		return err
@@ -375,6 +389,13 @@ func (s *DataStore) FetchRegistrationEntryEvent(ctx context.Context, eventID uin
 	return s.ds.FetchRegistrationEntryEvent(ctx, eventID)
 }
 
+func (s *DataStore) ConsumeJoinToken(ctx context.Context, token string, now time.Time, checkUse func(*datastore.JoinToken) error) (*datastore.JoinToken, error) {
+	if err := s.getNextError(); err != nil {
+		return nil, err
+	}
+	return s.ds.ConsumeJoinToken(ctx, token, now, checkUse)
+}
+
 func (s *DataStore) CreateJoinToken(ctx context.Context, token *datastore.JoinToken) error {
 	if err := s.getNextError(); err != nil {
 		return err
@@ -389,6 +410,13 @@ func (s *DataStore) FetchJoinToken(ctx context.Context, token string) (*datastor
 	return s.ds.FetchJoinToken(ctx, token)
 }
 
+func (s *DataStore) ListJoinTokens(ctx context.Context, req *datastore.ListJoinTokensRequest) (*datastore.ListJoinTokensResponse, error) {
+	if err := s.getNextError(); err != nil {
+		return nil, err
+	}
+	return s.ds.ListJoinTokens(ctx, req)
+}
+
 func (s *DataStore) DeleteJoinToken(ctx context.Context, token string) error {
 	if err := s.getNextError(); err != nil {
 		return err
@@ -438,6 +466,41 @@ func (s *DataStore) UpdateFederationRelationship(ctx context.Context, fr *datast
 	return s.ds.UpdateFederationRelationship(ctx, fr, mask)
 }
 
+func (s *DataStore) SetFederationRelationshipOptions(ctx context.Context, trustDomain spiffeid.TrustDomain, options *common.FederationRelationshipOptions) (*datastore.FederationRelationship, error) {
+	if err := s.getNextError(); err != nil {
+		return nil, err
+	}
+	return s.ds.SetFederationRelationshipOptions(ctx, trustDomain, options)
+}
+
+func (s *DataStore) SetQuarantinedBundle(ctx context.Context, qb *common.QuarantinedBundle) (*common.QuarantinedBundle, error) {
+	if err := s.getNextError(); err != nil {
+		return nil, err
+	}
+	return s.ds.SetQuarantinedBundle(ctx, qb)
+}
+
+func (s *DataStore) FetchQuarantinedBundle(ctx context.Context, trustDomainID string) (*common.QuarantinedBundle, error) {
+	if err := s.getNextError(); err != nil {
+		return nil, err
+	}
+	return s.ds.FetchQuarantinedBundle(ctx, trustDomainID)
+}
+
+func (s *DataStore) DeleteQuarantinedBundle(ctx context.Context, trustDomainID string) error {
+	if err := s.getNextError(); err != nil {
+		return err
+	}
+	return s.ds.DeleteQuarantinedBundle(ctx, trustDomainID)
+}
+
+func (s *DataStore) ReleaseQuarantinedBundle(ctx context.Context, bundle *common.Bundle) (*common.Bundle, error) {
+	if err := s.getNextError(); err != nil {
+		return nil, err
+	}
+	return s.ds.ReleaseQuarantinedBundle(ctx, bundle)
+}
+
 func (s *DataStore) FetchCAJournal(ctx context.Context, activeX509AuthorityID string) (*datastore.CAJournal, error) {
 	if err := s.getNextError(); err != nil {
 		return nil, err
@@ -445,6 +508,13 @@ func (s *DataStore) FetchCAJournal(ctx context.Context, activeX509AuthorityID st
 	return s.ds.FetchCAJournal(ctx, activeX509AuthorityID)
 }
 
+func (s *DataStore) ListCAJournals(ctx context.Context, req *datastore.ListCAJournalsRequest) (*datastore.ListCAJournalsResponse, error) {
+	if err := s.getNextError(); err != nil {
+		return nil, err
+	}
+	return s.ds.ListCAJournals(ctx, req)
+}
+
 func (s *DataStore) ListCAJournalsForTesting(ctx context.Context) ([]*datastore.CAJournal, error) {
 	if err := s.getNextError(); err != nil {
 		return nil, err
@@ -13,6 +13,7 @@ import (
 	"github.com/spiffe/spire/pkg/server/ca"
 	"github.com/spiffe/spire/pkg/server/credtemplate"
 	"github.com/spiffe/spire/pkg/server/credvalidator"
+	"github.com/spiffe/spire/pkg/server/plugin/credentialcomposer"
 	"github.com/spiffe/spire/test/clock"
 	"github.com/spiffe/spire/test/fakes/fakehealthchecker"
 	"github.com/spiffe/spire/test/testkey"
@@ -31,6 +32,8 @@ type Options struct {
 	WITSVIDTTL      time.Duration
 	DisableJWTSVIDs bool
 	DisableWITSVIDs bool
+
+	CredentialComposers []credentialcomposer.CredentialComposer
 }
 
 type CA struct {
@@ -76,6 +79,8 @@ func New(t *testing.T, trustDomain spiffeid.TrustDomain, options *Options) *CA {
 		X509SVIDTTL:  options.X509SVIDTTL,
 		JWTSVIDTTL:   options.JWTSVIDTTL,
 		WITSVIDTTL:   options.WITSVIDTTL,
+
+		CredentialComposers: options.CredentialComposers,
 	})
 	require.NoError(t, err)
 
@@ -6,6 +6,7 @@ import (
 	upstreamauthorityv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/server/upstreamauthority/v1"
 	"github.com/spiffe/spire/pkg/common/catalog"
 	"github.com/spiffe/spire/pkg/server/plugin/upstreamauthority"
+	"github.com/spiffe/spire/proto/private/server/upstreamwitauthority"
 	"github.com/spiffe/spire/test/plugintest"
 )
 
@@ -18,3 +19,17 @@ func Load(t *testing.T, config Config) (upstreamauthority.UpstreamAuthority, *Up
 	plugintest.Load(t, catalog.MakeBuiltIn("fake", server), v1)
 	return v1, fake
 }
+
+// LoadWithWITAuthority loads the fake as an UpstreamAuthority plugin that
+// also serves the WIT authorities of its bundle.
+func LoadWithWITAuthority(t *testing.T, config Config) (upstreamauthority.UpstreamAuthority, upstreamauthority.UpstreamWITAuthority, *UpstreamAuthority) {
+	fake := New(t, config)
+
+	server := upstreamauthorityv1.UpstreamAuthorityPluginServer(fake)
+	witServer := upstreamwitauthority.UpstreamWITAuthorityServiceServer(fake)
+
+	v1 := new(upstreamauthority.V1)
+	witV1 := new(upstreamauthority.WITV1)
+	plugintest.Load(t, catalog.MakeBuiltIn("fake", server, witServer), v1, plugintest.Services(witV1))
+	return v1, witV1, fake
+}
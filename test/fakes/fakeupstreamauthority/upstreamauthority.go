@@ -18,12 +18,14 @@ import (
 	"github.com/spiffe/spire/pkg/common/coretypes/x509certificate"
 	"github.com/spiffe/spire/pkg/common/x509svid"
 	"github.com/spiffe/spire/pkg/common/x509util"
+	"github.com/spiffe/spire/proto/private/server/upstreamwitauthority"
 	"github.com/spiffe/spire/proto/spire/common"
 	"github.com/spiffe/spire/test/clock"
 	"github.com/spiffe/spire/test/testkey"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 var (
@@ -44,6 +46,7 @@ type Config struct {
 
 type UpstreamAuthority struct {
 	upstreamauthorityv1.UnimplementedUpstreamAuthorityServer
+	upstreamwitauthority.UnimplementedUpstreamWITAuthorityServer
 
 	t      *testing.T
 	config Config
@@ -58,9 +61,13 @@ type UpstreamAuthority struct {
 	jwtKeysMtx sync.RWMutex
 	jwtKeys    []*common.PublicKey
 
+	witKeysMtx sync.RWMutex
+	witKeys    []*common.PublicKey
+
 	streamsMtx           sync.Mutex
 	mintX509CAStreams    map[chan struct{}]struct{}
 	publishJWTKeyStreams map[chan struct{}]struct{}
+	witAuthorityStreams  map[chan struct{}]struct{}
 }
 
 func New(t *testing.T, config Config) *UpstreamAuthority {
@@ -72,6 +79,7 @@ func New(t *testing.T, config Config) *UpstreamAuthority {
 		config:               config,
 		mintX509CAStreams:    make(map[chan struct{}]struct{}),
 		publishJWTKeyStreams: make(map[chan struct{}]struct{}),
+		witAuthorityStreams:  make(map[chan struct{}]struct{}),
 	}
 	ua.RotateX509CA()
 	return ua
@@ -179,6 +187,40 @@ func (ua *UpstreamAuthority) SubscribeToLocalBundle(req *upstreamauthorityv1.Sub
 	}
 }
 
+func (ua *UpstreamAuthority) SubscribeToLocalWITAuthorities(_ *upstreamwitauthority.SubscribeToLocalWITAuthoritiesRequest, stream upstreamwitauthority.UpstreamWITAuthority_SubscribeToLocalWITAuthoritiesServer) error {
+	streamCh := make(chan struct{}, 1)
+	ua.streamsMtx.Lock()
+	ua.witAuthorityStreams[streamCh] = struct{}{}
+	ua.streamsMtx.Unlock()
+	defer func() {
+		ua.streamsMtx.Lock()
+		delete(ua.witAuthorityStreams, streamCh)
+		ua.streamsMtx.Unlock()
+	}()
+
+	// Send a first update on the stream, as required.
+	if err := stream.Send(&upstreamwitauthority.SubscribeToLocalWITAuthoritiesResponse{
+		UpstreamWitKeys: ua.WITKeys(),
+	}); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-streamCh:
+		}
+
+		if err := stream.Send(&upstreamwitauthority.SubscribeToLocalWITAuthoritiesResponse{
+			UpstreamWitKeys: ua.WITKeys(),
+		}); err != nil {
+			return err
+		}
+	}
+}
+
 func (ua *UpstreamAuthority) RotateX509CA() {
 	ua.x509CAMtx.Lock()
 	defer ua.x509CAMtx.Unlock()
@@ -251,6 +293,49 @@ func (ua *UpstreamAuthority) AppendJWTKey(jwtKey *common.PublicKey) {
 	ua.TriggerJWTKeysChanged()
 }
 
+func (ua *UpstreamAuthority) WITKeys() []*common.PublicKey {
+	ua.witKeysMtx.RLock()
+	defer ua.witKeysMtx.RUnlock()
+	witKeys := make([]*common.PublicKey, 0, len(ua.witKeys))
+	for _, witKey := range ua.witKeys {
+		witKeys = append(witKeys, proto.Clone(witKey).(*common.PublicKey))
+	}
+	return witKeys
+}
+
+func (ua *UpstreamAuthority) AppendWITKey(witKey *common.PublicKey) {
+	ua.witKeysMtx.Lock()
+	defer ua.witKeysMtx.Unlock()
+	ua.witKeys = append(ua.witKeys, witKey)
+	ua.TriggerWITKeysChanged()
+}
+
+func (ua *UpstreamAuthority) TaintWITKey(keyID string) error {
+	ua.witKeysMtx.Lock()
+	defer ua.witKeysMtx.Unlock()
+	for _, witKey := range ua.witKeys {
+		if witKey.Kid == keyID {
+			witKey.TaintedKey = true
+			ua.TriggerWITKeysChanged()
+			return nil
+		}
+	}
+	return errors.New("no WIT key found")
+}
+
+func (ua *UpstreamAuthority) RemoveWITKey(keyID string) error {
+	ua.witKeysMtx.Lock()
+	defer ua.witKeysMtx.Unlock()
+	for i, witKey := range ua.witKeys {
+		if witKey.Kid == keyID {
+			ua.witKeys = append(ua.witKeys[:i], ua.witKeys[i+1:]...)
+			ua.TriggerWITKeysChanged()
+			return nil
+		}
+	}
+	return errors.New("no WIT key found")
+}
+
 func (ua *UpstreamAuthority) TriggerX509RootsChanged() {
 	ua.streamsMtx.Lock()
 	defer ua.streamsMtx.Unlock()
@@ -273,6 +358,17 @@ func (ua *UpstreamAuthority) TriggerJWTKeysChanged() {
 	}
 }
 
+func (ua *UpstreamAuthority) TriggerWITKeysChanged() {
+	ua.streamsMtx.Lock()
+	defer ua.streamsMtx.Unlock()
+	for streamCh := range ua.witAuthorityStreams {
+		select {
+		case streamCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
 func (ua *UpstreamAuthority) newMintX509CAStream() chan struct{} {
 	streamCh := make(chan struct{}, 1)
 	ua.streamsMtx.Lock()